@@ -1,14 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/greenstevester/fast-cc-git-hooks/internal/banner"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/dirs"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/scopes"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/webhook"
 	"github.com/greenstevester/fast-cc-git-hooks/pkg/ccgen"
 	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/pair"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/scopepin"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/ticket"
 )
 
 var (
@@ -18,14 +29,23 @@ var (
 
 	// Command line flags for ccdo.
 	noVerify = flag.Bool("no-verify", false, "Skip pre-commit hooks")
+	noSign   = flag.Bool("no-sign", false, "Commit without signing even if commit.gpgsign is set")
 	verbose  = flag.Bool("verbose", false, "Show detailed analysis")
 	verboseV = flag.Bool("v", false, "Show detailed analysis (shorthand)")
 	help     = flag.Bool("help", false, "Show help")
+	repo     = flag.String("repo", "", "Run in this repository instead of the current directory, as git -C does")
 )
 
 func main() {
 	flag.Parse()
 
+	// Generate for --repo as if started there
+	if *repo != "" {
+		if err := os.Chdir(*repo); err != nil {
+			log.Fatalf("Error: --repo: %v", err)
+		}
+	}
+
 	// Check if verbose mode is enabled (either flag)
 	isVerbose := *verbose || *verboseV
 
@@ -47,23 +67,68 @@ func main() {
 		log.Fatalf("Failed to get current directory: %v", err)
 	}
 
+	cfg, err := config.Load("")
+	if err != nil {
+		cfg = config.Default()
+	}
+
+	// Track tickets of the configured provider (JIRA unless configured otherwise)
+	jiraManager := jira.NewManager(cwd)
+	if provider, err := ticket.New(cfg.TicketProvider, ticket.Settings{GitHubRepo: cfg.GitHubRepo}); err == nil {
+		jiraManager = ticket.NewManager(cwd, provider)
+	}
+
+	// Scopes derived from the project structure replace guessed ones
+	scopeMapping, err := scopes.Apply(cfg, cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Skipping derived scopes: %v\n", err)
+	}
+
+	// Analysis caches live in the user cache directory (the git directory if
+	// it cannot be determined)
+	cacheDir, _ := dirs.Cache()
+
 	// Create generator with execute option enabled
 	generator := ccgen.New(ccgen.Options{
-		NoVerify:    *noVerify,
-		Execute:     true, // ccdo always executes
-		Copy:        false,
-		Verbose:     isVerbose,
-		JiraManager: jira.NewManager(cwd),
+		NoVerify:                *noVerify,
+		NoSign:                  *noSign,
+		Execute:                 true, // ccdo always executes
+		Copy:                    false,
+		Verbose:                 isVerbose,
+		Progress:                ccgen.NewProgress(os.Stderr, isVerbose),
+		CacheDir:                cacheDir,
+		JiraManager:             jiraManager,
+		CoAuthors:               pair.NewManager(cwd),
+		PinnedScope:             scopepin.NewManager(cwd),
+		Scopes:                  scopeMapping,
+		TicketPlacement:         cfg.TicketPlacement,
+		ExcludeTicketFromLength: cfg.ExcludeTicketFromLength,
+		SignOff:                 cfg.RequireSignoff,
+		HistoryWindow:           cfg.History.Window,
+		HistorySuggestions:      cfg.History.Suggestions,
+		NoHistory:               cfg.History.Disabled,
+		BreakingAPI:             cfg.DetectsBreakingAPI(),
+		CoverageHints:           cfg.CoverageHints,
+		Template:                &ccgen.MessageTemplate{Header: cfg.MessageTemplate.Header, Footer: cfg.MessageTemplate.Footer, Variables: cfg.MessageTemplate.Variables},
+		OnBypass:                webhook.OnBypass(cfg.Webhook, os.Stderr),
 	})
 
+	// Ctrl-C stops git cleanly instead of leaving it running
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Generate commit message and execute
-	result, err := generator.Generate()
+	result, err := generator.Generate(ctx)
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "Aborted")
+		os.Exit(130)
+	}
 	if err != nil {
 		log.Fatalf("Failed to generate commit: %v", err)
 	}
 
 	// Print result (includes execution)
-	generator.PrintResult(result)
+	generator.PrintResult(ctx, result)
 
 	// Exit with appropriate code
 	if !result.HasChanges {
@@ -81,7 +146,9 @@ USAGE:
 
 OPTIONS:
     --no-verify     Skip pre-commit hooks when committing
+    --no-sign       Commit unsigned even if commit.gpgsign is set
     --verbose, -v   Show detailed analysis of changes and version info
+    --repo PATH     Commit in the repository at PATH instead of the current directory
     --help          Show this help message
 
 DESCRIPTION:
@@ -1,16 +1,20 @@
 package main
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/greenstevester/fast-cc-git-hooks/pkg/ccgen"
 )
 
 func TestCCCUsesSharedPackage(t *testing.T) {
-	// Test that we can create a generator (main functionality of ccc)
+	// Test that we can create a generator (main functionality of ccdo)
 	generator := ccgen.New(ccgen.Options{
 		NoVerify: false,
-		Execute:  true, // ccc always executes
+		Execute:  true, // ccdo always executes
 		Copy:     false,
 		Verbose:  false,
 	})
@@ -32,3 +36,25 @@ func TestShowHelp(t *testing.T) {
 	// but we can ensure it doesn't panic
 	// showHelp() // This would print to stdout
 }
+
+// TestMain runs ccdo instead of the tests when CCDO_TEST_MAIN is set, so
+// tests can run the command by re-executing the test binary.
+func TestMain(m *testing.M) {
+	if os.Getenv("CCDO_TEST_MAIN") != "" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestMissingRepo(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	// #nosec G204 - re-executes the test binary
+	cmd := exec.Command(os.Args[0], "--repo", missing)
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(os.Environ(), "CCDO_TEST_MAIN=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "Error: --repo:") {
+		t.Errorf("ccdo --repo %s: %v, want a --repo error\n%s", missing, err, out)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+)
+
+// jiraPickLimit bounds the number of issues offered by `ccg jira-pick`
+const jiraPickLimit = 20
+
+// issueSearcher finds JIRA issues; *jira.Client implements it
+type issueSearcher interface {
+	SearchIssues(ctx context.Context, jql string, limit int) ([]*jira.Issue, error)
+}
+
+// ticketSetter stores the current ticket; *jira.Manager implements it
+type ticketSetter interface {
+	SetJiraTicket(ticketID string) error
+}
+
+// handleJiraPick implements `ccg jira-pick`
+func handleJiraPick(manager ticketSetter, in io.Reader, out io.Writer) error {
+	cfg, err := config.Load("")
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	client, err := newJIRAClient(cfg)
+	if err != nil {
+		return err
+	}
+	return pickJiraTicket(context.Background(), client, manager, in, out)
+}
+
+// pickJiraTicket lists the user's in-progress issues and sets the chosen
+// one as the current ticket
+func pickJiraTicket(ctx context.Context, searcher issueSearcher, manager ticketSetter, in io.Reader, out io.Writer) error {
+	issues, err := searcher.SearchIssues(ctx, jira.InProgressJQL, jiraPickLimit)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Fprintln(out, "No in-progress JIRA issues are assigned to you.")
+		return nil
+	}
+
+	fmt.Fprintln(out, "**Your in-progress JIRA issues:**")
+	for i, issue := range issues {
+		fmt.Fprintf(out, "  %2d. %s  %s\n", i+1, issue.Key, issue.Summary)
+	}
+	fmt.Fprintf(out, "\nPick an issue [1-%d] (Enter to cancel): ", len(issues))
+
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	fmt.Fprintln(out)
+	answer := strings.TrimSpace(line)
+	if answer == "" {
+		fmt.Fprintln(out, "No ticket selected.")
+		return nil
+	}
+
+	choice, err := strconv.Atoi(answer)
+	if err != nil || choice < 1 || choice > len(issues) {
+		return fmt.Errorf("invalid choice %q: enter a number between 1 and %d", answer, len(issues))
+	}
+
+	issue := issues[choice-1]
+	if err := manager.SetJiraTicket(issue.Key); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "✅ **JIRA ticket set:** `%s` %s\n", issue.Key, issue.Summary)
+	return nil
+}
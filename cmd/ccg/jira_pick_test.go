@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+)
+
+type fakeSearcher []*jira.Issue
+
+func (f fakeSearcher) SearchIssues(context.Context, string, int) ([]*jira.Issue, error) {
+	return f, nil
+}
+
+type recordingSetter struct{ ticket string }
+
+func (r *recordingSetter) SetJiraTicket(ticketID string) error {
+	r.ticket = ticketID
+	return nil
+}
+
+func TestPickJiraTicket(t *testing.T) {
+	issues := fakeSearcher{
+		{Key: "CGC-7", Summary: "Fix logout"},
+		{Key: "CGC-9", Summary: "Add audit log"},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "pick second", input: "2\n", want: "CGC-9"},
+		{name: "cancel", input: "\n"},
+		{name: "out of range", input: "3\n", wantErr: true},
+		{name: "not a number", input: "CGC-7\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			setter := &recordingSetter{}
+			err := pickJiraTicket(context.Background(), issues, setter, strings.NewReader(tt.input), &out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pickJiraTicket() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if setter.ticket != tt.want {
+				t.Errorf("ticket = %q, want %q", setter.ticket, tt.want)
+			}
+			if !strings.Contains(out.String(), " 2. CGC-9  Add audit log") {
+				t.Errorf("expected the issue list:\n%s", out.String())
+			}
+		})
+	}
+
+	var out bytes.Buffer
+	if err := pickJiraTicket(context.Background(), fakeSearcher{}, &recordingSetter{}, strings.NewReader(""), &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "No in-progress JIRA issues") {
+		t.Errorf("expected an empty-list message, got %q", out.String())
+	}
+}
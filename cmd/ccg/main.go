@@ -1,14 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/greenstevester/fast-cc-git-hooks/internal/banner"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/dirs"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/scopes"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/webhook"
 	"github.com/greenstevester/fast-cc-git-hooks/pkg/ccgen"
 	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/pair"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/scopepin"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/ticket"
 )
 
 var (
@@ -18,16 +31,37 @@ var (
 
 	// Command line flags.
 	noVerify = flag.Bool("no-verify", false, "Skip pre-commit hooks")
+	noSign   = flag.Bool("no-sign", false, "Commit without signing even if commit.gpgsign is set")
 	execute  = flag.Bool("execute", false, "Execute the commit after generating message")
 	noCopy   = flag.Bool("no-copy", false, "Disable copying git commit command to clipboard")
 	verbose  = flag.Bool("verbose", false, "Show detailed analysis")
 	verboseV = flag.Bool("v", false, "Show detailed analysis (shorthand)")
 	help     = flag.Bool("help", false, "Show help")
+	repo     = flag.String("repo", "", "Run in this repository instead of the current directory, as git -C does")
+	noCache  = flag.Bool("no-cache", false, "Re-run git analysis even if the staged tree is unchanged")
+	maxFiles = flag.Int("max-files", ccgen.DefaultMaxFiles, "Staged file count above which analysis uses statistics only")
+	explain  = flag.Bool("explain", false, "Show each semantic plugin result and why the chosen change won")
+
+	// Overrides for when the user already knows what they are committing.
+	commitType  = flag.String("type", "", "Use this commit type instead of inferring one")
+	commitScope = flag.String("scope", "", "Use this scope instead of the pinned or inferred one")
+
+	// JIRA smart commit commands appended for the current ticket.
+	smartTime       = flag.String("time", "", "Log work on the JIRA ticket (smart commit #time, e.g. 2h)")
+	smartComment    = flag.String("comment", "", "Comment on the JIRA ticket (smart commit #comment)")
+	smartTransition = flag.String("transition", "", "Transition the JIRA ticket (smart commit #transition)")
 )
 
 func main() {
 	flag.Parse()
 
+	// Generate for --repo as if started there
+	if *repo != "" {
+		if err := os.Chdir(*repo); err != nil {
+			log.Fatalf("Error: --repo: %v", err)
+		}
+	}
+
 	// Check if verbose mode is enabled (either flag)
 	isVerbose := *verbose || *verboseV
 
@@ -58,23 +92,143 @@ func main() {
 		log.Fatalf("Failed to get current directory: %v", err)
 	}
 
+	cfg, err := config.Load("")
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	if err := checkOverrides(cfg, *commitType, *commitScope); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	// Semantic plugins only run when their results are shown or gate the message
+	var analyzer *semantic.SemanticAnalyzer
+	if *explain || cfg.MinConfidence > 0 {
+		analyzer, err = newSemanticAnalyzer(newPluginRegistry(), cfg)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+
+	// Scopes derived from the project structure replace guessed ones
+	scopeMapping, err := scopes.Apply(cfg, cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Skipping derived scopes: %v\n", err)
+	}
+
+	// Ticket summaries are fetched only when the JIRA API is configured
+	var tickets ccgen.TicketLookup
+	if client, err := newJIRAClient(cfg); err == nil {
+		tickets = client
+	}
+
+	// Analysis caches live in the user cache directory (the git directory if
+	// it cannot be determined)
+	cacheDir, _ := dirs.Cache()
+
 	// Create generator with specified options
 	generator := ccgen.New(ccgen.Options{
-		NoVerify:    *noVerify,
-		Execute:     *execute,
-		Copy:        !*noCopy, // Copy by default unless --no-copy is specified
-		Verbose:     isVerbose,
-		JiraManager: jira.NewManager(cwd),
+		NoVerify:                *noVerify,
+		NoSign:                  *noSign,
+		Execute:                 *execute,
+		Copy:                    !*noCopy, // Copy by default unless --no-copy is specified
+		Verbose:                 isVerbose,
+		Progress:                ccgen.NewProgress(os.Stderr, isVerbose),
+		MaxFiles:                *maxFiles,
+		NoCache:                 *noCache,
+		CacheDir:                cacheDir,
+		JiraManager:             newTicketManager(cwd, cfg),
+		CoAuthors:               pair.NewManager(cwd),
+		Type:                    *commitType,
+		Scope:                   *commitScope,
+		PinnedScope:             scopepin.NewManager(cwd),
+		Scopes:                  scopeMapping,
+		TicketLookup:            tickets,
+		SmartCommit:             smartCommit(cfg),
+		TicketPlacement:         cfg.TicketPlacement,
+		ExcludeTicketFromLength: cfg.ExcludeTicketFromLength,
+		SignOff:                 cfg.RequireSignoff,
+		Semantic:                analyzer,
+		Explain:                 *explain,
+		MinConfidence:           cfg.MinConfidence,
+		HotspotWindow:           cfg.Hotspots.Window,
+		HotspotThreshold:        cfg.Hotspots.Threshold,
+		HistoryWindow:           cfg.History.Window,
+		HistorySuggestions:      cfg.History.Suggestions,
+		NoHistory:               cfg.History.Disabled,
+		BreakingAPI:             cfg.DetectsBreakingAPI(),
+		CoverageHints:           cfg.CoverageHints,
+		Template:                &ccgen.MessageTemplate{Header: cfg.MessageTemplate.Header, Footer: cfg.MessageTemplate.Footer, Variables: cfg.MessageTemplate.Variables},
+		OnBypass:                webhook.OnBypass(cfg.Webhook, os.Stderr),
 	})
 
+	// Ctrl-C stops git cleanly instead of leaving it running
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Generate commit message
-	result, err := generator.Generate()
+	result, err := generator.Generate(ctx)
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "Aborted")
+		os.Exit(130)
+	}
 	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
 
 	// Print result
-	generator.PrintResult(result)
+	generator.PrintResult(ctx, result)
+}
+
+// newJIRAClient creates the JIRA API client; FCGH_JIRA_URL cannot redirect
+// it when the admin config locks jira_url
+func newJIRAClient(cfg *config.Config) (*jira.Client, error) {
+	if cfg.IsLocked("jira_url") {
+		return jira.NewClientForServer(cfg.JIRAURL)
+	}
+	return jira.NewClientFromEnv(cfg.JIRAURL)
+}
+
+// newTicketManager creates the current-ticket manager for the configured
+// ticket provider. Config validation rejects unknown providers, so a
+// failure here falls back to JIRA tickets.
+func newTicketManager(cwd string, cfg *config.Config) *jira.Manager {
+	provider, err := ticket.New(cfg.TicketProvider, ticket.Settings{GitHubRepo: cfg.GitHubRepo})
+	if err != nil {
+		return jira.NewManager(cwd)
+	}
+	return ticket.NewManager(cwd, provider)
+}
+
+// checkOverrides rejects a --type or --scope the config does not allow
+func checkOverrides(cfg *config.Config, commitType, scope string) error {
+	if commitType != "" && !cfg.HasType(commitType) {
+		return fmt.Errorf("invalid --type %q (allowed: %s)", commitType, strings.Join(cfg.Types, ", "))
+	}
+	if scope != "" {
+		if err := scopepin.Validate(scope); err != nil {
+			return err
+		}
+		if !cfg.HasScope(scope) {
+			return fmt.Errorf("invalid --scope %q (allowed: %s)", scope, strings.Join(cfg.Scopes, ", "))
+		}
+	}
+	return nil
+}
+
+// smartCommit combines the smart commit flags with the configured defaults,
+// which only apply when smart commits are enabled
+func smartCommit(cfg *config.Config) jira.SmartCommit {
+	sc := jira.SmartCommit{Time: *smartTime, Comment: *smartComment, Transition: *smartTransition}
+	if cfg.SmartCommits.Enabled {
+		if sc.Comment == "" {
+			sc.Comment = cfg.SmartCommits.Comment
+		}
+		if sc.Transition == "" {
+			sc.Transition = cfg.SmartCommits.Transition
+		}
+	}
+	return sc
 }
 
 func handleSubcommand(args []string) error {
@@ -83,18 +237,31 @@ func handleSubcommand(args []string) error {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	jiraManager := jira.NewManager(cwd)
+	cfg, err := config.Load("")
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	jiraManager := newTicketManager(cwd, cfg)
 
 	switch args[0] {
 	case "set-jira":
-		if len(args) != 2 {
-			return fmt.Errorf("usage: ccg set-jira <JIRA-TICKET>\nExample: ccg set-jira CGC-1234")
+		if len(args) < 2 {
+			return fmt.Errorf("usage: ccg set-jira <TICKET> [RELATED-TICKET...]\nExample: ccg set-jira CGC-1234 CGC-1240 (or #123, ENG-123, AB#123 with ticket_provider)")
+		}
+		if limit := cfg.MaxTicketsPerCommit; limit > 0 && len(args)-1 > limit {
+			return fmt.Errorf("%d tickets exceed max_tickets_per_commit (%d)", len(args)-1, limit)
+		}
+		if err := jiraManager.SetJiraTickets(args[1], args[2:]...); err != nil {
+			return err
 		}
-		ticketID := args[1]
-		if err := jiraManager.SetJiraTicket(ticketID); err != nil {
+		tickets, err := jiraManager.GetCurrentJiraTickets()
+		if err != nil {
 			return err
 		}
-		fmt.Printf("✅ **JIRA ticket set:** `%s`\n", ticketID)
+		fmt.Printf("✅ **Ticket set:** `%s`\n", tickets[0])
+		if len(tickets) > 1 {
+			fmt.Printf("**Related tickets:** `%s` (listed in a Refs: footer)\n", strings.Join(tickets[1:], "`, `"))
+		}
 		fmt.Println("\nThis ticket will now be automatically included in commit messages.")
 		return nil
 
@@ -112,8 +279,82 @@ func handleSubcommand(args []string) error {
 	case "jira-history":
 		return jiraManager.ListJiraHistory()
 
+	case "jira-pick":
+		return handleJiraPick(jiraManager, os.Stdin, os.Stdout)
+
+	case "set-scope":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: ccg set-scope <SCOPE>\nExample: ccg set-scope api")
+		}
+		if !cfg.HasScope(args[1]) {
+			return fmt.Errorf("invalid scope %q (allowed: %s)", args[1], strings.Join(cfg.Scopes, ", "))
+		}
+		manager := scopepin.NewManager(cwd)
+		if err := manager.SetScope(args[1]); err != nil {
+			return err
+		}
+		scope, err := manager.GetScope()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ **Scope pinned:** `%s`\n", scope)
+		fmt.Println("\nGenerated commits in this repository will now use this scope.")
+		return nil
+
+	case "clear-scope":
+		if err := scopepin.NewManager(cwd).Clear(); err != nil {
+			return err
+		}
+		fmt.Println("✅ **Pinned scope cleared**")
+		fmt.Println("\nGenerated commits will infer their scope from the staged files.")
+		return nil
+
+	case "scope-status":
+		return scopepin.NewManager(cwd).ShowStatus()
+
+	case "pair":
+		return handlePair(pair.NewManager(cwd), args[1:])
+
+	case "plugins":
+		return handlePlugins(args[1:])
+
 	default:
-		return fmt.Errorf("unknown subcommand: %s\n\nAvailable subcommands:\n  set-jira <TICKET>   Set current JIRA ticket\n  clear-jira          Clear current JIRA ticket\n  jira-status         Show current JIRA ticket status\n  jira-history        Show JIRA ticket history", args[0])
+		return fmt.Errorf("unknown subcommand: %s\n\nAvailable subcommands:\n  set-jira <TICKET>   Set current JIRA ticket\n  clear-jira          Clear current JIRA ticket\n  jira-status         Show current JIRA ticket status\n  jira-history        Show JIRA ticket history\n  jira-pick           Pick one of your in-progress JIRA issues\n  set-scope <SCOPE>   Pin the scope of generated commits\n  clear-scope         Unpin the scope\n  scope-status        Show the pinned scope\n  pair add <AUTHOR>   Add a co-author to generated commits\n  pair clear          Stop adding co-authors\n  plugins list        List semantic analysis plugins", args[0])
+	}
+}
+
+// handlePair manages the co-authors credited in generated commits
+func handlePair(manager *pair.Manager, args []string) error {
+	if len(args) == 0 || args[0] == "status" {
+		return manager.ShowStatus()
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: ccg pair add <AUTHOR>...\nExample: ccg pair add \"Jane Doe <jane@example.com>\" (or a name or email from the git history)")
+		}
+		if err := manager.Add(args[1:]...); err != nil {
+			return err
+		}
+		coAuthors, err := manager.GetCoAuthors()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ **Pairing with:** `%s`\n", strings.Join(coAuthors, "`, `"))
+		fmt.Println("\nGenerated commits will now include Co-authored-by trailers.")
+		return nil
+
+	case "clear":
+		if err := manager.Clear(); err != nil {
+			return err
+		}
+		fmt.Println("✅ **Co-authors cleared**")
+		fmt.Println("\nNo Co-authored-by trailers will be added to commit messages.")
+		return nil
+
+	default:
+		return fmt.Errorf("unknown pair command: %s (use add, clear or status)", args[0])
 	}
 }
 
@@ -128,16 +369,40 @@ func showHelp() {
 	fmt.Println()
 	fmt.Println("Flags:")
 	fmt.Println("  --execute      Execute the commit after generating message")
+	fmt.Println("  --explain      Show each semantic plugin result, its confidence and why it won")
+	fmt.Println("  --no-cache     Re-run git analysis even if the staged tree is unchanged")
 	fmt.Println("  --no-copy      Disable copying git commit command to clipboard")
 	fmt.Println("  --no-verify    Skip pre-commit hooks when committing")
+	fmt.Println("  --no-sign      Commit unsigned even if commit.gpgsign is set")
+	fmt.Println("  --type T       Use commit type T instead of inferring one")
+	fmt.Println("  --scope S      Use scope S instead of the pinned or inferred one")
+	fmt.Println("  --max-files N  Summarize by scope from statistics above N staged files (default 1000)")
+	fmt.Println("  --time D       Log work on the JIRA ticket via smart commit (e.g. 2h, 1d 4h)")
+	fmt.Println("  --comment T    Comment on the JIRA ticket via smart commit")
+	fmt.Println("  --transition S Transition the JIRA ticket via smart commit (e.g. \"In Review\")")
 	fmt.Println("  --verbose, -v  Show detailed analysis of changes and version info")
+	fmt.Println("  --repo PATH    Generate for the repository at PATH instead of the current directory")
 	fmt.Println("  --help         Show this help message")
 	fmt.Println()
 	fmt.Println("JIRA Commands:")
-	fmt.Println("  set-jira <TICKET>     Set current JIRA ticket (e.g., CGC-1234)")
+	fmt.Println("  set-jira <TICKET>...  Set current JIRA ticket and optional related tickets (e.g., CGC-1234 CGC-1240)")
 	fmt.Println("  clear-jira            Clear current JIRA ticket")
 	fmt.Println("  jira-status           Show current JIRA ticket status")
 	fmt.Println("  jira-history          Show JIRA ticket history")
+	fmt.Println("  jira-pick             Pick one of your in-progress JIRA issues (needs API access)")
+	fmt.Println()
+	fmt.Println("Scope Commands:")
+	fmt.Println("  set-scope <SCOPE>     Pin the scope of generated commits in this repository (e.g., api)")
+	fmt.Println("  clear-scope           Unpin the scope")
+	fmt.Println("  scope-status          Show the pinned scope")
+	fmt.Println()
+	fmt.Println("Pair Commands:")
+	fmt.Println("  pair add <AUTHOR>...  Add co-authors (\"Name <email>\", or a name or email from the git history)")
+	fmt.Println("  pair clear            Stop adding Co-authored-by trailers")
+	fmt.Println("  pair                  Show current co-authors")
+	fmt.Println()
+	fmt.Println("Plugin Commands:")
+	fmt.Println("  plugins list          List semantic analysis plugins and their status")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  ccg                    # Generate and copy git commit command")
@@ -145,6 +410,7 @@ func showHelp() {
 	fmt.Println("  ccg set-jira CGC-1234  # Set JIRA ticket for future commits")
 	fmt.Println("  ccg jira-status        # Check current JIRA ticket")
 	fmt.Println("  ccg clear-jira         # Remove JIRA ticket from commits")
+	fmt.Println("  ccg --type fix         # Generate a fix commit whatever the changes look like")
 	fmt.Println()
 	fmt.Printf("Build info: %s (%s)\n", buildTime, commit)
 }
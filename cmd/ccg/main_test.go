@@ -1,6 +1,9 @@
 package main
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -184,3 +187,25 @@ func TestGenerateCommitMessageFormat(t *testing.T) {
 		})
 	}
 }
+
+// TestMain runs ccg instead of the tests when CCG_TEST_MAIN is set, so
+// tests can run the command by re-executing the test binary.
+func TestMain(m *testing.M) {
+	if os.Getenv("CCG_TEST_MAIN") != "" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestMissingRepo(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	// #nosec G204 - re-executes the test binary
+	cmd := exec.Command(os.Args[0], "--repo", missing)
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(os.Environ(), "CCG_TEST_MAIN=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "Error: --repo:") {
+		t.Errorf("ccg --repo %s: %v, want a --repo error\n%s", missing, err, out)
+	}
+}
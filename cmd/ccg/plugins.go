@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic/external"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic/plugins"
+)
+
+// pluginDirName is the directory under the config directory holding external
+// plugins
+const pluginDirName = "plugins"
+
+// handlePlugins implements `ccg plugins [list]`
+func handlePlugins(args []string) error {
+	if len(args) > 1 || len(args) == 1 && args[0] != "list" {
+		return fmt.Errorf("usage: ccg plugins list")
+	}
+
+	cfg, err := config.Load("")
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	registry := newPluginRegistry()
+	if _, err := newSemanticAnalyzer(registry, cfg); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tSTATUS\tPRIORITY\tSOURCE\tFILES")
+	for _, plugin := range registry.ListPlugins() {
+		status := "enabled"
+		if !registry.IsEnabled(plugin.Name()) {
+			status = "disabled"
+		}
+		source := "builtin"
+		if ext, ok := plugin.(*external.Plugin); ok {
+			source = ext.Path()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
+			plugin.Name(), plugin.Version(), status, registry.Priority(plugin.Name()), source, describeClaims(plugin))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("Configure plugins under `plugins.<name>` in fast-cc-config.yaml (enabled, priority and plugin options).")
+	return nil
+}
+
+// newPluginRegistry registers the builtin plugins plus external plugins from
+// the plugins directory under the config directory. Broken external plugins
+// are reported and skipped.
+func newPluginRegistry() *semantic.PluginRegistry {
+	registry := plugins.NewBuiltinRegistry()
+
+	configDir, err := config.GetDefaultConfigDir()
+	if err != nil {
+		return registry
+	}
+	for _, err := range external.Register(registry, filepath.Join(configDir, pluginDirName)) {
+		fmt.Fprintf(os.Stderr, "⚠️  Skipping external plugin: %v\n", err)
+	}
+	return registry
+}
+
+// newSemanticAnalyzer creates an analyzer over registry configured from the plugins section of cfg
+func newSemanticAnalyzer(registry *semantic.PluginRegistry, cfg *config.Config) (*semantic.SemanticAnalyzer, error) {
+	analyzer := semantic.NewSemanticAnalyzer(registry)
+	if err := analyzer.Configure(cfg.Plugins); err != nil {
+		return nil, fmt.Errorf("configuring plugins: %w", err)
+	}
+	return analyzer, nil
+}
+
+// describeClaims summarizes the files a plugin claims
+func describeClaims(plugin semantic.SemanticPlugin) string {
+	claims := append(append([]string{}, plugin.SupportedExtensions()...), plugin.SupportedFilePatterns()...)
+	if len(claims) == 0 {
+		return "(detected by content)"
+	}
+	return strings.Join(claims, ", ")
+}
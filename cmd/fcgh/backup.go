@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/backup"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+// defaultExportFile is where fcgh export writes without a file argument.
+const defaultExportFile = "fcgh-export.tar.gz"
+
+var (
+	exportForce bool
+	importForce bool
+)
+
+func exportCommand() *Command {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.BoolVar(&exportForce, "force", false, "overwrite an existing archive")
+
+	return &Command{
+		Name:        "export",
+		Description: "📦 Bundle the config directory (config, templates, plugins, policy, JIRA state) into an archive",
+		Usage:       "[flags] [file]",
+		Examples: []string{
+			"fcgh export  # writes " + defaultExportFile,
+			"fcgh export team-starter-kit.tar.gz",
+		},
+		Flags: fs,
+		Run: func(_ context.Context, args []string) error {
+			file := defaultExportFile
+			if len(args) > 0 {
+				file = args[0]
+			}
+			configDir, err := config.GetDefaultConfigDir()
+			if err != nil {
+				return err
+			}
+
+			flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+			if exportForce {
+				flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+			}
+			out, err := os.OpenFile(file, flags, 0o600) // #nosec G304 - the archive path is chosen by the user
+			if os.IsExist(err) {
+				return fmt.Errorf("%s already exists (use --force to overwrite it)", file)
+			}
+			if err != nil {
+				return fmt.Errorf("creating archive: %w", err)
+			}
+			names, err := backup.Export(out, configDir)
+			if closeErr := out.Close(); err == nil && closeErr != nil {
+				err = fmt.Errorf("writing archive: %w", closeErr)
+			}
+			if err != nil {
+				_ = os.Remove(file)
+				return err
+			}
+
+			fmt.Printf("📦 Exported %d file(s) from %s to %s:\n", len(names), configDir, file)
+			for _, name := range names {
+				fmt.Printf("   • %s\n", name)
+			}
+			fmt.Println("🔑 Stored API tokens are not exported; run 'fcgh auth login jira|github' on the new machine")
+			return nil
+		},
+	}
+}
+
+func importCommand() *Command {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.BoolVar(&importForce, "force", false, "overwrite files that differ from the archive")
+
+	return &Command{
+		Name:        "import",
+		Description: "📥 Restore an archive made by fcgh export into the config directory",
+		Usage:       "[flags] <file>",
+		Examples: []string{
+			"fcgh import " + defaultExportFile,
+			"fcgh import --force team-starter-kit.tar.gz  # replace local changes",
+		},
+		Flags: fs,
+		Run: func(_ context.Context, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("usage: fcgh import [--force] <file>")
+			}
+			configDir, err := config.GetDefaultConfigDir()
+			if err != nil {
+				return err
+			}
+			in, err := os.Open(args[0]) // #nosec G304 - the archive path is chosen by the user
+			if err != nil {
+				return fmt.Errorf("opening archive: %w", err)
+			}
+			defer in.Close()
+
+			result, err := backup.Import(in, configDir, importForce)
+			if err != nil {
+				return fmt.Errorf("importing %s: %w", args[0], err)
+			}
+
+			fmt.Printf("📥 Imported %s into %s\n", args[0], configDir)
+			for _, name := range result.Written {
+				fmt.Printf("   ✅ %s\n", name)
+			}
+			for _, name := range result.Unchanged {
+				fmt.Printf("   ✔️  %s (unchanged)\n", name)
+			}
+			for _, name := range result.Kept {
+				fmt.Printf("   ⚠️  %s differs and was kept\n", name)
+			}
+			if len(result.Kept) > 0 {
+				fmt.Println("💡 Re-run with --force to replace the kept files with the archived ones")
+			}
+			if slices.Contains(result.Written, config.DefaultConfigFile) {
+				if _, err := config.Load(filepath.Join(configDir, config.DefaultConfigFile)); err != nil {
+					fmt.Printf("⚠️  The imported config does not load: %v\n", err)
+				}
+			}
+			return nil
+		},
+	}
+}
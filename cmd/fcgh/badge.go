@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/audit"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+var (
+	badgeFormat  string
+	badgeOutput  string
+	badgeLimit   int
+	badgeRange   string
+	badgeMinimum int
+)
+
+func badgeCommand() *Command {
+	fs := flag.NewFlagSet("badge", flag.ExitOnError)
+	fs.StringVar(&badgeFormat, "format", "svg", "badge format: svg, or json for a shields.io endpoint")
+	fs.StringVar(&badgeOutput, "o", "", "write the badge to this file instead of stdout")
+	fs.IntVar(&badgeLimit, "n", audit.DefaultLimit, "number of recent commits to audit")
+	fs.StringVar(&badgeRange, "range", "", "revision range to audit, e.g. origin/main..HEAD (default: HEAD)")
+	fs.IntVar(&badgeMinimum, "min", 0, "fail when fewer than this percentage of commits comply")
+
+	return &Command{
+		Name:        "badge",
+		Description: "📛 Generate a conventional commit compliance badge",
+		Examples: []string{
+			"fcgh badge -o badge.svg",
+			"fcgh badge --format json -n 500 --min 90",
+		},
+		ConfigKeys: []string{"types", "scopes", "ignore_patterns"},
+		ExitCodes:  []int{exitViolation, exitConfig, exitIntegration},
+		Flags:      fs,
+		Run: func(ctx context.Context, _ []string) error {
+			if badgeFormat != "svg" && badgeFormat != "json" {
+				return fmt.Errorf("unknown badge format %q (expected svg or json)", badgeFormat)
+			}
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+			deriveScopes(cfg, "")
+			// Tickets of old commits are routinely closed, so they are not
+			// looked up
+			v, err := validator.New(cfg)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("creating validator: %w", err))
+			}
+
+			report, err := audit.Run(ctx, v, audit.Options{Range: badgeRange, Limit: badgeLimit})
+			if err != nil {
+				return withExitCode(exitIntegration, fmt.Errorf("auditing commits: %w", err))
+			}
+			badge := report.Badge()
+			data := badge.SVG()
+			if badgeFormat == "json" {
+				if data, err = badge.JSON(); err != nil {
+					return err
+				}
+			}
+
+			if badgeOutput == "" {
+				if _, err := os.Stdout.Write(data); err != nil {
+					return fmt.Errorf("writing badge: %w", err)
+				}
+			} else {
+				if err := os.WriteFile(badgeOutput, data, 0o644); err != nil { // #nosec G306 - badges are published
+					return fmt.Errorf("writing badge: %w", err)
+				}
+				fmt.Fprintf(os.Stderr, "📛 %s: %d of %d commits are conventional (%s)\n",
+					badgeOutput, report.Compliant(), report.Total(), badge.Message)
+			}
+
+			if report.Percent() < badgeMinimum {
+				return withExitCode(exitViolation, errors.New("compliance is below --min"))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+// commitIDRegex matches a full SHA-1 or SHA-256 commit id.
+var commitIDRegex = regexp.MustCompile(`^(?:[0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// batchCommit is one message read by validate --stdin-batch.
+type batchCommit struct {
+	ID      string
+	Message string
+}
+
+// batchLine is the JSON line written for each message of a batch.
+type batchLine struct {
+	Commit   string                       `json:"commit,omitempty"`
+	Index    int                          `json:"index"`
+	Valid    bool                         `json:"valid"`
+	Errors   []*validator.ValidationError `json:"errors"`
+	Warnings []string                     `json:"warnings"`
+}
+
+// splitBatch splits batch input into messages. With nul set, records are
+// NUL-delimited, as `git log -z` writes them; otherwise each line is a
+// message. A full commit id record followed by another record, as from
+// --format=%H%x00%B, names the commit of that message.
+func splitBatch(data []byte, nul bool) []batchCommit {
+	var records []string
+	if nul {
+		for _, record := range bytes.Split(data, []byte{0}) {
+			records = append(records, string(record))
+		}
+		// A trailing NUL terminates the last record
+		if len(records) > 0 && records[len(records)-1] == "" {
+			records = records[:len(records)-1]
+		}
+	} else {
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			if line = bytes.TrimSuffix(line, []byte("\r")); len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			for _, record := range bytes.Split(line, []byte{0}) {
+				records = append(records, string(record))
+			}
+		}
+	}
+
+	var commits []batchCommit
+	for i := 0; i < len(records); i++ {
+		if commitIDRegex.MatchString(records[i]) && i+1 < len(records) {
+			commits = append(commits, batchCommit{ID: records[i], Message: records[i+1]})
+			i++
+			continue
+		}
+		commits = append(commits, batchCommit{Message: records[i]})
+	}
+	return commits
+}
+
+// validateBatch validates every message read from r and writes one JSON line
+// per message to w. It fails with exitViolation when any message is invalid.
+func validateBatch(ctx context.Context, v *validator.Validator, r io.Reader, w io.Writer, nul bool) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("reading from stdin: %w", err)
+	}
+	commits := splitBatch(data, nul)
+	messages := make([]string, len(commits))
+	for i, commit := range commits {
+		messages[i] = commit.Message
+	}
+
+	encoder := json.NewEncoder(w)
+	invalid := 0
+	for _, batched := range v.ValidateBatch(ctx, messages) {
+		result, err := checkBatchSignature(ctx, v, commits[batched.Index], batched.ValidationResult)
+		if err != nil {
+			return err
+		}
+		line := batchLine{
+			Commit:   commits[batched.Index].ID,
+			Index:    batched.Index,
+			Valid:    result.Valid,
+			Errors:   result.ValidationErrors(),
+			Warnings: result.Warnings,
+		}
+		if line.Warnings == nil {
+			line.Warnings = []string{}
+		}
+		if err := encoder.Encode(line); err != nil {
+			return fmt.Errorf("writing result: %w", err)
+		}
+		if !result.Valid {
+			invalid++
+		}
+	}
+
+	if invalid > 0 {
+		return withExitCode(exitViolation, fmt.Errorf("%d of %d commit messages are invalid", invalid, len(commits)))
+	}
+	return nil
+}
+
+// checkBatchSignature adds the signed_commits verdict on the signature of a
+// batch commit given with its id. Identical messages share their result, so
+// the verdict goes into a copy.
+func checkBatchSignature(ctx context.Context, v *validator.Validator, commit batchCommit, result *validator.ValidationResult) (*validator.ValidationResult, error) {
+	if commit.ID == "" || !v.RequiresSignature(commit.Message) {
+		return result, nil
+	}
+	out, err := gitOutput(ctx, "log", "-1", "--format=%G?%x00%GF%x00%GP%x00%GK%x00%GS", commit.ID, "--")
+	if err != nil {
+		return nil, withExitCode(exitIntegration, fmt.Errorf("reading the signature of %s: %w", commit.ID, err))
+	}
+	fields := strings.SplitN(out, "\x00", 5)
+	for len(fields) < 5 {
+		fields = append(fields, "")
+	}
+	checked := &validator.ValidationResult{Errors: slices.Clone(result.Errors), Warnings: result.Warnings, Valid: result.Valid}
+	v.CheckSignature(commit.Message, validator.Signature{
+		Status:             fields[0],
+		Fingerprint:        fields[1],
+		PrimaryFingerprint: fields[2],
+		KeyID:              fields[3],
+		Signer:             fields[4],
+	}, checked)
+	return checked, nil
+}
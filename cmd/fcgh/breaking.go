@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/audit"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+var (
+	breakingSince  string
+	breakingFormat string
+	breakingOutput string
+)
+
+func breakingCommand() *Command {
+	fs := flag.NewFlagSet("breaking", flag.ExitOnError)
+	fs.StringVar(&breakingSince, "since", "", "list breaking changes after this ref, e.g. v1.2.0 (default: the latest tag)")
+	fs.StringVar(&breakingFormat, "format", "markdown", "report format: markdown or json")
+	fs.StringVar(&breakingOutput, "o", "", "write the report to this file instead of stdout")
+
+	return &Command{
+		Name:        "breaking",
+		Description: "💥 List breaking changes since a ref, for release notes and migration guides",
+		Examples: []string{
+			"fcgh breaking  # since the latest tag, as Markdown",
+			"fcgh breaking --since v1.2.0 --format json -o breaking.json",
+		},
+		ConfigKeys: []string{"allow_breaking_changes", "require_breaking_description"},
+		ExitCodes:  []int{exitConfig, exitIntegration},
+		Flags:      fs,
+		Run: func(ctx context.Context, _ []string) error {
+			if breakingFormat != "markdown" && breakingFormat != "json" {
+				return fmt.Errorf("unknown report format %q (expected markdown or json)", breakingFormat)
+			}
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+
+			since := breakingSince
+			if since == "" {
+				if since, err = gitOutput(ctx, "describe", "--tags", "--abbrev=0"); err != nil {
+					return withExitCode(exitIntegration, errors.New("no tag to start from; pass --since <ref>"))
+				}
+			}
+
+			parser := conventionalcommit.DefaultParser()
+			parser.ScopeDelimiters = cfg.ScopeDelimiters
+			report, err := audit.Breaking(ctx, parser, audit.Options{Range: since + "..HEAD"})
+			if err != nil {
+				return withExitCode(exitIntegration, fmt.Errorf("reading commits since %s: %w", since, err))
+			}
+
+			var data bytes.Buffer
+			if breakingFormat == "json" {
+				encoder := json.NewEncoder(&data)
+				encoder.SetEscapeHTML(false)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(report); err != nil {
+					return fmt.Errorf("encoding report: %w", err)
+				}
+			} else {
+				data.WriteString(report.Markdown("Breaking changes since " + since))
+			}
+
+			if breakingOutput == "" {
+				if _, err := os.Stdout.Write(data.Bytes()); err != nil {
+					return fmt.Errorf("writing report: %w", err)
+				}
+				return nil
+			}
+			if err := os.WriteFile(breakingOutput, data.Bytes(), 0o644); err != nil { // #nosec G306 - reports are published
+				return fmt.Errorf("writing report: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "💥 %s: %d breaking change(s) since %s\n", breakingOutput, len(report.Changes), since)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+// configTest runs the test cases of the config's tests key against its rules
+func configTest(ctx context.Context) error {
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+	}
+	if len(cfg.Tests) == 0 {
+		return withExitCode(exitConfig, errors.New("the config has no tests; add sample messages under the tests key"))
+	}
+	// Ticket lookups are left out so tests run offline and repeatably
+	deriveScopes(cfg, "")
+	v, err := validator.New(cfg)
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("creating validator: %w", err))
+	}
+
+	failed := 0
+	for i, result := range v.RunRuleTests(ctx, cfg.Tests) {
+		name := result.Test.Name
+		if name == "" {
+			header, _, _ := strings.Cut(result.Test.Message, "\n")
+			name = fmt.Sprintf("#%d %s", i+1, header)
+		}
+		if result.Passed() {
+			fmt.Printf("✅ %s\n", name)
+			continue
+		}
+		failed++
+		fmt.Printf("❌ %s\n   %s\n", name, strings.ReplaceAll(result.Problem, "\n", "\n   "))
+	}
+	fmt.Printf("\n%d passed, %d failed\n", len(cfg.Tests)-failed, failed)
+	if failed > 0 {
+		return withExitCode(exitViolation, fmt.Errorf("%d of %d config test(s) failed", failed, len(cfg.Tests)))
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/fileutil"
+)
+
+// defaultEnterpriseScopes are offered when the questionnaire asks for scopes
+var defaultEnterpriseScopes = []string{"api", "web", "cli", "db", "auth", "core"}
+
+// enterpriseAnswers tailor the enterprise config template, from the setup-ent
+// questionnaire or an --answers-file with the same keys
+type enterpriseAnswers struct {
+	JIRAProjects     []string `yaml:"jira_projects"`
+	Scopes           []string `yaml:"scopes"`
+	ScopeRequired    bool     `yaml:"scope_required"`
+	MaxSubjectLength int      `yaml:"max_subject_length"`
+	RequireSignoff   bool     `yaml:"require_signoff"`
+}
+
+// loadAnswers reads an answers file for unattended enterprise setup
+func loadAnswers(path string) (*enterpriseAnswers, error) {
+	data, err := fileutil.SafeReadFile(path, fileutil.MaxCommitFileSize)
+	if err != nil {
+		return nil, fmt.Errorf("reading answers file: %w", err)
+	}
+
+	var answers enterpriseAnswers
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&answers); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing answers file %s: %w", path, err)
+	}
+	return &answers, nil
+}
+
+// askEnterpriseQuestions runs the setup-ent questionnaire. Empty answers
+// keep the suggested defaults.
+func askEnterpriseQuestions(in io.Reader, out io.Writer) (*enterpriseAnswers, error) {
+	reader := bufio.NewReader(in)
+	ask := func(question, suggestion string) string {
+		if suggestion != "" {
+			fmt.Fprintf(out, "%s [%s]: ", question, suggestion)
+		} else {
+			fmt.Fprintf(out, "%s: ", question)
+		}
+		line, _ := reader.ReadString('\n')
+		if answer := strings.TrimSpace(line); answer != "" {
+			return answer
+		}
+		return suggestion
+	}
+
+	fmt.Fprintln(out, "📋 A few questions to tailor the enterprise rules (press Enter for the default):")
+	answers := &enterpriseAnswers{
+		JIRAProjects: splitList(strings.ToUpper(ask("   JIRA project keys, comma-separated (empty allows any)", ""))),
+		Scopes:       splitList(ask("   Allowed scopes, comma-separated", strings.Join(defaultEnterpriseScopes, ", "))),
+	}
+	answers.ScopeRequired = isYes(ask("   Require a scope on every commit? (y/n)", "n"))
+
+	maxLength := ask("   Maximum subject length", strconv.Itoa(config.DefaultMaxSubjectLength))
+	length, err := strconv.Atoi(maxLength)
+	if err != nil || length <= 0 {
+		return nil, fmt.Errorf("maximum subject length must be a positive number, got %q", maxLength)
+	}
+	answers.MaxSubjectLength = length
+
+	answers.RequireSignoff = isYes(ask("   Require a Signed-off-by line (git commit -s)? (y/n)", "n"))
+	fmt.Fprintln(out)
+	return answers, nil
+}
+
+// apply sets the answered keys in the enterprise template, keeping its
+// comments
+func (a *enterpriseAnswers) apply(doc *config.Document) error {
+	if len(a.JIRAProjects) > 0 {
+		if err := doc.Set("jira_projects", a.JIRAProjects); err != nil {
+			return err
+		}
+	}
+	if len(a.Scopes) > 0 {
+		if err := doc.Set("scopes", a.Scopes); err != nil {
+			return err
+		}
+	}
+	if a.MaxSubjectLength > 0 {
+		if err := doc.Set("max_subject_length", a.MaxSubjectLength); err != nil {
+			return err
+		}
+	}
+	if err := doc.Set("scope_required", a.ScopeRequired); err != nil {
+		return err
+	}
+	return doc.Set("require_signoff", a.RequireSignoff)
+}
+
+// writeTailoredEnterpriseConfig writes the enterprise template with the
+// answers applied, refusing answers that make the config invalid
+func writeTailoredEnterpriseConfig(destPath string, answers *enterpriseAnswers) error {
+	if err := copyEnterpriseConfig(destPath); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(destPath) // #nosec G304 - destPath is the config path just written
+	if err != nil {
+		return fmt.Errorf("reading enterprise config: %w", err)
+	}
+
+	doc, err := config.ParseDocument(data)
+	if err != nil {
+		return err
+	}
+	if err := answers.apply(doc); err != nil {
+		return err
+	}
+	if _, err := doc.Config(); err != nil {
+		_ = os.Remove(destPath)
+		return fmt.Errorf("invalid answers: %w", err)
+	}
+	if err := os.WriteFile(destPath, doc.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("writing enterprise config: %w", err)
+	}
+	return nil
+}
+
+// enterpriseAnswersSource returns how setup-ent gets its answers: from the
+// answers file, the questionnaire on a terminal, or none (the template as is)
+func enterpriseAnswersSource(answersFile string) func() (*enterpriseAnswers, error) {
+	switch {
+	case answersFile != "":
+		return func() (*enterpriseAnswers, error) { return loadAnswers(answersFile) }
+	case isTerminal(os.Stdin):
+		return func() (*enterpriseAnswers, error) { return askEnterpriseQuestions(os.Stdin, os.Stdout) }
+	default:
+		return nil
+	}
+}
+
+// splitList splits a comma-separated answer, dropping empty entries
+func splitList(answer string) []string {
+	var items []string
+	for _, item := range strings.Split(answer, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// isYes reports whether an answer is affirmative
+func isYes(answer string) bool {
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
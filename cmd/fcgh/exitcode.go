@@ -0,0 +1,49 @@
+package main
+
+import "errors"
+
+// Exit codes of validate and the hooks, so CI scripts and wrappers can branch
+// on the kind of failure. Other failures exit with exitFailure.
+const (
+	exitFailure     = 1
+	exitViolation   = 2 // the commit message breaks a rule
+	exitConfig      = 3 // the config cannot be loaded or is invalid
+	exitParse       = 4 // the commit message is empty or not a conventional commit
+	exitIntegration = 5 // policy verification, git or an issue tracker failed
+)
+
+// exitCodeMeanings describes the exit codes in command help.
+var exitCodeMeanings = map[int]string{
+	exitFailure:     "any other failure",
+	exitViolation:   "a commit message breaks a rule, or a check failed",
+	exitConfig:      "the config cannot be loaded or is invalid",
+	exitParse:       "the commit message is empty or not a conventional commit",
+	exitIntegration: "policy verification, git or an issue tracker failed",
+}
+
+// exitError attaches an exit code to a command error.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode makes the command exit with code when it fails with err.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the exit code for a failed command.
+func exitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitFailure
+}
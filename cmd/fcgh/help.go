@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// printCommandHelp writes the help of cmd: its usage, flags, examples, the
+// config keys it reads and its exit codes.
+func printCommandHelp(w io.Writer, cmd *Command) {
+	fmt.Fprintf(w, "%s\n\n", cmd.Description)
+
+	usage := cmd.Usage
+	if usage == "" && hasFlags(cmd.Flags) {
+		usage = "[flags]"
+	}
+	fmt.Fprintf(w, "Usage:\n  %s\n", strings.TrimSpace("fcgh "+cmd.Name+" "+usage))
+
+	if hasFlags(cmd.Flags) {
+		fmt.Fprintf(w, "\nFlags:\n")
+		output := cmd.Flags.Output()
+		cmd.Flags.SetOutput(w)
+		cmd.Flags.PrintDefaults()
+		cmd.Flags.SetOutput(output)
+	}
+
+	if len(cmd.Examples) > 0 {
+		fmt.Fprintf(w, "\nExamples:\n")
+		for _, example := range cmd.Examples {
+			fmt.Fprintf(w, "  %s\n", example)
+		}
+	}
+
+	if len(cmd.ConfigKeys) > 0 {
+		fmt.Fprintf(w, "\nConfig keys:\n  %s\n", strings.Join(cmd.ConfigKeys, ", "))
+		fmt.Fprintf(w, "  (show a value with: fcgh config get <key>)\n")
+	}
+
+	codes := append([]int{0, exitFailure}, cmd.ExitCodes...)
+	sort.Ints(codes)
+	fmt.Fprintf(w, "\nExit codes:\n")
+	for _, code := range codes {
+		meaning := exitCodeMeanings[code]
+		if code == 0 {
+			meaning = "success"
+		}
+		fmt.Fprintf(w, "  %d  %s\n", code, meaning)
+	}
+}
+
+// hasFlags reports whether fs defines any flag.
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/dirs"
+)
+
+// installBinary copies the running fcgh into dirs.Bin() and returns the
+// copy's path, for hooks to keep working when the go install or download
+// location they were set up from is upgraded or removed. Running the
+// installed copy itself leaves it in place.
+func installBinary() (string, error) {
+	source, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("finding executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(source); err == nil {
+		source = resolved
+	}
+
+	dir, err := dirs.Bin()
+	if err != nil {
+		return "", err
+	}
+	name := "fcgh"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	dest := filepath.Join(dir, name)
+	if same, err := sameFile(source, dest); err == nil && same {
+		return dest, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("creating %s: %w", dir, err)
+	}
+	// Copy next to the destination and rename over it, so a hook running
+	// the old copy meanwhile never sees a partial binary
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("creating %s: %w", dest, err)
+	}
+	defer os.Remove(tmp.Name())
+	in, err := os.Open(source) // #nosec G304 - the running executable
+	if err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("reading %s: %w", source, err)
+	}
+	defer in.Close()
+	if _, err := io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("copying %s: %w", source, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("writing %s: %w", dest, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil { // #nosec G302 - the binary must be executable
+		return "", fmt.Errorf("making %s executable: %w", dest, err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return "", fmt.Errorf("installing %s: %w", dest, err)
+	}
+	return dest, nil
+}
+
+// sameFile reports whether two paths name the same existing file.
+func sameFile(a, b string) (bool, error) {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(infoA, infoB), nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallBinary(t *testing.T) {
+	data := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", data)
+
+	path, err := installBinary()
+	if err != nil {
+		t.Fatalf("installBinary() error = %v", err)
+	}
+	if dir := filepath.Join(data, "fast-cc", "bin"); filepath.Dir(path) != dir {
+		t.Errorf("installBinary() = %q, want a file in %s", path, dir)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.Stat(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("installed binary: %v", err)
+	}
+	if got.Size() != want.Size() || got.Mode().Perm()&0o100 == 0 {
+		t.Errorf("installed binary is %d bytes with mode %v, want an executable copy of %d bytes", got.Size(), got.Mode(), want.Size())
+	}
+
+	// Installing again replaces the copy
+	if again, err := installBinary(); err != nil || again != path {
+		t.Errorf("installBinary() again = %q, %v", again, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("bin directory has %d entries, want only the binary", len(entries))
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/audit"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/prcomment"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+var (
+	labelsPR    bool
+	labelsRange string
+)
+
+func labelsCommand() *Command {
+	fs := flag.NewFlagSet("labels", flag.ExitOnError)
+	fs.BoolVar(&labelsPR, "pr", false, "apply the labels to the pull request of this CI job and remove stale ones (GitHub Actions or GitLab CI)")
+	fs.StringVar(&labelsRange, "range", "", "revision range whose commit types are labeled (default: the pull request's commits in CI)")
+
+	return &Command{
+		Name:        "labels",
+		Description: "🏷️  Label the pull request after its conventional commit types (feat → enhancement, fix → bug)",
+		Examples: []string{
+			"fcgh labels --range origin/main..HEAD",
+			"fcgh labels --pr  # sync the labels on the CI pull request",
+		},
+		ConfigKeys: []string{"type_labels"},
+		ExitCodes:  []int{exitConfig, exitIntegration},
+		Flags:      fs,
+		Run: func(ctx context.Context, _ []string) error {
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+
+			target, err := prcomment.Detect(nil)
+			if err != nil && (labelsPR || !errors.Is(err, prcomment.ErrNotInPullRequest)) {
+				return withExitCode(exitIntegration, err)
+			}
+			revisions := labelsRange
+			if revisions == "" && target != nil {
+				revisions = target.Range
+			}
+			if revisions == "" {
+				return errors.New("outside a pull request job, pass --range <from>..<to>")
+			}
+
+			v, err := validator.New(cfg)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("creating validator: %w", err))
+			}
+			types, breaking, err := audit.Types(ctx, v.Parser(), audit.Options{Range: revisions})
+			if err != nil {
+				return withExitCode(exitIntegration, fmt.Errorf("reading commits of %s: %w", revisions, err))
+			}
+			// The pull request title becomes the squash commit subject
+			if target != nil {
+				if title, err := v.Parser().Parse(target.Title); err == nil {
+					if !slices.Contains(types, title.Type) {
+						types = append(types, title.Type)
+					}
+					breaking = breaking || title.Breaking
+				}
+			}
+
+			mapping := cfg.TypeLabels
+			if len(mapping) == 0 {
+				mapping = config.DefaultTypeLabels()
+			}
+			labels, stale := audit.Labels(mapping, types, breaking)
+
+			if !labelsPR {
+				for _, label := range labels {
+					fmt.Println(label)
+				}
+				return nil
+			}
+			if err := prcomment.New(*target).SyncLabels(ctx, labels, stale); err != nil {
+				return withExitCode(exitIntegration, fmt.Errorf("labeling %s #%d: %w", target.Project, target.Number, err))
+			}
+			applied := strings.Join(labels, ", ")
+			if applied == "" {
+				applied = "no type labels apply"
+			}
+			fmt.Printf("🏷️  Labeled %s #%d: %s\n", target.Project, target.Number, applied)
+			return nil
+		},
+	}
+}
@@ -2,23 +2,44 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/greenstevester/fast-cc-git-hooks/internal/banner"
 	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/dirs"
 	"github.com/greenstevester/fast-cc-git-hooks/internal/fileutil"
 	"github.com/greenstevester/fast-cc-git-hooks/internal/hooks"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/lsp"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/metrics"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/policy"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/rpc"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/scopes"
 	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/webhook"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/ccgen"
 	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/pair"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/scopepin"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/secrets"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/ticket"
 )
 
 var (
@@ -33,63 +54,73 @@ type Command struct {
 	Flags       *flag.FlagSet
 	Name        string
 	Description string
+	// LongRunning commands, such as servers, run without the usual timeout.
+	LongRunning bool
+
+	// Help shown by "fcgh <command> -h" and "fcgh help <command>".
+	Usage      string   // what follows the command name, e.g. "[flags] [message]"
+	Examples   []string // command lines, each optionally followed by "  # comment"
+	ConfigKeys []string // config keys the command reads
+	ExitCodes  []int    // exit codes besides 0 and exitFailure
 }
 
 var (
 	// Global flags.
 	verbose    bool
 	configFile string
+	repoDir    string
 
 	// Command-specific flags..
-	validateFile string
-	forceInstall bool
-	localInstall bool
+	validateFile      string
+	validateFix       bool
+	validateColor     string
+	validateBatchMode bool
+	validateNUL       bool
+	validateBranch    string
+	metricsAddr       string
+	forceInstall      bool
+	localInstall      bool
+	prepareMsgHook    bool
+	prePushHook       bool
+	installBinaryFlag bool
+	prepareMsgFile    string
+	answersFile       string
+	initForce         bool
+	initMerge         bool
+	initPreset        string
 
 	logger *slog.Logger
 )
 
-// checkVerboseFlag scans args for verbose flags without full parsing
-func checkVerboseFlag(args []string) bool {
-	for _, arg := range args {
-		if arg == "-v" || arg == "--verbose" {
-			return true
-		}
-		// Stop at first non-flag argument (command name)
-		if !strings.HasPrefix(arg, "-") {
-			break
+// stdioCommands speak a protocol on stdout, so nothing else may be printed there.
+var stdioCommands = map[string]bool{"lsp": true, "serve": true}
+
+// writesData reports whether a command line prints machine-readable output
+// on stdout: a stdio command, a report (badge, breaking, release-notes,
+// labels, stats), version or validate --stdin-batch.
+func writesData(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if stdioCommands[args[0]] || args[0] == "badge" || args[0] == "breaking" || args[0] == "release-notes" || args[0] == "labels" || args[0] == "squash" || args[0] == "stats" || args[0] == "routing" || args[0] == "version" {
+		return true
+	}
+	if args[0] == "validate" {
+		for _, arg := range args[1:] {
+			if name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "="); strings.HasPrefix(arg, "-") && name == "stdin-batch" {
+				return true
+			}
 		}
 	}
 	return false
 }
 
 func main() {
-	// Check for verbose flag early to determine banner display
-	verbose = checkVerboseFlag(os.Args[1:])
-
-	// Print banner based on verbose flag
-	if verbose {
-		banner.PrintWithVersionAndBuildTime(version, commit, buildTime)
-	} else {
-		banner.PrintSimple()
-	}
-
-	// Setup logger with verbose setting
-	setupLogger(verbose)
-
-	// Define commands
-	commands := map[string]*Command{
-		"setup":     setupCommand(),
-		"setup-ent": setupEnterpriseCommand(),
-		"remove":    removeCommand(),
-		"validate":  validateCommand(),
-		"init":      initCommand(),
-		"status":    statusCommand(),
-	}
-
-	// Parse global flags
+	// Parse global flags, which come before the command name
 	flag.BoolVar(&verbose, "v", false, "verbose output")
 	flag.BoolVar(&verbose, "verbose", false, "verbose output")
 	flag.StringVar(&configFile, "config", "", "path to config file")
+	flag.StringVar(&repoDir, "repo", "", "run in this repository instead of the current directory, as git -C does")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "🚀 fcgh - Fast Conventional Git Hooks\n\n")
 
@@ -98,25 +129,82 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "setup-ent", "🏢 Enterprise setup - global by default (--local for current repo, local overrides global)")
 		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "remove", "🗑️  Easy removal - uninstall git hooks (--local for current repo removal)")
 		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "validate", "🔍 Test a git commit message, to see if it follows conventional format")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "precheck", "🚦 Check whether a commit would go through: staged changes, pre-commit hook and message (-m)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "wip", "🚧 Commit work in progress as \"WIP: ...\" (needs \"^WIP: \" in ignore_patterns)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "unwip", "🧹 Squash the WIP commits on HEAD into one validated conventional commit (-m message)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "badge", "📛 Generate an SVG or JSON badge of conventional commit compliance (-n 200, -o badge.svg)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "breaking", "💥 List breaking changes since a ref as Markdown or JSON (--since v1.2.0)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "release-notes", "📝 Summarize user-facing changes; --pr keeps a sticky comment on the CI pull request")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "labels", "🏷️  Map commit types to labels (feat → enhancement); --pr syncs them on the CI pull request")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "squash", "🔀 Validate the squash commit GitHub would create for the CI pull request, \" (#123)\" included")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "stats", "📈 Scope activity by author and directory (stats scopes); commits changelogs would miss (stats history)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "routing", "🧭 Map scopes to reviewer teams from scope_reviewers and CODEOWNERS, as YAML or JSON (-o file)")
 		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "status", "📊 Show git hook installation status and current JIRA ticket")
 		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "init", "📝 Create a config file")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "config", "⚙️  Manage the config file (get, set, unset, migrate, test)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "template", "🧾 Install a commit.template built from your config (install [--local])")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "policy", "📜 Install or verify a signed policy bundle (pull [url]|verify)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "lsp", "🧩 Language server for commit messages in your editor (stdio)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "serve", "🔌 JSON-RPC service for GUI clients: validate, generate, config (stdio)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "export", "📦 Bundle config, templates, plugins, policy and JIRA state into an archive for a new machine or a team")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "import", "📥 Restore an archive from export, keeping files that differ unless --force")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "auth", "🔑 Store JIRA/GitHub API tokens (login|logout|status jira|github)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "self-update", "⬆️  Install the latest release after verifying it (--check to only report, --channel prerelease)")
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", "version", "🏷️  Show the version; --json adds build provenance (modules, VCS revision, build flags, checksum)")
 
 		fmt.Fprintf(os.Stderr, "\n🔧 Options:\n")
 		flag.PrintDefaults()
-		fmt.Fprintf(os.Stderr, "\n💡 Need help? Use '%s <command> -h' for more details\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\n💡 Need help? Use '%s <command> -h' or 'fcgh help <command>' for usage, examples, config keys and exit codes\n", os.Args[0])
+	}
+
+	flag.Parse()
+	args := flag.Args()
+
+	// Commands run as if fcgh was started in --repo, so hooks, config and
+	// relative paths resolve against that repository
+	if repoDir != "" {
+		if err := os.Chdir(repoDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --repo: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Print banner based on verbose flag
+	switch {
+	case writesData(args):
+	case verbose:
+		banner.PrintWithVersionAndBuildTime(version, commit, buildTime)
+	default:
+		banner.PrintSimple()
 	}
 
+	// Setup logger with verbose setting
+	setupLogger(verbose)
+
+	// Define commands
+	commands := newCommands()
+
 	// Need at least command name
-	if len(os.Args) < 2 {
+	if len(args) < 1 {
 		flag.Usage()
 		os.Exit(1)
 	}
 
 	// Extract command
-	cmdName := os.Args[1]
+	cmdName := args[0]
 
 	// Handle help commands
-	if cmdName == "-h" || cmdName == "--help" || cmdName == "help" {
+	if cmdName == "help" && len(args) > 1 {
+		cmd, exists := commands[args[1]]
+		if !exists {
+			fmt.Fprintf(os.Stderr, "Unknown command: %s\n", args[1])
+			flag.Usage()
+			os.Exit(1)
+		}
+		printCommandHelp(os.Stdout, cmd)
+		os.Exit(0)
+	}
+	if cmdName == "help" {
 		flag.Usage()
 		os.Exit(0)
 	}
@@ -129,20 +217,67 @@ func main() {
 	}
 
 	// Parse command flags
-	if err := cmd.Flags.Parse(os.Args[2:]); err != nil {
+	if err := cmd.Flags.Parse(args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Create context with timeout...
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if cmd.LongRunning {
+		ctx, cancel = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	} else {
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	}
 	defer cancel()
 
 	// Run command...
 	if err := cmd.Run(ctx, cmd.Flags.Args()); err != nil {
 		logger.Error("command failed", "command", cmdName, "error", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+	notifyUpdate(ctx, args)
+}
+
+// newCommands returns the fcgh commands by name, each printing its own help
+// for -h.
+func newCommands() map[string]*Command {
+	commands := map[string]*Command{
+		"setup":         setupCommand(),
+		"setup-ent":     setupEnterpriseCommand(),
+		"remove":        removeCommand(),
+		"validate":      validateCommand(),
+		"precheck":      precheckCommand(),
+		"badge":         badgeCommand(),
+		"breaking":      breakingCommand(),
+		"release-notes": releaseNotesCommand(),
+		"labels":        labelsCommand(),
+		"squash":        squashCommand(),
+		"stats":         statsCommand(),
+		"routing":       routingCommand(),
+		"init":          initCommand(),
+		"status":        statusCommand(),
+		"auth":          authCommand(),
+		"policy":        policyCommand(),
+		"export":        exportCommand(),
+		"import":        importCommand(),
+		"config":        configCommand(),
+		"template":      templateCommand(),
+		"lsp":           lspCommand(),
+		"serve":         serveCommand(),
+		"self-update":   selfUpdateCommand(),
+		"version":       versionCommand(),
+		"wip":           wipCommand(),
+		"unwip":         unwipCommand(),
+		// prepare-msg and pre-push are invoked by hooks and are not listed in usage.
+		"prepare-msg": prepareMsgCommand(),
+		"pre-push":    prePushCommand(),
+	}
+	for _, cmd := range commands {
+		cmd.Flags.Usage = func() { printCommandHelp(cmd.Flags.Output(), cmd) }
 	}
+	return commands
 }
 
 func setupLogger(verbose bool) {
@@ -163,35 +298,86 @@ func setupLogger(verbose bool) {
 func validateCommand() *Command {
 	fs := flag.NewFlagSet("validate", flag.ExitOnError)
 	fs.StringVar(&validateFile, "file", "", "validate commit message from file")
+	fs.BoolVar(&validateFix, "fix", false, "fix type and scope case and redundant words before validating (also enabled by auto_fix)")
+	fs.StringVar(&validateColor, "color", "auto", "color the output: auto, always or never")
+	fs.BoolVar(&validateBatchMode, "stdin-batch", false, "validate many messages from stdin and print one JSON line per message")
+	fs.BoolVar(&validateNUL, "z", false, "with --stdin-batch, messages are NUL-delimited (git log -z) instead of one per line")
+	fs.StringVar(&validateBranch, "branch", "", "branch the commits are pushed to, for branch rules such as review_trailers.require_reviewed_by")
 
 	return &Command{
 		Name:        "validate",
 		Description: "🔍 Test a commit message",
-		Flags:       fs,
+		Usage:       "[flags] [message]",
+		Examples: []string{
+			"fcgh validate \"feat(api): add login endpoint\"",
+			"fcgh validate --file .git/COMMIT_EDITMSG --fix",
+			"git log -z --format=%B origin/main..HEAD | fcgh validate --stdin-batch -z",
+		},
+		ConfigKeys: []string{"types", "scopes", "scope_required", "max_subject_length", "type_rules", "custom_rules", "ignore_patterns", "require_jira_ticket", "auto_fix", "review_trailers.require_reviewed_by"},
+		ExitCodes:  []int{exitViolation, exitConfig, exitParse, exitIntegration},
+		Flags:      fs,
 		Run: func(ctx context.Context, args []string) error {
+			colors, err := newPalette(validateColor, os.Stderr)
+			if err != nil {
+				return err
+			}
+			if validateBatchMode && (validateFile != "" || len(args) > 0 || validateFix) {
+				return errors.New("--stdin-batch reads messages from stdin and cannot be combined with --file, --fix or arguments")
+			}
+
 			// Load configuration.
-			cfg, err := config.Load(configFile)
+			cfg, prov, err := config.LoadWithProvenance(configFile)
 			if err != nil {
-				return fmt.Errorf("loading config: %w", err)
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+			for _, ignored := range prov.Ignored {
+				fmt.Fprintf(os.Stderr, "🔒 %s\n", ignored)
+			}
+			if cfg.Policy.Verify {
+				if err := verifyPolicy(cfg); err != nil {
+					return withExitCode(exitIntegration, fmt.Errorf("refusing to validate against an unverified policy: %w", err))
+				}
 			}
 
 			// Create validator.
-			v, err := validator.New(cfg)
+			v, err := newValidator(cfg)
 			if err != nil {
-				return fmt.Errorf("creating validator: %w", err)
+				return err
+			}
+			v.SetBranch(validateBranch)
+
+			if validateBatchMode {
+				return validateBatch(ctx, v, os.Stdin, os.Stdout, validateNUL)
+			}
+			if cfg.DuplicateSubjects > 0 {
+				v.SetRecentCommits(recentCommits(ctx, cfg.DuplicateSubjects))
 			}
 
 			var result *validator.ValidationResult
+			var message string
+			fix := validateFix || cfg.AutoFix
 
 			if validateFile != "" {
+				if fix {
+					fixed, err := v.FixFile(validateFile)
+					if err != nil {
+						return err
+					}
+					if fixed != "" {
+						fmt.Fprintf(os.Stderr, "🔧 Fixed: %s\n", fixed)
+					}
+				}
+
 				// Validate from file.
 				result, err = v.ValidateFile(ctx, validateFile)
 				if err != nil {
 					return fmt.Errorf("validating file: %w", err)
 				}
+				if !result.Valid {
+					message, _ = validator.ReadMessageFile(validateFile)
+				}
 			} else {
 				// Validate from arguments or stdin.
-				var message string
 				if len(args) > 0 {
 					message = strings.Join(args, " ")
 				} else {
@@ -208,18 +394,29 @@ func validateCommand() *Command {
 				}
 
 				if message == "" {
-					return fmt.Errorf("no commit message provided")
+					return withExitCode(exitParse, fmt.Errorf("no commit message provided"))
+				}
+				if fixed, ok := v.Fix(message); fix && ok {
+					header, _, _ := strings.Cut(fixed, "\n")
+					fmt.Fprintf(os.Stderr, "🔧 Fixed: %s\n", header)
+					message = fixed
 				}
 
 				result = v.Validate(ctx, message)
 			}
 
+			renderWarnings(os.Stderr, result.Warnings, colors)
+
 			if !result.Valid {
-				fmt.Fprintf(os.Stderr, "❌ Commit message validation failed:\n")
-				for _, err := range result.Errors {
-					fmt.Fprintf(os.Stderr, "  • %v\n", err)
+				renderFailure(os.Stderr, message, result, cfg, prov, colors)
+				// Only hook runs (which pass --file) report blocked commits
+				if validateFile != "" {
+					notifyBlocked(ctx, cfg, validateFile, result)
 				}
-				return fmt.Errorf("validation failed")
+				if result.Unparsable() {
+					return withExitCode(exitParse, fmt.Errorf("validation failed"))
+				}
+				return withExitCode(exitViolation, fmt.Errorf("validation failed"))
 			}
 
 			fmt.Println("✅ Commit message is valid")
@@ -228,13 +425,375 @@ func validateCommand() *Command {
 	}
 }
 
+// newValidator creates the validator the commit-msg hook uses, verifying
+// tickets with the configured tracker when enabled
+func newValidator(cfg *config.Config) (*validator.Validator, error) {
+	deriveScopes(cfg, "")
+	v, err := validator.New(cfg)
+	if err != nil {
+		return nil, withExitCode(exitConfig, fmt.Errorf("creating validator: %w", err))
+	}
+	if cfg.VerifyTickets {
+		provider, err := ticket.New(cfg.TicketProvider, ticket.Settings{GitHubRepo: cfg.GitHubRepo})
+		if err != nil {
+			return nil, withExitCode(exitConfig, err)
+		}
+		if verifier, ok := provider.(ticket.Verifier); ok {
+			v.SetTicketVerifier(verifier)
+		} else {
+			fmt.Fprintf(os.Stderr, "⚠️  The %s ticket provider cannot verify tickets\n", provider.Name())
+		}
+	}
+	if cfg.VerifyJIRATickets {
+		client, err := newJIRAClient(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping JIRA ticket verification: %v\n", err)
+		} else {
+			v.SetTicketLookup(client)
+		}
+	}
+	return v, nil
+}
+
+// recentCommits returns up to n of the latest non-merge commits, or nil
+// outside a repository. When the commit-msg hook runs for git commit
+// --amend, git passes the amended commit's author date in GIT_AUTHOR_DATE;
+// that commit is skipped so amending never warns about its own subject.
+func recentCommits(ctx context.Context, n int) []validator.RecentCommit {
+	out, err := gitOutput(ctx, "log", "--no-merges", "-z", fmt.Sprintf("-%d", n+1), "--date=raw", "--format=%H%x00%ad%x00%s")
+	if err != nil {
+		return nil
+	}
+	fields := strings.Split(out, "\x00")
+	amended := strings.TrimPrefix(os.Getenv("GIT_AUTHOR_DATE"), "@")
+	var commits []validator.RecentCommit
+	for i := 0; i+2 < len(fields); i += 3 {
+		if i == 0 && amended != "" && fields[1] == amended {
+			continue
+		}
+		commits = append(commits, validator.RecentCommit{Hash: fields[i], Subject: fields[i+2]})
+	}
+	return commits[:min(n, len(commits))]
+}
+
+// deriveScopes adds the scopes derived from the project structure of the
+// repository containing dir to cfg and returns their path mapping. Sources
+// that cannot be read only warn, so commits are not blocked by them.
+func deriveScopes(cfg *config.Config, dir string) *scopes.Mapping {
+	mapping, err := scopes.Apply(cfg, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Skipping derived scopes: %v\n", err)
+	}
+	return mapping
+}
+
+// printRuleSources shows which config layer set the rule behind a
+// validation error, so locked admin rules are clearly attributed
+func printRuleSources(w io.Writer, err error, prov *config.Provenance, p palette) {
+	var validationErr *validator.ValidationError
+	if !errors.As(err, &validationErr) {
+		return
+	}
+	for _, key := range validationErr.ConfigKeys() {
+		if source := prov.Source(key); source != config.SourceDefault {
+			fmt.Fprintf(w, "      %s\n", p.dim(fmt.Sprintf("↳ %s: %s", key, source)))
+		}
+	}
+}
+
+// notifyBlocked posts a blocked commit to the configured webhook. Webhook
+// failures are only reported, so they never change the hook's outcome.
+func notifyBlocked(ctx context.Context, cfg *config.Config, file string, result *validator.ValidationResult) {
+	notifier := webhook.New(cfg.Webhook)
+	if !notifier.Enabled(webhook.EventBlocked) {
+		return
+	}
+
+	// An unreadable message file still reports the violations
+	message, _ := fileutil.SafeReadCommitFile(file)
+	violations := make([]string, 0, len(result.Errors))
+	for _, err := range result.Errors {
+		violations = append(violations, err.Error())
+	}
+	if err := notifier.Notify(ctx, webhook.EventBlocked, message, violations); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Webhook notification failed: %v\n", err)
+	}
+}
+
+func lspCommand() *Command {
+	fs := flag.NewFlagSet("lsp", flag.ExitOnError)
+	fs.StringVar(&metricsAddr, "metrics-addr", "", "serve Prometheus metrics on this address at /metrics, e.g. 127.0.0.1:9464")
+
+	return &Command{
+		Name:        "lsp",
+		Description: "🧩 Run a language server for commit messages over stdio",
+		Examples: []string{
+			"fcgh lsp  # started by the editor",
+			"fcgh lsp --metrics-addr 127.0.0.1:9464",
+		},
+		ConfigKeys:  []string{"types", "scopes"},
+		ExitCodes:   []int{exitConfig},
+		Flags:       fs,
+		LongRunning: true,
+		Run: func(ctx context.Context, _ []string) error {
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+			// Tickets are not verified remotely: diagnostics run on every keystroke
+			deriveScopes(cfg, "")
+			v, err := validator.New(cfg)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("creating validator: %w", err))
+			}
+			m, err := startMetrics(ctx, metricsAddr)
+			if err != nil {
+				return err
+			}
+
+			server := lsp.NewServer(lsp.Options{
+				Config:    cfg,
+				Validator: v,
+				Tickets: func() []string {
+					cwd, err := os.Getwd()
+					if err != nil {
+						return nil
+					}
+					tickets, _ := ticketManagerFor(cwd, cfg).GetCurrentJiraTickets()
+					return tickets
+				},
+				Metrics: m,
+			})
+			return server.Serve(ctx, os.Stdin, os.Stdout)
+		},
+	}
+}
+
+func serveCommand() *Command {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.StringVar(&metricsAddr, "metrics-addr", "", "serve Prometheus metrics on this address at /metrics, e.g. 127.0.0.1:9464")
+
+	return &Command{
+		Name:        "serve",
+		Description: "🔌 Serve validate, generate and config as line-delimited JSON-RPC over stdio",
+		Examples: []string{
+			"echo '{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"validate\",\"params\":{\"message\":\"feat: add login\"}}' | fcgh serve",
+		},
+		ConfigKeys:  []string{"types", "scopes", "message_template"},
+		ExitCodes:   []int{exitConfig},
+		Flags:       fs,
+		LongRunning: true,
+		Run: func(ctx context.Context, _ []string) error {
+			m, err := startMetrics(ctx, metricsAddr)
+			if err != nil {
+				return err
+			}
+			server, err := rpc.NewServer(rpc.Options{
+				Load: func() (*config.Config, error) {
+					cfg, err := config.Load(configFile)
+					if err == nil {
+						deriveScopes(cfg, "")
+					}
+					return cfg, err
+				},
+				Generate: generateStaged,
+				Metrics:  m,
+			})
+			if err != nil {
+				return withExitCode(exitConfig, err)
+			}
+			return server.Serve(ctx, os.Stdin, os.Stdout)
+		},
+	}
+}
+
+// startMetrics serves Prometheus metrics at /metrics on addr until ctx is
+// done. It returns nil metrics, which record nothing, when addr is empty.
+func startMetrics(ctx context.Context, addr string) (*metrics.Metrics, error) {
+	if addr == "" {
+		return nil, nil
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listening for metrics: %w", err)
+	}
+
+	m := metrics.New()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("metrics server failed", "addr", addr, "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	logger.Debug("serving metrics", "addr", listener.Addr().String())
+	return m, nil
+}
+
+// generateStaged generates a message from the changes staged in dir, or the
+// working directory, for the serve command. Stdin carries requests there, so
+// the generator never prompts.
+func generateStaged(ctx context.Context, cfg *config.Config, dir string) (*ccgen.Result, error) {
+	if dir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("getting working directory: %w", err)
+		}
+		dir = cwd
+	}
+	generator := ccgen.New(ccgen.Options{
+		StagedOnly:              true,
+		Output:                  io.Discard,
+		Input:                   strings.NewReader(""),
+		Backend:                 ccgen.NewExecBackend(dir),
+		JiraManager:             ticketManagerFor(dir, cfg),
+		CoAuthors:               pair.NewManager(dir),
+		PinnedScope:             scopepin.NewManager(dir),
+		Scopes:                  deriveScopes(cfg, dir),
+		TicketPlacement:         cfg.TicketPlacement,
+		ExcludeTicketFromLength: cfg.ExcludeTicketFromLength,
+		BreakingAPI:             cfg.DetectsBreakingAPI(),
+		CoverageHints:           cfg.CoverageHints,
+		Template:                &ccgen.MessageTemplate{Header: cfg.MessageTemplate.Header, Footer: cfg.MessageTemplate.Footer, Variables: cfg.MessageTemplate.Variables},
+		// Suggestions are never shown, so history is not read
+		NoHistory: true,
+	})
+	return generator.Generate(ctx)
+}
+
+func prepareMsgCommand() *Command {
+	fs := flag.NewFlagSet("prepare-msg", flag.ExitOnError)
+	fs.StringVar(&prepareMsgFile, "file", "", "commit message file to pre-populate (passed by git)")
+
+	return &Command{
+		Name:        "prepare-msg",
+		Description: "📝 Pre-populate a commit message file with a generated message",
+		Usage:       "--file <path>",
+		Examples: []string{
+			"fcgh prepare-msg --file .git/COMMIT_EDITMSG  # run by the prepare-commit-msg hook",
+		},
+		ConfigKeys: []string{"message_template", "coverage_hints", "ticket_placement"},
+		ExitCodes:  []int{exitIntegration},
+		Flags:      fs,
+		Run: func(ctx context.Context, _ []string) error {
+			if prepareMsgFile == "" {
+				return fmt.Errorf("--file is required")
+			}
+			return prepareCommitMessage(ctx, prepareMsgFile)
+		},
+	}
+}
+
+// newJIRAClient creates the JIRA API client; FCGH_JIRA_URL cannot redirect
+// it when the admin config locks jira_url
+func newJIRAClient(cfg *config.Config) (*jira.Client, error) {
+	if cfg.IsLocked("jira_url") {
+		return jira.NewClientForServer(cfg.JIRAURL)
+	}
+	return jira.NewClientFromEnv(cfg.JIRAURL)
+}
+
+// newTicketManager creates the current-ticket manager for the configured
+// ticket provider, falling back to JIRA tickets when the config is unusable.
+func newTicketManager(cwd string) *jira.Manager {
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return jira.NewManager(cwd)
+	}
+	return ticketManagerFor(cwd, cfg)
+}
+
+// ticketManagerFor creates the current-ticket manager for cfg's ticket provider
+func ticketManagerFor(cwd string, cfg *config.Config) *jira.Manager {
+	provider, err := ticket.New(cfg.TicketProvider, ticket.Settings{GitHubRepo: cfg.GitHubRepo})
+	if err != nil {
+		return jira.NewManager(cwd)
+	}
+	return ticket.NewManager(cwd, provider)
+}
+
+// prepareCommitMessage generates a message from the staged changes and writes it
+// above any existing content (git's comment template) in the message file.
+func prepareCommitMessage(ctx context.Context, path string) error {
+	existing, err := fileutil.SafeReadCommitFile(path)
+	if err != nil {
+		return fmt.Errorf("reading commit message file: %w", err)
+	}
+
+	// Respect anything the user (or another tool) already wrote
+	for _, line := range strings.Split(existing, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			logger.Debug("commit message already present, leaving it untouched", "file", path)
+			return nil
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting working directory: %w", err)
+	}
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		cfg = config.Default()
+	}
+
+	generator := ccgen.New(ccgen.Options{
+		StagedOnly:              true,
+		Output:                  io.Discard,
+		JiraManager:             ticketManagerFor(cwd, cfg),
+		CoAuthors:               pair.NewManager(cwd),
+		PinnedScope:             scopepin.NewManager(cwd),
+		Scopes:                  deriveScopes(cfg, cwd),
+		TicketPlacement:         cfg.TicketPlacement,
+		ExcludeTicketFromLength: cfg.ExcludeTicketFromLength,
+		BreakingAPI:             cfg.DetectsBreakingAPI(),
+		CoverageHints:           cfg.CoverageHints,
+		Template:                &ccgen.MessageTemplate{Header: cfg.MessageTemplate.Header, Footer: cfg.MessageTemplate.Footer, Variables: cfg.MessageTemplate.Variables},
+		// Suggestions are never shown, so history is not read
+		NoHistory: true,
+	})
+
+	result, err := generator.Generate(ctx)
+	if err != nil {
+		return withExitCode(exitIntegration, fmt.Errorf("generating commit message: %w", err))
+	}
+	if !result.HasChanges {
+		return nil
+	}
+
+	content := result.Message + "\n" + existing
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return fmt.Errorf("writing commit message file: %w", err)
+	}
+
+	logger.Debug("pre-populated commit message", "file", path)
+	return nil
+}
+
 func initCommand() *Command {
 	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	fs.BoolVar(&initForce, "force", false, "overwrite an existing config with the defaults")
+	fs.BoolVar(&initMerge, "merge", false, "add missing default keys to an existing config, keeping its settings and comments")
+	fs.StringVar(&initPreset, "preset", "", "start from a built-in preset: "+strings.Join(config.Presets(), ", "))
 
 	return &Command{
 		Name:        "init",
 		Description: "📝 Create a config file",
-		Flags:       fs,
+		Examples: []string{
+			"fcgh init",
+			"fcgh init --preset angular",
+			"fcgh init --merge  # add new default keys to an existing config",
+		},
+		ConfigKeys: []string{"extends"},
+		ExitCodes:  []int{exitConfig},
+		Flags:      fs,
 		Run: func(_ context.Context, _ []string) error {
 			path := configFile
 			if path == "" {
@@ -246,9 +805,25 @@ func initCommand() *Command {
 				}
 			}
 
+			if initForce && initMerge {
+				return errors.New("--force and --merge cannot be used together")
+			}
+			if initPreset != "" && initMerge {
+				return errors.New("--preset and --merge cannot be used together")
+			}
+
 			// Check if file exists.
 			if _, err := os.Stat(path); err == nil {
-				return fmt.Errorf("config file already exists: %s", path)
+				switch {
+				case initMerge:
+					return mergeDefaultConfig(path)
+				case !initForce:
+					return fmt.Errorf("config file already exists: %s (use --merge to add missing keys or --force to overwrite)", path)
+				}
+			}
+
+			if initPreset != "" {
+				return initFromPreset(path, initPreset)
 			}
 
 			// Create default config..
@@ -273,15 +848,89 @@ func initCommand() *Command {
 	}
 }
 
+// initFromPreset writes a config at path that extends the named preset,
+// leaving the preset's keys to it so later releases can refine them.
+func initFromPreset(path, name string) error {
+	cfg, err := config.Preset(name)
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	content := fmt.Sprintf("# Rules from the %s preset; keys set below override it.\n# 'fcgh config get <key>' prints the effective value of a key.\nextends: %s%s\n",
+		name, config.PresetPrefix, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+
+	logger.Info("created configuration file", "path", path, "preset", name)
+	fmt.Printf("✅ Created configuration file: %s\n", path)
+	fmt.Printf("\nThe %s preset includes:\n", name)
+	fmt.Printf("  • Commit types: %s\n", strings.Join(cfg.Types, ", "))
+	fmt.Printf("  • Max subject length: %d\n", cfg.MaxSubjectLength)
+	fmt.Printf("  • Scope required: %v\n", cfg.ScopeRequired)
+	fmt.Printf("  • JIRA ticket required: %v\n", cfg.RequireJIRATicket)
+	fmt.Println("\nAdd keys to the file to override the preset.")
+	return nil
+}
+
+// mergeDefaultConfig adds the default keys missing from the config at path,
+// keeping the user's values and comments.
+func mergeDefaultConfig(path string) error {
+	data, err := os.ReadFile(path) // #nosec G304 - path is the user's config file
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	doc, err := config.ParseDocument(data)
+	if err != nil {
+		return err
+	}
+
+	if merged, err := doc.Config(); err == nil && merged.Extends != "" {
+		fmt.Printf("✅ %s extends %s, which supplies the keys it does not set\n", path, merged.Extends)
+		return nil
+	}
+
+	added, err := doc.AddMissing(config.Default())
+	if err != nil {
+		return err
+	}
+	if len(added) == 0 {
+		fmt.Printf("✅ %s already sets every default key\n", path)
+		return nil
+	}
+
+	if _, err := doc.Config(); err != nil {
+		return fmt.Errorf("merged config is invalid: %w", err)
+	}
+	if err := os.WriteFile(path, doc.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+
+	logger.Info("merged default configuration", "path", path, "added", added)
+	fmt.Printf("✅ Added %d missing key(s) to %s: %s\n", len(added), path, strings.Join(added, ", "))
+	return nil
+}
+
 func setupCommand() *Command {
 	fs := flag.NewFlagSet("setup", flag.ExitOnError)
 	fs.BoolVar(&forceInstall, "force", false, "force installation, overwriting existing hooks")
 	fs.BoolVar(&localInstall, "local", false, "install only for current repository (default: install globally)")
+	fs.BoolVar(&prepareMsgHook, "prepare-msg", false, "also install a prepare-commit-msg hook that pre-fills generated messages")
+	fs.BoolVar(&prePushHook, "pre-push", false, "also install a pre-push hook that re-validates outgoing commits, catching --no-verify")
+	fs.BoolVar(&installBinaryFlag, "install-binary", false, "copy fcgh to a stable location and point the hooks at it, so they survive upgrades")
 
 	return &Command{
 		Name:        "setup",
 		Description: "🚀 Easy setup - install git hooks (global by default, local overrides global)",
-		Flags:       fs,
+		Examples: []string{
+			"fcgh setup  # install the commit-msg hook globally",
+			"fcgh setup --local --prepare-msg  # this repository only, pre-filling generated messages",
+			"fcgh setup --pre-push  # also re-validate pushed commits",
+		},
+		ConfigKeys: []string{"pre_push"},
+		Flags:      fs,
 		Run: func(ctx context.Context, _ []string) error {
 			fmt.Println("🚀 Setting up fcgh (Fast Conventional Git Hooks)...")
 			fmt.Println("   This will help you write better commit messages!")
@@ -300,29 +949,25 @@ func setupCommand() *Command {
 			fmt.Println("")
 
 			// Step 2: Install hooks
-			var err error
-			if localInstall {
-				fmt.Println("📁 Installing hooks for this repository only...")
-				opts := hooks.Options{
-					Logger:       logger,
-					ForceInstall: forceInstall,
-				}
-
-				installer, instErr := hooks.New(opts)
-				if instErr != nil {
-					return fmt.Errorf("creating installer: %w", instErr)
-				}
-
-				err = installer.Install(ctx)
-			} else {
-				fmt.Println("🌍 Installing hooks globally (for all your repositories)...")
-				err = hooks.GlobalInstall(ctx, logger)
+			executable, err := hookExecutable()
+			if err != nil {
+				fmt.Println("❌ Setup failed:", err)
+				return err
 			}
-
+			changed, outdated, err := installHooks(ctx, configPath, executable)
 			if err != nil {
 				fmt.Println("❌ Setup failed:", err)
 				return err
 			}
+			if !configCreated && !changed {
+				fmt.Println("")
+				if outdated {
+					fmt.Println("ℹ️  No changes made; re-run with --force to apply the differences above")
+				} else {
+					fmt.Println("✅ fcgh is already up to date (no changes)")
+				}
+				return nil
+			}
 
 			fmt.Println("")
 			fmt.Println("✅ All done! Your commit messages will now be checked automatically!")
@@ -330,28 +975,98 @@ func setupCommand() *Command {
 				fmt.Printf("⚙️  Configuration stored at: %s\n", configPath)
 				fmt.Println("   Edit this file to customize commit rules.")
 			}
+			if prepareMsgHook {
+				fmt.Println("📝 Plain 'git commit' will now open your editor with a generated message")
+			}
+			if prePushHook {
+				fmt.Println("🛂 Pushes will re-validate their commits, including those made with --no-verify")
+			}
 			fmt.Println("💡 Try making a commit like: git commit -m \"feat: add awesome feature\"")
 			return nil
 		},
 	}
 }
 
+// hookExecutable returns the fcgh the hooks run: the copy installed with
+// --install-binary, or "" for the running binary.
+func hookExecutable() (string, error) {
+	if !installBinaryFlag {
+		return "", nil
+	}
+	path, err := installBinary()
+	if err != nil {
+		return "", fmt.Errorf("installing binary: %w", err)
+	}
+	fmt.Printf("📦 Installed fcgh to %s; hooks will run it from there\n", path)
+	fmt.Println("")
+	return path, nil
+}
+
+// installHooks installs the hooks locally with --local and globally
+// otherwise, reporting whether anything had to be written and whether a
+// differing hook was left in place for lack of --force.
+func installHooks(ctx context.Context, configPath, executable string) (changed, outdated bool, err error) {
+	opts := hooks.Options{
+		Logger:           logger,
+		PrepareCommitMsg: prepareMsgHook,
+		PrePush:          prePushHook,
+		Executable:       executable,
+		Config:           hookConfig(configPath),
+		Output:           os.Stdout,
+	}
+	if !localInstall {
+		fmt.Println("🌍 Installing hooks globally (for all your repositories)...")
+		changed, err = hooks.GlobalInstallWithOptions(ctx, opts)
+		return changed, false, err
+	}
+
+	fmt.Println("📁 Installing hooks for this repository only...")
+	opts.ForceInstall = forceInstall
+	installer, err := hooks.New(opts)
+	if err != nil {
+		return false, false, fmt.Errorf("creating installer: %w", err)
+	}
+	if err := installer.Install(ctx); err != nil {
+		return false, false, err
+	}
+	return installer.Changed(), installer.Outdated(), nil
+}
+
+// hookConfig returns the config whose types and subject length the hook
+// checks when the fcgh binary is missing, or nil for the defaults.
+func hookConfig(path string) *config.Config {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
 func setupEnterpriseCommand() *Command {
 	fs := flag.NewFlagSet("setup-ent", flag.ExitOnError)
 	fs.BoolVar(&forceInstall, "force", false, "force installation, overwriting existing hooks")
 	fs.BoolVar(&localInstall, "local", false, "install only for current repository (default: install globally)")
+	fs.BoolVar(&prepareMsgHook, "prepare-msg", false, "also install a prepare-commit-msg hook that pre-fills generated messages")
+	fs.BoolVar(&prePushHook, "pre-push", false, "also install a pre-push hook that re-validates outgoing commits, catching --no-verify")
+	fs.BoolVar(&installBinaryFlag, "install-binary", false, "copy fcgh to a stable location and point the hooks at it, so they survive upgrades")
+	fs.StringVar(&answersFile, "answers-file", "", "YAML file with questionnaire answers for unattended setup")
 
 	return &Command{
 		Name:        "setup-ent",
 		Description: "🏢 Enterprise setup - with JIRA validation (global by default, local overrides global)",
-		Flags:       fs,
+		Examples: []string{
+			"fcgh setup-ent  # answer the enterprise questionnaire",
+			"fcgh setup-ent --answers-file answers.yaml  # unattended, e.g. in provisioning scripts",
+		},
+		ConfigKeys: []string{"jira_projects", "jira_url", "require_jira_ticket"},
+		Flags:      fs,
 		Run: func(ctx context.Context, _ []string) error {
 			fmt.Println("🏢 Setting up fcgh for Enterprise...")
 			fmt.Println("   This includes JIRA ticket validation and enterprise-ready rules!")
 			fmt.Println("")
 
 			// Step 1: Check/create enterprise configuration
-			configPath, configCreated, configErr := ensureEnterpriseConfigExists()
+			configPath, configCreated, configErr := ensureEnterpriseConfigExists(enterpriseAnswersSource(answersFile))
 			if configErr != nil {
 				fmt.Printf("⚠️  Warning: Could not create enterprise config: %v\n", configErr)
 				fmt.Println("   Hooks will use default settings.")
@@ -366,29 +1081,25 @@ func setupEnterpriseCommand() *Command {
 			fmt.Println("")
 
 			// Step 2: Install hooks
-			var err error
-			if localInstall {
-				fmt.Println("📁 Installing hooks for this repository only...")
-				opts := hooks.Options{
-					Logger:       logger,
-					ForceInstall: forceInstall,
-				}
-
-				installer, instErr := hooks.New(opts)
-				if instErr != nil {
-					return fmt.Errorf("creating installer: %w", instErr)
-				}
-
-				err = installer.Install(ctx)
-			} else {
-				fmt.Println("🌍 Installing hooks globally (for all your repositories)...")
-				err = hooks.GlobalInstall(ctx, logger)
+			executable, err := hookExecutable()
+			if err != nil {
+				fmt.Println("❌ Setup failed:", err)
+				return err
 			}
-
+			changed, outdated, err := installHooks(ctx, configPath, executable)
 			if err != nil {
 				fmt.Println("❌ Setup failed:", err)
 				return err
 			}
+			if !configCreated && !changed {
+				fmt.Println("")
+				if outdated {
+					fmt.Println("ℹ️  No changes made; re-run with --force to apply the differences above")
+				} else {
+					fmt.Println("✅ fcgh is already up to date (no changes)")
+				}
+				return nil
+			}
 
 			fmt.Println("")
 			fmt.Println("✅ Enterprise setup complete! Your commit messages will be validated with:")
@@ -405,9 +1116,10 @@ func setupEnterpriseCommand() *Command {
 	}
 }
 
-// ensureEnterpriseConfigExists checks for existing config or creates enterprise config.
+// ensureEnterpriseConfigExists checks for existing config or creates enterprise config,
+// tailored with the answers from getAnswers when it is not nil.
 // Returns (configPath, wasCreated, error).
-func ensureEnterpriseConfigExists() (string, bool, error) {
+func ensureEnterpriseConfigExists(getAnswers func() (*enterpriseAnswers, error)) (string, bool, error) {
 	// First check if there's already a config file specified
 	if configFile != "" {
 		if _, err := os.Stat(configFile); err == nil {
@@ -422,29 +1134,24 @@ func ensureEnterpriseConfigExists() (string, bool, error) {
 		return "", false, fmt.Errorf("cannot determine config path: %w", err)
 	}
 
-	// Check if any config already exists in home directory
-	if _, err := os.Stat(defaultPath); err == nil {
-		return defaultPath, false, nil
-	}
-
-	// Check for old filename in home directory for backward compatibility
-	oldPath := filepath.Join(filepath.Dir(defaultPath), ".fast-cc-hooks.yaml")
-	if _, err := os.Stat(oldPath); err == nil {
-		return oldPath, false, nil
+	// Reuse any existing config (home directory first, legacy names included)
+	if path, _, found := config.FindConfigFile(); found {
+		return path, false, nil
 	}
 
-	// Check if config exists in current directory (new filename first)
-	if _, err := os.Stat(config.DefaultConfigFile); err == nil {
-		return config.DefaultConfigFile, false, nil
+	// Create enterprise config in home directory
+	if getAnswers == nil {
+		if err := copyEnterpriseConfig(defaultPath); err != nil {
+			return "", false, fmt.Errorf("creating enterprise config: %w", err)
+		}
+		return defaultPath, true, nil
 	}
 
-	// Check for old filename in current directory
-	if _, err := os.Stat(".fast-cc-hooks.yaml"); err == nil {
-		return ".fast-cc-hooks.yaml", false, nil
+	answers, err := getAnswers()
+	if err != nil {
+		return "", false, err
 	}
-
-	// Create enterprise config in home directory
-	if err := copyEnterpriseConfig(defaultPath); err != nil {
+	if err := writeTailoredEnterpriseConfig(defaultPath, answers); err != nil {
 		return "", false, fmt.Errorf("creating enterprise config: %w", err)
 	}
 
@@ -527,15 +1234,91 @@ scope_required: false
 # Maximum length of the subject line
 max_subject_length: 72
 
+# Override max_subject_length and scope_required for some types
+# type_rules:
+#   docs:
+#     max_subject_length: 100
+#   feat:
+#     scope_required: true
+
+# Don't count ticket references (e.g. CGC-1234) against max_subject_length
+exclude_ticket_from_length: true
+
 # Allow breaking changes
 allow_breaking_changes: true
 
+# Breaking changes must describe what broke in a BREAKING CHANGE footer
+require_breaking_description: true
+
+# Warn when a subject repeats one of the last N commit subjects (0 disables)
+# duplicate_subjects: 10
+
+# Require a Signed-off-by trailer (git commit -s)
+require_signoff: false
+
+# Check Reviewed-by, Acked-by and Tested-by trailers; commits to the listed
+# branches need a Reviewed-by trailer (validate --branch, server-side)
+# review_trailers:
+#   validate: true
+#   require_reviewed_by: [main, release/*]
+
+# Commits of these types or scopes must be signed by one of the keys
+# (checked by validate --stdin-batch, e.g. in a server-side hook)
+# signed_commits:
+#   types: [revert]
+#   keys: ["SHA256:..."]
+
+# Reviewers of each scope in the file fcgh routing generates for review
+# bots; scopes not listed go to the CODEOWNERS owners of their paths
+# scope_reviewers:
+#   auth: ["@org/identity"]
+
+# Run organization checks as external commands (JSON on stdin and stdout)
+# checks:
+#   - name: owners
+#     command: [./scripts/check-owners]
+
+# Note missing test changes and updated docs in generated message bodies
+# coverage_hints: true
+
+# Shape generated messages (ccg, ccdo, prepare-msg) with variables such as
+# {header}, {branch}, {ticket}, {monorepo_package} and {author_initials}
+# message_template:
+#   header: "{header}"
+#   footer: ["Package: {monorepo_package}"]
+
+# Re-validate pushed commits in the pre-push hook (setup --pre-push) to
+# catch git commit --no-verify: record them in a log, or block the push
+# pre_push:
+#   action: record   # or block
+
+# Notify at most daily when a newer fcgh is released; fcgh self-update
+# installs it after verifying its checksum (and signature with public_key)
+# updates:
+#   check: true
+#   channel: stable   # or prerelease
+
+# Sample messages and their expected outcome, run by fcgh config test
+# tests:
+#   - message: "feat: add login"
+#     rules: [scope]
+
 # Require JIRA ticket references in commits
 require_jira_ticket: true
 
+# Only accept tickets from these JIRA projects (any project when empty)
+# jira_projects: [CGC, PLAT]
+
 # No general ticket reference requirement
 require_ticket_ref: false
 
+# Signed policy bundle installed with 'fcgh policy pull'; with verify,
+# hooks refuse to run if the bundle or its files were modified
+# policy:
+#   url: https://config.example.com/fast-cc/policy.tar
+#   public_key: <base64 ed25519 public key>
+#   verify: true
+
 # Custom rules (empty by default)
 custom_rules: []
 `
@@ -564,45 +1347,16 @@ func ensureConfigExists() (string, bool, error) {
 		return "", false, fmt.Errorf("specified config file not found: %s", configFile)
 	}
 
-	// Helper function to check current directory configs
-	checkCurrentDir := func() (string, bool) {
-		// Check for new filename in current directory
-		if _, err := os.Stat(config.DefaultConfigFile); err == nil {
-			return config.DefaultConfigFile, true
-		}
-		// Check for old filename in current directory
-		if _, err := os.Stat(".fast-cc-hooks.yaml"); err == nil {
-			return ".fast-cc-hooks.yaml", true
-		}
-		return "", false
+	// Reuse any existing config (home directory first, legacy names included)
+	if path, _, found := config.FindConfigFile(); found {
+		return path, false, nil
 	}
 
-	// Check for config in the default home directory location
 	defaultPath, err := config.GetDefaultConfigPath()
 	if err != nil {
-		// Fallback to current directory when home directory is not accessible
-		if path, found := checkCurrentDir(); found {
-			return path, false, nil
-		}
 		return "", false, fmt.Errorf("cannot determine config path: %w", err)
 	}
 
-	// Check if config already exists in home directory (new filename)
-	if _, err := os.Stat(defaultPath); err == nil {
-		return defaultPath, false, nil
-	}
-
-	// Check for old filename in home directory for backward compatibility
-	oldPath := filepath.Join(filepath.Dir(defaultPath), ".fast-cc-hooks.yaml")
-	if _, err := os.Stat(oldPath); err == nil {
-		return oldPath, false, nil
-	}
-
-	// Check current directory as final fallback before creating new config
-	if path, found := checkCurrentDir(); found {
-		return path, false, nil
-	}
-
 	// Create default config in home directory with new filename
 	cfg := config.Default()
 	if err := cfg.Save(defaultPath); err != nil {
@@ -617,10 +1371,14 @@ func checkInstallations() (bool, bool, error) {
 	// Check local installation
 	localOpts := hooks.Options{Logger: logger}
 	localInstaller, err := hooks.New(localOpts)
-	if err != nil {
+	// Outside a repository only global hooks can be installed
+	hasLocal := false
+	switch {
+	case err == nil:
+		hasLocal = localInstaller.IsInstalled()
+	case !errors.Is(err, hooks.ErrNoGitRepo):
 		return false, false, fmt.Errorf("creating local installer: %w", err)
 	}
-	hasLocal := localInstaller.IsInstalled()
 
 	// Check global installation by trying to detect global hooks directory
 	hasGlobal, err := hasGlobalInstallation()
@@ -692,6 +1450,17 @@ func removeGlobalInstallation() error {
 			return fmt.Errorf("removing global hook: %w", err)
 		}
 	}
+
+	// Only remove the prepare-commit-msg and pre-push hooks if we installed them
+	for _, name := range []string{hooks.PrepareHookName, hooks.PrePushHookName} {
+		hookPath := filepath.Join(configDir, "hooks", name)
+		// #nosec G304 - hookPath is constructed from validated git config directory
+		if content, err := os.ReadFile(hookPath); err == nil && strings.Contains(string(content), hooks.HookIdentifier) {
+			if err := os.Remove(hookPath); err != nil {
+				return fmt.Errorf("removing global %s hook: %w", name, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -732,7 +1501,10 @@ func statusCommand() *Command {
 	return &Command{
 		Name:        "status",
 		Description: "📊 Show git hook installation status and current JIRA ticket",
-		Flags:       fs,
+		Examples: []string{
+			"fcgh status",
+		},
+		Flags: fs,
 		Run: func(_ context.Context, _ []string) error {
 			fmt.Println("📊 fcgh Status")
 			fmt.Println("==============")
@@ -769,7 +1541,7 @@ func statusCommand() *Command {
 				fmt.Println("🎫 JIRA Status:")
 				fmt.Println("   ⚠️  Unable to determine current directory")
 			} else {
-				jiraManager := jira.NewManager(cwd)
+				jiraManager := newTicketManager(cwd)
 				currentTicket, err := jiraManager.GetCurrentJiraTicket()
 				if err != nil {
 					fmt.Println("🎫 JIRA Status:")
@@ -804,23 +1576,483 @@ func statusCommand() *Command {
 				fmt.Println("   ❌ No config file found")
 				fmt.Println("   💡 Run 'fcgh init' to create a config file")
 			}
+			if _, legacy, found := config.FindConfigFile(); configFile == "" && found && legacy {
+				fmt.Println("   💡 Legacy config filename in use; run 'fcgh config migrate'")
+			}
+			if _, err := os.Stat(config.AdminConfigPath); err == nil {
+				fmt.Printf("   🔒 Admin config: %s\n", config.AdminConfigPath)
+				if cfg, err := config.Load(configFile); err == nil && len(cfg.Locked) > 0 {
+					fmt.Printf("   🔒 Locked rules: %s\n", strings.Join(cfg.Locked, ", "))
+				}
+			}
 
 			return nil
 		},
 	}
 }
 
+func configCommand() *Command {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+
+	return &Command{
+		Name:        "config",
+		Description: "⚙️  Manage the config file",
+		Usage:       "get <key> | set <key> <value> | unset <key> | migrate [--dry-run] | test",
+		Examples: []string{
+			"fcgh config get max_subject_length",
+			"fcgh config set scope_required true",
+			"fcgh config test  # check the sample messages under tests",
+		},
+		ConfigKeys: []string{"tests"},
+		ExitCodes:  []int{exitViolation, exitConfig},
+		Flags:      fs,
+		Run: func(ctx context.Context, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("usage: fcgh config migrate [--dry-run] | get <key> | set <key> <value> | unset <key> | test")
+			}
+			switch args[0] {
+			case "get":
+				if len(args) != 2 {
+					return fmt.Errorf("usage: fcgh config get <key>")
+				}
+				return configGet(args[1])
+			case "set":
+				if len(args) != 3 {
+					return fmt.Errorf("usage: fcgh config set <key> <value>")
+				}
+				return editConfig(args[1], func(doc *config.Document) (bool, error) {
+					node, err := config.ParseValue(args[1], args[2])
+					if err != nil {
+						return false, err
+					}
+					return true, doc.Set(args[1], node)
+				})
+			case "unset":
+				if len(args) != 2 {
+					return fmt.Errorf("usage: fcgh config unset <key>")
+				}
+				if _, err := config.KeyType(args[1]); err != nil {
+					return err
+				}
+				return editConfig(args[1], func(doc *config.Document) (bool, error) {
+					return doc.Unset(args[1])
+				})
+			case "migrate":
+				migrateFlags := flag.NewFlagSet("config migrate", flag.ContinueOnError)
+				dryRun := migrateFlags.Bool("dry-run", false, "show what would be migrated without changing anything")
+				if err := migrateFlags.Parse(args[1:]); err != nil {
+					return err
+				}
+				return migrateConfig(*dryRun)
+			case "test":
+				if len(args) != 1 {
+					return fmt.Errorf("usage: fcgh config test")
+				}
+				return configTest(ctx)
+			default:
+				return fmt.Errorf("unknown config action %q (supported: migrate, get, set, unset, test)", args[0])
+			}
+		},
+	}
+}
+
+func templateCommand() *Command {
+	fs := flag.NewFlagSet("template", flag.ExitOnError)
+
+	return &Command{
+		Name:        "template",
+		Description: "🧾 Install a commit message template built from the config",
+		Usage:       "install [--local]",
+		Examples: []string{
+			"fcgh template install",
+			"fcgh template install --local",
+		},
+		ConfigKeys: []string{"types", "scopes", "max_subject_length", "type_rules"},
+		ExitCodes:  []int{exitConfig},
+		Flags:      fs,
+		Run: func(_ context.Context, args []string) error {
+			if len(args) == 0 || args[0] != "install" {
+				return fmt.Errorf("usage: fcgh template install [--local]")
+			}
+			installFlags := flag.NewFlagSet("template install", flag.ContinueOnError)
+			local := installFlags.Bool("local", false, "set commit.template for the current repository only (default: globally)")
+			if err := installFlags.Parse(args[1:]); err != nil {
+				return err
+			}
+
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+			path, err := hooks.InstallTemplate(cfg, *local)
+			if err != nil {
+				return err
+			}
+			where := "all repositories"
+			if *local {
+				where = "this repository"
+			}
+			fmt.Printf("✅ Installed commit template for %s: %s\n", where, path)
+			fmt.Println("   Run 'fcgh template install' again after changing your config.")
+			return nil
+		},
+	}
+}
+
+// configGet prints the effective value of a config key: plain for scalars,
+// YAML for lists and sections
+func configGet(key string) error {
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	value, err := cfg.Lookup(key)
+	if err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case string, bool, int, float64:
+		fmt.Println(v)
+	default:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encoding %s: %w", key, err)
+		}
+		fmt.Print(string(data))
+	}
+	return nil
+}
+
+// editConfig applies edit to the config file, keeping its comments, and
+// writes it back when edit changed something and the result is valid
+func editConfig(key string, edit func(doc *config.Document) (bool, error)) error {
+	path, err := editableConfigPath()
+	if err != nil {
+		return err
+	}
+	if cfg, err := config.Load(configFile); err == nil {
+		if top, _, _ := strings.Cut(key, "."); cfg.IsLocked(top) {
+			return fmt.Errorf("%s is locked by the admin config %s", top, config.AdminConfigPath)
+		}
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is the user's config file
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	doc, err := config.ParseDocument(data)
+	if err != nil {
+		return err
+	}
+	changed, err := edit(doc)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Printf("%s is not set in %s\n", key, path)
+		return nil
+	}
+	if _, err := doc.Config(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	if err := os.WriteFile(path, doc.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	logger.Info("updated configuration", "path", path, "key", key)
+	return nil
+}
+
+// editableConfigPath returns the config file that config set/unset edit:
+// --config, the config file in use, or the default path for a new one
+func editableConfigPath() (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+	if path, legacy, found := config.FindConfigFile(); found {
+		if legacy {
+			return "", fmt.Errorf("%s uses the legacy name; run 'fcgh config migrate' first", path)
+		}
+		return path, nil
+	}
+	return config.GetDefaultConfigPath()
+}
+
+// migrateConfig moves legacy .fast-cc-hooks.yaml files to the current
+// filename and schema and renames hooks left by the old fast-cc-hooks binary
+func migrateConfig(dryRun bool) error {
+	legacyDir := false
+	if from, to, ok := dirs.LegacyMigration(); ok {
+		legacyDir = true
+		if dryRun {
+			fmt.Printf("📁 Would move %s → %s\n", from, to)
+		} else if linked, err := dirs.MigrateLegacy(from, to); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping %s: %v\n", from, err)
+		} else {
+			fmt.Printf("✅ Moved %s → %s\n", from, to)
+			if !linked {
+				fmt.Printf("   ⚠️  Could not create a compatibility symlink at %s\n", from)
+			}
+		}
+	}
+
+	migrations := config.LegacyConfigs()
+	for _, m := range migrations {
+		if dryRun {
+			fmt.Printf("📝 Would migrate %s → %s\n", m.From, m.To)
+			continue
+		}
+		linked, err := m.Run()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping %s: %v\n", m.From, err)
+			continue
+		}
+		fmt.Printf("✅ Migrated %s → %s (original kept as %s.bak)\n", m.From, m.To, m.From)
+		if !linked {
+			fmt.Printf("   ⚠️  Could not create a compatibility symlink at %s\n", m.From)
+		}
+	}
+
+	var legacyHooks []string
+	if installer, err := hooks.New(hooks.Options{Logger: logger}); err == nil {
+		legacyHooks = append(legacyHooks, installer.LegacyHooks()...)
+	}
+	global, err := hooks.GlobalLegacyHooks()
+	if err != nil {
+		return err
+	}
+	legacyHooks = append(legacyHooks, global...)
+
+	if dryRun {
+		for _, path := range legacyHooks {
+			fmt.Printf("🪝 Would rename legacy hook %s → %s%s\n", path, path, hooks.LegacySuffix)
+		}
+	} else {
+		renamed, err := hooks.RenameLegacyHooks(legacyHooks)
+		for _, path := range renamed {
+			fmt.Printf("✅ Renamed legacy hook to %s\n", path)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !legacyDir && len(migrations) == 0 && len(legacyHooks) == 0 {
+		fmt.Println("✅ Nothing to migrate")
+	} else if len(legacyHooks) > 0 && !dryRun {
+		fmt.Println("💡 Run 'fcgh setup' to install the current hooks")
+	}
+	return nil
+}
+
+func policyCommand() *Command {
+	fs := flag.NewFlagSet("policy", flag.ExitOnError)
+
+	return &Command{
+		Name:        "policy",
+		Description: "📜 Install or verify a signed policy bundle",
+		Usage:       "pull [url] | verify",
+		Examples: []string{
+			"fcgh policy pull https://example.com/policy.tar.gz",
+			"fcgh policy verify",
+		},
+		ConfigKeys: []string{"policy"},
+		Flags:      fs,
+		Run: func(ctx context.Context, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("usage: fcgh policy pull [url] | fcgh policy verify")
+			}
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return fmt.Errorf("loading config: %w", err)
+			}
+
+			switch args[0] {
+			case "pull":
+				return pullPolicy(ctx, cfg, args[1:])
+			case "verify":
+				if err := verifyPolicy(cfg); err != nil {
+					return err
+				}
+				fmt.Println("✅ Policy bundle verified")
+				return nil
+			default:
+				return fmt.Errorf("unknown policy action %q (supported: pull, verify)", args[0])
+			}
+		},
+	}
+}
+
+// pullPolicy downloads, verifies and installs the policy bundle from the
+// given URL or policy.url, using the pinned policy.public_key
+func pullPolicy(ctx context.Context, cfg *config.Config, args []string) error {
+	url := cfg.Policy.URL
+	if len(args) > 0 {
+		url = args[0]
+	}
+	if url == "" {
+		return fmt.Errorf("no policy URL: pass one or set policy.url")
+	}
+	key, err := policyKey(cfg)
+	if err != nil {
+		return err
+	}
+	configDir, err := config.GetDefaultConfigDir()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📜 Pulling policy bundle from %s...\n", url)
+	installed, err := policy.Pull(ctx, url, key, configDir)
+	if err != nil {
+		return fmt.Errorf("pulling policy bundle: %w", err)
+	}
+	fmt.Println("✅ Signature verified, installed:")
+	for _, name := range installed {
+		fmt.Printf("   • %s\n", filepath.Join(configDir, name))
+	}
+	return nil
+}
+
+// verifyPolicy checks the installed policy bundle against the pinned key
+func verifyPolicy(cfg *config.Config) error {
+	key, err := policyKey(cfg)
+	if err != nil {
+		return err
+	}
+	configDir, err := config.GetDefaultConfigDir()
+	if err != nil {
+		return err
+	}
+	if err := policy.CheckInstalled(configDir, key); err != nil {
+		if errors.Is(err, policy.ErrNotInstalled) {
+			return fmt.Errorf("%w (run 'fcgh policy pull')", err)
+		}
+		return err
+	}
+	return nil
+}
+
+func policyKey(cfg *config.Config) (ed25519.PublicKey, error) {
+	if cfg.Policy.PublicKey == "" {
+		return nil, fmt.Errorf("no pinned policy public_key configured")
+	}
+	return policy.ParsePublicKey(cfg.Policy.PublicKey)
+}
+
+// authServices maps `fcgh auth` targets to credential store entries
+var authServices = map[string]string{
+	"jira":   secrets.JIRAService,
+	"github": secrets.GitHubService,
+}
+
+func authCommand() *Command {
+	fs := flag.NewFlagSet("auth", flag.ExitOnError)
+
+	return &Command{
+		Name:        "auth",
+		Description: "🔑 Store JIRA/GitHub API tokens in the OS keychain (or an encrypted file)",
+		Usage:       "login|logout|status [jira|github]",
+		Examples: []string{
+			"fcgh auth login jira",
+			"fcgh auth status",
+		},
+		ConfigKeys: []string{"jira_url", "github_repo"},
+		Flags:      fs,
+		Run: func(_ context.Context, args []string) error {
+			return runAuth(secrets.Default(), args, os.Stdin, os.Stdout)
+		},
+	}
+}
+
+// runAuth handles `fcgh auth login|logout|status [jira|github]`. Tokens are
+// read from the first line of in, so they can be piped rather than passed as
+// arguments that end up in shell history.
+func runAuth(store secrets.Store, args []string, in io.Reader, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: fcgh auth login|logout|status [jira|github]")
+	}
+	action := args[0]
+
+	targets := []string{"github", "jira"}
+	if len(args) > 1 {
+		if _, ok := authServices[args[1]]; !ok {
+			return fmt.Errorf("unknown auth target %q (supported: github, jira)", args[1])
+		}
+		targets = args[1:2]
+	} else if action != "status" {
+		return fmt.Errorf("usage: fcgh auth %s jira|github", action)
+	}
+
+	switch action {
+	case "login":
+		target := targets[0]
+		if file, ok := in.(*os.File); !ok || isTerminal(file) {
+			fmt.Fprintf(out, "🔑 Paste your %s API token and press Enter: ", target)
+		}
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("reading token: %w", err)
+		}
+		token := strings.TrimSpace(line)
+		if token == "" {
+			return fmt.Errorf("no token provided")
+		}
+		if err := store.Set(authServices[target], token); err != nil {
+			return fmt.Errorf("storing %s token: %w", target, err)
+		}
+		fmt.Fprintf(out, "✅ Stored %s token in the %s\n", target, store.Name())
+	case "logout":
+		target := targets[0]
+		if err := store.Delete(authServices[target]); err != nil && !errors.Is(err, secrets.ErrNotFound) {
+			return fmt.Errorf("removing %s token: %w", target, err)
+		}
+		fmt.Fprintf(out, "✅ Removed %s token\n", target)
+	case "status":
+		fmt.Fprintf(out, "🔑 Credential store: %s\n", store.Name())
+		for _, target := range targets {
+			if _, err := store.Get(authServices[target]); err == nil {
+				fmt.Fprintf(out, "   ✅ %s: token stored\n", target)
+			} else {
+				fmt.Fprintf(out, "   ❌ %s: no token (run 'fcgh auth login %s')\n", target, target)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown auth action %q (supported: login, logout, status)", action)
+	}
+	return nil
+}
+
+// isTerminal reports whether the file is an interactive character device
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 func removeCommand() *Command {
 	fs := flag.NewFlagSet("remove", flag.ExitOnError)
 	var localRemove bool
 	var globalRemove bool
 	fs.BoolVar(&localRemove, "local", false, "remove hooks only from current repository")
 	fs.BoolVar(&globalRemove, "global", false, "remove hooks only from global git configuration")
+	var purgeFiles bool
+	var assumeYes bool
+	fs.BoolVar(&purgeFiles, "purge", false, "also delete fcgh config, JIRA and pair state, caches and the installed binary")
+	fs.BoolVar(&assumeYes, "yes", false, "with --purge, delete without asking for confirmation")
 
 	return &Command{
 		Name:        "remove",
 		Description: "🗑️  Easy removal - uninstall git hooks",
-		Flags:       fs,
+		Examples: []string{
+			"fcgh remove  # remove local and global hooks",
+			"fcgh remove --local  # this repository only",
+			"fcgh remove --purge  # also delete config, state and caches, for a clean slate",
+		},
+		Flags: fs,
 		Run: func(ctx context.Context, _ []string) error {
 			fmt.Println("🗑️  Removing fcgh...")
 			fmt.Println("   (Don't worry, your code stays safe!)")
@@ -830,6 +2062,18 @@ func removeCommand() *Command {
 			if localRemove && globalRemove {
 				return fmt.Errorf("cannot specify both --local and --global flags")
 			}
+			if purgeFiles && !assumeYes && !isTerminal(os.Stdin) {
+				return fmt.Errorf("--purge asks for confirmation; pass --yes to delete without asking")
+			}
+			purgeState := func() error {
+				repoPath, err := os.Getwd()
+				if err != nil {
+					return fmt.Errorf("getting working directory: %w", err)
+				}
+				fmt.Println("")
+				_, err = purge(os.Stdin, os.Stdout, purgeTargets(repoPath), assumeYes)
+				return err
+			}
 
 			// Detect existing installations
 			hasLocal, hasGlobal, err := checkInstallations()
@@ -840,6 +2084,9 @@ func removeCommand() *Command {
 			// If no installations found
 			if !hasLocal && !hasGlobal {
 				fmt.Println("ℹ️  No fcgh installations found.")
+				if purgeFiles {
+					return purgeState()
+				}
 				return nil
 			}
 
@@ -911,6 +2158,11 @@ func removeCommand() *Command {
 			} else {
 				fmt.Println("ℹ️  Nothing to remove (installation not found)")
 			}
+			if purgeFiles {
+				if err := purgeState(); err != nil {
+					return err
+				}
+			}
 			fmt.Println("💭 Thanks for using fcgh!")
 			return nil
 		},
@@ -1,13 +1,22 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/secrets"
 )
 
 // Helper function to create a test command context
@@ -136,6 +145,113 @@ func TestValidateCommand(t *testing.T) {
 	}
 }
 
+func TestValidateCommandExitCodes(t *testing.T) {
+	cmd := validateCommand()
+	ctx, cleanup := setupTestContext(t)
+	defer cleanup()
+
+	for message, want := range map[string]int{
+		"badtype: invalid commit type": exitViolation,
+		"not a conventional commit":    exitParse,
+	} {
+		if got := exitCode(cmd.Run(ctx, []string{message})); got != want {
+			t.Errorf("validate %q exit code = %d, want %d", message, got, want)
+		}
+	}
+
+	configFile = filepath.Join(t.TempDir(), "fast-cc-config.yaml")
+	defer func() { configFile = "" }()
+	if err := os.WriteFile(configFile, []byte("max_subject_length: -1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := exitCode(cmd.Run(ctx, []string{"feat: add feature"})); got != exitConfig {
+		t.Errorf("validate with an invalid config exit code = %d, want %d", got, exitConfig)
+	}
+
+	if got := exitCode(errors.New("boom")); got != exitFailure {
+		t.Errorf("exitCode() of a plain error = %d, want %d", got, exitFailure)
+	}
+}
+
+func TestRenderFailure(t *testing.T) {
+	cfg := config.Default()
+	cfg.Types = []string{"feat", "fix"}
+	v, err := validator.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := "docs(api): update readme"
+	result := v.Validate(context.Background(), message)
+	result.Errors = append(result.Errors, context.Canceled)
+
+	var out bytes.Buffer
+	renderFailure(&out, message, result, cfg, &config.Provenance{}, palette{})
+	want := `❌ Commit message validation failed (2 problems):
+
+Type
+  ✖ invalid type (allowed: feat, fix)
+      │ docs(api): update readme
+      │ ^^^^
+
+Other
+  ✖ context canceled
+
+💡 How to fix:
+  • Type: use one of feat, fix
+`
+	if out.String() != want {
+		t.Errorf("renderFailure() =\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	renderFailure(&out, message, result, cfg, &config.Provenance{}, palette{enabled: true})
+	if !strings.Contains(out.String(), "\033[31m^^^^\033[0m") {
+		t.Errorf("colored output does not underline in red: %q", out.String())
+	}
+
+	if _, err := newPalette("sometimes", os.Stderr); err == nil {
+		t.Error("expected an error for an unknown --color mode")
+	}
+}
+
+func TestValidateBatch(t *testing.T) {
+	id := strings.Repeat("a1", 20)
+	commits := splitBatch([]byte(id+"\x00feat: add login\n\nbody\n\x00wip stuff\n\x00"), true)
+	want := []batchCommit{{ID: id, Message: "feat: add login\n\nbody\n"}, {Message: "wip stuff\n"}}
+	if !reflect.DeepEqual(commits, want) {
+		t.Errorf("splitBatch(-z) = %q, want %q", commits, want)
+	}
+	commits = splitBatch([]byte(id+"\x00fix: typo\n\nchore: bump\n"), false)
+	want = []batchCommit{{ID: id, Message: "fix: typo"}, {Message: "chore: bump"}}
+	if !reflect.DeepEqual(commits, want) {
+		t.Errorf("splitBatch() = %q, want %q", commits, want)
+	}
+
+	v, err := validator.New(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	err = validateBatch(context.Background(), v, strings.NewReader(id+"\x00feat: add login\n\x00wip stuff\x00"), &out, true)
+	if exitCode(err) != exitViolation {
+		t.Errorf("validateBatch() exit code = %d, want %d", exitCode(err), exitViolation)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("validateBatch() wrote %d lines, want 2:\n%s", len(lines), out.String())
+	}
+	if want := `{"commit":"` + id + `","index":0,"valid":true,"errors":[],"warnings":[]}`; lines[0] != want {
+		t.Errorf("line 1 = %s, want %s", lines[0], want)
+	}
+	if !strings.HasPrefix(lines[1], `{"index":1,"valid":false,"errors":[{"field":"format",`) {
+		t.Errorf("line 2 = %s, want a format error for the second message", lines[1])
+	}
+
+	if !writesData([]string{"validate", "--stdin-batch", "-z"}) || !writesData([]string{"badge"}) || !writesData([]string{"breaking", "--since", "v1.2.0"}) || !writesData([]string{"release-notes", "--pr"}) || !writesData([]string{"labels"}) || !writesData([]string{"squash", "--print"}) || !writesData([]string{"stats", "scopes"}) || !writesData([]string{"routing"}) || !writesData([]string{"version", "--json"}) || writesData([]string{"validate", "feat: x"}) {
+		t.Error("writesData() should only hold for commands with machine-readable stdout")
+	}
+}
+
 func TestStatusCommand(t *testing.T) {
 	cmd := statusCommand()
 
@@ -168,6 +284,117 @@ func TestInitCommand(t *testing.T) {
 	}
 }
 
+func TestInitCommandMergeAndForce(t *testing.T) {
+	cmd := initCommand()
+	ctx, cleanup := setupTestContext(t)
+	defer cleanup()
+
+	configFile = filepath.Join(t.TempDir(), "fast-cc-config.yaml")
+	defer func() { configFile, initForce, initMerge = "", false, false }()
+	custom := "# our rules\ntypes:\n  - feat\nmax_subject_length: 50 # keep it short\n"
+	if err := os.WriteFile(configFile, []byte(custom), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.Run(ctx, nil); err == nil {
+		t.Error("init should refuse to replace an existing config")
+	}
+
+	initMerge = true
+	if err := cmd.Run(ctx, nil); err != nil {
+		t.Fatalf("init --merge error = %v", err)
+	}
+	data, _ := os.ReadFile(configFile)
+	if !strings.HasPrefix(string(data), "# our rules\n") || !strings.Contains(string(data), "max_subject_length: 50 # keep it short") ||
+		!strings.Contains(string(data), "allow_breaking_changes: true") {
+		t.Errorf("merged config = %q", data)
+	}
+
+	initForce = true
+	if err := cmd.Run(ctx, nil); err == nil {
+		t.Error("--force and --merge together should be rejected")
+	}
+
+	initMerge = false
+	if err := cmd.Run(ctx, nil); err != nil {
+		t.Fatalf("init --force error = %v", err)
+	}
+	if cfg, err := config.Load(configFile); err != nil || cfg.MaxSubjectLength != config.DefaultMaxSubjectLength {
+		t.Errorf("forced config = %+v, %v", cfg, err)
+	}
+}
+
+func TestInitCommandPreset(t *testing.T) {
+	cmd := initCommand()
+	ctx, cleanup := setupTestContext(t)
+	defer cleanup()
+
+	configFile = filepath.Join(t.TempDir(), "fast-cc-config.yaml")
+	defer func() { configFile, initPreset, initMerge = "", "", false }()
+
+	initPreset = "missing"
+	if err := cmd.Run(ctx, nil); err == nil {
+		t.Error("init should reject an unknown preset")
+	}
+
+	initPreset = "jira-enterprise"
+	if err := cmd.Run(ctx, nil); err != nil {
+		t.Fatalf("init --preset error = %v", err)
+	}
+	cfg, err := config.Load(configFile)
+	if err != nil || cfg.Extends != "preset:jira-enterprise" || !cfg.RequireJIRATicket || !cfg.ScopeRequired {
+		t.Errorf("preset config = %+v, %v", cfg, err)
+	}
+
+	initMerge = true
+	if err := cmd.Run(ctx, nil); err == nil {
+		t.Error("--preset and --merge together should be rejected")
+	}
+}
+
+func TestConfigGetSetUnset(t *testing.T) {
+	cmd := configCommand()
+	ctx, cleanup := setupTestContext(t)
+	defer cleanup()
+
+	configFile = filepath.Join(t.TempDir(), "fast-cc-config.yaml")
+	defer func() { configFile = "" }()
+	custom := "# our rules\nmax_subject_length: 50 # keep it short\n"
+	if err := os.WriteFile(configFile, []byte(custom), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"set", "max_subject_length", "60"},
+		{"set", "scopes", "api, web"},
+		{"set", "smart_commits.enabled", "true"},
+		{"get", "smart_commits.enabled"},
+		{"unset", "smart_commits.enabled"},
+	} {
+		if err := cmd.Run(ctx, args); err != nil {
+			t.Fatalf("config %v error = %v", args, err)
+		}
+	}
+	data, _ := os.ReadFile(configFile)
+	if want := "# our rules\nmax_subject_length: 60 # keep it short\nscopes:\n  - api\n  - web\n"; string(data) != want {
+		t.Errorf("edited config = %q, want %q", data, want)
+	}
+
+	for _, args := range [][]string{
+		{"set", "max_subject_length", "sixty"},
+		{"set", "no_such_key", "1"},
+		{"set", "max_subject_length", "0"},
+		{"get"},
+	} {
+		if err := cmd.Run(ctx, args); err == nil {
+			t.Errorf("config %v should fail", args)
+		}
+	}
+	if after, _ := os.ReadFile(configFile); !bytes.Equal(after, data) {
+		t.Errorf("failed edits changed the config: %q", after)
+	}
+}
+
 func TestSetupCommand(t *testing.T) {
 	cmd := setupCommand()
 
@@ -270,7 +497,7 @@ func TestEnsureEnterpriseConfigExists(t *testing.T) {
 	defer cleanup()
 	_ = ctx
 
-	configPath, isNew, err := ensureEnterpriseConfigExists()
+	configPath, isNew, err := ensureEnterpriseConfigExists(nil)
 	if err != nil {
 		t.Errorf("ensureEnterpriseConfigExists should not return error: %v", err)
 	}
@@ -407,6 +634,7 @@ func TestAllCommandsHaveRequiredFields(t *testing.T) {
 		setupCommand(),
 		setupEnterpriseCommand(),
 		removeCommand(),
+		prepareMsgCommand(),
 	}
 
 	for _, cmd := range commands {
@@ -428,6 +656,54 @@ func TestAllCommandsHaveRequiredFields(t *testing.T) {
 	}
 }
 
+func TestCommandHelp(t *testing.T) {
+	for name, cmd := range newCommands() {
+		if cmd.Name != name {
+			t.Errorf("command %s is registered as %s", cmd.Name, name)
+		}
+		if len(cmd.Examples) == 0 {
+			t.Errorf("command %s has no examples", name)
+		}
+		for _, example := range cmd.Examples {
+			if !strings.Contains(example, "fcgh "+name) {
+				t.Errorf("command %s example %q does not run it", name, example)
+			}
+		}
+		for _, key := range cmd.ConfigKeys {
+			if _, err := config.KeyType(key); err != nil {
+				t.Errorf("command %s lists config key %s: %v", name, key, err)
+			}
+		}
+		for _, code := range cmd.ExitCodes {
+			if exitCodeMeanings[code] == "" || code == exitFailure {
+				t.Errorf("command %s lists exit code %d", name, code)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	printCommandHelp(&out, validateCommand())
+	help := out.String()
+	for _, want := range []string{
+		"Usage:\n  fcgh validate [flags] [message]\n",
+		"-stdin-batch",
+		"fcgh validate --file .git/COMMIT_EDITMSG --fix",
+		"Config keys:\n  types, scopes,",
+		"  0  success\n  1  any other failure\n  2  a commit message breaks a rule",
+		"  4  the commit message is empty",
+	} {
+		if !strings.Contains(help, want) {
+			t.Errorf("validate help lacks %q:\n%s", want, help)
+		}
+	}
+
+	out.Reset()
+	printCommandHelp(&out, statusCommand())
+	if help := out.String(); strings.Contains(help, "Flags:") || strings.Contains(help, "Config keys:") || !strings.Contains(help, "Usage:\n  fcgh status\n") {
+		t.Errorf("status help = %q", help)
+	}
+}
+
 // Test edge cases
 func TestValidateCommandWithEmptyArgs(t *testing.T) {
 	cmd := validateCommand()
@@ -524,7 +800,7 @@ func TestConfigFileOperations(t *testing.T) {
 	}
 
 	// Test enterprise config
-	entConfigPath, _, err := ensureEnterpriseConfigExists()
+	entConfigPath, _, err := ensureEnterpriseConfigExists(nil)
 	if err != nil {
 		t.Fatalf("Failed to ensure enterprise config exists: %v", err)
 	}
@@ -626,7 +902,7 @@ func TestEnsureEnterpriseConfigExistsEdgeCases(t *testing.T) {
 		t.Fatalf("Failed to write config file: %v", err)
 	}
 
-	_, isNew, err := ensureEnterpriseConfigExists()
+	_, isNew, err := ensureEnterpriseConfigExists(nil)
 	if err != nil {
 		t.Errorf("Should handle existing config: %v", err)
 	}
@@ -905,7 +1181,7 @@ func TestEnsureEnterpriseConfigExistsComprehensive(t *testing.T) {
 	}
 	defer os.Remove(".fast-cc-hooks.yaml")
 
-	configPath, isNew, err := ensureEnterpriseConfigExists()
+	configPath, isNew, err := ensureEnterpriseConfigExists(nil)
 	if err != nil {
 		t.Errorf("Enterprise config should handle existing local file: %v", err)
 	}
@@ -913,6 +1189,67 @@ func TestEnsureEnterpriseConfigExistsComprehensive(t *testing.T) {
 	_ = isNew
 }
 
+func TestEnterpriseQuestionnaire(t *testing.T) {
+	var out bytes.Buffer
+	answers, err := askEnterpriseQuestions(strings.NewReader("cgc, plat\napi,web\ny\n60\nyes\n"), &out)
+	if err != nil {
+		t.Fatalf("askEnterpriseQuestions() error = %v", err)
+	}
+	want := &enterpriseAnswers{
+		JIRAProjects:     []string{"CGC", "PLAT"},
+		Scopes:           []string{"api", "web"},
+		ScopeRequired:    true,
+		MaxSubjectLength: 60,
+		RequireSignoff:   true,
+	}
+	if !reflect.DeepEqual(answers, want) {
+		t.Errorf("answers = %+v, want %+v", answers, want)
+	}
+
+	// Empty input keeps the defaults
+	defaults, err := askEnterpriseQuestions(strings.NewReader(""), &out)
+	if err != nil || defaults.MaxSubjectLength != config.DefaultMaxSubjectLength ||
+		!reflect.DeepEqual(defaults.Scopes, defaultEnterpriseScopes) || defaults.JIRAProjects != nil {
+		t.Errorf("default answers = %+v, %v", defaults, err)
+	}
+
+	if _, err := askEnterpriseQuestions(strings.NewReader("\n\n\nlong\n"), &out); err == nil {
+		t.Error("expected an error for a non-numeric subject length")
+	}
+
+	destPath := filepath.Join(t.TempDir(), "fast-cc-config.yaml")
+	if err := writeTailoredEnterpriseConfig(destPath, answers); err != nil {
+		t.Fatalf("writeTailoredEnterpriseConfig() error = %v", err)
+	}
+	cfg, err := config.Load(destPath)
+	if err != nil {
+		t.Fatalf("loading tailored config: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.JIRAProjects, want.JIRAProjects) || !reflect.DeepEqual(cfg.Scopes, want.Scopes) ||
+		!cfg.ScopeRequired || cfg.MaxSubjectLength != 60 || !cfg.RequireSignoff || !cfg.RequireJIRATicket {
+		t.Errorf("tailored config = %+v", cfg)
+	}
+}
+
+func TestLoadAnswers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "answers.yaml")
+	if err := os.WriteFile(path, []byte("jira_projects: [CGC]\nmax_subject_length: 80\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	answers, err := loadAnswers(path)
+	if err != nil || answers.MaxSubjectLength != 80 || !reflect.DeepEqual(answers.JIRAProjects, []string{"CGC"}) {
+		t.Errorf("loadAnswers() = %+v, %v", answers, err)
+	}
+
+	if err := os.WriteFile(path, []byte("jira_project: [CGC]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadAnswers(path); err == nil {
+		t.Error("expected an error for an unknown answer key")
+	}
+}
+
 // Test removeCommand with different installation scenarios
 func TestRemoveCommandComprehensive(t *testing.T) {
 	cmd := removeCommand()
@@ -1599,7 +1936,7 @@ func TestEnsureEnterpriseConfigExistsMoreErrors(t *testing.T) {
 
 	configFile = filepath.Join(readOnlyDir, "config.yaml")
 
-	_, _, err := ensureEnterpriseConfigExists()
+	_, _, err := ensureEnterpriseConfigExists(nil)
 	if err == nil {
 		t.Error("Expected error when enterprise config path is not writable")
 	}
@@ -1748,3 +2085,338 @@ func TestCopyEnterpriseConfigComprehensive(t *testing.T) {
 		}
 	}
 }
+
+func TestPrepareMsgCommand(t *testing.T) {
+	ctx, cleanup := setupTestContext(t)
+	defer cleanup()
+
+	t.Run("requires file flag", func(t *testing.T) {
+		cmd := prepareMsgCommand()
+		if err := cmd.Run(ctx, []string{}); err == nil {
+			t.Error("prepare-msg without --file should return error")
+		}
+	})
+
+	t.Run("leaves existing message untouched", func(t *testing.T) {
+		msgFile := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+		original := "fix: user supplied message\n# Please enter the commit message\n"
+		if err := os.WriteFile(msgFile, []byte(original), 0o600); err != nil {
+			t.Fatalf("Failed to create message file: %v", err)
+		}
+
+		if err := prepareCommitMessage(context.Background(), msgFile); err != nil {
+			t.Fatalf("prepareCommitMessage() error = %v", err)
+		}
+
+		content, err := os.ReadFile(msgFile)
+		if err != nil {
+			t.Fatalf("Failed to read message file: %v", err)
+		}
+		if string(content) != original {
+			t.Errorf("message file was modified: %q", string(content))
+		}
+	})
+
+	t.Run("missing file returns error", func(t *testing.T) {
+		if err := prepareCommitMessage(context.Background(), filepath.Join(t.TempDir(), "missing")); err == nil {
+			t.Error("prepareCommitMessage() should fail for missing file")
+		}
+	})
+}
+
+func TestRunAuth(t *testing.T) {
+	store := secrets.NewFileStore(t.TempDir())
+	var out bytes.Buffer
+
+	if err := runAuth(store, []string{"login", "jira"}, strings.NewReader("jira-token\n"), &out); err != nil {
+		t.Fatalf("login error = %v", err)
+	}
+	if got, err := store.Get(secrets.JIRAService); err != nil || got != "jira-token" {
+		t.Errorf("stored token = %q, %v", got, err)
+	}
+
+	out.Reset()
+	if err := runAuth(store, []string{"status"}, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("status error = %v", err)
+	}
+	if !strings.Contains(out.String(), "jira: token stored") || !strings.Contains(out.String(), "github: no token") {
+		t.Errorf("unexpected status output:\n%s", out.String())
+	}
+
+	if err := runAuth(store, []string{"logout", "jira"}, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("logout error = %v", err)
+	}
+	if _, err := store.Get(secrets.JIRAService); !errors.Is(err, secrets.ErrNotFound) {
+		t.Errorf("expected token to be removed, got %v", err)
+	}
+
+	for _, args := range [][]string{{}, {"login"}, {"login", "gitlab"}, {"rotate", "jira"}} {
+		if err := runAuth(store, args, strings.NewReader("token\n"), &out); err == nil {
+			t.Errorf("runAuth(%v) expected an error", args)
+		}
+	}
+	if err := runAuth(store, []string{"login", "github"}, strings.NewReader("\n"), &out); err == nil {
+		t.Error("expected an error for an empty token")
+	}
+}
+
+func TestPolicyCommand(t *testing.T) {
+	cmd := policyCommand()
+	if cmd.Name != "policy" {
+		t.Errorf("Expected command name 'policy', got %s", cmd.Name)
+	}
+	if err := cmd.Run(context.Background(), nil); err == nil {
+		t.Error("Expected an error without a policy action")
+	}
+
+	if err := verifyPolicy(&config.Config{}); err == nil {
+		t.Error("Expected verification to fail without a pinned public key")
+	}
+}
+
+func TestPrecheckHook(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("shell hooks are not executable on Windows")
+	}
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Chdir(dir)
+
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("config", "user.name", "Test")
+	git("config", "user.email", "test@example.com")
+	file := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(file, []byte("one\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "a.txt")
+	git("commit", "-q", "-m", "feat: add a")
+
+	// Staged "two", with "three" left unstaged on top
+	if err := os.WriteFile(file, []byte("two\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "a.txt")
+	if err := os.WriteFile(file, []byte("three\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if problem, err := precheckHook(context.Background(), &out, true); err != nil || problem != "" {
+		t.Fatalf("precheckHook() without a hook = %q, %v", problem, err)
+	}
+
+	// The hook only passes when it sees the staged content
+	hook := "#!/bin/sh\ngrep -q two a.txt\n"
+	if err := os.WriteFile(filepath.Join(dir, ".git", "hooks", "pre-commit"), []byte(hook), 0o700); err != nil { // #nosec G306 - hooks must be executable
+		t.Fatal(err)
+	}
+	if problem, err := precheckHook(context.Background(), &out, true); err != nil || problem != "" {
+		t.Fatalf("precheckHook() = %q, %v\n%s", problem, err, out.String())
+	}
+	if content, _ := os.ReadFile(file); string(content) != "three\n" {
+		t.Errorf("unstaged changes not restored, a.txt = %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", precheckPatch)); !os.IsNotExist(err) {
+		t.Errorf("patch file left behind: %v", err)
+	}
+
+	if problem, err := precheckHook(context.Background(), &out, false); err != nil || problem == "" {
+		t.Errorf("precheckHook() with --no-stash = %q, %v, want a failed hook", problem, err)
+	}
+
+	git("add", "a.txt")
+	if problem := precheckStaged(context.Background(), &out); problem != "" {
+		t.Errorf("precheckStaged() = %q", problem)
+	}
+	git("commit", "-q", "--no-verify", "-m", "feat: update a")
+	if problem := precheckStaged(context.Background(), &out); problem != "nothing is staged" {
+		t.Errorf("precheckStaged() with nothing staged = %q", problem)
+	}
+}
+
+func TestWipChain(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Chdir(dir)
+
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("config", "user.name", "Test")
+	git("config", "user.email", "test@example.com")
+	git("commit", "-q", "--allow-empty", "-m", "WIP: first")
+
+	// WIP commits down to the root have nothing to squash onto
+	if _, base, count, err := wipChain(context.Background()); err != nil || base != "" || count != 1 {
+		t.Fatalf("wipChain() = %q, %d, %v, want root chain of 1", base, count, err)
+	}
+
+	git("commit", "-q", "--allow-empty", "-m", "feat: add a")
+	if _, _, count, err := wipChain(context.Background()); err != nil || count != 0 {
+		t.Fatalf("wipChain() on a conventional commit = %d, %v", count, err)
+	}
+
+	git("commit", "-q", "--allow-empty", "-m", "WIP: second")
+	git("commit", "-q", "--allow-empty", "-m", "WIP: third")
+	head, base, count, err := wipChain(context.Background())
+	if err != nil || count != 2 {
+		t.Fatalf("wipChain() = %d, %v, want 2", count, err)
+	}
+	if want, _ := gitOutput(context.Background(), "rev-parse", "HEAD"); head != want {
+		t.Errorf("head = %q, want %q", head, want)
+	}
+	if want, _ := gitOutput(context.Background(), "rev-parse", "HEAD~2"); base != want {
+		t.Errorf("base = %q, want %q", base, want)
+	}
+}
+
+func TestReadBuildInfo(t *testing.T) {
+	info := readBuildInfo()
+	if info.Version != version || info.GoVersion == "" || info.Platform == "" {
+		t.Errorf("readBuildInfo() = %+v, want the version and toolchain", info)
+	}
+	if info.Module == nil || info.Module.Path == "" {
+		t.Fatalf("readBuildInfo().Module = %+v, want the main module", info.Module)
+	}
+	if len(info.SHA256) != 64 {
+		t.Errorf("readBuildInfo().SHA256 = %q, want the executable's checksum", info.SHA256)
+	}
+
+	var out bytes.Buffer
+	if err := writeJSON(&out, info); err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("version --json output is not JSON: %v\n%s", err, out.String())
+	}
+	for _, key := range []string{"version", "commit", "go_version", "platform", "module", "dependencies"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("version --json output lacks %q:\n%s", key, out.String())
+		}
+	}
+}
+
+// TestMain runs fcgh instead of the tests when FCGH_TEST_MAIN is set, so
+// tests can check the command line end to end through runFcgh.
+func TestMain(m *testing.M) {
+	if os.Getenv("FCGH_TEST_MAIN") != "" {
+		// A release version, which -v shows in the banner
+		version = "v1.2.3"
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runFcgh runs fcgh with args in dir by re-executing the test binary and
+// returns its combined output.
+func runFcgh(t *testing.T, dir string, args ...string) (string, error) {
+	t.Helper()
+	// #nosec G204 - re-executes the test binary
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "FCGH_TEST_MAIN=1")
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func TestGlobalRepoFlag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, name := range []string{"GIT_DIR", "GIT_WORK_TREE", "FCGH_CONFIG_DIR"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+
+	// fcgh runs from cwd, which is a repository of its own
+	cwd := filepath.Join(t.TempDir(), "cwd")
+	repo := filepath.Join(t.TempDir(), "repo")
+	for _, dir := range []string{cwd, repo} {
+		if out, err := exec.Command("git", "init", dir).CombinedOutput(); err != nil {
+			t.Fatalf("git init %s: %v\n%s", dir, err, out)
+		}
+	}
+	hook := func(dir string) string { return filepath.Join(dir, ".git", "hooks", "commit-msg") }
+
+	t.Run("setup", func(t *testing.T) {
+		if out, err := runFcgh(t, cwd, "--repo", repo, "setup", "--local"); err != nil {
+			t.Fatalf("fcgh --repo %s setup --local: %v\n%s", repo, err, out)
+		}
+		if _, err := os.Stat(hook(repo)); err != nil {
+			t.Errorf("hook not installed in --repo: %v", err)
+		}
+		if _, err := os.Stat(hook(cwd)); !os.IsNotExist(err) {
+			t.Errorf("hook installed in the working directory: %v", err)
+		}
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(repo, "MSG"), []byte("feat: add login\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		out, err := runFcgh(t, cwd, "-v", "--repo", repo, "validate", "--file", "MSG")
+		if err != nil {
+			t.Fatalf("fcgh -v --repo %s validate --file MSG: %v\n%s", repo, err, out)
+		}
+		if !strings.Contains(out, "version v1.2.3") || !strings.Contains(out, "Commit message is valid") {
+			t.Errorf("output = %q, want the version banner of -v and MSG of --repo validated", out)
+		}
+
+		if out, err := runFcgh(t, repo, "-v", "validate", "--file", "MSG"); err != nil || !strings.Contains(out, "version v1.2.3") {
+			t.Errorf("fcgh -v validate --file MSG: %v, want the version banner\n%s", err, out)
+		}
+		if out, err := runFcgh(t, repo, "validate", "-v", "--file", "MSG"); err == nil {
+			t.Errorf("fcgh validate -v --file MSG succeeded, want -v after the command rejected\n%s", out)
+		}
+	})
+
+	t.Run("missing repository", func(t *testing.T) {
+		missing := filepath.Join(t.TempDir(), "missing")
+		out, err := runFcgh(t, cwd, "--repo", missing, "setup", "--local")
+		if err == nil || !strings.Contains(out, "Error: --repo:") {
+			t.Errorf("fcgh --repo %s setup --local: %v, want a --repo error\n%s", missing, err, out)
+		}
+		if _, err := os.Stat(hook(cwd)); !os.IsNotExist(err) {
+			t.Errorf("hook installed in the working directory: %v", err)
+		}
+	})
+
+	t.Run("global flag after the command", func(t *testing.T) {
+		_ = os.Remove(hook(repo))
+		out, err := runFcgh(t, cwd, "setup", "--local", "--repo", repo)
+		if err == nil || !strings.Contains(out, "-repo") {
+			t.Errorf("fcgh setup --local --repo %s: %v, want the flag rejected\n%s", repo, err, out)
+		}
+		for _, dir := range []string{repo, cwd} {
+			if _, err := os.Stat(hook(dir)); !os.IsNotExist(err) {
+				t.Errorf("hook installed in %s: %v", dir, err)
+			}
+		}
+	})
+}
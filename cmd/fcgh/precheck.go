@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+var (
+	precheckMessage string
+	precheckNoStash bool
+)
+
+// precheckPatch holds unstaged changes while the pre-commit hook runs, in
+// the git directory so they survive a crash.
+const precheckPatch = "fcgh-precheck.patch"
+
+func precheckCommand() *Command {
+	fs := flag.NewFlagSet("precheck", flag.ExitOnError)
+	fs.StringVar(&precheckMessage, "m", "", "check this commit message instead of the commit.template or generated one")
+	fs.BoolVar(&precheckNoStash, "no-stash", false, "run the pre-commit hook with unstaged changes in place")
+
+	return &Command{
+		Name:        "precheck",
+		Description: "🚦 Check whether a commit would go through, without committing",
+		Examples: []string{
+			"fcgh precheck  # staged changes, pre-commit hook and the generated message",
+			"fcgh precheck -m \"fix: handle empty input\"",
+		},
+		ConfigKeys: []string{"types", "scopes", "checks"},
+		ExitCodes:  []int{exitViolation, exitConfig, exitIntegration},
+		Flags:      fs,
+		// Pre-commit hooks run linters and tests, which can exceed the usual timeout
+		LongRunning: true,
+		Run: func(ctx context.Context, _ []string) error {
+			cfg, prov, err := config.LoadWithProvenance(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+			colors, err := newPalette("auto", os.Stderr)
+			if err != nil {
+				return err
+			}
+
+			var problems []string
+			if problem := precheckStaged(ctx, os.Stdout); problem != "" {
+				problems = append(problems, problem)
+			}
+			if problem, err := precheckHook(ctx, os.Stdout, !precheckNoStash); err != nil {
+				return withExitCode(exitIntegration, err)
+			} else if problem != "" {
+				problems = append(problems, problem)
+			}
+
+			message, source, err := precheckCommitMessage(ctx, cfg)
+			if err != nil {
+				return withExitCode(exitIntegration, err)
+			}
+			v, err := newValidator(cfg)
+			if err != nil {
+				return err
+			}
+			// Without staged changes there is no message to generate; that
+			// problem is already reported
+			if message != "" || source != "generated" {
+				result := v.Validate(ctx, message)
+				renderWarnings(os.Stderr, result.Warnings, colors)
+				header, _, _ := strings.Cut(message, "\n")
+				if result.Valid {
+					fmt.Printf("✅ Commit message (%s): %s\n", source, header)
+				} else {
+					fmt.Printf("❌ Commit message (%s) is invalid\n", source)
+					renderFailure(os.Stderr, message, result, cfg, prov, colors)
+					problems = append(problems, "the commit message is invalid")
+				}
+			}
+
+			fmt.Println()
+			if len(problems) > 0 {
+				fmt.Printf("❌ Your commit would be rejected: %s\n", strings.Join(problems, "; "))
+				return withExitCode(exitViolation, errors.New("precheck failed"))
+			}
+			fmt.Println("✅ Your commit will go through")
+			return nil
+		},
+	}
+}
+
+// precheckStaged reports whether anything is staged, and unresolved merge
+// conflicts, which git refuses to commit
+func precheckStaged(ctx context.Context, w io.Writer) string {
+	if conflicts, err := gitOutput(ctx, "diff", "--name-only", "--diff-filter=U"); err == nil && conflicts != "" {
+		fmt.Fprintf(w, "❌ Unresolved conflicts: %s\n", strings.Join(strings.Fields(conflicts), ", "))
+		return "merge conflicts are unresolved"
+	}
+	staged, err := gitOutput(ctx, "diff", "--cached", "--name-only")
+	if err != nil || staged == "" {
+		fmt.Fprintln(w, "❌ Nothing is staged")
+		return "nothing is staged"
+	}
+	fmt.Fprintf(w, "✅ Staged changes: %d file(s)\n", len(strings.Split(staged, "\n")))
+	return ""
+}
+
+// precheckHook runs the repository's pre-commit hook the way git commit
+// would. With stash set, unstaged changes to tracked files are set aside
+// first, so the hook checks exactly what would be committed.
+func precheckHook(ctx context.Context, w io.Writer, stash bool) (string, error) {
+	hook, err := gitOutput(ctx, "rev-parse", "--git-path", "hooks/pre-commit")
+	if err != nil {
+		return "", fmt.Errorf("locating the pre-commit hook: %w", err)
+	}
+	if info, err := os.Stat(hook); err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+		fmt.Fprintln(w, "✅ No pre-commit hook to run")
+		return "", nil
+	}
+	hook, err = filepath.Abs(hook)
+	if err != nil {
+		return "", fmt.Errorf("locating the pre-commit hook: %w", err)
+	}
+	top, err := gitOutput(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("locating the work tree: %w", err)
+	}
+
+	restore := func() error { return nil }
+	if stash {
+		if restore, err = stashUnstaged(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, hook) // #nosec G204 - the repository's own pre-commit hook, as git runs it
+	cmd.Dir = top
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	hookErr := cmd.Run()
+
+	if err := restore(); err != nil {
+		return "", err
+	}
+	if hookErr != nil {
+		fmt.Fprintf(w, "❌ pre-commit hook failed: %v\n", hookErr)
+		return "the pre-commit hook failed", nil
+	}
+	fmt.Fprintln(w, "✅ pre-commit hook passed")
+	return "", nil
+}
+
+// stashUnstaged saves unstaged changes to tracked files as a patch and
+// resets them to the index. The returned function restores them, dropping
+// any changes the hook made to those files if both cannot be kept.
+func stashUnstaged(ctx context.Context) (func() error, error) {
+	patch, err := gitOutput(ctx, "diff", "--binary", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return nil, fmt.Errorf("reading unstaged changes: %w", err)
+	}
+	if patch == "" {
+		return func() error { return nil }, nil
+	}
+
+	path, err := gitOutput(ctx, "rev-parse", "--git-path", precheckPatch)
+	if err != nil {
+		return nil, fmt.Errorf("locating the git directory: %w", err)
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return nil, fmt.Errorf("locating the git directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(patch+"\n"), 0o600); err != nil {
+		return nil, fmt.Errorf("saving unstaged changes: %w", err)
+	}
+	if _, err := gitOutput(ctx, "checkout", "--", ":/"); err != nil {
+		return nil, fmt.Errorf("setting unstaged changes aside (saved in %s): %w", path, err)
+	}
+
+	// Restoring must finish even if the hook was interrupted
+	return func() error {
+		restoreCtx := context.WithoutCancel(ctx)
+		if _, err := gitOutput(restoreCtx, "apply", "--whitespace=nowarn", path); err != nil {
+			fmt.Fprintln(os.Stderr, "⚠️  The pre-commit hook changed files with unstaged changes; discarding its changes")
+			if _, err := gitOutput(restoreCtx, "checkout", "--", ":/"); err != nil {
+				return fmt.Errorf("restoring unstaged changes: %w; they are saved in %s (git apply %s)", err, path, path)
+			}
+			if _, err := gitOutput(restoreCtx, "apply", "--whitespace=nowarn", path); err != nil {
+				return fmt.Errorf("restoring unstaged changes: %w; they are saved in %s (git apply %s)", err, path, path)
+			}
+		}
+		return os.Remove(path)
+	}, nil
+}
+
+// precheckCommitMessage returns the message git commit would start from and
+// where it came from: -m, a commit.template with content, or the message
+// the prepare-commit-msg hook would generate from the staged changes
+func precheckCommitMessage(ctx context.Context, cfg *config.Config) (string, string, error) {
+	if precheckMessage != "" {
+		return precheckMessage, "-m", nil
+	}
+	if path, err := gitOutput(ctx, "config", "--path", "--get", "commit.template"); err == nil && path != "" {
+		message, err := validator.ReadMessageFile(path)
+		if err != nil {
+			return "", "", fmt.Errorf("reading commit.template: %w", err)
+		}
+		if message != "" {
+			return message, "commit.template", nil
+		}
+	}
+
+	result, err := generateStaged(ctx, cfg, "")
+	if err != nil {
+		return "", "", fmt.Errorf("generating commit message: %w", err)
+	}
+	return result.Message, "generated", nil
+}
+
+// gitOutput runs git in the working directory and returns its trimmed output
+func gitOutput(ctx context.Context, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 - fixed git subcommands
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/audit"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/webhook"
+)
+
+func prePushCommand() *Command {
+	fs := flag.NewFlagSet("pre-push", flag.ExitOnError)
+
+	return &Command{
+		Name:        "pre-push",
+		Description: "🛂 Re-validate the commits of a push (run by the pre-push hook)",
+		Usage:       "<remote> [url] < refs",
+		Examples: []string{
+			"fcgh pre-push origin  # run by the pre-push hook, refs on stdin",
+		},
+		ConfigKeys: []string{"pre_push", "types", "scopes"},
+		ExitCodes:  []int{exitViolation, exitConfig, exitIntegration},
+		Flags:      fs,
+		Run: func(ctx context.Context, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("usage: fcgh pre-push <remote> [url] < refs")
+			}
+			return prePush(ctx, args[0])
+		},
+	}
+}
+
+// prePush validates the commits git is about to push to remote, reading
+// the ref updates from stdin. Invalid commits were made with the commit-msg
+// hook skipped: with the block action the push is rejected, otherwise they
+// are recorded in the audit log and the webhook.
+func prePush(ctx context.Context, remote string) error {
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+	}
+	refs, err := audit.ParsePushedRefs(os.Stdin)
+	if err != nil {
+		return withExitCode(exitIntegration, err)
+	}
+	// Like audits of history, ticket lookups are skipped: they already ran
+	// in the commit-msg hook, and a push should not wait on the tracker
+	deriveScopes(cfg, "")
+	v, err := validator.New(cfg)
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("creating validator: %w", err))
+	}
+	bypasses, err := audit.Outgoing(ctx, v, "", remote, refs)
+	if err != nil {
+		return withExitCode(exitIntegration, fmt.Errorf("reading pushed commits: %w", err))
+	}
+	if len(bypasses) == 0 {
+		return nil
+	}
+
+	for _, bypass := range bypasses {
+		fmt.Fprintf(os.Stderr, "❌ %.7s %s\n", bypass.Hash, bypass.Subject)
+		for _, e := range bypass.Errors {
+			fmt.Fprintf(os.Stderr, "   • %s\n", e.Message)
+		}
+	}
+
+	if cfg.PrePush.Action == config.PrePushBlock {
+		notifyPushed(ctx, cfg, webhook.EventBlocked, bypasses)
+		fmt.Fprintln(os.Stderr, "💡 Reword them with 'git rebase -i' before pushing")
+		return withExitCode(exitViolation, fmt.Errorf("%d pushed commit(s) break the commit message rules", len(bypasses)))
+	}
+
+	path, err := prePushLog(ctx, cfg)
+	if err == nil {
+		err = audit.RecordBypasses(path, bypasses)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not record bypassed commits: %v\n", err)
+	} else {
+		fmt.Fprintf(os.Stderr, "⚠️  %d pushed commit(s) break the commit message rules; recorded in %s\n", len(bypasses), path)
+	}
+	notifyPushed(ctx, cfg, webhook.EventBypass, bypasses)
+	return nil
+}
+
+// prePushLog returns the path of the bypass audit log; relative paths are
+// in the git directory shared by all worktrees
+func prePushLog(ctx context.Context, cfg *config.Config) (string, error) {
+	path := cfg.PrePush.Log
+	if path == "" {
+		path = config.DefaultPrePushLog
+	}
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	gitDir, err := gitOutput(ctx, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("locating the git directory: %w", err)
+	}
+	return filepath.Abs(filepath.Join(gitDir, path))
+}
+
+// notifyPushed posts each invalid pushed commit to the configured webhook.
+// Webhook failures are only reported, so they never change the outcome.
+func notifyPushed(ctx context.Context, cfg *config.Config, event string, bypasses []audit.Bypass) {
+	notifier := webhook.New(cfg.Webhook)
+	if !notifier.Enabled(event) {
+		return
+	}
+	for _, bypass := range bypasses {
+		violations := make([]string, 0, len(bypass.Errors))
+		for _, e := range bypass.Errors {
+			violations = append(violations, e.Error())
+		}
+		if err := notifier.Notify(ctx, event, bypass.Message, violations); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Webhook notification failed: %v\n", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/dirs"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/pair"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/scopepin"
+)
+
+// purgeTargets returns the existing files and directories fcgh created
+// outside the hooks: the config directory with the generated config, JIRA
+// state and secrets, the legacy ~/.fast-cc, the cache, the binary installed
+// with --install-binary, and the JIRA, pair and pinned scope state of the
+// repository at repoPath. A repository's .fast-cc directory itself is kept,
+// as it is usually shared with the team.
+func purgeTargets(repoPath string) []string {
+	var candidates []string
+	for _, dir := range []func() (string, error){dirs.Config, dirs.XDGConfig, dirs.Legacy, dirs.Cache, dirs.Bin} {
+		if path, err := dir(); err == nil {
+			candidates = append(candidates, path)
+		}
+	}
+	// The binary sits in its own fast-cc data directory
+	if bin, err := dirs.Bin(); err == nil && filepath.Base(filepath.Dir(bin)) == dirs.Name {
+		candidates = append(candidates, filepath.Dir(bin))
+	}
+
+	stateDirs := []string{filepath.Join(repoPath, ".fast-cc")}
+	// #nosec G204 - fixed git command
+	if out, err := exec.Command("git", "-C", repoPath, "rev-parse", "--absolute-git-dir").Output(); err == nil {
+		stateDirs = append(stateDirs, strings.TrimSpace(string(out)))
+	}
+	for _, dir := range stateDirs {
+		for _, name := range []string{jira.JiraRefFile, pair.PairFile, scopepin.ScopeFile} {
+			candidates = append(candidates, filepath.Join(dir, name))
+		}
+	}
+
+	var targets []string
+	seen := make(map[string]bool)
+	for _, path := range candidates {
+		path = filepath.Clean(path)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if _, err := os.Lstat(path); err == nil {
+			targets = append(targets, path)
+		}
+	}
+
+	// Drop paths inside another target, which go with it
+	kept := targets[:0]
+	for _, path := range targets {
+		inside := false
+		for _, other := range targets {
+			if other != path && strings.HasPrefix(path, other+string(filepath.Separator)) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			kept = append(kept, path)
+		}
+	}
+	return kept
+}
+
+// purge lists targets on out and deletes them once confirmed on in, or
+// straight away when assumeYes is set. It reports whether they were deleted.
+func purge(in io.Reader, out io.Writer, targets []string, assumeYes bool) (bool, error) {
+	if len(targets) == 0 {
+		fmt.Fprintln(out, "ℹ️  No fcgh config, state or cache files found.")
+		return false, nil
+	}
+
+	fmt.Fprintln(out, "🧹 These fcgh files and directories will be deleted:")
+	for _, path := range targets {
+		fmt.Fprintf(out, "   %s\n", path)
+	}
+	if !assumeYes {
+		fmt.Fprint(out, "Delete them? [y/N]: ")
+		answer, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return false, fmt.Errorf("reading confirmation: %w", err)
+		}
+		if !isYes(strings.TrimSpace(answer)) {
+			fmt.Fprintln(out, "❌ Kept config, state and cache files")
+			return false, nil
+		}
+	}
+
+	for _, path := range targets {
+		if err := os.RemoveAll(path); err != nil {
+			return false, fmt.Errorf("deleting %s: %w", path, err)
+		}
+	}
+	fmt.Fprintf(out, "✅ Deleted %d fcgh path(s)\n", len(targets))
+	return true, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPurge(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+	t.Setenv("FCGH_CONFIG_DIR", "")
+	repo := t.TempDir()
+
+	configDir := filepath.Join(home, "config", "fast-cc")
+	cacheDir := filepath.Join(home, "cache", "fast-cc")
+	ticket := filepath.Join(repo, ".fast-cc", "jira-commit-ref.txt")
+	shared := filepath.Join(repo, ".fast-cc", "fast-cc-config.yaml")
+	for _, file := range []string{filepath.Join(configDir, "fast-cc-config.yaml"), filepath.Join(cacheDir, "analysis.json"), ticket, shared} {
+		if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	targets := purgeTargets(repo)
+	if want := []string{configDir, cacheDir, ticket}; !reflect.DeepEqual(targets, want) {
+		t.Fatalf("purgeTargets() = %q, want %q", targets, want)
+	}
+
+	// Anything but yes keeps the files
+	if deleted, err := purge(strings.NewReader("\n"), io.Discard, targets, false); err != nil || deleted {
+		t.Fatalf("purge() unconfirmed = %v, %v", deleted, err)
+	}
+	if _, err := os.Stat(configDir); err != nil {
+		t.Errorf("config deleted without confirmation: %v", err)
+	}
+
+	if deleted, err := purge(strings.NewReader("y\n"), io.Discard, targets, false); err != nil || !deleted {
+		t.Fatalf("purge() confirmed = %v, %v", deleted, err)
+	}
+	for _, path := range targets {
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after purge(): %v", path, err)
+		}
+	}
+	if _, err := os.Stat(shared); err != nil {
+		t.Errorf("repository config deleted: %v", err)
+	}
+	if remaining := purgeTargets(repo); len(remaining) != 0 {
+		t.Errorf("purgeTargets() after purge() = %q", remaining)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/audit"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/prcomment"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+)
+
+// releaseNotesMarker identifies the release-notes comment fcgh keeps up to
+// date on a pull request
+const releaseNotesMarker = "<!-- fcgh:release-notes -->"
+
+var (
+	releaseNotesPR    bool
+	releaseNotesRange string
+	releaseNotesJIRA  bool
+)
+
+func releaseNotesCommand() *Command {
+	fs := flag.NewFlagSet("release-notes", flag.ExitOnError)
+	fs.BoolVar(&releaseNotesPR, "pr", false, "post the notes as a sticky comment on the pull request of this CI job (GitHub Actions or GitLab CI)")
+	fs.BoolVar(&releaseNotesJIRA, "jira", false, "group the changes by JIRA ticket, with ticket summaries from the JIRA API")
+	fs.StringVar(&releaseNotesRange, "range", "", "revision range to summarize (default: the pull request's commits in CI, else since the latest tag)")
+
+	return &Command{
+		Name:        "release-notes",
+		Description: "📝 Generate a release-note snippet from commits, and post it on the pull request (--pr)",
+		Examples: []string{
+			"fcgh release-notes --range v1.2.0..HEAD",
+			"fcgh release-notes --pr  # sticky comment on the CI pull request",
+			"fcgh release-notes --jira",
+		},
+		ConfigKeys: []string{"type_labels", "jira_url"},
+		ExitCodes:  []int{exitConfig, exitIntegration},
+		Flags:      fs,
+		Run: func(ctx context.Context, _ []string) error {
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+
+			target, err := prcomment.Detect(nil)
+			if err != nil && (releaseNotesPR || !errors.Is(err, prcomment.ErrNotInPullRequest)) {
+				return withExitCode(exitIntegration, err)
+			}
+			revisions := releaseNotesRange
+			if revisions == "" && target != nil {
+				revisions = target.Range
+			}
+			if revisions == "" {
+				tag, err := gitOutput(ctx, "describe", "--tags", "--abbrev=0")
+				if err != nil {
+					return withExitCode(exitIntegration, errors.New("no tag to start from; pass --range <from>..<to>"))
+				}
+				revisions = tag + "..HEAD"
+			}
+
+			v, err := validator.New(cfg)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("creating validator: %w", err))
+			}
+			notes, err := audit.Notes(ctx, v.Parser(), audit.Options{Range: revisions})
+			if err != nil {
+				return withExitCode(exitIntegration, fmt.Errorf("reading commits of %s: %w", revisions, err))
+			}
+
+			link := audit.TicketLinker{JIRAURL: cfg.JIRAURL}
+			if url := os.Getenv(jira.EnvURL); url != "" {
+				link.JIRAURL = url
+			}
+			switch {
+			case target != nil:
+				link.IssueURL = target.IssueURL
+			case cfg.GitHubRepo != "":
+				link.IssueURL = "https://github.com/" + cfg.GitHubRepo + "/issues"
+			}
+			markdown := notes.Markdown(link)
+			if releaseNotesJIRA {
+				markdown = jiraReleaseNotes(ctx, cfg, notes, link)
+			}
+
+			if !releaseNotesPR {
+				fmt.Print(markdown)
+				return nil
+			}
+			created, err := prcomment.New(*target).Upsert(ctx, releaseNotesMarker, markdown)
+			if err != nil {
+				return withExitCode(exitIntegration, fmt.Errorf("commenting on %s #%d: %w", target.Project, target.Number, err))
+			}
+			action := "Updated"
+			if created {
+				action = "Posted"
+			}
+			fmt.Printf("📝 %s release notes on %s #%d (%d change(s))\n", action, target.Project, target.Number, len(notes.Notes))
+			return nil
+		},
+	}
+}
+
+// jiraReleaseNotes renders the notes keyed by JIRA ticket, resolving the
+// ticket summaries when the JIRA API is configured
+func jiraReleaseNotes(ctx context.Context, cfg *config.Config, notes *audit.ReleaseNotes, link audit.TicketLinker) string {
+	release := notes.ByJIRATicket()
+	if len(release.Tickets) > 0 {
+		client, err := newJIRAClient(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Listing tickets without summaries: %v\n", err)
+		} else {
+			for _, err := range release.Resolve(ctx, client) {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			}
+		}
+	}
+	return release.Markdown(link)
+}
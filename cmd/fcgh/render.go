@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+// palette colors terminal output; with enabled unset text stays plain.
+type palette struct {
+	enabled bool
+}
+
+// newPalette returns the palette for the --color mode: auto colors terminals
+// unless NO_COLOR is set or TERM is dumb.
+func newPalette(mode string, file *os.File) (palette, error) {
+	switch mode {
+	case "always":
+		return palette{enabled: true}, nil
+	case "never":
+		return palette{}, nil
+	case "", "auto":
+		_, noColor := os.LookupEnv("NO_COLOR")
+		return palette{enabled: !noColor && os.Getenv("TERM") != "dumb" && isTerminal(file)}, nil
+	default:
+		return palette{}, fmt.Errorf("invalid --color %q (supported: auto, always, never)", mode)
+	}
+}
+
+func (p palette) paint(code, text string) string {
+	if !p.enabled || text == "" {
+		return text
+	}
+	return "\033[" + code + "m" + text + "\033[0m"
+}
+
+func (p palette) bold(text string) string   { return p.paint("1", text) }
+func (p palette) dim(text string) string    { return p.paint("2", text) }
+func (p palette) red(text string) string    { return p.paint("31", text) }
+func (p palette) yellow(text string) string { return p.paint("33", text) }
+func (p palette) cyan(text string) string   { return p.paint("36", text) }
+
+// categories are the validation error fields in the order they are shown,
+// with their headings.
+var categories = []struct {
+	field, title string
+}{
+	{"message", "Message"},
+	{"format", "Format"},
+	{"type", "Type"},
+	{"scope", "Scope"},
+	{"subject", "Subject"},
+	{"description", "Description"},
+	{"breaking", "Breaking changes"},
+	{"ticket", "Tickets"},
+	{"closing", "Closing keywords"},
+	{"smart_commit", "Smart commits"},
+	{"signoff", "Sign-off"},
+	{"review", "Review trailers"},
+	{"signature", "Signatures"},
+	{"custom", "Custom rules"},
+	{"check", "Checks"},
+}
+
+// renderWarnings prints validation warnings, which never fail a commit.
+func renderWarnings(w io.Writer, warnings []string, p palette) {
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "%s %s\n", p.yellow("⚠️ "), warning)
+	}
+}
+
+// renderFailure prints a failed validation grouped by rule category, with
+// the offending part of the message underlined and hints on how to fix it.
+func renderFailure(w io.Writer, message string, result *validator.ValidationResult, cfg *config.Config, prov *config.Provenance, p palette) {
+	groups := make(map[string][]error)
+	var other []error
+	for _, err := range result.Errors {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) && categoryTitle(validationErr.Field) != "" {
+			groups[validationErr.Field] = append(groups[validationErr.Field], err)
+		} else {
+			other = append(other, err)
+		}
+	}
+
+	problems := "problem"
+	if len(result.Errors) != 1 {
+		problems += "s"
+	}
+	fmt.Fprintf(w, "%s %s\n", p.red("❌"), p.paint("1;31", fmt.Sprintf("Commit message validation failed (%d %s):", len(result.Errors), problems)))
+
+	var hints []string
+	for _, category := range categories {
+		errs := groups[category.field]
+		if len(errs) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s\n", p.bold(category.title))
+		for _, err := range errs {
+			renderError(w, message, err, prov, p)
+		}
+		if hint := fixHint(category.field, cfg); hint != "" {
+			hints = append(hints, fmt.Sprintf("%s: %s", category.title, hint))
+		}
+	}
+	if len(other) > 0 {
+		fmt.Fprintf(w, "\n%s\n", p.bold("Other"))
+		for _, err := range other {
+			fmt.Fprintf(w, "  %s %v\n", p.red("✖"), err)
+		}
+	}
+
+	if len(hints) > 0 {
+		fmt.Fprintf(w, "\n%s\n", p.cyan("💡 How to fix:"))
+		for _, hint := range hints {
+			fmt.Fprintf(w, "  • %s\n", hint)
+		}
+	}
+}
+
+// renderError prints one error and, when the error has a position, the
+// message line with the offending text underlined.
+func renderError(w io.Writer, message string, err error, prov *config.Provenance, p palette) {
+	var validationErr *validator.ValidationError
+	errors.As(err, &validationErr)
+
+	line, underlined, found := excerpt(message, validationErr)
+	text := validationErr.Message
+	if validationErr.Value != "" && validationErr.Value != underlined {
+		text += fmt.Sprintf(" (got: %q)", validationErr.Value)
+	}
+	fmt.Fprintf(w, "  %s %s\n", p.red("✖"), text)
+
+	if found {
+		width := max(validationErr.EndColumn-validationErr.Column, 1)
+		fmt.Fprintf(w, "      %s %s\n", p.dim("│"), strings.ReplaceAll(line, "\t", " "))
+		fmt.Fprintf(w, "      %s %s%s\n", p.dim("│"), strings.Repeat(" ", validationErr.Column-1), p.red(strings.Repeat("^", width)))
+	}
+	printRuleSources(w, err, prov, p)
+}
+
+// excerpt returns the message line an error points at and the text it
+// underlines, if the error has a position within the message.
+func excerpt(message string, err *validator.ValidationError) (string, string, bool) {
+	lines := strings.Split(message, "\n")
+	if err.Line < 1 || err.Line > len(lines) {
+		return "", "", false
+	}
+	line := []rune(lines[err.Line-1])
+	if err.Column < 1 || err.Column > len(line)+1 || err.EndColumn < err.Column || err.EndColumn > len(line)+1 {
+		return "", "", false
+	}
+	return string(line), string(line[err.Column-1 : err.EndColumn-1]), true
+}
+
+func categoryTitle(field string) string {
+	for _, category := range categories {
+		if category.field == field {
+			return category.title
+		}
+	}
+	return ""
+}
+
+// fixHint explains how to satisfy the rules of a category under cfg.
+func fixHint(field string, cfg *config.Config) string {
+	switch field {
+	case "message":
+		return "write a commit message such as \"feat: add login page\""
+	case "format":
+		return "write the header as type(scope): description, e.g. \"fix(api): handle empty tokens\""
+	case "type":
+		hint := "use one of " + strings.Join(cfg.Types, ", ")
+		if cfg.TypeCase == "lower" {
+			hint += " in lowercase"
+		}
+		return hint
+	case "scope":
+		if len(cfg.Scopes) > 0 {
+			return "put one of " + strings.Join(cfg.Scopes, ", ") + " in parentheses after the type"
+		}
+		return "put a scope in parentheses after the type, e.g. \"feat(api): ...\""
+	case "subject":
+		if len(cfg.TypeRules) > 0 {
+			return "shorten the header to the maximum length for its type and move details to the body"
+		}
+		return fmt.Sprintf("keep the header within %d characters and move details to the body", cfg.MaxSubjectLength)
+	case "description":
+		return "drop redundant words such as \"added\"; 'fcgh validate --fix' can do it for you"
+	case "breaking":
+		return "describe the change in a \"BREAKING CHANGE: ...\" footer, or drop the ! if it is compatible"
+	case "ticket":
+		example := "ABC-123"
+		if len(cfg.JIRAProjects) > 0 {
+			example = cfg.JIRAProjects[0] + "-123"
+		}
+		return fmt.Sprintf("reference an existing, open ticket such as %s", example)
+	case "closing":
+		return "close the issue with a keyword, e.g. \"Fixes #123\""
+	case "smart_commit":
+		return "use smart commit commands such as \"#time 2h\", \"#comment text\" or \"#transition In Review\""
+	case "signoff":
+		return "sign off the commit with 'git commit -s'"
+	case "review":
+		return "add a \"Reviewed-by: Name <email>\" trailer for each reviewer"
+	case "signature":
+		return "sign the commit with a key listed in signed_commits.keys ('git commit -S')"
+	case "custom":
+		return "see custom_rules in your config for the patterns messages must match"
+	case "check":
+		return "see checks in your config for the commands that check messages"
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/scopes"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	routingFormat string
+	routingOutput string
+)
+
+func routingCommand() *Command {
+	fs := flag.NewFlagSet("routing", flag.ExitOnError)
+	fs.StringVar(&routingFormat, "format", "yaml", "routing file format: yaml or json")
+	fs.StringVar(&routingOutput, "o", "", "write the routing file to this file instead of stdout")
+
+	return &Command{
+		Name:        "routing",
+		Description: "🧭 Generate a file routing scopes to reviewers, from scope_reviewers and CODEOWNERS",
+		Examples: []string{
+			"fcgh routing -o .github/reviewers.yaml",
+			"fcgh routing --format json",
+		},
+		ConfigKeys: []string{"scope_reviewers", "scope_sources"},
+		ExitCodes:  []int{exitConfig},
+		Flags:      fs,
+		Run: func(_ context.Context, _ []string) error {
+			if routingFormat != "yaml" && routingFormat != "json" {
+				return fmt.Errorf("unknown routing format %q (expected yaml or json)", routingFormat)
+			}
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+			deriveScopes(cfg, "")
+			routing, err := scopes.Routes(cfg, "")
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("reading scope sources: %w", err))
+			}
+
+			var buf bytes.Buffer
+			if routingFormat == "json" {
+				err = writeJSON(&buf, routing)
+			} else {
+				encoder := yaml.NewEncoder(&buf)
+				encoder.SetIndent(2)
+				err = encoder.Encode(routing)
+			}
+			if err != nil {
+				return fmt.Errorf("encoding routing file: %w", err)
+			}
+			if routingOutput == "" {
+				if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+					return fmt.Errorf("writing routing file: %w", err)
+				}
+				return nil
+			}
+			if err := os.WriteFile(routingOutput, buf.Bytes(), 0o644); err != nil { // #nosec G306 - the routing file is committed or published
+				return fmt.Errorf("writing routing file: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "🧭 %s: %d scope(s) routed\n", routingOutput, len(routing.Scopes))
+			if len(routing.Unrouted) > 0 {
+				fmt.Fprintf(os.Stderr, "⚠️  No reviewers for %s; add them to scope_reviewers or CODEOWNERS\n", strings.Join(routing.Unrouted, ", "))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/dirs"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/update"
+)
+
+var (
+	selfUpdateChannel string
+	selfUpdateCheck   bool
+	selfUpdateForce   bool
+)
+
+// updateCheckTimeout bounds the background update check, so a slow network
+// never holds up a command
+const updateCheckTimeout = 2 * time.Second
+
+// quietCommands never run the update check: hooks run them on every commit
+// and the others own their output
+var quietCommands = map[string]bool{"validate": true, "prepare-msg": true, "precheck": true, "self-update": true}
+
+func selfUpdateCommand() *Command {
+	fs := flag.NewFlagSet("self-update", flag.ExitOnError)
+	fs.StringVar(&selfUpdateChannel, "channel", "", "release channel: stable or prerelease (default: updates.channel, else stable)")
+	fs.BoolVar(&selfUpdateCheck, "check", false, "only report whether a newer release exists")
+	fs.BoolVar(&selfUpdateForce, "force", false, "install the latest release even when it is not newer, or on a development build")
+
+	return &Command{
+		Name:        "self-update",
+		Description: "⬆️  Update fcgh, ccg and ccdo to the latest release, checking its checksum (and signature with updates.public_key)",
+		Examples: []string{
+			"fcgh self-update --check",
+			"fcgh self-update --channel prerelease",
+		},
+		ConfigKeys: []string{"updates.channel", "updates.repo", "updates.public_key"},
+		ExitCodes:  []int{exitConfig, exitIntegration},
+		Flags:      fs,
+		Run: func(ctx context.Context, _ []string) error {
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+			channel := selfUpdateChannel
+			if channel == "" {
+				channel = cfg.Updates.Channel
+			}
+			if channel != "" && channel != update.Stable && channel != update.Prerelease {
+				return fmt.Errorf("unknown channel %q (expected %s or %s)", channel, update.Stable, update.Prerelease)
+			}
+			var key ed25519.PublicKey
+			if cfg.Updates.PublicKey != "" {
+				if key, err = update.ParsePublicKey(cfg.Updates.PublicKey); err != nil {
+					return withExitCode(exitConfig, err)
+				}
+			}
+
+			client := update.New(cfg.Updates.Repo)
+			release, err := client.Latest(ctx, channel)
+			if err != nil {
+				return withExitCode(exitIntegration, fmt.Errorf("finding the latest release: %w", err))
+			}
+			newer := update.Newer(version, release.Version())
+			switch {
+			case selfUpdateCheck && newer:
+				fmt.Printf("⬆️  fcgh %s is available (you have %s); run fcgh self-update\n", release.Version(), version)
+				return nil
+			case selfUpdateCheck:
+				fmt.Printf("✅ fcgh %s is up to date (latest: %s)\n", version, release.Version())
+				return nil
+			case !newer && !selfUpdateForce:
+				if !update.IsRelease(version) {
+					return fmt.Errorf("fcgh %s is a development build; pass --force to replace it with %s", version, release.Version())
+				}
+				fmt.Printf("✅ fcgh %s is up to date (latest: %s)\n", version, release.Version())
+				return nil
+			}
+
+			executable, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("finding executable: %w", err)
+			}
+			if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+				executable = resolved
+			}
+
+			if key == nil {
+				fmt.Println("⚠️  The download is unsigned: without updates.public_key it is only checked against the")
+				fmt.Println("   checksums.txt of the same release, which catches corruption but not a tampered release")
+			}
+			fmt.Printf("⬇️  Downloading fcgh %s for %s/%s...\n", release.Version(), runtime.GOOS, runtime.GOARCH)
+			binaries, err := client.Fetch(ctx, release, runtime.GOOS, runtime.GOARCH, key)
+			if err != nil {
+				return withExitCode(exitIntegration, fmt.Errorf("downloading %s: %w", release.Tag, err))
+			}
+			if err := update.Replace(executable, binaries["fcgh"]); err != nil {
+				return err
+			}
+			verified := "checksum verified, unsigned"
+			if key != nil {
+				verified = "checksum and signature verified"
+			}
+			fmt.Printf("✅ Updated %s from %s to %s (%s)\n", executable, version, release.Version(), verified)
+			updateTools(filepath.Dir(executable), binaries)
+			return nil
+		},
+	}
+}
+
+// updateTools replaces the ccg and ccdo installed in dir, next to fcgh, with
+// the released binaries, and names those it leaves at their old version:
+// copies elsewhere on the PATH and tools the archive lacks.
+func updateTools(dir string, binaries map[string][]byte) {
+	for _, tool := range update.Tools[1:] {
+		path := filepath.Join(dir, update.BinaryName(tool, runtime.GOOS))
+		if _, err := os.Stat(path); err != nil {
+			if other, err := exec.LookPath(tool); err == nil {
+				fmt.Printf("⚠️  %s was not updated: it is not next to fcgh; update it with your package manager or reinstall\n", other)
+			}
+			continue
+		}
+		binary, ok := binaries[tool]
+		if !ok {
+			fmt.Printf("⚠️  %s was not updated: the release archive has no %s\n", path, tool)
+			continue
+		}
+		if err := update.Replace(path, binary); err != nil {
+			fmt.Printf("⚠️  %s was not updated: %v\n", path, err)
+			continue
+		}
+		fmt.Printf("✅ Updated %s\n", path)
+	}
+}
+
+// notifyUpdate tells on stderr when updates.check is on and a newer release
+// exists. Failures are ignored: the check must never get in the way.
+func notifyUpdate(ctx context.Context, args []string) {
+	if len(args) == 0 || quietCommands[args[0]] || writesData(args) {
+		return
+	}
+	cfg, err := config.Load(configFile)
+	if err != nil || !cfg.Updates.Check {
+		return
+	}
+	cacheDir, err := dirs.Cache()
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+	defer cancel()
+	latest, err := update.New(cfg.Updates.Repo).Check(ctx, cfg.Updates.Channel, version, cacheDir)
+	if err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			logger.Debug("update check failed", "error", err)
+		}
+		return
+	}
+	if latest != "" {
+		fmt.Fprintf(os.Stderr, "\n⬆️  fcgh %s is available (you have %s); run fcgh self-update\n", latest, version)
+	}
+}
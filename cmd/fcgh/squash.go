@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/audit"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/prcomment"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+var (
+	squashRange   string
+	squashTitle   string
+	squashNumber  int
+	squashBody    string
+	squashPRTitle bool
+	squashAuthor  string
+	squashPrint   bool
+)
+
+func squashCommand() *Command {
+	fs := flag.NewFlagSet("squash", flag.ExitOnError)
+	fs.StringVar(&squashRange, "range", "", "revision range of the squashed commits (default: the pull request's commits in CI)")
+	fs.StringVar(&squashTitle, "title", "", "pull request title (default: from the CI environment)")
+	fs.IntVar(&squashNumber, "number", 0, "pull request number GitHub appends as \" (#123)\" (default: from the CI environment)")
+	fs.StringVar(&squashBody, "body", audit.SquashBodyCommits, "squash commit body, as the repository's squash setting: commits, pr (the description) or blank")
+	fs.BoolVar(&squashPRTitle, "pr-title", false, "use the pull request title even for a single commit, as the \"Pull request title\" squash setting")
+	fs.StringVar(&squashAuthor, "author", "", "\"Name <email>\" the squash commit is attributed to (default: the author of the oldest commit)")
+	fs.BoolVar(&squashPrint, "print", false, "print the squash commit message")
+
+	return &Command{
+		Name:        "squash",
+		Description: "🔀 Validate the squash commit GitHub would create for the pull request (or the merge queue commit)",
+		Examples: []string{
+			"fcgh squash  # in CI, for the current pull request",
+			"fcgh squash --range origin/main..HEAD --title \"feat: add login\" --number 123 --print",
+		},
+		ConfigKeys: []string{"types", "scopes", "max_subject_length"},
+		ExitCodes:  []int{exitViolation, exitConfig, exitIntegration},
+		Flags:      fs,
+		Run: func(ctx context.Context, _ []string) error {
+			cfg, prov, err := config.LoadWithProvenance(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+			v, err := newValidator(cfg)
+			if err != nil {
+				return err
+			}
+
+			// A merge queue job runs on the commit the queue is about to land
+			if os.Getenv("GITHUB_EVENT_NAME") == "merge_group" && squashRange == "" {
+				message, err := gitOutput(ctx, "log", "-1", "--format=%B")
+				if err != nil {
+					return withExitCode(exitIntegration, fmt.Errorf("reading the merge queue commit: %w", err))
+				}
+				return checkSquash(ctx, v, cfg, prov, &audit.Squash{Message: message, Commits: 1}, "merge queue commit")
+			}
+
+			target, err := prcomment.Detect(nil)
+			if err != nil && !errors.Is(err, prcomment.ErrNotInPullRequest) {
+				return withExitCode(exitIntegration, err)
+			}
+			opts := audit.SquashOptions{Title: squashTitle, Number: squashNumber, Body: squashBody, PRTitle: squashPRTitle, Author: squashAuthor}
+			revisions := squashRange
+			if target != nil {
+				if revisions == "" {
+					revisions = target.Range
+				}
+				if opts.Title == "" {
+					opts.Title = target.Title
+				}
+				if opts.Number == 0 {
+					opts.Number = target.Number
+				}
+				opts.Description = target.Description
+			}
+			if revisions == "" {
+				return errors.New("outside a pull request job, pass --range <from>..<to>, --title and --number")
+			}
+
+			squash, err := audit.SquashMessage(ctx, audit.Options{Range: revisions}, opts)
+			if err != nil {
+				return withExitCode(exitIntegration, fmt.Errorf("building the squash commit of %s: %w", revisions, err))
+			}
+			return checkSquash(ctx, v, cfg, prov, squash, "squash commit")
+		},
+	}
+}
+
+// checkSquash validates a squash commit message and explains failures the
+// " (#123)" suffix causes
+func checkSquash(ctx context.Context, v *validator.Validator, cfg *config.Config, prov *config.Provenance, squash *audit.Squash, label string) error {
+	if squashPrint {
+		fmt.Println(squash.Message)
+		fmt.Println()
+	}
+	colors, _ := newPalette("auto", os.Stderr)
+	subject, _, _ := strings.Cut(squash.Message, "\n")
+	result := v.Validate(ctx, squash.Message)
+	renderWarnings(os.Stderr, result.Warnings, colors)
+	if result.Valid {
+		fmt.Printf("✅ The %s is valid: %s\n", label, subject)
+		return nil
+	}
+
+	renderFailure(os.Stderr, squash.Message, result, cfg, prov, colors)
+	if excess, limit := subjectExcess(result); excess > 0 && squash.Suffix != "" {
+		without := v.Validate(ctx, strings.Replace(squash.Message, subject, squash.Subject, 1))
+		if excess, _ := subjectExcess(without); excess == 0 {
+			fmt.Fprintf(os.Stderr, "💡 GitHub appends %q to the subject, which takes it over the maximum subject length (%d); shorten the pull request title by %d character(s)\n",
+				strings.TrimSpace(squash.Suffix), limit, excess)
+		}
+	}
+	return withExitCode(exitViolation, fmt.Errorf("the %s would not pass validation", label))
+}
+
+// subjectExcess returns by how much the subject exceeds its maximum length,
+// and that length (which type_rules may set per type), or 0 when result has
+// no subject length error
+func subjectExcess(result *validator.ValidationResult) (excess, limit int) {
+	for _, err := range result.ValidationErrors() {
+		var length int
+		if err.Field != "subject" {
+			continue
+		}
+		if _, scanErr := fmt.Sscanf(err.Message, "exceeds maximum length of %d", &limit); scanErr != nil {
+			continue
+		}
+		if _, scanErr := fmt.Sscanf(err.Value, "%d", &length); scanErr == nil {
+			return length - limit, limit
+		}
+	}
+	return 0, 0
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/audit"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+func statsCommand() *Command {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+
+	return &Command{
+		Name:        "stats",
+		Description: "📈 Reports on commit history (scopes, history)",
+		Usage:       "scopes|history [flags]",
+		Examples: []string{
+			"fcgh stats scopes --format csv -o scopes.csv",
+			"fcgh stats history --check  # fail when changelogs would drop commits",
+		},
+		ConfigKeys: []string{"scopes", "scope_sources"},
+		ExitCodes:  []int{exitViolation, exitConfig, exitIntegration},
+		Flags:      fs,
+		Run: func(ctx context.Context, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("usage: fcgh stats scopes [--format json|csv] [-o file] [-n 1000] [--range A..B] [--stale-days 90]\n       fcgh stats history [--format markdown|json] [-o file] [-n 200] [--range A..B] [--check]")
+			}
+			switch args[0] {
+			case "scopes":
+				return statsScopes(ctx, args[1:])
+			case "history":
+				return statsHistory(ctx, args[1:])
+			default:
+				return fmt.Errorf("unknown stats report %q (supported: scopes, history)", args[0])
+			}
+		},
+	}
+}
+
+// statsScopes writes the scope activity and ownership report
+func statsScopes(ctx context.Context, args []string) error {
+	scopeFlags := flag.NewFlagSet("stats scopes", flag.ContinueOnError)
+	format := scopeFlags.String("format", "json", "report format: json or csv")
+	output := scopeFlags.String("o", "", "write the report to this file instead of stdout")
+	limit := scopeFlags.Int("n", audit.DefaultScopeLimit, "number of recent commits to include")
+	revisions := scopeFlags.String("range", "", "revision range to include, e.g. v1.0.0..HEAD (default: HEAD)")
+	staleDays := scopeFlags.Int("stale-days", 90, "flag scopes without commits in this many days as stale")
+	if err := scopeFlags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("unknown report format %q (expected json or csv)", *format)
+	}
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+	}
+	deriveScopes(cfg, "")
+	v, err := validator.New(cfg)
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("creating validator: %w", err))
+	}
+
+	report, err := audit.ScopeActivity(ctx, v.Parser(), audit.Options{Range: *revisions, Limit: *limit}, cfg.Scopes)
+	if err != nil {
+		return withExitCode(exitIntegration, fmt.Errorf("reading commits: %w", err))
+	}
+	report.MarkStale(time.Now().AddDate(0, 0, -*staleDays))
+
+	encode := report.JSON
+	if *format == "csv" {
+		encode = report.CSV
+	}
+	data, err := encode()
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil { // #nosec G306 - reports are published
+		return fmt.Errorf("writing report: %w", err)
+	}
+	stale := 0
+	for _, s := range report.Scopes {
+		if s.Stale {
+			stale++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "📈 %s: %d scope(s), %d without commits in %d days\n", *output, len(report.Scopes), stale, *staleDays)
+	return nil
+}
+
+// statsHistory writes the commits a generated changelog would miss: merges
+// without conventional subjects, other messages that do not parse, and
+// commits dropped by rewrites of the current branch or its upstream
+func statsHistory(ctx context.Context, args []string) error {
+	historyFlags := flag.NewFlagSet("stats history", flag.ContinueOnError)
+	format := historyFlags.String("format", "markdown", "report format: markdown or json")
+	output := historyFlags.String("o", "", "write the report to this file instead of stdout")
+	limit := historyFlags.Int("n", audit.DefaultLimit, "number of recent commits to check")
+	revisions := historyFlags.String("range", "", "revision range to check, e.g. v1.0.0..HEAD (default: HEAD)")
+	check := historyFlags.Bool("check", false, "exit with 2 when commits would be dropped from generated changelogs")
+	if err := historyFlags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "markdown" && *format != "json" {
+		return fmt.Errorf("unknown report format %q (expected markdown or json)", *format)
+	}
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+	}
+	v, err := validator.New(cfg)
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("creating validator: %w", err))
+	}
+
+	// The current branch and its upstream show local and pushed rewrites
+	var refs []string
+	for _, name := range []string{"HEAD", "@{upstream}"} {
+		if ref, err := gitOutput(ctx, "rev-parse", "--symbolic-full-name", name); err == nil && ref != "" && ref != "HEAD" {
+			refs = append(refs, ref)
+		}
+	}
+	report, err := audit.History(ctx, v.Parser(), audit.Options{Range: *revisions, Limit: *limit}, refs)
+	if err != nil {
+		return withExitCode(exitIntegration, fmt.Errorf("reading commits: %w", err))
+	}
+
+	var data []byte
+	if *format == "json" {
+		if data, err = report.JSON(); err != nil {
+			return err
+		}
+	} else {
+		data = []byte(report.Markdown())
+	}
+	if *output == "" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+	} else {
+		if err := os.WriteFile(*output, data, 0o644); err != nil { // #nosec G306 - reports are published
+			return fmt.Errorf("writing report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "📈 %s: %d of %d commit(s) would be dropped from generated changelogs, %d rewrite(s)\n",
+			*output, len(report.Missed), report.Checked, len(report.Rewrites))
+	}
+	if *check && report.Problems() > 0 {
+		return withExitCode(exitViolation, fmt.Errorf("%d commit(s) would be dropped from generated changelogs", report.Problems()))
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+var versionJSON bool
+
+// buildInfo is the provenance of the running binary, for auditing which
+// build enforces policy on a machine.
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+	// Executable and SHA256 identify the binary file itself.
+	Executable string      `json:"executable,omitempty"`
+	SHA256     string      `json:"sha256,omitempty"`
+	Module     *moduleInfo `json:"module,omitempty"`
+	VCS        *vcsInfo    `json:"vcs,omitempty"`
+	// Settings are the build settings other than VCS ones, such as
+	// -ldflags, -trimpath, CGO_ENABLED and GOARCH.
+	Settings     map[string]string `json:"settings,omitempty"`
+	Dependencies []moduleInfo      `json:"dependencies"`
+}
+
+// moduleInfo is a module compiled into the binary.
+type moduleInfo struct {
+	Path    string      `json:"path"`
+	Version string      `json:"version"`
+	Sum     string      `json:"sum,omitempty"`
+	Replace *moduleInfo `json:"replace,omitempty"`
+}
+
+// vcsInfo is the source revision the binary was built from, when the Go
+// toolchain recorded it.
+type vcsInfo struct {
+	System   string `json:"system"`
+	Revision string `json:"revision,omitempty"`
+	Time     string `json:"time,omitempty"`
+	Modified bool   `json:"modified"`
+}
+
+func versionCommand() *Command {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	fs.BoolVar(&versionJSON, "json", false, "print build metadata (modules, VCS revision, build flags, binary checksum) as JSON")
+
+	return &Command{
+		Name:        "version",
+		Description: "🏷️  Show the fcgh version and build provenance",
+		Examples: []string{
+			"fcgh version",
+			"fcgh version --json",
+		},
+		Flags: fs,
+		Run: func(_ context.Context, _ []string) error {
+			info := readBuildInfo()
+			if versionJSON {
+				return writeJSON(os.Stdout, info)
+			}
+			fmt.Printf("fcgh %s (commit %s, built %s, %s %s)\n", info.Version, info.Commit, info.BuildTime, info.GoVersion, info.Platform)
+			return nil
+		},
+	}
+}
+
+// readBuildInfo collects the version variables set at link time and the
+// metadata the Go toolchain embeds in every binary.
+func readBuildInfo() *buildInfo {
+	info := &buildInfo{
+		Version:      version,
+		Commit:       commit,
+		BuildTime:    buildTime,
+		GoVersion:    runtime.Version(),
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		Dependencies: []moduleInfo{},
+	}
+	if path, err := os.Executable(); err == nil {
+		info.Executable = path
+		info.SHA256 = fileSHA256(path)
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = bi.GoVersion
+	info.Module = newModuleInfo(&bi.Main)
+	for _, dep := range bi.Deps {
+		info.Dependencies = append(info.Dependencies, *newModuleInfo(dep))
+	}
+	for _, setting := range bi.Settings {
+		key, ok := strings.CutPrefix(setting.Key, "vcs.")
+		if !ok {
+			if info.Settings == nil {
+				info.Settings = make(map[string]string)
+			}
+			info.Settings[setting.Key] = setting.Value
+			continue
+		}
+		if info.VCS == nil {
+			info.VCS = &vcsInfo{}
+		}
+		switch key {
+		case "revision":
+			info.VCS.Revision = setting.Value
+		case "time":
+			info.VCS.Time = setting.Value
+		case "modified":
+			info.VCS.Modified = setting.Value == "true"
+		}
+	}
+	if info.VCS != nil {
+		for _, setting := range bi.Settings {
+			if setting.Key == "vcs" {
+				info.VCS.System = setting.Value
+			}
+		}
+		// Builds without -X main.commit still know their revision
+		if info.Commit == "unknown" && info.VCS.Revision != "" {
+			info.Commit = info.VCS.Revision
+		}
+	}
+	return info
+}
+
+func newModuleInfo(m *debug.Module) *moduleInfo {
+	info := &moduleInfo{Path: m.Path, Version: m.Version, Sum: m.Sum}
+	if m.Replace != nil {
+		info.Replace = newModuleInfo(m.Replace)
+	}
+	return info
+}
+
+// fileSHA256 returns the hex SHA-256 of a file, or "" when it cannot be read.
+func fileSHA256(path string) string {
+	f, err := os.Open(path) // #nosec G304 - the running executable
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("writing JSON: %w", err)
+	}
+	return nil
+}
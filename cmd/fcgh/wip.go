@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+const (
+	// wipPrefix starts the subject of the commits fcgh wip creates
+	wipPrefix = "WIP: "
+	// wipIgnorePattern is the ignore_patterns entry that lets WIP commits
+	// through the commit-msg hook
+	wipIgnorePattern = "^WIP: "
+)
+
+var (
+	wipStaged     bool
+	unwipMessage  string
+	unwipForce    bool
+	unwipNoVerify bool
+)
+
+func wipCommand() *Command {
+	fs := flag.NewFlagSet("wip", flag.ExitOnError)
+	fs.BoolVar(&wipStaged, "staged", false, "commit only the staged changes (default: all changes, including untracked files)")
+
+	return &Command{
+		Name:        "wip",
+		Description: "🚧 Save work in progress as a \"WIP:\" commit, squashed later by fcgh unwip",
+		Usage:       "[flags] [description]",
+		Examples: []string{
+			"fcgh wip  # all changes, including untracked files",
+			"fcgh wip --staged parser refactor  # only the staged changes, with a description",
+		},
+		ConfigKeys: []string{"ignore_patterns"},
+		ExitCodes:  []int{exitConfig, exitIntegration},
+		Flags:      fs,
+		Run: func(ctx context.Context, args []string) error {
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+			description := strings.Join(args, " ")
+			if description == "" {
+				description = "work in progress"
+			}
+			message := wipPrefix + description
+
+			// The hook runs as for any commit, so WIP commits must be allowed
+			v, err := newValidator(cfg)
+			if err != nil {
+				return err
+			}
+			if result := v.Validate(ctx, message); !result.Valid {
+				return withExitCode(exitConfig, fmt.Errorf("WIP commits are not allowed: add %q to ignore_patterns", wipIgnorePattern))
+			}
+
+			if !wipStaged {
+				if _, err := gitOutput(ctx, "add", "--all"); err != nil {
+					return withExitCode(exitIntegration, fmt.Errorf("staging changes: %w", err))
+				}
+			}
+			if err := runGit(ctx, "commit", "-q", "-m", message); err != nil {
+				return withExitCode(exitIntegration, fmt.Errorf("committing: %w", err))
+			}
+			fmt.Printf("🚧 Committed %q; run fcgh unwip to squash the WIP commits into a conventional one\n", message)
+			return nil
+		},
+	}
+}
+
+func unwipCommand() *Command {
+	fs := flag.NewFlagSet("unwip", flag.ExitOnError)
+	fs.StringVar(&unwipMessage, "m", "", "message of the squashed commit (default: generated, then opened in the editor)")
+	fs.BoolVar(&unwipForce, "force", false, "squash WIP commits even if they were already pushed")
+	fs.BoolVar(&unwipNoVerify, "no-verify", false, "skip the commit-msg hook's second validation of the message")
+
+	return &Command{
+		Name:        "unwip",
+		Description: "🧹 Squash the WIP commits on top of HEAD into one validated conventional commit",
+		Examples: []string{
+			"fcgh unwip  # generated message, opened in the editor",
+			"fcgh unwip -m \"feat(api): add login endpoint\"",
+		},
+		ConfigKeys: []string{"ignore_patterns", "types", "scopes"},
+		ExitCodes:  []int{exitViolation, exitConfig, exitIntegration},
+		Flags:      fs,
+		Run: func(ctx context.Context, _ []string) error {
+			cfg, prov, err := config.LoadWithProvenance(configFile)
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("loading config: %w", err))
+			}
+			head, base, count, err := wipChain(ctx)
+			if err != nil {
+				return withExitCode(exitIntegration, err)
+			}
+			if count == 0 {
+				return errors.New("HEAD is not a WIP commit: nothing to squash")
+			}
+			if base == "" {
+				return withExitCode(exitIntegration, errors.New("every commit down to the root is a WIP commit; fcgh unwip needs a commit to squash onto"))
+			}
+			if !unwipForce {
+				if upstream, err := gitOutput(ctx, "rev-parse", "--abbrev-ref", "@{upstream}"); err == nil {
+					unpushed, err := gitOutput(ctx, "rev-list", "--count", base+".."+head, "^"+upstream)
+					if err == nil && unpushed != strconv.Itoa(count) {
+						return fmt.Errorf("WIP commits were already pushed to %s; pass --force to rewrite them anyway", upstream)
+					}
+				}
+			}
+
+			v, err := newValidator(cfg)
+			if err != nil {
+				return err
+			}
+			if unwipMessage != "" {
+				if result := v.Validate(ctx, unwipMessage); !result.Valid || strings.HasPrefix(unwipMessage, wipPrefix) {
+					colors, _ := newPalette("auto", os.Stderr)
+					renderFailure(os.Stderr, unwipMessage, result, cfg, prov, colors)
+					return withExitCode(exitViolation, errors.New("the squashed commit's message is invalid; the WIP commits are unchanged"))
+				}
+			}
+
+			if _, err := gitOutput(ctx, "reset", "--soft", base); err != nil {
+				return withExitCode(exitIntegration, fmt.Errorf("squashing WIP commits: %w", err))
+			}
+			// From here on, any failure puts the WIP commits back
+			restore := func(cause error) error {
+				if _, err := gitOutput(context.WithoutCancel(ctx), "reset", "--soft", head); err != nil {
+					return withExitCode(exitIntegration, fmt.Errorf("%w; restoring the WIP commits also failed (git reset --soft %s): %v", cause, head, err))
+				}
+				return withExitCode(exitIntegration, fmt.Errorf("%w; the WIP commits are unchanged", cause))
+			}
+
+			args := []string{"commit", "-q"}
+			if unwipNoVerify {
+				args = append(args, "--no-verify")
+			}
+			if unwipMessage != "" {
+				args = append(args, "-m", unwipMessage)
+			} else {
+				result, err := generateStaged(ctx, cfg, "")
+				if err != nil {
+					return restore(fmt.Errorf("generating commit message: %w", err))
+				}
+				args = append(args, "-e", "-m", result.Message)
+			}
+			if err := runGit(ctx, args...); err != nil {
+				return restore(fmt.Errorf("committing: %w", err))
+			}
+			fmt.Printf("🧹 Squashed %d WIP commit(s) into one commit\n", count)
+			return nil
+		},
+	}
+}
+
+// wipChain returns HEAD, the commit below the WIP commits on top of it ("" if
+// they reach the root) and their number. Merges end the chain.
+func wipChain(ctx context.Context) (head, base string, count int, err error) {
+	out, err := gitOutput(ctx, "log", "-z", "--first-parent", "--format=%H%x00%P%x00%s")
+	if err != nil {
+		return "", "", 0, fmt.Errorf("reading commits: %w", err)
+	}
+	fields := strings.Split(out, "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		hash, parents, subject := fields[i], strings.Fields(fields[i+1]), fields[i+2]
+		if i == 0 {
+			head = hash
+		}
+		if !strings.HasPrefix(subject, wipPrefix) || len(parents) > 1 {
+			return head, hash, count, nil
+		}
+		count++
+	}
+	return head, "", count, nil
+}
+
+// runGit runs git attached to the terminal, so the editor and hooks can
+// interact with the user
+func runGit(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 - fixed git subcommands
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -4,7 +4,4 @@ go 1.25
 
 toolchain go1.25.0
 
-require (
-	github.com/atotto/clipboard v0.1.4
-	gopkg.in/yaml.v3 v3.0.1
-)
+require gopkg.in/yaml.v3 v3.0.1
@@ -1,6 +1,6 @@
 #!/bin/bash
 # fcgh macOS Installation Script
-# This script downloads and installs fcgh, cc, and ccc for macOS
+# This script downloads and installs fcgh, ccg, and ccdo for macOS
 
 set -e  # Exit on any error
 
@@ -0,0 +1,160 @@
+// Package audit checks a repository's commit history against the commit
+// message rules and summarizes how much of it complies.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+// DefaultLimit is the number of recent commits audited
+const DefaultLimit = 200
+
+// Options selects the commits to audit
+type Options struct {
+	// Dir is the repository (empty means the current directory)
+	Dir string
+	// Range is a revision range such as "origin/main..HEAD" (empty audits
+	// the history of HEAD)
+	Range string
+	// Limit bounds the audit to the most recent commits (zero uses
+	// DefaultLimit)
+	Limit int
+}
+
+// Commit is the audit result of one commit
+type Commit struct {
+	Hash    string                       `json:"commit"`
+	Subject string                       `json:"subject"`
+	Valid   bool                         `json:"valid"`
+	Errors  []*validator.ValidationError `json:"errors,omitempty"`
+}
+
+// Report is the outcome of an audit
+type Report struct {
+	// Commits are the audited commits, newest first
+	Commits []Commit `json:"commits"`
+	// Limit is the number of commits the audit was bounded to
+	Limit int `json:"limit"`
+}
+
+// Total returns the number of audited commits
+func (r *Report) Total() int {
+	return len(r.Commits)
+}
+
+// Compliant returns the number of commits with valid messages
+func (r *Report) Compliant() int {
+	n := 0
+	for _, commit := range r.Commits {
+		if commit.Valid {
+			n++
+		}
+	}
+	return n
+}
+
+// Percent returns the share of compliant commits from 0 to 100, rounded
+// down so a single bad commit never shows as 100% (100 without commits)
+func (r *Report) Percent() int {
+	if r.Total() == 0 {
+		return 100
+	}
+	return r.Compliant() * 100 / r.Total()
+}
+
+// Run validates the messages of the selected commits. Merge commits are
+// skipped, as git writes their messages. Ticket lookups the validator is
+// configured with also apply, so callers auditing old history usually pass
+// a validator without them.
+func Run(ctx context.Context, v *validator.Validator, opts Options) (*Report, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	records, err := gitLog(ctx, opts, limit, "%H", "%B")
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, len(records))
+	messages := make([]string, len(records))
+	for i, record := range records {
+		hashes[i], messages[i] = record[0], record[1]
+	}
+
+	report := &Report{Commits: make([]Commit, len(messages)), Limit: limit}
+	for _, result := range v.ValidateBatch(ctx, messages) {
+		subject, _, _ := strings.Cut(strings.TrimSpace(messages[result.Index]), "\n")
+		report.Commits[result.Index] = Commit{
+			Hash:    hashes[result.Index],
+			Subject: subject,
+			Valid:   result.Valid,
+			Errors:  result.ValidationErrors(),
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// gitLog reads the given format fields of up to limit commits selected by
+// opts (every commit when limit is zero), skipping merges
+func gitLog(ctx context.Context, opts Options, limit int, fields ...string) ([][]string, error) {
+	var revisions []string
+	if opts.Range != "" {
+		revisions = []string{opts.Range}
+	}
+	return gitLogRevisions(ctx, opts.Dir, revisions, limit, fields...)
+}
+
+// gitLogRevisions is gitLog for commits selected by several revision
+// arguments, such as "main --not --remotes=origin"
+func gitLogRevisions(ctx context.Context, dir string, revisions []string, limit int, fields ...string) ([][]string, error) {
+	return gitLogArgs(ctx, dir, []string{"--no-merges"}, revisions, limit, fields...)
+}
+
+// gitLogArgs is gitLogRevisions with other git log options instead of
+// --no-merges
+func gitLogArgs(ctx context.Context, dir string, options, revisions []string, limit int, fields ...string) ([][]string, error) {
+	args := append([]string{"log", "-z", "--format=" + strings.Join(fields, "%x00")}, options...)
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-%d", limit))
+	}
+	if len(revisions) > 0 {
+		args = append(append(args, revisions...), "--")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 - fixed git command, revisions are passed before --
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git log: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	return parseLog(out, len(fields)), nil
+}
+
+// parseLog splits `git log -z` output with NUL-separated format fields into
+// one record of n fields per commit
+func parseLog(out []byte, n int) [][]string {
+	var records [][]string
+	values := bytes.Split(out, []byte{0})
+	for i := 0; i+n <= len(values); i += n {
+		record := make([]string, n)
+		for j := range record {
+			record[j] = strings.Trim(string(values[i+j]), "\n")
+		}
+		records = append(records, record)
+	}
+	return records
+}
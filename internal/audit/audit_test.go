@@ -0,0 +1,161 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+// newRepo creates a git repository and returns it with a function running
+// git in it
+func newRepo(t *testing.T) (string, func(args ...string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	git("config", "user.name", "Test")
+	git("config", "user.email", "test@example.com")
+	return dir, git
+}
+
+// commitFile commits a file whose content is the message
+func commitFile(t *testing.T, dir string, git func(args ...string), file, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(message), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	git("add", file)
+	git("commit", "-q", "-m", message)
+}
+
+func TestRun(t *testing.T) {
+	dir, git := newRepo(t)
+	commit := func(file, message string) { commitFile(t, dir, git, file, message) }
+	commit("a.txt", "feat: add a")
+	git("checkout", "-q", "-b", "topic")
+	commit("b.txt", "wip stuff")
+	git("checkout", "-q", "main")
+	commit("c.txt", "fix: handle c\n\nWith a body.")
+	git("merge", "-q", "--no-ff", "-m", "Merge branch 'topic'", "topic")
+
+	v, err := validator.New(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Run(context.Background(), v, Options{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total() != 3 || report.Compliant() != 2 || report.Percent() != 66 {
+		t.Fatalf("report = %d of %d (%d%%), want 2 of 3", report.Compliant(), report.Total(), report.Percent())
+	}
+	for _, c := range report.Commits {
+		if c.Subject == "wip stuff" && (c.Valid || len(c.Errors) == 0) {
+			t.Errorf("wip commit = %+v, want it invalid", c)
+		}
+		if len(c.Hash) != 40 {
+			t.Errorf("commit hash = %q", c.Hash)
+		}
+	}
+
+	report, err = Run(context.Background(), v, Options{Dir: dir, Limit: 1})
+	if err != nil || report.Total() != 1 {
+		t.Errorf("Run() with Limit 1 = %+v, %v", report, err)
+	}
+	report, err = Run(context.Background(), v, Options{Dir: dir, Range: "main..topic"})
+	if err != nil || report.Total() != 0 {
+		t.Errorf("Run() for an empty range = %+v, %v", report, err)
+	}
+	if _, err := Run(context.Background(), v, Options{Dir: dir, Range: "nope"}); err == nil {
+		t.Error("Run() with an unknown revision should fail")
+	}
+}
+
+func TestOutgoing(t *testing.T) {
+	dir, git := newRepo(t)
+	commit := func(file, message string) { commitFile(t, dir, git, file, message) }
+	rev := func(name string) string {
+		t.Helper()
+		cmd := exec.Command("git", "rev-parse", name)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit("a.txt", "wip before the remote")
+	git("update-ref", "refs/remotes/origin/main", "HEAD")
+	pushed := rev("HEAD")
+	commit("b.txt", "feat: add b")
+	commit("c.txt", "quick fix")
+	git("checkout", "-q", "-b", "topic")
+	commit("d.txt", "more stuff")
+
+	v, err := validator.New(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero := strings.Repeat("0", 40)
+	refs, err := ParsePushedRefs(strings.NewReader(
+		"refs/heads/main " + rev("main") + " refs/heads/main " + pushed + "\n" +
+			"refs/heads/topic " + rev("topic") + " refs/heads/topic " + zero + "\n" +
+			"(delete) " + zero + " refs/heads/old " + pushed + "\n"))
+	if err != nil || len(refs) != 3 {
+		t.Fatalf("ParsePushedRefs() = %v, %v, want 3 refs", refs, err)
+	}
+	bypasses, err := Outgoing(context.Background(), v, dir, "origin", refs)
+	if err != nil {
+		t.Fatalf("Outgoing() error = %v", err)
+	}
+	var got []string
+	for _, bypass := range bypasses {
+		got = append(got, bypass.Ref+" "+bypass.Subject)
+		if bypass.Author != "Test <test@example.com>" || len(bypass.Errors) == 0 {
+			t.Errorf("bypass %+v, want the author and errors", bypass)
+		}
+	}
+	// The new topic branch only sends its own commit, not those of main
+	want := []string{"refs/heads/main quick fix", "refs/heads/topic more stuff"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Outgoing() = %q, want %q", got, want)
+	}
+
+	log := filepath.Join(dir, "logs", "bypass.log")
+	for range 2 {
+		if err := RecordBypasses(log, bypasses); err != nil {
+			t.Fatalf("RecordBypasses() error = %v", err)
+		}
+	}
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[0], `"subject":"quick fix"`) {
+		t.Errorf("audit log = %s, want 4 JSON lines", data)
+	}
+
+	if _, err := ParsePushedRefs(strings.NewReader("refs/heads/main abc\n")); err == nil {
+		t.Error("ParsePushedRefs() accepted a malformed line")
+	}
+}
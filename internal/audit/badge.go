@@ -0,0 +1,113 @@
+// Package audit - Compliance badges
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// BadgeLabel is the left-hand text of compliance badges
+const BadgeLabel = "conventional commits"
+
+// Badge is a compliance badge in the shields.io endpoint schema, so the
+// JSON form can be served from CI artifacts as a shields.io endpoint badge
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// Badge summarizes the report, such as "98% of last 200"
+func (r *Report) Badge() Badge {
+	badge := Badge{SchemaVersion: 1, Label: BadgeLabel}
+	if r.Total() == 0 {
+		badge.Message = "no commits"
+		badge.Color = "lightgrey"
+		return badge
+	}
+
+	percent := r.Percent()
+	if r.Total() < r.Limit {
+		badge.Message = fmt.Sprintf("%d%% of %d", percent, r.Total())
+	} else {
+		badge.Message = fmt.Sprintf("%d%% of last %d", percent, r.Total())
+	}
+	switch {
+	case percent >= 95:
+		badge.Color = "brightgreen"
+	case percent >= 80:
+		badge.Color = "yellow"
+	case percent >= 50:
+		badge.Color = "orange"
+	default:
+		badge.Color = "red"
+	}
+	return badge
+}
+
+// JSON encodes the badge for a shields.io endpoint
+func (b Badge) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding badge: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// badgeColors maps badge color names to the hex values shields.io uses
+var badgeColors = map[string]string{
+	"brightgreen": "#4c1",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"lightgrey":   "#9f9f9f",
+}
+
+// SVG renders the badge in the shields.io flat style, so it can be served
+// without a badge service
+func (b Badge) SVG() []byte {
+	labelWidth := textWidth(b.Label) + 10
+	messageWidth := textWidth(b.Message) + 10
+	width := labelWidth + messageWidth
+	label, message := html.EscapeString(b.Label), html.EscapeString(b.Message)
+	color := badgeColors[b.Color]
+	if color == "" {
+		color = badgeColors["lightgrey"]
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+"\n", width, label, message)
+	fmt.Fprintf(&sb, "  <title>%s: %s</title>\n", label, message)
+	sb.WriteString(`  <linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	fmt.Fprintf(&sb, `  <clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", width)
+	fmt.Fprintf(&sb, `  <g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`+"\n",
+		labelWidth, labelWidth, messageWidth, color, width)
+	sb.WriteString(`  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` + "\n")
+	for _, text := range []struct {
+		x     int
+		value string
+	}{{labelWidth / 2, label}, {labelWidth + messageWidth/2, message}} {
+		fmt.Fprintf(&sb, `    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`+"\n", text.x, text.value, text.x, text.value)
+	}
+	sb.WriteString("  </g>\n</svg>\n")
+	return []byte(sb.String())
+}
+
+// textWidth approximates the width in pixels of text in 11px Verdana
+func textWidth(text string) int {
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case r == ' ' || r == '.' || r == ',' || r == 'i' || r == 'l' || r == 'j' || r == 't' || r == 'f':
+			width += 4
+		case r == '%' || r == 'm' || r == 'w' || (r >= 'A' && r <= 'Z'):
+			width += 9.5
+		default:
+			width += 7
+		}
+	}
+	return int(width + 0.5)
+}
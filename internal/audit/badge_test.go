@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func reportOf(valid, invalid, limit int) *Report {
+	report := &Report{Limit: limit}
+	for i := 0; i < valid+invalid; i++ {
+		report.Commits = append(report.Commits, Commit{Valid: i < valid})
+	}
+	return report
+}
+
+func TestReportBadge(t *testing.T) {
+	tests := []struct {
+		report  *Report
+		message string
+		color   string
+	}{
+		{reportOf(196, 4, 200), "98% of last 200", "brightgreen"},
+		{reportOf(199, 1, 200), "99% of last 200", "brightgreen"},
+		{reportOf(9, 1, 200), "90% of 10", "yellow"},
+		{reportOf(1, 3, 200), "25% of 4", "red"},
+		{reportOf(0, 0, 200), "no commits", "lightgrey"},
+	}
+	for _, tt := range tests {
+		badge := tt.report.Badge()
+		if badge.Message != tt.message || badge.Color != tt.color || badge.Label != BadgeLabel {
+			t.Errorf("Badge() = %+v, want %q in %s", badge, tt.message, tt.color)
+		}
+	}
+}
+
+func TestBadgeFormats(t *testing.T) {
+	badge := Badge{SchemaVersion: 1, Label: BadgeLabel, Message: "98% of <last> 200", Color: "brightgreen"}
+
+	data, err := badge.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["schemaVersion"] != 1.0 || decoded["message"] != badge.Message {
+		t.Errorf("JSON() = %s", data)
+	}
+
+	svg := badge.SVG()
+	decoder := xml.NewDecoder(strings.NewReader(string(svg)))
+	for {
+		if _, err := decoder.Token(); err != nil {
+			if err.Error() != "EOF" {
+				t.Fatalf("SVG() is not well-formed: %v\n%s", err, svg)
+			}
+			break
+		}
+	}
+	if !strings.Contains(string(svg), "98% of &lt;last&gt; 200") || !strings.Contains(string(svg), `fill="#4c1"`) {
+		t.Errorf("SVG() =\n%s", svg)
+	}
+}
@@ -0,0 +1,149 @@
+// Package audit - Breaking change reports for release managers
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// UnscopedHeading groups breaking changes without a scope in reports
+const UnscopedHeading = "General"
+
+// BreakingChange is a commit marked as breaking with "!" or a BREAKING
+// CHANGE footer
+type BreakingChange struct {
+	Hash    string   `json:"commit"`
+	Subject string   `json:"subject"`
+	Type    string   `json:"type"`
+	Scopes  []string `json:"scopes"`
+	// Description is the BREAKING CHANGE footer, or the header description
+	// when only "!" marks the commit
+	Description string `json:"description"`
+	Author      string `json:"author"`
+}
+
+// BreakingReport lists the breaking changes of a revision range, newest
+// first
+type BreakingReport struct {
+	Range   string           `json:"range"`
+	Changes []BreakingChange `json:"changes"`
+}
+
+// Breaking finds the breaking changes among the commits selected by opts,
+// every commit of the range unless opts.Limit is set. Messages that are not
+// conventional commits are skipped.
+func Breaking(ctx context.Context, parser *conventionalcommit.Parser, opts Options) (*BreakingReport, error) {
+	records, err := gitLog(ctx, opts, opts.Limit, "%H", "%an <%ae>", "%B")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BreakingReport{Range: opts.Range, Changes: []BreakingChange{}}
+	for _, record := range records {
+		commit, err := parser.Parse(record[2])
+		if err != nil || !commit.Breaking {
+			continue
+		}
+		description := commit.BreakingDescription
+		if description == "" {
+			description = commit.Description
+		}
+		subject, _, _ := strings.Cut(record[2], "\n")
+		scopes := commit.Scopes
+		if scopes == nil {
+			scopes = []string{}
+		}
+		report.Changes = append(report.Changes, BreakingChange{
+			Hash:        record[0],
+			Subject:     subject,
+			Type:        commit.Type,
+			Scopes:      scopes,
+			Description: description,
+			Author:      record[1],
+		})
+	}
+	return report, nil
+}
+
+// Scopes returns the affected scopes, sorted
+func (r *BreakingReport) Scopes() []string {
+	seen := make(map[string]bool)
+	var scopes []string
+	for _, change := range r.Changes {
+		for _, scope := range change.Scopes {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// Authors returns the authors of breaking changes, sorted
+func (r *BreakingReport) Authors() []string {
+	seen := make(map[string]bool)
+	var authors []string
+	for _, change := range r.Changes {
+		if !seen[change.Author] {
+			seen[change.Author] = true
+			authors = append(authors, change.Author)
+		}
+	}
+	sort.Strings(authors)
+	return authors
+}
+
+// Markdown renders the report as a migration guide section: a summary,
+// then one heading per affected scope (UnscopedHeading last) listing its
+// changes. A change with several scopes is listed under each.
+func (r *BreakingReport) Markdown(title string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	if len(r.Changes) == 0 {
+		sb.WriteString("No breaking changes.\n")
+		return sb.String()
+	}
+
+	scopes := r.Scopes()
+	fmt.Fprintf(&sb, "%d breaking change(s)", len(r.Changes))
+	if len(scopes) > 0 {
+		fmt.Fprintf(&sb, " affecting %s", strings.Join(scopes, ", "))
+	}
+	fmt.Fprintf(&sb, ", by %s.\n", strings.Join(r.Authors(), ", "))
+
+	byScope := make(map[string][]BreakingChange)
+	for _, change := range r.Changes {
+		if len(change.Scopes) == 0 {
+			byScope[UnscopedHeading] = append(byScope[UnscopedHeading], change)
+		}
+		for _, scope := range change.Scopes {
+			byScope[scope] = append(byScope[scope], change)
+		}
+	}
+	if len(byScope[UnscopedHeading]) > 0 {
+		scopes = append(scopes, UnscopedHeading)
+	}
+	for _, scope := range scopes {
+		fmt.Fprintf(&sb, "\n## %s\n", scope)
+		for _, change := range byScope[scope] {
+			fmt.Fprintf(&sb, "\n### %s\n\n", change.Subject)
+			fmt.Fprintf(&sb, "%s by %s\n\n", shortHash(change.Hash), change.Author)
+			fmt.Fprintf(&sb, "%s\n", change.Description)
+		}
+	}
+	return sb.String()
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+func TestBreaking(t *testing.T) {
+	dir, git := newRepo(t)
+	commitFile(t, dir, git, "a.txt", "feat: add a")
+	git("tag", "v1.2.0")
+	commitFile(t, dir, git, "b.txt", "feat(api)!: drop v1 endpoints")
+	commitFile(t, dir, git, "c.txt", "fix(auth): rotate keys\n\nBREAKING CHANGE: sessions created before the upgrade are invalid")
+	commitFile(t, dir, git, "d.txt", "docs: explain upgrade")
+	commitFile(t, dir, git, "e.txt", "not conventional\n\nBREAKING CHANGE: ignored")
+
+	report, err := Breaking(context.Background(), conventionalcommit.DefaultParser(), Options{Dir: dir, Range: "v1.2.0..HEAD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, change := range report.Changes {
+		got = append(got, change.Subject+" | "+change.Description+" | "+change.Author)
+	}
+	want := []string{
+		"fix(auth): rotate keys | sessions created before the upgrade are invalid | Test <test@example.com>",
+		"feat(api)!: drop v1 endpoints | drop v1 endpoints | Test <test@example.com>",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Breaking() = %q, want %q", got, want)
+	}
+	if scopes := report.Scopes(); !reflect.DeepEqual(scopes, []string{"api", "auth"}) {
+		t.Errorf("Scopes() = %q", scopes)
+	}
+}
+
+func TestBreakingMarkdown(t *testing.T) {
+	report := &BreakingReport{Changes: []BreakingChange{
+		{Hash: "0123456789", Subject: "feat(api,web)!: rename users", Scopes: []string{"api", "web"}, Description: "Use /members.", Author: "Jane <jane@example.com>"},
+		{Hash: "abcdef0123", Subject: "feat!: require Go 1.25", Scopes: []string{}, Description: "require Go 1.25", Author: "Bob <bob@example.com>"},
+	}}
+	got := report.Markdown("Breaking changes since v1.2.0")
+	for _, want := range []string{
+		"# Breaking changes since v1.2.0\n\n2 breaking change(s) affecting api, web, by Bob <bob@example.com>, Jane <jane@example.com>.\n",
+		"\n## api\n\n### feat(api,web)!: rename users\n\n0123456 by Jane <jane@example.com>\n\nUse /members.\n",
+		"\n## web\n\n### feat(api,web)!: rename users\n",
+		"\n## General\n\n### feat!: require Go 1.25\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Markdown() is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "## General") < strings.Index(got, "## web") {
+		t.Errorf("unscoped changes should come last:\n%s", got)
+	}
+
+	empty := (&BreakingReport{}).Markdown("Breaking changes since v1.2.0")
+	if !strings.Contains(empty, "No breaking changes.") {
+		t.Errorf("Markdown() without changes = %q", empty)
+	}
+}
@@ -0,0 +1,203 @@
+// Package audit - History problems that make generated changelogs miss commits
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// Reasons a generated changelog misses a commit
+const (
+	// ReasonMerge is a merge commit whose subject is not a conventional
+	// commit, such as "Merge branch 'main'"
+	ReasonMerge = "merge"
+	// ReasonNotConventional is a commit whose message does not parse
+	ReasonNotConventional = "not-conventional"
+	// ReasonRewritten is a commit a history rewrite removed from the ref
+	ReasonRewritten = "rewritten"
+)
+
+// DefaultReflogLimit is the number of recent reflog entries checked for
+// rewrites
+const DefaultReflogLimit = 100
+
+// rewriteActions are reflog messages of updates that may rewrite history;
+// their old and new values are checked for ancestry to confirm it
+var rewriteActions = []string{"forced-update", "reset:", "rebase", "filter-branch"}
+
+// MissedCommit is a commit a changelog generated from the history misses
+type MissedCommit struct {
+	Hash    string `json:"commit"`
+	Subject string `json:"subject"`
+	Reason  string `json:"reason"`
+}
+
+// Rewrite is a reflog entry that replaced history instead of extending it
+type Rewrite struct {
+	Ref    string `json:"ref"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+	Action string `json:"action"`
+	// Lost are the commits of Old that the ref no longer contains, not
+	// counting commits re-applied with the same changes, as by a rebase
+	Lost []MissedCommit `json:"lost"`
+}
+
+// HistoryReport lists the commits a changelog generated from a range would
+// miss, and the rewrites of the checked refs
+type HistoryReport struct {
+	Range string `json:"range,omitempty"`
+	Limit int    `json:"limit"`
+	// Checked is the number of commits checked, merges included
+	Checked  int            `json:"checked"`
+	Missed   []MissedCommit `json:"missed"`
+	Rewrites []Rewrite      `json:"rewrites"`
+}
+
+// Problems returns the number of missed and lost commits
+func (r *HistoryReport) Problems() int {
+	n := len(r.Missed)
+	for _, rewrite := range r.Rewrites {
+		n += len(rewrite.Lost)
+	}
+	return n
+}
+
+// History checks the commits selected by opts (up to DefaultLimit unless
+// opts.Limit is set) for merges without conventional subjects and messages
+// that do not parse, and the recent reflog of refs for rewrites that
+// dropped commits. Refs without a reflog are skipped.
+func History(ctx context.Context, parser *conventionalcommit.Parser, opts Options, refs []string) (*HistoryReport, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	var revisions []string
+	if opts.Range != "" {
+		revisions = []string{opts.Range}
+	}
+	records, err := gitLogArgs(ctx, opts.Dir, nil, revisions, limit, "%H", "%P", "%B")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &HistoryReport{Range: opts.Range, Limit: limit, Checked: len(records), Missed: []MissedCommit{}, Rewrites: []Rewrite{}}
+	for _, record := range records {
+		if _, err := parser.Parse(record[2]); err == nil {
+			continue
+		}
+		reason := ReasonNotConventional
+		if len(strings.Fields(record[1])) > 1 {
+			reason = ReasonMerge
+		}
+		report.Missed = append(report.Missed, MissedCommit{Hash: record[0], Subject: subjectOf(record[2]), Reason: reason})
+	}
+
+	for _, ref := range refs {
+		report.Rewrites = append(report.Rewrites, reflogRewrites(ctx, opts.Dir, ref)...)
+	}
+	return report, nil
+}
+
+// reflogRewrites returns the rewrites among the recent reflog entries of ref
+func reflogRewrites(ctx context.Context, dir, ref string) []Rewrite {
+	entries, err := gitLogArgs(ctx, dir, []string{"--walk-reflogs"}, []string{ref}, DefaultReflogLimit, "%H", "%gs")
+	if err != nil {
+		// A ref without a reflog has no rewrites to report
+		return nil
+	}
+	var rewrites []Rewrite
+	// Entries are newest first: each one's old value is the next one's hash
+	for i := 0; i+1 < len(entries); i++ {
+		newHash, action, oldHash := entries[i][0], entries[i][1], entries[i+1][0]
+		if oldHash == newHash || !isRewriteAction(action) || isAncestor(ctx, dir, oldHash, newHash) {
+			continue
+		}
+		lost, err := gitLogArgs(ctx, dir, []string{"--no-merges", "--right-only", "--cherry-pick"}, []string{ref + "..." + oldHash}, 0, "%H", "%s")
+		if err != nil {
+			// Commits older than the reflog may have been pruned
+			continue
+		}
+		rewrite := Rewrite{Ref: ref, Old: oldHash, New: newHash, Action: action, Lost: []MissedCommit{}}
+		for _, commit := range lost {
+			rewrite.Lost = append(rewrite.Lost, MissedCommit{Hash: commit[0], Subject: commit[1], Reason: ReasonRewritten})
+		}
+		rewrites = append(rewrites, rewrite)
+	}
+	return rewrites
+}
+
+// isRewriteAction reports whether a reflog message names an update that may
+// rewrite history
+func isRewriteAction(action string) bool {
+	for _, indicator := range rewriteActions {
+		if strings.Contains(action, indicator) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAncestor reports whether ancestor is reachable from commit
+func isAncestor(ctx context.Context, dir, ancestor, commit string) bool {
+	cmd := exec.CommandContext(ctx, "git", "merge-base", "--is-ancestor", ancestor, commit) // #nosec G204 - fixed git command with commit ids from the reflog
+	cmd.Dir = dir
+	return cmd.Run() == nil
+}
+
+// subjectOf returns the first line of message
+func subjectOf(message string) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return subject
+}
+
+// JSON encodes the report
+func (r *HistoryReport) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding history report: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Markdown renders the report for people: the missed commits, then the
+// rewrites with the commits they lost
+func (r *HistoryReport) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Changelog history check\n\n")
+	fmt.Fprintf(&sb, "%d commit(s) checked", r.Checked)
+	if r.Range != "" {
+		fmt.Fprintf(&sb, " in %s", r.Range)
+	}
+	sb.WriteString(".\n")
+	if r.Problems() == 0 && len(r.Rewrites) == 0 {
+		sb.WriteString("\nNo commits would be dropped from generated changelogs.\n")
+		return sb.String()
+	}
+
+	if len(r.Missed) > 0 {
+		sb.WriteString("\n## Dropped from generated changelogs\n\n")
+		for _, commit := range r.Missed {
+			label := "not a conventional commit"
+			if commit.Reason == ReasonMerge {
+				label = "merge without a conventional subject"
+			}
+			fmt.Fprintf(&sb, "- %s %s (%s)\n", shortHash(commit.Hash), commit.Subject, label)
+		}
+	}
+	for _, rewrite := range r.Rewrites {
+		fmt.Fprintf(&sb, "\n## %s rewritten: %s → %s (%s)\n\n", rewrite.Ref, shortHash(rewrite.Old), shortHash(rewrite.New), rewrite.Action)
+		if len(rewrite.Lost) == 0 {
+			sb.WriteString("No changes were lost: the rewritten commits were re-applied.\n")
+		}
+		for _, commit := range rewrite.Lost {
+			fmt.Fprintf(&sb, "- %s %s\n", shortHash(commit.Hash), commit.Subject)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+func TestHistory(t *testing.T) {
+	dir, git := newRepo(t)
+	commitFile(t, dir, git, "a.txt", "feat: add a")
+	git("checkout", "-q", "-b", "topic")
+	commitFile(t, dir, git, "b.txt", "fix: handle b")
+	git("checkout", "-q", "main")
+	commitFile(t, dir, git, "c.txt", "updated c")
+	git("merge", "-q", "--no-ff", "-m", "Merge branch 'topic'", "topic")
+	commitFile(t, dir, git, "d.txt", "feat: add d")
+	commitFile(t, dir, git, "e.txt", "feat: add e")
+	// Drop "add e" and rewrite "add d"
+	git("reset", "-q", "--hard", "HEAD~2")
+	commitFile(t, dir, git, "d.txt", "feat: add d")
+
+	report, err := History(context.Background(), conventionalcommit.DefaultParser(), Options{Dir: dir}, []string{"refs/heads/main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Checked != 5 || len(report.Missed) != 2 {
+		t.Fatalf("History() checked %d, missed %+v", report.Checked, report.Missed)
+	}
+	if report.Missed[0].Reason != ReasonMerge || report.Missed[1].Reason != ReasonNotConventional {
+		t.Errorf("reasons = %s, %s", report.Missed[0].Reason, report.Missed[1].Reason)
+	}
+	if len(report.Rewrites) != 1 || len(report.Rewrites[0].Lost) != 1 || report.Rewrites[0].Lost[0].Subject != "feat: add e" {
+		t.Fatalf("Rewrites = %+v, want the reset losing only \"add e\"", report.Rewrites)
+	}
+	if report.Problems() != 3 {
+		t.Errorf("Problems() = %d, want 3", report.Problems())
+	}
+
+	got := report.Markdown()
+	for _, want := range []string{
+		"5 commit(s) checked.",
+		"Merge branch 'topic' (merge without a conventional subject)",
+		"updated c (not a conventional commit)",
+		"## refs/heads/main rewritten: ",
+		"(reset: moving to HEAD~2)\n\n- ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Markdown() is missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Package audit - Release notes keyed by JIRA ticket
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// TicketNotes are the changes of a release that reference one JIRA ticket
+type TicketNotes struct {
+	Key string
+	// Summary and Status are resolved from JIRA, empty when unknown
+	Summary string
+	Status  string
+	Notes   []Note
+}
+
+// JIRARelease is a release document keyed by JIRA ticket rather than by
+// commit
+type JIRARelease struct {
+	Range   string
+	Tickets []TicketNotes
+	// Untracked are the changes that reference no JIRA ticket
+	Untracked []Note
+	// Other counts the commits left out of the notes, as in ReleaseNotes
+	Other int
+}
+
+// ByJIRATicket groups the notes by the JIRA tickets they reference, in key
+// order; a change referencing several tickets is listed under each.
+func (r *ReleaseNotes) ByJIRATicket() *JIRARelease {
+	release := &JIRARelease{Range: r.Range, Other: r.otherTotal()}
+	index := make(map[string]int)
+	for _, note := range r.Notes {
+		tracked := false
+		for _, ref := range note.Tickets {
+			if ref.Type != "JIRA" {
+				continue
+			}
+			key := strings.ToUpper(ref.ID)
+			i, ok := index[key]
+			if !ok {
+				i = len(release.Tickets)
+				index[key] = i
+				release.Tickets = append(release.Tickets, TicketNotes{Key: key})
+			}
+			if notes := release.Tickets[i].Notes; len(notes) == 0 || notes[len(notes)-1].Hash != note.Hash {
+				release.Tickets[i].Notes = append(notes, note)
+			}
+			tracked = true
+		}
+		if !tracked {
+			release.Untracked = append(release.Untracked, note)
+		}
+	}
+	sort.Slice(release.Tickets, func(i, j int) bool {
+		return ticketKeyLess(release.Tickets[i].Key, release.Tickets[j].Key)
+	})
+	return release
+}
+
+// Resolve fills in the summary and status of each ticket from JIRA. Tickets
+// that cannot be fetched keep an empty summary; their errors are returned.
+func (r *JIRARelease) Resolve(ctx context.Context, lookup validator.TicketLookup) []error {
+	var errs []error
+	for i := range r.Tickets {
+		issue, err := lookup.GetIssue(ctx, r.Tickets[i].Key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		r.Tickets[i].Summary = issue.Summary
+		r.Tickets[i].Status = issue.Status
+	}
+	return errs
+}
+
+// Markdown renders the release as one section per ticket, headed by its
+// linked key and summary and listing its changes, then the changes without
+// a ticket.
+func (r *JIRARelease) Markdown(link TicketLinker) string {
+	var sb strings.Builder
+	sb.WriteString("### 📝 Release notes by JIRA ticket\n")
+	if len(r.Tickets) == 0 && len(r.Untracked) == 0 {
+		sb.WriteString("\nNo user-facing changes.\n")
+	}
+	for _, t := range r.Tickets {
+		heading := t.Key
+		if url := link.URL(conventionalcommit.TicketRef{Type: "JIRA", ID: t.Key}); url != "" {
+			heading = fmt.Sprintf("[%s](%s)", t.Key, url)
+		}
+		if t.Summary != "" {
+			heading += ": " + t.Summary
+		}
+		fmt.Fprintf(&sb, "\n#### %s\n\n", heading)
+		if t.Status != "" {
+			fmt.Fprintf(&sb, "Status: %s\n\n", t.Status)
+		}
+		for _, note := range t.Notes {
+			fmt.Fprintf(&sb, "- %s %s\n", noteMarker(note), noteLine(withoutTicket(note, t.Key), link))
+		}
+	}
+	if len(r.Untracked) > 0 {
+		sb.WriteString("\n#### Without a JIRA ticket\n\n")
+		for _, note := range r.Untracked {
+			fmt.Fprintf(&sb, "- %s %s\n", noteMarker(note), noteLine(note, link))
+		}
+	}
+	if r.Other > 0 {
+		fmt.Fprintf(&sb, "\n<sub>%d other commit(s) are not listed.</sub>\n", r.Other)
+	}
+	return sb.String()
+}
+
+// withoutTicket returns note without its references to the JIRA ticket key,
+// which the section heading already names
+func withoutTicket(note Note, key string) Note {
+	tickets := make([]conventionalcommit.TicketRef, 0, len(note.Tickets))
+	for _, ref := range note.Tickets {
+		if ref.Type == "JIRA" && strings.EqualFold(ref.ID, key) {
+			note.Description = strings.ReplaceAll(note.Description, "("+ref.Raw+")", "")
+			note.Description = strings.ReplaceAll(note.Description, ref.Raw, "")
+			continue
+		}
+		tickets = append(tickets, ref)
+	}
+	note.Tickets = tickets
+	return note
+}
+
+// noteMarker returns the emoji of a note's release-note section
+func noteMarker(note Note) string {
+	heading := sectionHeading(note.Type)
+	if note.Breaking || heading == "" {
+		return "⚠️"
+	}
+	marker, _, _ := strings.Cut(heading, " ")
+	return marker
+}
+
+// ticketKeyLess orders JIRA keys by project, then by issue number
+func ticketKeyLess(a, b string) bool {
+	projectA, numberA, _ := strings.Cut(a, "-")
+	projectB, numberB, _ := strings.Cut(b, "-")
+	if projectA != projectB {
+		return projectA < projectB
+	}
+	n, errA := strconv.Atoi(numberA)
+	m, errB := strconv.Atoi(numberB)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return n < m
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+)
+
+// fakeLookup serves JIRA issues from memory
+type fakeLookup map[string]*jira.Issue
+
+func (f fakeLookup) GetIssue(_ context.Context, key string) (*jira.Issue, error) {
+	if issue, ok := f[key]; ok {
+		return issue, nil
+	}
+	return nil, fmt.Errorf("%w: %s", jira.ErrIssueNotFound, key)
+}
+
+func TestNotesByJIRATicket(t *testing.T) {
+	dir, git := newRepo(t)
+	commitFile(t, dir, git, "a.txt", "chore: init")
+	git("tag", "v1.0.0")
+	commitFile(t, dir, git, "b.txt", "feat(auth): CGC-12 add login")
+	commitFile(t, dir, git, "c.txt", "fix(auth): CGC-9 handle empty password")
+	commitFile(t, dir, git, "d.txt", "feat: add logout (CGC-12)")
+	commitFile(t, dir, git, "e.txt", "fix: handle empty input (#34)")
+	commitFile(t, dir, git, "f.txt", "docs: CGC-12 explain login")
+
+	notes, err := Notes(context.Background(), conventionalcommit.DefaultParser(), Options{Dir: dir, Range: "v1.0.0..HEAD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := notes.ByJIRATicket()
+	if len(release.Tickets) != 2 || release.Tickets[0].Key != "CGC-9" || len(release.Tickets[1].Notes) != 2 {
+		t.Fatalf("ByJIRATicket() = %+v", release.Tickets)
+	}
+	if len(release.Untracked) != 1 || release.Other != 1 {
+		t.Errorf("Untracked, Other = %+v, %d", release.Untracked, release.Other)
+	}
+
+	errs := release.Resolve(context.Background(), fakeLookup{"CGC-12": {Key: "CGC-12", Summary: "Login page", Status: "Done"}})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "CGC-9") {
+		t.Errorf("Resolve() errors = %v, want CGC-9 not found", errs)
+	}
+
+	got := release.Markdown(TicketLinker{JIRAURL: "https://jira.example.com", IssueURL: "https://github.com/o/r/issues"})
+	for _, want := range []string{
+		"### 📝 Release notes by JIRA ticket\n",
+		"\n#### [CGC-9](https://jira.example.com/browse/CGC-9)\n\n- 🐛 **auth:** handle empty password ",
+		"\n#### [CGC-12](https://jira.example.com/browse/CGC-12): Login page\n\nStatus: Done\n\n- ✨ add logout ",
+		"\n- ✨ **auth:** add login ",
+		"\n#### Without a JIRA ticket\n\n- 🐛 handle empty input ([#34](https://github.com/o/r/issues/34)) ",
+		"<sub>1 other commit(s) are not listed.</sub>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Markdown() is missing %q:\n%s", want, got)
+		}
+	}
+}
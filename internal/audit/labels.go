@@ -0,0 +1,66 @@
+// Package audit - Pull request labels from commit types
+package audit
+
+import (
+	"context"
+	"sort"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// Types returns the sorted types of the conventional commits selected by
+// opts, and whether any of them is a breaking change
+func Types(ctx context.Context, parser *conventionalcommit.Parser, opts Options) ([]string, bool, error) {
+	records, err := gitLog(ctx, opts, opts.Limit, "%B")
+	if err != nil {
+		return nil, false, err
+	}
+	seen := make(map[string]bool)
+	var types []string
+	breaking := false
+	for _, record := range records {
+		commit, err := parser.Parse(record[0])
+		if err != nil {
+			continue
+		}
+		breaking = breaking || commit.Breaking
+		if !seen[commit.Type] {
+			seen[commit.Type] = true
+			types = append(types, commit.Type)
+		}
+	}
+	sort.Strings(types)
+	return types, breaking, nil
+}
+
+// Labels maps commit types to labels with mapping, whose config.BreakingLabelKey entry
+// labels breaking changes. It returns the sorted labels that apply and the
+// other labels of mapping, which are stale where present.
+func Labels(mapping map[string]string, types []string, breaking bool) (labels, stale []string) {
+	apply := make(map[string]bool)
+	for _, commitType := range types {
+		if label, ok := mapping[commitType]; ok {
+			apply[label] = true
+		}
+	}
+	if label, ok := mapping[config.BreakingLabelKey]; ok && breaking {
+		apply[label] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, label := range mapping {
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		if apply[label] {
+			labels = append(labels, label)
+		} else {
+			stale = append(stale, label)
+		}
+	}
+	sort.Strings(labels)
+	sort.Strings(stale)
+	return labels, stale
+}
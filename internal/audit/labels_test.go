@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+func TestTypes(t *testing.T) {
+	dir, git := newRepo(t)
+	commitFile(t, dir, git, "a.txt", "chore: init")
+	git("tag", "base")
+	commitFile(t, dir, git, "b.txt", "fix(auth): rotate keys")
+	commitFile(t, dir, git, "c.txt", "feat(api)!: drop v1")
+	commitFile(t, dir, git, "d.txt", "fix: typo")
+	commitFile(t, dir, git, "e.txt", "not conventional")
+
+	types, breaking, err := Types(context.Background(), conventionalcommit.DefaultParser(), Options{Dir: dir, Range: "base..HEAD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(types, []string{"feat", "fix"}) || !breaking {
+		t.Errorf("Types() = %q, %v", types, breaking)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	mapping := config.DefaultTypeLabels()
+	mapping[config.BreakingLabelKey] = "breaking change"
+	mapping["perf"] = "enhancement"
+
+	labels, stale := Labels(mapping, []string{"chore", "fix", "perf"}, false)
+	if !reflect.DeepEqual(labels, []string{"bug", "enhancement"}) {
+		t.Errorf("labels = %q", labels)
+	}
+	if !reflect.DeepEqual(stale, []string{"breaking change", "documentation"}) {
+		t.Errorf("stale = %q", stale)
+	}
+
+	if labels, _ := Labels(mapping, nil, true); !reflect.DeepEqual(labels, []string{"breaking change"}) {
+		t.Errorf("labels of a breaking change = %q", labels)
+	}
+}
@@ -0,0 +1,151 @@
+// Package audit - Re-validation of pushed commits, for commits made with
+// the commit-msg hook skipped
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+// zeroHash is the object name git passes to pre-push for a ref that does
+// not exist on one side, all zeros in SHA-1 and SHA-256 repositories alike
+func zeroHash(hash string) bool {
+	return strings.Trim(hash, "0") == ""
+}
+
+// PushedRef is a ref update git passes to the pre-push hook on stdin
+type PushedRef struct {
+	LocalRef   string
+	LocalHash  string
+	RemoteRef  string
+	RemoteHash string
+}
+
+// ParsePushedRefs reads the "<local ref> <local hash> <remote ref> <remote
+// hash>" lines git writes to the pre-push hook
+func ParsePushedRefs(r io.Reader) ([]PushedRef, error) {
+	var refs []PushedRef
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected pre-push line %q", scanner.Text())
+		}
+		refs = append(refs, PushedRef{LocalRef: fields[0], LocalHash: fields[1], RemoteRef: fields[2], RemoteHash: fields[3]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading pushed refs: %w", err)
+	}
+	return refs, nil
+}
+
+// revisions selects the commits the update sends to remote: those since the
+// remote's old value, or for a new ref those on no branch of the remote.
+// Deletions send nothing.
+func (r PushedRef) revisions(remote string) []string {
+	switch {
+	case zeroHash(r.LocalHash):
+		return nil
+	case zeroHash(r.RemoteHash):
+		return []string{r.LocalHash, "--not", "--remotes=" + remote}
+	default:
+		return []string{r.RemoteHash + ".." + r.LocalHash}
+	}
+}
+
+// Bypass is a pushed commit whose message breaks the rules, so it was made
+// with the commit-msg hook skipped (git commit --no-verify) or not installed
+type Bypass struct {
+	Time    time.Time                    `json:"time"`
+	Remote  string                       `json:"remote"`
+	Ref     string                       `json:"ref"`
+	Hash    string                       `json:"commit"`
+	Author  string                       `json:"author"`
+	Subject string                       `json:"subject"`
+	Errors  []*validator.ValidationError `json:"errors"`
+	// Message is the full commit message, for webhooks
+	Message string `json:"-"`
+}
+
+// Outgoing validates the commits that the ref updates push to remote and
+// returns the invalid ones, newest first per ref. Each ref is bounded to
+// DefaultLimit commits, so pushing a long history to an empty remote stays
+// quick; merges are skipped as in Run.
+func Outgoing(ctx context.Context, v *validator.Validator, dir, remote string, refs []PushedRef) ([]Bypass, error) {
+	now := time.Now()
+	seen := make(map[string]bool)
+	var bypasses []Bypass
+	for _, ref := range refs {
+		revisions := ref.revisions(remote)
+		if revisions == nil {
+			continue
+		}
+		records, err := gitLogRevisions(ctx, dir, revisions, DefaultLimit, "%H", "%an <%ae>", "%B")
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			if seen[record[0]] {
+				continue
+			}
+			seen[record[0]] = true
+			result := v.Validate(ctx, record[2])
+			if result.Valid {
+				continue
+			}
+			subject, _, _ := strings.Cut(strings.TrimSpace(record[2]), "\n")
+			bypasses = append(bypasses, Bypass{
+				Time:    now,
+				Remote:  remote,
+				Ref:     ref.RemoteRef,
+				Hash:    record[0],
+				Author:  record[1],
+				Subject: subject,
+				Errors:  result.ValidationErrors(),
+				Message: record[2],
+			})
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return bypasses, nil
+}
+
+// RecordBypasses appends the bypasses to the JSON lines audit log at path,
+// creating it and its directory as needed
+func RecordBypasses(path string, bypasses []Bypass) error {
+	if len(bypasses) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("creating audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 - the configured audit log
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	for _, bypass := range bypasses {
+		if err := enc.Encode(bypass); err != nil {
+			f.Close()
+			return fmt.Errorf("writing audit log: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	return nil
+}
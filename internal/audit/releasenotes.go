@@ -0,0 +1,198 @@
+// Package audit - Release-note snippets for pull request reviewers
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// releaseSections are the user-facing commit types, in the order their
+// sections appear in release notes
+var releaseSections = []struct {
+	commitType string
+	heading    string
+}{
+	{"feat", "✨ Features"},
+	{"fix", "🐛 Bug fixes"},
+	{"perf", "⚡ Performance"},
+	{"revert", "⏪ Reverts"},
+}
+
+// Note is a user-facing change of a release
+type Note struct {
+	Hash        string
+	Type        string
+	Scopes      []string
+	Description string
+	Breaking    bool
+	// BreakingDescription is the BREAKING CHANGE footer, if any
+	BreakingDescription string
+	Tickets             []conventionalcommit.TicketRef
+}
+
+// ReleaseNotes are the user-facing changes of a revision range, newest first
+type ReleaseNotes struct {
+	Range string
+	Notes []Note
+	// Other counts the remaining conventional commits by type, such as docs
+	// and chore
+	Other map[string]int
+}
+
+// Notes collects the release notes of the commits selected by opts, every
+// commit of the range unless opts.Limit is set. Breaking changes are
+// user-facing whatever their type; messages that are not conventional
+// commits are skipped.
+func Notes(ctx context.Context, parser *conventionalcommit.Parser, opts Options) (*ReleaseNotes, error) {
+	records, err := gitLog(ctx, opts, opts.Limit, "%H", "%B")
+	if err != nil {
+		return nil, err
+	}
+
+	notes := &ReleaseNotes{Range: opts.Range, Other: make(map[string]int)}
+	for _, record := range records {
+		commit, err := parser.Parse(record[1])
+		if err != nil {
+			continue
+		}
+		if !commit.Breaking && sectionHeading(commit.Type) == "" {
+			notes.Other[commit.Type]++
+			continue
+		}
+		notes.Notes = append(notes.Notes, Note{
+			Hash:                record[0],
+			Type:                commit.Type,
+			Scopes:              commit.Scopes,
+			Description:         commit.Description,
+			Breaking:            commit.Breaking,
+			BreakingDescription: commit.BreakingDescription,
+			Tickets:             commit.TicketRefs,
+		})
+	}
+	return notes, nil
+}
+
+// TicketLinker links ticket references in release notes
+type TicketLinker struct {
+	// JIRAURL is the JIRA server JIRA keys link to
+	JIRAURL string
+	// IssueURL is the issue tracker #123 and GH-123 link to, such as
+	// "https://github.com/owner/name/issues"
+	IssueURL string
+}
+
+// URL returns the link of a ticket reference: the ticket pattern's URL
+// template, else a link derived from the tracker of its type, else ""
+func (l TicketLinker) URL(ref conventionalcommit.TicketRef) string {
+	switch {
+	case ref.URL != "":
+		return ref.URL
+	case ref.Type == "JIRA" && l.JIRAURL != "":
+		return strings.TrimRight(l.JIRAURL, "/") + "/browse/" + ref.ID
+	case ref.Type == "GITHUB" && l.IssueURL != "":
+		return strings.TrimRight(l.IssueURL, "/") + "/" + ref.ID
+	}
+	return ""
+}
+
+// Markdown renders the notes as a snippet for a pull request: breaking
+// changes first, then one section per user-facing type, with each change's
+// tickets linked by link.
+func (r *ReleaseNotes) Markdown(link TicketLinker) string {
+	var sb strings.Builder
+	sb.WriteString("### 📝 Release notes\n")
+	if len(r.Notes) == 0 {
+		sb.WriteString("\nNo user-facing changes.\n")
+	}
+
+	var breaking []Note
+	byType := make(map[string][]Note)
+	for _, note := range r.Notes {
+		if note.Breaking {
+			breaking = append(breaking, note)
+		} else {
+			byType[note.Type] = append(byType[note.Type], note)
+		}
+	}
+	writeSection := func(heading string, notes []Note) {
+		if len(notes) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "\n#### %s\n\n", heading)
+		for _, note := range notes {
+			fmt.Fprintf(&sb, "- %s\n", noteLine(note, link))
+		}
+	}
+	writeSection("⚠️ Breaking changes", breaking)
+	for _, section := range releaseSections {
+		writeSection(section.heading, byType[section.commitType])
+	}
+
+	if other := r.otherTotal(); other > 0 {
+		types := make([]string, 0, len(r.Other))
+		for commitType := range r.Other {
+			types = append(types, commitType)
+		}
+		sort.Strings(types)
+		fmt.Fprintf(&sb, "\n<sub>%d other commit(s) (%s) are not listed.</sub>\n", other, strings.Join(types, ", "))
+	}
+	return sb.String()
+}
+
+// otherTotal returns the number of commits left out of the notes
+func (r *ReleaseNotes) otherTotal() int {
+	n := 0
+	for _, count := range r.Other {
+		n += count
+	}
+	return n
+}
+
+// noteLine renders a note as "**scope:** description (links)", with the
+// ticket references moved out of the description into the links
+func noteLine(note Note, link TicketLinker) string {
+	description := note.Description
+	var links []string
+	for _, ref := range note.Tickets {
+		description = strings.ReplaceAll(description, "("+ref.Raw+")", "")
+		description = strings.ReplaceAll(description, ref.Raw, "")
+		label := ref.ID
+		if ref.Type == "GITHUB" {
+			label = "#" + ref.ID
+		}
+		if url := link.URL(ref); url != "" {
+			label = fmt.Sprintf("[%s](%s)", label, url)
+		}
+		links = append(links, label)
+	}
+	description = strings.Trim(strings.Join(strings.Fields(description), " "), " :-,")
+
+	var sb strings.Builder
+	if len(note.Scopes) > 0 {
+		fmt.Fprintf(&sb, "**%s:** ", strings.Join(note.Scopes, ", "))
+	}
+	sb.WriteString(description)
+	if len(links) > 0 {
+		fmt.Fprintf(&sb, " (%s)", strings.Join(links, ", "))
+	}
+	fmt.Fprintf(&sb, " %s", shortHash(note.Hash))
+	if note.BreakingDescription != "" {
+		fmt.Fprintf(&sb, "\n  %s", strings.ReplaceAll(note.BreakingDescription, "\n", "\n  "))
+	}
+	return sb.String()
+}
+
+// sectionHeading returns the release-note heading of a commit type, or ""
+// for types that are not user-facing
+func sectionHeading(commitType string) string {
+	for _, section := range releaseSections {
+		if section.commitType == commitType {
+			return section.heading
+		}
+	}
+	return ""
+}
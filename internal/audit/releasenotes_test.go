@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+func TestNotes(t *testing.T) {
+	dir, git := newRepo(t)
+	commitFile(t, dir, git, "a.txt", "feat: add a")
+	git("tag", "v1.0.0")
+	commitFile(t, dir, git, "b.txt", "feat(auth): CGC-12 add login")
+	commitFile(t, dir, git, "c.txt", "fix: handle empty input (#34)")
+	commitFile(t, dir, git, "d.txt", "refactor(api)!: drop v1 endpoints\n\nBREAKING CHANGE: use /v2 instead")
+	commitFile(t, dir, git, "e.txt", "docs: explain login")
+	commitFile(t, dir, git, "f.txt", "chore: bump deps")
+	commitFile(t, dir, git, "g.txt", "not conventional")
+
+	notes, err := Notes(context.Background(), conventionalcommit.DefaultParser(), Options{Dir: dir, Range: "v1.0.0..HEAD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes.Notes) != 3 {
+		t.Fatalf("Notes() = %+v, want 3 notes", notes.Notes)
+	}
+	if notes.Other["docs"] != 1 || notes.Other["chore"] != 1 {
+		t.Errorf("Other = %v", notes.Other)
+	}
+
+	got := notes.Markdown(TicketLinker{JIRAURL: "https://jira.example.com/", IssueURL: "https://github.com/o/r/issues"})
+	for _, want := range []string{
+		"### 📝 Release notes\n",
+		"\n#### ⚠️ Breaking changes\n\n- **api:** drop v1 endpoints ",
+		"\n  use /v2 instead\n",
+		"\n#### ✨ Features\n\n- **auth:** add login ([CGC-12](https://jira.example.com/browse/CGC-12)) ",
+		"\n#### 🐛 Bug fixes\n\n- handle empty input ([#34](https://github.com/o/r/issues/34)) ",
+		"<sub>2 other commit(s) (chore, docs) are not listed.</sub>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Markdown() is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "Breaking changes") > strings.Index(got, "Features") {
+		t.Errorf("breaking changes should come first:\n%s", got)
+	}
+}
+
+func TestNotesMarkdownWithoutChanges(t *testing.T) {
+	got := (&ReleaseNotes{}).Markdown(TicketLinker{})
+	if !strings.Contains(got, "No user-facing changes.") {
+		t.Errorf("Markdown() = %q", got)
+	}
+}
+
+func TestTicketLinkerURL(t *testing.T) {
+	link := TicketLinker{}
+	tests := []struct {
+		ref  conventionalcommit.TicketRef
+		want string
+	}{
+		{conventionalcommit.TicketRef{Type: "LINEAR", ID: "ENG-1", URL: "https://linear.app/t/ENG-1"}, "https://linear.app/t/ENG-1"},
+		{conventionalcommit.TicketRef{Type: "JIRA", ID: "CGC-1"}, ""},
+		{conventionalcommit.TicketRef{Type: "GITHUB", ID: "7"}, ""},
+	}
+	for _, tt := range tests {
+		if got := link.URL(tt.ref); got != tt.want {
+			t.Errorf("URL(%+v) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
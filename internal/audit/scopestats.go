@@ -0,0 +1,271 @@
+// Package audit - Scope activity and ownership reports
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// DefaultScopeLimit is the number of recent commits a scope report covers
+const DefaultScopeLimit = 1000
+
+// RootDirectory stands for files at the top of the repository in reports
+const RootDirectory = "."
+
+// Count is a number of commits attributed to an author or directory
+type Count struct {
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+}
+
+// ScopeStats is the activity of one scope
+type ScopeStats struct {
+	Scope   string `json:"scope"`
+	Commits int    `json:"commits"`
+	// LastCommit is the author date of the latest commit, nil without
+	// commits
+	LastCommit *time.Time `json:"last_commit,omitempty"`
+	// Stale reports that the scope had no commit since the report's cutoff
+	Stale bool `json:"stale"`
+	// Authors and Directories are sorted by commits, most first. A commit
+	// counts once for each top-level directory it touches.
+	Authors     []Count `json:"authors"`
+	Directories []Count `json:"directories"`
+
+	// cells counts commits per author and directory, for CSV
+	cells map[[2]string]int
+}
+
+// ScopeReport correlates scopes with the authors and directories of their
+// commits, most active scope first
+type ScopeReport struct {
+	Range string `json:"range,omitempty"`
+	Limit int    `json:"limit"`
+	// StaleBefore is the cutoff set by MarkStale
+	StaleBefore *time.Time   `json:"stale_before,omitempty"`
+	Scopes      []ScopeStats `json:"scopes"`
+}
+
+// ScopeActivity reports the scopes of the conventional commits selected by
+// opts (up to DefaultScopeLimit unless opts.Limit is set). Known scopes,
+// such as the configured ones, are listed even without commits.
+func ScopeActivity(ctx context.Context, parser *conventionalcommit.Parser, opts Options, known []string) (*ScopeReport, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultScopeLimit
+	}
+	commits, err := logWithFiles(ctx, opts, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	type tally struct {
+		stats   *ScopeStats
+		authors map[string]int
+		dirs    map[string]int
+	}
+	byScope := make(map[string]*tally)
+	scope := func(name string) *tally {
+		if byScope[name] == nil {
+			byScope[name] = &tally{
+				stats:   &ScopeStats{Scope: name, cells: make(map[[2]string]int)},
+				authors: make(map[string]int),
+				dirs:    make(map[string]int),
+			}
+		}
+		return byScope[name]
+	}
+	for _, name := range known {
+		scope(name)
+	}
+
+	for _, c := range commits {
+		commit, err := parser.Parse(c.message)
+		if err != nil {
+			continue
+		}
+		dirs := topDirs(c.files)
+		for _, name := range commit.Scopes {
+			t := scope(name)
+			t.stats.Commits++
+			if t.stats.LastCommit == nil || c.date.After(*t.stats.LastCommit) {
+				date := c.date
+				t.stats.LastCommit = &date
+			}
+			t.authors[c.author]++
+			for _, dir := range dirs {
+				t.dirs[dir]++
+				t.stats.cells[[2]string{c.author, dir}]++
+			}
+			if len(dirs) == 0 {
+				t.stats.cells[[2]string{c.author, ""}]++
+			}
+		}
+	}
+
+	report := &ScopeReport{Range: opts.Range, Limit: limit, Scopes: []ScopeStats{}}
+	for _, t := range byScope {
+		t.stats.Authors = sortedCounts(t.authors)
+		t.stats.Directories = sortedCounts(t.dirs)
+		report.Scopes = append(report.Scopes, *t.stats)
+	}
+	sort.Slice(report.Scopes, func(i, j int) bool {
+		a, b := report.Scopes[i], report.Scopes[j]
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		return a.Scope < b.Scope
+	})
+	return report, nil
+}
+
+// MarkStale flags the scopes without a commit since cutoff
+func (r *ScopeReport) MarkStale(cutoff time.Time) {
+	r.StaleBefore = &cutoff
+	for i := range r.Scopes {
+		last := r.Scopes[i].LastCommit
+		r.Scopes[i].Stale = last == nil || last.Before(cutoff)
+	}
+}
+
+// JSON encodes the report
+func (r *ScopeReport) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding scope report: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// CSV encodes the report as one row per scope, author and directory with
+// their commit count, the long format BI tools pivot. Scopes without
+// commits get a row with empty author and directory.
+func (r *ScopeReport) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	rows := [][]string{{"scope", "author", "directory", "commits", "scope_last_commit", "scope_stale"}}
+	for _, s := range r.Scopes {
+		last := ""
+		if s.LastCommit != nil {
+			last = s.LastCommit.UTC().Format(time.RFC3339)
+		}
+		stale := strconv.FormatBool(s.Stale)
+		if len(s.cells) == 0 {
+			rows = append(rows, []string{s.Scope, "", "", "0", last, stale})
+		}
+		cells := make([][2]string, 0, len(s.cells))
+		for cell := range s.cells {
+			cells = append(cells, cell)
+		}
+		sort.Slice(cells, func(i, j int) bool {
+			if cells[i][0] != cells[j][0] {
+				return cells[i][0] < cells[j][0]
+			}
+			return cells[i][1] < cells[j][1]
+		})
+		for _, cell := range cells {
+			rows = append(rows, []string{s.Scope, cell[0], cell[1], strconv.Itoa(s.cells[cell]), last, stale})
+		}
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("encoding scope report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// sortedCounts returns counts sorted by commits, most first, then by name
+func sortedCounts(counts map[string]int) []Count {
+	sorted := make([]Count, 0, len(counts))
+	for name, n := range counts {
+		sorted = append(sorted, Count{Name: name, Commits: n})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Commits != sorted[j].Commits {
+			return sorted[i].Commits > sorted[j].Commits
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// topDirs returns the distinct top-level directories of files, with
+// RootDirectory for files at the top
+func topDirs(files []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, file := range files {
+		dir, _, found := strings.Cut(file, "/")
+		if !found {
+			dir = RootDirectory
+		}
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// loggedCommit is a commit with the files it changed
+type loggedCommit struct {
+	author  string
+	date    time.Time
+	message string
+	files   []string
+}
+
+// logWithFiles reads the author, date, message and changed files of up to
+// limit commits selected by opts, skipping merges
+func logWithFiles(ctx context.Context, opts Options, limit int) ([]loggedCommit, error) {
+	// Each commit starts with a record separator; the message ends with a
+	// NUL, followed by the newline-separated file names
+	args := []string{"log", "--no-merges", "--name-only", "--format=%x1e%an <%ae>%x00%aI%x00%B%x00", fmt.Sprintf("-%d", limit)}
+	if opts.Range != "" {
+		args = append(args, opts.Range, "--")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 - fixed git command, the range is passed before --
+	cmd.Dir = opts.Dir
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git log: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	return parseLogWithFiles(out), nil
+}
+
+// parseLogWithFiles parses the output of logWithFiles' git log command
+func parseLogWithFiles(out []byte) []loggedCommit {
+	var commits []loggedCommit
+	for _, record := range bytes.Split(out, []byte{0x1e}) {
+		fields := strings.SplitN(string(record), "\x00", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			continue
+		}
+		commit := loggedCommit{author: fields[0], date: date, message: strings.Trim(fields[2], "\n")}
+		for _, file := range strings.Split(fields[3], "\n") {
+			if file = strings.TrimSpace(file); file != "" {
+				commit.files = append(commit.files, file)
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+func TestScopeActivity(t *testing.T) {
+	dir, git := newRepo(t)
+	commit := func(author, date, message string, files ...string) {
+		t.Helper()
+		for _, file := range files {
+			if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(file)), 0o750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, file), []byte(message), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			git("add", file)
+		}
+		git("commit", "-q", "--author", author, "--date", date, "-m", message)
+	}
+	commit("Jane <jane@example.com>", "2026-01-10T12:00:00Z", "feat(api): add users", "api/users.go", "docs/users.md")
+	commit("Bob <bob@example.com>", "2026-03-01T12:00:00Z", "fix(api): handle empty names", "api/users.go")
+	commit("Jane <jane@example.com>", "2025-06-01T12:00:00Z", "feat(web,api): show users", "web/users.ts", "README.md")
+	commit("Bob <bob@example.com>", "2026-03-02T12:00:00Z", "chore: bump deps", "go.sum")
+
+	parser := conventionalcommit.DefaultParser()
+	parser.ScopeDelimiters = ","
+	report, err := ScopeActivity(context.Background(), parser, Options{Dir: dir}, []string{"api", "billing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report.MarkStale(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var scopes []string
+	for _, s := range report.Scopes {
+		scopes = append(scopes, s.Scope)
+	}
+	if !reflect.DeepEqual(scopes, []string{"api", "web", "billing"}) {
+		t.Fatalf("scopes = %q", scopes)
+	}
+	api, web, billing := report.Scopes[0], report.Scopes[1], report.Scopes[2]
+	if api.Commits != 3 || api.Stale || !api.LastCommit.Equal(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("api = %+v", api)
+	}
+	if want := []Count{{"Jane <jane@example.com>", 2}, {"Bob <bob@example.com>", 1}}; !reflect.DeepEqual(api.Authors, want) {
+		t.Errorf("api authors = %+v", api.Authors)
+	}
+	if want := []Count{{"api", 2}, {".", 1}, {"docs", 1}, {"web", 1}}; !reflect.DeepEqual(api.Directories, want) {
+		t.Errorf("api directories = %+v", api.Directories)
+	}
+	if !web.Stale || !billing.Stale || billing.Commits != 0 || billing.LastCommit != nil {
+		t.Errorf("web = %+v, billing = %+v; want both stale", web, billing)
+	}
+
+	data, err := report.CSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"scope,author,directory,commits,scope_last_commit,scope_stale\n",
+		"api,Bob <bob@example.com>,api,1,2026-03-01T12:00:00Z,false\n",
+		"api,Jane <jane@example.com>,api,1,2026-03-01T12:00:00Z,false\n",
+		"billing,,,0,,true\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CSV() is missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package audit - Squash commit messages as GitHub generates them
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Sources of a squash commit's body, named after GitHub's
+// squash_merge_commit_message repository setting values
+const (
+	// SquashBodyCommits lists the messages of the squashed commits, GitHub's
+	// default
+	SquashBodyCommits = "commits"
+	// SquashBodyPR uses the pull request description
+	SquashBodyPR = "pr"
+	// SquashBodyBlank leaves the body empty, apart from co-authors
+	SquashBodyBlank = "blank"
+)
+
+var coAuthorRegex = regexp.MustCompile(`(?im)^co-authored-by:[ \t]*(.+?)[ \t]*$`)
+
+// SquashOptions describe the pull request whose squash commit is built
+type SquashOptions struct {
+	// Title and Number make the "Title (#Number)" subject
+	Title  string
+	Number int
+	// Description is the pull request body, used with SquashBodyPR
+	Description string
+	// Body is SquashBodyCommits (when empty), SquashBodyPR or SquashBodyBlank
+	Body string
+	// PRTitle uses Title even when the pull request has a single commit,
+	// whose subject GitHub uses by default
+	PRTitle bool
+	// Author is the "Name <email>" the squash commit is attributed to (empty
+	// means the author of the oldest commit); other authors become
+	// co-authors
+	Author string
+}
+
+// Squash is a squash commit message
+type Squash struct {
+	Message string
+	// Subject is the subject without the " (#123)" suffix
+	Subject string
+	// Suffix is what GitHub appends to the subject, such as " (#123)"
+	Suffix    string
+	Commits   int
+	CoAuthors []string
+}
+
+// SquashMessage builds the message of the commit GitHub creates when it
+// squashes the commits selected by opts, as its default squash settings (or
+// those given by squash) would.
+func SquashMessage(ctx context.Context, opts Options, squash SquashOptions) (*Squash, error) {
+	records, err := gitLog(ctx, opts, 0, "%an <%ae>", "%B")
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("no commits to squash")
+	}
+	// Oldest first, as GitHub lists them
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+
+	author := squash.Author
+	if author == "" {
+		author = records[0][0]
+	}
+	seen := map[string]bool{authorKey(author): true}
+	result := &Squash{Commits: len(records)}
+	addCoAuthor := func(name string) {
+		if key := authorKey(name); !seen[key] {
+			seen[key] = true
+			result.CoAuthors = append(result.CoAuthors, name)
+		}
+	}
+
+	messages := make([]string, len(records))
+	for i, record := range records {
+		addCoAuthor(record[0])
+		for _, match := range coAuthorRegex.FindAllStringSubmatch(record[1], -1) {
+			addCoAuthor(match[1])
+		}
+		messages[i] = strings.TrimSpace(coAuthorRegex.ReplaceAllString(record[1], ""))
+	}
+
+	result.Subject = squash.Title
+	var body string
+	switch squash.Body {
+	case "", SquashBodyCommits:
+		if len(messages) == 1 && !squash.PRTitle {
+			var rest string
+			result.Subject, rest, _ = strings.Cut(messages[0], "\n")
+			body = strings.TrimSpace(rest)
+			break
+		}
+		items := make([]string, len(messages))
+		for i, message := range messages {
+			items[i] = "* " + message
+		}
+		body = strings.Join(items, "\n\n")
+	case SquashBodyPR:
+		body = strings.TrimSpace(coAuthorRegex.ReplaceAllString(squash.Description, ""))
+		for _, match := range coAuthorRegex.FindAllStringSubmatch(squash.Description, -1) {
+			addCoAuthor(match[1])
+		}
+	case SquashBodyBlank:
+	default:
+		return nil, fmt.Errorf("unknown squash body %q (expected %s, %s or %s)", squash.Body, SquashBodyCommits, SquashBodyPR, SquashBodyBlank)
+	}
+	if result.Subject == "" {
+		return nil, errors.New("the pull request title is unknown; pass it with --title")
+	}
+	if squash.Number > 0 {
+		result.Suffix = fmt.Sprintf(" (#%d)", squash.Number)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(result.Subject + result.Suffix)
+	if body != "" {
+		sb.WriteString("\n\n" + body)
+	}
+	if len(result.CoAuthors) > 0 {
+		sb.WriteString("\n\n")
+		for i, name := range result.CoAuthors {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString("Co-authored-by: " + name)
+		}
+	}
+	result.Message = sb.String()
+	return result, nil
+}
+
+// authorKey identifies an author by email, or by name without one
+func authorKey(author string) string {
+	if start := strings.LastIndex(author, "<"); start >= 0 {
+		return strings.ToLower(strings.Trim(author[start:], "<> "))
+	}
+	return strings.ToLower(strings.TrimSpace(author))
+}
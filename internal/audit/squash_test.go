@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSquashMessage(t *testing.T) {
+	dir, git := newRepo(t)
+	commitFile(t, dir, git, "a.txt", "chore: init")
+	git("tag", "base")
+	commitFile(t, dir, git, "b.txt", "feat(auth): add login form")
+	opts := Options{Dir: dir, Range: "base..HEAD"}
+
+	// A single commit keeps its own subject
+	squash, err := SquashMessage(context.Background(), opts, SquashOptions{Title: "Login", Number: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if squash.Message != "feat(auth): add login form (#12)" || squash.Suffix != " (#12)" {
+		t.Errorf("single commit squash = %q", squash.Message)
+	}
+
+	git("config", "user.name", "Other")
+	git("config", "user.email", "other@example.com")
+	commitFile(t, dir, git, "c.txt", "fix(auth): handle empty password\n\nCo-authored-by: Pair <pair@example.com>")
+	squash, err = SquashMessage(context.Background(), opts, SquashOptions{Title: "feat(auth): add login", Number: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "feat(auth): add login (#12)\n\n* feat(auth): add login form\n\n* fix(auth): handle empty password\n\n" +
+		"Co-authored-by: Other <other@example.com>\nCo-authored-by: Pair <pair@example.com>"
+	if squash.Message != want {
+		t.Errorf("SquashMessage() =\n%s\nwant\n%s", squash.Message, want)
+	}
+
+	squash, err = SquashMessage(context.Background(), opts, SquashOptions{
+		Title: "feat(auth): add login", Body: SquashBodyPR, Description: "Adds a login form.",
+		Author: "Other <OTHER@example.com>",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "feat(auth): add login\n\nAdds a login form.\n\nCo-authored-by: Test <test@example.com>\nCo-authored-by: Pair <pair@example.com>"
+	if squash.Message != want {
+		t.Errorf("SquashMessage() with the description =\n%s\nwant\n%s", squash.Message, want)
+	}
+
+	if _, err := SquashMessage(context.Background(), opts, SquashOptions{Body: "merge"}); err == nil || !strings.Contains(err.Error(), "unknown squash body") {
+		t.Errorf("SquashMessage() with an unknown body = %v", err)
+	}
+}
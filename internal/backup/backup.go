@@ -0,0 +1,215 @@
+// Package backup exports the fast-cc config directory to a gzipped tar
+// archive and imports it again, to move fcgh to a new machine or to hand a
+// team a starter kit.
+//
+// The archive holds every regular file of the directory (the config and the
+// files it extends, the commit template, external plugins, the policy bundle
+// and the JIRA ticket state) except the encrypted secrets, which belong to
+// one user on one machine.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/secrets"
+)
+
+const maxFileSize = 10 * 1024 * 1024
+
+// Export writes the files in configDir to w as a gzipped tar archive and
+// returns their paths relative to configDir.
+func Export(w io.Writer, configDir string) ([]string, error) {
+	excluded := make(map[string]bool)
+	for _, name := range secrets.StoreFiles() {
+		excluded[name] = true
+	}
+
+	var names []string
+	err := filepath.WalkDir(configDir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(configDir, file)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if entry.IsDir() || excluded[name] || strings.HasSuffix(name, ".tmp") {
+			return nil
+		}
+		// Follow symlinked files, skip anything else that is not a file
+		if info, err := os.Stat(file); err == nil && info.Mode().IsRegular() {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", configDir, err)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("nothing to export in %s", configDir)
+	}
+	sort.Strings(names)
+
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	for _, name := range names {
+		if err := addFile(archive, configDir, name); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("writing archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("writing archive: %w", err)
+	}
+	return names, nil
+}
+
+// addFile writes configDir/name to archive, keeping whether it is executable.
+func addFile(archive *tar.Writer, configDir, name string) error {
+	file := filepath.Join(configDir, filepath.FromSlash(name))
+	data, err := os.ReadFile(file) // #nosec G304 - walked from the config directory
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	mode := int64(0o600)
+	if info.Mode()&0o111 != 0 {
+		mode = 0o700
+	}
+	header := &tar.Header{Name: name, Mode: mode, Size: int64(len(data)), ModTime: info.ModTime(), Typeflag: tar.TypeReg}
+	if err := archive.WriteHeader(header); err != nil {
+		return fmt.Errorf("archiving %s: %w", name, err)
+	}
+	if _, err := archive.Write(data); err != nil {
+		return fmt.Errorf("archiving %s: %w", name, err)
+	}
+	return nil
+}
+
+// Result lists the files of an import by their path relative to the config
+// directory.
+type Result struct {
+	// Written were created or overwritten.
+	Written []string
+	// Unchanged already had the archived content.
+	Unchanged []string
+	// Kept differ from the archive and were left alone, as overwrite was
+	// not set.
+	Kept []string
+}
+
+// Import extracts an archive written by Export into configDir. Files that
+// exist with other content are kept unless overwrite is set. The archive is
+// read in full before anything is written, so a corrupt or unsafe archive
+// changes nothing.
+func Import(r io.Reader, configDir string, overwrite bool) (*Result, error) {
+	files, modes, err := readArchive(r)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &Result{}
+	for _, name := range names {
+		target := filepath.Join(configDir, filepath.FromSlash(name))
+		existing, err := os.ReadFile(target) // #nosec G304 - a validated archive path inside the config directory
+		switch {
+		case err == nil && bytes.Equal(existing, files[name]):
+			result.Unchanged = append(result.Unchanged, name)
+			continue
+		case err == nil && !overwrite:
+			result.Kept = append(result.Kept, name)
+			continue
+		case err != nil && !errors.Is(err, os.ErrNotExist):
+			return result, fmt.Errorf("reading %s: %w", target, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+			return result, fmt.Errorf("creating directory for %s: %w", name, err)
+		}
+		if err := os.WriteFile(target, files[name], modes[name]); err != nil {
+			return result, fmt.Errorf("importing %s: %w", name, err)
+		}
+		// WriteFile leaves the mode of existing files alone
+		if err := os.Chmod(target, modes[name]); err != nil {
+			return result, fmt.Errorf("importing %s: %w", name, err)
+		}
+		result.Written = append(result.Written, name)
+	}
+	return result, nil
+}
+
+// readArchive returns the regular files of a gzipped tar archive and their
+// modes by relative path. Absolute paths, paths leaving the archive and the
+// secret store files are rejected.
+func readArchive(r io.Reader) (map[string][]byte, map[string]os.FileMode, error) {
+	excluded := make(map[string]bool)
+	for _, name := range secrets.StoreFiles() {
+		excluded[name] = true
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading archive: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	modes := make(map[string]os.FileMode)
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") || excluded[name] {
+			return nil, nil, fmt.Errorf("archive contains an invalid path %q", header.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(archive, maxFileSize+1))
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s from archive: %w", name, err)
+		}
+		if len(data) > maxFileSize {
+			return nil, nil, fmt.Errorf("%s in archive is larger than %d bytes", name, maxFileSize)
+		}
+		files[name] = data
+		modes[name] = 0o600
+		if header.Mode&0o111 != 0 {
+			modes[name] = 0o700
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil, errors.New("archive contains no files")
+	}
+	return files, modes, nil
+}
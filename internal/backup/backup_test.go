@@ -0,0 +1,107 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	source := t.TempDir()
+	writeFiles(t, source, map[string]string{
+		"fast-cc-config.yaml": "types: [feat]\n",
+		"jira-commit-ref.txt": "PROJ-1\n",
+		"plugins/lint":        "#!/bin/sh\n",
+		"credentials.enc":     "secret",
+		"credentials.key":     "key",
+	})
+	if err := os.Chmod(filepath.Join(source, "plugins", "lint"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	names, err := Export(&archive, source)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if want := []string{"fast-cc-config.yaml", "jira-commit-ref.txt", "plugins/lint"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Export() = %q, want %q", names, want)
+	}
+
+	target := t.TempDir()
+	writeFiles(t, target, map[string]string{"jira-commit-ref.txt": "PROJ-2\n", "fast-cc-config.yaml": "types: [feat]\n"})
+	result, err := Import(bytes.NewReader(archive.Bytes()), target, false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	want := &Result{Written: []string{"plugins/lint"}, Unchanged: []string{"fast-cc-config.yaml"}, Kept: []string{"jira-commit-ref.txt"}}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Import() = %+v, want %+v", result, want)
+	}
+	if info, err := os.Stat(filepath.Join(target, "plugins", "lint")); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm() != 0o700) {
+		t.Errorf("imported plugin: %v, %v, want an executable file", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "credentials.enc")); !os.IsNotExist(err) {
+		t.Errorf("secrets were imported: %v", err)
+	}
+
+	result, err = Import(bytes.NewReader(archive.Bytes()), target, true)
+	if err != nil || !reflect.DeepEqual(result.Written, []string{"jira-commit-ref.txt"}) {
+		t.Fatalf("Import() with overwrite = %+v, %v", result, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(target, "jira-commit-ref.txt")); string(data) != "PROJ-1\n" {
+		t.Errorf("overwritten file = %q", data)
+	}
+}
+
+func TestImportRejectsUnsafeArchives(t *testing.T) {
+	for _, name := range []string{"../escape", "/etc/passwd", "credentials.key"} {
+		var archive bytes.Buffer
+		gz := gzip.NewWriter(&archive)
+		writer := tar.NewWriter(gz)
+		for _, entry := range []string{"fast-cc-config.yaml", name} {
+			if err := writer.WriteHeader(&tar.Header{Name: entry, Mode: 0o600, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := writer.Write([]byte("x")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		target := t.TempDir()
+		if _, err := Import(&archive, target, true); err == nil {
+			t.Errorf("Import() of %q succeeded", name)
+		}
+		if entries, _ := os.ReadDir(target); len(entries) != 0 {
+			t.Errorf("Import() of %q wrote %d file(s)", name, len(entries))
+		}
+	}
+
+	if _, err := Import(bytes.NewReader([]byte("not an archive")), t.TempDir(), false); err == nil {
+		t.Error("Import() of a non-archive succeeded")
+	}
+}
@@ -0,0 +1,177 @@
+package benchmarks
+
+import (
+	"context"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/dirs"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// messages are typical commit messages, all valid under ReferenceConfig.
+var messages = []struct {
+	name    string
+	message string
+}{
+	{"header", "feat(api): CGC-1425 add pagination to the orders endpoint"},
+	{"body", "fix(auth): CGC-88 refresh expired tokens before retrying\n\n" +
+		"Requests made while a token expired failed with 401 and were not\n" +
+		"retried, so long-running jobs aborted overnight.\n\n" +
+		"Reviewed-by: Jane Doe <jane@example.com>\nRefs: #412"},
+	{"breaking", "feat(db)!: CGC-2001 drop the legacy schema\n\n" +
+		"BREAKING CHANGE: the v1 tables are removed; run the migration first."},
+}
+
+func BenchmarkParse(b *testing.B) {
+	parser := conventionalcommit.DefaultParser()
+	for _, m := range messages {
+		b.Run(m.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := parser.Parse(m.message); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	v := referenceValidator(b)
+	ctx := context.Background()
+	for _, m := range messages {
+		b.Run(m.name, func(b *testing.B) {
+			for b.Loop() {
+				if result := v.Validate(ctx, m.message); !result.Valid {
+					b.Fatal(result.Error())
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkHook covers what a commit-msg hook does in process: load the
+// config, build the validator and validate the message file.
+func BenchmarkHook(b *testing.B) {
+	file := messageFile(b, messages[1].message)
+	ctx := context.Background()
+	for b.Loop() {
+		cfg, err := config.Load(ReferenceConfig)
+		if err != nil {
+			b.Fatal(err)
+		}
+		v, err := validator.New(cfg)
+		if err != nil {
+			b.Fatal(err)
+		}
+		result, err := v.ValidateFile(ctx, file)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if !result.Valid {
+			b.Fatal(result.Error())
+		}
+	}
+}
+
+// BenchmarkHookProcess runs the hook's command, `fcgh validate --file`, as
+// git runs it.
+func BenchmarkHookProcess(b *testing.B) {
+	hook := hookCommand(b, messages[1].message)
+	for b.Loop() {
+		if out, err := hook().CombinedOutput(); err != nil {
+			b.Fatalf("fcgh validate: %v\n%s", err, out)
+		}
+	}
+}
+
+// TestHookBudget fails when the 95th percentile of hook runs exceeds
+// HookBudget. It runs only when BudgetEnv is set.
+func TestHookBudget(t *testing.T) {
+	if os.Getenv(BudgetEnv) == "" {
+		t.Skipf("set %s=1 to check the hook performance budget", BudgetEnv)
+	}
+	hook := hookCommand(t, messages[1].message)
+	const warmup, runs = 5, 100
+	durations := make([]time.Duration, 0, runs)
+	for i := 0; i < warmup+runs; i++ {
+		start := time.Now()
+		if out, err := hook().CombinedOutput(); err != nil {
+			t.Fatalf("fcgh validate: %v\n%s", err, out)
+		}
+		if i >= warmup {
+			durations = append(durations, time.Since(start))
+		}
+	}
+
+	slices.Sort(durations)
+	p95 := durations[int(math.Ceil(0.95*runs))-1]
+	t.Logf("hook p50 %s, p95 %s, max %s (budget %s)", durations[runs/2], p95, durations[runs-1], HookBudget)
+	if p95 > HookBudget {
+		t.Errorf("hook p95 %s exceeds the %s budget", p95, HookBudget)
+	}
+}
+
+// referenceValidator returns a validator for ReferenceConfig.
+func referenceValidator(tb testing.TB) *validator.Validator {
+	tb.Helper()
+	cfg, err := config.Load(ReferenceConfig)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	v, err := validator.New(cfg)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return v
+}
+
+// messageFile writes message to a COMMIT_EDITMSG file, as git does.
+func messageFile(tb testing.TB, message string) string {
+	tb.Helper()
+	file := filepath.Join(tb.TempDir(), "COMMIT_EDITMSG")
+	if err := os.WriteFile(file, []byte(message+"\n"), 0o600); err != nil {
+		tb.Fatal(err)
+	}
+	return file
+}
+
+// hookCommand builds fcgh and returns a function creating the hook's command
+// for message, isolated from the user's home and config.
+func hookCommand(tb testing.TB, message string) func() *exec.Cmd {
+	tb.Helper()
+	dir := tb.TempDir()
+	binary := filepath.Join(dir, "fcgh")
+	build := exec.Command("go", "build", "-o", binary, "github.com/greenstevester/fast-cc-git-hooks/cmd/fcgh")
+	if out, err := build.CombinedOutput(); err != nil {
+		tb.Fatalf("building fcgh: %v\n%s", err, out)
+	}
+	// The hook finds its config in the config directory, as installed
+	reference, err := os.ReadFile(ReferenceConfig)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	configDir := filepath.Join(dir, "config")
+	if err := os.MkdirAll(configDir, 0o750); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, config.DefaultConfigFile), reference, 0o600); err != nil {
+		tb.Fatal(err)
+	}
+	file := messageFile(tb, message)
+	env := append(os.Environ(), "HOME="+dir, dirs.EnvConfigDir+"="+configDir, "XDG_CACHE_HOME="+filepath.Join(dir, "cache"))
+
+	return func() *exec.Cmd {
+		cmd := exec.Command(binary, "validate", "--file", file) // #nosec G204 - the binary built above
+		cmd.Dir = dir
+		cmd.Env = env
+		return cmd
+	}
+}
@@ -0,0 +1,22 @@
+// Package benchmarks measures the parse, validate and commit-msg hook paths
+// against a reference config and enforces the hook's performance budget, so
+// "fast" stays a measured property.
+//
+// Run the benchmarks with `make bench`, compare a branch with its base with
+// `make bench-compare`, and check the budget with `make bench-budget`.
+package benchmarks
+
+import "time"
+
+// ReferenceConfig is the config the benchmarks run with, relative to this
+// package: the enterprise example, with scopes and JIRA ticket checks but no
+// network lookups.
+const ReferenceConfig = "../../example-configs/fast-cc-hooks.enterprise.yaml"
+
+// HookBudget is the 95th percentile duration a commit-msg hook run, process
+// start included, must stay below on ReferenceConfig.
+const HookBudget = 20 * time.Millisecond
+
+// BudgetEnv enables the budget test, which builds fcgh and times it; it is
+// off by default as timings vary on loaded machines.
+const BudgetEnv = "FCGH_BENCH_BUDGET"
@@ -0,0 +1,163 @@
+// Package clipboard copies text to the system clipboard. It uses the
+// platform's clipboard command (pbcopy, clip, wl-copy, xclip or xsel, and
+// clip.exe under WSL) and falls back to an OSC 52 terminal escape sequence,
+// which also reaches the local clipboard from SSH sessions.
+package clipboard
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// OSC52 is the method reported when text was sent to the terminal as an
+// OSC 52 escape sequence. Terminals do not acknowledge it, so the copy only
+// succeeds if the terminal allows clipboard access.
+const OSC52 = "OSC 52"
+
+// ErrUnavailable is returned when no clipboard command or terminal is available.
+var ErrUnavailable = errors.New("no clipboard available")
+
+// command is a clipboard command reading the text from stdin
+type command struct {
+	name string
+	args []string
+}
+
+// environment is what Copy inspects to choose a clipboard
+type environment struct {
+	goos     string
+	getenv   func(string) string
+	lookPath func(string) (string, error)
+	wsl      bool
+}
+
+// Copy copies text to the clipboard and returns the method used: a command
+// name or OSC52. Inside SSH sessions without a forwarded display the
+// terminal is preferred, since commands would copy on the remote machine.
+func Copy(text string) (string, error) {
+	env := environment{
+		goos:     runtime.GOOS,
+		getenv:   os.Getenv,
+		lookPath: exec.LookPath,
+		wsl:      isWSL(),
+	}
+
+	var failures []string
+	osc52 := func() bool {
+		tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+		if err != nil {
+			return false
+		}
+		defer tty.Close()
+		if err := writeOSC52(tty, text, env.getenv("TMUX") != ""); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", OSC52, err))
+			return false
+		}
+		return true
+	}
+
+	if env.remote() && osc52() {
+		return OSC52, nil
+	}
+	for _, cmd := range env.commands() {
+		c := exec.Command(cmd.name, cmd.args...) // #nosec G204 - fixed clipboard commands
+		c.Stdin = strings.NewReader(text)
+		if output, err := c.CombinedOutput(); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v %s", cmd.name, err, strings.TrimSpace(string(output))))
+			continue
+		}
+		return cmd.name, nil
+	}
+	if !env.remote() && osc52() {
+		return OSC52, nil
+	}
+
+	if len(failures) > 0 {
+		return "", fmt.Errorf("%w (%s)", ErrUnavailable, strings.Join(failures, "; "))
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnavailable, env.hint())
+}
+
+// remote reports whether this is an SSH session without a forwarded display
+func (e environment) remote() bool {
+	ssh := e.getenv("SSH_TTY") != "" || e.getenv("SSH_CONNECTION") != ""
+	return ssh && e.getenv("DISPLAY") == "" && e.getenv("WAYLAND_DISPLAY") == ""
+}
+
+// commands returns the installed clipboard commands to try, best first
+func (e environment) commands() []command {
+	var candidates []command
+	switch {
+	case e.goos == "darwin":
+		candidates = append(candidates, command{name: "pbcopy"})
+	case e.goos == "windows":
+		candidates = append(candidates, command{name: "clip"})
+	case e.wsl:
+		candidates = append(candidates, command{name: "clip.exe"})
+	}
+	if e.goos != "darwin" && e.goos != "windows" {
+		if e.getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, command{name: "wl-copy"})
+		}
+		if e.getenv("DISPLAY") != "" {
+			candidates = append(candidates,
+				command{name: "xclip", args: []string{"-selection", "clipboard"}},
+				command{name: "xsel", args: []string{"--clipboard", "--input"}})
+		}
+	}
+
+	installed := candidates[:0]
+	for _, cmd := range candidates {
+		if _, err := e.lookPath(cmd.name); err == nil {
+			installed = append(installed, cmd)
+		}
+	}
+	return installed
+}
+
+// hint says what to install or enable so copying works
+func (e environment) hint() string {
+	switch {
+	case e.goos == "darwin":
+		return "pbcopy not found"
+	case e.goos == "windows":
+		return "clip not found"
+	case e.wsl:
+		return "clip.exe not found; enable Windows interop in WSL"
+	case e.getenv("WAYLAND_DISPLAY") != "":
+		return "install wl-clipboard"
+	case e.getenv("DISPLAY") != "":
+		return "install xclip or xsel"
+	default:
+		return "no display and no terminal to send OSC 52 to"
+	}
+}
+
+// writeOSC52 sends text to the terminal's clipboard, wrapped for tmux so it
+// reaches the outer terminal
+func writeOSC52(w io.Writer, text string, tmux bool) error {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if tmux {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	_, err := io.WriteString(w, seq)
+	return err
+}
+
+// isWSL reports whether this is Linux running under the Windows Subsystem for Linux
+func isWSL() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(release)), "microsoft")
+}
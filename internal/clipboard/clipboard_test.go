@@ -0,0 +1,87 @@
+package clipboard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testEnvironment(goos string, vars map[string]string, installed ...string) environment {
+	return environment{
+		goos:   goos,
+		getenv: func(key string) string { return vars[key] },
+		lookPath: func(name string) (string, error) {
+			for _, cmd := range installed {
+				if cmd == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		},
+	}
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		env  environment
+		want string
+	}{
+		{"macOS", testEnvironment("darwin", nil, "pbcopy"), "pbcopy"},
+		{"Windows", testEnvironment("windows", nil, "clip"), "clip"},
+		{"Wayland before X11", testEnvironment("linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, "wl-copy", "xclip"), "wl-copy,xclip"},
+		{"X11 without xclip", testEnvironment("linux", map[string]string{"DISPLAY": ":0"}, "xsel"), "xsel"},
+		{"no display", testEnvironment("linux", nil, "xclip", "wl-copy"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, cmd := range tt.env.commands() {
+				names = append(names, cmd.name)
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Errorf("commands() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	wsl := testEnvironment("linux", map[string]string{"DISPLAY": ":0"}, "clip.exe", "xclip")
+	wsl.wsl = true
+	if cmds := wsl.commands(); len(cmds) != 2 || cmds[0].name != "clip.exe" {
+		t.Errorf("commands() under WSL = %+v, want clip.exe first", cmds)
+	}
+	wsl.lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if hint := wsl.hint(); !strings.Contains(hint, "interop") {
+		t.Errorf("hint() under WSL = %q", hint)
+	}
+}
+
+func TestRemote(t *testing.T) {
+	if !testEnvironment("linux", map[string]string{"SSH_TTY": "/dev/pts/0"}).remote() {
+		t.Error("SSH session without a display is not remote")
+	}
+	if testEnvironment("linux", map[string]string{"SSH_CONNECTION": "10.0.0.1 22 10.0.0.2 22", "DISPLAY": "localhost:10.0"}).remote() {
+		t.Error("SSH session with X forwarding is remote")
+	}
+	if testEnvironment("darwin", nil).remote() {
+		t.Error("local session is remote")
+	}
+}
+
+func TestWriteOSC52(t *testing.T) {
+	var sb strings.Builder
+	if err := writeOSC52(&sb, "git commit", false); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sb.String(), "\x1b]52;c;Z2l0IGNvbW1pdA==\a"; got != want {
+		t.Errorf("writeOSC52() = %q, want %q", got, want)
+	}
+
+	sb.Reset()
+	if err := writeOSC52(&sb, "git commit", true); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sb.String(), "\x1bPtmux;\x1b\x1b]52;c;Z2l0IGNvbW1pdA==\a\x1b\\"; got != want {
+		t.Errorf("writeOSC52() in tmux = %q, want %q", got, want)
+	}
+}
@@ -5,23 +5,36 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/greenstevester/fast-cc-git-hooks/internal/dirs"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/policy"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/update"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
 	"gopkg.in/yaml.v3"
 )
 
 const (
 	// DefaultConfigFile is the default configuration filename.
 	DefaultConfigFile = "fast-cc-config.yaml"
-	// DefaultConfigDir is the default configuration directory.
-	DefaultConfigDir = ".fast-cc"
+	// DefaultConfigDir is the legacy configuration directory in the home
+	// directory, used until it is migrated to the XDG config directory.
+	DefaultConfigDir = dirs.LegacyName
 	// DefaultMaxSubjectLength is the default maximum subject line length.
 	DefaultMaxSubjectLength = 72
 )
 
 // Config represents the complete configuration for fast-cc-hooks.
 type Config struct {
+	// Extends names a built-in preset ("preset:angular") the rest of the
+	// file overrides.
+	Extends string `yaml:"extends,omitempty"`
 	// JIRATicketPattern defines a regex pattern for valid JIRA tickets.
 	JIRATicketPattern string `yaml:"jira_ticket_pattern,omitempty"`
 	// Types defines allowed commit types.
@@ -34,16 +47,326 @@ type Config struct {
 	IgnorePatterns []string `yaml:"ignore_patterns,omitempty"`
 	// JIRAProjects defines allowed JIRA project prefixes.
 	JIRAProjects []string `yaml:"jira_projects,omitempty"`
+	// JIRAURL is the JIRA server used for API lookups (FCGH_JIRA_URL overrides it).
+	JIRAURL string `yaml:"jira_url,omitempty"`
+	// VerifyJIRATickets checks via the JIRA API that referenced tickets exist,
+	// are not closed and belong to an allowed project.
+	VerifyJIRATickets bool `yaml:"verify_jira_tickets,omitempty"`
+	// TicketProvider selects the issue tracker for the current-ticket workflow:
+	// jira (default), github, linear or azure.
+	TicketProvider string `yaml:"ticket_provider,omitempty"`
+	// GitHubRepo is the "owner/name" repository used to check GitHub issues.
+	GitHubRepo string `yaml:"github_repo,omitempty"`
+	// VerifyTickets checks that referenced tickets exist via the provider's
+	// API (GitHub issues; JIRA uses verify_jira_tickets).
+	VerifyTickets bool `yaml:"verify_tickets,omitempty"`
+	// RequireClosingKeyword requires fix commits to close a GitHub issue
+	// from the footer ("Fixes #123" or "Closes org/repo#45").
+	RequireClosingKeyword bool `yaml:"require_closing_keyword,omitempty"`
+	// TicketPatterns recognizes organization-specific ticket formats (such as
+	// SNOW-123 or AB#456) in addition to JIRA and GitHub references.
+	TicketPatterns []TicketPattern `yaml:"ticket_patterns,omitempty"`
+	// TicketPlacement requires referenced tickets to appear at the "start" or
+	// "end" of the description or in a "footer" (Refs: trailer); empty allows
+	// them anywhere. ccg places the current ticket accordingly.
+	TicketPlacement string `yaml:"ticket_placement,omitempty"`
+	// MaxTicketsPerCommit limits how many tickets a commit may reference (0 is unlimited).
+	MaxTicketsPerCommit int `yaml:"max_tickets_per_commit,omitempty"`
+	// RequirePrimaryTicket requires exactly one ticket in the subject when a
+	// commit references tickets; related tickets and sub-tasks go in the body.
+	RequirePrimaryTicket bool `yaml:"require_primary_ticket,omitempty"`
+	// SmartCommits configures JIRA smart commit commands (#time, #comment, #transition).
+	SmartCommits SmartCommitConfig `yaml:"smart_commits,omitempty"`
 	// MaxSubjectLength defines maximum subject line length.
 	MaxSubjectLength int `yaml:"max_subject_length"`
+	// ExcludeTicketFromLength leaves ticket references out of the subject
+	// length, so a required ticket does not eat into max_subject_length.
+	ExcludeTicketFromLength bool `yaml:"exclude_ticket_from_length,omitempty"`
+	// SubjectLengthMode sets how the subject is measured: "runes" (default),
+	// "graphemes" (user-perceived characters) or "width" (display columns,
+	// where CJK characters count twice).
+	SubjectLengthMode string `yaml:"subject_length_mode,omitempty"`
+	// ScopeDelimiters lists the characters ("," and/or "/") separating
+	// multiple scopes, as in feat(api,web); empty allows a single scope.
+	ScopeDelimiters string `yaml:"scope_delimiters,omitempty"`
+	// MaxScopes limits how many scopes a commit may list (0 is unlimited).
+	MaxScopes int `yaml:"max_scopes,omitempty"`
+	// ScopeFormat constrains how scopes are written (case, length, charset).
+	ScopeFormat ScopeFormatConfig `yaml:"scope_format,omitempty"`
+	// ScopeSources derives allowed scopes, and the scope of each path, from
+	// the repository's project structure.
+	ScopeSources ScopeSourcesConfig `yaml:"scope_sources,omitempty"`
+	// TypeCase requires "lower" case commit types (empty compares types as listed).
+	TypeCase string `yaml:"type_case,omitempty"`
+	// NoRedundantWords rejects descriptions that repeat the type ("fix: fix
+	// the bug") or a word in another form ("add new added feature").
+	NoRedundantWords bool `yaml:"no_redundant_words,omitempty"`
+	// DuplicateSubjects warns when a commit subject repeats one of the last
+	// DuplicateSubjects commit subjects, as in "fix" loops (0 disables it).
+	DuplicateSubjects int `yaml:"duplicate_subjects,omitempty"`
+	// AutoFix rewrites fixable format problems (type and scope case,
+	// redundant words) in the commit message file instead of rejecting the
+	// commit.
+	AutoFix bool `yaml:"auto_fix,omitempty"`
 	// ScopeRequired indicates if scope is mandatory.
 	ScopeRequired bool `yaml:"scope_required"`
+	// TypeRules overrides max_subject_length and scope_required for
+	// commits of a type, keyed by type.
+	TypeRules map[string]TypeRule `yaml:"type_rules,omitempty"`
 	// AllowBreakingChanges permits breaking change indicators (!).
 	AllowBreakingChanges bool `yaml:"allow_breaking_changes"`
+	// RequireBreakingDescription requires a "BREAKING CHANGE: <description>"
+	// footer on breaking changes, so changelogs can say what broke.
+	RequireBreakingDescription bool `yaml:"require_breaking_description,omitempty"`
+	// RequireSignoff requires a "Signed-off-by:" trailer (git commit -s).
+	RequireSignoff bool `yaml:"require_signoff,omitempty"`
+	// ReviewTrailers checks the Reviewed-by, Acked-by and Tested-by trailers.
+	ReviewTrailers ReviewTrailersConfig `yaml:"review_trailers,omitempty"`
+	// SignedCommits requires commits of some types or scopes to be signed
+	// by trusted keys.
+	SignedCommits SignedCommitsConfig `yaml:"signed_commits,omitempty"`
+	// Checks run external commands on every message, for organization
+	// rules the built-in ones do not cover.
+	Checks []CheckConfig `yaml:"checks,omitempty"`
+	// Tests are sample messages with their expected outcome, run by
+	// `fcgh config test` to catch rule regressions.
+	Tests []RuleTest `yaml:"tests,omitempty"`
 	// RequireJIRATicket requires JIRA ticket references in commits.
 	RequireJIRATicket bool `yaml:"require_jira_ticket"`
 	// RequireTicketRef requires any type of ticket reference in commits.
 	RequireTicketRef bool `yaml:"require_ticket_ref"`
+	// Plugins holds per-plugin semantic analysis settings keyed by plugin name.
+	// "enabled" and "priority" control the plugin; other keys are plugin options.
+	Plugins map[string]map[string]string `yaml:"plugins,omitempty"`
+	// MinConfidence is the semantic analysis confidence (0-1) below which ccg
+	// asks for the commit subject instead of guessing (0 disables the prompt).
+	MinConfidence float64 `yaml:"min_confidence,omitempty"`
+	// Hotspots configures detection of files changed repeatedly in recent commits.
+	Hotspots HotspotConfig `yaml:"hotspots,omitempty"`
+	// History configures the similar previous commits ccg offers for wording.
+	History HistoryConfig `yaml:"history,omitempty"`
+	// CoverageHints adds body notes to the messages ccg, ccdo and the
+	// prepare-commit-msg hook generate: "No test changes included." when
+	// production code changes without tests, and the docs updated with it.
+	CoverageHints bool `yaml:"coverage_hints,omitempty"`
+	// MessageTemplate shapes the messages ccg, ccdo and the
+	// prepare-commit-msg hook generate.
+	MessageTemplate MessageTemplateConfig `yaml:"message_template,omitempty"`
+	// TypeLabels maps commit types, and "breaking" for breaking changes, to
+	// the pull request labels `fcgh labels` applies (DefaultTypeLabels when
+	// empty).
+	TypeLabels map[string]string `yaml:"type_labels,omitempty"`
+	// ScopeReviewers maps scopes to the reviewers (users or teams, such as
+	// "@org/payments") of the routing file `fcgh routing` writes; other
+	// scopes are routed to the CODEOWNERS owners of their paths.
+	ScopeReviewers map[string][]string `yaml:"scope_reviewers,omitempty"`
+	// Webhook posts blocked commits and hook bypasses to an HTTP endpoint.
+	Webhook WebhookConfig `yaml:"webhook,omitempty"`
+	// PrePush configures the pre-push hook (setup --pre-push), which
+	// re-validates outgoing commits to catch git commit --no-verify.
+	PrePush PrePushConfig `yaml:"pre_push,omitempty"`
+	// Policy configures signed config bundles installed with `fcgh policy pull`.
+	Policy PolicyConfig `yaml:"policy,omitempty"`
+	// Updates configures the opt-in update check and `fcgh self-update`.
+	Updates UpdatesConfig `yaml:"updates,omitempty"`
+	// Locked lists keys that user and repository config files and environment
+	// variables cannot override. Only the admin config may set it.
+	Locked []string `yaml:"locked,omitempty"`
+}
+
+// ScopeFormatConfig defines scope format rules. Case is "lower" or "kebab"
+// (lowercase words joined by hyphens), and Charset is a regular expression
+// character class such as "a-z0-9-".
+type ScopeFormatConfig struct {
+	Case      string `yaml:"case,omitempty"`
+	MaxLength int    `yaml:"max_length,omitempty"`
+	Charset   string `yaml:"charset,omitempty"`
+}
+
+// ScopeSourcesConfig lists where scopes are derived from: "codeowners"
+// (directories in CODEOWNERS), "pnpm-workspace" (pnpm-workspace.yaml
+// packages), "go-work" (go.work modules) and "mapping" (MappingFile, a YAML
+// map of path prefixes to scopes). Derived scopes are allowed in addition
+// to Scopes.
+type ScopeSourcesConfig struct {
+	From []string `yaml:"from,omitempty"`
+	// MappingFile is relative to the repository root (DefaultScopeMappingFile
+	// when empty).
+	MappingFile string `yaml:"mapping_file,omitempty"`
+}
+
+// DefaultScopeMappingFile is the scope mapping read by the "mapping" scope source.
+const DefaultScopeMappingFile = ".fast-cc/scopes.yaml"
+
+// ScopeSourceNames are the valid scope_sources.from entries.
+var ScopeSourceNames = []string{"codeowners", "pnpm-workspace", "go-work", "mapping"}
+
+// ReviewTrailersConfig defines checks of the review trailers Reviewed-by,
+// Acked-by and Tested-by. Validate requires each to name a person as
+// "Name <email>". RequireReviewedBy lists branch patterns such as "main" or
+// "release/*" whose commits need a Reviewed-by trailer; it applies only when
+// the target branch is known, as in server-side validation.
+type ReviewTrailersConfig struct {
+	Validate          bool     `yaml:"validate,omitempty"`
+	RequireReviewedBy []string `yaml:"require_reviewed_by,omitempty"`
+}
+
+// SignedCommitsConfig lists the types (such as "revert") and scopes whose
+// commits must be GPG or SSH signed by one of Keys: GPG key fingerprints or
+// long key IDs, or SSH key fingerprints ("SHA256:..."). Signatures are only
+// known for existing commits, so it applies to validate --stdin-batch input
+// with commit ids, as in server-side hooks.
+type SignedCommitsConfig struct {
+	Types  []string `yaml:"types,omitempty"`
+	Scopes []string `yaml:"scopes,omitempty"`
+	Keys   []string `yaml:"keys,omitempty"`
+}
+
+// TypeRule overrides the subject and scope rules for one commit type, such
+// as a longer subject for docs or a required scope for feat. Unset fields
+// keep the top-level setting.
+type TypeRule struct {
+	MaxSubjectLength int   `yaml:"max_subject_length,omitempty"`
+	ScopeRequired    *bool `yaml:"scope_required,omitempty"`
+}
+
+// Check stages select when an external check runs.
+const (
+	// CheckStagePreParse checks the raw message before it is parsed.
+	CheckStagePreParse = "pre-parse"
+	// CheckStagePostValidate checks the parsed commit after the built-in
+	// rules.
+	CheckStagePostValidate = "post-validate"
+	// DefaultCheckTimeout bounds a check run, in seconds.
+	DefaultCheckTimeout = 10
+)
+
+// CheckConfig registers an external check: Command (the program and its
+// arguments) reads the message as JSON on stdin and prints its violations as
+// JSON on stdout. Stage is CheckStagePostValidate when empty, and Timeout is
+// in seconds (DefaultCheckTimeout when zero).
+type CheckConfig struct {
+	Name    string   `yaml:"name"`
+	Command []string `yaml:"command"`
+	Stage   string   `yaml:"stage,omitempty"`
+	Timeout int      `yaml:"timeout,omitempty"`
+}
+
+// RuleTest is a sample message and its expected outcome. Expect is "pass"
+// or "fail" ("fail" when empty); Rules lists rules the message must break,
+// by error field (such as "scope") or custom rule and check name.
+type RuleTest struct {
+	Name    string   `yaml:"name,omitempty"`
+	Message string   `yaml:"message"`
+	Expect  string   `yaml:"expect,omitempty"`
+	Rules   []string `yaml:"rules,omitempty"`
+}
+
+// HotspotConfig defines when a file counts as a hotspot: touched by at least
+// Threshold of the last Window commits (zero values use the built-in defaults).
+type HotspotConfig struct {
+	Window    int `yaml:"window,omitempty"`
+	Threshold int `yaml:"threshold,omitempty"`
+}
+
+// HistoryConfig defines how many of the last Window commits are searched
+// for changes to similar files and how many Suggestions are offered (zero
+// values use the built-in defaults). Disabled offers none.
+type HistoryConfig struct {
+	Window      int  `yaml:"window,omitempty"`
+	Suggestions int  `yaml:"suggestions,omitempty"`
+	Disabled    bool `yaml:"disabled,omitempty"`
+}
+
+// TemplateVariables are the variables message templates expand, such as
+// {branch}: the generated {header} and its {type}, {scope} and
+// {description}, the checked-out {branch}, the current {ticket}, the
+// {monorepo_package} all staged files belong to (from scope_sources), and
+// the commit {author} and their {author_initials}.
+var TemplateVariables = []string{"header", "type", "scope", "description", "branch", "ticket", "monorepo_package", "author", "author_initials"}
+
+var (
+	// templateVariableRegex matches a {variable} in a message template.
+	templateVariableRegex = regexp.MustCompile(`\{([a-z_]+)\}`)
+	templateNameRegex     = regexp.MustCompile(`^[a-z_]+$`)
+)
+
+// MessageTemplateConfig rewrites generated messages. Header replaces the
+// generated header ("{header}" keeps it) and Footer lines are appended;
+// footer lines whose variables are all empty are left out. Variables
+// defines constant variables in addition to TemplateVariables.
+type MessageTemplateConfig struct {
+	Header    string            `yaml:"header,omitempty"`
+	Footer    []string          `yaml:"footer,omitempty"`
+	Variables map[string]string `yaml:"variables,omitempty"`
+}
+
+// SmartCommitConfig enables JIRA smart commits. When enabled, ccg appends the
+// default commands to generated messages and the validator checks the
+// commands found on ticket lines.
+type SmartCommitConfig struct {
+	Enabled            bool     `yaml:"enabled,omitempty"`
+	Comment            string   `yaml:"comment,omitempty"`
+	Transition         string   `yaml:"transition,omitempty"`
+	AllowedTransitions []string `yaml:"allowed_transitions,omitempty"`
+}
+
+// WebhookConfig defines where commit policy events are posted. The JSON
+// payload includes a "text" summary, so Slack incoming webhooks work as is.
+type WebhookConfig struct {
+	URL string `yaml:"url,omitempty"`
+	// Events selects "blocked" and/or "bypass" (empty sends both).
+	Events []string `yaml:"events,omitempty"`
+}
+
+// Pre-push actions select what the pre-push hook does with invalid commits.
+const (
+	// PrePushRecord logs invalid commits and lets the push through.
+	PrePushRecord = "record"
+	// PrePushBlock rejects the push.
+	PrePushBlock = "block"
+	// DefaultPrePushLog is the audit log of invalid pushed commits, in the
+	// git directory.
+	DefaultPrePushLog = "fcgh-bypass.log"
+)
+
+// PrePushConfig defines what the pre-push hook does with pushed commits
+// whose messages break the rules. Action is PrePushRecord when empty; each
+// commit is then appended to Log as a JSON line (DefaultPrePushLog when
+// empty, relative paths are in the git directory) and sent to the webhook
+// as a bypass event.
+type PrePushConfig struct {
+	Action string `yaml:"action,omitempty"`
+	Log    string `yaml:"log,omitempty"`
+}
+
+// PolicyConfig points at a signed policy bundle. With Verify set, hooks
+// refuse to run when the installed bundle or its files fail verification
+// against PublicKey.
+type PolicyConfig struct {
+	URL string `yaml:"url,omitempty"`
+	// PublicKey is the pinned base64 ed25519 key that signs the bundle.
+	PublicKey string `yaml:"public_key,omitempty"`
+	Verify    bool   `yaml:"verify,omitempty"`
+}
+
+// UpdatesConfig configures release checks. With Check, fcgh notifies at
+// most daily when the channel ("stable" by default, or "prerelease") has a
+// newer release in Repo (the upstream repository by default). PublicKey pins
+// the base64 ed25519 key that must sign a release's checksums.txt.
+type UpdatesConfig struct {
+	Check     bool   `yaml:"check,omitempty"`
+	Channel   string `yaml:"channel,omitempty"`
+	Repo      string `yaml:"repo,omitempty"`
+	PublicKey string `yaml:"public_key,omitempty"`
+}
+
+// TicketPattern defines an extra ticket reference format. The first capture
+// group of the pattern is the ticket ID, and {id} in the URL is replaced by it.
+type TicketPattern struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+	URL     string `yaml:"url,omitempty"`
 }
 
 // CustomRule defines a custom validation rule.
@@ -53,13 +376,10 @@ type CustomRule struct {
 	Message string `yaml:"message"`
 }
 
-// GetDefaultConfigDir returns the default configuration directory path.
+// GetDefaultConfigDir returns the default configuration directory path:
+// FCGH_CONFIG_DIR, else $XDG_CONFIG_HOME/fast-cc or a legacy ~/.fast-cc.
 func GetDefaultConfigDir() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("getting home directory: %w", err)
-	}
-	return filepath.Join(home, DefaultConfigDir), nil
+	return dirs.Config()
 }
 
 // GetDefaultConfigPath returns the default configuration file path.
@@ -88,6 +408,18 @@ func DefaultTypes() []string {
 	}
 }
 
+// BreakingLabelKey is the type_labels key of the label for breaking changes.
+const BreakingLabelKey = "breaking"
+
+// DefaultTypeLabels returns the GitHub default labels for commit types.
+func DefaultTypeLabels() map[string]string {
+	return map[string]string{
+		"feat": "enhancement",
+		"fix":  "bug",
+		"docs": "documentation",
+	}
+}
+
 // Default returns a default configuration.
 func Default() *Config {
 	return &Config{
@@ -101,58 +433,49 @@ func Default() *Config {
 	}
 }
 
-// Load reads configuration from a file.
+// Load reads configuration from a file, layered over the admin config at
+// AdminConfigPath when one exists.
 func Load(path string) (*Config, error) {
-	if path == "" {
-		// Try default path in home directory first
-		if defaultPath, err := GetDefaultConfigPath(); err == nil {
-			if _, err := os.Stat(defaultPath); err == nil {
-				path = defaultPath
-			} else {
-				// Check for old filename in home directory for backward compatibility
-				oldPath := filepath.Join(filepath.Dir(defaultPath), ".fast-cc-hooks.yaml")
-				if _, err := os.Stat(oldPath); err == nil {
-					path = oldPath
-				} else {
-					// Fall back to current directory (new filename first)
-					if _, err := os.Stat(DefaultConfigFile); err == nil {
-						path = DefaultConfigFile
-					} else {
-						// Check for old filename in current directory
-						path = ".fast-cc-hooks.yaml"
-					}
-				}
-			}
-		} else {
-			path = DefaultConfigFile
-		}
-	}
+	cfg, _, err := LoadWithProvenance(path)
+	return cfg, err
+}
 
-	// Check if file exists.
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		// Return default config if file doesn't exist.
-		return Default(), nil
+// resolvePath returns the config file to load when none is given (see
+// FindConfigFile); a missing file loads the defaults.
+func resolvePath(path string) string {
+	if path != "" {
+		return path
 	}
-
-	file, err := os.Open(path) // #nosec G304 - path is validated by caller
-	if err != nil {
-		return nil, fmt.Errorf("opening config file: %w", err)
+	if found, _, ok := FindConfigFile(); ok {
+		return found
 	}
-	defer file.Close()
-
-	return Parse(file)
+	return DefaultConfigFile
 }
 
-// Parse parses configuration from an io.Reader.
+// Parse parses configuration from an io.Reader, over the preset it extends.
 func Parse(r io.Reader) (*Config, error) {
 	cfg := Default()
 
-	decoder := yaml.NewDecoder(r)
-	if err := decoder.Decode(cfg); err != nil {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
 		if !errors.Is(err, io.EOF) {
 			return nil, fmt.Errorf("parsing config: %w", err)
 		}
 	}
+	if len(doc.Content) > 0 {
+		preset, name, err := presetOf(doc.Content[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+		if preset != nil {
+			if err := preset.Decode(cfg); err != nil {
+				return nil, fmt.Errorf("parsing preset %s: %w", name, err)
+			}
+		}
+		if err := doc.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("parsing config: %w", err)
+		}
+	}
 
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
@@ -196,10 +519,223 @@ func (c *Config) Validate() error {
 		return errors.New("at least one commit type must be defined")
 	}
 
+	if c.Extends != "" {
+		name, ok := strings.CutPrefix(c.Extends, PresetPrefix)
+		if !ok || !slices.Contains(Presets(), name) {
+			return fmt.Errorf("extends must be one of %s%s, got %q", PresetPrefix, strings.Join(Presets(), ", "+PresetPrefix), c.Extends)
+		}
+	}
+
 	if c.MaxSubjectLength <= 0 {
 		return errors.New("max_subject_length must be positive")
 	}
 
+	switch c.SubjectLengthMode {
+	case "", "runes", "graphemes", "width":
+	default:
+		return fmt.Errorf("subject_length_mode must be one of runes, graphemes or width, got %q", c.SubjectLengthMode)
+	}
+
+	if strings.Trim(c.ScopeDelimiters, ",/") != "" {
+		return fmt.Errorf("scope_delimiters may only contain , and /, got %q", c.ScopeDelimiters)
+	}
+	if c.MaxScopes < 0 {
+		return errors.New("max_scopes must not be negative")
+	}
+	if c.MaxScopes > 1 && c.ScopeDelimiters == "" {
+		return errors.New("max_scopes above 1 requires scope_delimiters")
+	}
+
+	switch c.ScopeFormat.Case {
+	case "", "lower", "kebab":
+	default:
+		return fmt.Errorf("scope_format case must be lower or kebab, got %q", c.ScopeFormat.Case)
+	}
+	if c.ScopeFormat.MaxLength < 0 {
+		return errors.New("scope_format max_length must not be negative")
+	}
+	if c.ScopeFormat.Charset != "" {
+		if _, err := regexp.Compile("^[" + c.ScopeFormat.Charset + "]+$"); err != nil {
+			return fmt.Errorf("scope_format charset %q is not a valid character class: %w", c.ScopeFormat.Charset, err)
+		}
+	}
+	if c.TypeCase != "" && c.TypeCase != "lower" {
+		return fmt.Errorf("type_case must be lower, got %q", c.TypeCase)
+	}
+
+	if c.MinConfidence < 0 || c.MinConfidence > 1 {
+		return fmt.Errorf("min_confidence must be between 0 and 1, got %g", c.MinConfidence)
+	}
+
+	for _, source := range c.ScopeSources.From {
+		if !slices.Contains(ScopeSourceNames, source) {
+			return fmt.Errorf("scope_sources entry %q must be one of %s", source, strings.Join(ScopeSourceNames, ", "))
+		}
+	}
+
+	checkNames := make(map[string]bool, len(c.Checks))
+	for _, check := range c.Checks {
+		if check.Name == "" || checkNames[check.Name] {
+			return fmt.Errorf("checks need unique names, got %q", check.Name)
+		}
+		checkNames[check.Name] = true
+		if len(check.Command) == 0 || check.Command[0] == "" {
+			return fmt.Errorf("check %s needs a command", check.Name)
+		}
+		if check.Stage != "" && check.Stage != CheckStagePreParse && check.Stage != CheckStagePostValidate {
+			return fmt.Errorf("check %s stage must be %s or %s, got %q", check.Name, CheckStagePreParse, CheckStagePostValidate, check.Stage)
+		}
+		if check.Timeout < 0 {
+			return fmt.Errorf("check %s timeout must not be negative", check.Name)
+		}
+	}
+
+	for i, test := range c.Tests {
+		if test.Message == "" {
+			return fmt.Errorf("tests entry %d needs a message", i+1)
+		}
+		if test.Expect != "" && test.Expect != "pass" && test.Expect != "fail" {
+			return fmt.Errorf("tests entry %d expect must be pass or fail, got %q", i+1, test.Expect)
+		}
+		if test.Expect == "pass" && len(test.Rules) > 0 {
+			return fmt.Errorf("tests entry %d expects a pass but lists rules", i+1)
+		}
+	}
+
+	for commitType, label := range c.TypeLabels {
+		if strings.TrimSpace(label) == "" {
+			return fmt.Errorf("type_labels entry %q needs a label", commitType)
+		}
+	}
+	for scope, reviewers := range c.ScopeReviewers {
+		if len(reviewers) == 0 || slices.ContainsFunc(reviewers, func(r string) bool { return strings.TrimSpace(r) == "" }) {
+			return fmt.Errorf("scope_reviewers entry %q needs reviewers", scope)
+		}
+	}
+
+	for commitType, rule := range c.TypeRules {
+		if !c.HasType(commitType) {
+			return fmt.Errorf("type_rules entry %q is not one of the commit types", commitType)
+		}
+		if rule.MaxSubjectLength < 0 {
+			return fmt.Errorf("type_rules entry %q max_subject_length must not be negative", commitType)
+		}
+	}
+
+	if signed := c.SignedCommits; len(signed.Types)+len(signed.Scopes) > 0 && len(signed.Keys) == 0 {
+		return errors.New("signed_commits needs the keys commits may be signed with")
+	}
+	for _, key := range c.SignedCommits.Keys {
+		if strings.TrimSpace(key) == "" {
+			return errors.New("signed_commits keys must not be empty")
+		}
+	}
+
+	for _, pattern := range c.ReviewTrailers.RequireReviewedBy {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("review_trailers.require_reviewed_by entry %q is not a valid branch pattern", pattern)
+		}
+	}
+
+	if c.Hotspots.Window < 0 || c.Hotspots.Threshold < 0 {
+		return errors.New("hotspots window and threshold must not be negative")
+	}
+	if c.Hotspots.Window > 0 && c.Hotspots.Threshold > c.Hotspots.Window {
+		return fmt.Errorf("hotspots threshold %d exceeds window %d", c.Hotspots.Threshold, c.Hotspots.Window)
+	}
+
+	if c.DuplicateSubjects < 0 {
+		return errors.New("duplicate_subjects must not be negative")
+	}
+
+	if c.History.Window < 0 || c.History.Suggestions < 0 {
+		return errors.New("history window and suggestions must not be negative")
+	}
+
+	if err := c.MessageTemplate.validate(); err != nil {
+		return err
+	}
+
+	for _, project := range c.JIRAProjects {
+		if !jiraProjectKeyRegex.MatchString(project) {
+			return fmt.Errorf("jira_projects entry %q must be an uppercase JIRA project key such as CGC", project)
+		}
+	}
+
+	switch c.TicketProvider {
+	case "", "jira", "github", "linear", "azure":
+	default:
+		return fmt.Errorf("ticket_provider must be one of jira, github, linear or azure, got %q", c.TicketProvider)
+	}
+
+	switch c.TicketPlacement {
+	case "", "start", "end", "footer":
+	default:
+		return fmt.Errorf("ticket_placement must be start, end or footer, got %q", c.TicketPlacement)
+	}
+
+	if c.MaxTicketsPerCommit < 0 {
+		return errors.New("max_tickets_per_commit must not be negative")
+	}
+	if c.RequirePrimaryTicket && c.TicketPlacement == "footer" {
+		return errors.New("require_primary_ticket needs the ticket in the subject, which conflicts with ticket_placement: footer")
+	}
+
+	if c.JIRAURL != "" {
+		if u, err := url.ParseRequestURI(c.JIRAURL); err != nil || u.Host == "" {
+			return fmt.Errorf("jira_url must be an absolute URL, got %q", c.JIRAURL)
+		}
+	}
+
+	if sc := c.SmartCommits; sc.Transition != "" && len(sc.AllowedTransitions) > 0 && !containsFold(sc.AllowedTransitions, sc.Transition) {
+		return fmt.Errorf("smart_commits transition %q is not in allowed_transitions", sc.Transition)
+	}
+
+	if c.Webhook.URL != "" {
+		if u, err := url.ParseRequestURI(c.Webhook.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("webhook url must be an absolute http(s) URL, got %q", c.Webhook.URL)
+		}
+	}
+	for _, event := range c.Webhook.Events {
+		if event != "blocked" && event != "bypass" {
+			return fmt.Errorf("webhook events must be blocked or bypass, got %q", event)
+		}
+	}
+	if action := c.PrePush.Action; action != "" && action != PrePushRecord && action != PrePushBlock {
+		return fmt.Errorf("pre_push action must be %s or %s, got %q", PrePushRecord, PrePushBlock, action)
+	}
+
+	if c.Policy.PublicKey != "" {
+		if _, err := policy.ParsePublicKey(c.Policy.PublicKey); err != nil {
+			return fmt.Errorf("policy public_key: %w", err)
+		}
+	} else if c.Policy.Verify || c.Policy.URL != "" {
+		return errors.New("policy url and verify require a policy public_key")
+	}
+
+	switch c.Updates.Channel {
+	case "", update.Stable, update.Prerelease:
+	default:
+		return fmt.Errorf("updates channel must be %s or %s, got %q", update.Stable, update.Prerelease, c.Updates.Channel)
+	}
+	if owner, name, ok := strings.Cut(c.Updates.Repo, "/"); c.Updates.Repo != "" && (!ok || owner == "" || name == "" || strings.Contains(name, "/")) {
+		return fmt.Errorf("updates repo must be owner/name, got %q", c.Updates.Repo)
+	}
+	if c.Updates.PublicKey != "" {
+		if _, err := update.ParsePublicKey(c.Updates.PublicKey); err != nil {
+			return fmt.Errorf("updates public_key: %w", err)
+		}
+	}
+
+	for i, pattern := range c.TicketPatterns {
+		if pattern.Name == "" {
+			return fmt.Errorf("ticket pattern %d: name is required", i)
+		}
+		if _, err := conventionalcommit.CompilePattern(pattern.Pattern); pattern.Pattern == "" || err != nil {
+			return fmt.Errorf("ticket pattern %s: pattern must be a valid regular expression", pattern.Name)
+		}
+	}
+
 	// Validate custom rules.
 	for i, rule := range c.CustomRules {
 		if rule.Name == "" {
@@ -213,6 +749,19 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// jiraProjectKeyRegex matches JIRA project keys such as CGC or PLAT2.
+var jiraProjectKeyRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]+$`)
+
+// containsFold reports whether values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
 // HasType checks if a commit type is allowed.
 func (c *Config) HasType(t string) bool {
 	for _, allowed := range c.Types {
@@ -223,6 +772,30 @@ func (c *Config) HasType(t string) bool {
 	return false
 }
 
+// SubjectLengthFor returns the maximum subject length of commits of type t.
+func (c *Config) SubjectLengthFor(t string) int {
+	if rule, ok := c.TypeRules[t]; ok && rule.MaxSubjectLength > 0 {
+		return rule.MaxSubjectLength
+	}
+	return c.MaxSubjectLength
+}
+
+// ScopeRequiredFor reports whether commits of type t need a scope.
+func (c *Config) ScopeRequiredFor(t string) bool {
+	if rule, ok := c.TypeRules[t]; ok && rule.ScopeRequired != nil {
+		return *rule.ScopeRequired
+	}
+	return c.ScopeRequired
+}
+
+// DetectsBreakingAPI reports whether generated messages mark incompatible
+// changes to the exported Go API as breaking, as the go plugin's
+// detect_breaking_changes option does unless it or the plugin is disabled.
+func (c *Config) DetectsBreakingAPI() bool {
+	settings := c.Plugins["go"]
+	return settings["enabled"] != "false" && settings["detect_breaking_changes"] != "false"
+}
+
 // HasScope checks if a scope is allowed (returns true if no scopes defined).
 func (c *Config) HasScope(s string) bool {
 	if len(c.Scopes) == 0 {
@@ -235,3 +808,27 @@ func (c *Config) HasScope(s string) bool {
 	}
 	return false
 }
+
+// validate checks that the template only uses known variables and that its
+// own variables do not shadow the built-in ones.
+func (t MessageTemplateConfig) validate() error {
+	for name := range t.Variables {
+		if !templateNameRegex.MatchString(name) {
+			return fmt.Errorf("message_template variable %q must be lowercase letters and underscores", name)
+		}
+		if slices.Contains(TemplateVariables, name) {
+			return fmt.Errorf("message_template variable %q is built in", name)
+		}
+	}
+	for _, text := range append([]string{t.Header}, t.Footer...) {
+		for _, match := range templateVariableRegex.FindAllStringSubmatch(text, -1) {
+			if _, ok := t.Variables[match[1]]; !ok && !slices.Contains(TemplateVariables, match[1]) {
+				return fmt.Errorf("message_template uses unknown variable {%s} (known: %s)", match[1], strings.Join(TemplateVariables, ", "))
+			}
+		}
+	}
+	if strings.Contains(t.Header, "\n") {
+		return errors.New("message_template header must be a single line")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -55,6 +56,317 @@ func TestConfig_Validate(t *testing.T) {
 			name:    "negative max length",
 			wantErr: true,
 		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				MinConfidence:    1.5,
+			},
+			name:    "min confidence out of range",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Hotspots:         HotspotConfig{Window: 5, Threshold: 6},
+			},
+			name:    "hotspot threshold above window",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				ScopeSources:     ScopeSourcesConfig{From: []string{"codeowners", "lerna"}},
+			},
+			name:    "unknown scope source",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				ReviewTrailers:   ReviewTrailersConfig{RequireReviewedBy: []string{"release/[0-9"}},
+			},
+			name:    "invalid protected branch pattern",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				TypeLabels:       map[string]string{"feat": " "},
+			},
+			name:    "empty type label",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				TypeRules:        map[string]TypeRule{"docs": {MaxSubjectLength: 100}},
+			},
+			name:    "type rule",
+			wantErr: false,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				TypeRules:        map[string]TypeRule{"documentation": {MaxSubjectLength: 100}},
+			},
+			name:    "type rule for an unknown type",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				TypeRules:        map[string]TypeRule{"docs": {MaxSubjectLength: -1}},
+			},
+			name:    "negative type rule length",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				ScopeReviewers:   map[string][]string{"auth": {"@org/auth", ""}},
+			},
+			name:    "empty scope reviewer",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				SignedCommits:    SignedCommitsConfig{Types: []string{"revert"}},
+			},
+			name:    "signed commits without keys",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Checks:           []CheckConfig{{Name: "owners", Command: []string{"./check"}, Stage: "pre-commit"}},
+			},
+			name:    "unknown check stage",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Checks:           []CheckConfig{{Name: "owners"}},
+			},
+			name:    "check without command",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Tests:            []RuleTest{{Message: "feat: add login", Expect: "pass", Rules: []string{"scope"}}},
+			},
+			name:    "passing test with rules",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Tests:            []RuleTest{{Message: "feat: add login", Expect: "ok"}},
+			},
+			name:    "unknown test expectation",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Updates:          UpdatesConfig{Check: true, Channel: "nightly"},
+			},
+			name:    "unknown update channel",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Updates:          UpdatesConfig{Repo: "acme"},
+			},
+			name:    "update repo without owner",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				JIRAURL:          "jira.example.com",
+			},
+			name:    "relative jira url",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				TicketProvider:   "trello",
+			},
+			name:    "unknown ticket provider",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				TicketPlacement:  "subject",
+			},
+			name:    "unknown ticket placement",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:                DefaultTypes(),
+				MaxSubjectLength:     72,
+				RequirePrimaryTicket: true,
+				TicketPlacement:      "footer",
+			},
+			name:    "primary ticket with footer placement",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				JIRAProjects:     []string{"CGC", "plat"},
+			},
+			name:    "lowercase jira project",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				SmartCommits:     SmartCommitConfig{Transition: "Closed", AllowedTransitions: []string{"In Review"}},
+			},
+			name:    "smart commit transition not allowed",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Webhook:          WebhookConfig{URL: "https://hooks.slack.com/services/T0/B0/x", Events: []string{"blocked"}},
+			},
+			name:    "valid webhook",
+			wantErr: false,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Webhook:          WebhookConfig{URL: "ftp://example.com/hook"},
+			},
+			name:    "non-http webhook url",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Webhook:          WebhookConfig{URL: "https://example.com/hook", Events: []string{"pushed"}},
+			},
+			name:    "unknown webhook event",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				PrePush:          PrePushConfig{Action: PrePushBlock, Log: "/var/log/fcgh.log"},
+			},
+			name:    "blocking pre-push",
+			wantErr: false,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				PrePush:          PrePushConfig{Action: "warn"},
+			},
+			name:    "unknown pre-push action",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:             DefaultTypes(),
+				MaxSubjectLength:  72,
+				DuplicateSubjects: -1,
+			},
+			name:    "negative duplicate subjects",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				MessageTemplate: MessageTemplateConfig{
+					Header:    "{header} ({author_initials})",
+					Footer:    []string{"Package: {monorepo_package}", "Team: {team}"},
+					Variables: map[string]string{"team": "payments"},
+				},
+			},
+			name:    "message template",
+			wantErr: false,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				MessageTemplate:  MessageTemplateConfig{Footer: []string{"Team: {team}"}},
+			},
+			name:    "unknown message template variable",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				MessageTemplate:  MessageTemplateConfig{Variables: map[string]string{"branch": "main"}},
+			},
+			name:    "message template variable shadowing a built-in",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Policy:           PolicyConfig{Verify: true, PublicKey: "MCowBQYDK2VwAyEAGb9ECWmEzf6FQbrBZ9w7lshQhqowtrbLDFw4rXAxZuE="},
+			},
+			name:    "policy public key of the wrong size",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Policy:           PolicyConfig{Verify: true},
+			},
+			name:    "policy verification without a public key",
+			wantErr: true,
+		},
+		{
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				Policy:           PolicyConfig{Verify: true, PublicKey: "GfV5C5+gZDr2DRJrZjmto3Dv3TbUBeHcRCby1GrKfa8="},
+			},
+			name:    "valid policy",
+			wantErr: false,
+		},
 		{
 			name: "invalid custom rule - no name",
 			config: &Config{
@@ -77,6 +389,33 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid scope format case",
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				ScopeFormat:      ScopeFormatConfig{Case: "camel"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid subject length mode",
+			config: &Config{
+				Types:             DefaultTypes(),
+				MaxSubjectLength:  72,
+				SubjectLengthMode: "bytes",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid ticket pattern",
+			config: &Config{
+				Types:            DefaultTypes(),
+				MaxSubjectLength: 72,
+				TicketPatterns:   []TicketPattern{{Name: "SNOW", Pattern: "SNOW-("}},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -198,6 +537,29 @@ custom_rules:
 				},
 			},
 		},
+		{
+			name: "config with plugin sections",
+			yaml: `
+types:
+  - feat
+max_subject_length: 72
+plugins:
+  terraform:
+    provider_sensitivity: medium
+    priority: 5
+  kubernetes:
+    enabled: false
+`,
+			want: &Config{
+				Types:                []string{"feat"},
+				MaxSubjectLength:     72,
+				AllowBreakingChanges: true,
+				Plugins: map[string]map[string]string{
+					"terraform":  {"provider_sensitivity": "medium", "priority": "5"},
+					"kubernetes": {"enabled": "false"},
+				},
+			},
+		},
 		{
 			name: "empty yaml uses defaults",
 			yaml: "",
@@ -234,6 +596,9 @@ types: [
 				if got.ScopeRequired != tt.want.ScopeRequired {
 					t.Errorf("Parse() ScopeRequired = %v, want %v", got.ScopeRequired, tt.want.ScopeRequired)
 				}
+				if !reflect.DeepEqual(got.Plugins, tt.want.Plugins) {
+					t.Errorf("Parse() Plugins = %v, want %v", got.Plugins, tt.want.Plugins)
+				}
 			}
 		})
 	}
@@ -362,3 +727,397 @@ func BenchmarkConfig_Validate(b *testing.B) {
 		}
 	}
 }
+
+func TestLoadWithProvenance_AdminLocks(t *testing.T) {
+	tmpDir := t.TempDir()
+	adminPath := filepath.Join(tmpDir, "admin.yaml")
+	userPath := filepath.Join(tmpDir, "user.yaml")
+
+	original := AdminConfigPath
+	AdminConfigPath = adminPath
+	t.Cleanup(func() { AdminConfigPath = original })
+
+	admin := "max_subject_length: 60\nrequire_jira_ticket: true\nlocked: [max_subject_length, require_jira_ticket, types]\n"
+	if err := os.WriteFile(adminPath, []byte(admin), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	user := "max_subject_length: 100\nrequire_jira_ticket: true\nscope_required: true\ntypes: [feat, wip]\n"
+	if err := os.WriteFile(userPath, []byte(user), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, prov, err := LoadWithProvenance(userPath)
+	if err != nil {
+		t.Fatalf("LoadWithProvenance() error = %v", err)
+	}
+	if cfg.MaxSubjectLength != 60 || !cfg.RequireJIRATicket || !cfg.ScopeRequired {
+		t.Errorf("unexpected layered config %+v", cfg)
+	}
+	// A locked key the admin config leaves at its default keeps the default
+	if !reflect.DeepEqual(cfg.Types, DefaultTypes()) {
+		t.Errorf("Types = %v, want the locked default", cfg.Types)
+	}
+	if !cfg.IsLocked("types") || cfg.IsLocked("scope_required") {
+		t.Errorf("unexpected locks %v", cfg.Locked)
+	}
+
+	if got := prov.Source("max_subject_length"); got != "admin config "+adminPath+" (locked)" {
+		t.Errorf("Source(max_subject_length) = %q", got)
+	}
+	if got := prov.Source("scope_required"); got != "config "+userPath {
+		t.Errorf("Source(scope_required) = %q", got)
+	}
+	if got := prov.Source("types"); got != SourceDefault+" (locked)" {
+		t.Errorf("Source(types) = %q", got)
+	}
+	// Equal values are not reported as ignored overrides
+	if len(prov.Ignored) != 2 {
+		t.Errorf("expected ignored max_subject_length and types overrides, got %v", prov.Ignored)
+	}
+
+	if err := os.WriteFile(userPath, []byte("locked: []\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(userPath); err == nil {
+		t.Error("expected an error for locked outside the admin config")
+	}
+
+	if err := os.WriteFile(adminPath, []byte("locked: [no_such_key]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(userPath); err == nil {
+		t.Error("expected an error for an unknown locked key")
+	}
+}
+
+func TestLoadWithProvenance_Preset(t *testing.T) {
+	tmpDir := t.TempDir()
+	userPath := filepath.Join(tmpDir, "user.yaml")
+
+	original := AdminConfigPath
+	AdminConfigPath = filepath.Join(tmpDir, "admin.yaml")
+	t.Cleanup(func() { AdminConfigPath = original })
+
+	for _, name := range Presets() {
+		if _, err := Preset(name); err != nil {
+			t.Errorf("Preset(%q) error = %v", name, err)
+		}
+	}
+	if _, err := Preset("missing"); err == nil {
+		t.Error("expected an error for an unknown preset")
+	}
+
+	user := "extends: preset:angular\nmax_subject_length: 80\n"
+	if err := os.WriteFile(userPath, []byte(user), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, prov, err := LoadWithProvenance(userPath)
+	if err != nil {
+		t.Fatalf("LoadWithProvenance() error = %v", err)
+	}
+	if cfg.MaxSubjectLength != 80 || cfg.HasType("chore") || !cfg.HasType("perf") || !cfg.RequireBreakingDescription {
+		t.Errorf("unexpected config over the angular preset %+v", cfg)
+	}
+	if got := prov.Source("types"); got != "preset angular" {
+		t.Errorf("Source(types) = %q", got)
+	}
+	if got := prov.Source("max_subject_length"); got != "config "+userPath {
+		t.Errorf("Source(max_subject_length) = %q", got)
+	}
+
+	parsed, err := Parse(strings.NewReader(user))
+	if err != nil || parsed.MaxSubjectLength != 80 || parsed.HasType("chore") {
+		t.Errorf("Parse() = %+v, %v", parsed, err)
+	}
+
+	for _, extends := range []string{"preset:missing", "./base.yaml"} {
+		if err := os.WriteFile(userPath, []byte("extends: "+extends+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(userPath); err == nil {
+			t.Errorf("expected an error for extends %q", extends)
+		}
+	}
+}
+
+func TestMigration_Run(t *testing.T) {
+	dir := t.TempDir()
+	m := Migration{From: filepath.Join(dir, LegacyConfigFile), To: filepath.Join(dir, DefaultConfigFile)}
+	if err := os.WriteFile(m.From, []byte("types: [feat, fix]\nmax_subject_length: 60\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	linked, err := m.Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	cfg, err := Load(m.To)
+	if err != nil || cfg.MaxSubjectLength != 60 || !reflect.DeepEqual(cfg.Types, []string{"feat", "fix"}) {
+		t.Errorf("migrated config = %+v, %v", cfg, err)
+	}
+	if _, err := os.Stat(m.From + ".bak"); err != nil {
+		t.Errorf("expected the original to be kept: %v", err)
+	}
+	if linked {
+		if target, err := os.Readlink(m.From); err != nil || target != DefaultConfigFile {
+			t.Errorf("compatibility symlink = %q, %v", target, err)
+		}
+		if old, err := Load(m.From); err != nil || old.MaxSubjectLength != 60 {
+			t.Errorf("loading through the symlink = %+v, %v", old, err)
+		}
+	}
+
+	// An existing current config is never overwritten
+	if err := os.Remove(m.From); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(m.From, []byte("types: [feat]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Run(); err == nil {
+		t.Error("expected an error when the new config already exists")
+	}
+}
+
+func TestDocument_Set(t *testing.T) {
+	original := "# Allowed types\ntypes:\n  - feat # features\n\n# Maximum length\nmax_subject_length: 72 # header only\n\n# trailing note\n"
+	doc, err := ParseDocument([]byte(original))
+	if err != nil {
+		t.Fatalf("ParseDocument() error = %v", err)
+	}
+	if err := doc.Set("max_subject_length", 60); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Set("scopes", []string{"api"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Set("smart_commits.enabled", true); err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Has("scopes") || !doc.Has("smart_commits.enabled") || doc.Has("jira_projects") {
+		t.Error("Has() does not reflect the keys set")
+	}
+
+	want := "# Allowed types\ntypes:\n  - feat # features\n\n# Maximum length\nmax_subject_length: 60 # header only\n" +
+		"scopes:\n  - api\nsmart_commits:\n  enabled: true\n\n# trailing note\n"
+	if got := string(doc.Bytes()); got != want {
+		t.Errorf("Bytes() = %q, want %q", got, want)
+	}
+	cfg, err := doc.Config()
+	if err != nil || cfg.MaxSubjectLength != 60 || !reflect.DeepEqual(cfg.Scopes, []string{"api"}) || !cfg.SmartCommits.Enabled {
+		t.Errorf("edited config = %+v, %v", cfg, err)
+	}
+
+	// Unsetting the only key of a section removes the section
+	for _, key := range []string{"smart_commits.enabled", "scopes"} {
+		if ok, err := doc.Unset(key); !ok || err != nil {
+			t.Errorf("Unset(%s) = %v, %v", key, ok, err)
+		}
+	}
+	if ok, _ := doc.Unset("scopes"); ok {
+		t.Error("Unset() of a missing key reported it set")
+	}
+	if got := string(doc.Bytes()); got != strings.Replace(original, "72", "60", 1) {
+		t.Errorf("Bytes() after Unset = %q", got)
+	}
+
+	if err := doc.Set("types.feat", true); err == nil {
+		t.Error("expected an error setting a key below a list")
+	}
+	if _, err := ParseDocument([]byte("- feat\n")); err == nil {
+		t.Error("expected an error for a non-mapping document")
+	}
+}
+
+func TestDocument_FlowSection(t *testing.T) {
+	doc, err := ParseDocument([]byte("smart_commits: {enabled: true} # JIRA\ntypes: [feat]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Set("smart_commits.comment", "done"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc.Unset("smart_commits.enabled"); err != nil {
+		t.Fatal(err)
+	}
+	want := "smart_commits: # JIRA\n  comment: done\ntypes: [feat]\n"
+	if got := string(doc.Bytes()); got != want {
+		t.Errorf("Bytes() = %q, want %q", got, want)
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		path    string
+		value   string
+		want    any
+		wantErr bool
+	}{
+		{path: "scope_required", value: "true", want: true},
+		{path: "max_subject_length", value: "60", want: 60},
+		{path: "scopes", value: "api, web", want: []any{"api", "web"}},
+		{path: "scopes", value: "", want: []any{}},
+		{path: "jira_ticket_pattern", value: "[A-Z]+-[0-9]+", want: "[A-Z]+-[0-9]+"},
+		{path: "plugins.go.priority", value: "5", want: "5"},
+		{path: "max_subject_length", value: "sixty", wantErr: true},
+		{path: "smart_commits.unknown", value: "x", wantErr: true},
+		{path: "max_subject_length.value", value: "1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+"="+tt.value, func(t *testing.T) {
+			node, err := ParseValue(tt.path, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got any
+			if err := node.Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Lookup(t *testing.T) {
+	cfg := Default()
+	cfg.SmartCommits.Enabled = true
+	if got, err := cfg.Lookup("smart_commits.enabled"); err != nil || got != true {
+		t.Errorf("Lookup(smart_commits.enabled) = %v, %v", got, err)
+	}
+	if got, err := cfg.Lookup("max_subject_length"); err != nil || got != DefaultMaxSubjectLength {
+		t.Errorf("Lookup(max_subject_length) = %v, %v", got, err)
+	}
+	if _, err := cfg.Lookup("nope"); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
+
+func TestDocument_AddMissing(t *testing.T) {
+	doc, err := ParseDocument([]byte("# Team types\ntypes: [feat, fix] # short list\nmax_subject_length: 50\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	added, err := doc.AddMissing(Default())
+	if err != nil {
+		t.Fatalf("AddMissing() error = %v", err)
+	}
+	if len(added) == 0 || slices.Contains(added, "types") || slices.Contains(added, "max_subject_length") {
+		t.Errorf("AddMissing() added = %v", added)
+	}
+
+	data := doc.Bytes()
+	if !strings.Contains(string(data), "# Team types\n") || !strings.Contains(string(data), "# short list") {
+		t.Errorf("comments lost: %q", data)
+	}
+	cfg, err := Parse(strings.NewReader(string(data)))
+	if err != nil || cfg.MaxSubjectLength != 50 || !reflect.DeepEqual(cfg.Types, []string{"feat", "fix"}) || !cfg.AllowBreakingChanges {
+		t.Errorf("merged config = %+v, %v", cfg, err)
+	}
+
+	if again, _ := doc.AddMissing(Default()); len(again) != 0 {
+		t.Errorf("second AddMissing() added = %v", again)
+	}
+}
+
+func TestLoadWithProvenance_EnvOverrides(t *testing.T) {
+	tmpDir := t.TempDir()
+	adminPath := filepath.Join(tmpDir, "admin.yaml")
+	userPath := filepath.Join(tmpDir, "user.yaml")
+
+	original := AdminConfigPath
+	AdminConfigPath = adminPath
+	t.Cleanup(func() { AdminConfigPath = original })
+
+	if err := os.WriteFile(adminPath, []byte("max_subject_length: 60\nlocked: [max_subject_length]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(userPath, []byte("scope_required: false\nscopes: [api]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("FCGH_SCOPE_REQUIRED", "true")
+	t.Setenv("FCGH_TYPES", "feat, fix")
+	t.Setenv("FCGH_SCOPES", "")
+	t.Setenv("FCGH_TICKET_PLACEMENT", "end")
+	t.Setenv("FCGH_MAX_SUBJECT_LENGTH", "100")
+	t.Setenv("FCGH_CUSTOM_RULES", "[{name: no-wip, pattern: '^(?!.*WIP)'}]")
+
+	cfg, prov, err := LoadWithProvenance(userPath)
+	if err != nil {
+		t.Fatalf("LoadWithProvenance() error = %v", err)
+	}
+	if !cfg.ScopeRequired || !reflect.DeepEqual(cfg.Types, []string{"feat", "fix"}) || len(cfg.Scopes) != 0 ||
+		cfg.TicketPlacement != "end" || len(cfg.CustomRules) != 1 || cfg.CustomRules[0].Name != "no-wip" {
+		t.Errorf("unexpected config with environment overrides %+v", cfg)
+	}
+	if got := prov.Source("scope_required"); got != "environment FCGH_SCOPE_REQUIRED" {
+		t.Errorf("Source(scope_required) = %q", got)
+	}
+
+	// Locked keys ignore the environment
+	if cfg.MaxSubjectLength != 60 || len(prov.Ignored) != 1 {
+		t.Errorf("MaxSubjectLength = %d, ignored %v", cfg.MaxSubjectLength, prov.Ignored)
+	}
+
+	t.Setenv("FCGH_SCOPE_REQUIRED", "sometimes")
+	if _, err := Load(userPath); err == nil || !strings.Contains(err.Error(), "FCGH_SCOPE_REQUIRED") {
+		t.Errorf("expected an error naming FCGH_SCOPE_REQUIRED, got %v", err)
+	}
+}
+
+func TestLoadWithProvenance_RepositoryChecks(t *testing.T) {
+	tmpDir := t.TempDir()
+	original := AdminConfigPath
+	AdminConfigPath = filepath.Join(tmpDir, "admin.yaml")
+	t.Cleanup(func() { AdminConfigPath = original })
+	userDir := filepath.Join(tmpDir, "user")
+	t.Setenv("FCGH_CONFIG_DIR", userDir)
+	repo := filepath.Join(tmpDir, "repo")
+	if err := os.MkdirAll(repo, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(repo)
+
+	content := []byte("scopes: [api]\nchecks:\n  - name: evil\n    command: [sh, -c, 'touch pwned']\n")
+	if err := os.WriteFile(DefaultConfigFile, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Found in the current directory only: the checks are refused
+	cfg, prov, err := LoadWithProvenance("")
+	if err != nil {
+		t.Fatalf("LoadWithProvenance() error = %v", err)
+	}
+	if len(cfg.Checks) != 0 {
+		t.Errorf("Checks = %+v from a repository config, want none", cfg.Checks)
+	}
+	if !reflect.DeepEqual(cfg.Scopes, []string{"api"}) {
+		t.Errorf("Scopes = %v, want the rest of the repository config applied", cfg.Scopes)
+	}
+	if len(prov.Ignored) != 1 || !strings.Contains(prov.Ignored[0], "checks") {
+		t.Errorf("Ignored = %v, want the refused checks reported", prov.Ignored)
+	}
+
+	// Passed explicitly, it is trusted
+	if cfg, err := Load(DefaultConfigFile); err != nil || len(cfg.Checks) != 1 {
+		t.Errorf("Load(%s) checks = %v, %v, want the explicit config's check", DefaultConfigFile, cfg, err)
+	}
+
+	// So is the user config
+	if err := os.MkdirAll(userDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(userDir, DefaultConfigFile), content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err := Load(""); err != nil || len(cfg.Checks) != 1 {
+		t.Errorf("Load() of the user config checks = %v, %v, want its check", cfg, err)
+	}
+}
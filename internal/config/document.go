@@ -0,0 +1,324 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a config file parsed for editing. Unlike Load and Save it
+// rewrites only the entries it changes, so comments, key order and
+// formatting elsewhere in the file are kept as written.
+type Document struct {
+	lines []string
+	root  *yaml.Node
+}
+
+// ParseDocument parses config file content for editing. Empty content is an
+// empty document.
+func ParseDocument(data []byte) (*Document, error) {
+	d := &Document{}
+	if text := strings.TrimSuffix(string(data), "\n"); text != "" {
+		d.lines = strings.Split(text, "\n")
+	}
+	if err := d.parse(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// parse rebuilds the node tree, whose line numbers locate entries, after
+// every edit.
+func (d *Document) parse() error {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(d.lines, "\n")), &root); err != nil {
+		return fmt.Errorf("parsing config: %w", err)
+	}
+	if root.Kind == 0 {
+		root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if len(root.Content) != 1 || root.Content[0].Kind != yaml.MappingNode {
+		return errors.New("parsing config: top level must be a mapping")
+	}
+	if root.Content[0].Style&yaml.FlowStyle != 0 {
+		return errors.New("parsing config: top level must be a block mapping")
+	}
+	d.root = &root
+	return nil
+}
+
+// step is one mapping on a dot path and the position of the path's key in
+// it, or -1.
+type step struct {
+	mapping *yaml.Node
+	index   int
+}
+
+// lookup walks a dot path, returning the steps taken; it stops early at a
+// missing key or a value that is not a mapping.
+func (d *Document) lookup(parts []string) []step {
+	mapping := d.root.Content[0]
+	var steps []step
+	for n, part := range parts {
+		i := index(mapping, part)
+		steps = append(steps, step{mapping: mapping, index: i})
+		if i < 0 || n == len(parts)-1 || mapping.Content[i+1].Kind != yaml.MappingNode {
+			break
+		}
+		mapping = mapping.Content[i+1]
+	}
+	return steps
+}
+
+// index returns the position of key's node in a mapping, or -1.
+func index(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Has reports whether the document sets a key, given as a dot path.
+func (d *Document) Has(path string) bool {
+	parts := strings.Split(path, ".")
+	steps := d.lookup(parts)
+	return len(steps) == len(parts) && steps[len(steps)-1].index >= 0
+}
+
+// Set sets a key, given as a dot path such as "smart_commits.enabled",
+// keeping the comments around an existing entry. Missing keys and sections
+// are added after the last key of their section.
+func (d *Document) Set(path string, value any) error {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return fmt.Errorf("encoding %s: %w", path, err)
+	}
+
+	parts := strings.Split(path, ".")
+	steps := d.lookup(parts)
+	last := steps[len(steps)-1]
+	n := len(steps) - 1
+
+	switch {
+	case last.mapping.Style&yaml.FlowStyle != 0:
+		return d.setFlow(steps, parts, &node)
+	case last.index < 0:
+		// Add the rest of the path as a new entry
+		return d.insert(last.mapping, parts[n], section(parts[n+1:], &node))
+	case n < len(parts)-1:
+		// The path continues below a value that is not a section
+		if last.mapping.Content[last.index+1].Tag != "!!null" {
+			return fmt.Errorf("%s is not a section", strings.Join(parts[:n+1], "."))
+		}
+		return d.replace(last.mapping, last.index, section(parts[n+1:], &node))
+	default:
+		if existing := last.mapping.Content[last.index+1]; node.Kind == yaml.ScalarNode {
+			node.LineComment = existing.LineComment
+		}
+		return d.replace(last.mapping, last.index, &node)
+	}
+}
+
+// setFlow sets a key inside a flow mapping such as "smart_commits: {...}" by
+// rewriting the outermost flow section of the path as a block.
+func (d *Document) setFlow(steps []step, parts []string, value *yaml.Node) error {
+	outer := flowStart(steps)
+	parent := steps[outer-1]
+	sectionNode := parent.mapping.Content[parent.index+1]
+	setNode(sectionNode, parts[outer:], value)
+	clearFlow(sectionNode)
+	return d.replace(parent.mapping, parent.index, sectionNode)
+}
+
+// flowStart returns the first step whose mapping is in flow style.
+func flowStart(steps []step) int {
+	outer := 0
+	for steps[outer].mapping.Style&yaml.FlowStyle == 0 {
+		outer++
+	}
+	return outer
+}
+
+// setNode sets a dot path below mapping in the node tree.
+func setNode(mapping *yaml.Node, parts []string, value *yaml.Node) {
+	i := index(mapping, parts[0])
+	switch {
+	case i < 0:
+		mapping.Content = append(mapping.Content, scalar(parts[0]), section(parts[1:], value))
+	case len(parts) == 1 || mapping.Content[i+1].Kind != yaml.MappingNode:
+		mapping.Content[i+1] = section(parts[1:], value)
+	default:
+		setNode(mapping.Content[i+1], parts[1:], value)
+	}
+}
+
+// Unset removes a key, given as a dot path, along with sections it leaves
+// empty and comment lines directly above it. It reports whether the key was
+// set.
+func (d *Document) Unset(path string) (bool, error) {
+	parts := strings.Split(path, ".")
+	steps := d.lookup(parts)
+	last := steps[len(steps)-1]
+	if len(steps) != len(parts) || last.index < 0 {
+		return false, nil
+	}
+
+	if len(last.mapping.Content) == 2 && len(parts) > 1 {
+		return d.Unset(strings.Join(parts[:len(parts)-1], "."))
+	}
+	if last.mapping.Style&yaml.FlowStyle != 0 {
+		last.mapping.Content = append(last.mapping.Content[:last.index], last.mapping.Content[last.index+2:]...)
+		parent := steps[flowStart(steps)-1]
+		sectionNode := parent.mapping.Content[parent.index+1]
+		clearFlow(sectionNode)
+		return true, d.replace(parent.mapping, parent.index, sectionNode)
+	}
+
+	start, end := d.span(last.mapping, last.index)
+	for start > 0 && strings.HasPrefix(strings.TrimSpace(d.lines[start-1]), "#") {
+		start--
+	}
+	// Don't leave two blank lines where the entry was
+	if start > 0 && end+1 < len(d.lines) && strings.TrimSpace(d.lines[start-1]) == "" && strings.TrimSpace(d.lines[end+1]) == "" {
+		end++
+	}
+	d.lines = append(d.lines[:start], d.lines[end+1:]...)
+	return true, d.parse()
+}
+
+// AddMissing adds the keys of defaults the document does not set, leaving
+// every existing key and comment alone. It returns the added keys in order.
+func (d *Document) AddMissing(defaults *Config) ([]string, error) {
+	var node yaml.Node
+	if err := node.Encode(defaults); err != nil {
+		return nil, fmt.Errorf("encoding defaults: %w", err)
+	}
+
+	var added []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if d.Has(key) {
+			continue
+		}
+		if err := d.insert(d.root.Content[0], key, node.Content[i+1]); err != nil {
+			return nil, err
+		}
+		added = append(added, key)
+	}
+	return added, nil
+}
+
+// insert adds an entry after the last entry of a block mapping, or at the
+// end of an empty document.
+func (d *Document) insert(mapping *yaml.Node, key string, value *yaml.Node) error {
+	at, indent := len(d.lines), 0
+	if n := len(mapping.Content); n > 0 {
+		_, end := d.span(mapping, n-2)
+		at = end + 1
+		indent = mapping.Content[n-2].Column - 1
+	}
+	entry, err := encodeEntry(scalar(key), value, indent)
+	if err != nil {
+		return err
+	}
+	d.lines = append(d.lines[:at], append(entry, d.lines[at:]...)...)
+	return d.parse()
+}
+
+// replace rewrites the entry at position i of a mapping with a new value,
+// keeping the comment on the key's line.
+func (d *Document) replace(mapping *yaml.Node, i int, value *yaml.Node) error {
+	key := mapping.Content[i]
+	comment := key.LineComment
+	if value.Kind != yaml.ScalarNode && value.Style&yaml.FlowStyle == 0 && value.LineComment != "" {
+		// A block value starts on the next line, so its comment moves up
+		comment, value.LineComment = value.LineComment, ""
+	}
+	start, end := d.span(mapping, i)
+	entry, err := encodeEntry(&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.Value, LineComment: comment}, value, key.Column-1)
+	if err != nil {
+		return err
+	}
+	d.lines = append(d.lines[:start], append(entry, d.lines[end+1:]...)...)
+	return d.parse()
+}
+
+// span returns the first and last line (0-based) of the entry at position i
+// of a mapping, leaving out comments below it.
+func (d *Document) span(mapping *yaml.Node, i int) (int, int) {
+	return mapping.Content[i].Line - 1, lastLine(mapping.Content[i+1]) - 1
+}
+
+// lastLine returns the last line (1-based) a value occupies.
+func lastLine(node *yaml.Node) int {
+	line := node.Line
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		line += strings.Count(strings.TrimSuffix(node.Value, "\n"), "\n") + 1
+	}
+	for _, child := range node.Content {
+		if l := lastLine(child); l > line {
+			line = l
+		}
+	}
+	return line
+}
+
+// encodeEntry encodes "key: value" as lines indented by indent spaces.
+func encodeEntry(key, value *yaml.Node, indent int) ([]string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{key, value}}); err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", key.Value, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", key.Value, err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	prefix := strings.Repeat(" ", indent)
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return lines, nil
+}
+
+// section nests value under the keys of a dot path ("a.b" -> a: {b: value}).
+func section(parts []string, value *yaml.Node) *yaml.Node {
+	for i := len(parts) - 1; i >= 0; i-- {
+		value = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{scalar(parts[i]), value}}
+	}
+	return value
+}
+
+// clearFlow switches a node tree to block style.
+func clearFlow(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle
+	for _, child := range node.Content {
+		clearFlow(child)
+	}
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// Config parses the document as a config, validating it.
+func (d *Document) Config() (*Config, error) {
+	return Parse(bytes.NewReader(d.Bytes()))
+}
+
+// Bytes returns the document content.
+func (d *Document) Bytes() []byte {
+	if len(d.lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(d.lines, "\n") + "\n")
+}
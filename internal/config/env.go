@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes the environment variables that override config keys,
+// such as FCGH_SCOPE_REQUIRED for scope_required.
+const EnvPrefix = "FCGH_"
+
+// EnvName returns the environment variable that overrides a config key.
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(key)
+}
+
+// applyEnv applies FCGH_* overrides over every config file, so CI jobs can
+// tighten or relax rules without editing the repository. Values are parsed
+// like `fcgh config set` values (see ParseValue). Locked keys keep their
+// admin values.
+func applyEnv(cfg, adminCfg *Config, prov *Provenance) error {
+	fields := configFields()
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	t := reflect.TypeOf(Config{})
+	for _, key := range keys {
+		// Presets are only extended by config files
+		if key == "locked" || key == "extends" {
+			continue
+		}
+		name := EnvName(key)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		// An empty value clears a list and is otherwise ignored
+		field := t.Field(fields[key]).Type
+		if strings.TrimSpace(value) == "" && field.Kind() != reflect.Slice {
+			continue
+		}
+		node, err := parseValue(field, value)
+		if err != nil {
+			return fmt.Errorf("environment %s: %w", name, err)
+		}
+		layer := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, node,
+		}}
+		if err := applyLayer(cfg, adminCfg, layer, "environment "+name, prov); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KeyType returns the type of the config value at a dot path such as
+// "scope_required", "smart_commits.enabled" or "plugins.go.priority".
+func KeyType(path string) (reflect.Type, error) {
+	t := reflect.TypeOf(Config{})
+	walked := ""
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			return nil, fmt.Errorf("invalid config key %q", path)
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			index, ok := fieldsOf(t)[part]
+			if !ok {
+				return nil, fmt.Errorf("unknown config key %q", strings.TrimPrefix(walked+"."+part, "."))
+			}
+			t = t.Field(index).Type
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("%s is not a section; cannot address %q", walked, path)
+		}
+		walked = strings.TrimPrefix(walked+"."+part, ".")
+	}
+	return t, nil
+}
+
+// ParseValue converts a command-line value for the key at path to YAML,
+// following the key's type: strings are taken as is, string lists may be
+// comma-separated, and other values are YAML ("true", "60", or flow syntax
+// such as "[{name: x, pattern: y}]").
+func ParseValue(path, value string) (*yaml.Node, error) {
+	t, err := KeyType(path)
+	if err != nil {
+		return nil, err
+	}
+	node, err := parseValue(t, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := node.Decode(reflect.New(t).Interface()); err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", path, err)
+	}
+	return node, nil
+}
+
+// Lookup returns the value of the key at path in cfg; keys of map sections
+// that are not set yield the zero value.
+func (c *Config) Lookup(path string) (any, error) {
+	if _, err := KeyType(path); err != nil {
+		return nil, err
+	}
+	v := reflect.ValueOf(c).Elem()
+	for _, part := range strings.Split(path, ".") {
+		switch v.Kind() {
+		case reflect.Struct:
+			v = v.Field(fieldsOf(v.Type())[part])
+		case reflect.Map:
+			elem := v.MapIndex(reflect.ValueOf(part))
+			if !elem.IsValid() {
+				elem = reflect.Zero(v.Type().Elem())
+			}
+			v = elem
+		}
+	}
+	return v.Interface(), nil
+}
+
+// parseValue converts value to a YAML node for a field of type t. An empty
+// value is an empty list or string.
+func parseValue(t reflect.Type, value string) (*yaml.Node, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "" && t.Kind() == reflect.Slice:
+		return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}, nil
+	case t.Kind() == reflect.String:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String && !strings.HasPrefix(value, "["):
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		}
+		return seq, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+		return nil, fmt.Errorf("parsing value: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("empty value")
+	}
+	return doc.Content[0], nil
+}
+
+// fieldsOf maps the YAML keys of a struct type to its field indexes.
+func fieldsOf(t reflect.Type) map[string]int {
+	fields := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			fields[name] = i
+		}
+	}
+	return fields
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourceDefault describes settings no config file sets.
+const SourceDefault = "built-in default"
+
+// AdminConfigPath is the admin-managed config applied beneath every other
+// config file. Keys it lists under "locked" cannot be changed by user or
+// repository config files or by environment variables.
+var AdminConfigPath = defaultAdminConfigPath()
+
+func defaultAdminConfigPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "fast-cc", DefaultConfigFile)
+	}
+	return filepath.Join("/etc", "fast-cc", DefaultConfigFile)
+}
+
+// Provenance records which config layer set each key of a loaded config.
+type Provenance struct {
+	sources map[string]string
+	locked  map[string]bool
+	// Ignored describes values from other layers that were discarded because
+	// the admin config locks their key.
+	Ignored []string
+}
+
+// Source describes the layer that set a config key, such as
+// "admin config /etc/fast-cc/fast-cc-config.yaml (locked)".
+func (p *Provenance) Source(key string) string {
+	source, ok := p.sources[key]
+	if !ok {
+		source = SourceDefault
+	}
+	if p.locked[key] {
+		source += " (locked)"
+	}
+	return source
+}
+
+// IsSet reports whether a config file or environment variable set the key.
+func (p *Provenance) IsSet(key string) bool {
+	_, ok := p.sources[key]
+	return ok
+}
+
+// IsLocked reports whether the admin config locks a config key.
+func (c *Config) IsLocked(key string) bool {
+	for _, locked := range c.Locked {
+		if locked == key {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadWithProvenance loads the config like Load and also reports which layer
+// set each key.
+func LoadWithProvenance(path string) (*Config, *Provenance, error) {
+	explicit := path != ""
+	path = resolvePath(path)
+	prov := &Provenance{sources: map[string]string{}, locked: map[string]bool{}}
+
+	cfg := Default()
+	admin, err := readLayer(AdminConfigPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading admin config: %w", err)
+	}
+	adminCfg := Default()
+	if admin != nil {
+		preset, name, err := presetOf(admin)
+		if err != nil {
+			return nil, nil, fmt.Errorf("admin config %s: %w", AdminConfigPath, err)
+		}
+		if preset != nil {
+			if err := preset.Decode(cfg); err != nil {
+				return nil, nil, fmt.Errorf("parsing preset %s: %w", name, err)
+			}
+			if err := preset.Decode(adminCfg); err != nil {
+				return nil, nil, fmt.Errorf("parsing preset %s: %w", name, err)
+			}
+			for _, key := range mappingKeys(preset) {
+				prov.sources[key] = "preset " + name
+			}
+		}
+		if err := admin.Decode(cfg); err != nil {
+			return nil, nil, fmt.Errorf("parsing admin config %s: %w", AdminConfigPath, err)
+		}
+		// Decoded separately so locked values can be restored after the
+		// other layers are applied
+		if err := admin.Decode(adminCfg); err != nil {
+			return nil, nil, fmt.Errorf("parsing admin config %s: %w", AdminConfigPath, err)
+		}
+		for _, key := range mappingKeys(admin) {
+			prov.sources[key] = "admin config " + AdminConfigPath
+		}
+		for _, key := range adminCfg.Locked {
+			if _, ok := configFields()[key]; !ok {
+				return nil, nil, fmt.Errorf("admin config %s locks unknown key %q", AdminConfigPath, key)
+			}
+			prov.locked[key] = true
+		}
+	}
+
+	if filepath.Clean(path) != filepath.Clean(AdminConfigPath) {
+		layer, err := readLayer(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if layer != nil {
+			preset, name, err := presetOf(layer)
+			if err != nil {
+				return nil, nil, fmt.Errorf("config %s: %w", path, err)
+			}
+			if !explicit && !isUserConfig(path) {
+				dropChecks(layer, path, prov)
+			}
+			if preset != nil {
+				if err := applyLayer(cfg, adminCfg, preset, "preset "+name, prov); err != nil {
+					return nil, nil, err
+				}
+			}
+			if err := applyLayer(cfg, adminCfg, layer, "config "+path, prov); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if err := applyEnv(cfg, adminCfg, prov); err != nil {
+		return nil, nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, prov, nil
+}
+
+// applyLayer decodes a user or repository config file (or an environment
+// override) over cfg, then restores the admin values of locked keys. source
+// names the layer in provenance and messages.
+func applyLayer(cfg, adminCfg *Config, layer *yaml.Node, source string, prov *Provenance) error {
+	if err := layer.Decode(cfg); err != nil {
+		return fmt.Errorf("parsing %s: %w", source, err)
+	}
+
+	fields := configFields()
+	for _, key := range mappingKeys(layer) {
+		if key == "locked" {
+			return fmt.Errorf("%s: \"locked\" can only be set in the admin config %s", source, AdminConfigPath)
+		}
+		if !prov.locked[key] {
+			prov.sources[key] = source
+			continue
+		}
+
+		index, ok := fields[key]
+		if !ok {
+			continue
+		}
+		got := reflect.ValueOf(cfg).Elem().Field(index)
+		want := reflect.ValueOf(adminCfg).Elem().Field(index)
+		if !reflect.DeepEqual(got.Interface(), want.Interface()) {
+			prov.Ignored = append(prov.Ignored, fmt.Sprintf("%s is locked by the admin config; ignoring the value from %s", key, source))
+		}
+		got.Set(want)
+	}
+	return nil
+}
+
+// isUserConfig reports whether path is in the user's config directory, as
+// opposed to a config found in the current directory, which may come with a
+// cloned repository.
+func isUserConfig(path string) bool {
+	dir, err := GetDefaultConfigDir()
+	if err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	absDir, err := filepath.Abs(dir)
+	return err == nil && filepath.Dir(abs) == absDir
+}
+
+// dropChecks removes the checks of a config found in the current directory:
+// they run programs on every commit, so a repository must not bring its own.
+// Only the admin config, the user config and a config passed explicitly with
+// --config may set them.
+func dropChecks(layer *yaml.Node, path string, prov *Provenance) {
+	if layer.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(layer.Content); i += 2 {
+		if layer.Content[i].Value == "checks" {
+			layer.Content = append(layer.Content[:i], layer.Content[i+2:]...)
+			prov.Ignored = append(prov.Ignored, fmt.Sprintf("checks in %s are ignored: repository configs may not run programs; move them to the user or admin config, or pass --config %s", path, path))
+			return
+		}
+	}
+}
+
+// readLayer reads a config file as a YAML mapping; a missing file yields nil.
+func readLayer(path string) (*yaml.Node, error) {
+	file, err := os.Open(path) // #nosec G304 - path is validated by caller
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening config file: %w", err)
+	}
+	defer file.Close()
+
+	var doc yaml.Node
+	if err := yaml.NewDecoder(file).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return &yaml.Node{Kind: yaml.MappingNode}, nil
+		}
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode}, nil
+	}
+	return doc.Content[0], nil
+}
+
+// mappingKeys returns the top-level keys of a YAML mapping.
+func mappingKeys(node *yaml.Node) []string {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	keys := make([]string, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return keys
+}
+
+// configFields maps YAML keys to Config field indexes.
+func configFields() map[string]int {
+	return fieldsOf(reflect.TypeOf(Config{}))
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LegacyConfigFile is the config filename used before fast-cc-config.yaml.
+const LegacyConfigFile = ".fast-cc-hooks.yaml"
+
+// FindConfigFile returns the config file used when none is given: the home
+// directory config, else the current directory's, preferring the current
+// filename over LegacyConfigFile in each. found is false when none exists.
+func FindConfigFile() (path string, legacy, found bool) {
+	var dirs []string
+	if home, err := GetDefaultConfigDir(); err == nil {
+		dirs = append(dirs, home)
+	}
+	dirs = append(dirs, "")
+
+	for _, dir := range dirs {
+		for _, name := range []string{DefaultConfigFile, LegacyConfigFile} {
+			candidate := filepath.Join(dir, name)
+			if _, err := os.Stat(candidate); err == nil {
+				return candidate, name == LegacyConfigFile, true
+			}
+		}
+	}
+	return "", false, false
+}
+
+// Migration converts one legacy config file to the current filename.
+type Migration struct {
+	From string
+	To   string
+}
+
+// LegacyConfigs returns the legacy config files in the home config directory
+// and the current directory that have not been migrated yet. Migrated files
+// are left behind as symlinks and are skipped.
+func LegacyConfigs() []Migration {
+	var dirs []string
+	if home, err := GetDefaultConfigDir(); err == nil {
+		dirs = append(dirs, home)
+	}
+	dirs = append(dirs, ".")
+
+	var migrations []Migration
+	for _, dir := range dirs {
+		from := filepath.Join(dir, LegacyConfigFile)
+		if info, err := os.Lstat(from); err == nil && info.Mode().IsRegular() {
+			migrations = append(migrations, Migration{From: from, To: filepath.Join(dir, DefaultConfigFile)})
+		}
+	}
+	return migrations
+}
+
+// Run writes the legacy config in the current schema to To, keeps the
+// original as From+".bak" and replaces From with a symlink to To so older
+// tools keep working. linked is false when the platform does not allow the
+// symlink; the config is migrated either way.
+func (m Migration) Run() (linked bool, err error) {
+	if _, err := os.Stat(m.To); err == nil {
+		return false, fmt.Errorf("%s already exists; merge %s into it by hand", m.To, m.From)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("checking %s: %w", m.To, err)
+	}
+
+	file, err := os.Open(m.From) // #nosec G304 - path is a fixed legacy location
+	if err != nil {
+		return false, fmt.Errorf("opening legacy config: %w", err)
+	}
+	cfg, err := Parse(file)
+	file.Close()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", m.From, err)
+	}
+
+	if err := cfg.Save(m.To); err != nil {
+		return false, fmt.Errorf("writing %s: %w", m.To, err)
+	}
+	if err := os.Rename(m.From, m.From+".bak"); err != nil {
+		return false, fmt.Errorf("backing up %s: %w", m.From, err)
+	}
+	// Relative target: both files live in the same directory
+	if err := os.Symlink(filepath.Base(m.To), m.From); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
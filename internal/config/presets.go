@@ -0,0 +1,88 @@
+package config
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PresetPrefix marks an extends value naming a built-in preset, as in
+// "extends: preset:angular".
+const PresetPrefix = "preset:"
+
+//go:embed presets/*.yaml
+var presetFiles embed.FS
+
+// Presets returns the names of the built-in presets, sorted.
+func Presets() []string {
+	entries, err := presetFiles.ReadDir("presets")
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Preset returns the defaults with the built-in preset name applied.
+func Preset(name string) (*Config, error) {
+	layer, err := presetLayer(name)
+	if err != nil {
+		return nil, err
+	}
+	cfg := Default()
+	if err := layer.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("parsing preset %s: %w", name, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid preset %s: %w", name, err)
+	}
+	return cfg, nil
+}
+
+// presetLayer reads a built-in preset as a YAML mapping.
+func presetLayer(name string) (*yaml.Node, error) {
+	if !slices.Contains(Presets(), name) {
+		return nil, fmt.Errorf("unknown preset %q (available: %s)", name, strings.Join(Presets(), ", "))
+	}
+	data, err := presetFiles.ReadFile(path.Join("presets", name+".yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("reading preset %s: %w", name, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing preset %s: %w", name, err)
+	}
+	return doc.Content[0], nil
+}
+
+// presetOf returns the preset a config layer extends and its name, or nil
+// when the layer has no extends key.
+func presetOf(layer *yaml.Node) (*yaml.Node, string, error) {
+	extends := ""
+	for i := 0; i+1 < len(layer.Content); i += 2 {
+		if layer.Content[i].Value == "extends" {
+			extends = layer.Content[i+1].Value
+		}
+	}
+	if extends == "" {
+		return nil, "", nil
+	}
+	name, ok := strings.CutPrefix(extends, PresetPrefix)
+	if !ok {
+		return nil, "", fmt.Errorf("extends must name a preset, as in %sangular, got %q", PresetPrefix, extends)
+	}
+	preset, err := presetLayer(name)
+	if err != nil {
+		return nil, "", err
+	}
+	return preset, name, nil
+}
@@ -0,0 +1,135 @@
+// Package dirs locates the fast-cc config, cache and binary directories
+// following the XDG base directory specification.
+package dirs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	// Name is the directory created under the XDG config and cache homes.
+	Name = "fast-cc"
+	// LegacyName is the pre-XDG config directory in the home directory.
+	LegacyName = ".fast-cc"
+	// EnvConfigDir overrides the config directory.
+	EnvConfigDir = "FCGH_CONFIG_DIR"
+)
+
+// Config returns the config directory: FCGH_CONFIG_DIR when set, else
+// $XDG_CONFIG_HOME/fast-cc (~/.config/fast-cc). A legacy ~/.fast-cc stays in
+// use until it is migrated, as long as the XDG directory does not exist.
+func Config() (string, error) {
+	if dir := os.Getenv(EnvConfigDir); dir != "" {
+		return dir, nil
+	}
+	dir, err := XDGConfig()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		if legacy, err := Legacy(); err == nil && isDir(legacy) {
+			return legacy, nil
+		}
+	}
+	return dir, nil
+}
+
+// XDGConfig returns $XDG_CONFIG_HOME/fast-cc, defaulting to ~/.config/fast-cc
+// (%AppData%\fast-cc on Windows).
+func XDGConfig() (string, error) {
+	return baseDir("XDG_CONFIG_HOME", ".config", os.UserConfigDir)
+}
+
+// Cache returns $XDG_CACHE_HOME/fast-cc, defaulting to ~/.cache/fast-cc
+// (%LocalAppData%\fast-cc on Windows).
+func Cache() (string, error) {
+	return baseDir("XDG_CACHE_HOME", ".cache", os.UserCacheDir)
+}
+
+// Bin returns the directory `fcgh setup --install-binary` copies fcgh to,
+// $XDG_DATA_HOME/fast-cc/bin, defaulting to ~/.local/share/fast-cc/bin
+// (%LocalAppData%\fast-cc\bin on Windows).
+func Bin() (string, error) {
+	dir, err := baseDir("XDG_DATA_HOME", filepath.Join(".local", "share"), os.UserCacheDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bin"), nil
+}
+
+// Legacy returns the pre-XDG config directory, ~/.fast-cc.
+func Legacy() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	return filepath.Join(home, LegacyName), nil
+}
+
+// LegacyMigration returns the legacy directory and its XDG destination when
+// ~/.fast-cc still needs moving; ok is false when there is nothing to do.
+func LegacyMigration() (from, to string, ok bool) {
+	if os.Getenv(EnvConfigDir) != "" {
+		return "", "", false
+	}
+	from, err := Legacy()
+	if err != nil {
+		return "", "", false
+	}
+	if info, err := os.Lstat(from); err != nil || !info.IsDir() {
+		return "", "", false
+	}
+	to, err = XDGConfig()
+	if err != nil {
+		return "", "", false
+	}
+	if _, err := os.Lstat(to); !errors.Is(err, os.ErrNotExist) {
+		return "", "", false
+	}
+	return from, to, true
+}
+
+// MigrateLegacy moves the legacy directory to its XDG destination and leaves
+// a symlink behind so older tools keep finding it. linked is false when the
+// platform does not allow the symlink; the directory is moved either way.
+func MigrateLegacy(from, to string) (linked bool, err error) {
+	if err := os.MkdirAll(filepath.Dir(to), 0o750); err != nil {
+		return false, fmt.Errorf("creating %s: %w", filepath.Dir(to), err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return false, fmt.Errorf("moving %s to %s: %w", from, to, err)
+	}
+	if err := os.Symlink(to, from); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// baseDir returns Name under the XDG base directory in env, which must be
+// absolute to count, falling back to ~/fallback or the Windows equivalent.
+func baseDir(env, fallback string, windows func() (string, error)) (string, error) {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, Name), nil
+	}
+	if runtime.GOOS == "windows" {
+		dir, err := windows()
+		if err != nil {
+			return "", fmt.Errorf("getting %s: %w", env, err)
+		}
+		return filepath.Join(dir, Name), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	return filepath.Join(home, fallback, Name), nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,100 @@
+package dirs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv(EnvConfigDir, "")
+	return home
+}
+
+func TestConfig(t *testing.T) {
+	home := setHome(t)
+
+	xdg, err := XDGConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Config(); got != xdg {
+		t.Errorf("Config() = %q, want %q without a legacy directory", got, xdg)
+	}
+
+	// An unmigrated ~/.fast-cc stays in use
+	legacy := filepath.Join(home, LegacyName)
+	if err := os.Mkdir(legacy, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Config(); got != legacy {
+		t.Errorf("Config() = %q, want the legacy %q", got, legacy)
+	}
+
+	custom := filepath.Join(home, "xdg")
+	t.Setenv("XDG_CONFIG_HOME", custom)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+	if err := os.MkdirAll(filepath.Join(custom, Name), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Config(); got != filepath.Join(custom, Name) {
+		t.Errorf("Config() = %q, want the existing XDG directory", got)
+	}
+	if got, _ := Cache(); got != filepath.Join(home, "cache", Name) {
+		t.Errorf("Cache() = %q", got)
+	}
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+	if got, _ := Bin(); got != filepath.Join(home, "data", Name, "bin") {
+		t.Errorf("Bin() = %q", got)
+	}
+
+	t.Setenv(EnvConfigDir, filepath.Join(home, "pinned"))
+	if got, _ := Config(); got != filepath.Join(home, "pinned") {
+		t.Errorf("Config() = %q, want %s", got, EnvConfigDir)
+	}
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	home := setHome(t)
+	if _, _, ok := LegacyMigration(); ok {
+		t.Error("expected nothing to migrate without ~/.fast-cc")
+	}
+
+	legacy := filepath.Join(home, LegacyName)
+	if err := os.Mkdir(legacy, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "fast-cc-config.yaml"), []byte("types: [feat]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	from, to, ok := LegacyMigration()
+	if !ok || from != legacy {
+		t.Fatalf("LegacyMigration() = %q, %q, %v", from, to, ok)
+	}
+	linked, err := MigrateLegacy(from, to)
+	if err != nil {
+		t.Fatalf("MigrateLegacy() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(to, "fast-cc-config.yaml")); err != nil {
+		t.Errorf("config not moved: %v", err)
+	}
+	if got, _ := Config(); got != to {
+		t.Errorf("Config() = %q after migrating, want %q", got, to)
+	}
+	if linked {
+		if _, err := os.Stat(filepath.Join(legacy, "fast-cc-config.yaml")); err != nil {
+			t.Errorf("config not reachable through the symlink: %v", err)
+		}
+	}
+	if _, _, ok := LegacyMigration(); ok {
+		t.Error("expected nothing left to migrate")
+	}
+}
@@ -0,0 +1,78 @@
+package hooks
+
+import "strings"
+
+// diffContext is the number of unchanged lines shown around changes.
+const diffContext = 2
+
+// lineDiff renders the changes from before to after line by line: "-" for
+// removed lines, "+" for added ones and a few unchanged lines around them,
+// with "..." where unchanged lines are skipped. Hook scripts are short, so a
+// quadratic longest common subsequence is fine.
+func lineDiff(before, after string) string {
+	a := strings.Split(strings.TrimSuffix(before, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(after, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	// Keep unchanged lines only near changes
+	keep := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for c := max(0, k-diffContext); c <= min(len(lines)-1, k+diffContext); c++ {
+			keep[c] = true
+		}
+	}
+
+	var sb strings.Builder
+	skipped := false
+	for k, l := range lines {
+		if !keep[k] {
+			skipped = true
+			continue
+		}
+		if skipped && sb.Len() > 0 {
+			sb.WriteString("  ...\n")
+		}
+		skipped = false
+		sb.WriteByte(l.op)
+		sb.WriteString(" " + l.text + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,19 @@
+package hooks
+
+import "testing"
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		before, after string
+		want          string
+	}{
+		{"a\nb\nc\n", "a\nb\nc\n", ""},
+		{"a\nb\nc\n", "a\nx\nc\n", "  a\n- b\n+ x\n  c\n"},
+		{"1\n2\n3\n4\n5\n6\n7\n8\n9\n", "0\n1\n2\n3\n4\n5\n6\n7\n8\n", "+ 0\n  1\n  2\n  ...\n  7\n  8\n- 9\n"},
+	}
+	for _, tt := range tests {
+		if got := lineDiff(tt.before, tt.after); got != tt.want {
+			t.Errorf("lineDiff(%q, %q) = %q, want %q", tt.before, tt.after, got, tt.want)
+		}
+	}
+}
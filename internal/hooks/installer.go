@@ -10,16 +10,27 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
 )
 
 const (
 	// HookName is the name of the commit-msg hook.
 	HookName = "commit-msg"
+	// PrepareHookName is the name of the prepare-commit-msg hook.
+	PrepareHookName = "prepare-commit-msg"
+	// PrePushHookName is the name of the pre-push hook.
+	PrePushHookName = "pre-push"
 	// BackupSuffix is appended to backup files.
 	BackupSuffix = ".backup"
+	// LegacySuffix is appended to renamed hooks of the old fast-cc-hooks binary.
+	LegacySuffix = ".legacy"
+	// legacyHookMarker identifies hooks written by the old fast-cc-hooks binary.
+	legacyHookMarker = "fast-cc-hooks"
 	// HookIdentifier identifies our hooks.
 	HookIdentifier = "# fcgh"
 )
@@ -33,10 +44,17 @@ var (
 
 // Installer manages git hook installation.
 type Installer struct {
-	logger       *slog.Logger
-	gitDir       string
-	executable   string
-	forceInstall bool
+	logger           *slog.Logger
+	gitDir           string
+	executable       string
+	forceInstall     bool
+	prepareCommitMsg bool
+	prePush          bool
+	fallbackTypes    []string
+	fallbackLength   int
+	output           io.Writer
+	changed          bool
+	outdated         bool
 }
 
 // Options configures the Installer.
@@ -45,6 +63,19 @@ type Options struct {
 	GitDir       string
 	Executable   string
 	ForceInstall bool
+	// PrepareCommitMsg also installs a prepare-commit-msg hook that
+	// pre-populates the editor with a generated conventional commit message.
+	PrepareCommitMsg bool
+	// PrePush also installs a pre-push hook that re-validates outgoing
+	// commits, catching those made with the commit-msg hook skipped.
+	PrePush bool
+	// Config supplies the types and subject length the commit-msg hook
+	// checks in shell when the fcgh binary is missing (defaults when nil).
+	Config *config.Config
+	// Output receives a line per hook that is already up to date, and the
+	// diff of each customized hook ForceInstall overwrites or would
+	// overwrite (nil discards them).
+	Output io.Writer
 }
 
 // New creates a new Installer.
@@ -71,11 +102,31 @@ func New(opts Options) (*Installer, error) {
 		executable = exe
 	}
 
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	output := opts.Output
+	if output == nil {
+		output = io.Discard
+	}
+	var types []string
+	for _, t := range cfg.Types {
+		if fallbackTypeRegex.MatchString(t) {
+			types = append(types, t)
+		}
+	}
+
 	return &Installer{
-		logger:       opts.Logger,
-		gitDir:       gitDir,
-		executable:   executable,
-		forceInstall: opts.ForceInstall,
+		logger:           opts.Logger,
+		gitDir:           gitDir,
+		executable:       executable,
+		forceInstall:     opts.ForceInstall,
+		prepareCommitMsg: opts.PrepareCommitMsg,
+		prePush:          opts.PrePush,
+		fallbackTypes:    types,
+		fallbackLength:   fallbackLengthLimit(cfg),
+		output:           output,
 	}, nil
 }
 
@@ -89,18 +140,67 @@ func (i *Installer) Install(_ context.Context) error {
 		return fmt.Errorf("creating hooks directory: %w", err)
 	}
 
-	hookPath := filepath.Join(hooksDir, HookName)
+	if err := i.installHook(filepath.Join(hooksDir, HookName), i.generateHookScript()); err != nil {
+		return err
+	}
+
+	if i.prepareCommitMsg {
+		if err := i.installHook(filepath.Join(hooksDir, PrepareHookName), i.generatePrepareHookScript()); err != nil {
+			return err
+		}
+	}
+
+	if i.prePush {
+		if err := i.installHook(filepath.Join(hooksDir, PrePushHookName), i.generatePrePushHookScript()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// generatedAtPrefix starts the line recording when a hook script was generated.
+const generatedAtPrefix = "# Generated at: "
+
+// withoutTimestamp returns script without its generatedAtPrefix line.
+func withoutTimestamp(script string) string {
+	lines := strings.SplitAfter(script, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(line, generatedAtPrefix) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "")
+}
 
+// installHook writes a single hook script, backing up foreign hooks when
+// forced. An identical executable hook is left untouched, so re-running
+// setup changes nothing.
+func (i *Installer) installHook(hookPath, script string) error {
 	// Check if hook already exists.
 	if info, err := os.Stat(hookPath); err == nil {
+		data, readErr := os.ReadFile(hookPath) // #nosec G304 - path is controlled internally
+		// Scripts differing only in when they were generated are the same hook
+		existing, wanted := withoutTimestamp(string(data)), withoutTimestamp(script)
+		if readErr == nil && existing == wanted && info.Mode()&0o111 != 0 {
+			i.logger.Info("hook already up to date", "path", hookPath)
+			fmt.Fprintf(i.output, "✅ %s is already up to date (no changes)\n", hookPath)
+			return nil
+		}
+
 		if !i.forceInstall {
 			// Check if it's our hook.
 			if i.isOurHook(hookPath) {
 				i.logger.Info("hook already installed", "path", hookPath)
+				i.outdated = true
+				fmt.Fprintf(i.output, "ℹ️  %s differs from this fcgh version's hook; --force would change:\n%s", hookPath, lineDiff(existing, wanted))
 				return nil
 			}
+			fmt.Fprintf(i.output, "⚠️  %s is not an fcgh hook; --force would back it up and change:\n%s", hookPath, lineDiff(existing, wanted))
 			return fmt.Errorf("%w: %s (use --force to override)", ErrHookExists, hookPath)
 		}
+		fmt.Fprintf(i.output, "✏️  Updating %s:\n%s", hookPath, lineDiff(existing, wanted))
 
 		// Backup existing hook.
 		if err := i.backupHook(hookPath, info); err != nil {
@@ -108,14 +208,12 @@ func (i *Installer) Install(_ context.Context) error {
 		}
 	}
 
-	// Create hook script.
-	script := i.generateHookScript()
-
 	// Write hook file.
 	// #nosec G306 - Git hooks must be executable (755 permissions required)
 	if err := os.WriteFile(hookPath, []byte(script), 0o755); err != nil {
 		return fmt.Errorf("writing hook: %w", err)
 	}
+	i.changed = true
 
 	i.logger.Info("hook installed successfully",
 		"path", hookPath,
@@ -124,10 +222,21 @@ func (i *Installer) Install(_ context.Context) error {
 	return nil
 }
 
-// Uninstall removes the commit-msg hook.
+// Uninstall removes the commit-msg hook and, if present, our prepare-commit-msg
+// and pre-push hooks.
 func (i *Installer) Uninstall(_ context.Context) error {
-	hookPath := filepath.Join(i.gitDir, "hooks", HookName)
+	if err := i.uninstallHook(filepath.Join(i.gitDir, "hooks", HookName), true); err != nil {
+		return err
+	}
+	if err := i.uninstallHook(filepath.Join(i.gitDir, "hooks", PrepareHookName), false); err != nil {
+		return err
+	}
+	return i.uninstallHook(filepath.Join(i.gitDir, "hooks", PrePushHookName), false)
+}
 
+// uninstallHook removes a single hook and restores any backup.
+// When strict is false, hooks not installed by fcgh are left alone silently.
+func (i *Installer) uninstallHook(hookPath string, strict bool) error {
 	// Check if hook exists.
 	if _, err := os.Stat(hookPath); os.IsNotExist(err) {
 		i.logger.Info("hook not installed", "path", hookPath)
@@ -136,6 +245,9 @@ func (i *Installer) Uninstall(_ context.Context) error {
 
 	// Verify it's our hook before removing.
 	if !i.isOurHook(hookPath) {
+		if !strict {
+			return nil
+		}
 		return fmt.Errorf("hook exists but was not installed by fcgh: %s", hookPath)
 	}
 
@@ -158,6 +270,17 @@ func (i *Installer) Uninstall(_ context.Context) error {
 	return nil
 }
 
+// Changed reports whether Install wrote any hook.
+func (i *Installer) Changed() bool {
+	return i.changed
+}
+
+// Outdated reports whether Install left a differing fcgh hook in place
+// because ForceInstall was not set.
+func (i *Installer) Outdated() bool {
+	return i.outdated
+}
+
 // IsInstalled checks if the hook is installed.
 func (i *Installer) IsInstalled() bool {
 	hookPath := filepath.Join(i.gitDir, "hooks", HookName)
@@ -171,12 +294,130 @@ func (i *Installer) generateHookScript() string {
 	sb.WriteString("#!/bin/sh\n")
 	sb.WriteString(HookIdentifier + "\n")
 	sb.WriteString("# Auto-generated by fcgh\n")
-	sb.WriteString(fmt.Sprintf("# Generated at: %s\n", time.Now().Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("%s%s\n", generatedAtPrefix, time.Now().Format(time.RFC3339)))
 	sb.WriteString("\n")
 
 	// Add hook logic.
+	sb.WriteString(fmt.Sprintf("fcgh=%q\n", i.executable))
+	sb.WriteString("\n")
 	sb.WriteString("# Validate commit message\n")
-	sb.WriteString(fmt.Sprintf("exec %q validate --file \"$1\"\n", i.executable))
+	sb.WriteString("if [ -x \"$fcgh\" ]; then\n")
+	sb.WriteString("  exec \"$fcgh\" validate --file \"$1\"\n")
+	sb.WriteString("fi\n")
+	sb.WriteString("\n")
+	i.writeFallback(&sb)
+
+	return sb.String()
+}
+
+// fallbackTypeRegex matches the types the fallback can check: only word
+// characters parse as a type, and they need no quoting in the script.
+var fallbackTypeRegex = regexp.MustCompile(`^\w+$`)
+
+// fallbackTicketSlack is the room left for ticket references in the
+// fallback length check when exclude_ticket_from_length is set.
+const fallbackTicketSlack = 40
+
+// fallbackLengthLimit returns the longest header any type may have under cfg:
+// the largest of max_subject_length and its type_rules overrides, plus
+// fallbackTicketSlack when tickets do not count. Zero disables the check.
+func fallbackLengthLimit(cfg *config.Config) int {
+	if cfg.MaxSubjectLength <= 0 {
+		return 0
+	}
+	limit := cfg.MaxSubjectLength
+	for commitType := range cfg.TypeRules {
+		limit = max(limit, cfg.SubjectLengthFor(commitType))
+	}
+	if cfg.ExcludeTicketFromLength {
+		limit += fallbackTicketSlack
+	}
+	return limit
+}
+
+// writeFallback writes the shell validation run when the fcgh binary is
+// missing, such as on a freshly cloned machine: the header must be
+// conventional with an allowed type and fit the subject length.
+//
+// The fallback is deliberately lax. The shell knows neither the type_rules
+// of each type, the tickets to leave out nor subject_length_mode, so the
+// header is only held to the fallbackLengthLimit sanity cap, counted in
+// characters with wc -m. fcgh enforces the exact limits once it is back.
+func (i *Installer) writeFallback(sb *strings.Builder) {
+	typePattern := `\w+`
+	if len(i.fallbackTypes) > 0 {
+		typePattern = "(" + strings.Join(i.fallbackTypes, "|") + ")"
+	}
+
+	sb.WriteString("# fcgh is missing: run minimal checks rather than letting anything through\n")
+	sb.WriteString("echo \"⚠️  fcgh not found at $fcgh; running minimal checks (reinstall fcgh for full validation)\" >&2\n")
+	sb.WriteString("header=$(grep -v '^#' \"$1\" | grep -v '^[[:space:]]*$' | head -n 1)\n")
+	sb.WriteString(fmt.Sprintf("if ! printf '%%s\\n' \"$header\" | grep -Eq '^%s(\\([^)]*\\))?!?:[[:space:]]*[^[:space:]]'; then\n", typePattern))
+	sb.WriteString("  echo \"❌ Invalid commit message: $header\" >&2\n")
+	if len(i.fallbackTypes) > 0 {
+		sb.WriteString(fmt.Sprintf("  echo \"   Expected 'type(scope): description' with a type of %s\" >&2\n", strings.Join(i.fallbackTypes, ", ")))
+	} else {
+		sb.WriteString("  echo \"   Expected 'type(scope): description'\" >&2\n")
+	}
+	sb.WriteString("  exit 1\n")
+	sb.WriteString("fi\n")
+	if i.fallbackLength > 0 {
+		// ${#header} counts bytes in some shells, such as dash
+		sb.WriteString("length=$(printf '%s' \"$header\" | wc -m | tr -d ' ')\n")
+		sb.WriteString(fmt.Sprintf("if [ \"$length\" -gt %d ]; then\n", i.fallbackLength))
+		sb.WriteString(fmt.Sprintf("  echo \"❌ Header is $length characters long, over the limit of %d\" >&2\n", i.fallbackLength))
+		sb.WriteString("  exit 1\n")
+		sb.WriteString("fi\n")
+	}
+}
+
+// generatePrepareHookScript creates the prepare-commit-msg hook content.
+// Git passes the message file as $1 and the message source as $2; we only
+// pre-populate plain `git commit` invocations (no -m, merge or amend). A
+// commit.template such as `fcgh template install` writes is only comments,
+// so those commits are pre-populated too.
+func (i *Installer) generatePrepareHookScript() string {
+	var sb strings.Builder
+
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString(HookIdentifier + "\n")
+	sb.WriteString("# Auto-generated by fcgh\n")
+	sb.WriteString(fmt.Sprintf("%s%s\n", generatedAtPrefix, time.Now().Format(time.RFC3339)))
+	sb.WriteString("\n")
+
+	sb.WriteString("# Only pre-populate messages for plain `git commit`, with or without a template\n")
+	sb.WriteString("if [ -n \"$2\" ] && [ \"$2\" != \"template\" ]; then\n")
+	sb.WriteString("  exit 0\n")
+	sb.WriteString("fi\n")
+	sb.WriteString("\n")
+
+	// Never block the commit if generation fails.
+	sb.WriteString("# Generate a conventional commit message (never blocks the commit)\n")
+	sb.WriteString(fmt.Sprintf("%q prepare-msg --file \"$1\" >/dev/null 2>&1 || true\n", i.executable))
+
+	return sb.String()
+}
+
+// generatePrePushHookScript creates the pre-push hook content. Git passes
+// the remote name and URL as $1 and $2 and the pushed refs on stdin, which
+// fcgh pre-push reads in turn. Without the binary, pushes go through
+// unaudited rather than being blocked.
+func (i *Installer) generatePrePushHookScript() string {
+	var sb strings.Builder
+
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString(HookIdentifier + "\n")
+	sb.WriteString("# Auto-generated by fcgh\n")
+	sb.WriteString(fmt.Sprintf("%s%s\n", generatedAtPrefix, time.Now().Format(time.RFC3339)))
+	sb.WriteString("\n")
+
+	sb.WriteString(fmt.Sprintf("fcgh=%q\n", i.executable))
+	sb.WriteString("\n")
+	sb.WriteString("# Re-validate outgoing commits, including those made with --no-verify\n")
+	sb.WriteString("if [ -x \"$fcgh\" ]; then\n")
+	sb.WriteString("  exec \"$fcgh\" pre-push \"$1\" \"$2\"\n")
+	sb.WriteString("fi\n")
+	sb.WriteString("echo \"⚠️  fcgh not found at $fcgh; pushed commits are not audited\" >&2\n")
 
 	return sb.String()
 }
@@ -200,6 +441,51 @@ func (*Installer) isOurHook(path string) bool {
 	return strings.Contains(content, HookIdentifier)
 }
 
+// LegacyHooks returns the hooks in this repository that were installed by
+// the old fast-cc-hooks binary.
+func (i *Installer) LegacyHooks() []string {
+	return legacyHooks(filepath.Join(i.gitDir, "hooks"))
+}
+
+// GlobalLegacyHooks returns old fast-cc-hooks hooks in the git template
+// directory used by global installs.
+func GlobalLegacyHooks() ([]string, error) {
+	configDir, err := getGitConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("finding git config directory: %w", err)
+	}
+	return legacyHooks(filepath.Join(configDir, "hooks")), nil
+}
+
+// RenameLegacyHooks renames each legacy hook to <hook>.legacy so it no
+// longer runs, returning the new paths.
+func RenameLegacyHooks(paths []string) ([]string, error) {
+	renamed := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if err := os.Rename(path, path+LegacySuffix); err != nil {
+			return renamed, fmt.Errorf("renaming legacy hook %s: %w", path, err)
+		}
+		renamed = append(renamed, path+LegacySuffix)
+	}
+	return renamed, nil
+}
+
+func legacyHooks(hooksDir string) []string {
+	var found []string
+	for _, name := range []string{HookName, PrepareHookName} {
+		path := filepath.Join(hooksDir, name)
+		content, err := os.ReadFile(path) // #nosec G304 - path is controlled internally
+		if err != nil {
+			continue
+		}
+		text := string(content)
+		if !strings.Contains(text, HookIdentifier) && strings.Contains(text, legacyHookMarker) {
+			found = append(found, path)
+		}
+	}
+	return found
+}
+
 // backupHook creates a backup of an existing hook.
 func (i *Installer) backupHook(path string, info os.FileInfo) error {
 	backupPath := path + BackupSuffix
@@ -236,13 +522,60 @@ func (i *Installer) backupHook(path string, info os.FileInfo) error {
 	return nil
 }
 
-// findGitDir locates the .git directory.
+// findGitDir locates the git directory that holds hooks for the current repository.
+// Linked worktrees and submodules with worktrees share hooks through the common
+// git directory, so that is returned rather than the per-worktree directory.
 func findGitDir() (string, error) {
+	if dir, err := gitCommonDir(); err == nil {
+		return dir, nil
+	}
+
+	// Fall back to the environment and filesystem when git is unavailable.
+	return findGitDirOnDisk()
+}
+
+// gitCommonDir asks git for the common git directory of the current repository.
+// This handles linked worktrees, submodules and bare repositories uniformly.
+func gitCommonDir() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-common-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse --git-common-dir: %w", err)
+	}
+
+	dir := strings.TrimSpace(string(output))
+	if dir == "" {
+		return "", ErrNoGitRepo
+	}
+
+	// Relative paths are relative to the working directory.
+	if !filepath.IsAbs(dir) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("getting working directory: %w", err)
+		}
+		dir = filepath.Join(wd, dir)
+	}
+
+	return filepath.Clean(dir), nil
+}
+
+// findGitDirOnDisk locates the git directory from GIT_COMMON_DIR / GIT_DIR or by
+// walking up from the working directory.
+func findGitDirOnDisk() (string, error) {
 	dir, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("getting working directory: %w", err)
 	}
 
+	// Tools such as pre-commit, lazygit and GUI clients point git at the
+	// repository through the environment instead of the working directory.
+	if commonDir := os.Getenv("GIT_COMMON_DIR"); commonDir != "" {
+		return absFrom(dir, commonDir), nil
+	}
+	if gitDir := os.Getenv("GIT_DIR"); gitDir != "" {
+		return resolveCommonDir(absFrom(dir, gitDir)), nil
+	}
+
 	// Walk up directory tree looking for .git.
 	for {
 		gitDir := filepath.Join(dir, ".git")
@@ -250,7 +583,7 @@ func findGitDir() (string, error) {
 			if info.IsDir() {
 				return gitDir, nil
 			}
-			// Handle git worktrees (.git as file).
+			// Handle git worktrees and submodules (.git as file).
 			// #nosec G304 - gitDir is controlled internally
 			if content, err := os.ReadFile(gitDir); err == nil {
 				if strings.HasPrefix(string(content), "gitdir:") {
@@ -258,11 +591,16 @@ func findGitDir() (string, error) {
 					if !filepath.IsAbs(gitPath) {
 						gitPath = filepath.Join(dir, gitPath)
 					}
-					return gitPath, nil
+					return resolveCommonDir(gitPath), nil
 				}
 			}
 		}
 
+		// Bare repositories are git directories themselves.
+		if isBareGitDir(dir) {
+			return dir, nil
+		}
+
 		parent := filepath.Dir(dir)
 		if parent == dir {
 			break // Reached root.
@@ -273,6 +611,40 @@ func findGitDir() (string, error) {
 	return "", ErrNoGitRepo
 }
 
+// resolveCommonDir follows a linked worktree's "commondir" file to the main
+// git directory. Git directories without one (e.g. submodules) are returned as-is.
+func resolveCommonDir(gitDir string) string {
+	// #nosec G304 - path is derived from the repository's git directory
+	content, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+
+	commonDir := strings.TrimSpace(string(content))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+	return filepath.Clean(commonDir)
+}
+
+// absFrom resolves path relative to base unless it is already absolute.
+func absFrom(base, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(base, path)
+}
+
+// isBareGitDir reports whether dir looks like a bare repository.
+func isBareGitDir(dir string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
 // GlobalInstall installs hooks globally for all repositories.
 // Note: Git's precedence rules ensure that local repository hooks (installed via Install())
 // will always take precedence over global template hooks when both exist.
@@ -283,36 +655,45 @@ func findGitDir() (string, error) {
 //   - Linux/macOS: ~/.config/git/hooks/
 //   - Windows: ~/AppData/Roaming/Git/hooks/
 func GlobalInstall(ctx context.Context, logger *slog.Logger) error {
+	_, err := GlobalInstallWithOptions(ctx, Options{Logger: logger})
+	return err
+}
+
+// GlobalInstallWithOptions installs hooks globally, honouring installer options
+// such as PrepareCommitMsg, and reports whether it changed any hook or git
+// setting. GitDir and ForceInstall are always set by this function.
+func GlobalInstallWithOptions(ctx context.Context, opts Options) (bool, error) {
 	// Get git config directory.
 	fmt.Printf("Installing Git Hooks to git template dir. Any hooks placed in the template directory will be copied to every new repository\n")
 	configDir, err := getGitConfigDir()
 	if err != nil {
-		return fmt.Errorf("finding git config directory: %w", err)
+		return false, fmt.Errorf("finding git config directory: %w", err)
 	}
 
 	templateDir := filepath.Join(configDir, "hooks")
 	if mkdirErr := os.MkdirAll(templateDir, 0o750); mkdirErr != nil {
-		return fmt.Errorf("creating git template directory: %w", mkdirErr)
+		return false, fmt.Errorf("creating git template directory: %w", mkdirErr)
 	}
 
 	// Configure git to use template directory.
-	if configErr := configureGitTemplate(templateDir); configErr != nil {
-		return fmt.Errorf("configuring git template: %w", configErr)
+	configured, configErr := configureGitTemplate(templateDir)
+	if configErr != nil {
+		return false, fmt.Errorf("configuring git template: %w", configErr)
 	}
 
 	// Install hook in template directory.
-	opts := Options{
-		Logger:       logger,
-		GitDir:       filepath.Dir(templateDir), // Parent of hooks dir
-		ForceInstall: true,
-	}
+	opts.GitDir = filepath.Dir(templateDir) // Parent of hooks dir
+	opts.ForceInstall = true
 
 	installer, err := New(opts)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return installer.Install(ctx)
+	if err := installer.Install(ctx); err != nil {
+		return false, err
+	}
+	return configured || installer.Changed(), nil
 }
 
 // getGitConfigDir returns the git configuration directory.
@@ -336,18 +717,25 @@ func getGitConfigDir() (string, error) {
 	}
 }
 
-// configureGitTemplate sets up git to use our template directory.
-func configureGitTemplate(templateDir string) error {
+// configureGitTemplate sets up git to use our template directory, reporting
+// whether the setting changed.
+func configureGitTemplate(templateDir string) (bool, error) {
 	// Get the parent directory (git config directory)
 	configDir := filepath.Dir(templateDir)
 
+	// Leave the global config untouched when it already points there
+	// #nosec G204 - fixed git command
+	if current, err := exec.Command("git", "config", "--global", "--get", "init.templatedir").Output(); err == nil && strings.TrimSpace(string(current)) == configDir {
+		return false, nil
+	}
+
 	// Execute git config command to set the template directory
 	// This makes Git use our template directory for all new repositories
 	cmd := exec.Command("git", "config", "--global", "init.templatedir", configDir) // #nosec G204 - configDir is controlled internally via getGitConfigDir
 	if err := cmd.Run(); err != nil {
 		// If git command fails, provide helpful error message
-		return fmt.Errorf("failed to configure git template directory - please run manually: git config --global init.templatedir %s (error: %w)", configDir, err)
+		return false, fmt.Errorf("failed to configure git template directory - please run manually: git config --global init.templatedir %s (error: %w)", configDir, err)
 	}
 
-	return nil
+	return true, nil
 }
@@ -0,0 +1,347 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+func TestCommitMsgHookFallback(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts run with sh")
+	}
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.Types = []string{"feat", "fix"}
+	cfg.MaxSubjectLength = 30
+	cfg.TypeRules = map[string]config.TypeRule{"feat": {MaxSubjectLength: 50}}
+	installer, err := New(Options{GitDir: dir, Executable: filepath.Join(dir, "missing", "fcgh"), Config: cfg})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := installer.Install(context.Background()); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	hook := filepath.Join(dir, "hooks", HookName)
+
+	tests := []struct {
+		message string
+		pass    bool
+	}{
+		{message: "feat(api): add login", pass: true},
+		{message: "# Please enter the commit message\n\nfix!: drop v1\n\nBody", pass: true},
+		{message: "docs: update readme", pass: false},
+		{message: "add login", pass: false},
+		{message: "feat:", pass: false},
+		{message: "feat: " + strings.Repeat("x", 40), pass: true},
+		{message: "feat: " + strings.Repeat("x", 50), pass: false},
+		// Only held to the most permissive limit
+		{message: "fix: " + strings.Repeat("x", 40), pass: true},
+	}
+	for _, tt := range tests {
+		file := filepath.Join(dir, "COMMIT_EDITMSG")
+		if err := os.WriteFile(file, []byte(tt.message+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		out, err := exec.Command("sh", hook, file).CombinedOutput()
+		if (err == nil) != tt.pass {
+			t.Errorf("hook(%q) error = %v, want pass %v\n%s", tt.message, err, tt.pass, out)
+		}
+		if !strings.Contains(string(out), "fcgh not found") {
+			t.Errorf("hook(%q) output = %q, want a missing fcgh warning", tt.message, out)
+		}
+	}
+}
+
+func TestCommitMsgHookRunsFcgh(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts run with sh")
+	}
+	dir := t.TempDir()
+	fake := filepath.Join(dir, "fcgh")
+	// #nosec G306 - the fake binary must be executable
+	if err := os.WriteFile(fake, []byte("#!/bin/sh\necho \"fcgh $*\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	installer, err := New(Options{GitDir: dir, Executable: fake})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := installer.Install(context.Background()); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	out, err := exec.Command("sh", filepath.Join(dir, "hooks", HookName), "MSG").CombinedOutput()
+	if err != nil || strings.TrimSpace(string(out)) != "fcgh validate --file MSG" {
+		t.Errorf("hook output = %q, error = %v, want fcgh to validate the file", out, err)
+	}
+}
+
+func TestPrePushHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts run with sh")
+	}
+	dir := t.TempDir()
+	fake := filepath.Join(dir, "fcgh")
+	// #nosec G306 - the fake binary must be executable
+	if err := os.WriteFile(fake, []byte("#!/bin/sh\necho \"fcgh $* $(cat)\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	installer, err := New(Options{GitDir: dir, Executable: fake, PrePush: true})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := installer.Install(context.Background()); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	hook := filepath.Join(dir, "hooks", PrePushHookName)
+
+	cmd := exec.Command("sh", hook, "origin", "git@example.com:repo.git")
+	cmd.Stdin = strings.NewReader("refs/heads/main abc refs/heads/main def\n")
+	out, err := cmd.CombinedOutput()
+	if want := "fcgh pre-push origin git@example.com:repo.git refs/heads/main abc refs/heads/main def"; err != nil || strings.TrimSpace(string(out)) != want {
+		t.Errorf("hook output = %q, error = %v, want %q", out, err, want)
+	}
+
+	// Without fcgh, pushes go through
+	if err := os.Remove(fake); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("sh", hook, "origin", "url").CombinedOutput(); err != nil || !strings.Contains(string(out), "fcgh not found") {
+		t.Errorf("hook without fcgh output = %q, error = %v, want a warning and success", out, err)
+	}
+
+	if err := installer.Uninstall(context.Background()); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if _, err := os.Stat(hook); !os.IsNotExist(err) {
+		t.Errorf("pre-push hook still exists after Uninstall(): %v", err)
+	}
+}
+
+func TestInstallIsIdempotent(t *testing.T) {
+	dir := t.TempDir()
+	fake := filepath.Join(dir, "fcgh")
+	if _, err := os.Create(fake); err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	installer, err := New(Options{GitDir: dir, Executable: fake, Output: &out})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := installer.Install(context.Background()); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !installer.Changed() {
+		t.Error("Changed() = false after the first Install()")
+	}
+	hook := filepath.Join(dir, "hooks", HookName)
+	before, err := os.Stat(hook)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-running leaves the hook alone
+	installer, _ = New(Options{GitDir: dir, Executable: fake, Output: &out})
+	if err := installer.Install(context.Background()); err != nil {
+		t.Fatalf("second Install() error = %v", err)
+	}
+	if installer.Changed() {
+		t.Error("Changed() = true after re-running Install()")
+	}
+	if after, err := os.Stat(hook); err != nil || !after.ModTime().Equal(before.ModTime()) {
+		t.Errorf("hook rewritten by the second Install(): %v", err)
+	}
+	if !strings.Contains(out.String(), "already up to date (no changes)") {
+		t.Errorf("output = %q, want an up to date notice", out.String())
+	}
+
+	// Forcing over a customized hook shows what changes
+	script, err := os.ReadFile(hook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// #nosec G306 - hooks must be executable
+	if err := os.WriteFile(hook, append(script, "echo custom\n"...), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	installer, _ = New(Options{GitDir: dir, Executable: fake, Output: &out, ForceInstall: true})
+	if err := installer.Install(context.Background()); err != nil {
+		t.Fatalf("forced Install() error = %v", err)
+	}
+	if !installer.Changed() || !strings.Contains(out.String(), "- echo custom\n") {
+		t.Errorf("forced Install() changed %v, output = %q, want the custom line removed", installer.Changed(), out.String())
+	}
+	if got, _ := os.ReadFile(hook); string(got) != string(script) {
+		t.Error("forced Install() did not restore the hook")
+	}
+}
+
+func TestInstallIgnoresGenerationTime(t *testing.T) {
+	dir := t.TempDir()
+	fake := filepath.Join(dir, "fcgh")
+	if _, err := os.Create(fake); err != nil {
+		t.Fatal(err)
+	}
+	installer, err := New(Options{GitDir: dir, Executable: fake})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := installer.Install(context.Background()); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	hook := filepath.Join(dir, "hooks", HookName)
+	script, err := os.ReadFile(hook)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A hook generated by an earlier run
+	var older []string
+	for _, line := range strings.Split(string(script), "\n") {
+		if strings.HasPrefix(line, generatedAtPrefix) {
+			line = generatedAtPrefix + "2000-01-01T00:00:00Z"
+		}
+		older = append(older, line)
+	}
+	// #nosec G306 - hooks must be executable
+	if err := os.WriteFile(hook, []byte(strings.Join(older, "\n")), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Global setup always forces, so both must leave the hook alone
+	for _, force := range []bool{false, true} {
+		var out strings.Builder
+		installer, _ := New(Options{GitDir: dir, Executable: fake, Output: &out, ForceInstall: force})
+		if err := installer.Install(context.Background()); err != nil {
+			t.Fatalf("Install() with force %v error = %v", force, err)
+		}
+		if installer.Changed() || installer.Outdated() {
+			t.Errorf("Install() with force %v: changed %v, outdated %v, want neither", force, installer.Changed(), installer.Outdated())
+		}
+		if got := out.String(); strings.Contains(got, "Generated at") || !strings.Contains(got, "already up to date (no changes)") {
+			t.Errorf("Install() with force %v output = %q, want an up to date notice without a diff", force, got)
+		}
+	}
+	if data, _ := os.ReadFile(hook); !strings.Contains(string(data), "2000-01-01T00:00:00Z") {
+		t.Error("hook rewritten although only its generation time differed")
+	}
+	if _, err := os.Stat(hook + BackupSuffix); !os.IsNotExist(err) {
+		t.Errorf("hook backed up although unchanged: %v", err)
+	}
+}
+
+func TestFindGitDirLayouts(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	// Run as if outside any hook, so only the working directory counts
+	for _, name := range []string{"GIT_DIR", "GIT_COMMON_DIR", "GIT_WORK_TREE"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+
+	tests := []struct {
+		name string
+		// setup creates the layout in root and returns the directory to
+		// run from and the git directory hooks belong in.
+		setup func(t *testing.T, root string) (string, string)
+	}{
+		{
+			name: "repository",
+			setup: func(t *testing.T, root string) (string, string) {
+				runGit(t, root, "init", "repo")
+				return filepath.Join(root, "repo"), filepath.Join(root, "repo", ".git")
+			},
+		},
+		{
+			name: "linked worktree",
+			setup: func(t *testing.T, root string) (string, string) {
+				main := filepath.Join(root, "main")
+				runGit(t, root, "init", "main")
+				runGit(t, main, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "chore: init")
+				runGit(t, main, "worktree", "add", "-b", "feature", filepath.Join(root, "wt"))
+				sub := filepath.Join(root, "wt", "src")
+				if err := os.Mkdir(sub, 0o750); err != nil {
+					t.Fatal(err)
+				}
+				return sub, filepath.Join(main, ".git")
+			},
+		},
+		{
+			name: "submodule",
+			setup: func(t *testing.T, root string) (string, string) {
+				runGit(t, root, "init", "super")
+				module := filepath.Join(root, "super", ".git", "modules", "lib")
+				if err := os.MkdirAll(filepath.Dir(module), 0o750); err != nil {
+					t.Fatal(err)
+				}
+				runGit(t, root, "init", "--separate-git-dir", module, filepath.Join(root, "super", "lib"))
+				// Submodules point at their git directory relatively
+				gitFile := filepath.Join(root, "super", "lib", ".git")
+				if err := os.WriteFile(gitFile, []byte("gitdir: ../.git/modules/lib\n"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				return filepath.Join(root, "super", "lib"), module
+			},
+		},
+		{
+			name: "bare repository",
+			setup: func(t *testing.T, root string) (string, string) {
+				runGit(t, root, "init", "--bare", "repo.git")
+				return filepath.Join(root, "repo.git"), filepath.Join(root, "repo.git")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := filepath.EvalSymlinks(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			dir, want := tt.setup(t, root)
+			t.Chdir(dir)
+
+			if got, err := gitCommonDir(); err != nil || got != want {
+				t.Errorf("gitCommonDir() = %q, %v, want %q", got, err, want)
+			}
+			if got, err := findGitDirOnDisk(); err != nil || got != want {
+				t.Errorf("findGitDirOnDisk() = %q, %v, want %q", got, err, want)
+			}
+
+			fake := filepath.Join(root, "fcgh")
+			if _, err := os.Create(fake); err != nil {
+				t.Fatal(err)
+			}
+			installer, err := New(Options{Executable: fake})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if err := installer.Install(context.Background()); err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(want, "hooks", HookName)); err != nil {
+				t.Errorf("hook not installed in %s: %v", want, err)
+			}
+		})
+	}
+}
+
+// runGit runs git with args in dir and fails the test if it does.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
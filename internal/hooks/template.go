@@ -0,0 +1,142 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/dirs"
+)
+
+// TemplateFile is the name of the commit message template written by
+// InstallTemplate.
+const TemplateFile = "commit-template.txt"
+
+// CommitTemplate renders a commit message template (git's commit.template)
+// that spells out the rules of cfg. Every line is a comment: git strips them
+// from the message, and the prepare-commit-msg hook only fills in a generated
+// message when the file holds nothing else.
+func CommitTemplate(cfg *config.Config) string {
+	ticketRequired := cfg.RequireJIRATicket || cfg.RequireTicketRef
+	ticketExample := "ABC-123"
+	if len(cfg.JIRAProjects) > 0 {
+		ticketExample = cfg.JIRAProjects[0] + "-123"
+	} else if cfg.TicketProvider == "github" {
+		ticketExample = "#123"
+	}
+
+	header := "<type>(<scope>): "
+	if !cfg.ScopeRequired {
+		header = "<type>[(<scope>)]: "
+	}
+	switch {
+	case !ticketRequired || cfg.TicketPlacement == "footer":
+		header += "<description>"
+	case cfg.TicketPlacement == "end":
+		header += "<description> <ticket>"
+	default:
+		header += "<ticket> <description>"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# " + header + "\n")
+	sb.WriteString("#\n")
+	sb.WriteString("# <body: what changed and why>\n")
+	if ticketRequired && cfg.TicketPlacement == "footer" {
+		sb.WriteString("#\n")
+		sb.WriteString("# Refs: <ticket>\n")
+	}
+	sb.WriteString("#\n")
+
+	fmt.Fprintf(&sb, "# Types:  %s\n", strings.Join(cfg.Types, ", "))
+	scopes := "any"
+	if len(cfg.Scopes) > 0 {
+		scopes = strings.Join(cfg.Scopes, ", ")
+	}
+	exceptions := scopeExceptions(cfg)
+	switch {
+	case cfg.ScopeRequired && len(exceptions) > 0:
+		scopes += " (required, except for " + strings.Join(exceptions, ", ") + ")"
+	case cfg.ScopeRequired:
+		scopes += " (required)"
+	case len(exceptions) > 0:
+		scopes += " (optional, required for " + strings.Join(exceptions, ", ") + ")"
+	default:
+		scopes += " (optional)"
+	}
+	fmt.Fprintf(&sb, "# Scopes: %s\n", scopes)
+	if ticketRequired {
+		fmt.Fprintf(&sb, "# Ticket: %s (required)\n", ticketExample)
+	} else if len(cfg.JIRAProjects) > 0 {
+		fmt.Fprintf(&sb, "# Ticket: %s (optional)\n", ticketExample)
+	}
+	if cfg.MaxSubjectLength > 0 {
+		limits := ""
+		for _, t := range cfg.Types {
+			if limit := cfg.SubjectLengthFor(t); limit != cfg.MaxSubjectLength {
+				limits += fmt.Sprintf(", %s: %d", t, limit)
+			}
+		}
+		if limits != "" {
+			limits = " (" + strings.TrimPrefix(limits, ", ") + ")"
+		}
+		fmt.Fprintf(&sb, "# Header: at most %d characters%s\n", cfg.MaxSubjectLength, limits)
+	}
+	if cfg.AllowBreakingChanges {
+		sb.WriteString("# Breaking changes: <type>!: ... and a \"BREAKING CHANGE: <what broke>\" footer\n")
+	}
+	if cfg.RequireSignoff {
+		sb.WriteString("# Sign off with 'git commit -s'\n")
+	}
+	return sb.String()
+}
+
+// scopeExceptions returns the types whose type_rules entry turns
+// scope_required the other way
+func scopeExceptions(cfg *config.Config) []string {
+	var types []string
+	for _, t := range cfg.Types {
+		if cfg.ScopeRequiredFor(t) != cfg.ScopeRequired {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// InstallTemplate writes the commit template for cfg and sets git's
+// commit.template to it: in the repository's git directory when local is
+// set, else in the fcgh config directory for all repositories. It returns
+// the template's path.
+func InstallTemplate(cfg *config.Config, local bool) (string, error) {
+	var dir string
+	var err error
+	if local {
+		dir, err = findGitDir()
+	} else {
+		dir, err = dirs.Config()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("creating template directory: %w", err)
+	}
+	path := filepath.Join(dir, TemplateFile)
+	if err := os.WriteFile(path, []byte(CommitTemplate(cfg)), 0o600); err != nil {
+		return "", fmt.Errorf("writing commit template: %w", err)
+	}
+
+	scope := "--global"
+	if local {
+		scope = "--local"
+	}
+	cmd := exec.Command("git", "config", scope, "commit.template", path) // #nosec G204 - path is our template file
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to set commit.template - please run manually: git config %s commit.template %s (error: %w: %s)", scope, path, err, strings.TrimSpace(string(output)))
+	}
+	return path, nil
+}
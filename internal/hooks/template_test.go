@@ -0,0 +1,56 @@
+package hooks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+func TestCommitTemplate(t *testing.T) {
+	cfg := config.Default()
+	cfg.Types = []string{"feat", "fix"}
+	cfg.Scopes = []string{"api", "web"}
+	cfg.ScopeRequired = true
+	cfg.RequireJIRATicket = true
+	cfg.JIRAProjects = []string{"PROJ"}
+	cfg.TicketPlacement = "footer"
+	cfg.MaxSubjectLength = 60
+	cfg.AllowBreakingChanges = false
+	cfg.RequireSignoff = true
+
+	want := `# <type>(<scope>): <description>
+#
+# <body: what changed and why>
+#
+# Refs: <ticket>
+#
+# Types:  feat, fix
+# Scopes: api, web (required)
+# Ticket: PROJ-123 (required)
+# Header: at most 60 characters
+# Sign off with 'git commit -s'
+`
+	if got := CommitTemplate(cfg); got != want {
+		t.Errorf("CommitTemplate() =\n%s\nwant:\n%s", got, want)
+	}
+
+	cfg.TicketPlacement = "end"
+	if got := CommitTemplate(cfg); !strings.HasPrefix(got, "# <type>(<scope>): <description> <ticket>\n") {
+		t.Errorf("CommitTemplate() with the ticket at the end = %q", got)
+	}
+	optional := false
+	cfg.TypeRules = map[string]config.TypeRule{"fix": {MaxSubjectLength: 80, ScopeRequired: &optional}}
+	got := CommitTemplate(cfg)
+	for _, line := range []string{"# Scopes: api, web (required, except for fix)\n", "# Header: at most 60 characters (fix: 80)\n"} {
+		if !strings.Contains(got, line) {
+			t.Errorf("CommitTemplate() with type rules lacks %q:\n%s", line, got)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(CommitTemplate(config.Default()), "\n"), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			t.Errorf("template line %q is not a comment", line)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+// Package lsp serves commit message diagnostics and completions over the
+// Language Server Protocol, so editors can flag problems in COMMIT_EDITMSG
+// while the message is being written.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/metrics"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+// Options configures a Server.
+type Options struct {
+	Config    *config.Config
+	Validator *validator.Validator
+	// Tickets returns the current tickets offered as completions; nil
+	// offers none.
+	Tickets func() []string
+	// Metrics records the validations behind diagnostics (nil records
+	// nothing).
+	Metrics *metrics.Metrics
+}
+
+// Server is a language server for git commit messages. It handles one
+// client at a time through Serve.
+type Server struct {
+	opts Options
+	// docs holds the text of open commit message documents by URI.
+	docs map[string]string
+	out  io.Writer
+}
+
+// NewServer creates a language server.
+func NewServer(opts Options) *Server {
+	return &Server{opts: opts, docs: make(map[string]string)}
+}
+
+var (
+	// typePrefixRegex matches a header being typed up to the type.
+	typePrefixRegex = regexp.MustCompile(`^\w*$`)
+	// scopePrefixRegex matches a header being typed inside the scope.
+	scopePrefixRegex = regexp.MustCompile(`^\w+\([^)]*$`)
+)
+
+// Serve reads requests from r and writes responses and diagnostics to w
+// until the client sends exit, r ends or ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.out = w
+	reader := bufio.NewReader(r)
+	for ctx.Err() == nil {
+		body, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(ctx, req); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// handle dispatches one request or notification. Only write failures are
+// returned; bad requests are answered with an error response.
+func (s *Server) handle(ctx context.Context, req request) error {
+	var result any
+	var failure *responseError
+	switch req.Method {
+	case "initialize":
+		result = initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:   syncFull,
+				CompletionProvider: completionOptions{TriggerCharacters: []string{"(", ","}},
+			},
+			ServerInfo: serverInfo{Name: "fcgh"},
+		}
+	case "shutdown":
+		result = nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil
+		}
+		if isCommitMessage(params.TextDocument.URI, params.TextDocument.LanguageID) {
+			s.docs[params.TextDocument.URI] = params.TextDocument.Text
+			return s.publishDiagnostics(ctx, params.TextDocument.URI)
+		}
+		return nil
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params.ContentChanges) == 0 {
+			return nil
+		}
+		uri := params.TextDocument.URI
+		if _, open := s.docs[uri]; !open {
+			return nil
+		}
+		s.docs[uri] = params.ContentChanges[len(params.ContentChanges)-1].Text
+		return s.publishDiagnostics(ctx, uri)
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil
+		}
+		if _, open := s.docs[params.TextDocument.URI]; !open {
+			return nil
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []diagnostic{}})
+	case "textDocument/completion":
+		var params completionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			failure = &responseError{Code: codeInvalidParams, Message: err.Error()}
+			break
+		}
+		result = s.complete(params)
+	default:
+		if req.ID == nil || strings.HasPrefix(req.Method, "$/") {
+			return nil // Notifications that need no handling
+		}
+		failure = &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not supported: %s", req.Method)}
+	}
+
+	if req.ID == nil {
+		return nil
+	}
+	return s.reply(req.ID, result, failure)
+}
+
+// publishDiagnostics validates an open document and sends its problems.
+func (s *Server) publishDiagnostics(ctx context.Context, uri string) error {
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: s.diagnose(ctx, s.docs[uri]),
+	})
+}
+
+// diagnose validates a document the way the commit-msg hook will see it.
+func (s *Server) diagnose(ctx context.Context, text string) []diagnostic {
+	message, lineMap := commitMessage(text)
+	diagnostics := []diagnostic{}
+	if message == "" {
+		return diagnostics
+	}
+	docLines := strings.Split(text, "\n")
+	header := lineMap[0]
+	wholeHeader := lspRange{
+		Start: position{Line: header},
+		End:   position{Line: header, Character: utf16Len(docLines[header])},
+	}
+
+	start := time.Now()
+	result := s.opts.Validator.Validate(ctx, message)
+	s.opts.Metrics.ObserveValidation(time.Since(start), result)
+	for _, err := range result.Errors {
+		d := diagnostic{Range: wholeHeader, Severity: severityError, Source: "fcgh", Message: err.Error()}
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			d.Code, d.Message = validationErr.Field, validationErr.Message
+			if validationErr.Value != "" {
+				d.Message += fmt.Sprintf(" (got: %q)", validationErr.Value)
+			}
+			if validationErr.Line >= 1 && validationErr.Line <= len(lineMap) {
+				line := lineMap[validationErr.Line-1]
+				d.Range = lspRange{
+					Start: position{Line: line, Character: utf16Column(docLines[line], validationErr.Column)},
+					End:   position{Line: line, Character: utf16Column(docLines[line], validationErr.EndColumn)},
+				}
+			}
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	for _, warning := range result.Warnings {
+		diagnostics = append(diagnostics, diagnostic{Range: wholeHeader, Severity: severityWarning, Source: "fcgh", Message: warning})
+	}
+	return diagnostics
+}
+
+// complete offers types while the header's type is typed, scopes inside its
+// parentheses, and the current tickets anywhere.
+func (s *Server) complete(params completionParams) completionList {
+	list := completionList{Items: []completionItem{}}
+	text, open := s.docs[params.TextDocument.URI]
+	if !open {
+		return list
+	}
+
+	lines := strings.Split(text, "\n")
+	if params.Position.Line < 0 || params.Position.Line >= len(lines) {
+		return list
+	}
+	line := lines[params.Position.Line]
+	prefix := line[:byteOffset(line, params.Position.Character)]
+
+	if isHeaderLine(lines, params.Position.Line) {
+		switch {
+		case typePrefixRegex.MatchString(prefix):
+			for _, t := range s.opts.Config.Types {
+				list.Items = append(list.Items, completionItem{Label: t, Kind: kindKeyword, Detail: "commit type"})
+			}
+			return list
+		case scopePrefixRegex.MatchString(prefix):
+			for _, scope := range s.opts.Config.Scopes {
+				list.Items = append(list.Items, completionItem{Label: scope, Kind: kindEnum, Detail: "scope"})
+			}
+			return list
+		}
+	}
+
+	if s.opts.Tickets != nil {
+		for _, ticket := range s.opts.Tickets() {
+			list.Items = append(list.Items, completionItem{Label: ticket, Kind: kindReference, Detail: "current ticket"})
+		}
+	}
+	return list
+}
+
+// isCommitMessage reports whether a document is a git commit message.
+func isCommitMessage(uri, languageID string) bool {
+	return languageID == "gitcommit" || path.Base(uri) == "COMMIT_EDITMSG"
+}
+
+// scissorsLine starts the part of a verbose commit message git discards.
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+// commitMessage strips what git strips from a commit message document:
+// comment lines, everything below the scissors line and surrounding blank
+// lines. It returns the message and the document line of each message line.
+func commitMessage(text string) (string, []int) {
+	var lines []string
+	var lineMap []int
+	for i, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, scissorsLine) {
+			break
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if len(lines) == 0 && strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\r"))
+		lineMap = append(lineMap, i)
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines, lineMap = lines[:len(lines)-1], lineMap[:len(lineMap)-1]
+	}
+	return strings.Join(lines, "\n"), lineMap
+}
+
+// isHeaderLine reports whether only blank and comment lines precede line.
+func isHeaderLine(lines []string, line int) bool {
+	for _, before := range lines[:line] {
+		if trimmed := strings.TrimSpace(before); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+// utf16Column converts a 1-based character column to a UTF-16 offset.
+func utf16Column(line string, column int) int {
+	offset := 0
+	for _, r := range line {
+		if column <= 1 {
+			break
+		}
+		offset += utf16.RuneLen(r)
+		column--
+	}
+	return offset
+}
+
+// byteOffset converts a UTF-16 offset in line to a byte offset.
+func byteOffset(line string, character int) int {
+	for i, r := range line {
+		if character <= 0 {
+			return i
+		}
+		character -= utf16.RuneLen(r)
+	}
+	return len(line)
+}
+
+func utf16Len(line string) int {
+	n := 0
+	for _, r := range line {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// readMessage reads one Content-Length framed message.
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) && line == "" && length < 0 {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("reading message header: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, found := strings.Cut(line, ":")
+		if found && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, errors.New("message without Content-Length")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("reading message body: %w", err)
+	}
+	return body, nil
+}
+
+func (s *Server) reply(id json.RawMessage, result any, failure *responseError) error {
+	resp := response{JSONRPC: "2.0", ID: id, Error: failure}
+	if id == nil {
+		resp.ID = json.RawMessage("null")
+	}
+	if failure == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("encoding result: %w", err)
+		}
+		resp.Result = data
+	}
+	return s.write(resp)
+}
+
+func (s *Server) notify(method string, params any) error {
+	return s.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *Server) write(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+	if _, err := fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		return fmt.Errorf("writing message: %w", err)
+	}
+	return nil
+}
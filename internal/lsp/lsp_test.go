@@ -0,0 +1,165 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+// frame encodes a JSON-RPC message with its Content-Length header.
+func frame(t *testing.T, msg map[string]any) string {
+	t.Helper()
+	msg["jsonrpc"] = "2.0"
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(data), data)
+}
+
+// serve runs a session and returns the decoded messages the server sent.
+func serve(t *testing.T, cfg *config.Config, msgs ...map[string]any) []map[string]any {
+	t.Helper()
+	v, err := validator.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var in strings.Builder
+	for _, msg := range msgs {
+		in.WriteString(frame(t, msg))
+	}
+
+	var out bytes.Buffer
+	server := NewServer(Options{Config: cfg, Validator: v, Tickets: func() []string { return []string{"PROJ-42"} }})
+	if err := server.Serve(context.Background(), strings.NewReader(in.String()), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	var sent []map[string]any
+	reader := bufio.NewReader(&out)
+	for {
+		body, err := readMessage(reader)
+		if err != nil {
+			break
+		}
+		var msg map[string]any
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, msg)
+	}
+	return sent
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	cfg := config.Default()
+	cfg.Types = []string{"feat", "fix"}
+	text := "# Please enter the commit message\nwip(ä): add login\n\n# comment"
+
+	sent := serve(t, cfg,
+		map[string]any{"id": 1, "method": "initialize", "params": map[string]any{}},
+		map[string]any{"method": "initialized", "params": map[string]any{}},
+		map[string]any{"method": "textDocument/didOpen", "params": map[string]any{
+			"textDocument": map[string]any{"uri": "file:///repo/.git/COMMIT_EDITMSG", "languageId": "gitcommit", "version": 1, "text": text},
+		}},
+		map[string]any{"method": "textDocument/didOpen", "params": map[string]any{
+			"textDocument": map[string]any{"uri": "file:///repo/main.go", "languageId": "go", "version": 1, "text": "package main"},
+		}},
+		map[string]any{"id": 2, "method": "shutdown"},
+		map[string]any{"method": "exit"},
+	)
+	if len(sent) != 3 {
+		t.Fatalf("server sent %d messages, want 3: %v", len(sent), sent)
+	}
+
+	capabilities := sent[0]["result"].(map[string]any)["capabilities"].(map[string]any)
+	if capabilities["textDocumentSync"] != float64(syncFull) || capabilities["completionProvider"] == nil {
+		t.Errorf("unexpected capabilities: %v", capabilities)
+	}
+
+	params := sent[1]["params"].(map[string]any)
+	diagnostics := params["diagnostics"].([]any)
+	if sent[1]["method"] != "textDocument/publishDiagnostics" || len(diagnostics) != 1 {
+		t.Fatalf("unexpected diagnostics: %v", sent[1])
+	}
+	d := diagnostics[0].(map[string]any)
+	want := map[string]any{"start": map[string]any{"line": float64(1), "character": float64(0)}, "end": map[string]any{"line": float64(1), "character": float64(3)}}
+	if d["code"] != "type" || fmt.Sprint(d["range"]) != fmt.Sprint(want) {
+		t.Errorf("diagnostic = %v, want a type error at %v", d, want)
+	}
+
+	if resp := sent[2]; resp["id"] != float64(2) || resp["result"] != nil {
+		t.Errorf("unexpected shutdown response: %v", resp)
+	}
+}
+
+func TestServer_Completion(t *testing.T) {
+	cfg := config.Default()
+	cfg.Types = []string{"feat", "fix"}
+	cfg.Scopes = []string{"api", "web"}
+	uri := "file:///repo/.git/COMMIT_EDITMSG"
+
+	open := map[string]any{"method": "textDocument/didOpen", "params": map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "gitcommit", "version": 1, "text": "fe\n"},
+	}}
+	change := func(text string) map[string]any {
+		return map[string]any{"method": "textDocument/didChange", "params": map[string]any{
+			"textDocument":   map[string]any{"uri": uri, "version": 2},
+			"contentChanges": []any{map[string]any{"text": text}},
+		}}
+	}
+	complete := func(id, line, character int) map[string]any {
+		return map[string]any{"id": id, "method": "textDocument/completion", "params": map[string]any{
+			"textDocument": map[string]any{"uri": uri},
+			"position":     map[string]any{"line": line, "character": character},
+		}}
+	}
+
+	sent := serve(t, cfg,
+		open, complete(1, 0, 2),
+		change("feat(a"), complete(2, 0, 6),
+		change("feat(api): add login\n\nRefs: "), complete(3, 2, 6),
+		map[string]any{"id": 4, "method": "textDocument/hover"},
+	)
+
+	labels := make(map[float64][]string)
+	for _, msg := range sent {
+		id, ok := msg["id"].(float64)
+		if !ok {
+			continue
+		}
+		if msg["error"] != nil {
+			labels[id] = []string{"error"}
+			continue
+		}
+		for _, item := range msg["result"].(map[string]any)["items"].([]any) {
+			labels[id] = append(labels[id], item.(map[string]any)["label"].(string))
+		}
+	}
+
+	want := map[float64][]string{1: {"feat", "fix"}, 2: {"api", "web"}, 3: {"PROJ-42"}, 4: {"error"}}
+	if fmt.Sprint(labels) != fmt.Sprint(want) {
+		t.Errorf("completions = %v, want %v", labels, want)
+	}
+}
+
+func TestCommitMessage(t *testing.T) {
+	text := "\n# comment\nfeat: add login\n\nbody\n\n# Changes:\n# ------------------------ >8 ------------------------\ndiff --git a/x b/x\n"
+	message, lines := commitMessage(text)
+	if message != "feat: add login\n\nbody" {
+		t.Errorf("commitMessage() = %q", message)
+	}
+	if fmt.Sprint(lines) != "[2 3 4]" {
+		t.Errorf("line map = %v, want [2 3 4]", lines)
+	}
+	if got := utf16Column("ä😀x", 3); got != 3 {
+		t.Errorf("utf16Column() = %d, want 3", got)
+	}
+}
@@ -0,0 +1,154 @@
+// Package metrics counts validations and message generations in the long
+// running modes (fcgh serve and fcgh lsp) and exposes them over HTTP in the
+// Prometheus text format.
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+var (
+	// validationBuckets bound validation latency; rules are regular
+	// expressions, so validations take well under a millisecond.
+	validationBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.1}
+	// generationBuckets bound message generation, which runs git and the
+	// semantic plugins.
+	generationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+)
+
+// Metrics collects counters and latency histograms. It is safe for
+// concurrent use, and a nil *Metrics records nothing, so callers need not
+// check whether metrics are enabled.
+type Metrics struct {
+	mu          sync.Mutex
+	validations map[string]uint64
+	failures    map[string]uint64
+	generations map[string]uint64
+	validation  histogram
+	generation  histogram
+}
+
+// New creates empty metrics.
+func New() *Metrics {
+	return &Metrics{
+		validations: make(map[string]uint64),
+		failures:    make(map[string]uint64),
+		generations: make(map[string]uint64),
+		validation:  newHistogram(validationBuckets),
+		generation:  newHistogram(generationBuckets),
+	}
+}
+
+// ObserveValidation records a validation that took d, counting its errors
+// by the rule (validation error field) that failed.
+func (m *Metrics) ObserveValidation(d time.Duration, result *validator.ValidationResult) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	outcome := "valid"
+	if !result.Valid {
+		outcome = "invalid"
+	}
+	m.validations[outcome]++
+	for _, err := range result.Errors {
+		rule := "other"
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) && validationErr.Field != "" {
+			rule = validationErr.Field
+		}
+		m.failures[rule]++
+	}
+	m.validation.observe(d.Seconds())
+}
+
+// ObserveGeneration records a message generation, including its semantic
+// plugin analysis, that took d and failed with err if not nil.
+func (m *Metrics) ObserveGeneration(d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	m.generations[outcome]++
+	m.generation.observe(d.Seconds())
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.Expose(w)
+}
+
+// Expose writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) Expose(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeCounter(w, "fcgh_validations_total", "Commit messages validated, by result.", "result", m.validations)
+	writeCounter(w, "fcgh_validation_failures_total", "Validation errors, by the rule that failed.", "rule", m.failures)
+	m.validation.write(w, "fcgh_validation_duration_seconds", "Time spent validating a commit message.")
+	writeCounter(w, "fcgh_generations_total", "Commit messages generated, by result.", "result", m.generations)
+	m.generation.write(w, "fcgh_generation_duration_seconds", "Time spent generating a commit message, including semantic plugin analysis.")
+}
+
+func writeCounter(w io.Writer, name, help, label string, values map[string]uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s{%s=%q} %d\n", name, label, key, values[key])
+	}
+}
+
+// histogram counts observations in cumulative buckets, as Prometheus
+// histograms do.
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) histogram {
+	return histogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+func (h *histogram) write(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for i, bound := range h.bounds {
+		fmt.Fprintf(w, "%s_bucket{le=%q} %d\n", name, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", name, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+)
+
+func TestMetrics(t *testing.T) {
+	v, err := validator.New(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New()
+	m.ObserveValidation(200*time.Microsecond, v.Validate(context.Background(), "feat: add login"))
+	m.ObserveValidation(2*time.Millisecond, v.Validate(context.Background(), "wip: add login"))
+	m.ObserveGeneration(3*time.Second, errors.New("not a git repository"))
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE fcgh_validations_total counter\n",
+		`fcgh_validations_total{result="invalid"} 1` + "\n",
+		`fcgh_validations_total{result="valid"} 1` + "\n",
+		`fcgh_validation_failures_total{rule="type"} 1` + "\n",
+		`fcgh_validation_duration_seconds_bucket{le="0.00025"} 1` + "\n",
+		`fcgh_validation_duration_seconds_bucket{le="+Inf"} 2` + "\n",
+		"fcgh_validation_duration_seconds_sum 0.0022\n",
+		`fcgh_generations_total{result="error"} 1` + "\n",
+		`fcgh_generation_duration_seconds_bucket{le="2.5"} 0` + "\n",
+		`fcgh_generation_duration_seconds_bucket{le="5"} 1` + "\n",
+		"fcgh_generation_duration_seconds_count 1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	var disabled *Metrics
+	disabled.ObserveValidation(time.Millisecond, &validator.ValidationResult{Valid: true})
+	disabled.ObserveGeneration(time.Second, nil)
+}
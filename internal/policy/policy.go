@@ -0,0 +1,218 @@
+// Package policy installs and verifies signed configuration bundles.
+//
+// A bundle is a tar archive of config files (such as fast-cc-config.yaml)
+// published next to an ed25519 signature of the archive at "<url>.sig". The
+// installed archive and signature are kept so hooks can check that neither
+// the bundle nor the config files extracted from it were modified.
+package policy
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Dir is the directory, inside the config directory, holding the
+	// installed bundle and its signature.
+	Dir = "policy"
+	// BundleFile and SignatureFile are the installed bundle and signature.
+	BundleFile    = "bundle.tar"
+	SignatureFile = "bundle.tar.sig"
+
+	maxBundleSize  = 10 * 1024 * 1024
+	requestTimeout = 30 * time.Second
+)
+
+var (
+	// ErrNotInstalled indicates no policy bundle has been pulled.
+	ErrNotInstalled = errors.New("no policy bundle installed")
+	// ErrBadSignature indicates the bundle was not signed by the pinned key.
+	ErrBadSignature = errors.New("policy bundle signature does not match the pinned public key")
+)
+
+// ParsePublicKey decodes a base64 ed25519 public key.
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("decoding policy public key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("policy public key must be %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Verify checks a bundle signature, given raw or base64 encoded.
+func Verify(bundle, signature []byte, key ed25519.PublicKey) error {
+	if len(signature) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return ErrBadSignature
+		}
+		signature = decoded
+	}
+	if !ed25519.Verify(key, bundle, signature) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// ReadBundle returns the regular files in a bundle by their relative path.
+// Absolute paths and paths leaving the bundle are rejected.
+func ReadBundle(bundle []byte) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	reader := tar.NewReader(bytes.NewReader(bundle))
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading policy bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") || name == Dir || strings.HasPrefix(name, Dir+"/") {
+			return nil, fmt.Errorf("policy bundle contains an invalid path %q", header.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(reader, maxBundleSize))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s from policy bundle: %w", name, err)
+		}
+		files[name] = data
+	}
+	if len(files) == 0 {
+		return nil, errors.New("policy bundle contains no files")
+	}
+	return files, nil
+}
+
+// Pull downloads the bundle at url and its signature at url+".sig",
+// verifies them and installs the bundle into configDir. It returns the
+// installed file paths relative to configDir.
+func Pull(ctx context.Context, url string, key ed25519.PublicKey, configDir string) ([]string, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	bundle, err := fetch(ctx, client, url)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := fetch(ctx, client, url+".sig")
+	if err != nil {
+		return nil, err
+	}
+	return Install(bundle, signature, key, configDir)
+}
+
+// Install verifies a bundle and writes its files into configDir, keeping the
+// bundle and signature for later checks. Nothing is written unless the
+// signature is valid.
+func Install(bundle, signature []byte, key ed25519.PublicKey, configDir string) ([]string, error) {
+	if err := Verify(bundle, signature, key); err != nil {
+		return nil, err
+	}
+	files, err := ReadBundle(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for name, data := range files {
+		target := filepath.Join(configDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+			return nil, fmt.Errorf("creating directory for %s: %w", name, err)
+		}
+		if err := os.WriteFile(target, data, 0o600); err != nil {
+			return nil, fmt.Errorf("installing %s: %w", name, err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	policyDir := filepath.Join(configDir, Dir)
+	if err := os.MkdirAll(policyDir, 0o750); err != nil {
+		return nil, fmt.Errorf("creating policy directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(policyDir, BundleFile), bundle, 0o600); err != nil {
+		return nil, fmt.Errorf("saving policy bundle: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(policyDir, SignatureFile), signature, 0o600); err != nil {
+		return nil, fmt.Errorf("saving policy signature: %w", err)
+	}
+	return names, nil
+}
+
+// CheckInstalled verifies the installed bundle's signature and that every
+// file it installed is unchanged.
+func CheckInstalled(configDir string, key ed25519.PublicKey) error {
+	policyDir := filepath.Join(configDir, Dir)
+	bundle, err := os.ReadFile(filepath.Join(policyDir, BundleFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotInstalled
+	}
+	if err != nil {
+		return fmt.Errorf("reading policy bundle: %w", err)
+	}
+	signature, err := os.ReadFile(filepath.Join(policyDir, SignatureFile))
+	if err != nil {
+		return fmt.Errorf("reading policy signature: %w", err)
+	}
+	if err := Verify(bundle, signature, key); err != nil {
+		return err
+	}
+
+	files, err := ReadBundle(bundle)
+	if err != nil {
+		return err
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(configDir, filepath.FromSlash(name)))
+		if err != nil {
+			return fmt.Errorf("policy file %s is missing: %w", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			return fmt.Errorf("policy file %s was modified after installation", name)
+		}
+	}
+	return nil
+}
+
+// fetch downloads a URL, bounded by maxBundleSize.
+func fetch(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request for %s: %w", url, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected HTTP status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBundleSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	if len(data) > maxBundleSize {
+		return nil, fmt.Errorf("fetching %s: larger than %d bytes", url, maxBundleSize)
+	}
+	return data, nil
+}
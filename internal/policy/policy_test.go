@@ -0,0 +1,127 @@
+package policy
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makeBundle builds a tar archive from name/content pairs
+func makeBundle(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	for name, content := range files {
+		if err := writer.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return public, private
+}
+
+func TestPullAndCheckInstalled(t *testing.T) {
+	public, private := newKey(t)
+	bundle := makeBundle(t, map[string]string{"fast-cc-config.yaml": "types: [feat, fix]\nmax_subject_length: 72\n"})
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(private, bundle))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/policy.tar":
+			_, _ = w.Write(bundle)
+		case "/policy.tar.sig":
+			_, _ = w.Write([]byte(signature + "\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := CheckInstalled(dir, public); !errors.Is(err, ErrNotInstalled) {
+		t.Fatalf("expected ErrNotInstalled before pulling, got %v", err)
+	}
+
+	installed, err := Pull(context.Background(), server.URL+"/policy.tar", public, dir)
+	if err != nil {
+		t.Fatalf("Pull() error = %v", err)
+	}
+	if len(installed) != 1 || installed[0] != "fast-cc-config.yaml" {
+		t.Errorf("unexpected installed files %v", installed)
+	}
+	if err := CheckInstalled(dir, public); err != nil {
+		t.Errorf("CheckInstalled() error = %v", err)
+	}
+
+	// Editing an installed file is detected
+	configPath := filepath.Join(dir, "fast-cc-config.yaml")
+	if err := os.WriteFile(configPath, []byte("types: [wip]\nmax_subject_length: 200\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckInstalled(dir, public); err == nil {
+		t.Error("expected a modified config file to fail verification")
+	}
+
+	// Replacing the stored bundle is detected by its signature
+	if err := os.WriteFile(filepath.Join(dir, Dir, BundleFile), makeBundle(t, map[string]string{"fast-cc-config.yaml": "types: [wip]\n"}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckInstalled(dir, public); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature for a replaced bundle, got %v", err)
+	}
+}
+
+func TestInstall_RejectsBadBundles(t *testing.T) {
+	public, private := newKey(t)
+	otherPublic, _ := newKey(t)
+	dir := t.TempDir()
+
+	bundle := makeBundle(t, map[string]string{"fast-cc-config.yaml": "types: [feat]\n"})
+	if _, err := Install(bundle, ed25519.Sign(private, bundle), otherPublic, dir); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature for another key, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "fast-cc-config.yaml")); !os.IsNotExist(err) {
+		t.Error("expected nothing to be installed without a valid signature")
+	}
+
+	for _, name := range []string{"../escape.yaml", "/etc/fast-cc.yaml", "policy/bundle.tar"} {
+		evil := makeBundle(t, map[string]string{name: "x"})
+		if _, err := Install(evil, ed25519.Sign(private, evil), public, dir); err == nil {
+			t.Errorf("expected bundle path %q to be rejected", name)
+		}
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	public, _ := newKey(t)
+	if key, err := ParsePublicKey(base64.StdEncoding.EncodeToString(public)); err != nil || !key.Equal(public) {
+		t.Errorf("ParsePublicKey() = %v, %v", key, err)
+	}
+	for _, encoded := range []string{"not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := ParsePublicKey(encoded); err == nil {
+			t.Errorf("ParsePublicKey(%q) expected an error", encoded)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+// Package prcomment annotates the GitHub pull request or GitLab merge
+// request a CI job runs for: it keeps a sticky comment, updated on every run
+// instead of adding a new one, and syncs labels.
+package prcomment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// GitHub is the provider of GitHub Actions pull request jobs
+	GitHub = "github"
+	// GitLab is the provider of GitLab CI merge request pipelines
+	GitLab = "gitlab"
+
+	// EnvGitLabToken holds the token used to write merge request notes and
+	// labels, which the CI job token cannot
+	EnvGitLabToken = "GITLAB_TOKEN"
+
+	defaultGitHubAPI    = "https://api.github.com"
+	defaultGitHubServer = "https://github.com"
+	requestTimeout      = 10 * time.Second
+	pageSize            = 100
+)
+
+// ErrNotInPullRequest is returned by Detect outside a pull request job
+var ErrNotInPullRequest = errors.New("not running in a GitHub Actions pull request job or GitLab CI merge request pipeline")
+
+var pullRefRegex = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+// Target is the pull request a CI job runs for
+type Target struct {
+	// Provider is GitHub or GitLab
+	Provider string
+	// API is the base URL of the provider's REST API
+	API string
+	// Project is the "owner/name" GitHub repository or the GitLab project ID
+	Project string
+	// Number is the pull request number or merge request IID
+	Number int
+	// Title is the pull request title, when the environment provides it
+	Title string
+	// Description is the pull request body, when the environment provides it
+	Description string
+	Token       string
+	// Range selects the pull request's commits, such as "origin/main..HEAD"
+	Range string
+	// IssueURL is where #123 references link to
+	IssueURL string
+}
+
+// Detect reads the pull request of the current CI job from the environment
+// (os.Getenv when getenv is nil). It returns ErrNotInPullRequest outside a
+// pull request job.
+func Detect(getenv func(string) string) (*Target, error) {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		return detectGitHub(getenv)
+	case getenv("GITLAB_CI") == "true":
+		return detectGitLab(getenv)
+	}
+	return nil, ErrNotInPullRequest
+}
+
+// detectGitHub reads a GitHub Actions pull_request or pull_request_target job
+func detectGitHub(getenv func(string) string) (*Target, error) {
+	var event struct {
+		PullRequest struct {
+			Number int    `json:"number"`
+			Title  string `json:"title"`
+			Body   string `json:"body"`
+		} `json:"pull_request"`
+	}
+	if eventPath := getenv("GITHUB_EVENT_PATH"); eventPath != "" {
+		data, err := os.ReadFile(eventPath) // #nosec G304 - event file written by the runner
+		if err != nil {
+			return nil, fmt.Errorf("reading GitHub event: %w", err)
+		}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("parsing GitHub event: %w", err)
+		}
+	}
+	// pull_request_target jobs run on the base branch, so only the event
+	// names their pull request
+	number := event.PullRequest.Number
+	if match := pullRefRegex.FindStringSubmatch(getenv("GITHUB_REF")); match != nil {
+		number, _ = strconv.Atoi(match[1])
+	}
+	base := getenv("GITHUB_BASE_REF")
+	if number == 0 || base == "" {
+		return nil, ErrNotInPullRequest
+	}
+
+	repo := getenv("GITHUB_REPOSITORY")
+	if repo == "" {
+		return nil, errors.New("GITHUB_REPOSITORY is not set")
+	}
+	token := getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil, errors.New("GITHUB_TOKEN is not set; pass it to the job with `env: GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}`")
+	}
+	return &Target{
+		Provider:    GitHub,
+		API:         envOr(getenv, "GITHUB_API_URL", defaultGitHubAPI),
+		Project:     repo,
+		Number:      number,
+		Title:       event.PullRequest.Title,
+		Description: event.PullRequest.Body,
+		Token:       token,
+		Range:       "origin/" + base + "..HEAD",
+		IssueURL:    envOr(getenv, "GITHUB_SERVER_URL", defaultGitHubServer) + "/" + repo + "/issues",
+	}, nil
+}
+
+// detectGitLab reads a GitLab CI merge request pipeline
+func detectGitLab(getenv func(string) string) (*Target, error) {
+	number, err := strconv.Atoi(getenv("CI_MERGE_REQUEST_IID"))
+	if err != nil {
+		return nil, ErrNotInPullRequest
+	}
+	token := getenv(EnvGitLabToken)
+	if token == "" {
+		return nil, fmt.Errorf("%s is not set; add a project access token with the api scope as a CI/CD variable", EnvGitLabToken)
+	}
+	revisions := "origin/" + getenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME") + "..HEAD"
+	if base := getenv("CI_MERGE_REQUEST_DIFF_BASE_SHA"); base != "" {
+		revisions = base + "..HEAD"
+	}
+	return &Target{
+		Provider:    GitLab,
+		API:         getenv("CI_API_V4_URL"),
+		Project:     getenv("CI_PROJECT_ID"),
+		Number:      number,
+		Title:       getenv("CI_MERGE_REQUEST_TITLE"),
+		Description: getenv("CI_MERGE_REQUEST_DESCRIPTION"),
+		Token:       token,
+		Range:       revisions,
+		IssueURL:    getenv("CI_PROJECT_URL") + "/-/issues",
+	}, nil
+}
+
+// envOr returns the environment variable, or fallback when it is empty
+func envOr(getenv func(string) string, name, fallback string) string {
+	if value := getenv(name); value != "" {
+		return strings.TrimRight(value, "/")
+	}
+	return fallback
+}
+
+// Client annotates a pull request
+type Client struct {
+	target     Target
+	httpClient *http.Client
+}
+
+// New creates a client for the pull request
+func New(target Target) *Client {
+	return &Client{target: target, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+// comment is a pull request comment as both APIs return it
+type comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// Upsert posts body as a comment starting with marker, or updates the
+// comment that already starts with it. It reports whether a comment was
+// created.
+func (c *Client) Upsert(ctx context.Context, marker, body string) (bool, error) {
+	body = marker + "\n" + body
+	existing, err := c.find(ctx, marker)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		if existing.Body == body {
+			return false, nil
+		}
+		method := http.MethodPatch
+		if c.target.Provider == GitLab {
+			method = http.MethodPut
+		}
+		return false, c.writeComment(ctx, method, c.commentURL(existing.ID), body)
+	}
+	return true, c.writeComment(ctx, http.MethodPost, c.commentsURL(), body)
+}
+
+// SyncLabels adds the labels to the pull request and removes the stale ones
+// it has. Labels that do not exist in the repository yet are created.
+func (c *Client) SyncLabels(ctx context.Context, add, stale []string) error {
+	api := strings.TrimRight(c.target.API, "/")
+	if c.target.Provider == GitLab {
+		endpoint := fmt.Sprintf("%s/projects/%s/merge_requests/%d", api, url.PathEscape(c.target.Project), c.target.Number)
+		payload := map[string]string{"add_labels": strings.Join(add, ","), "remove_labels": strings.Join(stale, ",")}
+		if err := c.send(ctx, http.MethodPut, endpoint, payload); err != nil {
+			return fmt.Errorf("updating labels: %w", err)
+		}
+		return nil
+	}
+
+	labelsURL := fmt.Sprintf("%s/repos/%s/issues/%d/labels", api, c.target.Project, c.target.Number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, labelsURL, http.NoBody)
+	if err != nil {
+		return err
+	}
+	var current []struct {
+		Name string `json:"name"`
+	}
+	if err := c.do(req, &current); err != nil {
+		return fmt.Errorf("listing labels: %w", err)
+	}
+	has := make(map[string]bool, len(current))
+	for _, label := range current {
+		has[label.Name] = true
+	}
+
+	var missing []string
+	for _, label := range add {
+		if !has[label] {
+			missing = append(missing, label)
+		}
+	}
+	if len(missing) > 0 {
+		if err := c.send(ctx, http.MethodPost, labelsURL, map[string][]string{"labels": missing}); err != nil {
+			return fmt.Errorf("adding labels: %w", err)
+		}
+	}
+	for _, label := range stale {
+		if !has[label] {
+			continue
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodDelete, labelsURL+"/"+url.PathEscape(label), http.NoBody)
+		if err != nil {
+			return err
+		}
+		if err := c.do(req, nil); err != nil {
+			return fmt.Errorf("removing label %s: %w", label, err)
+		}
+	}
+	return nil
+}
+
+// find returns the first comment starting with marker, or nil
+func (c *Client) find(ctx context.Context, marker string) (*comment, error) {
+	for page := 1; ; page++ {
+		endpoint := fmt.Sprintf("%s?per_page=%d&page=%d", c.commentsURL(), pageSize, page)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+		var comments []comment
+		if err := c.do(req, &comments); err != nil {
+			return nil, fmt.Errorf("listing comments: %w", err)
+		}
+		for i := range comments {
+			if strings.HasPrefix(comments[i].Body, marker) {
+				return &comments[i], nil
+			}
+		}
+		if len(comments) < pageSize {
+			return nil, nil
+		}
+	}
+}
+
+// writeComment posts or updates a comment
+func (c *Client) writeComment(ctx context.Context, method, endpoint, body string) error {
+	if err := c.send(ctx, method, endpoint, map[string]string{"body": body}); err != nil {
+		return fmt.Errorf("writing comment: %w", err)
+	}
+	return nil
+}
+
+// send sends payload as JSON to the endpoint
+func (c *Client) send(ctx context.Context, method, endpoint string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req, nil)
+}
+
+// do sends an authenticated request and decodes the JSON response into out
+// (unless it is nil)
+func (c *Client) do(req *http.Request, out any) error {
+	if c.target.Provider == GitLab {
+		req.Header.Set("PRIVATE-TOKEN", c.target.Token)
+	} else {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("Authorization", "Bearer "+c.target.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// commentsURL is the endpoint listing and creating the pull request's
+// comments
+func (c *Client) commentsURL() string {
+	api := strings.TrimRight(c.target.API, "/")
+	if c.target.Provider == GitLab {
+		return fmt.Sprintf("%s/projects/%s/merge_requests/%d/notes", api, url.PathEscape(c.target.Project), c.target.Number)
+	}
+	return fmt.Sprintf("%s/repos/%s/issues/%d/comments", api, c.target.Project, c.target.Number)
+}
+
+// commentURL is the endpoint updating a comment
+func (c *Client) commentURL(id int64) string {
+	if c.target.Provider == GitLab {
+		return fmt.Sprintf("%s/%d", c.commentsURL(), id)
+	}
+	return fmt.Sprintf("%s/repos/%s/issues/comments/%d", strings.TrimRight(c.target.API, "/"), c.target.Project, id)
+}
@@ -0,0 +1,229 @@
+package prcomment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const marker = "<!-- test -->"
+
+func envMap(env map[string]string) func(string) string {
+	return func(name string) string { return env[name] }
+}
+
+func TestDetectGitHub(t *testing.T) {
+	target, err := Detect(envMap(map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_REF":        "refs/pull/42/merge",
+		"GITHUB_BASE_REF":   "main",
+		"GITHUB_REPOSITORY": "o/r",
+		"GITHUB_TOKEN":      "secret",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Target{Provider: GitHub, API: defaultGitHubAPI, Project: "o/r", Number: 42, Token: "secret",
+		Range: "origin/main..HEAD", IssueURL: "https://github.com/o/r/issues"}
+	if *target != want {
+		t.Errorf("Detect() = %+v, want %+v", *target, want)
+	}
+}
+
+func TestDetectGitHubEventFile(t *testing.T) {
+	event := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(event, []byte(`{"pull_request":{"number":7,"title":"feat: add login","body":"Adds a login form"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	target, err := Detect(envMap(map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_REF":        "refs/heads/main",
+		"GITHUB_EVENT_PATH": event,
+		"GITHUB_BASE_REF":   "main",
+		"GITHUB_REPOSITORY": "o/r",
+		"GITHUB_TOKEN":      "secret",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.Number != 7 || target.Title != "feat: add login" || target.Description != "Adds a login form" {
+		t.Errorf("Number, Title, Description = %d, %q, %q", target.Number, target.Title, target.Description)
+	}
+}
+
+func TestDetectGitLab(t *testing.T) {
+	target, err := Detect(envMap(map[string]string{
+		"GITLAB_CI":                      "true",
+		"CI_MERGE_REQUEST_IID":           "5",
+		"CI_MERGE_REQUEST_DIFF_BASE_SHA": "abc123",
+		"CI_API_V4_URL":                  "https://gitlab.example.com/api/v4",
+		"CI_PROJECT_ID":                  "99",
+		"CI_PROJECT_URL":                 "https://gitlab.example.com/g/p",
+		EnvGitLabToken:                   "secret",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Target{Provider: GitLab, API: "https://gitlab.example.com/api/v4", Project: "99", Number: 5, Token: "secret",
+		Range: "abc123..HEAD", IssueURL: "https://gitlab.example.com/g/p/-/issues"}
+	if *target != want {
+		t.Errorf("Detect() = %+v, want %+v", *target, want)
+	}
+}
+
+func TestDetectErrors(t *testing.T) {
+	if _, err := Detect(envMap(nil)); !errors.Is(err, ErrNotInPullRequest) {
+		t.Errorf("Detect() outside CI = %v, want ErrNotInPullRequest", err)
+	}
+	// A push build is not a pull request
+	if _, err := Detect(envMap(map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_REF": "refs/heads/main"})); !errors.Is(err, ErrNotInPullRequest) {
+		t.Errorf("Detect() on push = %v, want ErrNotInPullRequest", err)
+	}
+	_, err := Detect(envMap(map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_REF": "refs/pull/1/merge", "GITHUB_BASE_REF": "main", "GITHUB_REPOSITORY": "o/r"}))
+	if err == nil || !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Errorf("Detect() without token = %v", err)
+	}
+}
+
+// fakeAPI serves the comment endpoints of both providers from memory
+type fakeAPI struct {
+	mu       sync.Mutex
+	comments []comment
+	labels   []string
+	requests []string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.EscapedPath())
+	if r.Header.Get("Authorization") != "Bearer secret" && r.Header.Get("PRIVATE-TOKEN") != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/labels") && r.Method == http.MethodGet {
+		var labels []map[string]string
+		for _, label := range f.labels {
+			labels = append(labels, map[string]string{"name": label})
+		}
+		_ = json.NewEncoder(w).Encode(labels)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		_ = json.NewEncoder(w).Encode(f.comments)
+	case http.MethodPost, http.MethodPatch, http.MethodPut:
+		var c comment
+		_ = json.NewDecoder(r.Body).Decode(&c)
+		if r.Method == http.MethodPost {
+			c.ID = int64(len(f.comments) + 1)
+			f.comments = append(f.comments, c)
+		} else {
+			for i := range f.comments {
+				if strings.HasSuffix(r.URL.Path, fmt.Sprintf("/%d", f.comments[i].ID)) {
+					f.comments[i].Body = c.Body
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(c)
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	tests := []struct {
+		provider   string
+		list       string
+		update     string
+		updateVerb string
+	}{
+		{GitHub, "/repos/o/r/issues/3/comments", "/repos/o/r/issues/comments/2", http.MethodPatch},
+		{GitLab, "/projects/o%2Fr/merge_requests/3/notes", "/projects/o%2Fr/merge_requests/3/notes/2", http.MethodPut},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			api := &fakeAPI{comments: []comment{{ID: 1, Body: "LGTM"}}}
+			server := httptest.NewServer(api)
+			defer server.Close()
+			client := New(Target{Provider: tt.provider, API: server.URL, Project: "o/r", Number: 3, Token: "secret"})
+
+			created, err := client.Upsert(context.Background(), marker, "first")
+			if err != nil || !created {
+				t.Fatalf("Upsert() = %v, %v; want a new comment", created, err)
+			}
+			if created, err = client.Upsert(context.Background(), marker, "second"); err != nil || created {
+				t.Fatalf("Upsert() = %v, %v; want the comment updated", created, err)
+			}
+			// Unchanged notes are not written again
+			if _, err = client.Upsert(context.Background(), marker, "second"); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(api.comments) != 2 || api.comments[1].Body != marker+"\nsecond" {
+				t.Errorf("comments = %+v", api.comments)
+			}
+			want := []string{
+				"GET " + tt.list, "POST " + tt.list,
+				"GET " + tt.list, tt.updateVerb + " " + tt.update,
+				"GET " + tt.list,
+			}
+			if strings.Join(api.requests, "\n") != strings.Join(want, "\n") {
+				t.Errorf("requests = %q, want %q", api.requests, want)
+			}
+		})
+	}
+}
+
+func TestUpsertHTTPError(t *testing.T) {
+	server := httptest.NewServer(&fakeAPI{})
+	defer server.Close()
+	client := New(Target{Provider: GitHub, API: server.URL, Project: "o/r", Number: 3, Token: "wrong"})
+	if _, err := client.Upsert(context.Background(), marker, "notes"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Upsert() = %v, want an HTTP status error", err)
+	}
+}
+
+func TestSyncLabelsGitHub(t *testing.T) {
+	api := &fakeAPI{labels: []string{"bug", "documentation", "triage"}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	client := New(Target{Provider: GitHub, API: server.URL, Project: "o/r", Number: 3, Token: "secret"})
+
+	if err := client.SyncLabels(context.Background(), []string{"bug", "enhancement"}, []string{"documentation", "breaking change"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"GET /repos/o/r/issues/3/labels",
+		"POST /repos/o/r/issues/3/labels",
+		"DELETE /repos/o/r/issues/3/labels/documentation",
+	}
+	if strings.Join(api.requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests = %q, want %q", api.requests, want)
+	}
+}
+
+func TestSyncLabelsGitLab(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.EscapedPath() != "/projects/99/merge_requests/5" || r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+	client := New(Target{Provider: GitLab, API: server.URL, Project: "99", Number: 5, Token: "secret"})
+
+	if err := client.SyncLabels(context.Background(), []string{"bug", "enhancement"}, []string{"documentation"}); err != nil {
+		t.Fatal(err)
+	}
+	if got["add_labels"] != "bug,enhancement" || got["remove_labels"] != "documentation" {
+		t.Errorf("payload = %v", got)
+	}
+}
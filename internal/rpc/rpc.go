@@ -0,0 +1,273 @@
+// Package rpc serves validation, message generation and config lookups as
+// line-delimited JSON-RPC 2.0 over stdio, so GUI clients can keep one warm
+// process instead of starting fcgh for every check.
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/metrics"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/validator"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/ccgen"
+)
+
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	// codeFailed reports a method that ran but failed, such as generation
+	// outside a git repository.
+	codeFailed = -32000
+)
+
+// Options configures a Server.
+type Options struct {
+	// Load reads the config; it is called on start and by the reload method.
+	Load func() (*config.Config, error)
+	// Generate generates a commit message from the changes staged in dir
+	// (the working directory when empty); nil disables the generate method.
+	Generate func(ctx context.Context, cfg *config.Config, dir string) (*ccgen.Result, error)
+	// Metrics records validations and generations (nil records nothing).
+	Metrics *metrics.Metrics
+}
+
+// Server answers JSON-RPC requests, one per line, until its input ends.
+type Server struct {
+	opts      Options
+	cfg       *config.Config
+	validator *validator.Validator
+	out       io.Writer
+}
+
+// NewServer creates a server and loads its config.
+func NewServer(opts Options) (*Server, error) {
+	s := &Server{opts: opts}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Server) reload() error {
+	cfg, err := s.opts.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	// Tickets are not verified remotely: clients validate as the user types
+	v, err := validator.New(cfg)
+	if err != nil {
+		return fmt.Errorf("creating validator: %w", err)
+	}
+	s.cfg, s.validator = cfg, v
+	return nil
+}
+
+type request struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *responseError  `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type validateParams struct {
+	// Message is validated as given; File is read like the commit-msg hook
+	// reads it, without comment lines.
+	Message string `json:"message"`
+	File    string `json:"file"`
+}
+
+type validateResult struct {
+	Valid    bool                         `json:"valid"`
+	Errors   []*validator.ValidationError `json:"errors"`
+	Warnings []string                     `json:"warnings"`
+}
+
+type generateParams struct {
+	Dir string `json:"dir"`
+}
+
+type generateResult struct {
+	Message    string `json:"message"`
+	HasChanges bool   `json:"has_changes"`
+}
+
+type configParams struct {
+	// Key is a dotted config key such as "jira.url"; empty returns the
+	// whole config.
+	Key string `json:"key"`
+}
+
+// Serve reads requests from r and writes responses to w until r ends or ctx
+// is cancelled. Notifications, requests without an id, get no response.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.out = w
+	reader := bufio.NewReader(r)
+	for ctx.Err() == nil {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("reading request: %w", err)
+		}
+		if len(strings.TrimSpace(string(line))) > 0 {
+			if err := s.handleLine(ctx, line); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// handleLine answers one line. Only write failures are returned; bad
+// requests are answered with an error response.
+func (s *Server) handleLine(ctx context.Context, line []byte) error {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()})
+	}
+	if req.Method == "" {
+		return s.reply(req.ID, nil, &responseError{Code: codeInvalidRequest, Message: "request without method"})
+	}
+
+	result, failure := s.handle(ctx, req)
+	if req.ID == nil {
+		return nil
+	}
+	return s.reply(req.ID, result, failure)
+}
+
+func (s *Server) handle(ctx context.Context, req request) (any, *responseError) {
+	switch req.Method {
+	case "validate":
+		var params validateParams
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.validate(ctx, params)
+	case "generate":
+		var params generateParams
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		if s.opts.Generate == nil {
+			return nil, &responseError{Code: codeMethodNotFound, Message: "generate is not available"}
+		}
+		start := time.Now()
+		result, err := s.opts.Generate(ctx, s.cfg, params.Dir)
+		s.opts.Metrics.ObserveGeneration(time.Since(start), err)
+		if err != nil {
+			return nil, &responseError{Code: codeFailed, Message: err.Error()}
+		}
+		return generateResult{Message: result.Message, HasChanges: result.HasChanges}, nil
+	case "config":
+		var params configParams
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.lookup(params.Key)
+	case "reload":
+		if err := s.reload(); err != nil {
+			return nil, &responseError{Code: codeFailed, Message: err.Error()}
+		}
+		return nil, nil
+	default:
+		return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not supported: %s", req.Method)}
+	}
+}
+
+func (s *Server) validate(ctx context.Context, params validateParams) (any, *responseError) {
+	message := params.Message
+	if params.File != "" {
+		var err error
+		if message, err = validator.ReadMessageFile(params.File); err != nil {
+			return nil, &responseError{Code: codeFailed, Message: err.Error()}
+		}
+	}
+
+	start := time.Now()
+	result := s.validator.Validate(ctx, message)
+	s.opts.Metrics.ObserveValidation(time.Since(start), result)
+	out := validateResult{Valid: result.Valid, Errors: result.ValidationErrors(), Warnings: result.Warnings}
+	if out.Warnings == nil {
+		out.Warnings = []string{}
+	}
+	return out, nil
+}
+
+// lookup returns a config value keyed by its YAML names, as the config file
+// spells them.
+func (s *Server) lookup(key string) (any, *responseError) {
+	var value any = s.cfg
+	if key != "" {
+		var err error
+		if value, err = s.cfg.Lookup(key); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+	}
+
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, &responseError{Code: codeFailed, Message: fmt.Sprintf("encoding %s: %v", key, err)}
+	}
+	var plain any
+	if err := yaml.Unmarshal(data, &plain); err != nil {
+		return nil, &responseError{Code: codeFailed, Message: fmt.Sprintf("encoding %s: %v", key, err)}
+	}
+	return plain, nil
+}
+
+// decodeParams decodes request params into v; absent params leave v zero.
+func decodeParams(params json.RawMessage, v any) *responseError {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) reply(id json.RawMessage, result any, failure *responseError) error {
+	resp := response{JSONRPC: "2.0", ID: id, Error: failure}
+	if id == nil {
+		resp.ID = json.RawMessage("null")
+	}
+	if failure == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("encoding result: %w", err)
+		}
+		resp.Result = data
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("encoding response: %w", err)
+	}
+	if _, err := fmt.Fprintf(s.out, "%s\n", data); err != nil {
+		return fmt.Errorf("writing response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/ccgen"
+)
+
+// serve runs a session of requests, one per line, and returns the decoded
+// responses.
+func serve(t *testing.T, opts Options, lines ...string) []map[string]any {
+	t.Helper()
+	server, err := NewServer(opts)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	var out strings.Builder
+	if err := server.Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	var sent []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var msg map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			t.Fatalf("response %q is not JSON: %v", scanner.Text(), err)
+		}
+		sent = append(sent, msg)
+	}
+	return sent
+}
+
+func testConfig() (*config.Config, error) {
+	cfg := config.Default()
+	cfg.Types = []string{"feat", "fix"}
+	return cfg, nil
+}
+
+func TestServer_Validate(t *testing.T) {
+	sent := serve(t, Options{Load: testConfig},
+		`{"jsonrpc":"2.0","id":1,"method":"validate","params":{"message":"wip: add login"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"validate","params":{"message":"feat: add login"}}`,
+		`{"jsonrpc":"2.0","method":"validate","params":{"message":"notified"}}`,
+		``,
+		`{"jsonrpc":"2.0","id":3,"method":"validate","params":{"message":7}}`,
+	)
+	if len(sent) != 3 {
+		t.Fatalf("server sent %d responses, want 3: %v", len(sent), sent)
+	}
+
+	invalid := sent[0]["result"].(map[string]any)
+	errs := invalid["errors"].([]any)
+	if invalid["valid"] != false || len(errs) != 1 {
+		t.Fatalf("unexpected result for an invalid message: %v", invalid)
+	}
+	want := map[string]any{"field": "type", "message": errs[0].(map[string]any)["message"], "value": "wip", "line": float64(1), "column": float64(1), "end_column": float64(4)}
+	if fmt.Sprint(errs[0]) != fmt.Sprint(want) {
+		t.Errorf("error = %v, want %v", errs[0], want)
+	}
+
+	if valid := sent[1]["result"].(map[string]any); valid["valid"] != true || len(valid["errors"].([]any)) != 0 {
+		t.Errorf("unexpected result for a valid message: %v", valid)
+	}
+	if failure, ok := sent[2]["error"].(map[string]any); !ok || failure["code"] != float64(codeInvalidParams) || sent[2]["id"] != float64(3) {
+		t.Errorf("expected invalid params for id 3, got %v", sent[2])
+	}
+}
+
+func TestServer_GenerateAndConfig(t *testing.T) {
+	loads := 0
+	opts := Options{
+		Load: func() (*config.Config, error) {
+			loads++
+			cfg, err := testConfig()
+			cfg.MaxSubjectLength = 50 + loads
+			return cfg, err
+		},
+		Generate: func(_ context.Context, _ *config.Config, dir string) (*ccgen.Result, error) {
+			if dir == "/nowhere" {
+				return nil, errors.New("not a git repository")
+			}
+			return &ccgen.Result{Message: "feat: add login", HasChanges: true}, nil
+		},
+	}
+
+	sent := serve(t, opts,
+		`{"jsonrpc":"2.0","id":1,"method":"generate"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"generate","params":{"dir":"/nowhere"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"config","params":{"key":"types"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"config","params":{"key":"no_such_key"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"reload"}`,
+		`{"jsonrpc":"2.0","id":6,"method":"config"}`,
+		`{"jsonrpc":"2.0","id":7,"method":"commit"}`,
+		`not json`,
+	)
+	if len(sent) != 8 {
+		t.Fatalf("server sent %d responses, want 8: %v", len(sent), sent)
+	}
+
+	if result := sent[0]["result"].(map[string]any); result["message"] != "feat: add login" || result["has_changes"] != true {
+		t.Errorf("unexpected generate result: %v", result)
+	}
+	if fmt.Sprint(sent[2]["result"]) != "[feat fix]" {
+		t.Errorf("config types = %v, want [feat fix]", sent[2]["result"])
+	}
+	if whole := sent[5]["result"].(map[string]any); whole["max_subject_length"] != float64(52) {
+		t.Errorf("max_subject_length after reload = %v, want 52", whole["max_subject_length"])
+	}
+
+	codes := map[int]float64{1: codeFailed, 3: codeInvalidParams, 6: codeMethodNotFound, 7: codeParseError}
+	for i, code := range codes {
+		failure, ok := sent[i]["error"].(map[string]any)
+		if !ok || failure["code"] != code {
+			t.Errorf("response %d = %v, want error code %v", i, sent[i], code)
+		}
+	}
+}
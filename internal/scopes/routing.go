@@ -0,0 +1,138 @@
+package scopes
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+// RoutingVersion is the version of the routing file format
+const RoutingVersion = 1
+
+// Sources of a route's reviewers
+const (
+	RouteFromConfig     = "config"
+	RouteFromCodeowners = "codeowners"
+)
+
+// Route is where the pull requests touching a scope go for review
+type Route struct {
+	Reviewers []string `json:"reviewers" yaml:"reviewers"`
+	// Paths are the directories mapped to the scope, if any
+	Paths []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	// Source is RouteFromConfig for scope_reviewers, else RouteFromCodeowners
+	Source string `json:"source" yaml:"source"`
+}
+
+// Routing maps scopes to reviewers, for bots that assign the reviewers of a
+// pull request from the scopes of its commits
+type Routing struct {
+	Version int `json:"version" yaml:"version"`
+	// Default are the owners of the CODEOWNERS catch-all rule, for scopes
+	// without a route
+	Default []string         `json:"default,omitempty" yaml:"default,omitempty"`
+	Scopes  map[string]Route `json:"scopes" yaml:"scopes"`
+	// Unrouted are the known scopes no reviewers were found for
+	Unrouted []string `json:"unrouted,omitempty" yaml:"unrouted,omitempty"`
+}
+
+// Routes builds the routing of the repository containing dir ("" for the
+// current directory). A scope is routed to its scope_reviewers entry, else
+// to the CODEOWNERS owners of the paths the scope sources (and CODEOWNERS
+// itself) map to it. The scopes are cfg's, or every derived scope when any
+// scope is allowed.
+func Routes(cfg *config.Config, dir string) (*Routing, error) {
+	root := repoRoot(dir)
+	sources := cfg.ScopeSources
+	if !slices.Contains(sources.From, "codeowners") {
+		sources.From = append(slices.Clone(sources.From), "codeowners")
+	}
+	mapping, err := Derive(root, sources)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := codeownersRules(root)
+	if err != nil {
+		return nil, err
+	}
+
+	known := slices.Clone(cfg.Scopes)
+	if len(known) == 0 {
+		known = mapping.Scopes()
+	}
+	for scope := range cfg.ScopeReviewers {
+		if !slices.Contains(known, scope) {
+			known = append(known, scope)
+		}
+	}
+	sort.Strings(known)
+
+	routing := &Routing{Version: RoutingVersion, Default: ownersOf(rules, ""), Scopes: make(map[string]Route)}
+	for _, scope := range known {
+		route := Route{Paths: mapping.paths(scope), Source: RouteFromConfig, Reviewers: cfg.ScopeReviewers[scope]}
+		if len(route.Reviewers) == 0 {
+			route.Source = RouteFromCodeowners
+			for _, dir := range route.Paths {
+				for _, owner := range ownersOf(rules, dir) {
+					if !slices.Contains(route.Reviewers, owner) {
+						route.Reviewers = append(route.Reviewers, owner)
+					}
+				}
+			}
+		}
+		if len(route.Reviewers) == 0 {
+			routing.Unrouted = append(routing.Unrouted, scope)
+			continue
+		}
+		routing.Scopes[scope] = route
+	}
+	return routing, nil
+}
+
+// paths returns the prefixes mapped to scope, sorted
+func (m *Mapping) paths(scope string) []string {
+	if m == nil {
+		return nil
+	}
+	var prefixes []string
+	for _, e := range m.entries {
+		if e.scope == scope {
+			prefixes = append(prefixes, e.prefix)
+		}
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+// ownersOf returns the owners of a directory as CODEOWNERS assigns them: the
+// last matching rule wins. The empty directory only matches catch-all
+// rules.
+func ownersOf(rules []codeownersRule, dir string) []string {
+	var owners []string
+	for _, rule := range rules {
+		if codeownersMatch(rule.pattern, dir) {
+			owners = rule.owners
+		}
+	}
+	return owners
+}
+
+// codeownersMatch reports whether a CODEOWNERS pattern covers the directory
+func codeownersMatch(pattern, dir string) bool {
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" || pattern == "*" || pattern == "**" {
+		return true
+	}
+	if dir == "" {
+		return false
+	}
+	pattern = strings.TrimSuffix(strings.TrimSuffix(pattern, "/**"), "/*")
+	if dir == pattern || strings.HasPrefix(dir, pattern+"/") {
+		return true
+	}
+	matched, err := path.Match(pattern, dir)
+	return err == nil && matched
+}
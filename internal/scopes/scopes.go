@@ -0,0 +1,344 @@
+// Package scopes derives commit scopes from a repository's project
+// structure (CODEOWNERS, pnpm workspaces, go.work or a mapping file), so
+// allowed scopes follow the project layout without being listed by hand.
+package scopes
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// codeownersPaths are where GitHub and GitLab look for CODEOWNERS
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// entry maps a path prefix to a scope
+type entry struct {
+	prefix string
+	scope  string
+}
+
+// Mapping maps repository paths to the scopes derived for them. A nil
+// Mapping maps nothing.
+type Mapping struct {
+	// entries are sorted longest prefix first, so the most specific wins
+	entries []entry
+}
+
+// Scopes returns the derived scopes, sorted
+func (m *Mapping) Scopes() []string {
+	if m == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var scopes []string
+	for _, e := range m.entries {
+		if !seen[e.scope] {
+			seen[e.scope] = true
+			scopes = append(scopes, e.scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// Scope returns the scope of a path relative to the repository root, or ""
+// when no derived scope covers it
+func (m *Mapping) Scope(file string) string {
+	if m == nil {
+		return ""
+	}
+	file = strings.TrimPrefix(filepath.ToSlash(file), "/")
+	for _, e := range m.entries {
+		if file == e.prefix || strings.HasPrefix(file, e.prefix+"/") {
+			return e.scope
+		}
+	}
+	return ""
+}
+
+// Apply derives scopes for the repository containing dir ("" for the
+// current directory) from the sources in cfg and adds them to cfg.Scopes.
+// Without sources it returns a nil Mapping and leaves cfg unchanged.
+func Apply(cfg *config.Config, dir string) (*Mapping, error) {
+	if len(cfg.ScopeSources.From) == 0 {
+		return nil, nil
+	}
+	mapping, err := Derive(repoRoot(dir), cfg.ScopeSources)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range mapping.Scopes() {
+		if !slices.Contains(cfg.Scopes, scope) {
+			cfg.Scopes = append(cfg.Scopes, scope)
+		}
+	}
+	return mapping, nil
+}
+
+// Derive reads the given sources under the repository root. Sources whose
+// file does not exist contribute nothing; when several sources map the same
+// prefix, the first listed wins.
+func Derive(root string, sources config.ScopeSourcesConfig) (*Mapping, error) {
+	mapping := &Mapping{}
+	seen := make(map[string]bool)
+	for _, source := range sources.From {
+		var entries []entry
+		var err error
+		switch source {
+		case "codeowners":
+			entries, err = fromCodeowners(root)
+		case "pnpm-workspace":
+			entries, err = fromPnpmWorkspace(root)
+		case "go-work":
+			entries, err = fromGoWork(root)
+		case "mapping":
+			file := sources.MappingFile
+			if file == "" {
+				file = config.DefaultScopeMappingFile
+			}
+			entries, err = fromMappingFile(filepath.Join(root, file))
+		default:
+			err = fmt.Errorf("unknown scope source %q", source)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("scope source %s: %w", source, err)
+		}
+		for _, e := range entries {
+			if e.prefix != "" && e.scope != "" && !seen[e.prefix] {
+				seen[e.prefix] = true
+				mapping.entries = append(mapping.entries, e)
+			}
+		}
+	}
+	sort.SliceStable(mapping.entries, func(i, j int) bool {
+		return len(mapping.entries[i].prefix) > len(mapping.entries[j].prefix)
+	})
+	return mapping, nil
+}
+
+// fromCodeowners maps each directory owned in CODEOWNERS to a scope named
+// after it. File patterns and wildcards inside paths say nothing about
+// project structure and are skipped.
+func fromCodeowners(root string) ([]entry, error) {
+	rules, err := codeownersRules(root)
+	if err != nil {
+		return nil, err
+	}
+	var entries []entry
+	for _, rule := range rules {
+		dir := strings.TrimSuffix(strings.TrimSuffix(rule.pattern, "**"), "*")
+		if !strings.HasSuffix(dir, "/") && dir != rule.pattern {
+			continue
+		}
+		isDir := strings.HasSuffix(dir, "/")
+		dir = strings.Trim(dir, "/")
+		if dir == "" || strings.ContainsAny(dir, "*?[") {
+			continue
+		}
+		// Without a trailing slash a pattern may name a file
+		if !isDir && strings.Contains(path.Base(dir), ".") {
+			continue
+		}
+		entries = append(entries, entry{prefix: dir, scope: scopeName(dir)})
+	}
+	return entries, nil
+}
+
+// codeownersRule is a CODEOWNERS line: a path pattern and its owners
+type codeownersRule struct {
+	pattern string
+	owners  []string
+}
+
+// codeownersRules reads the rules of the first CODEOWNERS file found, in
+// file order
+func codeownersRules(root string) ([]codeownersRule, error) {
+	for _, name := range codeownersPaths {
+		file, err := os.Open(filepath.Join(root, name)) // #nosec G304 - fixed CODEOWNERS locations in the repository
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		var rules []codeownersRule
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			// Comments and GitLab section headers
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+				continue
+			}
+			fields := strings.Fields(line)
+			var owners []string
+			for _, owner := range fields[1:] {
+				if strings.HasPrefix(owner, "#") {
+					break
+				}
+				owners = append(owners, owner)
+			}
+			rules = append(rules, codeownersRule{pattern: fields[0], owners: owners})
+		}
+		return rules, scanner.Err()
+	}
+	return nil, nil
+}
+
+// fromPnpmWorkspace maps each package directory matched by the packages
+// globs of pnpm-workspace.yaml to a scope named after it
+func fromPnpmWorkspace(root string) ([]entry, error) {
+	data, err := os.ReadFile(filepath.Join(root, "pnpm-workspace.yaml")) // #nosec G304 - fixed file in the repository
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var workspace struct {
+		Packages []string `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(data, &workspace); err != nil {
+		return nil, fmt.Errorf("parsing pnpm-workspace.yaml: %w", err)
+	}
+
+	excluded := make(map[string]bool)
+	var dirs []string
+	for _, pattern := range workspace.Packages {
+		exclude := strings.HasPrefix(pattern, "!")
+		pattern = strings.Trim(strings.TrimPrefix(pattern, "!"), "/")
+		// Package directories are matched one level at a time
+		pattern = strings.ReplaceAll(pattern, "**", "*")
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid package pattern %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || !info.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(root, match)
+			if err != nil {
+				continue
+			}
+			if exclude {
+				excluded[filepath.ToSlash(rel)] = true
+			} else {
+				dirs = append(dirs, filepath.ToSlash(rel))
+			}
+		}
+	}
+
+	var entries []entry
+	for _, dir := range dirs {
+		if !excluded[dir] && dir != "." {
+			entries = append(entries, entry{prefix: dir, scope: scopeName(dir)})
+		}
+	}
+	return entries, nil
+}
+
+// goWorkUseRegex matches a single-line use directive of go.work
+var goWorkUseRegex = regexp.MustCompile(`^use\s+(\S+)$`)
+
+// fromGoWork maps each module directory used by go.work, except the root
+// module, to a scope named after it
+func fromGoWork(root string) ([]entry, error) {
+	file, err := os.Open(filepath.Join(root, "go.work")) // #nosec G304 - fixed file in the repository
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []entry
+	add := func(dir string) {
+		dir = strings.Trim(path.Clean(strings.Trim(dir, `"`)), "/")
+		if dir != "." && !strings.HasPrefix(dir, "..") {
+			entries = append(entries, entry{prefix: dir, scope: scopeName(dir)})
+		}
+	}
+
+	inUse := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		line = strings.TrimSpace(line)
+		switch {
+		case inUse && line == ")":
+			inUse = false
+		case inUse && line != "":
+			add(line)
+		case strings.HasPrefix(line, "use") && strings.TrimSpace(strings.TrimPrefix(line, "use")) == "(":
+			inUse = true
+		default:
+			if m := goWorkUseRegex.FindStringSubmatch(line); m != nil {
+				add(m[1])
+			}
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// fromMappingFile reads a YAML map of path prefixes to scopes, such as
+// "services/billing: payments"
+func fromMappingFile(file string) ([]entry, error) {
+	data, err := os.ReadFile(file) // #nosec G304 - the configured mapping file
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var prefixes map[string]string
+	if err := yaml.Unmarshal(data, &prefixes); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+
+	var entries []entry
+	for prefix, scope := range prefixes {
+		entries = append(entries, entry{prefix: strings.Trim(filepath.ToSlash(prefix), "/"), scope: strings.TrimSpace(scope)})
+	}
+	// Map order is random; sorting keeps duplicate handling stable
+	sort.Slice(entries, func(i, j int) bool { return entries[i].prefix < entries[j].prefix })
+	return entries, nil
+}
+
+// scopeInvalidChars matches runs of characters that do not belong in a scope
+var scopeInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// scopeName turns a directory into a kebab-case scope named after its last
+// element, such as "apps/Web_App" into "web-app"
+func scopeName(dir string) string {
+	name := strings.ToLower(path.Base(dir))
+	return strings.Trim(scopeInvalidChars.ReplaceAllString(name, "-"), "-")
+}
+
+// repoRoot returns the top level of the repository containing dir, or dir
+// itself outside a repository
+func repoRoot(dir string) string {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel") // #nosec G204 - fixed git command
+	cmd.Dir = dir
+	if out, err := cmd.Output(); err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
@@ -0,0 +1,143 @@
+package scopes
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+// writeFiles creates files under root; a "/" content creates a directory
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if content == "/" {
+			if err := os.MkdirAll(path, 0o750); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDerive(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".github/CODEOWNERS":   "# Owners\n* @org/all\n/apps/web/ @org/web\napps/api/** @org/api\n*.md @org/docs\n/services/Billing_Core @org/billing\n/Makefile.inc @org/build\n[Docs]\n/docs/ @org/docs\n",
+		"pnpm-workspace.yaml":  "packages:\n  - 'packages/*'\n  - '!packages/legacy'\n",
+		"packages/ui/":         "/",
+		"packages/legacy/":     "/",
+		"packages/README.md":   "not a package",
+		"go.work":              "go 1.22\n\nuse (\n\t. // root module\n\t./tools/gen\n)\nuse ./services/billing\n",
+		".fast-cc/scopes.yaml": "apps/web/admin: admin\nlibs/: shared\n",
+	})
+
+	mapping, err := Derive(root, config.ScopeSourcesConfig{From: []string{"codeowners", "pnpm-workspace", "go-work", "mapping"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"admin", "api", "billing", "billing-core", "docs", "gen", "shared", "ui", "web"}
+	if got := mapping.Scopes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Scopes() = %q, want %q", got, want)
+	}
+
+	for path, want := range map[string]string{
+		"apps/web/src/index.ts":       "web",
+		"apps/web/admin/page.tsx":     "admin",
+		"apps/api/main.go":            "api",
+		"packages/ui/button.tsx":      "ui",
+		"packages/legacy/old.js":      "",
+		"services/billing/invoice.go": "billing",
+		"services/Billing_Core/a.go":  "billing-core",
+		"tools/gen/main.go":           "gen",
+		"libs/date.ts":                "shared",
+		"README.md":                   "",
+		"apps/webhooks/hook.ts":       "",
+	} {
+		if got := mapping.Scope(path); got != want {
+			t.Errorf("Scope(%q) = %q, want %q", path, got, want)
+		}
+	}
+
+	var none *Mapping
+	if none.Scope("apps/web/a.ts") != "" || none.Scopes() != nil {
+		t.Error("a nil Mapping should map nothing")
+	}
+}
+
+func TestDeriveMissingSources(t *testing.T) {
+	mapping, err := Derive(t.TempDir(), config.ScopeSourcesConfig{From: []string{"codeowners", "pnpm-workspace", "go-work", "mapping"}})
+	if err != nil || len(mapping.Scopes()) != 0 {
+		t.Errorf("Derive() without source files = %v, %v", mapping.Scopes(), err)
+	}
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"pnpm-workspace.yaml": "packages: [unclosed\n"})
+	if _, err := Derive(root, config.ScopeSourcesConfig{From: []string{"pnpm-workspace"}}); err == nil {
+		t.Error("Derive() should fail on an invalid pnpm-workspace.yaml")
+	}
+}
+
+func TestApply(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"scopes.yaml": "apps/web: web\nlibs: docs\n"})
+
+	cfg := config.Default()
+	cfg.Scopes = []string{"docs", "ci"}
+	cfg.ScopeSources = config.ScopeSourcesConfig{From: []string{"mapping"}, MappingFile: "scopes.yaml"}
+	mapping, err := Apply(cfg, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"docs", "ci", "web"}; !reflect.DeepEqual(cfg.Scopes, want) {
+		t.Errorf("Scopes = %q, want %q", cfg.Scopes, want)
+	}
+	if mapping.Scope("apps/web/a.ts") != "web" {
+		t.Errorf("Scope() = %q", mapping.Scope("apps/web/a.ts"))
+	}
+
+	cfg = config.Default()
+	if mapping, err := Apply(cfg, root); mapping != nil || err != nil || len(cfg.Scopes) != 0 {
+		t.Errorf("Apply() without sources = %v, %v, scopes %q", mapping, err, cfg.Scopes)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".github/CODEOWNERS":   "* @org/all\n/apps/web/ @org/web @alice # frontend\n/apps/web/admin/ @org/admin\n/services/billing/ @org/billing\n",
+		".fast-cc/scopes.yaml": "services/billing: payments\n",
+	})
+	cfg := &config.Config{
+		Scopes:         []string{"web", "payments", "admin", "cli"},
+		ScopeReviewers: map[string][]string{"admin": {"@org/security"}},
+		ScopeSources:   config.ScopeSourcesConfig{From: []string{"mapping"}},
+	}
+
+	routing, err := Routes(cfg, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Routing{
+		Version: RoutingVersion,
+		Default: []string{"@org/all"},
+		Scopes: map[string]Route{
+			"admin":    {Reviewers: []string{"@org/security"}, Paths: []string{"apps/web/admin"}, Source: RouteFromConfig},
+			"payments": {Reviewers: []string{"@org/billing"}, Paths: []string{"services/billing"}, Source: RouteFromCodeowners},
+			"web":      {Reviewers: []string{"@org/web", "@alice"}, Paths: []string{"apps/web"}, Source: RouteFromCodeowners},
+		},
+		Unrouted: []string{"cli"},
+	}
+	if !reflect.DeepEqual(routing, want) {
+		t.Errorf("Routes() = %+v, want %+v", routing, want)
+	}
+}
@@ -0,0 +1,57 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// CheckInterval is how often Check asks GitHub for a newer release.
+	CheckInterval = 24 * time.Hour
+	// StateFile records the last check, in the cache directory.
+	StateFile = "update-check.json"
+)
+
+// checkState is the answer of the last check.
+type checkState struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Repo      string    `json:"repo"`
+	Channel   string    `json:"channel"`
+	Latest    string    `json:"latest"`
+}
+
+// Check returns the latest version of the channel when it is newer than
+// current, or "" when fcgh is up to date. GitHub is asked at most once per
+// CheckInterval; the answer is remembered in cacheDir in between.
+func (c *Client) Check(ctx context.Context, channel, current, cacheDir string) (string, error) {
+	if !IsRelease(current) {
+		return "", nil
+	}
+	path := filepath.Join(cacheDir, StateFile)
+	var state checkState
+	if data, err := os.ReadFile(path); err == nil { // #nosec G304 - the cache file
+		_ = json.Unmarshal(data, &state)
+	}
+
+	if state.Repo != c.Repo || state.Channel != channel || time.Since(state.CheckedAt) >= CheckInterval {
+		release, err := c.Latest(ctx, channel)
+		if err != nil {
+			return "", err
+		}
+		state = checkState{CheckedAt: time.Now(), Repo: c.Repo, Channel: channel, Latest: release.Version()}
+		if data, err := json.Marshal(state); err == nil {
+			// A failed write only means asking again next time
+			if os.MkdirAll(cacheDir, 0o750) == nil {
+				_ = os.WriteFile(path, data, 0o600)
+			}
+		}
+	}
+
+	if Newer(current, state.Latest) {
+		return state.Latest, nil
+	}
+	return "", nil
+}
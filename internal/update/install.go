@@ -0,0 +1,299 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// ChecksumsFile lists the SHA-256 of every release archive.
+	ChecksumsFile = "checksums.txt"
+	// SignatureFile is the ed25519 signature of ChecksumsFile.
+	SignatureFile = ChecksumsFile + ".sig"
+
+	maxChecksumsSize = 1 << 20
+	maxArchiveSize   = 200 << 20
+)
+
+var (
+	// ErrChecksumMismatch indicates a downloaded archive was altered.
+	ErrChecksumMismatch = errors.New("archive checksum does not match checksums.txt")
+	// ErrBadSignature indicates checksums.txt was not signed by the pinned key.
+	ErrBadSignature = errors.New("checksums.txt signature does not match the pinned public key")
+)
+
+// ParsePublicKey decodes a base64 ed25519 public key.
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("decoding update public key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("update public key must be %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// archiveNames returns the names the release archive of a platform may have.
+func archiveNames(version, goos, goarch string) []string {
+	base := fmt.Sprintf("fcgh_%s_%s_%s", version, goos, goarch)
+	return []string{base + ".tar.gz", base + ".zip"}
+}
+
+// Tools are the binaries a release archive ships, fcgh first.
+var Tools = []string{"fcgh", "ccg", "ccdo"}
+
+// Fetch downloads the release archive for a platform and returns the Tools
+// binaries in it by name, fcgh always among them. The archive must match its
+// checksum in checksums.txt, which, with a key, must carry a valid
+// signature. Without a key the checksum only guards against corrupt
+// downloads, as it comes from the same release.
+func (c *Client) Fetch(ctx context.Context, release *Release, goos, goarch string, key ed25519.PublicKey) (map[string][]byte, error) {
+	var archive Asset
+	found := false
+	for _, name := range archiveNames(release.Version(), goos, goarch) {
+		if archive, found = release.asset(name); found {
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("release %s has no archive for %s/%s", release.Tag, goos, goarch)
+	}
+	checksumsAsset, ok := release.asset(ChecksumsFile)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s to verify the download", release.Tag, ChecksumsFile)
+	}
+
+	checksums, err := c.download(ctx, checksumsAsset.URL, maxChecksumsSize)
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		signatureAsset, ok := release.asset(SignatureFile)
+		if !ok {
+			return nil, fmt.Errorf("release %s is not signed: %s is missing", release.Tag, SignatureFile)
+		}
+		signature, err := c.download(ctx, signatureAsset.URL, maxChecksumsSize)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifySignature(checksums, signature, key); err != nil {
+			return nil, err
+		}
+	}
+	want, err := checksumOf(checksums, archive.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.download(ctx, archive.URL, maxArchiveSize)
+	if err != nil {
+		return nil, err
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != want {
+		return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, archive.Name)
+	}
+
+	names := make(map[string]string, len(Tools))
+	for _, tool := range Tools {
+		names[BinaryName(tool, goos)] = tool
+	}
+	var binaries map[string][]byte
+	if strings.HasSuffix(archive.Name, ".zip") {
+		binaries, err = extractZip(data, names)
+	} else {
+		binaries, err = extractTarGz(data, names)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := binaries[Tools[0]]; !ok {
+		return nil, fmt.Errorf("archive has no %s", BinaryName(Tools[0], goos))
+	}
+	return binaries, nil
+}
+
+// BinaryName returns the file name of tool on goos.
+func BinaryName(tool, goos string) string {
+	if goos == "windows" {
+		return tool + ".exe"
+	}
+	return tool
+}
+
+// verifySignature checks a signature of data, given raw or base64 encoded.
+func verifySignature(data, signature []byte, key ed25519.PublicKey) error {
+	if len(signature) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return ErrBadSignature
+		}
+		signature = decoded
+	}
+	if !ed25519.Verify(key, data, signature) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// checksumOf returns the SHA-256 listed for name in a checksums.txt.
+func checksumOf(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s has no checksum for %s", ChecksumsFile, name)
+}
+
+// extractTarGz returns the files of a .tar.gz archive whose base name is in
+// names, keyed by the tool names maps it to.
+func extractTarGz(data []byte, names map[string]string) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	defer gz.Close()
+	binaries := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return binaries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+		tool, ok := names[path.Base(header.Name)]
+		if header.Typeflag != tar.TypeReg || !ok {
+			continue
+		}
+		if binaries[tool], err = readLimited(tr); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// extractZip returns the files of a .zip archive whose base name is in
+// names, keyed by the tool names maps it to.
+func extractZip(data []byte, names map[string]string) (map[string][]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	binaries := make(map[string][]byte)
+	for _, file := range zr.File {
+		tool, ok := names[path.Base(file.Name)]
+		if !ok || file.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+		binaries[tool], err = readLimited(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return binaries, nil
+}
+
+// readLimited reads an extracted binary, bounded by maxArchiveSize.
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxArchiveSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	if len(data) > maxArchiveSize {
+		return nil, fmt.Errorf("binary larger than %d bytes", maxArchiveSize)
+	}
+	return data, nil
+}
+
+// download fetches a release asset, bounded by limit bytes.
+func (c *Client) download(ctx context.Context, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request for %s: %w", url, err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: unexpected HTTP status %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", url, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("downloading %s: larger than %d bytes", url, limit)
+	}
+	return data, nil
+}
+
+// Replace swaps the binary at path for data, keeping its permissions. The
+// new binary is written beside it and renamed over it, so the path always
+// holds a complete binary; on Windows the running binary is moved aside
+// first, as it cannot be overwritten.
+func Replace(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.new")
+	if err != nil {
+		return fmt.Errorf("writing update: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing update: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing update: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0o111); err != nil { // #nosec G302 - the binary must stay executable
+		return fmt.Errorf("writing update: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return fmt.Errorf("moving %s aside: %w", path, err)
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			_ = os.Rename(old, path)
+			return fmt.Errorf("installing update: %w", err)
+		}
+		return nil
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("installing update: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+// Package update finds newer fcgh releases on GitHub and installs them.
+//
+// Releases are the goreleaser archives "fcgh_<version>_<os>_<arch>.tar.gz"
+// listed in checksums.txt. When a public key is pinned, checksums.txt must
+// come with checksums.txt.sig, an ed25519 signature of it, so a release can
+// only be installed as published.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Stable releases exclude prereleases; Prerelease includes them.
+	Stable     = "stable"
+	Prerelease = "prerelease"
+
+	// DefaultRepo is the GitHub repository fcgh is released from.
+	DefaultRepo = "greenstevester/fast-cc-git-hooks"
+	// EnvGitHubToken raises the GitHub API rate limit when set.
+	EnvGitHubToken = "GITHUB_TOKEN"
+
+	defaultAPI     = "https://api.github.com"
+	requestTimeout = 30 * time.Second
+	maxAPIResponse = 5 << 20
+)
+
+// ErrNoRelease is returned when the channel has no published release.
+var ErrNoRelease = errors.New("no release found")
+
+// Release is a published fcgh release.
+type Release struct {
+	Tag        string  `json:"tag_name"`
+	Prerelease bool    `json:"prerelease"`
+	Draft      bool    `json:"draft"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release version without the "v" prefix.
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+// asset returns the release's asset with the name.
+func (r *Release) asset(name string) (Asset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Client reads releases from the GitHub API.
+type Client struct {
+	// API is the GitHub API base URL, https://api.github.com by default.
+	API string
+	// Repo is the "owner/name" repository, DefaultRepo by default.
+	Repo string
+	// Token authenticates API requests when set.
+	Token      string
+	httpClient *http.Client
+}
+
+// New returns a client for repo (DefaultRepo when empty), using GITHUB_TOKEN
+// when set.
+func New(repo string) *Client {
+	if repo == "" {
+		repo = DefaultRepo
+	}
+	return &Client{
+		API:        defaultAPI,
+		Repo:       repo,
+		Token:      os.Getenv(EnvGitHubToken),
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Latest returns the newest release of the channel: the release GitHub marks
+// latest for Stable, the highest version including prereleases otherwise.
+func (c *Client) Latest(ctx context.Context, channel string) (*Release, error) {
+	if channel != Prerelease {
+		var release Release
+		if err := c.getJSON(ctx, "/releases/latest", &release); err != nil {
+			return nil, err
+		}
+		return &release, nil
+	}
+
+	var releases []Release
+	if err := c.getJSON(ctx, "/releases?per_page=30", &releases); err != nil {
+		return nil, err
+	}
+	var latest *Release
+	for i := range releases {
+		release := &releases[i]
+		if release.Draft {
+			continue
+		}
+		if latest == nil || Compare(release.Version(), latest.Version()) > 0 {
+			latest = release
+		}
+	}
+	if latest == nil {
+		return nil, ErrNoRelease
+	}
+	return latest, nil
+}
+
+// getJSON decodes the response of a repository API endpoint.
+func (c *Client) getJSON(ctx context.Context, endpoint string, v any) error {
+	url := strings.TrimRight(c.API, "/") + "/repos/" + c.Repo + endpoint
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching releases of %s: %w", c.Repo, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w in %s", ErrNoRelease, c.Repo)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching releases of %s: unexpected HTTP status %d", c.Repo, resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxAPIResponse)).Decode(v); err != nil {
+		return fmt.Errorf("decoding releases of %s: %w", c.Repo, err)
+	}
+	return nil
+}
+
+// IsRelease reports whether version is a release version, unlike "dev" and
+// other development builds.
+func IsRelease(version string) bool {
+	_, ok := parseVersion(version)
+	return ok
+}
+
+// Newer reports whether latest is a newer version than current. Development
+// builds ("dev" or any version that does not parse) are never outdated.
+func Newer(current, latest string) bool {
+	if !IsRelease(current) {
+		return false
+	}
+	return Compare(latest, current) > 0
+}
+
+// Compare orders two versions such as "1.2.0" and "v1.3.0-rc.1" like
+// semantic versioning, returning -1, 0 or 1. A prerelease sorts before its
+// release; versions that do not parse sort first.
+func Compare(a, b string) int {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := range va.core {
+		if va.core[i] != vb.core[i] {
+			if va.core[i] < vb.core[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return comparePrerelease(va.prerelease, vb.prerelease)
+}
+
+// version is a parsed semantic version.
+type version struct {
+	core       [3]int
+	prerelease []string
+}
+
+// parseVersion parses "v1.2.3", "1.2.3-rc.1" or "1.2.3+meta".
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, hasPre := strings.Cut(s, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return version{}, false
+	}
+	var v version
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		v.core[i] = n
+	}
+	if hasPre {
+		if pre == "" {
+			return version{}, false
+		}
+		v.prerelease = strings.Split(pre, ".")
+	}
+	return v, true
+}
+
+// comparePrerelease orders prerelease identifiers: a release (none) sorts
+// after any prerelease, numeric identifiers compare numerically and before
+// alphanumeric ones.
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+func compareIdentifier(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,223 @@
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.0", "v1.2.0", 0},
+		{"1.2.0", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.2.0-rc.1", "1.2.0", -1},
+		{"1.2.0-rc.2", "1.2.0-rc.10", -1},
+		{"1.2.0-beta", "1.2.0-alpha", 1},
+		{"1.2.0-rc.1", "1.2.0-rc.1.1", -1},
+		{"1.2.0+build.5", "1.2.0", 0},
+		{"dev", "0.0.1", -1},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if Newer("dev", "9.9.9") {
+		t.Error("Newer() reported an update for a development build")
+	}
+	if !Newer("v1.2.0", "1.3.0") || Newer("1.3.0", "1.3.0") {
+		t.Error("Newer() compared releases wrongly")
+	}
+}
+
+// fakeGitHub serves releases and their assets.
+type fakeGitHub struct {
+	*httptest.Server
+	releases []Release
+	files    map[string][]byte
+	requests int
+}
+
+func newFakeGitHub(t *testing.T, releases []Release, files map[string][]byte) *fakeGitHub {
+	t.Helper()
+	f := &fakeGitHub{releases: releases, files: files}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/fcgh/releases/latest":
+			f.requests++
+			for _, release := range f.releases {
+				if !release.Prerelease && !release.Draft {
+					_ = json.NewEncoder(w).Encode(release)
+					return
+				}
+			}
+			http.NotFound(w, r)
+		case "/repos/acme/fcgh/releases":
+			f.requests++
+			_ = json.NewEncoder(w).Encode(f.releases)
+		default:
+			data, ok := f.files[filepath.Base(r.URL.Path)]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeGitHub) client() *Client {
+	c := New("acme/fcgh")
+	c.API = f.URL
+	c.Token = ""
+	return c
+}
+
+func TestClient_Latest(t *testing.T) {
+	f := newFakeGitHub(t, []Release{
+		{Tag: "v1.4.0-rc.1", Prerelease: true},
+		{Tag: "v1.5.0", Draft: true},
+		{Tag: "v1.3.0"},
+		{Tag: "v1.2.0"},
+	}, nil)
+	c := f.client()
+
+	stable, err := c.Latest(context.Background(), Stable)
+	if err != nil || stable.Version() != "1.3.0" {
+		t.Fatalf("Latest(stable) = %v, %v, want 1.3.0", stable, err)
+	}
+	pre, err := c.Latest(context.Background(), Prerelease)
+	if err != nil || pre.Version() != "1.4.0-rc.1" {
+		t.Fatalf("Latest(prerelease) = %v, %v, want 1.4.0-rc.1", pre, err)
+	}
+}
+
+func TestClient_Check(t *testing.T) {
+	f := newFakeGitHub(t, []Release{{Tag: "v1.3.0"}}, nil)
+	c := f.client()
+	cacheDir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		latest, err := c.Check(context.Background(), Stable, "1.2.0", cacheDir)
+		if err != nil || latest != "1.3.0" {
+			t.Fatalf("Check() = %q, %v, want 1.3.0", latest, err)
+		}
+	}
+	if f.requests != 1 {
+		t.Errorf("Check() asked GitHub %d times, want once within the interval", f.requests)
+	}
+	if latest, err := c.Check(context.Background(), Stable, "1.3.0", cacheDir); err != nil || latest != "" {
+		t.Errorf("Check() on the latest release = %q, %v, want up to date", latest, err)
+	}
+	if latest, _ := c.Check(context.Background(), Prerelease, "1.2.0", cacheDir); latest != "1.3.0" || f.requests != 2 {
+		t.Errorf("Check() after a channel change = %q with %d requests, want a new request", latest, f.requests)
+	}
+}
+
+func TestClient_Fetch(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho fcgh 1.3.0\n")
+	ccg := []byte("#!/bin/sh\necho ccg 1.3.0\n")
+	archive := tarGz(t, map[string][]byte{"README.md": []byte("readme"), "fcgh": binary, "ccg": ccg})
+	name := "fcgh_1.3.0_linux_amd64.tar.gz"
+	sum := sha256.Sum256(archive)
+	checksums := []byte(fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name))
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{
+		name:          archive,
+		ChecksumsFile: checksums,
+		SignatureFile: ed25519.Sign(private, checksums),
+	}
+	f := newFakeGitHub(t, nil, files)
+	release := &Release{Tag: "v1.3.0"}
+	for file := range files {
+		release.Assets = append(release.Assets, Asset{Name: file, URL: f.URL + "/download/" + file})
+	}
+	c := f.client()
+	ctx := context.Background()
+
+	got, err := c.Fetch(ctx, release, "linux", "amd64", public)
+	if err != nil || !bytes.Equal(got["fcgh"], binary) || !bytes.Equal(got["ccg"], ccg) || len(got) != 2 {
+		t.Fatalf("Fetch() = %q, %v, want the fcgh and ccg binaries", got, err)
+	}
+	if _, err := c.Fetch(ctx, release, "plan9", "amd64", nil); err == nil {
+		t.Error("Fetch() found an archive for a platform without one")
+	}
+
+	otherKey, _, _ := ed25519.GenerateKey(nil)
+	if _, err := c.Fetch(ctx, release, "linux", "amd64", otherKey); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("Fetch() with another key error = %v, want ErrBadSignature", err)
+	}
+
+	files[name] = append(archive, 0)
+	if _, err := c.Fetch(ctx, release, "linux", "amd64", nil); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Fetch() of an altered archive error = %v, want ErrChecksumMismatch", err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fcgh")
+	// #nosec G306 - a fake binary
+	if err := os.WriteFile(path, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Replace(path, []byte("new")); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Errorf("binary = %q, %v, want the new one", data, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("binary mode = %v, want executable", info.Mode())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the binary", len(entries))
+	}
+}
+
+// tarGz builds a .tar.gz archive of files.
+func tarGz(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
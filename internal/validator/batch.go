@@ -0,0 +1,102 @@
+package validator
+
+import (
+	"context"
+	"sync"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/ticket"
+)
+
+// BatchResult is the validation result of one message in a batch.
+type BatchResult struct {
+	// Index is the message's position in the batch.
+	Index int
+	*ValidationResult
+}
+
+// ValidateBatch validates many messages (such as a commit range) with one
+// shared context: compiled rules are reused, identical messages are
+// validated once, and each ticket is looked up at most once across the
+// batch. Results are returned in message order. Once ctx is cancelled the
+// remaining messages report the cancellation.
+func (v *Validator) ValidateBatch(ctx context.Context, messages []string) []BatchResult {
+	batch := *v
+	if v.tickets != nil {
+		batch.tickets = &cachedLookup{lookup: v.tickets, issues: map[string]lookupResult{}}
+	}
+	if v.verifier != nil {
+		batch.verifier = &cachedVerifier{verifier: v.verifier, results: map[string]error{}}
+	}
+
+	results := make([]BatchResult, len(messages))
+	seen := make(map[string]*ValidationResult, len(messages))
+	for i, message := range messages {
+		result, ok := seen[message]
+		if !ok {
+			result = batch.Validate(ctx, message)
+			// Cancelled results are not reused so later duplicates also report it
+			if ctx.Err() == nil {
+				seen[message] = result
+			}
+		} else {
+			result = result.clone()
+		}
+		results[i] = BatchResult{Index: i, ValidationResult: result}
+	}
+	return results
+}
+
+// clone copies a result so batch duplicates do not share slices.
+func (r *ValidationResult) clone() *ValidationResult {
+	return &ValidationResult{
+		Errors:   append([]error{}, r.Errors...),
+		Warnings: append([]string(nil), r.Warnings...),
+		Valid:    r.Valid,
+	}
+}
+
+type lookupResult struct {
+	issue *jira.Issue
+	err   error
+}
+
+// cachedLookup remembers JIRA lookups, including failures, for one batch.
+type cachedLookup struct {
+	lookup TicketLookup
+	mu     sync.Mutex
+	issues map[string]lookupResult
+}
+
+func (c *cachedLookup) GetIssue(ctx context.Context, key string) (*jira.Issue, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.issues[key]; ok {
+		return cached.issue, cached.err
+	}
+	issue, err := c.lookup.GetIssue(ctx, key)
+	if ctx.Err() == nil {
+		c.issues[key] = lookupResult{issue: issue, err: err}
+	}
+	return issue, err
+}
+
+// cachedVerifier remembers provider ticket checks for one batch.
+type cachedVerifier struct {
+	verifier ticket.Verifier
+	mu       sync.Mutex
+	results  map[string]error
+}
+
+func (c *cachedVerifier) Verify(ctx context.Context, ref string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err, ok := c.results[ref]; ok {
+		return err
+	}
+	err := c.verifier.Verify(ctx, ref)
+	if ctx.Err() == nil {
+		c.results[ref] = err
+	}
+	return err
+}
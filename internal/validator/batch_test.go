@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+)
+
+// countingLookup counts GetIssue calls per key
+type countingLookup struct {
+	fakeTicketLookup
+	calls map[string]int
+}
+
+func (c *countingLookup) GetIssue(ctx context.Context, key string) (*jira.Issue, error) {
+	c.calls[key]++
+	return c.fakeTicketLookup.GetIssue(ctx, key)
+}
+
+func TestValidator_ValidateBatch(t *testing.T) {
+	v, err := New(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := &countingLookup{
+		fakeTicketLookup: fakeTicketLookup{issues: map[string]*jira.Issue{
+			"CGC-1": {Key: "CGC-1", StatusCategory: "indeterminate", Project: "CGC"},
+		}},
+		calls: map[string]int{},
+	}
+	v.SetTicketLookup(lookup)
+
+	messages := []string{
+		"feat: CGC-1 add login",
+		"not conventional",
+		"fix: CGC-1 handle logout",
+		"feat: CGC-9 add search",
+		"not conventional",
+	}
+	results := v.ValidateBatch(context.Background(), messages)
+
+	if len(results) != len(messages) {
+		t.Fatalf("expected %d results, got %d", len(messages), len(results))
+	}
+	wantValid := []bool{true, false, true, false, false}
+	for i, result := range results {
+		if result.Index != i {
+			t.Errorf("result %d has index %d", i, result.Index)
+		}
+		if result.Valid != wantValid[i] {
+			t.Errorf("message %q valid = %v, want %v (%v)", messages[i], result.Valid, wantValid[i], result.Errors)
+		}
+	}
+	if lookup.calls["CGC-1"] != 1 || lookup.calls["CGC-9"] != 1 {
+		t.Errorf("expected each ticket to be looked up once, got %v", lookup.calls)
+	}
+
+	// Duplicate messages get independent results
+	results[1].Errors = nil
+	if len(results[4].Errors) == 0 {
+		t.Error("duplicate results share their errors")
+	}
+}
+
+func TestValidator_ValidateBatch_Cancelled(t *testing.T) {
+	v, err := New(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, result := range v.ValidateBatch(ctx, []string{"feat: a", "feat: a"}) {
+		if result.Valid {
+			t.Errorf("expected message %d to report the cancellation", result.Index)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// CheckProtocolVersion is the version of the JSON exchanged with external
+// checks.
+const CheckProtocolVersion = 1
+
+// PreParseHook checks a raw message before it is parsed.
+type PreParseHook func(ctx context.Context, message string) (*CheckResult, error)
+
+// PostValidateHook checks a parsed commit after the built-in rules; result
+// holds their findings.
+type PostValidateHook func(ctx context.Context, commit *conventionalcommit.Commit, result *ValidationResult) (*CheckResult, error)
+
+// CheckResult is what a check found. Violations fail validation; warnings
+// are reported without failing it.
+type CheckResult struct {
+	Violations []*ValidationError `json:"violations"`
+	Warnings   []string           `json:"warnings"`
+}
+
+// hook is a registered check; exactly one of pre and post is set.
+type hook struct {
+	name string
+	pre  PreParseHook
+	post PostValidateHook
+}
+
+// AddPreParseHook registers a check of raw messages, run before parsing so
+// it also sees messages that are not conventional commits.
+func (v *Validator) AddPreParseHook(name string, check PreParseHook) {
+	v.hooks = append(v.hooks, hook{name: name, pre: check})
+}
+
+// AddPostValidateHook registers a check of parsed commits, run after the
+// built-in rules.
+func (v *Validator) AddPostValidateHook(name string, check PostValidateHook) {
+	v.hooks = append(v.hooks, hook{name: name, post: check})
+}
+
+// runPreParseHooks runs the pre-parse checks in registration order.
+func (v *Validator) runPreParseHooks(ctx context.Context, message string, result *ValidationResult) {
+	for _, h := range v.hooks {
+		if h.pre != nil {
+			found, err := h.pre(ctx, message)
+			v.addCheckResult(result, h.name, found, err)
+		}
+	}
+}
+
+// runPostValidateHooks runs the post-validate checks in registration order.
+func (v *Validator) runPostValidateHooks(ctx context.Context, commit *conventionalcommit.Commit, result *ValidationResult) {
+	for _, h := range v.hooks {
+		if h.post != nil {
+			found, err := h.post(ctx, commit, result)
+			v.addCheckResult(result, h.name, found, err)
+		}
+	}
+}
+
+// addCheckResult records a check's findings under the "check" field. A check
+// that fails to run fails validation, so broken checks are noticed.
+func (v *Validator) addCheckResult(result *ValidationResult, name string, found *CheckResult, err error) {
+	if err != nil {
+		v.addRuleError(result, "check", name, fmt.Sprintf("check %s failed: %v", name, err), "", conventionalcommit.Span{})
+		return
+	}
+	if found == nil {
+		return
+	}
+	for _, violation := range found.Violations {
+		if violation == nil || violation.Message == "" {
+			continue
+		}
+		span := conventionalcommit.Span{Line: violation.Line, Column: violation.Column, EndColumn: violation.EndColumn}
+		v.addRuleError(result, "check", name, name+": "+violation.Message, violation.Value, span)
+	}
+	for _, warning := range found.Warnings {
+		result.Warnings = append(result.Warnings, name+": "+warning)
+	}
+}
+
+// checkInput is the JSON an external check reads on stdin.
+type checkInput struct {
+	Protocol int    `json:"protocol"`
+	Stage    string `json:"stage"`
+	Message  string `json:"message"`
+	Branch   string `json:"branch,omitempty"`
+	// Commit and Errors (the built-in findings) are set after validation.
+	Commit *checkCommit       `json:"commit,omitempty"`
+	Errors []*ValidationError `json:"errors,omitempty"`
+}
+
+// checkCommit is the JSON form of a parsed commit.
+type checkCommit struct {
+	Type                string              `json:"type"`
+	Scope               string              `json:"scope,omitempty"`
+	Scopes              []string            `json:"scopes,omitempty"`
+	Description         string              `json:"description"`
+	Body                string              `json:"body,omitempty"`
+	Footer              string              `json:"footer,omitempty"`
+	Breaking            bool                `json:"breaking"`
+	BreakingDescription string              `json:"breaking_description,omitempty"`
+	Trailers            []map[string]string `json:"trailers,omitempty"`
+	Tickets             []map[string]string `json:"tickets,omitempty"`
+}
+
+// newCheckCommit converts a parsed commit for external checks.
+func newCheckCommit(commit *conventionalcommit.Commit) *checkCommit {
+	c := &checkCommit{
+		Type:                commit.Type,
+		Scope:               commit.Scope,
+		Scopes:              commit.Scopes,
+		Description:         commit.Description,
+		Body:                commit.Body,
+		Footer:              commit.Footer,
+		Breaking:            commit.Breaking,
+		BreakingDescription: commit.BreakingDescription,
+	}
+	for _, trailer := range commit.Trailers {
+		c.Trailers = append(c.Trailers, map[string]string{"key": trailer.Key, "value": trailer.Value})
+	}
+	for _, ref := range commit.TicketRefs {
+		c.Tickets = append(c.Tickets, map[string]string{"type": ref.Type, "id": ref.ID})
+	}
+	return c
+}
+
+// addCommandChecks registers the external checks of the config.
+func (v *Validator) addCommandChecks(checks []config.CheckConfig) {
+	for _, check := range checks {
+		if check.Stage == config.CheckStagePreParse {
+			v.AddPreParseHook(check.Name, func(ctx context.Context, message string) (*CheckResult, error) {
+				return runCheckCommand(ctx, check, checkInput{Stage: check.Stage, Message: message, Branch: v.branch})
+			})
+			continue
+		}
+		v.AddPostValidateHook(check.Name, func(ctx context.Context, commit *conventionalcommit.Commit, result *ValidationResult) (*CheckResult, error) {
+			return runCheckCommand(ctx, check, checkInput{
+				Stage:   config.CheckStagePostValidate,
+				Message: commit.Raw,
+				Branch:  v.branch,
+				Commit:  newCheckCommit(commit),
+				Errors:  result.ValidationErrors(),
+			})
+		})
+	}
+}
+
+// runCheckCommand runs an external check with input on stdin. Empty output
+// means nothing was found; a non-zero exit status is an error.
+func runCheckCommand(ctx context.Context, check config.CheckConfig, input checkInput) (*CheckResult, error) {
+	timeout := time.Duration(check.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = config.DefaultCheckTimeout * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	input.Protocol = CheckProtocolVersion
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("encoding input: %w", err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, check.Command[0], check.Command[1:]...) // #nosec G204 - checks are configured commands
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil, nil
+	}
+	var found CheckResult
+	if err := json.Unmarshal(stdout.Bytes(), &found); err != nil {
+		return nil, fmt.Errorf("parsing output: %w", err)
+	}
+	return &found, nil
+}
@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// shellCheck returns a check running script with sh.
+func shellCheck(t *testing.T, name, stage, script string) config.CheckConfig {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil || runtime.GOOS == "windows" {
+		t.Skip("sh not available")
+	}
+	return config.CheckConfig{Name: name, Stage: stage, Command: []string{"sh", "-c", script}}
+}
+
+func TestValidator_CommandChecks(t *testing.T) {
+	cfg := config.Default()
+	cfg.Checks = []config.CheckConfig{
+		shellCheck(t, "wip", config.CheckStagePreParse,
+			`case "$(cat)" in *'"stage":"pre-parse"'*WIP*) echo '{"violations":[{"message":"no WIP commits","value":"WIP"}]}';; esac`),
+		shellCheck(t, "fix-tests", "",
+			`input=$(cat); case "$input" in *'"type":"fix"'*'"errors"'*) echo '{"violations":[{"message":"built-in errors seen"}]}';;
+			*'"type":"fix"'*) echo '{"violations":[{"message":"fixes need a test","line":1,"column":1,"end_column":4}],"warnings":["ask QA"]}';; esac`),
+	}
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	if result := v.Validate(context.Background(), "feat: add login"); !result.Valid {
+		t.Errorf("Validate() = %v, want valid", result.Errors)
+	}
+
+	result := v.Validate(context.Background(), "fix: handle empty names")
+	errs := result.ValidationErrors()
+	if result.Valid || len(errs) != 1 || errs[0].Field != "check" || errs[0].Message != "fix-tests: fixes need a test" || errs[0].EndColumn != 4 {
+		t.Errorf("Validate() errors = %+v", errs)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0] != "fix-tests: ask QA" {
+		t.Errorf("Validate() warnings = %q", result.Warnings)
+	}
+
+	// Pre-parse checks also see messages that do not parse
+	result = v.Validate(context.Background(), "WIP")
+	if errs := result.ValidationErrors(); len(errs) != 2 || errs[0].Message != "wip: no WIP commits" || errs[1].Field != "format" {
+		t.Errorf("Validate(WIP) errors = %+v", errs)
+	}
+}
+
+func TestValidator_CommandCheckFailures(t *testing.T) {
+	tests := []struct {
+		check config.CheckConfig
+		want  string
+	}{
+		{shellCheck(t, "broken", "", "echo 'no such rule' >&2; exit 3"), "check broken failed: exit status 3: no such rule"},
+		{shellCheck(t, "garbled", "", "echo not json"), "check garbled failed: parsing output"},
+		{config.CheckConfig{Name: "slow", Command: []string{"sleep", "5"}, Timeout: 1}, "check slow failed: timed out after 1s"},
+	}
+	for _, tt := range tests {
+		cfg := config.Default()
+		cfg.Checks = []config.CheckConfig{tt.check}
+		v, err := New(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create validator: %v", err)
+		}
+		result := v.Validate(context.Background(), "feat: add login")
+		if result.Valid || !strings.HasPrefix(result.Error(), "check: "+tt.want) {
+			t.Errorf("Validate() with %s = %q, want %q", tt.check.Name, result.Error(), tt.want)
+		}
+	}
+}
+
+func TestValidator_PostValidateHook(t *testing.T) {
+	v, err := New(config.Default())
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	v.AddPostValidateHook("owners", func(_ context.Context, commit *conventionalcommit.Commit, _ *ValidationResult) (*CheckResult, error) {
+		if commit.Scope == "" {
+			return &CheckResult{Violations: []*ValidationError{{Message: "name the owning team in the scope"}}}, nil
+		}
+		return nil, nil
+	})
+
+	if result := v.Validate(context.Background(), "feat(payments): add refunds"); !result.Valid {
+		t.Errorf("Validate() = %v, want valid", result.Errors)
+	}
+	if result := v.Validate(context.Background(), "feat: add refunds"); result.Valid || result.Error() != "check: owners: name the owning team in the scope" {
+		t.Errorf("Validate() = %q", result.Error())
+	}
+}
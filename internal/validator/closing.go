@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+var (
+	// closingKeywordRegex matches a GitHub issue-closing line such as
+	// "Fixes #12" or "Closes: org/repo#7, #8".
+	closingKeywordRegex = regexp.MustCompile(`(?i)^(close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+(.+)$`)
+	// closingRefRegex matches the references GitHub auto-closes: #N,
+	// OWNER/REPO#N or an issue URL.
+	closingRefRegex = regexp.MustCompile(`^(?:(?:[\w.-]+/[\w.-]+)?#\d+|https://github\.com/[\w.-]+/[\w.-]+/issues/\d+)$`)
+)
+
+// validateClosingKeyword requires fix commits to close an issue from the
+// footer when require_closing_keyword is set, and checks the references so
+// GitHub actually closes the issue on merge.
+func (v *Validator) validateClosingKeyword(commit *conventionalcommit.Commit, result *ValidationResult) {
+	if !v.config.RequireClosingKeyword || commit.Type != "fix" {
+		return
+	}
+
+	found := false
+	for _, line := range strings.Split(lastParagraph(commit), "\n") {
+		matches := closingKeywordRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		found = true
+		for _, ref := range strings.Split(matches[2], ",") {
+			if ref = strings.TrimSpace(ref); !closingRefRegex.MatchString(ref) {
+				v.addValidationErrorAt(result, "closing",
+					"closing reference must be #N, OWNER/REPO#N or a GitHub issue URL", ref, commit.LocateLast(ref))
+			}
+		}
+	}
+	if !found {
+		v.addValidationError(result, "closing",
+			`fix commits must close an issue in the footer (e.g. "Fixes #123")`, "")
+	}
+}
+
+// lastParagraph returns the footer, or the last paragraph of the body when
+// closing lines such as "Closes org/repo#7" were not parsed as trailers.
+func lastParagraph(commit *conventionalcommit.Commit) string {
+	if commit.Footer != "" {
+		return commit.Footer
+	}
+	paragraphs := strings.Split(commit.Body, "\n\n")
+	return paragraphs[len(paragraphs)-1]
+}
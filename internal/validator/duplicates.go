@@ -0,0 +1,53 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RecentCommit is a previous commit the duplicate subject guard compares
+// new subjects with.
+type RecentCommit struct {
+	Hash    string
+	Subject string
+}
+
+// SetRecentCommits sets the latest commits of the repository, newest first,
+// enabling the duplicate_subjects warning. Only the first
+// duplicate_subjects of them are compared.
+func (v *Validator) SetRecentCommits(commits []RecentCommit) {
+	v.recent = commits
+}
+
+// warnDuplicateSubject warns when the subject of message repeats one of the
+// recent commits, as in "fix" or "wip" loops. Repeats are not errors: a
+// revert of a revert or a re-landed change may legitimately reuse one.
+func (v *Validator) warnDuplicateSubject(message string, result *ValidationResult) {
+	n := v.config.DuplicateSubjects
+	if n <= 0 || len(v.recent) == 0 {
+		return
+	}
+	subject := normalizeSubject(message)
+	if subject == "" {
+		return
+	}
+	for _, commit := range v.recent[:min(n, len(v.recent))] {
+		if normalizeSubject(commit.Subject) != subject {
+			continue
+		}
+		hash := commit.Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"subject repeats commit %s; use git commit --fixup=%s to fold this change into it, or describe what this commit changes", hash, hash))
+		return
+	}
+}
+
+// normalizeSubject returns the first line of message in lower case with
+// runs of whitespace collapsed, so trivially different subjects compare equal.
+func normalizeSubject(message string) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return strings.ToLower(strings.Join(strings.Fields(subject), " "))
+}
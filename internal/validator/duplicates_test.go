@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+func TestValidate_DuplicateSubjects(t *testing.T) {
+	cfg := config.Default()
+	cfg.DuplicateSubjects = 2
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	v.SetRecentCommits([]RecentCommit{
+		{Hash: "1111111aaaa", Subject: "fix: typo"},
+		{Hash: "2222222bbbb", Subject: "wip"},
+		{Hash: "3333333cccc", Subject: "feat: add login"},
+	})
+
+	tests := []struct {
+		message string
+		warning string
+	}{
+		{message: "fix: typo", warning: "--fixup=1111111"},
+		{message: "Fix:  Typo\n\nAnother one.", warning: "--fixup=1111111"},
+		{message: "wip", warning: "--fixup=2222222"},
+		// Beyond the last two commits
+		{message: "feat: add login"},
+		{message: "fix: typo in the login form"},
+	}
+	for _, tt := range tests {
+		result := v.Validate(context.Background(), tt.message)
+		got := strings.Join(result.Warnings, "\n")
+		if tt.warning == "" && got != "" || !strings.Contains(got, tt.warning) {
+			t.Errorf("Validate(%q) warnings = %q, want %q", tt.message, got, tt.warning)
+		}
+	}
+
+	cfg.DuplicateSubjects = 0
+	if result := v.Validate(context.Background(), "fix: typo"); len(result.Warnings) != 0 {
+		t.Errorf("Validate() with duplicate_subjects 0 warned: %v", result.Warnings)
+	}
+}
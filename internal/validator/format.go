@@ -0,0 +1,164 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/fileutil"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// kebabCaseRegex matches lowercase words joined by single hyphens.
+var kebabCaseRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// validateTypeCase enforces type_case. It reports whether the type failed,
+// so the allowed-types check does not report the same problem twice.
+func (v *Validator) validateTypeCase(commit *conventionalcommit.Commit, result *ValidationResult) bool {
+	if v.config.TypeCase != "lower" || commit.Type == strings.ToLower(commit.Type) {
+		return false
+	}
+	v.addValidationErrorAt(result, "type", "type must be lowercase", commit.Type, commit.TypeSpan())
+	return true
+}
+
+// validateScopeFormat applies the scope_format rules to each scope.
+func (v *Validator) validateScopeFormat(commit *conventionalcommit.Commit, result *ValidationResult) {
+	spans := commit.ScopeSpans()
+	for i, scope := range commit.Scopes {
+		if scope != "" {
+			v.validateScopeFormatOf(scope, spans[i], result)
+		}
+	}
+}
+
+// validateScopeFormatOf applies the scope_format rules to a single scope.
+func (v *Validator) validateScopeFormatOf(scope string, span conventionalcommit.Span, result *ValidationResult) {
+	format := v.config.ScopeFormat
+	switch {
+	case format.Case == "lower" && scope != strings.ToLower(scope):
+		v.addValidationErrorAt(result, "scope", "scope must be lowercase", scope, span)
+	case format.Case == "kebab" && !kebabCaseRegex.MatchString(scope):
+		v.addValidationErrorAt(result, "scope", "scope must be kebab-case (e.g. user-auth)", scope, span)
+	}
+	if format.MaxLength > 0 && utf8.RuneCountInString(scope) > format.MaxLength {
+		v.addValidationErrorAt(result, "scope",
+			fmt.Sprintf("exceeds maximum scope length of %d characters", format.MaxLength), scope, span)
+	}
+	if v.scopeCharsetRegex != nil && !v.scopeCharsetRegex.MatchString(scope) {
+		v.addValidationErrorAt(result, "scope",
+			fmt.Sprintf("scope may only contain [%s]", format.Charset), scope, span)
+	}
+}
+
+// Fix rewrites the header of message so that the type and scope follow
+// type_case and scope_format case, and strips redundant words with
+// no_redundant_words. It reports whether anything changed; other problems
+// are left for Validate to report.
+func (v *Validator) Fix(message string) (string, bool) {
+	commit, err := v.parser.Parse(message)
+	if err != nil || commit.Type == "" {
+		return message, false
+	}
+
+	typ, scope, description := commit.Type, v.fixScope(commit.Scope), commit.Description
+	if v.config.TypeCase == "lower" {
+		typ = strings.ToLower(typ)
+	}
+	if v.config.NoRedundantWords {
+		description = fixRedundantWords(commit.Type, description)
+	}
+	if typ == commit.Type && scope == commit.Scope && description == commit.Description {
+		return message, false
+	}
+
+	// Only the type, scope and description are replaced, keeping the rest of
+	// the header and message exactly as written.
+	header, rest, hasBody := strings.Cut(message, "\n")
+	prefix, fixedPrefix := commit.Type, typ
+	if strings.HasPrefix(header[len(prefix):], "(") {
+		prefix += "(" + commit.Scope + ")"
+		fixedPrefix += "(" + scope + ")"
+	}
+	fixed := fixedPrefix + header[len(prefix):len(header)-len(commit.Description)] + description
+	if hasBody {
+		fixed += "\n" + rest
+	}
+	return fixed, true
+}
+
+// fixScope converts each scope in a delimited scope list to the
+// scope_format case, keeping the delimiters.
+func (v *Validator) fixScope(scope string) string {
+	var fix func(string) string
+	switch v.config.ScopeFormat.Case {
+	case "lower":
+		fix = strings.ToLower
+	case "kebab":
+		fix = toKebabCase
+	default:
+		return scope
+	}
+
+	var sb strings.Builder
+	for {
+		i := strings.IndexAny(scope, v.config.ScopeDelimiters)
+		if i < 0 {
+			sb.WriteString(fix(strings.TrimSpace(scope)))
+			return sb.String()
+		}
+		sb.WriteString(fix(strings.TrimSpace(scope[:i])))
+		sb.WriteByte(scope[i])
+		scope = scope[i+1:]
+	}
+}
+
+// FixFile applies Fix to the commit message file at path, skipping the
+// comment and blank lines git puts before the header, and reports the fixed
+// header (empty when nothing changed).
+func (v *Validator) FixFile(path string) (string, error) {
+	content, err := fileutil.SafeReadCommitFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading commit file: %w", err)
+	}
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		fixed, ok := v.Fix(line)
+		if !ok {
+			return "", nil
+		}
+		lines[i] = fixed
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+			return "", fmt.Errorf("writing commit file: %w", err)
+		}
+		return fixed, nil
+	}
+	return "", nil
+}
+
+// toKebabCase converts camelCase, snake_case and spaced words to kebab-case.
+func toKebabCase(s string) string {
+	var sb strings.Builder
+	var prev rune
+	for _, r := range s {
+		switch {
+		case r == '_' || r == ' ' || r == '-':
+			r = '-'
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			sb.WriteRune('-')
+		}
+		if r == '-' && (prev == '-' || sb.Len() == 0) {
+			continue
+		}
+		sb.WriteRune(unicode.ToLower(r))
+		prev = r
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
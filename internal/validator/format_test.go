@@ -0,0 +1,111 @@
+package validator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+func newFormatValidator(t *testing.T) *Validator {
+	t.Helper()
+	cfg := config.Default()
+	cfg.TypeCase = "lower"
+	cfg.ScopeFormat = config.ScopeFormatConfig{Case: "kebab", MaxLength: 12, Charset: "a-z0-9-"}
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return v
+}
+
+func TestValidate_ScopeFormat(t *testing.T) {
+	v := newFormatValidator(t)
+	tests := []struct {
+		message string
+		errors  int
+	}{
+		{message: "feat(user-auth): add login", errors: 0},
+		{message: "feat(userAuth): add login", errors: 2},          // Not kebab-case, uppercase outside the charset.
+		{message: "feat(authentication-v2): add login", errors: 1}, // Too long.
+		{message: "Feat(api): add login", errors: 1},               // Type case only, not also an invalid type.
+		{message: "feat(user_auth): add login", errors: 2},         // Not kebab-case, underscore outside the charset.
+		{message: "feat: add login without a scope", errors: 0},
+	}
+	for _, tt := range tests {
+		result := v.Validate(context.Background(), tt.message)
+		if len(result.Errors) != tt.errors {
+			t.Errorf("Validate(%q) = %v, want %d errors", tt.message, result.Errors, tt.errors)
+		}
+	}
+}
+
+func TestFix(t *testing.T) {
+	v := newFormatValidator(t)
+	tests := []struct {
+		message string
+		want    string
+		changed bool
+	}{
+		{message: "Feat(UserAuth)!: add login\n\nBody", want: "feat(user-auth)!: add login\n\nBody", changed: true},
+		{message: "fix(user_auth service): typo", want: "fix(user-auth-service): typo", changed: true},
+		{message: "FIX: typo", want: "fix: typo", changed: true},
+		{message: "fix(api): already fine", want: "fix(api): already fine"},
+		{message: "not conventional", want: "not conventional"},
+	}
+	for _, tt := range tests {
+		got, changed := v.Fix(tt.message)
+		if got != tt.want || changed != tt.changed {
+			t.Errorf("Fix(%q) = %q, %v, want %q, %v", tt.message, got, changed, tt.want, tt.changed)
+		}
+	}
+}
+
+func TestFixFile(t *testing.T) {
+	v := newFormatValidator(t)
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte("# comment\n\nFeat(WebApp): add page\n\n# Please enter the commit message\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fixed, err := v.FixFile(path)
+	if err != nil || fixed != "feat(web-app): add page" {
+		t.Fatalf("FixFile() = %q, %v", fixed, err)
+	}
+	data, _ := os.ReadFile(path)
+	if want := "# comment\n\nfeat(web-app): add page\n\n# Please enter the commit message\n"; string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+}
+
+func TestValidate_MultipleScopes(t *testing.T) {
+	cfg := config.Default()
+	cfg.Scopes = []string{"api", "web", "db"}
+	cfg.ScopeDelimiters = ","
+	cfg.MaxScopes = 2
+	cfg.ScopeFormat.Case = "lower"
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := map[string]int{
+		"feat(api,web): add login":    0,
+		"feat(api, web): add login":   0,
+		"feat(api,web,db): add login": 1, // Too many scopes.
+		"feat(api,cli): add login":    1, // cli is not allowed.
+		"feat(api,): add login":       1, // Empty scope.
+		"feat(api/web): add login":    1, // "/" is not a delimiter here.
+	}
+	for message, errors := range tests {
+		if result := v.Validate(context.Background(), message); len(result.Errors) != errors {
+			t.Errorf("Validate(%q) = %v, want %d errors", message, result.Errors, errors)
+		}
+	}
+
+	if fixed, _ := v.Fix("feat(API, Web): add login"); fixed != "feat(api,web): add login" {
+		t.Errorf("Fix() = %q", fixed)
+	}
+}
@@ -0,0 +1,142 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// Subject length modes for the subject_length_mode setting.
+const (
+	lengthRunes     = "runes"
+	lengthGraphemes = "graphemes"
+	lengthWidth     = "width"
+)
+
+// textLength measures text in the given mode: runes (the default), grapheme
+// clusters, or terminal display columns where wide CJK characters and emoji
+// count twice.
+func textLength(text, mode string) int {
+	switch mode {
+	case lengthGraphemes:
+		return len(graphemes(text))
+	case lengthWidth:
+		width := 0
+		for _, cluster := range graphemes(text) {
+			width += clusterWidth(cluster)
+		}
+		return width
+	default:
+		return utf8.RuneCountInString(text)
+	}
+}
+
+// overLimitSpan locates the end of the header that takes it over units past
+// the length limit, in the configured subject_length_mode.
+func (v *Validator) overLimitSpan(commit *conventionalcommit.Commit, over int) conventionalcommit.Span {
+	span := conventionalcommit.HeaderSpan(commit.Raw)
+	if span.IsZero() || over <= 0 {
+		return conventionalcommit.Span{}
+	}
+	header, _, _ := strings.Cut(commit.Raw, "\n")
+	runes := []rune(strings.TrimRight(header, " \t"))
+	n := 1
+	for n < len(runes) && textLength(string(runes[len(runes)-n:]), v.config.SubjectLengthMode) < over {
+		n++
+	}
+	span.Column = span.EndColumn - n
+	return span
+}
+
+// graphemes splits text into user-perceived characters. It approximates the
+// Unicode segmentation rules well enough for commit subjects: combining
+// marks, variation selectors, emoji modifiers and tags extend the previous
+// character, a zero width joiner joins the next one, and regional
+// indicators pair into flags.
+func graphemes(text string) [][]rune {
+	var clusters [][]rune
+	join := false
+	for _, r := range text {
+		last := len(clusters) - 1
+		switch {
+		case last >= 0 && (join || isExtender(r)):
+			clusters[last] = append(clusters[last], r)
+		case last >= 0 && isRegionalIndicator(r) && len(clusters[last]) == 1 && isRegionalIndicator(clusters[last][0]):
+			clusters[last] = append(clusters[last], r)
+		default:
+			clusters = append(clusters, []rune{r})
+		}
+		join = r == '\u200d'
+	}
+	return clusters
+}
+
+// isExtender reports whether r belongs to the preceding grapheme cluster.
+func isExtender(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r == '\u200d', r >= 0xFE00 && r <= 0xFE0F, r >= 0xE0100 && r <= 0xE01EF:
+		return true // Zero width joiner and variation selectors.
+	case r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F:
+		return true // Emoji skin tone modifiers and tags.
+	case r >= 0x1160 && r <= 0x11FF:
+		return true // Hangul medial vowels and final consonants.
+	}
+	return false
+}
+
+// isRegionalIndicator reports whether r is one half of a flag emoji.
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// clusterWidth returns the display columns of a grapheme cluster.
+func clusterWidth(cluster []rune) int {
+	r := cluster[0]
+	switch {
+	case unicode.IsControl(r):
+		return 0
+	case isWide(r) || isRegionalIndicator(r):
+		return 2
+	}
+	for _, extra := range cluster[1:] {
+		if extra == '\ufe0f' {
+			return 2 // Emoji presentation selector.
+		}
+	}
+	return 1
+}
+
+// wideRanges are the East Asian wide and fullwidth blocks, plus the emoji
+// blocks terminals render two columns wide.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo initial consonants.
+	{0x2E80, 0x303E},   // CJK radicals, Kangxi radicals, CJK symbols and punctuation.
+	{0x3041, 0x33FF},   // Hiragana, Katakana, Bopomofo, Hangul compatibility jamo, CJK compatibility.
+	{0x3400, 0x4DBF},   // CJK unified ideographs extension A.
+	{0x4E00, 0x9FFF},   // CJK unified ideographs.
+	{0xA000, 0xA4CF},   // Yi syllables and radicals.
+	{0xAC00, 0xD7A3},   // Hangul syllables.
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs.
+	{0xFE30, 0xFE4F},   // CJK compatibility forms.
+	{0xFF00, 0xFF60},   // Fullwidth forms.
+	{0xFFE0, 0xFFE6},   // Fullwidth signs.
+	{0x1F300, 0x1F64F}, // Miscellaneous symbols and pictographs, emoticons.
+	{0x1F680, 0x1F6FF}, // Transport and map symbols.
+	{0x1F900, 0x1F9FF}, // Supplemental symbols and pictographs.
+	{0x20000, 0x2FFFD}, // CJK unified ideographs extensions B to F.
+	{0x30000, 0x3FFFD}, // CJK unified ideographs extension G.
+}
+
+// isWide reports whether r occupies two terminal columns.
+func isWide(r rune) bool {
+	for _, span := range wideRanges {
+		if r >= span[0] && r <= span[1] {
+			return true
+		}
+	}
+	return false
+}
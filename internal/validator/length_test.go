@@ -0,0 +1,90 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+func TestTextLength(t *testing.T) {
+	tests := []struct {
+		text                   string
+		runes, graphemes, cols int
+	}{
+		{text: "fix: typo", runes: 9, graphemes: 9, cols: 9},
+		{text: "修正: ログイン", runes: 8, graphemes: 8, cols: 14},
+		{text: "cafe\u0301", runes: 5, graphemes: 4, cols: 4},
+		{text: "👩\u200d💻 ok", runes: 6, graphemes: 4, cols: 5},
+		{text: "🇯🇵", runes: 2, graphemes: 1, cols: 2},
+	}
+	for _, tt := range tests {
+		if got := textLength(tt.text, ""); got != tt.runes {
+			t.Errorf("textLength(%q, runes) = %d, want %d", tt.text, got, tt.runes)
+		}
+		if got := textLength(tt.text, lengthGraphemes); got != tt.graphemes {
+			t.Errorf("textLength(%q, graphemes) = %d, want %d", tt.text, got, tt.graphemes)
+		}
+		if got := textLength(tt.text, lengthWidth); got != tt.cols {
+			t.Errorf("textLength(%q, width) = %d, want %d", tt.text, got, tt.cols)
+		}
+	}
+}
+
+func TestValidate_SubjectLengthMode(t *testing.T) {
+	// 24 characters but 50 bytes, and 37 display columns.
+	message := "feat(api): ユーザーログイン機能を追加"
+
+	for mode, valid := range map[string]bool{"": true, "graphemes": true, "width": false} {
+		cfg := &config.Config{Types: config.DefaultTypes(), MaxSubjectLength: 30, SubjectLengthMode: mode}
+		v, err := New(cfg)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if result := v.Validate(context.Background(), message); result.Valid != valid {
+			t.Errorf("mode %q: valid = %v, want %v (%v)", mode, result.Valid, valid, result)
+		}
+	}
+}
+
+func TestValidate_TypeRules(t *testing.T) {
+	required, optional := true, false
+	cfg := config.Default()
+	cfg.MaxSubjectLength = 40
+	cfg.ScopeRequired = false
+	cfg.TypeRules = map[string]config.TypeRule{
+		"docs":  {MaxSubjectLength: 60},
+		"feat":  {ScopeRequired: &required},
+		"chore": {ScopeRequired: &optional},
+	}
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		message string
+		valid   bool
+	}{
+		{message: "docs: describe the configuration file in detail", valid: true},
+		{message: "fix: describe the configuration file in detail", valid: false},
+		{message: "feat: add login", valid: false},
+		{message: "feat(auth): add login", valid: true},
+		{message: "chore: bump deps", valid: true},
+	}
+	for _, tt := range tests {
+		if result := v.Validate(context.Background(), tt.message); result.Valid != tt.valid {
+			t.Errorf("Validate(%q) valid = %v, want %v (%v)", tt.message, result.Valid, tt.valid, result)
+		}
+	}
+
+	// The global scope_required can be lifted for a type
+	cfg.ScopeRequired = true
+	v, _ = New(cfg)
+	if result := v.Validate(context.Background(), "chore: bump deps"); !result.Valid {
+		t.Errorf("chore without a scope is invalid: %v", result)
+	}
+	if result := v.Validate(context.Background(), "fix: typo"); result.Valid {
+		t.Error("fix without a scope is valid")
+	}
+}
@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+// RuleTestResult is the outcome of one config test.
+type RuleTestResult struct {
+	Test config.RuleTest
+	// Problem explains why the test failed; it is empty when it passed.
+	Problem string
+}
+
+// Passed reports whether the message had the expected outcome.
+func (r RuleTestResult) Passed() bool {
+	return r.Problem == ""
+}
+
+// RunRuleTests validates each test message and compares the outcome with the
+// expected one, in test order.
+func (v *Validator) RunRuleTests(ctx context.Context, tests []config.RuleTest) []RuleTestResult {
+	results := make([]RuleTestResult, len(tests))
+	for i, test := range tests {
+		results[i] = RuleTestResult{Test: test, Problem: ruleTestProblem(test, v.Validate(ctx, test.Message))}
+	}
+	return results
+}
+
+// ruleTestProblem compares a validation result with a test's expectation.
+func ruleTestProblem(test config.RuleTest, result *ValidationResult) string {
+	if test.Expect == "pass" {
+		if !result.Valid {
+			return "expected to pass, but got: " + result.Error()
+		}
+		return ""
+	}
+	if result.Valid {
+		return "expected to fail, but it passed"
+	}
+
+	var hit []string
+	for _, err := range result.ValidationErrors() {
+		hit = append(hit, err.Field)
+		if err.Rule != "" {
+			hit = append(hit, err.Rule)
+		}
+	}
+	var missing []string
+	for _, rule := range test.Rules {
+		if !slices.Contains(hit, rule) {
+			missing = append(missing, rule)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("expected to break %s, but got: %s", strings.Join(missing, ", "), result.Error())
+	}
+	return ""
+}
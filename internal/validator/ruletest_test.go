@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+func TestRunRuleTests(t *testing.T) {
+	v, err := New(&config.Config{
+		Types:            config.DefaultTypes(),
+		MaxSubjectLength: 72,
+		CustomRules: []config.CustomRule{
+			{Name: "jira-ticket", Pattern: `\[JIRA-\d+\]`, Message: "commit must reference a JIRA ticket"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		test    config.RuleTest
+		problem string
+	}{
+		{test: config.RuleTest{Message: "feat: [JIRA-1] add login", Expect: "pass"}},
+		{test: config.RuleTest{Message: "feat: add login", Rules: []string{"jira-ticket"}}},
+		{test: config.RuleTest{Message: "wip: add login", Rules: []string{"type"}}},
+		{test: config.RuleTest{Message: "feat: add login", Expect: "pass"}, problem: "expected to pass"},
+		{test: config.RuleTest{Message: "feat: [JIRA-1] add login", Expect: "fail"}, problem: "expected to fail"},
+		{test: config.RuleTest{Message: "feat: add login", Rules: []string{"jira-ticket", "scope"}}, problem: "expected to break scope,"},
+	}
+	ruleTests := make([]config.RuleTest, len(tests))
+	for i, tt := range tests {
+		ruleTests[i] = tt.test
+	}
+
+	results := v.RunRuleTests(context.Background(), ruleTests)
+	if len(results) != len(tests) {
+		t.Fatalf("RunRuleTests() returned %d results, want %d", len(results), len(tests))
+	}
+	for i, tt := range tests {
+		got := results[i]
+		if tt.problem == "" && !got.Passed() {
+			t.Errorf("%q: unexpected problem %q", tt.test.Message, got.Problem)
+		}
+		if tt.problem != "" && !strings.HasPrefix(got.Problem, tt.problem) {
+			t.Errorf("%q: problem = %q, want prefix %q", tt.test.Message, got.Problem, tt.problem)
+		}
+	}
+}
+
+func TestValidate_CustomRuleName(t *testing.T) {
+	v, err := New(&config.Config{
+		Types:            config.DefaultTypes(),
+		MaxSubjectLength: 72,
+		CustomRules:      []config.CustomRule{{Name: "jira-ticket", Pattern: `\[JIRA-\d+\]`, Message: "commit must reference a JIRA ticket"}},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	errs := v.Validate(context.Background(), "feat: add login").ValidationErrors()
+	if len(errs) != 1 || errs[0].Rule != "jira-ticket" {
+		t.Fatalf("errors = %v, want one error of rule jira-ticket", errs)
+	}
+}
@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Signature is the signature of a commit as git verified it.
+type Signature struct {
+	// Status is git's %G? verdict: "G" good, "U" good with unknown
+	// validity, "N" unsigned, "E" not checkable (such as a missing key),
+	// and "B", "X", "Y" or "R" for bad, expired or revoked signatures.
+	Status string
+	// Fingerprint and PrimaryFingerprint (%GF, %GP) and KeyID (%GK) name
+	// the signing key; Signer (%GS) is who it belongs to.
+	Fingerprint        string
+	PrimaryFingerprint string
+	KeyID              string
+	Signer             string
+}
+
+// RequiresSignature reports whether signed_commits applies to the commit of
+// message, by its type or one of its scopes.
+func (v *Validator) RequiresSignature(message string) bool {
+	signed := v.config.SignedCommits
+	if len(signed.Types)+len(signed.Scopes) == 0 {
+		return false
+	}
+	commit, err := v.parser.Parse(message)
+	if err != nil {
+		return false
+	}
+	if slices.ContainsFunc(signed.Types, func(t string) bool { return strings.EqualFold(t, commit.Type) }) {
+		return true
+	}
+	for _, scope := range commit.Scopes {
+		if slices.ContainsFunc(signed.Scopes, func(s string) bool { return strings.EqualFold(s, scope) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckSignature fails result when the commit of message must be signed by
+// a trusted key and sig is not such a signature.
+func (v *Validator) CheckSignature(message string, sig Signature, result *ValidationResult) {
+	if !v.RequiresSignature(message) {
+		return
+	}
+	switch sig.Status {
+	case "G", "U":
+	case "", "N":
+		v.addValidationError(result, "signature", "this commit must be signed by a trusted key (git commit -S)", "")
+		return
+	case "E":
+		v.addValidationError(result, "signature", "the signature cannot be checked, as the server lacks the signing key", sig.KeyID)
+		return
+	default:
+		v.addValidationError(result, "signature", "the signature is bad, expired or revoked", sig.Status)
+		return
+	}
+	for _, key := range v.config.SignedCommits.Keys {
+		if trustedKey(key, sig) {
+			return
+		}
+	}
+	signer := sig.Fingerprint
+	if sig.Signer != "" {
+		signer = fmt.Sprintf("%s (%s)", sig.Fingerprint, sig.Signer)
+	}
+	v.addValidationError(result, "signature", "signed by a key that is not in signed_commits.keys", signer)
+}
+
+// trustedKey reports whether the allowed key names the key of sig: its
+// fingerprint, primary key fingerprint, or a long key ID ending either.
+func trustedKey(allowed string, sig Signature) bool {
+	allowed = normalizeKey(allowed)
+	for _, key := range []string{sig.Fingerprint, sig.PrimaryFingerprint, sig.KeyID} {
+		key = normalizeKey(key)
+		if key == "" {
+			continue
+		}
+		if key == allowed || (len(allowed) >= 16 && !strings.HasPrefix(key, "SHA256:") && strings.HasSuffix(key, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeKey writes GPG fingerprints and key IDs in upper case without
+// spaces or 0x; SSH fingerprints are case-sensitive and kept as they are.
+func normalizeKey(key string) string {
+	key = strings.TrimSpace(key)
+	if strings.HasPrefix(key, "SHA256:") {
+		return key
+	}
+	key = strings.ToUpper(strings.ReplaceAll(key, " ", ""))
+	return strings.TrimPrefix(key, "0X")
+}
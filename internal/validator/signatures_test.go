@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+func TestCheckSignature(t *testing.T) {
+	cfg := config.Default()
+	cfg.SignedCommits = config.SignedCommitsConfig{
+		Types:  []string{"revert"},
+		Scopes: []string{"release"},
+		Keys:   []string{"0x1234 5678 9ABC DEF0", "SHA256:abcDEF"},
+	}
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	gpg := Signature{Status: "G", Fingerprint: "AAAABBBBCCCCDDDD123456789abcdef0", KeyID: "123456789ABCDEF0"}
+
+	tests := []struct {
+		name    string
+		message string
+		sig     Signature
+		want    string
+	}{
+		{name: "other type", message: "feat: add login"},
+		{name: "unsigned", message: "revert: undo login", want: "must be signed"},
+		{name: "trusted GPG key", message: "revert: undo login", sig: gpg},
+		{name: "trusted SSH key", message: "fix(release): tag builds", sig: Signature{Status: "U", Fingerprint: "SHA256:abcDEF"}},
+		{name: "SSH fingerprints are case-sensitive", message: "fix(release): tag builds", sig: Signature{Status: "G", Fingerprint: "SHA256:ABCDEF"}, want: "not in signed_commits.keys"},
+		{name: "missing key", message: "revert: undo login", sig: Signature{Status: "E", KeyID: "123456789ABCDEF0"}, want: "cannot be checked"},
+		{name: "bad signature", message: "revert: undo login", sig: Signature{Status: "B", Fingerprint: gpg.Fingerprint}, want: "bad, expired or revoked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ValidationResult{Valid: true}
+			v.CheckSignature(tt.message, tt.sig, result)
+			if tt.want == "" {
+				if !result.Valid {
+					t.Errorf("CheckSignature() = %v, want valid", result.Errors)
+				}
+				return
+			}
+			if result.Valid || !strings.Contains(result.Error(), tt.want) {
+				t.Errorf("CheckSignature() = %q, want %q", result.Error(), tt.want)
+			}
+			if got := result.ValidationErrors()[0].Field; got != "signature" {
+				t.Errorf("field = %q, want signature", got)
+			}
+		})
+	}
+}
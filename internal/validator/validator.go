@@ -5,19 +5,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
 	"github.com/greenstevester/fast-cc-git-hooks/internal/fileutil"
 	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/ticket"
 )
 
+// placementTicketRegex matches JIRA keys of any project key length, for
+// ticket placement and count rules.
+var placementTicketRegex = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-\d+\b`)
+
 // ValidationError represents a validation failure.
 type ValidationError struct {
-	Field   string
-	Message string
-	Value   string
+	Field string `json:"field,omitempty"`
+	// Rule names the custom rule or check that produced a "custom" or
+	// "check" error.
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+	Value   string `json:"value,omitempty"`
+	// Line, Column and EndColumn locate the offending text in the message
+	// as a conventionalcommit.Span does; they are zero when the problem is
+	// something missing, such as a required ticket.
+	Line      int `json:"line,omitempty"`
+	Column    int `json:"column,omitempty"`
+	EndColumn int `json:"end_column,omitempty"`
 }
 
 func (e *ValidationError) Error() string {
@@ -27,10 +44,39 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Field, e.Message)
 }
 
+// ruleKeys maps validation error fields to the config keys of their rules.
+var ruleKeys = map[string][]string{
+	"type":         {"types", "type_case"},
+	"scope":        {"scopes", "scope_required", "scope_delimiters", "max_scopes", "scope_format"},
+	"subject":      {"max_subject_length", "exclude_ticket_from_length", "subject_length_mode"},
+	"breaking":     {"allow_breaking_changes", "require_breaking_description"},
+	"description":  {"no_redundant_words"},
+	"custom":       {"custom_rules"},
+	"smart_commit": {"smart_commits"},
+	"closing":      {"require_closing_keyword"},
+	"signoff":      {"require_signoff"},
+	"review":       {"review_trailers"},
+	"signature":    {"signed_commits"},
+	"check":        {"checks"},
+	"ticket": {
+		"require_jira_ticket", "require_ticket_ref", "jira_ticket_pattern", "jira_projects", "ticket_patterns",
+		"ticket_provider", "ticket_placement", "max_tickets_per_commit", "require_primary_ticket",
+		"verify_jira_tickets", "verify_tickets",
+	},
+}
+
+// ConfigKeys returns the config keys of the rule that produced the error.
+func (e *ValidationError) ConfigKeys() []string {
+	return ruleKeys[e.Field]
+}
+
 // ValidationResult contains all validation errors.
 type ValidationResult struct {
 	Errors []error
-	Valid  bool
+	// Warnings are problems that do not fail validation, such as an
+	// unreachable issue tracker.
+	Warnings []string
+	Valid    bool
 }
 
 // Error implements the error interface.
@@ -46,6 +92,32 @@ func (r *ValidationResult) Error() string {
 	return strings.Join(messages, "; ")
 }
 
+// ValidationErrors returns the errors as ValidationErrors, for encoding;
+// other errors keep only their message.
+func (r *ValidationResult) ValidationErrors() []*ValidationError {
+	errs := make([]*ValidationError, 0, len(r.Errors))
+	for _, err := range r.Errors {
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			validationErr = &ValidationError{Message: err.Error()}
+		}
+		errs = append(errs, validationErr)
+	}
+	return errs
+}
+
+// Unparsable reports whether validation failed because the message is empty
+// or not a conventional commit, rather than because it breaks a rule.
+func (r *ValidationResult) Unparsable() bool {
+	for _, err := range r.Errors {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) && (validationErr.Field == "format" || validationErr.Field == "message") {
+			return true
+		}
+	}
+	return false
+}
+
 // Validator validates commit messages according to configuration.
 type Validator struct {
 	config *config.Config
@@ -54,6 +126,53 @@ type Validator struct {
 	compiledRules map[string]*regexp.Regexp
 	// Compiled ignore patterns for performance.
 	compiledIgnorePatterns []*regexp.Regexp
+	// scopeCharsetRegex matches scopes made of the scope_format charset.
+	scopeCharsetRegex *regexp.Regexp
+	// projectTicketRegex matches tickets of the allowed JIRA projects, whose
+	// keys may be longer than the parser's generic JIRA pattern.
+	projectTicketRegex *regexp.Regexp
+	// tickets verifies JIRA tickets against the API when set.
+	tickets TicketLookup
+	// provider finds tickets of a non-JIRA tracker (nil for JIRA).
+	provider ticket.Provider
+	// verifier checks that the provider's tickets exist when set.
+	verifier ticket.Verifier
+	// branch is the branch commits are validated for, when known.
+	branch string
+	// hooks are the registered pre-parse and post-validate checks.
+	hooks []hook
+	// recent are the latest commits, for the duplicate subject warning.
+	recent []RecentCommit
+}
+
+// TicketLookup fetches JIRA issues; *jira.Client implements it.
+type TicketLookup interface {
+	GetIssue(ctx context.Context, key string) (*jira.Issue, error)
+}
+
+// SetTicketLookup enables verification that referenced JIRA tickets exist,
+// are not closed and belong to an allowed project.
+func (v *Validator) SetTicketLookup(lookup TicketLookup) {
+	v.tickets = lookup
+}
+
+// SetTicketVerifier enables verification that tickets of the configured
+// non-JIRA provider exist.
+func (v *Validator) SetTicketVerifier(verifier ticket.Verifier) {
+	v.verifier = verifier
+}
+
+// SetBranch sets the branch validated commits are pushed to, enabling
+// branch-specific rules such as review_trailers.require_reviewed_by. It is
+// known server-side, where the hook or CI job sees the updated ref.
+func (v *Validator) SetBranch(branch string) {
+	v.branch = strings.TrimPrefix(branch, "refs/heads/")
+}
+
+// Parser returns the parser messages are validated with, configured with
+// the scope delimiters and ticket patterns of the config.
+func (v *Validator) Parser() *conventionalcommit.Parser {
+	return v.parser
 }
 
 // New creates a new validator with the given configuration.
@@ -67,10 +186,23 @@ func New(cfg *config.Config) (*Validator, error) {
 		parser:        conventionalcommit.DefaultParser(),
 		compiledRules: make(map[string]*regexp.Regexp),
 	}
+	v.parser.ScopeDelimiters = cfg.ScopeDelimiters
+
+	if len(cfg.TicketPatterns) > 0 {
+		patterns := make([]conventionalcommit.TicketPattern, len(cfg.TicketPatterns))
+		for i, pattern := range cfg.TicketPatterns {
+			patterns[i] = conventionalcommit.TicketPattern{Name: pattern.Name, Pattern: pattern.Pattern, URLTemplate: pattern.URL}
+		}
+		if err := v.parser.SetTicketPatterns(patterns); err != nil {
+			return nil, err
+		}
+	}
+
+	v.addCommandChecks(cfg.Checks)
 
 	// Compile custom rules.
 	for _, rule := range cfg.CustomRules {
-		re, err := regexp.Compile(rule.Pattern)
+		re, err := conventionalcommit.CompilePattern(rule.Pattern)
 		if err != nil {
 			return nil, fmt.Errorf("compiling custom rule %s: %w", rule.Name, err)
 		}
@@ -79,17 +211,42 @@ func New(cfg *config.Config) (*Validator, error) {
 
 	// Compile JIRA ticket pattern if specified.
 	if cfg.JIRATicketPattern != "" {
-		re, err := regexp.Compile(cfg.JIRATicketPattern)
+		re, err := conventionalcommit.CompilePattern(cfg.JIRATicketPattern)
 		if err != nil {
 			return nil, fmt.Errorf("compiling JIRA ticket pattern: %w", err)
 		}
 		v.compiledRules["jira-pattern"] = re
 	}
 
+	if cfg.ScopeFormat.Charset != "" {
+		re, err := regexp.Compile("^[" + cfg.ScopeFormat.Charset + "]+$")
+		if err != nil {
+			return nil, fmt.Errorf("compiling scope charset: %w", err)
+		}
+		v.scopeCharsetRegex = re
+	}
+
+	// Compile the allowed JIRA project ticket pattern if specified.
+	if len(cfg.JIRAProjects) > 0 {
+		keys := make([]string, len(cfg.JIRAProjects))
+		for i, project := range cfg.JIRAProjects {
+			keys[i] = regexp.QuoteMeta(project)
+		}
+		v.projectTicketRegex = regexp.MustCompile(`\b(?:` + strings.Join(keys, "|") + `)-\d+\b`)
+	}
+
+	if cfg.TicketProvider != "" && cfg.TicketProvider != ticket.JIRA {
+		provider, err := ticket.New(cfg.TicketProvider, ticket.Settings{GitHubRepo: cfg.GitHubRepo})
+		if err != nil {
+			return nil, err
+		}
+		v.provider = provider
+	}
+
 	// Compile ignore patterns.
 	v.compiledIgnorePatterns = make([]*regexp.Regexp, 0, len(cfg.IgnorePatterns))
 	for _, pattern := range cfg.IgnorePatterns {
-		re, err := regexp.Compile(pattern)
+		re, err := conventionalcommit.CompilePattern(pattern)
 		if err != nil {
 			return nil, fmt.Errorf("compiling ignore pattern %q: %w", pattern, err)
 		}
@@ -111,15 +268,24 @@ func (v *Validator) Validate(ctx context.Context, message string) *ValidationRes
 		return result
 	}
 
+	// Reject oversized input before any pattern or hook sees it.
+	if err := v.parser.CheckSize(message); err != nil {
+		v.addValidationErrorAt(result, "format", err.Error(), "", conventionalcommit.Span{})
+		return result
+	}
+
 	// Check ignore patterns.
 	if v.shouldIgnore(message) {
 		return result
 	}
 
+	v.runPreParseHooks(ctx, message, result)
+	v.warnDuplicateSubject(message, result)
+
 	// Parse the commit message.
 	commit, err := v.parser.Parse(message)
 	if err != nil {
-		v.addValidationError(result, "format", err.Error(), "")
+		v.addValidationErrorAt(result, "format", err.Error(), "", conventionalcommit.HeaderSpan(message))
 		return result
 	}
 
@@ -127,31 +293,27 @@ func (v *Validator) Validate(ctx context.Context, message string) *ValidationRes
 	v.validateType(commit, result)
 	v.validateScope(commit, result)
 	v.validateSubjectLength(commit, result)
+	v.validateRedundantWords(commit, result)
 	v.validateBreakingChanges(commit, result)
+	v.validateSignoff(commit, result)
+	v.validateReviewTrailers(commit, result)
 	v.validateCustomRules(message, result)
 	v.validateTicketRequirements(commit, result)
+	v.validateClosingKeyword(commit, result)
+	v.verifyJiraTickets(ctx, commit, result)
+	v.verifyProviderTickets(ctx, commit, result)
+	v.validateSmartCommits(message, result)
+	v.runPostValidateHooks(ctx, commit, result)
 
 	return result
 }
 
 // ValidateFile validates commit messages from a file.
 func (v *Validator) ValidateFile(ctx context.Context, path string) (*ValidationResult, error) {
-	// Read commit message from file with validation.
-	content, err := fileutil.SafeReadCommitFile(path)
+	message, err := ReadMessageFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("reading commit file: %w", err)
-	}
-
-	// Remove comment lines (lines starting with #).
-	lines := strings.Split(content, "\n")
-	var messageLines []string
-	for _, line := range lines {
-		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
-			messageLines = append(messageLines, line)
-		}
+		return nil, err
 	}
-
-	message := strings.TrimSpace(strings.Join(messageLines, "\n"))
 	if message == "" {
 		return &ValidationResult{
 			Valid: false,
@@ -165,6 +327,26 @@ func (v *Validator) ValidateFile(ctx context.Context, path string) (*ValidationR
 	return v.Validate(ctx, message), nil
 }
 
+// ReadMessageFile reads a commit message file as ValidateFile sees it, with
+// comment lines removed.
+func ReadMessageFile(path string) (string, error) {
+	// Read commit message from file with validation.
+	content, err := fileutil.SafeReadCommitFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading commit file: %w", err)
+	}
+
+	// Remove comment lines (lines starting with #).
+	lines := strings.Split(content, "\n")
+	var messageLines []string
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			messageLines = append(messageLines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(messageLines, "\n")), nil
+}
+
 // shouldIgnore checks if a message matches any ignore pattern.
 func (v *Validator) shouldIgnore(message string) bool {
 	for _, re := range v.compiledIgnorePatterns {
@@ -181,18 +363,47 @@ func (v *Validator) validateTicketRequirements(commit *conventionalcommit.Commit
 	v.validateTicketRefRequired(commit, result)
 	v.validateJiraTicketPattern(commit, result)
 	v.validateJiraProjectPrefixes(commit, result)
+	v.validateTicketPlacement(commit, result)
+	v.validateTicketCount(commit, result)
 }
 
-// validateJiraTicketRequired checks if JIRA ticket is required.
+// validateJiraTicketRequired checks if JIRA ticket is required. With allowed
+// projects the ticket must belong to one of them; tickets from other projects
+// are reported by validateJiraProjectPrefixes.
 func (v *Validator) validateJiraTicketRequired(commit *conventionalcommit.Commit, result *ValidationResult) {
-	if v.config.RequireJIRATicket && !commit.HasJIRATicket() {
-		v.addValidationError(result, "ticket", "JIRA ticket reference is required", "")
+	if !v.config.RequireJIRATicket {
+		return
 	}
+
+	if v.projectTicketRegex == nil {
+		if !commit.HasJIRATicket() {
+			v.addValidationError(result, "ticket", "JIRA ticket reference is required", "")
+		}
+		return
+	}
+
+	if v.projectTicketRegex.MatchString(commit.Raw) || commit.HasJIRATicket() {
+		return
+	}
+	message := fmt.Sprintf("JIRA ticket reference is required from one of the allowed projects: %s (e.g. %s-123)",
+		strings.Join(v.config.JIRAProjects, ", "), v.config.JIRAProjects[0])
+	v.addValidationError(result, "ticket", message, "")
 }
 
-// validateTicketRefRequired checks if any ticket reference is required.
+// validateTicketRefRequired checks if any ticket reference is required. With a
+// non-JIRA provider the reference must be one of that tracker's tickets.
 func (v *Validator) validateTicketRefRequired(commit *conventionalcommit.Commit, result *ValidationResult) {
-	if v.config.RequireTicketRef && !commit.HasTicketRefs() {
+	if !v.config.RequireTicketRef {
+		return
+	}
+	if v.provider != nil {
+		if len(v.provider.Find(commit.Raw)) == 0 {
+			v.addValidationError(result, "ticket",
+				fmt.Sprintf("%s ticket reference is required (e.g. %s)", v.provider.Name(), v.provider.Example()), "")
+		}
+		return
+	}
+	if !commit.HasTicketRefs() {
 		v.addValidationError(result, "ticket", "ticket reference is required", "")
 	}
 }
@@ -212,8 +423,98 @@ func (v *Validator) validateJiraTicketPattern(commit *conventionalcommit.Commit,
 	for _, ticket := range jiraTickets {
 		if !re.MatchString(ticket.ID) {
 			message := fmt.Sprintf("JIRA ticket '%s' does not match required pattern", ticket.ID)
-			v.addValidationError(result, "ticket", message, ticket.ID)
+			v.addValidationErrorAt(result, "ticket", message, ticket.ID, commit.Locate(ticket.Raw))
+		}
+	}
+}
+
+// validateTicketPlacement checks that a referenced ticket appears where
+// ticket_placement requires it.
+func (v *Validator) validateTicketPlacement(commit *conventionalcommit.Commit, result *ValidationResult) {
+	if v.config.TicketPlacement == "" || len(v.findTickets(commit.Raw)) == 0 {
+		return
+	}
+
+	words := strings.Fields(commit.Description)
+	example := v.exampleTicket()
+	switch v.config.TicketPlacement {
+	case "start":
+		if len(words) == 0 || len(v.findTickets(words[0])) == 0 {
+			v.addValidationError(result, "ticket",
+				fmt.Sprintf("ticket must appear at the start of the description (e.g. %s: %s add login)", commit.Type, example), "")
+		}
+	case "end":
+		if len(words) == 0 || len(v.findTickets(words[len(words)-1])) == 0 {
+			v.addValidationError(result, "ticket",
+				fmt.Sprintf("ticket must appear at the end of the description (e.g. %s: add login %s)", commit.Type, example), "")
 		}
+	case "footer":
+		for _, line := range strings.Split(commit.Raw, "\n")[1:] {
+			key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+			if found && strings.EqualFold(key, "Refs") && len(v.findTickets(value)) > 0 {
+				return
+			}
+		}
+		v.addValidationError(result, "ticket",
+			fmt.Sprintf("ticket must be referenced in a footer (e.g. Refs: %s)", example), "")
+	}
+}
+
+// validateTicketCount enforces max_tickets_per_commit and a single primary
+// ticket in the subject.
+func (v *Validator) validateTicketCount(commit *conventionalcommit.Commit, result *ValidationResult) {
+	tickets := v.findTickets(commit.Raw)
+	if len(tickets) == 0 {
+		return
+	}
+
+	if limit := v.config.MaxTicketsPerCommit; limit > 0 && len(tickets) > limit {
+		v.addValidationError(result, "ticket",
+			fmt.Sprintf("commit references %d tickets (max %d): %s", len(tickets), limit, strings.Join(tickets, ", ")), "")
+	}
+
+	if !v.config.RequirePrimaryTicket {
+		return
+	}
+	switch primary := v.findTickets(commit.Description); len(primary) {
+	case 0:
+		v.addValidationError(result, "ticket",
+			fmt.Sprintf("primary ticket must appear in the subject (e.g. %s: %s add login); list related tickets in the body",
+				commit.Type, v.exampleTicket()), "")
+	case 1:
+	default:
+		v.addValidationError(result, "ticket",
+			fmt.Sprintf("only one primary ticket may appear in the subject (found %s); move related tickets to the body",
+				strings.Join(primary, ", ")), "")
+	}
+}
+
+// findTickets returns the distinct tickets referenced in text, in order: the
+// configured provider's, or JIRA keys (including long allowed project keys).
+func (v *Validator) findTickets(text string) []string {
+	if v.provider != nil {
+		return v.provider.Find(text)
+	}
+	var tickets []string
+	seen := make(map[string]bool)
+	for _, ticket := range placementTicketRegex.FindAllString(text, -1) {
+		if !seen[ticket] {
+			seen[ticket] = true
+			tickets = append(tickets, ticket)
+		}
+	}
+	return tickets
+}
+
+// exampleTicket returns a sample ticket reference for error messages.
+func (v *Validator) exampleTicket() string {
+	switch {
+	case v.provider != nil:
+		return v.provider.Example()
+	case len(v.config.JIRAProjects) > 0:
+		return v.config.JIRAProjects[0] + "-123"
+	default:
+		return "CGC-123"
 	}
 }
 
@@ -225,12 +526,12 @@ func (v *Validator) validateJiraProjectPrefixes(commit *conventionalcommit.Commi
 
 	jiraTickets := commit.GetJIRATickets()
 	for _, ticket := range jiraTickets {
-		v.validateJiraProjectPrefix(ticket, result)
+		v.validateJiraProjectPrefix(commit, ticket, result)
 	}
 }
 
 // validateJiraProjectPrefix validates a single JIRA project prefix.
-func (v *Validator) validateJiraProjectPrefix(ticket conventionalcommit.TicketRef, result *ValidationResult) {
+func (v *Validator) validateJiraProjectPrefix(commit *conventionalcommit.Commit, ticket conventionalcommit.TicketRef, result *ValidationResult) {
 	parts := strings.Split(ticket.ID, "-")
 	if len(parts) < 2 {
 		return // Skip malformed tickets.
@@ -241,9 +542,69 @@ func (v *Validator) validateJiraProjectPrefix(ticket conventionalcommit.TicketRe
 		return
 	}
 
-	message := fmt.Sprintf("JIRA project '%s' is not allowed (allowed: %s)",
+	message := fmt.Sprintf("JIRA project '%s' is not allowed; use a ticket from one of: %s",
 		projectPrefix, strings.Join(v.config.JIRAProjects, ", "))
-	v.addValidationError(result, "ticket", message, ticket.ID)
+	v.addValidationErrorAt(result, "ticket", message, ticket.ID, commit.Locate(ticket.Raw))
+}
+
+// verifyJiraTickets checks referenced JIRA tickets against the issue tracker.
+// Lookup failures other than a missing ticket only produce warnings so an
+// unreachable server never blocks a commit.
+func (v *Validator) verifyJiraTickets(ctx context.Context, commit *conventionalcommit.Commit, result *ValidationResult) {
+	if v.tickets == nil {
+		return
+	}
+
+	for _, ticket := range commit.GetJIRATickets() {
+		issue, err := v.tickets.GetIssue(ctx, ticket.ID)
+		switch {
+		case errors.Is(err, jira.ErrIssueNotFound):
+			v.addValidationErrorAt(result, "ticket", "JIRA ticket does not exist", ticket.ID, commit.Locate(ticket.Raw))
+			continue
+		case err != nil:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("could not verify JIRA ticket %s: %v", ticket.ID, err))
+			continue
+		}
+
+		if issue.IsClosed() {
+			v.addValidationErrorAt(result, "ticket",
+				fmt.Sprintf("JIRA ticket is closed (status: %s)", issue.Status), ticket.ID, commit.Locate(ticket.Raw))
+		}
+		if len(v.config.JIRAProjects) > 0 && issue.Project != "" && !v.isProjectAllowed(issue.Project) {
+			v.addValidationErrorAt(result, "ticket",
+				fmt.Sprintf("JIRA ticket belongs to project '%s' (allowed: %s)",
+					issue.Project, strings.Join(v.config.JIRAProjects, ", ")), ticket.ID, commit.Locate(ticket.Raw))
+		}
+	}
+}
+
+// verifyProviderTickets checks that the provider's tickets exist. As with
+// JIRA, only a confirmed missing ticket fails validation.
+func (v *Validator) verifyProviderTickets(ctx context.Context, commit *conventionalcommit.Commit, result *ValidationResult) {
+	if v.provider == nil || v.verifier == nil {
+		return
+	}
+
+	for _, ref := range v.provider.Find(commit.Raw) {
+		err := v.verifier.Verify(ctx, ref)
+		switch {
+		case errors.Is(err, ticket.ErrNotFound):
+			v.addValidationErrorAt(result, "ticket", fmt.Sprintf("%s ticket does not exist", v.provider.Name()), ref, commit.Locate(ref))
+		case err != nil:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("could not verify %s ticket %s: %v", v.provider.Name(), ref, err))
+		}
+	}
+}
+
+// validateSmartCommits checks JIRA smart commit commands when enabled.
+func (v *Validator) validateSmartCommits(message string, result *ValidationResult) {
+	if !v.config.SmartCommits.Enabled {
+		return
+	}
+	commands := jira.ParseSmartCommands(message)
+	for _, err := range jira.ValidateSmartCommands(commands, v.config.SmartCommits.AllowedTransitions) {
+		v.addValidationError(result, "smart_commit", err.Error(), "")
+	}
 }
 
 // isProjectAllowed checks if a project prefix is in the allowed list.
@@ -257,15 +618,30 @@ func (v *Validator) isProjectAllowed(projectPrefix string) bool {
 }
 
 // addValidationError adds a validation error to the result.
-func (*Validator) addValidationError(result *ValidationResult, field, message, value string) {
+func (v *Validator) addValidationError(result *ValidationResult, field, message, value string) {
+	v.addValidationErrorAt(result, field, message, value, conventionalcommit.Span{})
+}
+
+// addValidationErrorAt adds a validation error locating the offending text.
+func (*Validator) addValidationErrorAt(result *ValidationResult, field, message, value string, span conventionalcommit.Span) {
 	result.Valid = false
 	result.Errors = append(result.Errors, &ValidationError{
-		Field:   field,
-		Message: message,
-		Value:   value,
+		Field:     field,
+		Message:   message,
+		Value:     value,
+		Line:      span.Line,
+		Column:    span.Column,
+		EndColumn: span.EndColumn,
 	})
 }
 
+// addRuleError adds a validation error produced by a named custom rule or
+// check.
+func (v *Validator) addRuleError(result *ValidationResult, field, rule, message, value string, span conventionalcommit.Span) {
+	v.addValidationErrorAt(result, field, message, value, span)
+	result.Errors[len(result.Errors)-1].(*ValidationError).Rule = rule
+}
+
 // Quick validation helper for simple use cases.
 func Quick(message string) error {
 	cfg := config.Default()
@@ -295,41 +671,164 @@ func (*Validator) checkCancellation(ctx context.Context, result *ValidationResul
 
 // validateType validates the commit type.
 func (v *Validator) validateType(commit *conventionalcommit.Commit, result *ValidationResult) {
+	if v.validateTypeCase(commit, result) {
+		return
+	}
 	if commit.Type != "" && !v.config.HasType(commit.Type) {
-		v.addValidationError(result, "type",
+		v.addValidationErrorAt(result, "type",
 			fmt.Sprintf("invalid type (allowed: %s)", strings.Join(v.config.Types, ", ")),
-			commit.Type)
+			commit.Type, commit.TypeSpan())
 	}
 }
 
 // validateScope validates the commit scope.
 func (v *Validator) validateScope(commit *conventionalcommit.Commit, result *ValidationResult) {
-	if v.config.ScopeRequired && commit.Scope == "" {
-		v.addValidationError(result, "scope", "scope is required", "")
-	} else if commit.Scope != "" && !v.config.HasScope(commit.Scope) {
-		v.addValidationError(result, "scope",
-			fmt.Sprintf("invalid scope (allowed: %s)", strings.Join(v.config.Scopes, ", ")),
-			commit.Scope)
+	if v.config.ScopeRequiredFor(commit.Type) && commit.Scope == "" {
+		message := "scope is required"
+		if !v.config.ScopeRequired {
+			message = fmt.Sprintf("scope is required for %s commits", commit.Type)
+		}
+		v.addValidationError(result, "scope", message, "")
+		return
+	}
+	if limit := v.config.MaxScopes; limit > 0 && len(commit.Scopes) > limit {
+		v.addValidationErrorAt(result, "scope", fmt.Sprintf("at most %d scopes are allowed", limit), commit.Scope, commit.ScopeSpan())
+	}
+	spans := commit.ScopeSpans()
+	for i, scope := range commit.Scopes {
+		if scope == "" {
+			v.addValidationErrorAt(result, "scope", "scope list contains an empty scope", commit.Scope, commit.ScopeSpan())
+		} else if !v.config.HasScope(scope) {
+			v.addValidationErrorAt(result, "scope",
+				fmt.Sprintf("invalid scope (allowed: %s)", strings.Join(v.config.Scopes, ", ")),
+				scope, spans[i])
+		}
 	}
+	v.validateScopeFormat(commit, result)
 }
 
 // validateSubjectLength validates the subject line length.
 func (v *Validator) validateSubjectLength(commit *conventionalcommit.Commit, result *ValidationResult) {
-	header := commit.Header()
-	if len(header) > v.config.MaxSubjectLength {
-		v.addValidationError(result, "subject",
-			fmt.Sprintf("exceeds maximum length of %d characters", v.config.MaxSubjectLength),
-			fmt.Sprintf("%d characters", len(header)))
+	limit := v.config.SubjectLengthFor(commit.Type)
+	if length := v.subjectLength(commit.Header()); length > limit {
+		unit := "characters"
+		if v.config.SubjectLengthMode == lengthWidth {
+			unit = "columns"
+		}
+		message := fmt.Sprintf("exceeds maximum length of %d %s", limit, unit)
+		if v.config.ExcludeTicketFromLength {
+			message += " (excluding tickets)"
+		}
+		v.addValidationErrorAt(result, "subject", message, fmt.Sprintf("%d %s", length, unit),
+			v.overLimitSpan(commit, length-limit))
+	}
+}
+
+// subjectLength returns the header length counted against the limit, in the
+// configured subject_length_mode. With exclude_ticket_from_length, tickets
+// and the space before each do not count.
+func (v *Validator) subjectLength(header string) int {
+	mode := v.config.SubjectLengthMode
+	length := textLength(header, mode)
+	if !v.config.ExcludeTicketFromLength {
+		return length
+	}
+	for _, span := range v.ticketSpans(header) {
+		length -= textLength(header[span[0]:span[1]], mode) + 1
+	}
+	return length
+}
+
+// ticketSpans returns the start and end offsets of the ticket references in
+// text, none overlapping another.
+func (v *Validator) ticketSpans(text string) [][]int {
+	if v.provider == nil {
+		return placementTicketRegex.FindAllStringIndex(text, -1)
+	}
+	// Providers only return the references, so each is located in the text,
+	// longest first so one inside another (#12 in #123) is not counted twice
+	tickets := v.provider.Find(text)
+	sort.Slice(tickets, func(i, j int) bool { return len(tickets[i]) > len(tickets[j]) })
+	covered := make([]bool, len(text))
+	var spans [][]int
+	for _, ticket := range tickets {
+		if ticket == "" {
+			continue
+		}
+		for start := 0; ; {
+			i := strings.Index(text[start:], ticket)
+			if i < 0 {
+				break
+			}
+			from, to := start+i, start+i+len(ticket)
+			start = to
+			if covered[from] || covered[to-1] {
+				continue
+			}
+			for k := from; k < to; k++ {
+				covered[k] = true
+			}
+			spans = append(spans, []int{from, to})
+		}
 	}
+	return spans
 }
 
 // validateBreakingChanges validates breaking change rules.
 func (v *Validator) validateBreakingChanges(commit *conventionalcommit.Commit, result *ValidationResult) {
 	if commit.Breaking && !v.config.AllowBreakingChanges {
-		v.addValidationError(result, "breaking", "breaking changes are not allowed", "")
+		v.addValidationErrorAt(result, "breaking", "breaking changes are not allowed", "", commit.BreakingSpan())
+		return
+	}
+	if commit.Breaking && v.config.RequireBreakingDescription && commit.BreakingDescription == "" {
+		v.addValidationErrorAt(result, "breaking",
+			"breaking changes need a \"BREAKING CHANGE: <description>\" footer describing what broke", "", commit.BreakingSpan())
+	}
+}
+
+// validateSignoff requires a Signed-off-by trailer when require_signoff is set.
+func (v *Validator) validateSignoff(commit *conventionalcommit.Commit, result *ValidationResult) {
+	if !v.config.RequireSignoff {
+		return
+	}
+	if value, ok := commit.GetTrailer("Signed-off-by"); !ok || value == "" {
+		v.addValidationError(result, "signoff", "commit must be signed off with a Signed-off-by trailer (git commit -s)", "")
 	}
 }
 
+// validateReviewTrailers checks that review trailers name a person, and
+// requires a Reviewed-by trailer on commits to protected branches.
+func (v *Validator) validateReviewTrailers(commit *conventionalcommit.Commit, result *ValidationResult) {
+	cfg := v.config.ReviewTrailers
+	reviewed := false
+	for _, review := range commit.Reviews() {
+		if cfg.Validate && review.Email == "" {
+			v.addValidationError(result, "review", review.Key+" must name the reviewer as \"Name <email>\"", review.Value)
+		}
+		if review.Key == "Reviewed-by" && strings.TrimSpace(review.Value) != "" {
+			reviewed = true
+		}
+	}
+	if reviewed || !v.isProtectedBranch() {
+		return
+	}
+	v.addValidationError(result, "review", "commits to "+v.branch+" need a Reviewed-by trailer", "")
+}
+
+// isProtectedBranch reports whether the validated branch matches a pattern of
+// review_trailers.require_reviewed_by.
+func (v *Validator) isProtectedBranch() bool {
+	if v.branch == "" {
+		return false
+	}
+	for _, pattern := range v.config.ReviewTrailers.RequireReviewedBy {
+		if ok, _ := path.Match(pattern, v.branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // validateCustomRules applies custom validation rules.
 func (v *Validator) validateCustomRules(message string, result *ValidationResult) {
 	for _, rule := range v.config.CustomRules {
@@ -339,7 +838,7 @@ func (v *Validator) validateCustomRules(message string, result *ValidationResult
 			if msg == "" {
 				msg = fmt.Sprintf("failed custom rule: %s", rule.Name)
 			}
-			v.addValidationError(result, "custom", msg, "")
+			v.addRuleError(result, "custom", rule.Name, msg, "", conventionalcommit.Span{})
 		}
 	}
 }
@@ -2,11 +2,16 @@ package validator
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/ticket"
 )
 
 func TestValidator_Validate(t *testing.T) {
@@ -95,6 +100,64 @@ func TestValidator_Validate(t *testing.T) {
 			valid:   true,
 			errors:  0,
 		},
+		{
+			name: "ticket excluded from subject length",
+			config: &config.Config{
+				Types:                   config.DefaultTypes(),
+				MaxSubjectLength:        30,
+				ExcludeTicketFromLength: true,
+			},
+			message: "feat(api): CGC-1234 add user endpoints",
+			valid:   true,
+			errors:  0,
+		},
+		{
+			name: "prefix-overlapping tickets excluded from subject length once",
+			config: &config.Config{
+				Types:                   config.DefaultTypes(),
+				MaxSubjectLength:        30,
+				ExcludeTicketFromLength: true,
+			},
+			// 49 characters less "CGC-12 " and " CGC-123" leaves 34; also
+			// counting CGC-12 inside CGC-123 would leave 27 and pass
+			message: "feat(api): CGC-12 add users endpoints now CGC-123",
+			valid:   false,
+			errors:  1,
+		},
+		{
+			name: "ticket counted in subject length",
+			config: &config.Config{
+				Types:            config.DefaultTypes(),
+				MaxSubjectLength: 30,
+			},
+			message: "feat(api): CGC-1234 add user endpoints",
+			valid:   false,
+			errors:  1,
+		},
+		{
+			name: "breaking change without description",
+			config: &config.Config{
+				Types:                      config.DefaultTypes(),
+				MaxSubjectLength:           72,
+				AllowBreakingChanges:       true,
+				RequireBreakingDescription: true,
+			},
+			message: "feat!: breaking change",
+			valid:   false,
+			errors:  1,
+		},
+		{
+			name: "breaking change with description",
+			config: &config.Config{
+				Types:                      config.DefaultTypes(),
+				MaxSubjectLength:           72,
+				AllowBreakingChanges:       true,
+				RequireBreakingDescription: true,
+			},
+			message: "feat!: breaking change\n\nBREAKING CHANGE: the v1 endpoints are removed",
+			valid:   true,
+			errors:  0,
+		},
 		{
 			name: "ignored pattern",
 			config: &config.Config{
@@ -569,6 +632,447 @@ func TestValidator_ContextCancellation(t *testing.T) {
 	}
 }
 
+// fakeTicketLookup serves JIRA issues from a map; unknown keys are missing
+// unless err is set
+type fakeTicketLookup struct {
+	issues map[string]*jira.Issue
+	err    error
+}
+
+func (f *fakeTicketLookup) GetIssue(_ context.Context, key string) (*jira.Issue, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if issue, ok := f.issues[key]; ok {
+		return issue, nil
+	}
+	return nil, jira.ErrIssueNotFound
+}
+
+func TestValidator_VerifyJiraTickets(t *testing.T) {
+	lookup := &fakeTicketLookup{issues: map[string]*jira.Issue{
+		"CGC-1": {Key: "CGC-1", Status: "In Progress", StatusCategory: "indeterminate", Project: "CGC"},
+		"CGC-2": {Key: "CGC-2", Status: "Done", StatusCategory: "done", Project: "CGC"},
+		"CGC-3": {Key: "CGC-3", Status: "To Do", StatusCategory: "new", Project: "OPS"},
+	}}
+
+	tests := []struct {
+		name     string
+		message  string
+		lookup   *fakeTicketLookup
+		valid    bool
+		warnings int
+	}{
+		{name: "open ticket", message: "feat: CGC-1 add login", lookup: lookup, valid: true},
+		{name: "missing ticket", message: "feat: CGC-9 add login", lookup: lookup, valid: false},
+		{name: "closed ticket", message: "feat: CGC-2 add login", lookup: lookup, valid: false},
+		{name: "moved to another project", message: "feat: CGC-3 add login", lookup: lookup, valid: false},
+		{name: "no ticket", message: "feat: add login", lookup: lookup, valid: true},
+		{
+			name:     "server unreachable fails open",
+			message:  "feat: CGC-1 add login",
+			lookup:   &fakeTicketLookup{err: errors.New("connection refused")},
+			valid:    true,
+			warnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.JIRAProjects = []string{"CGC"}
+			v, err := New(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			v.SetTicketLookup(tt.lookup)
+
+			result := v.Validate(context.Background(), tt.message)
+			if result.Valid != tt.valid {
+				t.Errorf("Validate() valid = %v, want %v (errors: %v)", result.Valid, tt.valid, result.Errors)
+			}
+			if len(result.Warnings) != tt.warnings {
+				t.Errorf("Validate() warnings = %v, want %d", result.Warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestValidator_JiraProjectPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "allowed project", message: "feat: CGC-12 add login"},
+		{name: "long project key", message: "feat: MOBILE-7 add login"},
+		{name: "no ticket", message: "feat: add login", want: "allowed projects: CGC, PLAT, MOBILE (e.g. CGC-123)"},
+		{name: "other project", message: "feat: OPS-3 add login", want: "JIRA project 'OPS' is not allowed; use a ticket from one of: CGC, PLAT, MOBILE"},
+		{name: "allowed and other project", message: "feat: PLAT-1 add login\n\nRelates to OPS-3", want: "JIRA project 'OPS' is not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.RequireJIRATicket = true
+			cfg.JIRAProjects = []string{"CGC", "PLAT", "MOBILE"}
+			v, err := New(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+
+			result := v.Validate(context.Background(), tt.message)
+			if tt.want == "" {
+				if !result.Valid {
+					t.Errorf("expected valid, got %v", result.Errors)
+				}
+				return
+			}
+			if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), tt.want) {
+				t.Errorf("expected one error containing %q, got %v", tt.want, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidator_TicketPlacement(t *testing.T) {
+	tests := []struct {
+		placement string
+		message   string
+		valid     bool
+	}{
+		{placement: "start", message: "feat: CGC-1 add login", valid: true},
+		{placement: "start", message: "feat(auth): [CGC-1] add login", valid: true},
+		{placement: "start", message: "feat: add login CGC-1", valid: false},
+		{placement: "end", message: "feat: add login (CGC-1)", valid: true},
+		{placement: "end", message: "feat: CGC-1 add login", valid: false},
+		{placement: "footer", message: "feat: add login\n\nRefs: CGC-1, CGC-2", valid: true},
+		{placement: "footer", message: "feat: CGC-1 add login", valid: false},
+		{placement: "footer", message: "feat: add login", valid: true},
+		{placement: "", message: "feat: add login CGC-1", valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.placement+" "+tt.message, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.TicketPlacement = tt.placement
+			v, err := New(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			if result := v.Validate(context.Background(), tt.message); result.Valid != tt.valid {
+				t.Errorf("Validate() valid = %v, want %v (errors: %v)", result.Valid, tt.valid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidator_Signoff(t *testing.T) {
+	cfg := config.Default()
+	cfg.RequireSignoff = true
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	for message, valid := range map[string]bool{
+		"feat: add login\n\nSigned-off-by: Jo Doe <jo@example.com>": true,
+		"feat: add login": false,
+		"feat: add login\n\nReviewed-by: Jo Doe <jo@example.com>": false,
+	} {
+		if result := v.Validate(context.Background(), message); result.Valid != valid {
+			t.Errorf("Validate(%q) valid = %v, want %v (errors: %v)", message, result.Valid, valid, result.Errors)
+		}
+	}
+}
+
+func TestValidator_ReviewTrailers(t *testing.T) {
+	cfg := config.Default()
+	cfg.ReviewTrailers = config.ReviewTrailersConfig{Validate: true, RequireReviewedBy: []string{"main", "release/*"}}
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	tests := []struct {
+		branch  string
+		message string
+		valid   bool
+	}{
+		{"", "feat: add login", true},
+		{"feature/login", "feat: add login", true},
+		{"main", "feat: add login", false},
+		{"refs/heads/release/1.2", "feat: add login\n\nAcked-by: Jo Doe <jo@example.com>", false},
+		{"main", "feat: add login\n\nReviewed-by: Jo Doe <jo@example.com>", true},
+		{"", "feat: add login\n\nTested-by: jo", false},
+		{"", "feat: add login\n\nreviewed-by: Jo Doe <jo@example.com>\nTested-by: CI Bot <ci@example.com>", true},
+	}
+	for _, tt := range tests {
+		v.SetBranch(tt.branch)
+		if result := v.Validate(context.Background(), tt.message); result.Valid != tt.valid {
+			t.Errorf("Validate(%q) on %q valid = %v, want %v (errors: %v)", tt.message, tt.branch, result.Valid, tt.valid, result.Errors)
+		}
+	}
+}
+
+func TestValidationResult_Unparsable(t *testing.T) {
+	v, err := New(config.Default())
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	for message, unparsable := range map[string]bool{
+		"added login":          true,
+		"badtype: add login":   false,
+		"feat: add login":      false,
+		"feat(api): add login": false,
+	} {
+		if got := v.Validate(context.Background(), message).Unparsable(); got != unparsable {
+			t.Errorf("Validate(%q).Unparsable() = %v, want %v", message, got, unparsable)
+		}
+	}
+}
+
+func TestValidator_ErrorPositions(t *testing.T) {
+	cfg := config.Default()
+	cfg.Scopes = []string{"api"}
+	cfg.MaxSubjectLength = 30
+	cfg.NoRedundantWords = true
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	type position struct{ line, column, end int }
+	tests := []struct {
+		message string
+		field   string
+		want    position
+	}{
+		{message: "wip: add login", field: "type", want: position{1, 1, 4}},
+		{message: "feat(web): add login", field: "scope", want: position{1, 6, 9}},
+		{message: "feat(api): add the new login page for admins", field: "subject", want: position{1, 31, 45}},
+		{message: "feat(api): add login added", field: "description", want: position{1, 22, 27}},
+		{message: "added login", field: "format", want: position{1, 1, 12}},
+		{message: "feat(api): add login", field: "", want: position{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			result := v.Validate(context.Background(), tt.message)
+			var got *ValidationError
+			for _, err := range result.Errors {
+				if validationErr, ok := err.(*ValidationError); ok && validationErr.Field == tt.field {
+					got = validationErr
+				}
+			}
+			if tt.field == "" {
+				if !result.Valid {
+					t.Errorf("unexpected errors: %v", result.Errors)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("no %s error in %v", tt.field, result.Errors)
+			}
+			if pos := (position{got.Line, got.Column, got.EndColumn}); pos != tt.want {
+				t.Errorf("%s error at %+v, want %+v", tt.field, pos, tt.want)
+			}
+		})
+	}
+
+	// Missing parts have no position
+	cfg.ScopeRequired = true
+	v, _ = New(cfg)
+	for _, err := range v.Validate(context.Background(), "feat: add login").Errors {
+		if validationErr := err.(*ValidationError); validationErr.Line != 0 {
+			t.Errorf("missing scope error has a position: %+v", validationErr)
+		}
+	}
+}
+
+func TestValidator_MultipleTickets(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		valid   bool
+	}{
+		{name: "primary with sub-tasks", message: "feat: CGC-1 add login\n\nRefs: CGC-2, CGC-3", valid: true},
+		{name: "repeated ticket counts once", message: "feat: CGC-1 add login\n\nRefs: CGC-1, CGC-2", valid: true},
+		{name: "too many tickets", message: "feat: CGC-1 add login\n\nRefs: CGC-2, CGC-3, CGC-4", valid: false},
+		{name: "no primary ticket", message: "feat: add login\n\nRefs: CGC-2", valid: false},
+		{name: "two primary tickets", message: "feat: CGC-1 CGC-2 add login", valid: false},
+		{name: "no tickets", message: "feat: add login", valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.MaxTicketsPerCommit = 3
+			cfg.RequirePrimaryTicket = true
+			v, err := New(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			if result := v.Validate(context.Background(), tt.message); result.Valid != tt.valid {
+				t.Errorf("Validate() valid = %v, want %v (errors: %v)", result.Valid, tt.valid, result.Errors)
+			}
+		})
+	}
+}
+
+// fakeVerifier treats GH-1 as the only existing ticket unless err is set
+type fakeVerifier struct{ err error }
+
+func (f fakeVerifier) Verify(_ context.Context, ref string) error {
+	if f.err != nil {
+		return f.err
+	}
+	if ref != "GH-1" {
+		return ticket.ErrNotFound
+	}
+	return nil
+}
+
+func TestValidator_TicketProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		message  string
+		verifier ticket.Verifier
+		valid    bool
+		warnings int
+	}{
+		{name: "github reference", provider: "github", message: "fix: resolve crash\n\nFixes #1", valid: true},
+		{name: "jira key is not a github issue", provider: "github", message: "fix: CGC-1 resolve crash", valid: false},
+		{name: "azure work item", provider: "azure", message: "fix: AB#12 resolve crash", valid: true},
+		{name: "missing azure work item", provider: "azure", message: "fix: resolve crash #12", valid: false},
+		{name: "linear issue", provider: "linear", message: "feat(api): ENG-123 add endpoint", valid: true},
+		{name: "verified issue", provider: "github", message: "fix: GH-1 resolve crash", verifier: fakeVerifier{}, valid: true},
+		{name: "unknown issue", provider: "github", message: "fix: GH-2 resolve crash", verifier: fakeVerifier{}, valid: false},
+		{
+			name:     "github unreachable fails open",
+			provider: "github",
+			message:  "fix: GH-2 resolve crash",
+			verifier: fakeVerifier{err: errors.New("timeout")},
+			valid:    true,
+			warnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.TicketProvider = tt.provider
+			cfg.RequireTicketRef = true
+			v, err := New(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			if tt.verifier != nil {
+				v.SetTicketVerifier(tt.verifier)
+			}
+
+			result := v.Validate(context.Background(), tt.message)
+			if result.Valid != tt.valid {
+				t.Errorf("Validate() valid = %v, want %v (errors: %v)", result.Valid, tt.valid, result.Errors)
+			}
+			if len(result.Warnings) != tt.warnings {
+				t.Errorf("Validate() warnings = %v, want %d", result.Warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestValidator_SubjectLengthOverlappingTickets(t *testing.T) {
+	for _, provider := range []string{"", "linear"} {
+		cfg := config.Default()
+		cfg.TicketProvider = provider
+		cfg.ExcludeTicketFromLength = true
+		v, err := New(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create validator: %v", err)
+		}
+		header := "feat: ENG-12 add users ENG-123"
+		if got, want := v.subjectLength(header), len(header)-len("ENG-12 ")-len(" ENG-123"); got != want {
+			t.Errorf("subjectLength(%q) with provider %q = %d, want %d", header, provider, got, want)
+		}
+	}
+}
+
+func TestValidator_ClosingKeyword(t *testing.T) {
+	tests := []struct {
+		name     string
+		disabled bool
+		message  string
+		valid    bool
+		value    string
+	}{
+		{name: "issue number", message: "fix: resolve crash\n\nFixes #12", valid: true},
+		{name: "issue in another repository", message: "fix: resolve crash\n\nCloses owner/repo#12", valid: true},
+		{name: "issue URL", message: "fix: resolve crash\n\nResolves https://github.com/owner/repo/issues/12", valid: true},
+		{name: "several references", message: "fix: resolve crash\n\nFixes: #12, owner/repo#13", valid: true},
+		{name: "malformed reference", message: "fix: resolve crash\n\nFixes issue-12", valid: false, value: "issue-12"},
+		{name: "keyword outside the last paragraph", message: "fix: resolve crash\n\nFixes #12\n\nThe parser now checks its input.", valid: false},
+		{name: "no keyword", message: "fix: resolve crash\n\nThe parser now checks its input.", valid: false},
+		{name: "no body", message: "fix: resolve crash", valid: false},
+		{name: "other types need no keyword", message: "feat: add login", valid: true},
+		{name: "disabled", disabled: true, message: "fix: resolve crash", valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.RequireClosingKeyword = !tt.disabled
+			v, err := New(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+
+			result := v.Validate(context.Background(), tt.message)
+			if result.Valid != tt.valid {
+				t.Errorf("Validate() valid = %v, want %v (errors: %v)", result.Valid, tt.valid, result.Errors)
+			}
+			for _, e := range result.ValidationErrors() {
+				if e.Field != "closing" {
+					t.Errorf("unexpected %s error: %v", e.Field, e)
+				} else if e.Value != tt.value {
+					t.Errorf("closing error value = %q, want %q", e.Value, tt.value)
+				}
+			}
+		})
+	}
+}
+
+func TestValidator_SmartCommits(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		message string
+		valid   bool
+	}{
+		{name: "well-formed", enabled: true, message: "feat: CGC-1 add login\n\nCGC-1 #time 2h #transition In Review", valid: true},
+		{name: "bad duration", enabled: true, message: "feat: CGC-1 add login\n\nCGC-1 #time later", valid: false},
+		{name: "transition not allowed", enabled: true, message: "feat: CGC-1 add login\n\nCGC-1 #transition Done", valid: false},
+		{name: "issue references are not commands", enabled: true, message: "fix: resolve crash\n\nFixes #42", valid: true},
+		{name: "disabled", enabled: false, message: "feat: CGC-1 add login\n\nCGC-1 #time later", valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.SmartCommits = config.SmartCommitConfig{Enabled: tt.enabled, AllowedTransitions: []string{"In Review"}}
+			v, err := New(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			if result := v.Validate(context.Background(), tt.message); result.Valid != tt.valid {
+				t.Errorf("Validate() valid = %v, want %v (errors: %v)", result.Valid, tt.valid, result.Errors)
+			}
+		})
+	}
+}
+
 func BenchmarkValidator_Validate(b *testing.B) {
 	cfg := config.Default()
 	v, err := New(cfg)
@@ -617,3 +1121,54 @@ func BenchmarkValidator_ValidateWithCustomRules(b *testing.B) {
 		}
 	}
 }
+
+func TestValidate_OversizedMessage(t *testing.T) {
+	v, err := New(config.Default())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	message := "feat: add login\n\n" + strings.Repeat("x", conventionalcommit.DefaultMaxMessageSize)
+	result := v.Validate(context.Background(), message)
+	errs := result.ValidationErrors()
+	if result.Valid || len(errs) != 1 || errs[0].Field != "format" {
+		t.Fatalf("Validate() errors = %v, want one format error", result.Errors)
+	}
+}
+
+func TestValidator_Presets(t *testing.T) {
+	tests := []struct {
+		preset  string
+		message string
+		valid   bool
+	}{
+		{preset: "angular", message: "feat(router): add lazy loading", valid: true},
+		{preset: "angular", message: "chore: bump dependencies", valid: false},
+		{preset: "angular", message: "feat!: drop the v1 api", valid: false},
+		{preset: "angular", message: "feat!: drop the v1 api\n\nBREAKING CHANGE: v1 clients must upgrade", valid: true},
+		{preset: "conventional", message: "chore: bump dependencies", valid: true},
+		{preset: "conventional", message: "Feat: add login", valid: false},
+		{preset: "jira-enterprise", message: "feat(auth): CGC-12 add login", valid: true},
+		{preset: "jira-enterprise", message: "feat: CGC-12 add login", valid: false},
+		{preset: "jira-enterprise", message: "feat(auth): add login", valid: false},
+		{preset: "jira-enterprise", message: "chore: CGC-12 bump dependencies", valid: true},
+		{preset: "gitmoji", message: "feat(api): ✨ add login", valid: true},
+		{preset: "gitmoji", message: "fix: :bug: handle empty tokens", valid: true},
+		{preset: "gitmoji", message: "fix: handle empty tokens", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.preset+"/"+tt.message, func(t *testing.T) {
+			cfg, err := config.Preset(tt.preset)
+			if err != nil {
+				t.Fatalf("Preset() error = %v", err)
+			}
+			v, err := New(cfg)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if result := v.Validate(context.Background(), tt.message); result.Valid != tt.valid {
+				t.Errorf("Validate() valid = %v, want %v (%v)", result.Valid, tt.valid, result)
+			}
+		})
+	}
+}
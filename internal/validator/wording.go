@@ -0,0 +1,135 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/conventionalcommit"
+)
+
+// typeWords are words other than the type and its inflections that repeat
+// a commit type when they start the description.
+var typeWords = map[string][]string{
+	"feat": {"feature"},
+	"docs": {"doc", "document", "documentation"},
+	"perf": {"performance"},
+	"test": {"tests", "testing"},
+}
+
+// validateRedundantWords flags descriptions that start by repeating the type
+// ("fix: fix the bug") or repeat a word in another form ("add new added
+// feature"), when no_redundant_words is set.
+func (v *Validator) validateRedundantWords(commit *conventionalcommit.Commit, result *ValidationResult) {
+	if !v.config.NoRedundantWords || commit.Type == "" {
+		return
+	}
+
+	words := strings.Fields(commit.Description)
+	if len(words) > 1 && repeatsType(commit.Type, words[0]) {
+		v.addValidationErrorAt(result, "description",
+			fmt.Sprintf("description repeats the %q type; start with what changed", commit.Type), words[0], wordSpan(commit, 0))
+	}
+	if i, j := redundantWord(words); j > 0 {
+		v.addValidationErrorAt(result, "description",
+			fmt.Sprintf("description repeats %q as %q", words[i], words[j]), commit.Description, wordSpan(commit, j))
+	}
+}
+
+// wordSpan locates the nth word of the description in the header.
+func wordSpan(commit *conventionalcommit.Commit, n int) conventionalcommit.Span {
+	span := commit.DescriptionSpan()
+	if span.IsZero() {
+		return span
+	}
+	column, start := span.Column, 0
+	for _, r := range commit.Description + " " {
+		switch {
+		case !unicode.IsSpace(r) && start == 0:
+			start = column
+		case unicode.IsSpace(r) && start > 0:
+			if n == 0 {
+				return conventionalcommit.Span{Line: span.Line, Column: start, EndColumn: column}
+			}
+			n, start = n-1, 0
+		}
+		column++
+	}
+	return conventionalcommit.Span{}
+}
+
+// fixRedundantWords strips a leading type word and later repetitions of a
+// word from description.
+func fixRedundantWords(typ, description string) string {
+	words := strings.Fields(description)
+	count := len(words)
+	if len(words) > 1 && repeatsType(typ, words[0]) {
+		words = words[1:]
+	}
+	for {
+		_, j := redundantWord(words)
+		if j < 0 {
+			break
+		}
+		words = append(words[:j], words[j+1:]...)
+	}
+	if len(words) == count {
+		return description
+	}
+	return strings.Join(words, " ")
+}
+
+// repeatsType reports whether word is the commit type, one of its
+// inflections ("fixes", "fixed") or a synonym such as "feature".
+func repeatsType(typ, word string) bool {
+	typ = strings.ToLower(typ)
+	word = normalizeWord(word)
+	if stem(word) == stem(typ) {
+		return true
+	}
+	for _, synonym := range typeWords[typ] {
+		if word == synonym {
+			return true
+		}
+	}
+	return false
+}
+
+// redundantWord returns the positions of the first word repeated in a
+// different form ("add" ... "added") or immediately ("the the"), or -1, -1.
+func redundantWord(words []string) (int, int) {
+	first := make(map[string]int)
+	for j, word := range words {
+		lower := normalizeWord(word)
+		if j > 0 && lower == normalizeWord(words[j-1]) {
+			return j - 1, j
+		}
+		s := stem(lower)
+		if len(s) < 3 {
+			continue
+		}
+		i, seen := first[s]
+		if !seen {
+			first[s] = j
+		} else if normalizeWord(words[i]) != lower {
+			return i, j
+		}
+	}
+	return -1, -1
+}
+
+// normalizeWord lowercases word and strips surrounding punctuation.
+func normalizeWord(word string) string {
+	return strings.ToLower(strings.Trim(word, ".,:;!"))
+}
+
+// stem strips common English inflections, so "added", "adds" and "add"
+// compare equal.
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s", "e"} {
+		if len(word) > len(suffix)+2 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+func TestValidate_RedundantWords(t *testing.T) {
+	cfg := config.Default()
+	cfg.NoRedundantWords = true
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		message string
+		errors  int
+		fixed   string
+	}{
+		{message: "fix: fix the login bug", errors: 1, fixed: "fix: the login bug"},
+		{message: "fix(auth): fixed token refresh", errors: 1, fixed: "fix(auth): token refresh"},
+		{message: "feat: add new added feature", errors: 1, fixed: "feat: add new feature"},
+		{message: "docs: update the the readme", errors: 1, fixed: "docs: update the readme"},
+		{message: "feat: feature flags for search", errors: 1, fixed: "feat: flags for search"},
+		{message: "fix: handle empty config files", errors: 0, fixed: "fix: handle empty config files"},
+		{message: "test: add tests for parser", errors: 0, fixed: "test: add tests for parser"},
+	}
+	for _, tt := range tests {
+		if result := v.Validate(context.Background(), tt.message); len(result.Errors) != tt.errors {
+			t.Errorf("Validate(%q) = %v, want %d errors", tt.message, result.Errors, tt.errors)
+		}
+		if fixed, _ := v.Fix(tt.message); fixed != tt.fixed {
+			t.Errorf("Fix(%q) = %q, want %q", tt.message, fixed, tt.fixed)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Package webhook posts commit policy events to an HTTP webhook.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+const (
+	// EventBlocked is sent when the commit-msg hook rejects a commit.
+	EventBlocked = "blocked"
+	// EventBypass is sent when a commit is made with hooks skipped (--no-verify).
+	EventBypass = "bypass"
+
+	requestTimeout = 5 * time.Second
+)
+
+// Payload is the JSON body posted to the webhook. Text holds a one-line
+// summary so Slack-compatible incoming webhooks display it as is.
+type Payload struct {
+	Text       string   `json:"text"`
+	Event      string   `json:"event"`
+	Repo       string   `json:"repo"`
+	Branch     string   `json:"branch"`
+	Author     string   `json:"author"`
+	Message    string   `json:"message"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+// Notifier posts events to the configured webhook.
+type Notifier struct {
+	url        string
+	events     []string
+	httpClient *http.Client
+	// gitContext returns the repository, branch and author (replaced in tests).
+	gitContext func() (repo, branch, author string)
+}
+
+// New creates a notifier for the webhook config, or returns nil when no URL
+// is configured. Without events, every event is sent.
+func New(cfg config.WebhookConfig) *Notifier {
+	if cfg.URL == "" {
+		return nil
+	}
+	events := cfg.Events
+	if len(events) == 0 {
+		events = []string{EventBlocked, EventBypass}
+	}
+	return &Notifier{
+		url:        cfg.URL,
+		events:     events,
+		httpClient: &http.Client{Timeout: requestTimeout},
+		gitContext: currentGitContext,
+	}
+}
+
+// Enabled reports whether the event is sent. A nil notifier sends nothing.
+func (n *Notifier) Enabled(event string) bool {
+	if n == nil {
+		return false
+	}
+	for _, e := range n.events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify posts the event for a commit message and its violations. Disabled
+// events are ignored; callers should treat errors as warnings so an
+// unreachable webhook never blocks a commit.
+func (n *Notifier) Notify(ctx context.Context, event, message string, violations []string) error {
+	if !n.Enabled(event) {
+		return nil
+	}
+
+	repo, branch, author := n.gitContext()
+	payload := Payload{
+		Event:      event,
+		Repo:       repo,
+		Branch:     branch,
+		Author:     author,
+		Message:    strings.TrimSpace(message),
+		Violations: violations,
+	}
+	payload.Text = summary(payload)
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("posting webhook: unexpected HTTP status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// OnBypass returns a callback reporting commits made with hooks skipped, for
+// ccgen.Options.OnBypass, or nil when bypass events are not sent. Failures are
+// written to warn.
+func OnBypass(cfg config.WebhookConfig, warn io.Writer) func(message string) {
+	notifier := New(cfg)
+	if !notifier.Enabled(EventBypass) {
+		return nil
+	}
+	return func(message string) {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+		if err := notifier.Notify(ctx, EventBypass, message, nil); err != nil {
+			fmt.Fprintf(warn, "⚠️  Webhook notification failed: %v\n", err)
+		}
+	}
+}
+
+// summary describes the event in one line for chat webhooks.
+func summary(p Payload) string {
+	subject, _, _ := strings.Cut(p.Message, "\n")
+	where := p.Repo
+	if p.Branch != "" {
+		where += "@" + p.Branch
+	}
+
+	switch p.Event {
+	case EventBlocked:
+		return fmt.Sprintf("🚫 Commit blocked in %s for %s: %q (%d violation(s))", where, p.Author, subject, len(p.Violations))
+	case EventBypass:
+		return fmt.Sprintf("⚠️ Commit hooks bypassed in %s by %s: %q", where, p.Author, subject)
+	default:
+		return fmt.Sprintf("%s in %s by %s: %q", p.Event, where, p.Author, subject)
+	}
+}
+
+// currentGitContext reads the repository (origin URL, or the top-level
+// directory name), branch and author from git. Missing values stay empty.
+func currentGitContext() (repo, branch, author string) {
+	repo = gitOutput("config", "--get", "remote.origin.url")
+	if repo == "" {
+		if top := gitOutput("rev-parse", "--show-toplevel"); top != "" {
+			repo = filepath.Base(top)
+		}
+	}
+	// symbolic-ref also works before the first commit, unlike rev-parse HEAD
+	branch = gitOutput("symbolic-ref", "--short", "HEAD")
+
+	// GIT_AUTHOR_IDENT is "Name <email> timestamp zone"
+	ident := gitOutput("var", "GIT_AUTHOR_IDENT")
+	if end := strings.LastIndex(ident, ">"); end >= 0 {
+		ident = ident[:end+1]
+	}
+	return repo, branch, ident
+}
+
+func gitOutput(args ...string) string {
+	output, err := exec.Command("git", args...).Output() // #nosec G204 - fixed git arguments
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+func TestNotifier_Notify(t *testing.T) {
+	var received []Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, p)
+	}))
+	defer server.Close()
+
+	n := New(config.WebhookConfig{URL: server.URL, Events: []string{EventBlocked}})
+	n.gitContext = func() (string, string, string) { return "acme/app", "main", "Dev <dev@example.com>" }
+
+	err := n.Notify(context.Background(), EventBlocked, "bad message\n\nbody", []string{"invalid format"})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if err := n.Notify(context.Background(), EventBypass, "feat: skip", nil); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if len(received) != 1 {
+		t.Fatalf("expected only the enabled event to be posted, got %d", len(received))
+	}
+	p := received[0]
+	if p.Event != EventBlocked || p.Repo != "acme/app" || p.Branch != "main" || p.Author != "Dev <dev@example.com>" {
+		t.Errorf("unexpected payload %+v", p)
+	}
+	if p.Message != "bad message\n\nbody" || len(p.Violations) != 1 {
+		t.Errorf("unexpected message or violations %+v", p)
+	}
+	if !strings.Contains(p.Text, "acme/app@main") || !strings.Contains(p.Text, `"bad message"`) {
+		t.Errorf("unexpected Slack text %q", p.Text)
+	}
+}
+
+func TestNotifier_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := New(config.WebhookConfig{URL: server.URL})
+	n.gitContext = func() (string, string, string) { return "", "", "" }
+	if err := n.Notify(context.Background(), EventBypass, "feat: x", nil); err == nil {
+		t.Error("expected an error for a failing webhook")
+	}
+
+	// No URL means no notifier, which sends nothing
+	disabled := New(config.WebhookConfig{})
+	if disabled != nil || disabled.Enabled(EventBlocked) {
+		t.Error("expected a nil notifier without a URL")
+	}
+	if err := disabled.Notify(context.Background(), EventBlocked, "x", nil); err != nil {
+		t.Errorf("nil notifier Notify() error = %v", err)
+	}
+}
+
+func TestOnBypass(t *testing.T) {
+	if OnBypass(config.WebhookConfig{URL: "https://example.com/hook", Events: []string{EventBlocked}}, io.Discard) != nil {
+		t.Error("expected no callback when bypass events are disabled")
+	}
+
+	var warnings strings.Builder
+	onBypass := OnBypass(config.WebhookConfig{URL: "http://127.0.0.1:1/hook"}, &warnings)
+	if onBypass == nil {
+		t.Fatal("expected a callback for bypass events")
+	}
+	onBypass("feat: skip hooks")
+	if !strings.Contains(warnings.String(), "Webhook notification failed") {
+		t.Errorf("expected an unreachable webhook to warn, got %q", warnings.String())
+	}
+}
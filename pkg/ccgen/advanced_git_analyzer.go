@@ -2,10 +2,11 @@
 package ccgen
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
+	"path/filepath"
 	"regexp"
-	"strconv"
+	"sort"
 	"strings"
 )
 
@@ -37,6 +38,11 @@ type GitAnalysisResult struct {
 	// Historical context
 	RecentCommits  []CommitInfo
 	CommitPatterns *CommitPatterns
+
+	// Size budget outcome
+	Degraded       bool     // too many files; diff content was not read
+	DiffTruncated  bool     // total diff budget exhausted
+	TruncatedFiles []string // files whose diff was cut at the per-file budget
 }
 
 // FileStatistics contains detailed stats for each file
@@ -44,7 +50,12 @@ type FileStatistics struct {
 	Filename   string
 	Additions  int
 	Deletions  int
-	ChangeType string // A/M/D
+	ChangeType string // A/M/D/R/C
+	OldPath    string // source path for renames (R) and copies (C)
+	Kind       string // empty for hand-written files, otherwise a FileKind* value
+	// ExportIgnored marks export-ignore files, which `git archive` leaves
+	// out of releases, such as CI configuration and test fixtures
+	ExportIgnored bool
 }
 
 // NumStat contains precise numerical statistics from git diff --numstat
@@ -70,7 +81,7 @@ type CommitPatterns struct {
 }
 
 // performAdvancedGitAnalysis implements the comprehensive algorithm
-func (g *Generator) performAdvancedGitAnalysis() (*GitAnalysisResult, error) {
+func (g *Generator) performAdvancedGitAnalysis(ctx context.Context) (*GitAnalysisResult, error) {
 	result := &GitAnalysisResult{
 		FileStats:         make(map[string]*FileStatistics),
 		ChangeTypes:       make(map[string]string),
@@ -80,278 +91,147 @@ func (g *Generator) performAdvancedGitAnalysis() (*GitAnalysisResult, error) {
 		ModifiedFunctions: make([]string, 0),
 	}
 
-	// Step 1: Get change types (A/M/D) - fundamental file operations
-	if err := g.getChangeTypes(result); err != nil {
-		return nil, fmt.Errorf("getting change types: %w", err)
-	}
-
-	// Step 2: Get file operation summaries (create/delete/rename details)
-	if err := g.getFileSummaries(result); err != nil {
-		return nil, fmt.Errorf("getting file summaries: %w", err)
-	}
-
-	// Step 3: Get precise numerical statistics (exact line counts)
-	if err := g.getNumStats(result); err != nil {
-		return nil, fmt.Errorf("getting numerical statistics: %w", err)
-	}
-
-	// Step 4: Get file statistics (visual representation for compatibility)
-	if err := g.getFileStatistics(result); err != nil {
-		return nil, fmt.Errorf("getting file statistics: %w", err)
-	}
-
-	// Step 5: Get directory distribution statistics
-	if err := g.getDirStats(result); err != nil {
-		return nil, fmt.Errorf("getting directory statistics: %w", err)
+	// Step 1: Get change types, precise line counts and modes in a single pass
+	g.progress.Start("Running `git diff --cached --raw --numstat -z`")
+	files, err := g.backend.StagedFiles(ctx)
+	g.progress.Done(err)
+	if err != nil {
+		return nil, fmt.Errorf("getting staged files: %w", err)
 	}
+	g.applyStagedFiles(files, result)
+	g.classifyStagedFiles(ctx, files, result)
 
-	// Step 6: Get staged diff (maintain compatibility)
-	if err := g.getStagedDiffContent(result); err != nil {
-		return nil, fmt.Errorf("getting staged diff: %w", err)
-	}
+	// Large changesets skip diff content entirely and use statistics only
+	limits := g.limits()
+	if result.TotalFiles > limits.MaxFiles {
+		result.Degraded = true
+		fmt.Fprintf(g.out, "Skipping diff content: %d files exceeds budget of %d\n", result.TotalFiles, limits.MaxFiles)
+	} else {
+		// Step 2: Stream staged diff used for content, word and function analysis
+		if err := g.readStagedDiff(ctx, result, limits); err != nil {
+			return nil, err
+		}
 
-	// Step 7: Get word-level diff for granular analysis
-	if err := g.getWordDiff(result); err != nil {
-		return nil, fmt.Errorf("getting word diff: %w", err)
+		// Step 3: Derive word-level changes and modified function contexts from
+		// the hand-written part of the diff
+		markGeneratedFromDiff(result.StagedDiff, result.FileStats)
+		content := handWrittenDiff(result.StagedDiff, result.FileStats)
+		result.WordDiffContent = buildWordDiff(content)
+		result.ModifiedFunctions = extractFunctionContexts(content, 10)
 	}
 
-	// Step 8: Extract modified function contexts (specific change locations)
-	if err := g.extractFunctionContexts(result); err != nil {
-		return nil, fmt.Errorf("extracting function contexts: %w", err)
+	// Step 4: Analyze recent commit patterns
+	if err := g.analyzeRecentCommitPatterns(ctx, result); err != nil {
+		return nil, err
 	}
 
-	// Step 9: Analyze recent commit patterns
-	g.analyzeRecentCommitPatterns(result)
-
 	return result, nil
 }
 
-// getFileStatistics implements: git diff --stat HEAD~1 HEAD (or --staged if no HEAD~1)
-func (g *Generator) getFileStatistics(result *GitAnalysisResult) error {
-	if g.options.Verbose {
-		fmt.Printf("Running `git diff --stat`")
+// readStagedDiff streams the staged diff into result within the given limits
+func (g *Generator) readStagedDiff(ctx context.Context, result *GitAnalysisResult, limits DiffLimits) error {
+	g.progress.Start("Running `git diff --cached`")
+	stream, err := g.backend.StagedDiff(ctx)
+	if err != nil {
+		g.progress.Done(err)
+		return fmt.Errorf("getting staged diff: %w", err)
 	}
+	defer stream.Close()
 
-	// Try staged first (for initial commits), fallback to HEAD~1 comparison
-	var cmd *exec.Cmd
-	if g.hasPreviousCommits() {
-		cmd = exec.Command("git", "diff", "--stat", "HEAD~1", "HEAD")
-	} else {
-		cmd = exec.Command("git", "diff", "--stat", "--staged")
+	progress := func(files int) {
+		g.progress.Update(fmt.Sprintf("(%d/%d files)", files, result.TotalFiles))
 	}
 
-	output, err := cmd.Output()
+	diff, err := readLimitedDiff(stream, limits, progress)
+	g.progress.Done(err)
 	if err != nil {
-		// Fallback to staged if HEAD~1 fails
-		cmd = exec.Command("git", "diff", "--stat", "--staged")
-		output, err = cmd.Output()
-		if err != nil {
-			fmt.Println(" ❌")
-			return fmt.Errorf("failed to get diff stat: %w", err)
-		}
+		return fmt.Errorf("getting staged diff: %w", err)
 	}
-	fmt.Println(" ✅")
-
-	// Parse diff --stat output
-	g.parseStatOutput(string(output), result)
-
-	// Cross-reference with NumStats for more accurate data
-	g.enhanceWithNumStats(result)
 
+	result.StagedDiff = diff.Content
+	result.DiffTruncated = diff.Truncated
+	result.TruncatedFiles = diff.TruncatedFiles
 	return nil
 }
 
-// getChangeTypes implements: git diff --name-status HEAD~1 HEAD
-func (g *Generator) getChangeTypes(result *GitAnalysisResult) error {
-	fmt.Printf("Running `git diff --name-status`")
-
-	var cmd *exec.Cmd
-	if g.hasPreviousCommits() {
-		cmd = exec.Command("git", "diff", "--name-status", "HEAD~1", "HEAD")
-	} else {
-		cmd = exec.Command("git", "diff", "--name-status", "--staged")
-	}
-
-	output, err := cmd.Output()
-	if err != nil {
-		// Fallback to staged
-		cmd = exec.Command("git", "diff", "--name-status", "--staged")
-		output, err = cmd.Output()
-		if err != nil {
-			fmt.Println(" ❌")
-			return fmt.Errorf("failed to get name-status: %w", err)
-		}
-	}
-	fmt.Println(" ✅")
-
-	// Parse name-status output (format: "M\tfilename" or "A\tfilename")
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line == "" {
-			continue
+// applyStagedFiles populates per-file, directory and summary statistics
+func (g *Generator) applyStagedFiles(files []StagedFile, result *GitAnalysisResult) {
+	dirCounts := make(map[string]int)
+
+	for _, file := range files {
+		result.ChangeTypes[file.Path] = file.Status
+		result.FileStats[file.Path] = &FileStatistics{
+			Filename:   file.Path,
+			Additions:  file.Additions,
+			Deletions:  file.Deletions,
+			ChangeType: file.Status,
+			OldPath:    file.OldPath,
 		}
-		parts := strings.Split(line, "\t")
-		if len(parts) >= 2 {
-			changeType := parts[0]
-			filename := parts[1]
-			result.ChangeTypes[filename] = changeType
-
-			// Update FileStatistics with change type
-			if stat, exists := result.FileStats[filename]; exists {
-				stat.ChangeType = changeType
-			} else {
-				result.FileStats[filename] = &FileStatistics{
-					Filename:   filename,
-					ChangeType: changeType,
-				}
-			}
+		result.NumStats[file.Path] = &NumStat{
+			Additions: file.Additions,
+			Deletions: file.Deletions,
+			Filename:  file.Path,
 		}
-	}
 
-	return nil
-}
+		result.TotalFiles++
+		result.TotalAdditions += file.Additions
+		result.TotalDeletions += file.Deletions
 
-// getWordDiff implements: git diff HEAD~1 HEAD --word-diff
-func (g *Generator) getWordDiff(result *GitAnalysisResult) error {
-	fmt.Printf("Running `git diff --word-diff`")
-
-	var cmd *exec.Cmd
-	if g.hasPreviousCommits() {
-		cmd = exec.Command("git", "diff", "HEAD~1", "HEAD", "--word-diff")
-	} else {
-		cmd = exec.Command("git", "diff", "--staged", "--word-diff")
-	}
+		if summary := summarizeStagedFile(file); summary != "" {
+			result.FileSummaries = append(result.FileSummaries, summary)
+		}
 
-	output, err := cmd.Output()
-	if err != nil {
-		// Fallback to staged
-		cmd = exec.Command("git", "diff", "--staged", "--word-diff")
-		output, err = cmd.Output()
-		if err != nil {
-			fmt.Println(" ❌")
-			return fmt.Errorf("failed to get word diff: %w", err)
+		if dir := filepath.ToSlash(filepath.Dir(file.Path)); dir != "." {
+			dirCounts[dir+"/"]++
 		}
 	}
-	fmt.Println(" ✅")
 
-	result.WordDiffContent = string(output)
-	return nil
+	// Equivalent of --dirstat=files,0: share of changed files per directory
+	for dir, count := range dirCounts {
+		result.DirStats[dir] = float64(count) * 100 / float64(result.TotalFiles)
+	}
 }
 
-// getStagedDiffContent maintains compatibility with existing analyzer
-func (g *Generator) getStagedDiffContent(result *GitAnalysisResult) error {
-	fmt.Printf("Running `git diff --staged`")
-
-	cmd := exec.Command("git", "diff", "--staged")
-	output, err := cmd.Output()
-	if err != nil {
-		fmt.Println(" ❌")
-		return fmt.Errorf("failed to get staged diff: %w", err)
+// summarizeStagedFile mirrors a `git diff --summary` line for a staged file
+func summarizeStagedFile(file StagedFile) string {
+	switch {
+	case file.Status == "A":
+		return fmt.Sprintf("create mode %s %s", file.NewMode, file.Path)
+	case file.Status == "D":
+		return fmt.Sprintf("delete mode %s %s", file.OldMode, file.Path)
+	case file.Status == "R":
+		return fmt.Sprintf("rename %s => %s (%d%%)", file.OldPath, file.Path, file.Similarity)
+	case file.Status == "C":
+		return fmt.Sprintf("copy %s => %s (%d%%)", file.OldPath, file.Path, file.Similarity)
+	case file.OldMode != "" && file.NewMode != "" && file.OldMode != file.NewMode:
+		return fmt.Sprintf("mode change %s => %s %s", file.OldMode, file.NewMode, file.Path)
+	default:
+		return ""
 	}
-	fmt.Println(" ✅")
-
-	result.StagedDiff = string(output)
-	return nil
 }
 
-// analyzeRecentCommitPatterns implements: git log --oneline -10
-func (g *Generator) analyzeRecentCommitPatterns(result *GitAnalysisResult) {
-	fmt.Printf("Running `git log --oneline -10`")
+// analyzeRecentCommitPatterns implements: git log --oneline -10. Only a
+// cancelled ctx fails it.
+func (g *Generator) analyzeRecentCommitPatterns(ctx context.Context, result *GitAnalysisResult) error {
+	g.progress.Start("Running `git log --oneline -10`")
 
-	cmd := exec.Command("git", "log", "--oneline", "-10")
-	output, err := cmd.Output()
+	commits, err := g.backend.RecentCommits(ctx, 10)
+	g.progress.Done(err)
 	if err != nil {
-		fmt.Println(" ❌")
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		// Don't fail if no commits exist yet
 		result.CommitPatterns = &CommitPatterns{
 			CommonTypes:  make(map[string]int),
 			CommonScopes: make(map[string]int),
 		}
-		return
+		return nil
 	}
-	fmt.Println(" ✅")
 
-	// Parse recent commits
-	result.RecentCommits = g.parseRecentCommits(string(output))
+	result.RecentCommits = commits
 	result.CommitPatterns = g.analyzeCommitPatterns(result.RecentCommits)
-}
-
-// parseStatOutput parses git diff --stat output
-func (g *Generator) parseStatOutput(output string, result *GitAnalysisResult) {
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-
-	for _, line := range lines {
-		if strings.Contains(line, "|") {
-			// Parse line: " filename.go | 23 +++++++++++----------"
-			parts := strings.Split(line, "|")
-			if len(parts) < 2 {
-				continue
-			}
-
-			filename := strings.TrimSpace(parts[0])
-			statsStr := strings.TrimSpace(parts[1])
-
-			// Extract numbers and symbols
-			additions, deletions := g.parseStatsLine(statsStr)
-
-			result.FileStats[filename] = &FileStatistics{
-				Filename:  filename,
-				Additions: additions,
-				Deletions: deletions,
-			}
-
-			result.TotalAdditions += additions
-			result.TotalDeletions += deletions
-			result.TotalFiles++
-		}
-	}
-}
-
-// parseStatsLine extracts addition/deletion counts from stats line
-func (g *Generator) parseStatsLine(statsStr string) (additions, deletions int) {
-	// Extract number at beginning (total changes)
-	parts := strings.Fields(statsStr)
-	if len(parts) == 0 {
-		return 0, 0
-	}
-
-	totalChanges, err := strconv.Atoi(parts[0])
-	if err != nil {
-		return 0, 0 // Return zeros if parsing fails
-	}
-
-	// Count + and - symbols
-	plusCount := strings.Count(statsStr, "+")
-	minusCount := strings.Count(statsStr, "-")
-
-	if plusCount+minusCount > 0 {
-		// Proportional distribution based on symbols
-		additions = (totalChanges * plusCount) / (plusCount + minusCount)
-		deletions = totalChanges - additions
-	}
-
-	return additions, deletions
-}
-
-// parseRecentCommits parses git log --oneline output
-func (g *Generator) parseRecentCommits(output string) []CommitInfo {
-	var commits []CommitInfo
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-		parts := strings.SplitN(line, " ", 2)
-		if len(parts) >= 2 {
-			commits = append(commits, CommitInfo{
-				Hash:    parts[0],
-				Message: parts[1],
-			})
-		}
-	}
-
-	return commits
+	return nil
 }
 
 // analyzeCommitPatterns analyzes patterns from recent commits
@@ -405,24 +285,23 @@ func (g *Generator) analyzeCommitPatterns(commits []CommitInfo) *CommitPatterns
 	return patterns
 }
 
-// hasPreviousCommits checks if repository has any commits
-func (g *Generator) hasPreviousCommits() bool {
-	cmd := exec.Command("git", "rev-parse", "HEAD~1")
-	return cmd.Run() == nil
-}
-
 // getAdvancedChangeAnalyses converts GitAnalysisResult to IntelligentChangeAnalysis
 func (g *Generator) getAdvancedChangeAnalyses(analysis *GitAnalysisResult) []*IntelligentChangeAnalysis {
 	var analyses []*IntelligentChangeAnalysis
 
-	for filename, stats := range analysis.FileStats {
+	for _, filename := range sortedKeys(analysis.FileStats) {
+		stats := analysis.FileStats[filename]
+		// Binary, lockfile, vendored and generated files are summarized separately
+		if stats.Kind != "" {
+			continue
+		}
 		changeAnalysis := g.createAdvancedChangeAnalysis(filename, stats, analysis)
 		if changeAnalysis != nil {
 			analyses = append(analyses, changeAnalysis)
 		}
 	}
 
-	return analyses
+	return append(analyses, g.getAuxiliaryChangeAnalyses(analysis)...)
 }
 
 // createAdvancedChangeAnalysis creates detailed analysis using comprehensive data
@@ -450,6 +329,11 @@ func (g *Generator) createAdvancedChangeAnalysis(filename string, stats *FileSta
 	// Priority based on change magnitude and type
 	analysis.Priority = g.calculateAdvancedPriority(analysis.ChangeType, stats)
 
+	// Files left out of releases only lead when nothing shipped changed
+	if stats.ExportIgnored && hasShippedFiles(gitAnalysis) {
+		analysis.Priority += downWeight
+	}
+
 	return analysis
 }
 
@@ -458,8 +342,10 @@ func (g *Generator) determineAdvancedChangeType(stats *FileStatistics, gitAnalys
 	switch stats.ChangeType {
 	case "A":
 		return "feat"
-	case "D":
+	case "D", "R":
 		return "refactor"
+	case "C":
+		return "feat"
 	case "M":
 		// For modifications, use ratio analysis
 		total := stats.Additions + stats.Deletions
@@ -522,6 +408,10 @@ func (g *Generator) generateAdvancedDescription(filename string, stats *FileStat
 		return fmt.Sprintf("add %s with %d lines", baseName, stats.Additions)
 	case "D":
 		return fmt.Sprintf("remove %s (%d lines deleted)", baseName, stats.Deletions)
+	case "R":
+		return describeRename(stats.OldPath, filename)
+	case "C":
+		return fmt.Sprintf("add %s based on %s", baseName, g.extractFileName(stats.OldPath))
 	case "M":
 		if stats.Additions > stats.Deletions*2 {
 			return fmt.Sprintf("expand %s functionality (+%d lines)", baseName, stats.Additions)
@@ -535,6 +425,14 @@ func (g *Generator) generateAdvancedDescription(filename string, stats *FileStat
 	}
 }
 
+// describeRename renders "rename X to Y" within a directory and "move X to Y" across directories
+func describeRename(oldPath, newPath string) string {
+	if filepath.Dir(oldPath) == filepath.Dir(newPath) {
+		return fmt.Sprintf("rename %s to %s", filepath.Base(oldPath), filepath.Base(newPath))
+	}
+	return fmt.Sprintf("move %s to %s", oldPath, newPath)
+}
+
 // detectContextFromWordDiff analyzes word-level changes for context
 func (g *Generator) detectContextFromWordDiff(wordDiff string) string {
 	contexts := []string{}
@@ -578,191 +476,58 @@ func (g *Generator) calculateAdvancedPriority(changeType string, stats *FileStat
 	return basePriority
 }
 
-// enhanceWithNumStats improves FileStatistics accuracy using NumStat data
-func (g *Generator) enhanceWithNumStats(result *GitAnalysisResult) {
-	for filename, numStat := range result.NumStats {
-		if fileStat, exists := result.FileStats[filename]; exists {
-			// Use precise NumStat data instead of approximated --stat parsing
-			fileStat.Additions = numStat.Additions
-			fileStat.Deletions = numStat.Deletions
-		}
-	}
-}
-
-// getDirStats implements: git diff --cached --dirstat=files,0
-func (g *Generator) getDirStats(result *GitAnalysisResult) error {
-	fmt.Printf("Running `git diff --cached --dirstat=files,0`")
+// buildWordDiff renders added and removed words from a unified diff in
+// `git diff --word-diff` notation ({+word+} / [-word-]) without a second git call
+func buildWordDiff(diff string) string {
+	var sb strings.Builder
 
-	var cmd *exec.Cmd
-	if g.hasPreviousCommits() {
-		cmd = exec.Command("git", "diff", "HEAD~1", "HEAD", "--dirstat=files,0")
-	} else {
-		cmd = exec.Command("git", "diff", "--cached", "--dirstat=files,0")
-	}
-
-	output, err := cmd.Output()
-	if err != nil {
-		// Fallback to cached
-		cmd = exec.Command("git", "diff", "--cached", "--dirstat=files,0")
-		output, err = cmd.Output()
-		if err != nil {
-			fmt.Println(" ❌")
-			return fmt.Errorf("failed to get dir stats: %w", err)
-		}
-	}
-	fmt.Println(" ✅")
-
-	// Parse dirstat output: " 28.5% pkg/semantic/plugins/"
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---"):
 			continue
-		}
-		parts := strings.Fields(line)
-		if len(parts) >= 2 {
-			percentStr := strings.TrimSuffix(parts[0], "%")
-			if percent, err := strconv.ParseFloat(percentStr, 64); err == nil {
-				directory := parts[1]
-				result.DirStats[directory] = percent
+		case strings.HasPrefix(line, "+"):
+			for _, word := range strings.Fields(line[1:]) {
+				sb.WriteString("{+" + word + "+} ")
 			}
-		}
-	}
-
-	return nil
-}
-
-// getNumStats implements: git diff --cached --numstat
-func (g *Generator) getNumStats(result *GitAnalysisResult) error {
-	fmt.Printf("Running `git diff --cached --numstat`")
-
-	var cmd *exec.Cmd
-	if g.hasPreviousCommits() {
-		cmd = exec.Command("git", "diff", "HEAD~1", "HEAD", "--numstat")
-	} else {
-		cmd = exec.Command("git", "diff", "--cached", "--numstat")
-	}
-
-	output, err := cmd.Output()
-	if err != nil {
-		// Fallback to cached
-		cmd = exec.Command("git", "diff", "--cached", "--numstat")
-		output, err = cmd.Output()
-		if err != nil {
-			fmt.Println(" ❌")
-			return fmt.Errorf("failed to get numstat: %w", err)
-		}
-	}
-	fmt.Println(" ✅")
-
-	// Parse numstat output: "78	78	pkg/ccgen/advanced_git_analyzer.go"
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		parts := strings.Fields(line)
-		if len(parts) >= 3 {
-			additions, err1 := strconv.Atoi(parts[0])
-			deletions, err2 := strconv.Atoi(parts[1])
-			filename := parts[2]
-
-			if err1 == nil && err2 == nil {
-				result.NumStats[filename] = &NumStat{
-					Additions: additions,
-					Deletions: deletions,
-					Filename:  filename,
-				}
+		case strings.HasPrefix(line, "-"):
+			for _, word := range strings.Fields(line[1:]) {
+				sb.WriteString("[-" + word + "-] ")
 			}
+		default:
+			sb.WriteString(line)
 		}
+		sb.WriteString("\n")
 	}
 
-	return nil
+	return sb.String()
 }
 
-// getFileSummaries implements: git diff --cached --summary
-func (g *Generator) getFileSummaries(result *GitAnalysisResult) error {
-	fmt.Printf("Running `git diff --cached --summary`")
-
-	var cmd *exec.Cmd
-	if g.hasPreviousCommits() {
-		cmd = exec.Command("git", "diff", "HEAD~1", "HEAD", "--summary")
-	} else {
-		cmd = exec.Command("git", "diff", "--cached", "--summary")
-	}
-
-	output, err := cmd.Output()
-	if err != nil {
-		// Fallback to cached
-		cmd = exec.Command("git", "diff", "--cached", "--summary")
-		output, err = cmd.Output()
-		if err != nil {
-			fmt.Println(" ❌")
-			return fmt.Errorf("failed to get summary: %w", err)
-		}
-	}
-	fmt.Println(" ✅")
-
-	// Parse summary output: " create mode 100644 pkg/semantic/plugins/terraform_changeset_analyzer.go"
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			result.FileSummaries = append(result.FileSummaries, line)
-		}
-	}
-
-	return nil
-}
-
-// extractFunctionContexts implements: git diff --cached --function-context --unified=0 | sed -n 's/^@@.* \(.*\) @@/\1/p' | sort -u | head -n 10
-func (g *Generator) extractFunctionContexts(result *GitAnalysisResult) error {
-	fmt.Printf("Running `git diff --cached --function-context --unified=0`")
-
-	var cmd *exec.Cmd
-	if g.hasPreviousCommits() {
-		cmd = exec.Command("git", "diff", "HEAD~1", "HEAD", "--function-context", "--unified=0")
-	} else {
-		cmd = exec.Command("git", "diff", "--cached", "--function-context", "--unified=0")
-	}
-
-	output, err := cmd.Output()
-	if err != nil {
-		// Fallback to cached
-		cmd = exec.Command("git", "diff", "--cached", "--function-context", "--unified=0")
-		output, err = cmd.Output()
-		if err != nil {
-			fmt.Println(" ❌")
-			return fmt.Errorf("failed to get function context: %w", err)
-		}
-	}
-	fmt.Println(" ✅")
-
-	// Extract function names from @@ lines using regex
-	lines := strings.Split(string(output), "\n")
+// extractFunctionContexts implements: sed -n 's/^@@.* @@ \(.*\)/\1/p' | sort -u | head -n limit
+// using the function names git places in hunk headers
+func extractFunctionContexts(diff string, limit int) []string {
 	functionMap := make(map[string]bool) // Use map to deduplicate
 
-	for _, line := range lines {
-		if strings.HasPrefix(line, "@@") && strings.HasSuffix(line, "@@") {
-			// Extract function name from: "@@ -1,2 +1,3 @@ func methodName"
-			parts := strings.Split(line, "@@")
-			if len(parts) >= 3 {
-				functionName := strings.TrimSpace(parts[2])
-				if functionName != "" {
-					functionMap[functionName] = true
-				}
+	for _, line := range strings.Split(diff, "\n") {
+		if !strings.HasPrefix(line, "@@") {
+			continue
+		}
+		// Extract function name from: "@@ -1,2 +1,3 @@ func methodName"
+		parts := strings.SplitN(line, "@@", 3)
+		if len(parts) == 3 {
+			if functionName := strings.TrimSpace(parts[2]); functionName != "" {
+				functionMap[functionName] = true
 			}
 		}
 	}
 
-	// Convert map to slice and limit to 10
-	count := 0
+	functions := make([]string, 0, len(functionMap))
 	for funcName := range functionMap {
-		if count >= 10 {
-			break
-		}
-		result.ModifiedFunctions = append(result.ModifiedFunctions, funcName)
-		count++
+		functions = append(functions, funcName)
 	}
+	sort.Strings(functions)
 
-	return nil
+	if len(functions) > limit {
+		functions = functions[:limit]
+	}
+	return functions
 }
@@ -0,0 +1,162 @@
+// Package ccgen - Analysis cache keyed by the staged tree
+package ccgen
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// CacheDirName is the cache directory created inside the git directory
+	// when Options.CacheDir is empty
+	CacheDirName = "fcgh-cache"
+
+	// cacheVersion is bumped whenever GitAnalysisResult changes shape
+	cacheVersion = 4
+)
+
+// cachedAnalysis is the on-disk form of a cached analysis
+type cachedAnalysis struct {
+	Version  int                `json:"version"`
+	Key      string             `json:"key"`
+	Analysis *GitAnalysisResult `json:"analysis"`
+}
+
+// cachedGitAnalysis returns the analysis for the current index, reusing a
+// previous run when the staged tree, HEAD and limits are unchanged. Cache
+// problems never fail generation; they only cost a fresh analysis.
+func (g *Generator) cachedGitAnalysis(ctx context.Context) (*GitAnalysisResult, error) {
+	if g.options.NoCache {
+		return g.performAdvancedGitAnalysis(ctx)
+	}
+
+	path, key, ok := g.analysisCachePath(ctx)
+	if !ok {
+		return g.performAdvancedGitAnalysis(ctx)
+	}
+
+	if cached := loadCachedAnalysis(path, key); cached != nil {
+		g.progress.Start(fmt.Sprintf("Using cached analysis for staged tree %s", shortKey(key)))
+		g.progress.Done(nil)
+		return cached, nil
+	}
+
+	result, err := g.performAdvancedGitAnalysis(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := storeCachedAnalysis(path, key, result); err != nil && g.options.Verbose {
+		fmt.Fprintf(g.out, "Warning: could not write analysis cache: %v\n", err)
+	}
+	return result, nil
+}
+
+// analysisCachePath returns the cache file and key for the current index
+func (g *Generator) analysisCachePath(ctx context.Context) (string, string, bool) {
+	indexKey, err := g.backend.IndexKey(ctx)
+	if err != nil || indexKey == "" {
+		return "", "", false
+	}
+	gitDir, err := g.backend.GitDir(ctx)
+	if err != nil || gitDir == "" {
+		return "", "", false
+	}
+
+	// Limits change what the analysis contains, so they are part of the key
+	limits := g.limits()
+	key := fmt.Sprintf("%s:%d:%d:%d", indexKey, limits.MaxFiles, limits.MaxDiffBytes, limits.MaxFileDiffBytes)
+
+	sum := sha256.Sum256([]byte(key))
+	name := "analysis-" + hex.EncodeToString(sum[:8]) + ".json"
+	return filepath.Join(g.cacheDir(gitDir), name), key, true
+}
+
+// cacheDir returns the directory holding analysis caches for a repository:
+// one directory per git directory under Options.CacheDir, or inside the git
+// directory itself
+func (g *Generator) cacheDir(gitDir string) string {
+	if g.options.CacheDir == "" {
+		return filepath.Join(gitDir, CacheDirName)
+	}
+	sum := sha256.Sum256([]byte(gitDir))
+	return filepath.Join(g.options.CacheDir, "analysis", hex.EncodeToString(sum[:8]))
+}
+
+// loadCachedAnalysis reads a cache entry, returning nil on any mismatch or error
+func loadCachedAnalysis(path, key string) *GitAnalysisResult {
+	data, err := os.ReadFile(path) // #nosec G304 - path is derived from the git directory
+	if err != nil {
+		return nil
+	}
+
+	var entry cachedAnalysis
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	if entry.Version != cacheVersion || entry.Key != key || entry.Analysis == nil {
+		return nil
+	}
+	return entry.Analysis
+}
+
+// storeCachedAnalysis writes a cache entry and drops entries for older trees.
+// Only the latest analysis is kept: the cache exists to make re-runs after a
+// rejected hook or aborted commit instant, not to keep history.
+func storeCachedAnalysis(path, key string, result *GitAnalysisResult) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(cachedAnalysis{Version: cacheVersion, Key: key, Analysis: result})
+	if err != nil {
+		return fmt.Errorf("encoding analysis: %w", err)
+	}
+
+	// Write to a temporary file first so concurrent readers never see partial data
+	tmp, err := os.CreateTemp(dir, "analysis-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating cache file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if name != filepath.Base(path) && strings.HasPrefix(name, "analysis-") && strings.HasSuffix(name, ".json") {
+			_ = os.Remove(filepath.Join(dir, name))
+		}
+	}
+	return nil
+}
+
+// shortKey abbreviates the tree hash at the start of a cache key
+func shortKey(key string) string {
+	tree, _, _ := strings.Cut(key, ":")
+	if len(tree) > 7 {
+		return tree[:7]
+	}
+	return tree
+}
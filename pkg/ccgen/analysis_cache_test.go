@@ -0,0 +1,112 @@
+package ccgen
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCachedGitAnalysis(t *testing.T) {
+	gitDir := t.TempDir()
+	backend := &fakeBackend{
+		files:  []StagedFile{{Path: "pkg/a/a.go", Status: "M", Additions: 3, Deletions: 1}},
+		diff:   "@@ -1 +1 @@ func main() {\n+x\n",
+		key:    "4b825dc642cb6eb9a060e54bf8d69288fbee4904:abc",
+		gitDir: gitDir,
+	}
+	g := New(Options{Backend: backend, Output: io.Discard})
+
+	first, err := g.cachedGitAnalysis(context.Background())
+	if err != nil {
+		t.Fatalf("cachedGitAnalysis() error = %v", err)
+	}
+	second, err := g.cachedGitAnalysis(context.Background())
+	if err != nil {
+		t.Fatalf("cachedGitAnalysis() error = %v", err)
+	}
+	if backend.reads != 1 {
+		t.Errorf("expected one backend read with a warm cache, got %d", backend.reads)
+	}
+	if second.TotalAdditions != first.TotalAdditions || second.FileStats["pkg/a/a.go"] == nil {
+		t.Errorf("cached analysis differs: %+v", second)
+	}
+
+	// A new staged tree misses the cache and replaces the old entry
+	backend.key = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa:abc"
+	if _, err := g.cachedGitAnalysis(context.Background()); err != nil {
+		t.Fatalf("cachedGitAnalysis() error = %v", err)
+	}
+	if backend.reads != 2 {
+		t.Errorf("expected a fresh read after the index changed, got %d reads", backend.reads)
+	}
+	entries, err := os.ReadDir(filepath.Join(gitDir, CacheDirName))
+	if err != nil {
+		t.Fatalf("reading cache dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the latest cache entry, got %d", len(entries))
+	}
+
+	// NoCache always analyzes
+	g = New(Options{Backend: backend, Output: io.Discard, NoCache: true})
+	if _, err := g.cachedGitAnalysis(context.Background()); err != nil {
+		t.Fatalf("cachedGitAnalysis() error = %v", err)
+	}
+	if backend.reads != 3 {
+		t.Errorf("expected NoCache to bypass the cache, got %d reads", backend.reads)
+	}
+}
+
+func TestLoadCachedAnalysisRejectsMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analysis.json")
+	if err := storeCachedAnalysis(path, "key-a", &GitAnalysisResult{TotalFiles: 2}); err != nil {
+		t.Fatalf("storeCachedAnalysis() error = %v", err)
+	}
+	if got := loadCachedAnalysis(path, "key-a"); got == nil || got.TotalFiles != 2 {
+		t.Errorf("expected cached analysis for matching key, got %+v", got)
+	}
+	if got := loadCachedAnalysis(path, "key-b"); got != nil {
+		t.Errorf("expected nil for mismatched key, got %+v", got)
+	}
+	if err := os.WriteFile(path, []byte("{corrupt"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadCachedAnalysis(path, "key-a"); got != nil {
+		t.Errorf("expected nil for corrupt cache, got %+v", got)
+	}
+}
+
+func TestCachedGitAnalysis_CacheDir(t *testing.T) {
+	cacheDir := t.TempDir()
+	newBackend := func() *fakeBackend {
+		return &fakeBackend{
+			files:  []StagedFile{{Path: "a.go", Status: "M", Additions: 1}},
+			diff:   "@@ -1 +1 @@\n+x\n",
+			key:    "4b825dc642cb6eb9a060e54bf8d69288fbee4904:abc",
+			gitDir: t.TempDir(),
+		}
+	}
+
+	// Repositories with the same staged tree get separate cache directories
+	first, second := newBackend(), newBackend()
+	for _, backend := range []*fakeBackend{first, second} {
+		g := New(Options{Backend: backend, Output: io.Discard, CacheDir: cacheDir})
+		if _, err := g.cachedGitAnalysis(context.Background()); err != nil {
+			t.Fatalf("cachedGitAnalysis() error = %v", err)
+		}
+	}
+	repos, err := os.ReadDir(filepath.Join(cacheDir, "analysis"))
+	if err != nil || len(repos) != 2 {
+		t.Fatalf("expected one cache directory per repository, got %v, %v", repos, err)
+	}
+	if _, err := os.Stat(filepath.Join(first.gitDir, CacheDirName)); !os.IsNotExist(err) {
+		t.Errorf("expected nothing cached in the git directory, got %v", err)
+	}
+
+	g := New(Options{Backend: first, Output: io.Discard, CacheDir: cacheDir})
+	if _, err := g.cachedGitAnalysis(context.Background()); err != nil || first.reads != 1 {
+		t.Errorf("expected a warm cache, got %d reads, %v", first.reads, err)
+	}
+}
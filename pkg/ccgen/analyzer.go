@@ -36,13 +36,8 @@ func (g *Generator) GenerateCommitMessage(changes []ChangeType) string {
 	// Use the highest priority change as primary.
 	primary := changes[0]
 
-	// Get JIRA ticket if available
-	var jiraTicket string
-	if g.options.JiraManager != nil {
-		if ticket, err := g.options.JiraManager.GetCurrentJiraTicket(); err == nil && ticket != "" {
-			jiraTicket = ticket
-		}
-	}
+	// Get JIRA ticket if it belongs at the start of the description
+	jiraTicket := g.subjectTicket()
 
 	// Create subject line.
 	subject := primary.Type
@@ -59,10 +54,10 @@ func (g *Generator) GenerateCommitMessage(changes []ChangeType) string {
 	subject += primary.Description
 
 	// Truncate subject if too long.
-	if utf8.RuneCountInString(subject) > MaxSubjectLength {
+	if limit := g.subjectLimit(jiraTicket); utf8.RuneCountInString(subject) > limit {
 		runes := []rune(subject)
-		if len(runes) > MaxSubjectLength-3 {
-			subject = string(runes[:MaxSubjectLength-3]) + "..."
+		if len(runes) > limit-3 {
+			subject = string(runes[:limit-3]) + "..."
 		}
 	}
 
@@ -83,11 +78,12 @@ func (g *Generator) GenerateCommitMessage(changes []ChangeType) string {
 		}
 	}
 
+	message := subject
 	if len(body) > 0 {
-		return subject + strings.Join(body, "\n")
+		message += strings.Join(body, "\n")
 	}
 
-	return subject
+	return g.placeTicket(message, g.currentTickets())
 }
 
 // capitalizeFirst capitalizes the first character of a string
@@ -15,9 +15,7 @@ func (g *Generator) generateClaudeStyleCommitMessageWithPatterns(analyses []*Int
 	}
 
 	// Sort by priority to get the primary change
-	sort.Slice(analyses, func(i, j int) bool {
-		return analyses[i].Priority < analyses[j].Priority
-	})
+	sortAnalyses(analyses)
 
 	primary := analyses[0]
 
@@ -27,13 +25,8 @@ func (g *Generator) generateClaudeStyleCommitMessageWithPatterns(analyses []*Int
 		return g.generateFreeformMessage(primary, analyses)
 	}
 
-	// Get JIRA ticket if available
-	var jiraTicket string
-	if g.options.JiraManager != nil {
-		if ticket, err := g.options.JiraManager.GetCurrentJiraTicket(); err == nil && ticket != "" {
-			jiraTicket = ticket
-		}
-	}
+	// Get JIRA ticket if it belongs at the start of the description
+	jiraTicket := g.subjectTicket()
 
 	// Create Claude-style subject line
 	subject := g.buildClaudeSubject(primary, jiraTicket)
@@ -91,8 +84,8 @@ func (g *Generator) buildClaudeSubject(primary *IntelligentChangeAnalysis, jiraT
 	subject += description
 
 	// Truncate if too long, but prefer complete words
-	if utf8.RuneCountInString(subject) > MaxSubjectLength {
-		subject = g.intelligentTruncate(subject, MaxSubjectLength)
+	if limit := g.subjectLimit(jiraTicket); utf8.RuneCountInString(subject) > limit {
+		subject = g.intelligentTruncate(subject, limit)
 	}
 
 	return subject
@@ -103,19 +96,20 @@ func (g *Generator) enhanceDescription(analysis *IntelligentChangeAnalysis) stri
 	desc := analysis.Description
 
 	// Make descriptions more action-oriented and specific
-	replacements := map[string]string{
-		"update":     "improve",
-		"enhance":    "improve",
-		"change":     "update",
-		"modify":     "refine",
-		"fix issues": "resolve issues",
+	// The first matching replacement wins, so they are checked in order
+	replacements := []struct{ old, new string }{
+		{"update", "improve"},
+		{"enhance", "improve"},
+		{"change", "update"},
+		{"modify", "refine"},
+		{"fix issues", "resolve issues"},
 	}
 
-	for old, new := range replacements {
-		if strings.Contains(strings.ToLower(desc), old) {
+	for _, r := range replacements {
+		if strings.Contains(strings.ToLower(desc), r.old) {
 			// Preserve case by using case-insensitive replacement
-			desc = strings.ReplaceAll(desc, old, new)
-			desc = strings.ReplaceAll(desc, g.capitalizeFirst(old), g.capitalizeFirst(new))
+			desc = strings.ReplaceAll(desc, r.old, r.new)
+			desc = strings.ReplaceAll(desc, g.capitalizeFirst(r.old), g.capitalizeFirst(r.new))
 			break
 		}
 	}
@@ -167,9 +161,7 @@ func (g *Generator) buildClaudeBody(analyses []*IntelligentChangeAnalysis, prima
 		}
 	} else {
 		// Multiple files - group by change type
-		grouped := g.groupAnalysesByType(analyses)
-
-		for changeType, group := range grouped {
+		for _, group := range g.groupAnalysesByType(analyses) {
 			if len(group) == 1 {
 				analysis := group[0]
 				desc := g.enhanceDescription(analysis)
@@ -181,7 +173,7 @@ func (g *Generator) buildClaudeBody(analyses []*IntelligentChangeAnalysis, prima
 					files = append(files, g.extractFileName(analysis.FilePath))
 				}
 
-				desc := g.generateGroupDescription(changeType, files)
+				desc := g.generateGroupDescription(group[0].ChangeType, files)
 				bodyLines = append(bodyLines, fmt.Sprintf("- %s", desc))
 			}
 		}
@@ -206,21 +198,44 @@ func (g *Generator) buildClaudeBody(analyses []*IntelligentChangeAnalysis, prima
 	return strings.Join(wrappedLines, "\n")
 }
 
-// groupAnalysesByType groups analyses by their change type
-func (g *Generator) groupAnalysesByType(analyses []*IntelligentChangeAnalysis) map[string][]*IntelligentChangeAnalysis {
-	grouped := make(map[string][]*IntelligentChangeAnalysis)
+// groupAnalysesByType groups analyses by their change type and scope. Groups
+// keep the order of their first analysis, so sorted analyses give a stable body.
+func (g *Generator) groupAnalysesByType(analyses []*IntelligentChangeAnalysis) [][]*IntelligentChangeAnalysis {
+	var grouped [][]*IntelligentChangeAnalysis
+	index := make(map[string]int)
 
 	for _, analysis := range analyses {
 		key := analysis.ChangeType
 		if analysis.Scope != "" {
 			key += ":" + analysis.Scope
 		}
-		grouped[key] = append(grouped[key], analysis)
+		i, ok := index[key]
+		if !ok {
+			i = len(grouped)
+			index[key] = i
+			grouped = append(grouped, nil)
+		}
+		grouped[i] = append(grouped[i], analysis)
 	}
 
 	return grouped
 }
 
+// sortAnalyses orders analyses by priority, then scope and file path, so the
+// same staged tree always produces the same message
+func sortAnalyses(analyses []*IntelligentChangeAnalysis) {
+	sort.SliceStable(analyses, func(i, j int) bool {
+		a, b := analyses[i], analyses[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+		return a.FilePath < b.FilePath
+	})
+}
+
 // generateGroupDescription creates description for grouped changes
 func (g *Generator) generateGroupDescription(changeType string, files []string) string {
 	if len(files) == 1 {
@@ -351,3 +366,13 @@ func (g *Generator) intelligentTruncate(text string, maxLength int) string {
 
 	return result
 }
+
+// sortedKeys returns map keys in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
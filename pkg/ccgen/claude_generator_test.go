@@ -0,0 +1,99 @@
+package ccgen
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	newBackend := func() *fakeBackend {
+		return &fakeBackend{files: []StagedFile{
+			{Path: "internal/api/users.go", Status: "M", Additions: 3, Deletions: 1},
+			{Path: "internal/api/orders.go", Status: "M", Additions: 3, Deletions: 1},
+			{Path: "cmd/web/main.go", Status: "M", Additions: 3, Deletions: 1},
+			{Path: "docs/guide.md", Status: "M", Additions: 3, Deletions: 1},
+		}}
+	}
+
+	want := "feat(api): expand orders functionality (+3 lines)\n" +
+		"\n" +
+		"- Enhance orders and users functionality\n" +
+		"- Expand main functionality (+3 lines)\n" +
+		"- Expand guide functionality (+3 lines)\n" +
+		"\n" +
+		"These changes expand functionality of the system."
+	for i := 0; i < 20; i++ {
+		g := New(Options{Backend: newBackend(), Output: io.Discard, StagedOnly: true, NoCache: true})
+		result, err := g.Generate(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Message != want {
+			t.Fatalf("run %d: Message =\n%s\nwant:\n%s", i, result.Message, want)
+		}
+	}
+}
+
+// prefixScopes maps paths under a prefix to a scope
+type prefixScopes map[string]string
+
+func (p prefixScopes) Scope(path string) string {
+	for prefix, scope := range p {
+		if strings.HasPrefix(path, prefix) {
+			return scope
+		}
+	}
+	return ""
+}
+
+func TestGenerateUsesDerivedScopes(t *testing.T) {
+	backend := &fakeBackend{files: []StagedFile{
+		{Path: "apps/billing/invoice.go", Status: "M", Additions: 3, Deletions: 1},
+	}}
+	g := New(Options{Backend: backend, Output: io.Discard, StagedOnly: true, NoCache: true,
+		Scopes: prefixScopes{"apps/billing/": "payments"}})
+	result, err := g.Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(result.Message, "feat(payments): ") {
+		t.Errorf("Message = %q, want the derived scope", result.Message)
+	}
+}
+
+// fixedScope pins a scope
+type fixedScope string
+
+func (s fixedScope) GetScope() (string, error) { return string(s), nil }
+
+func TestGenerateOverridesTypeAndScope(t *testing.T) {
+	backend := &fakeBackend{files: []StagedFile{
+		{Path: "internal/billing/invoice.go", Status: "M", Additions: 3, Deletions: 1},
+		{Path: "docs/billing.md", Status: "A", Additions: 10},
+	}}
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{name: "pinned scope", opts: Options{PinnedScope: fixedScope("payments")}, want: "feat(payments): "},
+		{name: "scope flag wins", opts: Options{Scope: "api", PinnedScope: fixedScope("payments")}, want: "feat(api): "},
+		{name: "type and pinned scope", opts: Options{Type: "fix", PinnedScope: fixedScope("payments")}, want: "fix(payments): "},
+		{name: "nothing pinned", opts: Options{Type: "perf", PinnedScope: fixedScope("")}, want: "perf(docs): "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Backend, opts.Output, opts.StagedOnly, opts.NoCache, opts.NoHistory = backend, io.Discard, true, true, true
+			result, err := New(opts).Generate(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(result.Message, tt.want) {
+				t.Errorf("Message = %q, want prefix %q", result.Message, tt.want)
+			}
+		})
+	}
+}
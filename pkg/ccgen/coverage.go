@@ -0,0 +1,89 @@
+// Package ccgen - Test and documentation coverage notes for commit bodies
+package ccgen
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// NoTestsNote is the body note for production code changed without tests
+const NoTestsNote = "No test changes included."
+
+// sourceExtensions are the extensions of production code files
+var sourceExtensions = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true,
+	".java": true, ".kt": true, ".scala": true, ".rb": true, ".rs": true, ".php": true,
+	".c": true, ".cc": true, ".cpp": true, ".h": true, ".hpp": true, ".cs": true,
+	".swift": true, ".m": true, ".ex": true, ".exs": true, ".dart": true,
+}
+
+// testDirs are path segments that hold tests
+var testDirs = []string{"test", "tests", "__tests__", "spec", "testdata"}
+
+// docsExtensions are the extensions of documentation files
+var docsExtensions = map[string]bool{".md": true, ".rst": true, ".adoc": true}
+
+// isTestFile reports whether a path is a test, by the naming conventions of
+// common languages or a test directory
+func isTestFile(filePath string) bool {
+	base := path.Base(filePath)
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	switch {
+	case strings.HasSuffix(stem, "_test"), strings.HasPrefix(stem, "test_"),
+		strings.HasSuffix(stem, ".test"), strings.HasSuffix(stem, ".spec"),
+		strings.HasSuffix(stem, "Test"), strings.HasSuffix(stem, "Tests"):
+		return true
+	}
+	for _, segment := range strings.Split(path.Dir(filePath), "/") {
+		for _, dir := range testDirs {
+			if segment == dir {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isDocsFile reports whether a path is documentation
+func isDocsFile(filePath string) bool {
+	if docsExtensions[strings.ToLower(path.Ext(filePath))] {
+		return true
+	}
+	return strings.HasPrefix(filePath, "docs/") || strings.HasPrefix(filePath, "doc/")
+}
+
+// coverageNote returns the body note on the tests and documentation of the
+// staged files: NoTestsNote when production code changed without test
+// changes, and the documentation updated with it. Deleted files count
+// neither as changed code nor as updated documentation.
+func coverageNote(stats map[string]*FileStatistics) string {
+	var code, tests bool
+	var docs []string
+	for filename, s := range stats {
+		if s.Kind != "" || s.ChangeType == "D" {
+			continue
+		}
+		switch {
+		case isTestFile(filename):
+			tests = true
+		case isDocsFile(filename):
+			docs = append(docs, filename)
+		case sourceExtensions[path.Ext(filename)]:
+			code = true
+		}
+	}
+	if !code {
+		return ""
+	}
+
+	var lines []string
+	if !tests {
+		lines = append(lines, NoTestsNote)
+	}
+	if len(docs) > 0 {
+		sort.Strings(docs)
+		lines = append(lines, "Docs updated: "+strings.Join(docs, ", ")+".")
+	}
+	return strings.Join(lines, "\n")
+}
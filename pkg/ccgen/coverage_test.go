@@ -0,0 +1,68 @@
+package ccgen
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCoverageNote(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string // path to change type
+		want  string
+	}{
+		{name: "code without tests", files: map[string]string{"internal/api/handler.go": "M"}, want: NoTestsNote},
+		{name: "code with tests", files: map[string]string{"internal/api/handler.go": "M", "internal/api/handler_test.go": "M"}},
+		{name: "code with a test directory", files: map[string]string{"src/app.ts": "M", "src/__tests__/app.ts": "A"}},
+		{name: "code with a spec", files: map[string]string{"src/app.ts": "M", "src/app.spec.ts": "M"}},
+		{name: "docs only", files: map[string]string{"README.md": "M"}},
+		{name: "tests only", files: map[string]string{"test_parser.py": "A"}},
+		{name: "deleted code", files: map[string]string{"old.go": "D"}},
+		{
+			name:  "code with docs",
+			files: map[string]string{"cmd/main.go": "M", "README.md": "M", "docs/usage.md": "A", "cmd/main_test.go": "M"},
+			want:  "Docs updated: README.md, docs/usage.md.",
+		},
+		{
+			name:  "code with docs and without tests",
+			files: map[string]string{"cmd/main.go": "M", "README.md": "M"},
+			want:  NoTestsNote + "\nDocs updated: README.md.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := make(map[string]*FileStatistics)
+			for path, change := range tt.files {
+				stats[path] = &FileStatistics{Filename: path, ChangeType: change}
+			}
+			if got := coverageNote(stats); got != tt.want {
+				t.Errorf("coverageNote() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Generated and vendored code is not production code to test
+	stats := map[string]*FileStatistics{"api/service.pb.go": {ChangeType: "M", Kind: FileKindGenerated}}
+	if got := coverageNote(stats); got != "" {
+		t.Errorf("coverageNote() of generated code = %q", got)
+	}
+}
+
+func TestGenerateCoverageHints(t *testing.T) {
+	backend := &fakeBackend{files: []StagedFile{
+		{Path: "internal/billing/invoice.go", Status: "M", Additions: 3, Deletions: 1},
+	}}
+	for _, hints := range []bool{false, true} {
+		g := New(Options{Backend: backend, Output: io.Discard, StagedOnly: true, NoCache: true, NoHistory: true, CoverageHints: hints})
+		result, err := g.Generate(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.HasSuffix(result.Message, "\n\n"+NoTestsNote); got != hints {
+			t.Errorf("CoverageHints %v: Message = %q", hints, result.Message)
+		}
+	}
+}
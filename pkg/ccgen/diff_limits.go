@@ -0,0 +1,211 @@
+// Package ccgen - Size budgets and streaming diff reading for large changesets
+package ccgen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultMaxFiles is the number of staged files above which analysis degrades
+	// to statistics only (no diff content is read)
+	DefaultMaxFiles = 1000
+	// DefaultMaxDiffBytes caps the total diff content kept in memory
+	DefaultMaxDiffBytes = 16 * 1024 * 1024
+	// DefaultMaxFileDiffBytes caps the diff content kept for a single file
+	DefaultMaxFileDiffBytes = 256 * 1024
+
+	// progressEvery controls how often diff reading progress is reported (in files)
+	progressEvery = 200
+	// maxLineBytes is the longest diff line kept; longer lines (minified
+	// code, lockfiles, embedded base64) cut their file's diff short
+	maxLineBytes = 1024 * 1024
+)
+
+// DiffLimits bounds how much of a changeset is analyzed
+type DiffLimits struct {
+	MaxFiles         int
+	MaxDiffBytes     int64
+	MaxFileDiffBytes int64
+}
+
+// limits returns the configured diff limits with defaults applied
+func (g *Generator) limits() DiffLimits {
+	l := DiffLimits{
+		MaxFiles:         g.options.MaxFiles,
+		MaxDiffBytes:     g.options.MaxDiffBytes,
+		MaxFileDiffBytes: g.options.MaxFileDiffBytes,
+	}
+	if l.MaxFiles <= 0 {
+		l.MaxFiles = DefaultMaxFiles
+	}
+	if l.MaxDiffBytes <= 0 {
+		l.MaxDiffBytes = DefaultMaxDiffBytes
+	}
+	if l.MaxFileDiffBytes <= 0 {
+		l.MaxFileDiffBytes = DefaultMaxFileDiffBytes
+	}
+	return l
+}
+
+// limitedDiff is the outcome of reading a diff stream under a byte budget
+type limitedDiff struct {
+	Content        string
+	TruncatedFiles []string
+	Truncated      bool // total budget exhausted, remaining files skipped
+	Files          int
+}
+
+// readLimitedDiff streams a unified diff, keeping at most limits.MaxFileDiffBytes
+// per file and limits.MaxDiffBytes overall. progress, if non-nil, is called every
+// progressEvery files with the number of files read so far.
+func readLimitedDiff(r io.Reader, limits DiffLimits, progress func(files int)) (*limitedDiff, error) {
+	result := &limitedDiff{}
+
+	var sb strings.Builder
+	var total, fileBytes int64
+	currentFile := ""
+	fileTruncated := false
+
+	reader := bufio.NewReaderSize(r, 64*1024)
+	for {
+		line, tooLong, err := readDiffLine(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading diff: %w", err)
+		}
+
+		if strings.HasPrefix(line, "diff --git ") {
+			result.Files++
+			currentFile = diffHeaderPath(line)
+			fileBytes = 0
+			fileTruncated = false
+			if progress != nil && result.Files%progressEvery == 0 {
+				progress(result.Files)
+			}
+		}
+
+		// Lines skipped for their file's budget never count toward the whole
+		// diff's, so an over-long line cannot cut off the files after it
+		size := int64(len(line) + 1)
+		if tooLong {
+			fileBytes = limits.MaxFileDiffBytes
+		}
+		if fileBytes+size > limits.MaxFileDiffBytes {
+			if !fileTruncated {
+				fileTruncated = true
+				result.TruncatedFiles = append(result.TruncatedFiles, currentFile)
+			}
+			continue
+		}
+		if total+size > limits.MaxDiffBytes {
+			result.Truncated = true
+			break
+		}
+
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+		total += size
+		fileBytes += size
+	}
+
+	result.Content = sb.String()
+	return result, nil
+}
+
+// readDiffLine returns the next line without its line ending. A line longer
+// than maxLineBytes is read to its end but only its first maxLineBytes are
+// returned, with tooLong set. err is io.EOF once the input is exhausted.
+func readDiffLine(r *bufio.Reader) (line string, tooLong bool, err error) {
+	var buf []byte
+	read := false
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			if err == io.EOF && read {
+				return string(buf), tooLong, nil
+			}
+			return "", false, err
+		}
+		read = true
+		if room := maxLineBytes - len(buf); len(chunk) > room {
+			chunk = chunk[:room]
+			tooLong = true
+		}
+		buf = append(buf, chunk...)
+		if !isPrefix {
+			return string(buf), tooLong, nil
+		}
+	}
+}
+
+// diffHeaderPath extracts the new path from a "diff --git a/x b/x" header
+func diffHeaderPath(header string) string {
+	if idx := strings.LastIndex(header, " b/"); idx != -1 {
+		return header[idx+3:]
+	}
+	return strings.TrimPrefix(header, "diff --git ")
+}
+
+// getDegradedChangeAnalyses summarizes very large changesets per scope using
+// statistics only, keeping generation fast when the diff is not read
+func (g *Generator) getDegradedChangeAnalyses(analysis *GitAnalysisResult) []*IntelligentChangeAnalysis {
+	type group struct {
+		files      []string
+		additions  int
+		deletions  int
+		statusSeen map[string]int
+	}
+	groups := make(map[string]*group)
+
+	for _, filename := range sortedKeys(analysis.FileStats) {
+		stats := analysis.FileStats[filename]
+		scope := g.determineIntelligentScope(filename)
+		grp, ok := groups[scope]
+		if !ok {
+			grp = &group{statusSeen: make(map[string]int)}
+			groups[scope] = grp
+		}
+		grp.files = append(grp.files, filename)
+		grp.additions += stats.Additions
+		grp.deletions += stats.Deletions
+		grp.statusSeen[stats.ChangeType]++
+	}
+
+	analyses := make([]*IntelligentChangeAnalysis, 0, len(groups))
+	for _, scope := range sortedKeys(groups) {
+		grp := groups[scope]
+
+		changeType := "chore"
+		switch {
+		case grp.statusSeen["A"] > len(grp.files)/2:
+			changeType = "feat"
+		case grp.statusSeen["D"] > len(grp.files)/2:
+			changeType = "refactor"
+		}
+
+		target := scope
+		if target == "" {
+			target = "project"
+		}
+
+		analyses = append(analyses, &IntelligentChangeAnalysis{
+			FilePath:    grp.files[0],
+			ChangeType:  changeType,
+			Scope:       scope,
+			Description: fmt.Sprintf("update %d %s files (+%d/-%d lines)", len(grp.files), target, grp.additions, grp.deletions),
+			Files:       grp.files,
+			Priority:    g.getTypePriority(changeType) - len(grp.files)/100,
+			Impact:      "major changes",
+		})
+	}
+
+	// Ordered by path so output does not depend on how scopes are grouped
+	sort.Slice(analyses, func(i, j int) bool { return analyses[i].FilePath < analyses[j].FilePath })
+	return analyses
+}
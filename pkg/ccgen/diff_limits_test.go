@@ -0,0 +1,140 @@
+package ccgen
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadLimitedDiff(t *testing.T) {
+	small := "diff --git a/a.go b/a.go\n+one\n"
+	big := "diff --git a/big.go b/big.go\n" + strings.Repeat("+xxxxxxxxx\n", 20)
+
+	tests := []struct {
+		name          string
+		input         string
+		limits        DiffLimits
+		wantFiles     int
+		wantTruncated bool
+		wantFileCuts  []string
+		wantContains  []string
+		wantMissing   []string
+	}{
+		{
+			name:         "within budget",
+			input:        small + big,
+			limits:       DiffLimits{MaxDiffBytes: 1 << 20, MaxFileDiffBytes: 1 << 20},
+			wantFiles:    2,
+			wantContains: []string{"+one", "diff --git a/big.go b/big.go"},
+		},
+		{
+			name:         "per file budget",
+			input:        big + small,
+			limits:       DiffLimits{MaxDiffBytes: 1 << 20, MaxFileDiffBytes: 64},
+			wantFiles:    2,
+			wantFileCuts: []string{"big.go"},
+			wantContains: []string{"diff --git a/big.go b/big.go", "+one"},
+		},
+		{
+			name:          "total budget",
+			input:         small + big,
+			limits:        DiffLimits{MaxDiffBytes: int64(len(small)) + 10, MaxFileDiffBytes: 1 << 20},
+			wantFiles:     2,
+			wantTruncated: true,
+			wantContains:  []string{"+one"},
+		},
+		{
+			name:         "over-long line",
+			input:        "diff --git a/min.js b/min.js\n+" + strings.Repeat("x", 2*maxLineBytes) + "\n+after\n" + small,
+			limits:       DiffLimits{MaxDiffBytes: 16 << 20, MaxFileDiffBytes: 16 << 20},
+			wantFiles:    2,
+			wantFileCuts: []string{"min.js"},
+			wantContains: []string{"diff --git a/min.js b/min.js", "diff --git a/a.go b/a.go", "+one"},
+			wantMissing:  []string{"+after"},
+		},
+		{
+			name:         "over-long line larger than the whole budget",
+			input:        "diff --git a/min.js b/min.js\n+" + strings.Repeat("x", 2*maxLineBytes) + "\n" + small,
+			limits:       DiffLimits{MaxDiffBytes: 64 << 10, MaxFileDiffBytes: 32 << 10},
+			wantFiles:    2,
+			wantFileCuts: []string{"min.js"},
+			wantContains: []string{"diff --git a/min.js b/min.js", "diff --git a/a.go b/a.go", "+one"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readLimitedDiff(strings.NewReader(tt.input), tt.limits, nil)
+			if err != nil {
+				t.Fatalf("readLimitedDiff() error = %v", err)
+			}
+			if got.Files != tt.wantFiles {
+				t.Errorf("Files = %d, want %d", got.Files, tt.wantFiles)
+			}
+			if got.Truncated != tt.wantTruncated {
+				t.Errorf("Truncated = %v, want %v", got.Truncated, tt.wantTruncated)
+			}
+			if !reflect.DeepEqual(got.TruncatedFiles, tt.wantFileCuts) {
+				t.Errorf("TruncatedFiles = %v, want %v", got.TruncatedFiles, tt.wantFileCuts)
+			}
+			if int64(len(got.Content)) > tt.limits.MaxDiffBytes {
+				t.Errorf("Content length %d exceeds budget %d", len(got.Content), tt.limits.MaxDiffBytes)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(got.Content, want) {
+					t.Errorf("Content missing %q", want)
+				}
+			}
+			for _, unwanted := range tt.wantMissing {
+				if strings.Contains(got.Content, unwanted) {
+					t.Errorf("Content has %q", unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestPerformAdvancedGitAnalysisDegraded(t *testing.T) {
+	backend := &fakeBackend{
+		files: []StagedFile{
+			{Path: "pkg/a/a.go", Status: "A", Additions: 10},
+			{Path: "pkg/a/b.go", Status: "A", Additions: 5},
+			{Path: "docs/x.md", Status: "M", Additions: 1, Deletions: 1},
+		},
+		diff: "@@ -1 +1 @@ func main() {\n+x\n",
+	}
+
+	g := New(Options{Backend: backend, Output: io.Discard, MaxFiles: 2})
+	result, err := g.performAdvancedGitAnalysis(context.Background())
+	if err != nil {
+		t.Fatalf("performAdvancedGitAnalysis() error = %v", err)
+	}
+	if !result.Degraded {
+		t.Fatal("expected degraded analysis above MaxFiles")
+	}
+	if result.StagedDiff != "" || len(result.ModifiedFunctions) != 0 {
+		t.Errorf("expected diff content to be skipped, got %q", result.StagedDiff)
+	}
+
+	analyses := g.getDegradedChangeAnalyses(result)
+	files := 0
+	for _, a := range analyses {
+		files += len(a.Files)
+		if a.Description == "" {
+			t.Errorf("expected description for scope %q", a.Scope)
+		}
+	}
+	if files != 3 {
+		t.Errorf("degraded analyses cover %d files, want 3", files)
+	}
+	for i := 1; i < len(analyses); i++ {
+		if analyses[i-1].FilePath > analyses[i].FilePath {
+			t.Errorf("degraded analyses not ordered by path: %s before %s", analyses[i-1].FilePath, analyses[i].FilePath)
+		}
+	}
+	if again := g.getDegradedChangeAnalyses(result); !reflect.DeepEqual(again, analyses) {
+		t.Error("degraded analyses differ between runs")
+	}
+}
@@ -0,0 +1,98 @@
+// Package ccgen - Semantic plugin explanations and low-confidence prompts
+package ccgen
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// explainChanges classifies the staged diff with the configured semantic
+// plugins, sharing recent-history hotspots with all of them
+func (g *Generator) explainChanges(ctx context.Context, analysis *GitAnalysisResult) (*semantic.Explanation, error) {
+	g.options.Semantic.SetHotspotDetector(NewHotspotService(ctx, g.backend, g.options.HotspotWindow, g.options.HotspotThreshold))
+
+	explanation, err := g.options.Semantic.Explain(ctx, semantic.ParseDiff(analysis.StagedDiff))
+	if err != nil {
+		return nil, fmt.Errorf("semantic analysis: %w", err)
+	}
+	return explanation, nil
+}
+
+// printExplanation shows every plugin result and why the primary change won
+func (g *Generator) printExplanation(explanation *semantic.Explanation) {
+	fmt.Fprintf(g.out, "**Semantic plugin results:**\n\n")
+	if len(explanation.Results) == 0 {
+		fmt.Fprintf(g.out, "- No plugin claimed the staged files\n")
+	}
+	for _, result := range explanation.Results {
+		target := "project"
+		if len(result.Files) > 0 {
+			target = strings.Join(result.Files, ", ")
+		}
+		if result.Err != nil {
+			fmt.Fprintf(g.out, "- **%s** on `%s`: error: %v\n", result.Plugin, target, result.Err)
+			continue
+		}
+
+		change := result.Change
+		header := change.Type
+		if change.Scope != "" {
+			header += "(" + change.Scope + ")"
+		}
+		if change.BreakingChange {
+			header += "!"
+		}
+		fmt.Fprintf(g.out, "- **%s** on `%s`: %s: %s\n", result.Plugin, target, header, change.Description)
+		fmt.Fprintf(g.out, "   - Confidence: %.2f\n", change.Confidence)
+		if change.Reasoning != "" {
+			fmt.Fprintf(g.out, "   - Reasoning: %s\n", change.Reasoning)
+		}
+	}
+
+	fmt.Fprintf(g.out, "\n**Decision:** %s\n", explanation.Decision)
+	if explanation.Composition != nil {
+		fmt.Fprintf(g.out, "**Plugin suggestion:**\n```\n%s\n```\n", explanation.Composition.Message())
+	}
+	fmt.Fprintln(g.out)
+}
+
+// confirmLowConfidence asks for a commit subject when the classification
+// confidence is below the configured threshold, numbering the subjects of
+// similar previous commits so one can be picked. An empty answer (or no
+// input at all) keeps the generated message.
+func (g *Generator) confirmLowConfidence(message string, confidence float64, suggestions []HistorySuggestion) string {
+	subject, body, _ := strings.Cut(message, "\n")
+
+	fmt.Fprintf(g.out, "**Low confidence** (%.2f < %.2f): the changes could not be classified reliably.\n",
+		confidence, g.options.MinConfidence)
+	fmt.Fprintf(g.out, "Suggested subject: `%s`\n", subject)
+	if len(suggestions) > 0 {
+		fmt.Fprintf(g.out, "Similar previous commits:\n")
+		for i, s := range suggestions {
+			fmt.Fprintf(g.out, "  %d. `%s`\n", i+1, s.Subject)
+		}
+		fmt.Fprintf(g.out, "Enter a commit subject or a number to reuse, or press Enter to keep the suggestion: ")
+	} else {
+		fmt.Fprintf(g.out, "Enter a commit subject, or press Enter to keep the suggestion: ")
+	}
+
+	line, _ := bufio.NewReader(g.in).ReadString('\n')
+	fmt.Fprintln(g.out)
+
+	answer := strings.TrimSpace(line)
+	if answer == "" {
+		return message
+	}
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(suggestions) {
+		answer = suggestions[n-1].Subject
+	}
+	if body == "" {
+		return answer
+	}
+	return answer + "\n" + body
+}
@@ -0,0 +1,102 @@
+package ccgen
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// sqlPlugin classifies .sql files with a fixed confidence
+type sqlPlugin struct{ confidence float64 }
+
+func (p *sqlPlugin) Name() string                    { return "sql" }
+func (p *sqlPlugin) Version() string                 { return "1.0.0" }
+func (p *sqlPlugin) SupportedExtensions() []string   { return []string{".sql"} }
+func (p *sqlPlugin) SupportedFilePatterns() []string { return nil }
+func (p *sqlPlugin) CanAnalyze(semantic.FileChange) bool {
+	return false
+}
+func (p *sqlPlugin) AnalyzeFile(_ context.Context, file semantic.FileChange, _ semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	return &semantic.SemanticChange{
+		Type: "feat", Scope: "db", Description: "add orders table",
+		Files: []string{file.Path}, Confidence: p.confidence, Reasoning: "CREATE TABLE found",
+	}, nil
+}
+func (p *sqlPlugin) AnalyzeProject(context.Context, semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	return nil, nil
+}
+func (p *sqlPlugin) DefaultConfig() map[string]string       { return map[string]string{} }
+func (p *sqlPlugin) ValidateConfig(map[string]string) error { return nil }
+
+func newSemanticAnalyzer(t *testing.T, confidence float64) *semantic.SemanticAnalyzer {
+	t.Helper()
+	registry := semantic.NewPluginRegistry()
+	if err := registry.Register(&sqlPlugin{confidence: confidence}); err != nil {
+		t.Fatal(err)
+	}
+	return semantic.NewSemanticAnalyzer(registry)
+}
+
+func TestExplainAndConfidenceThreshold(t *testing.T) {
+	newBackend := func() *fakeBackend {
+		return &fakeBackend{
+			files: []StagedFile{{Path: "db/V1__orders.sql", Status: "A", Additions: 1}},
+			diff:  "diff --git a/db/V1__orders.sql b/db/V1__orders.sql\nnew file mode 100644\n+CREATE TABLE orders (id int);\n",
+		}
+	}
+
+	t.Run("explain prints plugin results", func(t *testing.T) {
+		var out bytes.Buffer
+		g := New(Options{Backend: newBackend(), Output: &out, StagedOnly: true, NoCache: true,
+			Semantic: newSemanticAnalyzer(t, 0.9), Explain: true})
+		result, err := g.Generate(context.Background())
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		for _, want := range []string{
+			"**sql** on `db/V1__orders.sql`: feat(db): add orders table",
+			"Confidence: 0.90",
+			"Reasoning: CREATE TABLE found",
+			"**Decision:** feat(db) is the only change detected",
+		} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("explain output missing %q:\n%s", want, out.String())
+			}
+		}
+		if result.Confidence != 0.9 {
+			t.Errorf("Confidence = %v", result.Confidence)
+		}
+	})
+
+	t.Run("low confidence asks for the subject", func(t *testing.T) {
+		var out bytes.Buffer
+		g := New(Options{Backend: newBackend(), Output: &out, StagedOnly: true, NoCache: true,
+			Semantic: newSemanticAnalyzer(t, 0.4), MinConfidence: 0.6,
+			Input: strings.NewReader("feat(db): add orders schema\n")})
+		result, err := g.Generate(context.Background())
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if subject, _, _ := strings.Cut(result.Message, "\n"); subject != "feat(db): add orders schema" {
+			t.Errorf("subject = %q", subject)
+		}
+		if !strings.Contains(out.String(), "**Low confidence** (0.40 < 0.60)") {
+			t.Errorf("expected a low confidence prompt:\n%s", out.String())
+		}
+	})
+
+	t.Run("empty answer keeps the suggestion", func(t *testing.T) {
+		g := New(Options{Backend: newBackend(), Output: &bytes.Buffer{}, StagedOnly: true, NoCache: true,
+			Semantic: newSemanticAnalyzer(t, 0.4), MinConfidence: 0.6, Input: strings.NewReader("")})
+		result, err := g.Generate(context.Background())
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if strings.HasPrefix(result.Message, "feat(db): add orders schema") || result.Message == "" {
+			t.Errorf("expected the generated message, got %q", result.Message)
+		}
+	})
+}
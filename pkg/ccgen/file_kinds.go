@@ -0,0 +1,248 @@
+// Package ccgen - Classification of binary, lockfile, vendored and generated files
+package ccgen
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// File kinds that are excluded from content analysis and down-weighted when
+// choosing the primary change. Hand-written files have an empty kind.
+const (
+	FileKindBinary    = "binary"
+	FileKindLockfile  = "lockfile"
+	FileKindVendored  = "vendored"
+	FileKindGenerated = "generated"
+)
+
+// downWeight is added to the priority of non hand-written changes when the
+// changeset also contains hand-written files, so they never become the subject
+const downWeight = 20
+
+// lockfileNames are dependency lockfiles regenerated by package managers
+var lockfileNames = map[string]bool{
+	"go.sum":              true,
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lockb":           true,
+	"Cargo.lock":          true,
+	"Gemfile.lock":        true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+	"composer.lock":       true,
+	"mix.lock":            true,
+	"pubspec.lock":        true,
+	"Podfile.lock":        true,
+	"flake.lock":          true,
+	".terraform.lock.hcl": true,
+}
+
+// vendoredDirs are path segments that hold third-party code
+var vendoredDirs = []string{"vendor", "node_modules", "third_party", "bower_components"}
+
+// generatedSuffixes are filename suffixes produced by common code generators
+var generatedSuffixes = []string{
+	".pb.go", ".pb.gw.go", "_grpc.pb.go", "_generated.go", "_gen.go", ".gen.go",
+	"_string.go", ".min.js", ".min.css", ".js.map", ".css.map", "_pb2.py", "_pb2_grpc.py",
+}
+
+// classifyPath returns the kind implied by a file's path alone
+func classifyPath(filePath string) string {
+	base := path.Base(filePath)
+	if lockfileNames[base] {
+		return FileKindLockfile
+	}
+
+	for _, segment := range strings.Split(path.Dir(filePath), "/") {
+		for _, dir := range vendoredDirs {
+			if segment == dir {
+				return FileKindVendored
+			}
+		}
+	}
+
+	if strings.HasPrefix(base, "zz_generated") || strings.HasPrefix(base, "mock_") {
+		return FileKindGenerated
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return FileKindGenerated
+		}
+	}
+
+	return ""
+}
+
+// classifyStagedFiles sets the kind of every staged file from binary flags,
+// path heuristics and linguist-generated / linguist-vendored attributes, and
+// flags export-ignore files
+func (g *Generator) classifyStagedFiles(ctx context.Context, files []StagedFile, result *GitAnalysisResult) {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+
+	// Attribute lookup failures only lose the .gitattributes overrides
+	attrs, err := g.backend.PathAttributes(ctx, paths, "linguist-generated", "linguist-vendored", "export-ignore")
+	if err != nil {
+		attrs = nil
+	}
+
+	for _, file := range files {
+		stats := result.FileStats[file.Path]
+		if stats == nil {
+			continue
+		}
+
+		switch {
+		case file.Binary:
+			stats.Kind = FileKindBinary
+		case attributeSet(attrs[file.Path]["linguist-generated"]):
+			stats.Kind = FileKindGenerated
+		case attributeSet(attrs[file.Path]["linguist-vendored"]):
+			stats.Kind = FileKindVendored
+		default:
+			stats.Kind = classifyPath(file.Path)
+		}
+
+		// An explicit "-linguist-generated" or "-linguist-vendored" marks the
+		// file as hand-written
+		if attrs[file.Path]["linguist-generated"] == "unset" && stats.Kind == FileKindGenerated ||
+			attrs[file.Path]["linguist-vendored"] == "unset" && stats.Kind == FileKindVendored {
+			stats.Kind = ""
+		}
+		stats.ExportIgnored = attributeSet(attrs[file.Path]["export-ignore"])
+	}
+}
+
+// attributeSet reports whether a git attribute value means "enabled"
+func attributeSet(value string) bool {
+	return value == "set" || value == "true"
+}
+
+// markGeneratedFromDiff flags files whose added content carries a standard
+// "Code generated ... DO NOT EDIT." header
+func markGeneratedFromDiff(diff string, stats map[string]*FileStatistics) {
+	current := ""
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			current = diffHeaderPath(line)
+			continue
+		}
+		if !strings.HasPrefix(line, "+") || strings.HasPrefix(line, "+++") {
+			continue
+		}
+		if strings.Contains(line, "Code generated") && strings.Contains(line, "DO NOT EDIT") {
+			if s := stats[current]; s != nil && s.Kind == "" {
+				s.Kind = FileKindGenerated
+			}
+		}
+	}
+}
+
+// handWrittenDiff drops diff sections of non hand-written files so lockfiles
+// and generated code do not influence word and function analysis
+func handWrittenDiff(diff string, stats map[string]*FileStatistics) string {
+	var sb strings.Builder
+	keep := true
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			s := stats[diffHeaderPath(strings.TrimRight(line, "\n"))]
+			keep = s == nil || s.Kind == ""
+		}
+		if keep {
+			sb.WriteString(line)
+		}
+	}
+	return sb.String()
+}
+
+// hasHandWrittenFiles reports whether any staged file is hand-written
+func hasHandWrittenFiles(analysis *GitAnalysisResult) bool {
+	for _, stats := range analysis.FileStats {
+		if stats.Kind == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasShippedFiles reports whether any staged hand-written file is part of
+// releases, that is not export-ignore
+func hasShippedFiles(analysis *GitAnalysisResult) bool {
+	for _, stats := range analysis.FileStats {
+		if stats.Kind == "" && !stats.ExportIgnored {
+			return true
+		}
+	}
+	return false
+}
+
+// getAuxiliaryChangeAnalyses summarizes non hand-written files per kind,
+// down-weighting them when hand-written changes are also staged
+func (g *Generator) getAuxiliaryChangeAnalyses(analysis *GitAnalysisResult) []*IntelligentChangeAnalysis {
+	byKind := make(map[string][]string)
+	for filename, stats := range analysis.FileStats {
+		if stats.Kind != "" {
+			byKind[stats.Kind] = append(byKind[stats.Kind], filename)
+		}
+	}
+
+	penalty := 0
+	if hasHandWrittenFiles(analysis) {
+		penalty = downWeight
+	}
+
+	kinds := make([]string, 0, len(byKind))
+	for kind := range byKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	analyses := make([]*IntelligentChangeAnalysis, 0, len(kinds))
+	for _, kind := range kinds {
+		files := byKind[kind]
+		sort.Strings(files)
+
+		changeType, scope, description := describeAuxiliaryFiles(kind, files, g.extractFileName)
+		analyses = append(analyses, &IntelligentChangeAnalysis{
+			FilePath:    files[0],
+			ChangeType:  changeType,
+			Scope:       scope,
+			Description: description,
+			Files:       files,
+			Priority:    g.getTypePriority(changeType) + penalty,
+			Impact:      "minor changes",
+		})
+	}
+
+	return analyses
+}
+
+// describeAuxiliaryFiles picks the type, scope and description for a kind
+func describeAuxiliaryFiles(kind string, files []string, baseName func(string) string) (string, string, string) {
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, baseName(file))
+	}
+	subject := strings.Join(names, ", ")
+	if len(files) > 3 {
+		subject = fmt.Sprintf("%d files", len(files))
+	}
+
+	switch kind {
+	case FileKindLockfile:
+		return "build", "deps", "update dependency lockfiles (" + subject + ")"
+	case FileKindVendored:
+		return "build", "deps", "update vendored dependencies (" + subject + ")"
+	case FileKindGenerated:
+		return "chore", "", "regenerate generated code (" + subject + ")"
+	default:
+		return "chore", "", "update binary files (" + subject + ")"
+	}
+}
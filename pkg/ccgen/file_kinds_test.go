@@ -0,0 +1,128 @@
+package ccgen
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestClassifyPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"pkg/ccgen/generator.go", ""},
+		{"go.sum", FileKindLockfile},
+		{"web/package-lock.json", FileKindLockfile},
+		{"vendor/github.com/x/y/y.go", FileKindVendored},
+		{"web/node_modules/left-pad/index.js", FileKindVendored},
+		{"api/service.pb.go", FileKindGenerated},
+		{"apis/v1/zz_generated.deepcopy.go", FileKindGenerated},
+		{"static/app.min.js", FileKindGenerated},
+		{"docs/vendor-guide.md", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := classifyPath(tt.path); got != tt.want {
+				t.Errorf("classifyPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCheckAttr(t *testing.T) {
+	data := []byte("gen/a.go\x00linguist-generated\x00set\x00gen/a.go\x00linguist-vendored\x00unspecified\x00b.go\x00linguist-generated\x00unset\x00")
+	got := parseCheckAttr(data)
+	if got["gen/a.go"]["linguist-generated"] != "set" || got["b.go"]["linguist-generated"] != "unset" {
+		t.Errorf("parseCheckAttr() = %v", got)
+	}
+}
+
+func TestLockfileDoesNotDriveSubject(t *testing.T) {
+	backend := &fakeBackend{
+		files: []StagedFile{
+			{Path: "go.sum", Status: "M", Additions: 400, Deletions: 380},
+			{Path: "gen/api.go", Status: "M", Additions: 200, Deletions: 10},
+			{Path: "assets/logo.png", Status: "M", Binary: true},
+			{Path: "pkg/jira/manager.go", Status: "M", Additions: 12, Deletions: 2},
+		},
+		diff: "diff --git a/go.sum b/go.sum\n+fix bug in module hash\n" +
+			"diff --git a/gen/api.go b/gen/api.go\n+// Code generated by oapi-codegen. DO NOT EDIT.\n" +
+			"diff --git a/pkg/jira/manager.go b/pkg/jira/manager.go\n@@ -1 +1 @@ func (m *Manager) Load() {\n+return nil\n",
+	}
+
+	g := New(Options{Backend: backend, Output: io.Discard})
+	result, err := g.performAdvancedGitAnalysis(context.Background())
+	if err != nil {
+		t.Fatalf("performAdvancedGitAnalysis() error = %v", err)
+	}
+
+	kinds := map[string]string{
+		"go.sum":              FileKindLockfile,
+		"gen/api.go":          FileKindGenerated,
+		"assets/logo.png":     FileKindBinary,
+		"pkg/jira/manager.go": "",
+	}
+	for path, want := range kinds {
+		if got := result.FileStats[path].Kind; got != want {
+			t.Errorf("%s kind = %q, want %q", path, got, want)
+		}
+	}
+	if strings.Contains(result.WordDiffContent, "bug") {
+		t.Errorf("lockfile content leaked into word diff: %q", result.WordDiffContent)
+	}
+
+	message := g.generateClaudeStyleCommitMessageWithPatterns(g.getAdvancedChangeAnalyses(result), nil)
+	subject := strings.SplitN(message, "\n", 2)[0]
+	if !strings.Contains(subject, "jira") {
+		t.Errorf("expected hand-written change to drive the subject, got %q", subject)
+	}
+}
+
+func TestGitAttributesWeighting(t *testing.T) {
+	backend := &fakeBackend{
+		files: []StagedFile{
+			{Path: "third_party/parser/parser.go", Status: "M", Additions: 30, Deletions: 4},
+			{Path: "assets/bundle.js", Status: "M", Additions: 900, Deletions: 850},
+			{Path: ".github/workflows/ci.yml", Status: "M", Additions: 150, Deletions: 2},
+			{Path: "pkg/jira/manager.go", Status: "M", Additions: 12, Deletions: 2},
+		},
+		attrs: map[string]map[string]string{
+			"third_party/parser/parser.go": {"linguist-vendored": "unset"},
+			"assets/bundle.js":             {"linguist-generated": "true"},
+			".github/workflows/ci.yml":     {"export-ignore": "set"},
+		},
+	}
+
+	g := New(Options{Backend: backend, Output: io.Discard})
+	result, err := g.performAdvancedGitAnalysis(context.Background())
+	if err != nil {
+		t.Fatalf("performAdvancedGitAnalysis() error = %v", err)
+	}
+	if kind := result.FileStats["third_party/parser/parser.go"].Kind; kind != "" {
+		t.Errorf("-linguist-vendored file kind = %q, want hand-written", kind)
+	}
+	if kind := result.FileStats["assets/bundle.js"].Kind; kind != FileKindGenerated {
+		t.Errorf("linguist-generated file kind = %q, want %q", kind, FileKindGenerated)
+	}
+	if !result.FileStats[".github/workflows/ci.yml"].ExportIgnored {
+		t.Error("export-ignore file not flagged")
+	}
+
+	analyses := g.getAdvancedChangeAnalyses(result)
+	sortAnalyses(analyses)
+	if primary := analyses[0].FilePath; primary == ".github/workflows/ci.yml" || primary == "assets/bundle.js" {
+		t.Errorf("primary change is %s, want a shipped hand-written file", primary)
+	}
+
+	// With nothing shipped, export-ignore files lead as usual
+	result.FileStats["pkg/jira/manager.go"].ExportIgnored = true
+	result.FileStats["third_party/parser/parser.go"].ExportIgnored = true
+	analyses = g.getAdvancedChangeAnalyses(result)
+	sortAnalyses(analyses)
+	if analyses[0].Scope != "ci" {
+		t.Errorf("primary scope = %q, want ci", analyses[0].Scope)
+	}
+}
@@ -1,15 +1,20 @@
 // Package ccgen provides core commit message generation functionality
-// shared between cc and ccc commands
+// shared by the ccg, ccdo and fcgh commands, which link it directly
 package ccgen
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
 
-	"github.com/atotto/clipboard"
 	"github.com/greenstevester/fast-cc-git-hooks/internal/banner"
+	"github.com/greenstevester/fast-cc-git-hooks/internal/clipboard"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
 )
 
 const (
@@ -31,13 +36,109 @@ type JiraManager interface {
 	GetCurrentJiraTicket() (string, error)
 }
 
+// ScopeMapper maps repository paths to scopes derived from the project
+// structure
+type ScopeMapper interface {
+	// Scope returns the scope of a path, or "" when none is derived for it
+	Scope(path string) string
+}
+
+// ScopeSource provides the scope pinned for generated messages
+type ScopeSource interface {
+	GetScope() (string, error)
+}
+
+// CoAuthorSource provides the co-authors credited in generated messages
+type CoAuthorSource interface {
+	GetCoAuthors() ([]string, error)
+}
+
 // Options configures the commit generation behavior
 type Options struct {
-	NoVerify    bool
-	Execute     bool
-	Copy        bool
-	Verbose     bool
+	NoVerify bool
+	Execute  bool
+	Copy     bool
+	Verbose  bool
+	// StagedOnly analyzes the index as-is instead of running `git add .` first.
+	StagedOnly bool
+	// Output receives analysis output (defaults to os.Stdout).
+	Output io.Writer
+	// Progress reports the git commands run during analysis (nil reports
+	// nothing; see NewProgress).
+	Progress Progress
+	// Backend provides repository data (defaults to an exec-based git backend).
+	Backend GitBackend
+	// MaxFiles, MaxDiffBytes and MaxFileDiffBytes bound analysis of large
+	// changesets (zero uses DefaultMaxFiles, DefaultMaxDiffBytes, DefaultMaxFileDiffBytes).
+	MaxFiles         int
+	MaxDiffBytes     int64
+	MaxFileDiffBytes int64
+	// NoCache disables reuse of a previous analysis of the same staged tree.
+	NoCache bool
+	// CacheDir holds analysis caches, one directory per repository (empty
+	// keeps them in the repository's git directory).
+	CacheDir    string
 	JiraManager JiraManager
+	// Scopes takes the scope of files it maps from the project structure
+	// instead of guessing it from the path (nil guesses for every file).
+	Scopes ScopeMapper
+	// CoAuthors adds a Co-authored-by trailer for each current co-author
+	// (nil adds none).
+	CoAuthors CoAuthorSource
+	// Type and Scope replace the inferred type and scope of every change
+	// (empty infers them). PinnedScope supplies the scope when Scope is empty
+	// (nil, or no pinned scope, infers it).
+	Type        string
+	Scope       string
+	PinnedScope ScopeSource
+	// TicketLookup fetches the current JIRA ticket's summary for the commit
+	// body (nil disables it).
+	TicketLookup TicketLookup
+	// TicketPlacement puts the current ticket at the start or end of the
+	// description or in a "Refs:" footer (TicketAtStart when empty).
+	TicketPlacement string
+	// ExcludeTicketFromLength leaves the current ticket out of the subject
+	// length budget, so tickets do not force shorter descriptions.
+	ExcludeTicketFromLength bool
+	// SignOff commits with -s so the message gets a Signed-off-by trailer.
+	SignOff bool
+	// NoSign commits with --no-gpg-sign even when commit.gpgsign is set.
+	NoSign bool
+	// SmartCommit holds JIRA smart commit commands appended for the current
+	// ticket (empty appends nothing).
+	SmartCommit jira.SmartCommit
+	// Semantic classifies the staged diff with semantic plugins (nil disables it).
+	Semantic *semantic.SemanticAnalyzer
+	// Explain prints every plugin result and why the primary change won.
+	Explain bool
+	// MinConfidence is the plugin confidence below which the user is asked for
+	// the commit subject instead of guessing (zero never asks).
+	MinConfidence float64
+	// Input supplies answers to prompts (defaults to os.Stdin).
+	Input io.Reader
+	// HotspotWindow and HotspotThreshold configure hotspot detection for
+	// semantic plugins: a file touched by at least HotspotThreshold of the last
+	// HotspotWindow commits is a hotspot (zero uses the defaults).
+	HotspotWindow    int
+	HotspotThreshold int
+	// HistoryWindow and HistorySuggestions configure the similar previous
+	// commits offered for wording: up to HistorySuggestions of the last
+	// HistoryWindow commits (zero uses the defaults). NoHistory offers none.
+	HistoryWindow      int
+	HistorySuggestions int
+	NoHistory          bool
+	// BreakingAPI compares the exported API of staged Go files with HEAD and
+	// marks incompatible changes breaking: "!" and a BREAKING CHANGE footer.
+	BreakingAPI bool
+	// CoverageHints notes in the body when production code changes without
+	// test changes, and which documentation was updated.
+	CoverageHints bool
+	// Template rewrites generated messages with variables from repository
+	// metadata (nil leaves them as generated).
+	Template *MessageTemplate
+	// OnBypass is called with the message after a commit is created with
+	// NoVerify, so hook bypasses can be reported (nil does nothing).
+	OnBypass func(message string)
 }
 
 // Result contains the generated commit message and any additional information
@@ -46,153 +147,259 @@ type Result struct {
 	Changes    []ChangeType
 	GitCommand string
 	HasChanges bool
+	// Confidence is the semantic plugin confidence (zero without plugin analysis)
+	Confidence float64
+	// Suggestions are previous commits that touched similar files, most
+	// similar first
+	Suggestions []HistorySuggestion
 }
 
 // Generator handles commit message generation
 type Generator struct {
-	options Options
+	options  Options
+	out      io.Writer
+	in       io.Reader
+	progress Progress
+	backend  GitBackend
 }
 
 // New creates a new commit message generator with the given options
 func New(opts Options) *Generator {
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	in := opts.Input
+	if in == nil {
+		in = os.Stdin
+	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = quietProgress{}
+	}
+	backend := opts.Backend
+	if backend == nil {
+		backend = NewExecBackend("")
+	}
 	return &Generator{
-		options: opts,
+		options:  opts,
+		out:      out,
+		in:       in,
+		progress: progress,
+		backend:  backend,
 	}
 }
 
-// Generate analyzes the repository and generates a commit message
-func (g *Generator) Generate() (*Result, error) {
-	fmt.Println()
+// Generate analyzes the repository and generates a commit message. Each git
+// command is bounded by the backend's timeout, and generation stops with
+// ctx's error once ctx is done.
+func (g *Generator) Generate(ctx context.Context) (*Result, error) {
+	fmt.Fprintln(g.out)
 
 	// Check if we're in a git repo
-	fmt.Printf("Running `git rev-parse --git-dir`")
-	if !g.isGitRepo() {
-		fmt.Println(" ❌")
-		return nil, fmt.Errorf("not a git repository")
+	g.progress.Start("Running `git rev-parse --git-dir`")
+	if !g.isGitRepo(ctx) {
+		if ctx.Err() != nil {
+			g.progress.Done(ctx.Err())
+			return nil, ctx.Err()
+		}
+		err := fmt.Errorf("not a git repository")
+		g.progress.Done(err)
+		return nil, err
 	}
-	fmt.Println(" ✅")
+	g.progress.Done(nil)
 
 	// Get git status
-	fmt.Printf("Running `git status --porcelain`")
-	status, err := g.getGitStatus()
+	g.progress.Start("Running `git status --porcelain`")
+	status, err := g.getGitStatus(ctx)
+	g.progress.Done(err)
 	if err != nil {
-		fmt.Println(" ❌")
 		return nil, fmt.Errorf("failed to get git status: %w", err)
 	}
-	fmt.Println(" ✅")
 
 	if g.options.Verbose {
-		fmt.Println("\n**Git status output:**")
-		fmt.Printf("```\n%s```\n", status)
+		fmt.Fprintln(g.out, "\n**Git status output:**")
+		fmt.Fprintf(g.out, "```\n%s```\n", status)
 	}
 
-	// Add all changes
-	fmt.Printf("Running `git add .`")
-	if addErr := g.addAllChanges(); addErr != nil {
-		fmt.Println(" ❌")
-		return nil, fmt.Errorf("failed to add changes: %w", addErr)
+	// Add all changes unless we were asked to work with the index as-is
+	if !g.options.StagedOnly {
+		g.progress.Start("Running `git add .`")
+		addErr := g.addAllChanges(ctx)
+		g.progress.Done(addErr)
+		if addErr != nil {
+			return nil, fmt.Errorf("failed to add changes: %w", addErr)
+		}
 	}
-	fmt.Println(" ✅")
 
-	fmt.Println()
+	fmt.Fprintln(g.out)
 	// Perform advanced git analysis using comprehensive algorithm
 	if banner.UseASCII() {
-		fmt.Println("## Performing Advanced Git Analysis")
+		fmt.Fprintln(g.out, "## Performing Advanced Git Analysis")
 	} else {
-		fmt.Println("## 🔬 Performing Advanced Git Analysis")
+		fmt.Fprintln(g.out, "## 🔬 Performing Advanced Git Analysis")
 	}
-	fmt.Println()
+	fmt.Fprintln(g.out)
 
 	// Use advanced git analysis algorithm
-	gitAnalysis, err := g.performAdvancedGitAnalysis()
+	gitAnalysis, err := g.cachedGitAnalysis(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("advanced git analysis failed: %w", err)
 	}
 
 	// Check if there are any changes
 	if gitAnalysis.TotalFiles == 0 && strings.TrimSpace(gitAnalysis.StagedDiff) == "" {
-		fmt.Println("\n**No changes detected** - nothing to commit")
+		fmt.Fprintln(g.out, "\n**No changes detected** - nothing to commit")
 		return &Result{HasChanges: false}, nil
 	}
 
 	// Convert advanced analysis to intelligent analyses
-	intelligentAnalyses := g.getAdvancedChangeAnalyses(gitAnalysis)
+	var intelligentAnalyses []*IntelligentChangeAnalysis
+	if gitAnalysis.Degraded {
+		intelligentAnalyses = g.getDegradedChangeAnalyses(gitAnalysis)
+	} else {
+		intelligentAnalyses = g.getAdvancedChangeAnalyses(gitAnalysis)
+	}
+	sortAnalyses(intelligentAnalyses)
 
 	// Display advanced analysis results
-	fmt.Printf("**Advanced Analysis Results:**\n")
-	fmt.Printf("- Total files changed: %d\n", gitAnalysis.TotalFiles)
-	fmt.Printf("- Total additions: +%d lines\n", gitAnalysis.TotalAdditions)
-	fmt.Printf("- Total deletions: -%d lines\n", gitAnalysis.TotalDeletions)
+	fmt.Fprintf(g.out, "**Advanced Analysis Results:**\n")
+	fmt.Fprintf(g.out, "- Total files changed: %d\n", gitAnalysis.TotalFiles)
+	fmt.Fprintf(g.out, "- Total additions: +%d lines\n", gitAnalysis.TotalAdditions)
+	fmt.Fprintf(g.out, "- Total deletions: -%d lines\n", gitAnalysis.TotalDeletions)
 
 	// Display directory statistics
 	if len(gitAnalysis.DirStats) > 0 {
-		fmt.Printf("- Directory distribution: ")
+		fmt.Fprintf(g.out, "- Directory distribution: ")
 		var dirParts []string
-		for dir, percent := range gitAnalysis.DirStats {
-			dirParts = append(dirParts, fmt.Sprintf("%s (%.1f%%)", dir, percent))
+		for _, dir := range sortedKeys(gitAnalysis.DirStats) {
+			dirParts = append(dirParts, fmt.Sprintf("%s (%.1f%%)", dir, gitAnalysis.DirStats[dir]))
 		}
-		fmt.Printf("%s\n", strings.Join(dirParts, ", "))
+		fmt.Fprintf(g.out, "%s\n", strings.Join(dirParts, ", "))
 	}
 
 	// Display file summaries
 	if len(gitAnalysis.FileSummaries) > 0 {
-		fmt.Printf("- File operations: %s\n", strings.Join(gitAnalysis.FileSummaries, ", "))
+		fmt.Fprintf(g.out, "- File operations: %s\n", strings.Join(gitAnalysis.FileSummaries, ", "))
 	}
 
 	// Display modified functions
 	if len(gitAnalysis.ModifiedFunctions) > 0 {
-		fmt.Printf("- Modified functions: %s\n", strings.Join(gitAnalysis.ModifiedFunctions, ", "))
+		fmt.Fprintf(g.out, "- Modified functions: %s\n", strings.Join(gitAnalysis.ModifiedFunctions, ", "))
+	}
+
+	// Report any analysis budget that was hit
+	if gitAnalysis.Degraded {
+		fmt.Fprintf(g.out, "- Large changeset: summarized by scope from statistics only\n")
+	}
+	if gitAnalysis.DiffTruncated {
+		fmt.Fprintf(g.out, "- Diff truncated: analysis budget exhausted, remaining files use statistics only\n")
+	}
+	if len(gitAnalysis.TruncatedFiles) > 0 {
+		fmt.Fprintf(g.out, "- Large file diffs truncated: %d file(s)\n", len(gitAnalysis.TruncatedFiles))
 	}
 
 	if gitAnalysis.CommitPatterns != nil && len(gitAnalysis.RecentCommits) > 0 {
-		fmt.Printf("- Recent commit style: %s\n", gitAnalysis.CommitPatterns.PreferredStyle)
-		fmt.Printf("- Average commit length: %d chars\n", gitAnalysis.CommitPatterns.AverageLength)
+		fmt.Fprintf(g.out, "- Recent commit style: %s\n", gitAnalysis.CommitPatterns.PreferredStyle)
+		fmt.Fprintf(g.out, "- Average commit length: %d chars\n", gitAnalysis.CommitPatterns.AverageLength)
 	}
-	fmt.Printf("\n**Found %d change type(s):**\n\n", len(intelligentAnalyses))
+
+	// The type and scope the user chose replace the inferred ones
+	g.applyOverrides(intelligentAnalyses)
+	fmt.Fprintf(g.out, "\n**Found %d change type(s):**\n\n", len(intelligentAnalyses))
 
 	for i, analysis := range intelligentAnalyses {
-		fmt.Printf("%d. **%s", i+1, analysis.ChangeType)
+		fmt.Fprintf(g.out, "%d. **%s", i+1, analysis.ChangeType)
 		if analysis.Scope != "" {
-			fmt.Printf("(%s)", analysis.Scope)
+			fmt.Fprintf(g.out, "(%s)", analysis.Scope)
 		}
-		fmt.Printf("**: %s", analysis.Description)
+		fmt.Fprintf(g.out, "**: %s", analysis.Description)
 		if len(analysis.Files) > 0 {
-			fmt.Printf("\n   - File: `%s`", analysis.Files[0])
+			fmt.Fprintf(g.out, "\n   - File: `%s`", analysis.Files[0])
 		}
 		if analysis.Impact != "" {
-			fmt.Printf("\n   - Impact: %s", analysis.Impact)
+			fmt.Fprintf(g.out, "\n   - Impact: %s", analysis.Impact)
 		}
 		if g.options.Verbose {
 			// Show detailed statistics in verbose mode
 			if stat, exists := gitAnalysis.FileStats[analysis.FilePath]; exists {
-				fmt.Printf("\n   - Statistics: +%d/-%d lines, Type: %s",
+				fmt.Fprintf(g.out, "\n   - Statistics: +%d/-%d lines, Type: %s",
 					stat.Additions, stat.Deletions, stat.ChangeType)
 			}
 			if analysis.Context != "" {
-				fmt.Printf("\n   - Context: %s", analysis.Context)
+				fmt.Fprintf(g.out, "\n   - Context: %s", analysis.Context)
 			}
 			if len(analysis.Details) > 0 {
-				fmt.Printf("\n   - Details:")
+				fmt.Fprintf(g.out, "\n   - Details:")
 				for _, detail := range analysis.Details {
-					fmt.Printf("\n     • %s", detail)
+					fmt.Fprintf(g.out, "\n     • %s", detail)
 				}
 			}
 		}
-		fmt.Printf("\n\n")
+		fmt.Fprintf(g.out, "\n\n")
+	}
+
+	// Offer the wording of previous commits to similar files
+	var primaryScope string
+	if len(intelligentAnalyses) > 0 {
+		primaryScope = intelligentAnalyses[0].Scope
+	}
+	suggestions, err := g.historySuggestions(ctx, sortedKeys(gitAnalysis.FileStats), primaryScope)
+	if err != nil {
+		return nil, err
+	}
+	g.printHistorySuggestions(suggestions)
+
+	// Classify with semantic plugins when explaining or gating on confidence
+	var explanation *semantic.Explanation
+	if g.options.Semantic != nil {
+		explanation, err = g.explainChanges(ctx, gitAnalysis)
+		if err != nil {
+			return nil, err
+		}
+		if g.options.Explain {
+			g.printExplanation(explanation)
+		}
 	}
 
 	// Check for JIRA ticket
+	var ticket, ticketSummary string
 	if g.options.JiraManager != nil {
-		if ticket, err := g.options.JiraManager.GetCurrentJiraTicket(); err == nil && ticket != "" {
-			fmt.Printf("**JIRA Ticket:** `%s` (will be included in commit)\n\n", ticket)
+		if current, err := g.options.JiraManager.GetCurrentJiraTicket(); err == nil && current != "" {
+			ticket = current
+			ticketSummary = g.ticketSummary(ctx, ticket)
+			if ticketSummary != "" {
+				fmt.Fprintf(g.out, "**JIRA Ticket:** `%s` %s (will be included in commit)\n\n", ticket, ticketSummary)
+			} else {
+				fmt.Fprintf(g.out, "**JIRA Ticket:** `%s` (will be included in commit)\n\n", ticket)
+			}
 		} else {
-			fmt.Printf("**JIRA Ticket:** None set (use `cc set-jira CGC-1234` to set one)\n\n")
+			fmt.Fprintf(g.out, "**JIRA Ticket:** None set (use `cc set-jira CGC-1234` to set one)\n\n")
 		}
 	}
 
 	// Generate Claude-style commit message using repository patterns
 	message := g.generateClaudeStyleCommitMessageWithPatterns(intelligentAnalyses, gitAnalysis.CommitPatterns)
 
+	var confidence float64
+	if explanation != nil {
+		confidence = explanation.Confidence()
+		if confidence < g.options.MinConfidence {
+			message = g.confirmLowConfidence(message, confidence, suggestions)
+		}
+	}
+	if g.options.BreakingAPI {
+		message = markBreaking(message, g.goAPIBreaks(ctx, gitAnalysis))
+	}
+	if g.options.CoverageHints {
+		message = appendBodyLine(message, coverageNote(gitAnalysis.FileStats))
+	}
+	message = appendTicketSummary(message, ticket, ticketSummary)
+	message = appendBodyLine(message, g.options.SmartCommit.Format(ticket))
+	message = g.placeTicket(message, g.currentTickets())
+	message = g.applyTemplate(ctx, message, sortedKeys(gitAnalysis.FileStats))
+	message = appendTrailers(message, "Co-authored-by", g.coAuthors())
+
 	// Also maintain backward compatibility by converting to old format for result
 	changes := g.convertToLegacyFormat(intelligentAnalyses)
 
@@ -200,89 +407,119 @@ func (g *Generator) Generate() (*Result, error) {
 	gitCommand := g.buildGitCommand(message)
 
 	return &Result{
-		Message:    message,
-		Changes:    changes,
-		GitCommand: gitCommand,
-		HasChanges: true,
+		Message:     message,
+		Changes:     changes,
+		GitCommand:  gitCommand,
+		HasChanges:  true,
+		Confidence:  confidence,
+		Suggestions: suggestions,
 	}, nil
 }
 
-// ExecuteCommit commits the changes with the generated message
-func (g *Generator) ExecuteCommit(message string) error {
+// ExecuteCommit commits the changes with the generated message, signed when
+// commit.gpgsign is set unless NoSign is. The commit has no timeout, as
+// hooks and signing may wait for the user, but stops when ctx is done.
+func (g *Generator) ExecuteCommit(ctx context.Context, message string) error {
 	args := []string{"commit", "-m", message}
+	if g.options.SignOff {
+		args = append(args, "-s")
+	}
 	if g.options.NoVerify {
 		args = append(args, "--no-verify")
 	}
+	sign := readSigning(ctx)
+	if g.options.NoSign {
+		sign.enabled = false
+	}
+	args = append(args, sign.commitArgs(g.options.NoSign)...)
 
-	cmd := exec.Command("git", args...) // #nosec G204 - args are validated git commands
+	// Git's errors are shown as usual and kept to explain signing failures
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 - args are validated git commands
 	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
 
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		return sign.explainFailure(stderr.String(), err)
+	}
+	return nil
 }
 
-// CopyToClipboard copies the git command to the system clipboard
-func (g *Generator) CopyToClipboard(gitCommand string) error {
-	return clipboard.WriteAll(gitCommand)
+// CopyToClipboard copies the git command to the system clipboard and
+// returns the method used (a clipboard command or clipboard.OSC52)
+func (g *Generator) CopyToClipboard(gitCommand string) (string, error) {
+	return clipboard.Copy(gitCommand)
 }
 
-// PrintResult displays the result to the user
-func (g *Generator) PrintResult(result *Result) {
+// PrintResult displays the result to the user, committing it when Execute
+// is set
+func (g *Generator) PrintResult(ctx context.Context, result *Result) {
 	if !result.HasChanges {
-		fmt.Println("**No changes to commit**")
+		fmt.Fprintln(g.out, "**No changes to commit**")
 		return
 	}
 
 	// Display the commit message in a code block
-	fmt.Printf("```\n%s\n```\n\n", result.Message)
+	fmt.Fprintf(g.out, "```\n%s\n```\n\n", result.Message)
 
 	if g.options.Copy {
-		if err := g.CopyToClipboard(result.GitCommand); err != nil {
-			fmt.Printf("❌ Failed to copy to clipboard: %v\n", err)
-		} else {
+		method, err := g.CopyToClipboard(result.GitCommand)
+		switch {
+		case err != nil:
+			// Without a clipboard the command is shown so it can be copied by hand
+			fmt.Fprintf(g.out, "❌ Failed to copy to clipboard: %v\n", err)
+			fmt.Fprintf(g.out, "Run:\n```\n%s\n```\n\n", result.GitCommand)
+		case method == clipboard.OSC52:
+			fmt.Fprintf(g.out, "✅ Git commit command sent to the terminal clipboard (OSC 52)!\n\n")
+		default:
 			if banner.UseASCII() {
-				fmt.Printf("✅ Git commit command copied to clipboard!\n\n")
+				fmt.Fprintf(g.out, "✅ Git commit command copied to clipboard!\n\n")
 			} else {
-				fmt.Printf("✅ Git commit command copied to clipboard!\n\n")
+				fmt.Fprintf(g.out, "✅ Git commit command copied to clipboard!\n\n")
 			}
 		}
 	}
 
 	if g.options.Execute {
-		if err := g.ExecuteCommit(result.Message); err != nil {
-			fmt.Printf("❌ Failed to commit: %v\n", err)
+		if err := g.ExecuteCommit(ctx, result.Message); err != nil {
+			fmt.Fprintf(g.out, "❌ Failed to commit: %v\n", err)
 			return
 		}
-		fmt.Printf("✅ Commit created successfully!\n")
+		fmt.Fprintf(g.out, "✅ Commit created successfully!\n")
+		if g.options.NoVerify && g.options.OnBypass != nil {
+			g.options.OnBypass(result.Message)
+		}
 	}
 }
 
 // buildGitCommand builds the full git commit command string
 func (g *Generator) buildGitCommand(message string) string {
 	cmd := fmt.Sprintf("git commit -m %q", message)
+	if g.options.SignOff {
+		cmd += " -s"
+	}
 	if g.options.NoVerify {
 		cmd += " --no-verify"
 	}
+	if g.options.NoSign {
+		cmd += " --no-gpg-sign"
+	}
 	return cmd
 }
 
 // isGitRepo checks if we're in a git repository
-func (g *Generator) isGitRepo() bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	return cmd.Run() == nil
+func (g *Generator) isGitRepo(ctx context.Context) bool {
+	return g.backend.IsRepo(ctx)
 }
 
 // getGitStatus gets git status output
-func (g *Generator) getGitStatus() (string, error) {
-	cmd := exec.Command("git", "status", "--porcelain")
-	output, err := cmd.Output()
-	return string(output), err
+func (g *Generator) getGitStatus(ctx context.Context) (string, error) {
+	return g.backend.Status(ctx)
 }
 
 // addAllChanges adds all changes to staging
-func (g *Generator) addAllChanges() error {
-	cmd := exec.Command("git", "add", ".")
-	return cmd.Run()
+func (g *Generator) addAllChanges(ctx context.Context) error {
+	return g.backend.AddAll(ctx)
 }
 
 // convertToLegacyFormat converts intelligent analyses to legacy ChangeType format for compatibility
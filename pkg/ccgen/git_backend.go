@@ -0,0 +1,572 @@
+// Package ccgen - Git backend abstraction used by the commit message generator
+package ccgen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultGitTimeout bounds each git command run by ExecBackend, so a hung git
+// (waiting on a credential prompt or fsmonitor) cannot stall generation
+const DefaultGitTimeout = time.Minute
+
+// GitBackend provides the repository data the generator needs.
+// Implementations should batch work so a single generation reads the index
+// as few times as possible, and stop when ctx is done.
+type GitBackend interface {
+	// IsRepo reports whether the working directory is inside a git repository
+	IsRepo(ctx context.Context) bool
+	// Status returns `git status --porcelain` style output
+	Status(ctx context.Context) (string, error)
+	// AddAll stages all changes in the working tree
+	AddAll(ctx context.Context) error
+	// StagedFiles returns per-file status and line counts for the index
+	StagedFiles(ctx context.Context) ([]StagedFile, error)
+	// StagedDiff streams the unified diff of the index against HEAD.
+	// Callers must Close the reader; closing early stops the underlying work.
+	StagedDiff(ctx context.Context) (io.ReadCloser, error)
+	// RecentCommits returns up to n recent commits, newest first
+	RecentCommits(ctx context.Context, n int) ([]CommitInfo, error)
+	// RecentChangedFiles returns the paths touched by each of up to n recent commits
+	RecentChangedFiles(ctx context.Context, n int) ([][]string, error)
+	// RecentCommitFiles returns the subject and touched paths of up to n
+	// recent commits, newest first
+	RecentCommitFiles(ctx context.Context, n int) ([]CommitFiles, error)
+	// IndexKey identifies the staged state (staged tree and HEAD) for caching
+	IndexKey(ctx context.Context) (string, error)
+	// GitDir returns the absolute path of the repository's git directory
+	GitDir(ctx context.Context) (string, error)
+	// Branch returns the checked-out branch, or "" on a detached HEAD
+	Branch(ctx context.Context) (string, error)
+	// Author returns the identity commits are authored with, as
+	// "Name <email>"
+	Author(ctx context.Context) (string, error)
+	// PathAttributes returns the values of the given git attributes per path
+	// ("set", "unset", "unspecified" or the assigned value)
+	PathAttributes(ctx context.Context, paths []string, attrs ...string) (map[string]map[string]string, error)
+	// FileAt returns the content of a path, relative to the top level, at a
+	// revision such as HEAD, or in the index when rev is empty
+	FileAt(ctx context.Context, rev, path string) (string, error)
+}
+
+// StagedFile describes a single staged file as reported by git
+type StagedFile struct {
+	Path       string
+	Status     string // A/M/D/T, or R/C for renames and copies
+	OldPath    string // source path of a rename or copy
+	Similarity int    // rename/copy similarity percentage
+	OldMode    string
+	NewMode    string
+	Additions  int
+	Deletions  int
+	Binary     bool
+}
+
+// CommitFiles is a commit's subject and the paths it touched
+type CommitFiles struct {
+	Hash    string
+	Subject string
+	Files   []string
+}
+
+// ExecBackend implements GitBackend by shelling out to the git binary.
+// File statistics are gathered with a single `git diff --raw --numstat -z -M -C` pass.
+type ExecBackend struct {
+	// Dir is the working directory for git commands (empty means current directory)
+	Dir string
+	// Timeout bounds each git command (zero uses DefaultGitTimeout)
+	Timeout time.Duration
+}
+
+// NewExecBackend creates a git backend rooted at dir
+func NewExecBackend(dir string) *ExecBackend {
+	return &ExecBackend{Dir: dir}
+}
+
+// gitPathEnvVars are repository location variables git resolves relative to
+// the working directory of the process that set them
+var gitPathEnvVars = []string{"GIT_DIR", "GIT_WORK_TREE", "GIT_INDEX_FILE", "GIT_COMMON_DIR", "GIT_OBJECT_DIRECTORY"}
+
+// command builds a git command that honors GIT_DIR, GIT_WORK_TREE and friends
+// and is killed when ctx is done
+func (b *ExecBackend) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 - args are fixed git plumbing commands
+	cmd.Dir = b.Dir
+	cmd.Env = gitEnv(b.Dir)
+	// Processes git started (fsmonitor, credential helpers) may hold its
+	// output open after it is killed
+	cmd.WaitDelay = time.Second
+	return cmd
+}
+
+// step bounds a single git command by the backend timeout
+func (b *ExecBackend) step(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, b.timeout())
+}
+
+func (b *ExecBackend) timeout() time.Duration {
+	if b.Timeout > 0 {
+		return b.Timeout
+	}
+	return DefaultGitTimeout
+}
+
+// interruption explains why a git command was stopped: the caller's context
+// ended, or the command took longer than the timeout. It is nil when the
+// command was not stopped.
+func (b *ExecBackend) interruption(ctx, step context.Context, args []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if errors.Is(step.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("git %s timed out after %s", subcommand(args), b.timeout())
+	}
+	return nil
+}
+
+// subcommand returns the git subcommand of args, skipping "-c name=value" options
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-c" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
+
+// gitEnv returns the environment for git commands run in dir. Relative
+// repository variables (set by tools like pre-commit or GUI clients) are made
+// absolute so they still point at the same repository from another directory.
+// A nil result inherits the current environment unchanged.
+func gitEnv(dir string) []string {
+	if dir == "" {
+		return nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+
+	env := os.Environ()
+	for i, entry := range env {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || value == "" || filepath.IsAbs(value) {
+			continue
+		}
+		for _, pathVar := range gitPathEnvVars {
+			if name == pathVar {
+				env[i] = name + "=" + filepath.Join(wd, value)
+			}
+		}
+	}
+	return env
+}
+
+// run executes a git command and returns its stdout
+func (b *ExecBackend) run(ctx context.Context, args ...string) ([]byte, error) {
+	return b.runWithInput(ctx, nil, args...)
+}
+
+// runWithInput executes a git command reading stdin from in and returns its stdout
+func (b *ExecBackend) runWithInput(ctx context.Context, in io.Reader, args ...string) ([]byte, error) {
+	step, cancel := b.step(ctx)
+	defer cancel()
+
+	cmd := b.command(step, args...)
+	cmd.Stdin = in
+	output, err := cmd.Output()
+	if err != nil {
+		if interrupted := b.interruption(ctx, step, args); interrupted != nil {
+			return nil, interrupted
+		}
+	}
+	return output, err
+}
+
+// IsRepo checks if we're in a git repository
+func (b *ExecBackend) IsRepo(ctx context.Context) bool {
+	_, err := b.run(ctx, "rev-parse", "--git-dir")
+	return err == nil
+}
+
+// Status gets git status output
+func (b *ExecBackend) Status(ctx context.Context) (string, error) {
+	output, err := b.run(ctx, "status", "--porcelain")
+	return string(output), err
+}
+
+// AddAll adds all changes to staging. With GIT_WORK_TREE set the working
+// directory may be outside the work tree, so the whole tree is added instead of "."
+func (b *ExecBackend) AddAll(ctx context.Context) error {
+	pathspec := "."
+	if os.Getenv("GIT_WORK_TREE") != "" {
+		pathspec = "--all"
+	}
+	_, err := b.run(ctx, "add", pathspec)
+	return err
+}
+
+// StagedFiles implements: git diff --cached --raw --numstat -z -M -C
+func (b *ExecBackend) StagedFiles(ctx context.Context) ([]StagedFile, error) {
+	output, err := b.run(ctx, "diff", "--cached", "--raw", "--numstat", "-z", "-M", "-C")
+	if err != nil {
+		return nil, fmt.Errorf("git diff --raw --numstat: %w", err)
+	}
+	return parseRawNumstat(output)
+}
+
+// StagedDiff implements: git diff --cached -M -C (streamed from the git process)
+func (b *ExecBackend) StagedDiff(ctx context.Context) (io.ReadCloser, error) {
+	args := []string{"diff", "--cached", "-M", "-C"}
+	step, cancel := b.step(ctx)
+	cmd := b.command(step, args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("git diff --cached: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("git diff --cached: %w", err)
+	}
+
+	interrupted := func() error { return b.interruption(ctx, step, args) }
+	return &cmdReader{ReadCloser: stdout, cmd: cmd, cancel: cancel, interrupted: interrupted}, nil
+}
+
+// cmdReader streams a command's stdout and reaps the process on Close
+type cmdReader struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	cancel context.CancelFunc
+	// interrupted reports why the command was stopped early, if it was
+	interrupted func() error
+	done        bool
+}
+
+// Read reads from the command output, noting when the stream is exhausted.
+// A killed command also ends its output, which is reported as an error
+// rather than the end of the stream.
+func (r *cmdReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err != nil {
+		if interrupted := r.interrupted(); interrupted != nil {
+			return n, interrupted
+		}
+	}
+	if err == io.EOF {
+		r.done = true
+	}
+	return n, err
+}
+
+// Close stops the command if output was not fully consumed and waits for it
+func (r *cmdReader) Close() error {
+	defer r.cancel()
+	if !r.done && r.cmd.Process != nil {
+		_ = r.cmd.Process.Kill()
+	}
+	_ = r.ReadCloser.Close()
+	err := r.cmd.Wait()
+	if !r.done {
+		// Killed on purpose after the caller stopped reading
+		return nil
+	}
+	return err
+}
+
+// RecentCommits implements: git log --oneline -n
+func (b *ExecBackend) RecentCommits(ctx context.Context, n int) ([]CommitInfo, error) {
+	output, err := b.run(ctx, "log", "--oneline", fmt.Sprintf("-%d", n))
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	return parseOnelineLog(string(output)), nil
+}
+
+// RecentChangedFiles implements: git log -n <n> --name-only --format=%x1e
+func (b *ExecBackend) RecentChangedFiles(ctx context.Context, n int) ([][]string, error) {
+	output, err := b.run(ctx, "-c", "core.quotePath=false", "log", fmt.Sprintf("-%d", n), "--name-only", "--format=%x1e")
+	if err != nil {
+		return nil, fmt.Errorf("git log --name-only: %w", err)
+	}
+	return parseNameOnlyLog(string(output)), nil
+}
+
+// parseNameOnlyLog splits `git log --name-only --format=%x1e` output into the
+// paths of each commit; commits without file changes yield empty entries
+func parseNameOnlyLog(output string) [][]string {
+	var commits [][]string
+	for _, record := range strings.Split(output, "\x1e")[1:] {
+		var paths []string
+		for _, line := range strings.Split(record, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				paths = append(paths, line)
+			}
+		}
+		commits = append(commits, paths)
+	}
+	return commits
+}
+
+// RecentCommitFiles implements: git log -n <n> --name-only --format=%x1e%h%x1f%s
+func (b *ExecBackend) RecentCommitFiles(ctx context.Context, n int) ([]CommitFiles, error) {
+	output, err := b.run(ctx, "-c", "core.quotePath=false", "log", fmt.Sprintf("-%d", n), "--name-only", "--format=%x1e%h%x1f%s")
+	if err != nil {
+		return nil, fmt.Errorf("git log --name-only: %w", err)
+	}
+	return parseCommitFilesLog(string(output)), nil
+}
+
+// parseCommitFilesLog splits `git log --name-only --format=%x1e%h%x1f%s`
+// output into each commit's hash, subject and paths
+func parseCommitFilesLog(output string) []CommitFiles {
+	var commits []CommitFiles
+	for _, record := range strings.Split(output, "\x1e")[1:] {
+		header, files, _ := strings.Cut(record, "\n")
+		hash, subject, _ := strings.Cut(header, "\x1f")
+		commit := CommitFiles{Hash: hash, Subject: strings.TrimSpace(subject)}
+		for _, line := range strings.Split(files, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				commit.Files = append(commit.Files, line)
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// IndexKey implements: git write-tree plus git rev-parse HEAD
+func (b *ExecBackend) IndexKey(ctx context.Context) (string, error) {
+	tree, err := b.run(ctx, "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("git write-tree: %w", err)
+	}
+
+	// A repository without commits has no HEAD; the tree alone is the key
+	head, err := b.run(ctx, "rev-parse", "--verify", "-q", "HEAD")
+	if err != nil {
+		head = nil
+	}
+
+	return strings.TrimSpace(string(tree)) + ":" + strings.TrimSpace(string(head)), nil
+}
+
+// GitDir implements: git rev-parse --absolute-git-dir
+func (b *ExecBackend) GitDir(ctx context.Context) (string, error) {
+	output, err := b.run(ctx, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse --absolute-git-dir: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Branch implements: git symbolic-ref --short -q HEAD
+func (b *ExecBackend) Branch(ctx context.Context) (string, error) {
+	output, err := b.run(ctx, "symbolic-ref", "--short", "-q", "HEAD")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil // detached HEAD
+		}
+		return "", fmt.Errorf("git symbolic-ref: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// FileAt implements: git show <rev>:<path>
+func (b *ExecBackend) FileAt(ctx context.Context, rev, path string) (string, error) {
+	output, err := b.run(ctx, "show", rev+":"+path)
+	if err != nil {
+		return "", fmt.Errorf("git show %s:%s: %w", rev, path, err)
+	}
+	return string(output), nil
+}
+
+// Author implements: git var GIT_AUTHOR_IDENT, without the timestamp
+func (b *ExecBackend) Author(ctx context.Context) (string, error) {
+	output, err := b.run(ctx, "var", "GIT_AUTHOR_IDENT")
+	if err != nil {
+		return "", fmt.Errorf("git var GIT_AUTHOR_IDENT: %w", err)
+	}
+	ident := strings.TrimSpace(string(output))
+	if end := strings.LastIndex(ident, ">"); end >= 0 {
+		ident = ident[:end+1]
+	}
+	return ident, nil
+}
+
+// PathAttributes implements: git check-attr -z --stdin <attrs>
+func (b *ExecBackend) PathAttributes(ctx context.Context, paths []string, attrs ...string) (map[string]map[string]string, error) {
+	if len(paths) == 0 || len(attrs) == 0 {
+		return map[string]map[string]string{}, nil
+	}
+
+	// Diff paths are relative to the top level; check-attr resolves paths
+	// against the working directory, so prefix them with the way back up
+	cdup, err := b.run(ctx, "rev-parse", "--show-cdup")
+	if err != nil {
+		return nil, fmt.Errorf("git rev-parse --show-cdup: %w", err)
+	}
+	prefix := strings.TrimSpace(string(cdup))
+
+	var stdin strings.Builder
+	for _, path := range paths {
+		stdin.WriteString(prefix + path)
+		stdin.WriteByte(0)
+	}
+
+	args := append([]string{"check-attr", "-z", "--stdin"}, attrs...)
+	output, err := b.runWithInput(ctx, strings.NewReader(stdin.String()), args...)
+	if err != nil {
+		return nil, fmt.Errorf("git check-attr: %w", err)
+	}
+
+	attributes := parseCheckAttr(output)
+	if prefix == "" {
+		return attributes, nil
+	}
+
+	result := make(map[string]map[string]string, len(attributes))
+	for path, values := range attributes {
+		result[strings.TrimPrefix(path, prefix)] = values
+	}
+	return result, nil
+}
+
+// parseCheckAttr parses `git check-attr -z` output ("<path>\0<attr>\0<value>\0" records)
+func parseCheckAttr(data []byte) map[string]map[string]string {
+	fields := strings.Split(string(data), "\x00")
+	result := make(map[string]map[string]string)
+
+	for i := 0; i+2 < len(fields); i += 3 {
+		path, attr, value := fields[i], fields[i+1], fields[i+2]
+		if result[path] == nil {
+			result[path] = make(map[string]string)
+		}
+		result[path][attr] = value
+	}
+
+	return result
+}
+
+// parseRawNumstat parses the NUL-separated output of `git diff --raw --numstat -z`.
+// Raw records come first (":<old mode> <new mode> <old sha> <new sha> <status>\0<path>\0"),
+// followed by numstat records ("<added>\t<deleted>\t<path>\0"). Renames and copies
+// carry both paths: "...R<score>\0<old>\0<new>\0" and "<added>\t<deleted>\t\0<old>\0<new>\0".
+func parseRawNumstat(data []byte) ([]StagedFile, error) {
+	fields := bytes.Split(data, []byte{0})
+
+	var files []StagedFile
+	index := make(map[string]int)
+
+	for i := 0; i < len(fields); i++ {
+		field := string(fields[i])
+		if field == "" {
+			continue
+		}
+
+		if strings.HasPrefix(field, ":") {
+			meta := strings.Fields(field[1:])
+			if len(meta) < 5 || i+1 >= len(fields) {
+				return nil, fmt.Errorf("malformed raw diff record: %q", field)
+			}
+			file := StagedFile{
+				Status:  meta[4][:1],
+				OldMode: meta[0],
+				NewMode: meta[1],
+			}
+			i++
+			file.Path = string(fields[i])
+
+			if file.Status == "R" || file.Status == "C" {
+				if i+1 >= len(fields) {
+					return nil, fmt.Errorf("malformed rename record: %q", field)
+				}
+				file.Similarity, _ = strconv.Atoi(meta[4][1:])
+				file.OldPath = file.Path
+				i++
+				file.Path = string(fields[i])
+			}
+
+			index[file.Path] = len(files)
+			files = append(files, file)
+			continue
+		}
+
+		parts := strings.SplitN(field, "\t", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("malformed numstat record: %q", field)
+		}
+
+		// An empty path means the old and new paths follow as separate fields
+		path := parts[2]
+		if path == "" {
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("malformed rename numstat record: %q", field)
+			}
+			path = string(fields[i+2])
+			i += 2
+		}
+
+		pos, ok := index[path]
+		if !ok {
+			index[path] = len(files)
+			pos = len(files)
+			files = append(files, StagedFile{Path: path, Status: "M"})
+		}
+
+		// Binary files report "-" for both counts
+		if parts[0] == "-" && parts[1] == "-" {
+			files[pos].Binary = true
+			continue
+		}
+
+		additions, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("parsing additions for %s: %w", path, err)
+		}
+		deletions, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("parsing deletions for %s: %w", path, err)
+		}
+		files[pos].Additions = additions
+		files[pos].Deletions = deletions
+	}
+
+	return files, nil
+}
+
+// parseOnelineLog parses git log --oneline output
+func parseOnelineLog(output string) []CommitInfo {
+	var commits []CommitInfo
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) >= 2 {
+			commits = append(commits, CommitInfo{
+				Hash:    parts[0],
+				Message: parts[1],
+			})
+		}
+	}
+
+	return commits
+}
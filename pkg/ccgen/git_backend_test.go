@@ -0,0 +1,318 @@
+package ccgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBackend is an in-memory GitBackend for tests
+type fakeBackend struct {
+	files   []StagedFile
+	diff    string
+	commits []CommitInfo
+	addErr  error
+	added   bool
+	key     string
+	gitDir  string
+	reads   int
+	attrs   map[string]map[string]string
+	history [][]string
+	logs    int
+	recent  []CommitFiles
+	branch  string
+	author  string
+	// contents are file contents by "rev:path", as in git show
+	contents map[string]string
+}
+
+func (f *fakeBackend) IsRepo(context.Context) bool            { return true }
+func (f *fakeBackend) Status(context.Context) (string, error) { return "", nil }
+func (f *fakeBackend) AddAll(context.Context) error           { f.added = true; return f.addErr }
+func (f *fakeBackend) StagedDiff(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(f.diff)), nil
+}
+func (f *fakeBackend) StagedFiles(context.Context) ([]StagedFile, error) {
+	f.reads++
+	return f.files, nil
+}
+func (f *fakeBackend) IndexKey(context.Context) (string, error) { return f.key, nil }
+func (f *fakeBackend) GitDir(context.Context) (string, error)   { return f.gitDir, nil }
+func (f *fakeBackend) Branch(context.Context) (string, error)   { return f.branch, nil }
+func (f *fakeBackend) Author(context.Context) (string, error)   { return f.author, nil }
+func (f *fakeBackend) PathAttributes(_ context.Context, paths []string, attrs ...string) (map[string]map[string]string, error) {
+	return f.attrs, nil
+}
+
+func (f *fakeBackend) FileAt(_ context.Context, rev, path string) (string, error) {
+	content, ok := f.contents[rev+":"+path]
+	if !ok {
+		return "", fmt.Errorf("no %s:%s", rev, path)
+	}
+	return content, nil
+}
+
+func (f *fakeBackend) RecentChangedFiles(_ context.Context, n int) ([][]string, error) {
+	f.logs++
+	if len(f.history) > n {
+		return f.history[:n], nil
+	}
+	return f.history, nil
+}
+
+func (f *fakeBackend) RecentCommitFiles(_ context.Context, n int) ([]CommitFiles, error) {
+	if len(f.recent) > n {
+		return f.recent[:n], nil
+	}
+	return f.recent, nil
+}
+
+func (f *fakeBackend) RecentCommits(_ context.Context, n int) ([]CommitInfo, error) {
+	if len(f.commits) > n {
+		return f.commits[:n], nil
+	}
+	return f.commits, nil
+}
+
+func TestParseRawNumstat(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []StagedFile
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  nil,
+		},
+		{
+			name: "modified added and binary",
+			input: ":100644 100644 b77b4eb 04ec35a M\x00b.txt\x00" +
+				":000000 100644 0000000 bdc955b A\x00bin.dat\x00" +
+				"1\t0\tb.txt\x00-\t-\tbin.dat\x00",
+			want: []StagedFile{
+				{Path: "b.txt", Status: "M", OldMode: "100644", NewMode: "100644", Additions: 1},
+				{Path: "bin.dat", Status: "A", OldMode: "000000", NewMode: "100644", Binary: true},
+			},
+		},
+		{
+			name:  "path with spaces",
+			input: ":100644 100644 aaa bbb M\x00dir/my file.go\x003\t2\tdir/my file.go\x00",
+			want: []StagedFile{
+				{Path: "dir/my file.go", Status: "M", OldMode: "100644", NewMode: "100644", Additions: 3, Deletions: 2},
+			},
+		},
+		{
+			name: "rename and copy",
+			input: ":100644 100644 96cc558 96cc558 C100\x00a.txt\x00copy.txt\x00" +
+				":100644 100644 96cc558 1c5a36f R097\x00a.txt\x00sub/b.txt\x00" +
+				"0\t0\t\x00a.txt\x00copy.txt\x001\t0\t\x00a.txt\x00sub/b.txt\x00",
+			want: []StagedFile{
+				{Path: "copy.txt", Status: "C", OldPath: "a.txt", Similarity: 100, OldMode: "100644", NewMode: "100644"},
+				{Path: "sub/b.txt", Status: "R", OldPath: "a.txt", Similarity: 97, OldMode: "100644", NewMode: "100644", Additions: 1},
+			},
+		},
+		{
+			name:    "malformed numstat",
+			input:   "garbage\x00",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRawNumstat([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRawNumstat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRawNumstat() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractFunctionContexts(t *testing.T) {
+	diff := "diff --git a/x.go b/x.go\n" +
+		"@@ -10,2 +10,3 @@ func (g *Generator) zeta() {\n" +
+		"+\treturn\n" +
+		"@@ -20,1 +21,1 @@ func alpha() {\n" +
+		"@@ -30,1 +31,1 @@ func alpha() {\n" +
+		"@@ -1 +1 @@\n"
+
+	got := extractFunctionContexts(diff, 10)
+	want := []string{"func (g *Generator) zeta() {", "func alpha() {"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractFunctionContexts() = %v, want %v", got, want)
+	}
+
+	if limited := extractFunctionContexts(diff, 1); len(limited) != 1 {
+		t.Errorf("extractFunctionContexts() with limit 1 returned %d entries", len(limited))
+	}
+}
+
+func TestBuildWordDiff(t *testing.T) {
+	diff := "--- a/x.go\n+++ b/x.go\n+return error\n-old value\n context"
+	got := buildWordDiff(diff)
+	want := "{+return+} {+error+} \n[-old-] [-value-] \n context\n"
+	if got != want {
+		t.Errorf("buildWordDiff() = %q, want %q", got, want)
+	}
+}
+
+func TestPerformAdvancedGitAnalysisWithBackend(t *testing.T) {
+	backend := &fakeBackend{
+		files: []StagedFile{
+			{Path: "pkg/a/a.go", Status: "A", OldMode: "000000", NewMode: "100644", Additions: 10},
+			{Path: "pkg/a/b.go", Status: "M", OldMode: "100644", NewMode: "100644", Additions: 2, Deletions: 1},
+			{Path: "README.md", Status: "D", OldMode: "100644", NewMode: "000000", Deletions: 4},
+		},
+		diff:    "@@ -1 +1 @@ func main() {\n+fmt.Println(error)\n",
+		commits: []CommitInfo{{Hash: "abc123", Message: "feat: something"}},
+	}
+
+	g := New(Options{Backend: backend, Output: io.Discard})
+	result, err := g.performAdvancedGitAnalysis(context.Background())
+	if err != nil {
+		t.Fatalf("performAdvancedGitAnalysis() error = %v", err)
+	}
+
+	if result.TotalFiles != 3 || result.TotalAdditions != 12 || result.TotalDeletions != 5 {
+		t.Errorf("unexpected totals: files=%d +%d -%d", result.TotalFiles, result.TotalAdditions, result.TotalDeletions)
+	}
+	if result.ChangeTypes["README.md"] != "D" {
+		t.Errorf("expected README.md to be deleted, got %q", result.ChangeTypes["README.md"])
+	}
+	if got := result.DirStats["pkg/a/"]; got < 66 || got > 67 {
+		t.Errorf("expected pkg/a/ dirstat ~66.7%%, got %.1f", got)
+	}
+	wantSummaries := []string{"create mode 100644 pkg/a/a.go", "delete mode 100644 README.md"}
+	if !reflect.DeepEqual(result.FileSummaries, wantSummaries) {
+		t.Errorf("FileSummaries = %v, want %v", result.FileSummaries, wantSummaries)
+	}
+	if !reflect.DeepEqual(result.ModifiedFunctions, []string{"func main() {"}) {
+		t.Errorf("ModifiedFunctions = %v", result.ModifiedFunctions)
+	}
+	if result.CommitPatterns == nil || result.CommitPatterns.PreferredStyle != "conventional" {
+		t.Errorf("expected conventional commit patterns, got %+v", result.CommitPatterns)
+	}
+}
+
+func TestRenameAnalysis(t *testing.T) {
+	backend := &fakeBackend{
+		files: []StagedFile{
+			{Path: "internal/hooks/install.go", Status: "R", OldPath: "pkg/hooks/install.go", Similarity: 100},
+		},
+	}
+
+	g := New(Options{Backend: backend, Output: io.Discard})
+	result, err := g.performAdvancedGitAnalysis(context.Background())
+	if err != nil {
+		t.Fatalf("performAdvancedGitAnalysis() error = %v", err)
+	}
+	if want := []string{"rename pkg/hooks/install.go => internal/hooks/install.go (100%)"}; !reflect.DeepEqual(result.FileSummaries, want) {
+		t.Errorf("FileSummaries = %v, want %v", result.FileSummaries, want)
+	}
+
+	analyses := g.getAdvancedChangeAnalyses(result)
+	if len(analyses) != 1 {
+		t.Fatalf("expected a single analysis for a rename, got %d", len(analyses))
+	}
+	if analyses[0].ChangeType != "refactor" || analyses[0].Description != "move pkg/hooks/install.go to internal/hooks/install.go" {
+		t.Errorf("unexpected rename analysis: %s %q", analyses[0].ChangeType, analyses[0].Description)
+	}
+
+	if got := describeRename("pkg/a/old.go", "pkg/a/new.go"); got != "rename old.go to new.go" {
+		t.Errorf("describeRename() = %q", got)
+	}
+}
+
+func TestGitEnvResolvesRelativeRepoVars(t *testing.T) {
+	t.Setenv("GIT_DIR", ".git")
+	t.Setenv("GIT_WORK_TREE", "/abs/tree")
+
+	if env := gitEnv(""); env != nil {
+		t.Errorf("expected inherited environment without a Dir, got %d entries", len(env))
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := gitEnv(t.TempDir())
+
+	want := map[string]string{
+		"GIT_DIR":       filepath.Join(wd, ".git"),
+		"GIT_WORK_TREE": "/abs/tree",
+	}
+	for _, entry := range env {
+		name, value, _ := strings.Cut(entry, "=")
+		if expected, ok := want[name]; ok {
+			if value != expected {
+				t.Errorf("%s = %q, want %q", name, value, expected)
+			}
+			delete(want, name)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("missing variables: %v", want)
+	}
+}
+
+func TestExecBackendStopsHungGit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake git is a shell script")
+	}
+	// A git that never answers, like one waiting on a credential prompt
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "git"), []byte("#!/bin/sh\nexec sleep 30\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	backend := &ExecBackend{Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	_, err := backend.Status(context.Background())
+	if err == nil || err.Error() != "git status timed out after 50ms" {
+		t.Errorf("Status() error = %v, want a timeout", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	backend.Timeout = 0
+	stream, err := backend.StagedDiff(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(stream); !errors.Is(err, context.Canceled) {
+		t.Errorf("reading a cancelled diff: error = %v, want context.Canceled", err)
+	}
+	_ = stream.Close()
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("hung git held the backend for %s", elapsed)
+	}
+}
+
+func TestParseCommitFilesLog(t *testing.T) {
+	output := "\x1ea1b2c3d\x1ffeat(api): add users\n\ninternal/api/users.go\ndocs/api.md\n\x1ee4f5a6b\x1fchore: empty commit\n"
+	want := []CommitFiles{
+		{Hash: "a1b2c3d", Subject: "feat(api): add users", Files: []string{"internal/api/users.go", "docs/api.md"}},
+		{Hash: "e4f5a6b", Subject: "chore: empty commit"},
+	}
+	if got := parseCommitFilesLog(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCommitFilesLog() = %+v, want %+v", got, want)
+	}
+}
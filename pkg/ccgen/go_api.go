@@ -0,0 +1,99 @@
+// Package ccgen - Breaking changes detected from the exported Go API
+package ccgen
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic/plugins/golang"
+)
+
+// goChangeTypes maps staged statuses to the change types of the Go plugin
+var goChangeTypes = map[string]string{"M": "modified", "T": "modified", "D": "deleted", "R": "renamed"}
+
+// goAPIBreaks compares the exported API of each staged Go file with HEAD
+// using the Go semantic plugin, on the whole files rather than the diff, and
+// describes the incompatible changes, such as "removed api.Client.Do".
+// Tests, generated code and packages outside the public API (main and
+// internal ones) are skipped, as are files git cannot show.
+func (g *Generator) goAPIBreaks(ctx context.Context, analysis *GitAnalysisResult) []string {
+	plugin := golang.NewGoPlugin()
+	analysisCtx := semantic.AnalysisContext{Config: plugin.DefaultConfig()}
+
+	var breaks []string
+	for _, filename := range sortedKeys(analysis.FileStats) {
+		stats := analysis.FileStats[filename]
+		changeType, ok := goChangeTypes[stats.ChangeType]
+		if !ok || stats.Kind != "" || !strings.HasSuffix(filename, ".go") || strings.HasSuffix(filename, "_test.go") || golang.InternalPackage(filename) {
+			continue
+		}
+
+		file := semantic.FileChange{Path: filename, OldPath: stats.OldPath, ChangeType: changeType, Language: "go"}
+		before := filename
+		if stats.OldPath != "" {
+			before = stats.OldPath
+		}
+		var err error
+		if file.BeforeContent, err = g.backend.FileAt(ctx, "HEAD", before); err != nil {
+			continue
+		}
+		if changeType != "deleted" {
+			if file.AfterContent, err = g.backend.FileAt(ctx, "", filename); err != nil {
+				continue
+			}
+		}
+		if golang.PackageName(file.BeforeContent) == "main" {
+			continue
+		}
+
+		change, err := plugin.AnalyzeFile(ctx, file, analysisCtx)
+		if err != nil || change == nil || !change.BreakingChange {
+			continue
+		}
+		breaks = append(breaks, describeGoBreak(golang.PackageName(file.BeforeContent), change)...)
+	}
+	sort.Strings(breaks)
+	return breaks
+}
+
+// describeGoBreak describes the incompatible changes the Go plugin found in
+// a file of package pkg
+func describeGoBreak(pkg string, change *semantic.SemanticChange) []string {
+	var breaks []string
+	for _, entry := range []struct{ key, verb string }{{"removed", "removed"}, {"changed", "changed"}} {
+		for _, name := range strings.Split(change.Metadata[entry.key], ",") {
+			if name != "" {
+				breaks = append(breaks, fmt.Sprintf("%s %s.%s", entry.verb, pkg, name))
+			}
+		}
+	}
+	if len(breaks) == 0 && change.Metadata["old_path"] != "" {
+		// A file with exported identifiers moved to another package
+		breaks = append(breaks, fmt.Sprintf("moved %s to %s", change.Metadata["old_path"], change.Files[len(change.Files)-1]))
+	}
+	return breaks
+}
+
+// markBreaking adds "!" to the header of message and a BREAKING CHANGE
+// footer listing breaks, unless the message already marks the change
+func markBreaking(message string, breaks []string) string {
+	if len(breaks) == 0 || strings.Contains(message, "BREAKING CHANGE:") {
+		return message
+	}
+	header, body, _ := strings.Cut(message, "\n")
+	if m := templateHeaderRegex.FindStringSubmatchIndex(header); m != nil && !strings.HasPrefix(header[m[3]:], "!") {
+		// Insert the ! where the type or scope ends
+		end := m[3]
+		if m[4] >= 0 {
+			end = m[5] + 1
+		}
+		header = header[:end] + "!" + header[end:]
+	}
+	if body != "" {
+		header += "\n" + body
+	}
+	return appendBodyLine(header, "BREAKING CHANGE: "+strings.Join(breaks, "; "))
+}
@@ -0,0 +1,69 @@
+package ccgen
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGoAPIBreaks(t *testing.T) {
+	client := "package api\n\ntype Client struct {\n\tURL string\n}\n\nfunc NewClient(url string) *Client { return nil }\n\nfunc (c *Client) Do() error { return nil }\n"
+	backend := &fakeBackend{
+		files: []StagedFile{
+			{Path: "pkg/api/client.go", Status: "M", Additions: 2, Deletions: 3},
+			{Path: "pkg/api/client_test.go", Status: "M", Additions: 1},
+			{Path: "internal/store/store.go", Status: "D", Deletions: 5},
+			{Path: "cmd/tool/main.go", Status: "M", Additions: 1, Deletions: 1},
+		},
+		contents: map[string]string{
+			"HEAD:pkg/api/client.go":       client,
+			":pkg/api/client.go":           "package api\n\ntype Client struct {\n\tURL string\n}\n\nfunc NewClient(url string, timeout int) *Client { return nil }\n",
+			"HEAD:internal/store/store.go": "package store\n\nfunc Open() {}\n",
+			"HEAD:cmd/tool/main.go":        "package main\n\nfunc Run() {}\n",
+			":cmd/tool/main.go":            "package main\n\nfunc Run(args []string) {}\n",
+		},
+	}
+
+	g := New(Options{Backend: backend, Output: io.Discard, StagedOnly: true, NoCache: true, NoHistory: true})
+	analysis, err := g.performAdvancedGitAnalysis(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"changed api.NewClient", "removed api.Client.Do"}
+	if got := g.goAPIBreaks(context.Background(), analysis); !reflect.DeepEqual(got, want) {
+		t.Errorf("goAPIBreaks() = %q, want %q", got, want)
+	}
+
+	g = New(Options{Backend: backend, Output: io.Discard, StagedOnly: true, NoCache: true, NoHistory: true, BreakingAPI: true})
+	result, err := g.Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, _, _ := strings.Cut(result.Message, "\n")
+	if !strings.Contains(header, "!: ") || !strings.HasSuffix(result.Message, "\n\nBREAKING CHANGE: changed api.NewClient; removed api.Client.Do") {
+		t.Errorf("Message = %q, want it marked breaking", result.Message)
+	}
+}
+
+func TestMarkBreaking(t *testing.T) {
+	breaks := []string{"removed api.Client.Do"}
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"feat(api): drop Do", "feat(api)!: drop Do\n\nBREAKING CHANGE: removed api.Client.Do"},
+		{"refactor: simplify client\n\nDetails.", "refactor!: simplify client\n\nDetails.\n\nBREAKING CHANGE: removed api.Client.Do"},
+		{"feat!: drop Do", "feat!: drop Do\n\nBREAKING CHANGE: removed api.Client.Do"},
+		{"feat: drop Do\n\nBREAKING CHANGE: Do is gone", "feat: drop Do\n\nBREAKING CHANGE: Do is gone"},
+	}
+	for _, tt := range tests {
+		if got := markBreaking(tt.message, breaks); got != tt.want {
+			t.Errorf("markBreaking(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+	if got := markBreaking("feat: add Do", nil); got != "feat: add Do" {
+		t.Errorf("markBreaking() without breaks = %q", got)
+	}
+}
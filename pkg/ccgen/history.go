@@ -0,0 +1,171 @@
+// Package ccgen - Wording suggestions from similar previous commits
+package ccgen
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultHistoryWindow is the number of recent commits searched for
+	// similar changes
+	DefaultHistoryWindow = 50
+	// DefaultHistorySuggestions is the number of similar commits offered
+	DefaultHistorySuggestions = 3
+
+	// minHistoryScore is the similarity below which a commit is not offered;
+	// touching the same directories is enough, sharing a scope alone is not
+	minHistoryScore = 0.25
+)
+
+// HistorySuggestion is a previous commit that touched files similar to the
+// staged ones, offered so related commits are worded consistently
+type HistorySuggestion struct {
+	Hash    string
+	Subject string
+	// SharedFiles is the number of staged files the commit also touched
+	SharedFiles int
+	// Score is the similarity from 0 to 1: shared files weigh most, then
+	// shared directories and the same scope
+	Score float64
+}
+
+// historyScopeRegex extracts the scope of a conventional commit subject
+var historyScopeRegex = regexp.MustCompile(`^\w+\(([^)]+)\)!?: `)
+
+// historySuggestions returns the recent commits most similar to the staged
+// paths and scope. History errors (for example a repository without
+// commits) simply mean there are no suggestions; only a cancelled ctx fails.
+func (g *Generator) historySuggestions(ctx context.Context, paths []string, scope string) ([]HistorySuggestion, error) {
+	if g.options.NoHistory || len(paths) == 0 {
+		return nil, nil
+	}
+	window := g.options.HistoryWindow
+	if window <= 0 {
+		window = DefaultHistoryWindow
+	}
+	limit := g.options.HistorySuggestions
+	if limit <= 0 {
+		limit = DefaultHistorySuggestions
+	}
+
+	g.progress.Start(fmt.Sprintf("Running `git log -%d --name-only`", window))
+	commits, err := g.backend.RecentCommitFiles(ctx, window)
+	g.progress.Done(err)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, nil
+	}
+	return rankHistory(commits, paths, scope, limit), nil
+}
+
+// rankHistory scores each commit against the staged paths and scope and
+// returns up to limit of the best, most recent first among equal scores.
+// Merges, reverts and fixups say nothing about wording and are skipped, as
+// are repeated subjects.
+func rankHistory(commits []CommitFiles, paths []string, scope string, limit int) []HistorySuggestion {
+	staged := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		staged[p] = true
+	}
+	stagedDirs := dirSet(paths)
+
+	var suggestions []HistorySuggestion
+	seen := make(map[string]bool)
+	for _, commit := range commits {
+		if commit.Subject == "" || seen[commit.Subject] || isHistoryNoise(commit.Subject) || len(commit.Files) == 0 {
+			continue
+		}
+
+		shared := 0
+		for _, file := range commit.Files {
+			if staged[file] {
+				shared++
+			}
+		}
+		score := 0.6 * jaccard(shared, len(staged), len(commit.Files))
+
+		commitDirs := dirSet(commit.Files)
+		sharedDirs := 0
+		for dir := range commitDirs {
+			if stagedDirs[dir] {
+				sharedDirs++
+			}
+		}
+		score += 0.3 * jaccard(sharedDirs, len(stagedDirs), len(commitDirs))
+
+		if m := historyScopeRegex.FindStringSubmatch(commit.Subject); scope != "" && m != nil && m[1] == scope {
+			score += 0.1
+		}
+		if score < minHistoryScore {
+			continue
+		}
+
+		seen[commit.Subject] = true
+		suggestions = append(suggestions, HistorySuggestion{
+			Hash:        commit.Hash,
+			Subject:     commit.Subject,
+			SharedFiles: shared,
+			Score:       score,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// isHistoryNoise reports subjects git or tools generate rather than people
+func isHistoryNoise(subject string) bool {
+	for _, prefix := range []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(subject, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// dirSet returns the parent directories of paths ("." for the root)
+func dirSet(paths []string) map[string]bool {
+	dirs := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		dirs[path.Dir(p)] = true
+	}
+	return dirs
+}
+
+// jaccard returns the Jaccard similarity of two sets from their sizes and
+// the size of their intersection
+func jaccard(shared, a, b int) float64 {
+	union := a + b - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// printHistorySuggestions lists the similar previous commits
+func (g *Generator) printHistorySuggestions(suggestions []HistorySuggestion) {
+	if len(suggestions) == 0 {
+		return
+	}
+	fmt.Fprintf(g.out, "**Similar previous commits:**\n")
+	for _, s := range suggestions {
+		fmt.Fprintf(g.out, "- `%s` (%s", s.Subject, s.Hash)
+		if s.SharedFiles > 0 {
+			fmt.Fprintf(g.out, ", %d shared file(s)", s.SharedFiles)
+		}
+		fmt.Fprintf(g.out, ")\n")
+	}
+	fmt.Fprintln(g.out)
+}
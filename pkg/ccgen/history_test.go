@@ -0,0 +1,77 @@
+package ccgen
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRankHistory(t *testing.T) {
+	commits := []CommitFiles{
+		{Hash: "a1", Subject: "docs: fix typo", Files: []string{"README.md"}},
+		{Hash: "b2", Subject: "Merge branch 'auth'", Files: []string{"internal/auth/token.go"}},
+		{Hash: "c3", Subject: "feat(auth): add token refresh", Files: []string{"internal/auth/token.go", "internal/auth/refresh.go"}},
+		{Hash: "d4", Subject: "fix(auth): reject expired sessions", Files: []string{"internal/auth/session.go"}},
+		{Hash: "e5", Subject: "feat(auth): add token refresh", Files: []string{"internal/auth/token.go"}},
+		{Hash: "f6", Subject: "chore(auth): bump deps", Files: []string{"go.mod"}},
+	}
+	got := rankHistory(commits, []string{"internal/auth/token.go", "internal/auth/token_test.go"}, "auth", 5)
+
+	var subjects []string
+	for _, s := range got {
+		subjects = append(subjects, s.Hash+" "+s.Subject)
+	}
+	want := []string{"c3 feat(auth): add token refresh", "d4 fix(auth): reject expired sessions"}
+	if !reflect.DeepEqual(subjects, want) {
+		t.Fatalf("rankHistory() = %q, want %q", subjects, want)
+	}
+	if got[0].SharedFiles != 1 || got[1].SharedFiles != 0 {
+		t.Errorf("unexpected shared files: %+v", got)
+	}
+
+	if got := rankHistory(commits, []string{"internal/auth/token.go"}, "auth", 1); len(got) != 1 {
+		t.Errorf("rankHistory() with limit 1 returned %d suggestions", len(got))
+	}
+	if got := rankHistory(commits, []string{"web/app.ts"}, "web", 3); len(got) != 0 {
+		t.Errorf("rankHistory() for unrelated files = %+v", got)
+	}
+}
+
+func TestGenerateSuggestsFromHistory(t *testing.T) {
+	newBackend := func() *fakeBackend {
+		return &fakeBackend{
+			files: []StagedFile{{Path: "internal/auth/token.go", Status: "M", Additions: 3, Deletions: 1}},
+			recent: []CommitFiles{
+				{Hash: "c3", Subject: "feat(auth): add token refresh", Files: []string{"internal/auth/token.go"}},
+			},
+		}
+	}
+
+	var out strings.Builder
+	g := New(Options{Backend: newBackend(), Output: &out, StagedOnly: true, NoCache: true})
+	result, err := g.Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Suggestions) != 1 || result.Suggestions[0].Subject != "feat(auth): add token refresh" {
+		t.Errorf("Suggestions = %+v", result.Suggestions)
+	}
+	if !strings.Contains(out.String(), "**Similar previous commits:**\n- `feat(auth): add token refresh` (c3, 1 shared file(s))") {
+		t.Errorf("suggestions not shown:\n%s", out.String())
+	}
+
+	g = New(Options{Backend: newBackend(), Output: io.Discard, StagedOnly: true, NoCache: true, NoHistory: true})
+	if result, err := g.Generate(context.Background()); err != nil || len(result.Suggestions) != 0 {
+		t.Errorf("Generate() with NoHistory = %+v, %v", result, err)
+	}
+}
+
+func TestConfirmLowConfidencePicksSuggestion(t *testing.T) {
+	suggestions := []HistorySuggestion{{Hash: "c3", Subject: "feat(auth): add token refresh"}}
+	g := New(Options{Output: io.Discard, Input: strings.NewReader("1\n"), MinConfidence: 0.6})
+	if got := g.confirmLowConfidence("chore: update token.go\n\nbody", 0.2, suggestions); got != "feat(auth): add token refresh\n\nbody" {
+		t.Errorf("confirmLowConfidence() = %q", got)
+	}
+}
@@ -0,0 +1,81 @@
+// Package ccgen - Hotspot detection shared with semantic plugins
+package ccgen
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// DefaultHotspotWindow is the number of recent commits searched for hotspots
+	DefaultHotspotWindow = 20
+	// DefaultHotspotThreshold is the number of commits within the window that
+	// must touch a file for it to count as a hotspot
+	DefaultHotspotThreshold = 3
+)
+
+// HotspotService finds files changed repeatedly in recent commits. History is
+// read with a single git call on first use and shared by every lookup, so it
+// can be handed to all semantic plugins. It implements semantic.HotspotDetector.
+type HotspotService struct {
+	ctx       context.Context
+	backend   GitBackend
+	window    int
+	threshold int
+
+	once   sync.Once
+	counts map[string]int
+}
+
+// NewHotspotService creates a hotspot detector over the last window commits,
+// reading history under ctx (the detector interface has no context of its
+// own); zero values use DefaultHotspotWindow and DefaultHotspotThreshold
+func NewHotspotService(ctx context.Context, backend GitBackend, window, threshold int) *HotspotService {
+	if window <= 0 {
+		window = DefaultHotspotWindow
+	}
+	if threshold <= 0 {
+		threshold = DefaultHotspotThreshold
+	}
+	return &HotspotService{ctx: ctx, backend: backend, window: window, threshold: threshold}
+}
+
+// Window returns the number of recent commits considered
+func (h *HotspotService) Window() int {
+	return h.window
+}
+
+// Threshold returns the commit count at which a file becomes a hotspot
+func (h *HotspotService) Threshold() int {
+	return h.threshold
+}
+
+// Hotspots returns the recent commit count of each given path that reached
+// the threshold. History errors (for example a repository without commits)
+// simply mean there are no hotspots.
+func (h *HotspotService) Hotspots(paths []string) map[string]int {
+	h.once.Do(h.load)
+
+	hotspots := make(map[string]int)
+	for _, path := range paths {
+		if count := h.counts[path]; count >= h.threshold {
+			hotspots[path] = count
+		}
+	}
+	return hotspots
+}
+
+// load counts how many of the recent commits touched each path
+func (h *HotspotService) load() {
+	h.counts = make(map[string]int)
+
+	commits, err := h.backend.RecentChangedFiles(h.ctx, h.window)
+	if err != nil {
+		return
+	}
+	for _, paths := range commits {
+		for _, path := range paths {
+			h.counts[path]++
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package ccgen
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestHotspotService(t *testing.T) {
+	backend := &fakeBackend{history: [][]string{
+		{"infra/main.tf", "README.md"},
+		{"infra/main.tf"},
+		{},
+		{"infra/main.tf", "pkg/a.go"},
+		{"pkg/a.go"},
+		{"pkg/a.go"},
+	}}
+
+	hotspots := NewHotspotService(context.Background(), backend, 5, 2)
+	got := hotspots.Hotspots([]string{"infra/main.tf", "pkg/a.go", "README.md", "new.go"})
+	want := map[string]int{"infra/main.tf": 3, "pkg/a.go": 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hotspots() = %v, want %v", got, want)
+	}
+
+	hotspots.Hotspots([]string{"pkg/a.go"})
+	if backend.logs != 1 {
+		t.Errorf("expected history to be read once, got %d reads", backend.logs)
+	}
+
+	defaults := NewHotspotService(context.Background(), backend, 0, 0)
+	if defaults.Window() != DefaultHotspotWindow || defaults.Threshold() != DefaultHotspotThreshold {
+		t.Errorf("unexpected defaults: window %d threshold %d", defaults.Window(), defaults.Threshold())
+	}
+}
+
+func TestParseNameOnlyLog(t *testing.T) {
+	output := "\x1e\n\na.go\nb/c.go\n\x1e\n\x1e\n\nd.txt\n"
+	want := [][]string{{"a.go", "b/c.go"}, nil, {"d.txt"}}
+	if got := parseNameOnlyLog(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNameOnlyLog() = %q, want %q", got, want)
+	}
+}
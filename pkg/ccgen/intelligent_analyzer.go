@@ -2,6 +2,7 @@
 package ccgen
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -18,8 +19,14 @@ type IntelligentChangeAnalysis struct {
 	Context     string
 }
 
-// determineIntelligentScope provides more granular scope detection
+// determineIntelligentScope provides more granular scope detection, using
+// the scope derived from the project structure when there is one
 func (g *Generator) determineIntelligentScope(filename string) string {
+	if g.options.Scopes != nil {
+		if scope := g.options.Scopes.Scope(filename); scope != "" {
+			return scope
+		}
+	}
 	switch {
 	case strings.HasPrefix(filename, "cmd/"):
 		// Extract specific command name
@@ -69,3 +76,37 @@ func (g *Generator) extractFileName(filePath string) string {
 
 	return filename
 }
+
+// applyOverrides gives every change the type and scope the user chose,
+// pinned or given on the command line, instead of the inferred ones, and
+// reports them so the analysis output shows where they came from
+func (g *Generator) applyOverrides(analyses []*IntelligentChangeAnalysis) {
+	scope, source := g.options.Scope, "--scope"
+	if scope == "" && g.options.PinnedScope != nil {
+		pinned, err := g.options.PinnedScope.GetScope()
+		if err != nil && g.options.Verbose {
+			fmt.Fprintf(g.out, "Warning: could not read pinned scope: %v\n", err)
+		}
+		scope, source = pinned, "pinned"
+	}
+	if scope == "" && g.options.Type == "" {
+		return
+	}
+
+	for _, analysis := range analyses {
+		if g.options.Type != "" {
+			analysis.ChangeType = g.options.Type
+		}
+		if scope != "" {
+			analysis.Scope = scope
+		}
+	}
+	sortAnalyses(analyses)
+
+	if g.options.Type != "" {
+		fmt.Fprintf(g.out, "- Type: `%s` (--type)\n", g.options.Type)
+	}
+	if scope != "" {
+		fmt.Fprintf(g.out, "- Scope: `%s` (%s)\n", scope, source)
+	}
+}
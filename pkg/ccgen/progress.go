@@ -0,0 +1,150 @@
+package ccgen
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/banner"
+)
+
+// Progress reports the steps of an analysis, such as the git commands it
+// runs, while they run. Steps do not nest: each Start is ended by Done.
+type Progress interface {
+	// Start begins a step described by label, e.g. "Running `git diff --cached`".
+	Start(label string)
+	// Update reports how far the current step has got, e.g. "(3/10 files)".
+	Update(detail string)
+	// Done ends the current step, which failed if err is not nil.
+	Done(err error)
+}
+
+// NewProgress returns the progress reporter for a command line tool writing
+// to f: every step on its own line when verbose, a spinner when f is a
+// terminal, and nothing otherwise, so scripts only see the tool's output.
+func NewProgress(f *os.File, verbose bool) Progress {
+	switch {
+	case verbose:
+		return NewLineProgress(f)
+	case isTerminal(f) && os.Getenv("TERM") != "dumb":
+		return &spinnerProgress{w: f}
+	default:
+		return quietProgress{}
+	}
+}
+
+// NewLineProgress returns a reporter that writes each step to w on its own
+// line, marked ✅ or ❌ when it is done.
+func NewLineProgress(w io.Writer) Progress {
+	return &lineProgress{w: w}
+}
+
+// isTerminal reports whether the file is an interactive character device
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// quietProgress reports nothing; it is the default for library consumers.
+type quietProgress struct{}
+
+func (quietProgress) Start(string)  {}
+func (quietProgress) Update(string) {}
+func (quietProgress) Done(error)    {}
+
+type lineProgress struct {
+	w     io.Writer
+	label string
+}
+
+func (p *lineProgress) Start(label string) {
+	p.label = label
+	fmt.Fprint(p.w, label)
+}
+
+func (p *lineProgress) Update(detail string) {
+	fmt.Fprintf(p.w, "\r%s %s", p.label, detail)
+}
+
+func (p *lineProgress) Done(err error) {
+	if err != nil {
+		fmt.Fprintln(p.w, " ❌")
+		return
+	}
+	fmt.Fprintln(p.w, " ✅")
+}
+
+// spinnerInterval is how often the spinner redraws the current step.
+const spinnerInterval = 100 * time.Millisecond
+
+// spinnerProgress redraws the current step on one terminal line and clears
+// it when the step is done, leaving the terminal as it found it.
+type spinnerProgress struct {
+	w io.Writer
+
+	mu     sync.Mutex
+	label  string
+	detail string
+	frame  int
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func (p *spinnerProgress) Start(label string) {
+	p.mu.Lock()
+	p.label, p.detail, p.frame = label, "", 0
+	p.stop, p.done = make(chan struct{}), make(chan struct{})
+	p.draw()
+	p.mu.Unlock()
+
+	go p.spin(p.stop, p.done)
+}
+
+func (p *spinnerProgress) spin(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			p.frame++
+			p.draw()
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (p *spinnerProgress) Update(detail string) {
+	p.mu.Lock()
+	p.detail = detail
+	p.draw()
+	p.mu.Unlock()
+}
+
+func (p *spinnerProgress) Done(error) {
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	<-p.done
+	p.stop = nil
+	fmt.Fprint(p.w, "\r\033[K")
+}
+
+// draw writes the current frame; p.mu must be held.
+func (p *spinnerProgress) draw() {
+	frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	if banner.UseASCII() {
+		frames = []string{"|", "/", "-", "\\"}
+	}
+	line := frames[p.frame%len(frames)] + " " + p.label
+	if p.detail != "" {
+		line += " " + p.detail
+	}
+	fmt.Fprintf(p.w, "\r\033[K%s", line)
+}
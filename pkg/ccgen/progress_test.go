@@ -0,0 +1,87 @@
+package ccgen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerateProgress(t *testing.T) {
+	newBackend := func() *fakeBackend {
+		return &fakeBackend{
+			files: []StagedFile{{Path: "main.go", Status: "M", Additions: 1}},
+			diff:  "diff --git a/main.go b/main.go\n+// login\n",
+		}
+	}
+
+	// Library consumers get no progress unless they ask for it
+	var out bytes.Buffer
+	g := New(Options{Backend: newBackend(), Output: &out, StagedOnly: true, NoCache: true})
+	if _, err := g.Generate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "Running") {
+		t.Errorf("output contains progress:\n%s", out.String())
+	}
+
+	var steps bytes.Buffer
+	g = New(Options{Backend: newBackend(), Output: &bytes.Buffer{}, Progress: NewLineProgress(&steps),
+		StagedOnly: true, NoCache: true})
+	if _, err := g.Generate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := "Running `git rev-parse --git-dir` ✅\n" +
+		"Running `git status --porcelain` ✅\n" +
+		"Running `git diff --cached --raw --numstat -z` ✅\n" +
+		"Running `git diff --cached` ✅\n" +
+		"Running `git log --oneline -10` ✅\n" +
+		"Running `git log -50 --name-only` ✅\n"
+	if steps.String() != want {
+		t.Errorf("progress =\n%q\nwant:\n%q", steps.String(), want)
+	}
+}
+
+func TestLineProgressFailure(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewLineProgress(&buf)
+	p.Start("Running `git add .`")
+	p.Done(errors.New("index.lock exists"))
+	if got, want := buf.String(), "Running `git add .` ❌\n"; got != want {
+		t.Errorf("progress = %q, want %q", got, want)
+	}
+}
+
+func TestSpinnerProgressClearsLine(t *testing.T) {
+	var buf bytes.Buffer
+	p := &spinnerProgress{w: &buf}
+	p.Start("Running `git diff --cached`")
+	p.Update("(1/2 files)")
+	p.Done(nil)
+	p.Done(nil)
+
+	got := buf.String()
+	if !strings.Contains(got, "Running `git diff --cached` (1/2 files)") {
+		t.Errorf("spinner did not show the update: %q", got)
+	}
+	if !strings.HasSuffix(got, "\r\033[K") || strings.Count(got, "\r\033[K") < 3 {
+		t.Errorf("spinner did not clear its line: %q", got)
+	}
+}
+
+func TestNewProgressQuietWhenNotATerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "progress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, ok := NewProgress(f, false).(quietProgress); !ok {
+		t.Error("NewProgress() on a file is not quiet")
+	}
+	if _, ok := NewProgress(f, true).(*lineProgress); !ok {
+		t.Error("NewProgress() in verbose mode does not report every step")
+	}
+}
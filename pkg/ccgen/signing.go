@@ -0,0 +1,92 @@
+// Package ccgen - Commit signing detection and signing failure hints
+package ccgen
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// signing is how git is configured to sign commits
+type signing struct {
+	enabled bool
+	// format is gpg.format: openpgp, ssh or x509
+	format string
+}
+
+// readSigning reads commit.gpgsign and gpg.format; unreadable settings
+// leave signing to git's defaults
+func readSigning(ctx context.Context) signing {
+	ctx, cancel := context.WithTimeout(ctx, DefaultGitTimeout)
+	defer cancel()
+
+	s := signing{format: "openpgp"}
+	if out, err := exec.CommandContext(ctx, "git", "config", "--type=bool", "--get", "commit.gpgsign").Output(); err == nil {
+		s.enabled = strings.TrimSpace(string(out)) == "true"
+	}
+	if out, err := exec.CommandContext(ctx, "git", "config", "--get", "gpg.format").Output(); err == nil {
+		if format := strings.TrimSpace(string(out)); format != "" {
+			s.format = format
+		}
+	}
+	return s
+}
+
+// commitArgs returns the git commit flags for signing: -S when signing is
+// configured, so it cannot be lost to overrides, and --no-gpg-sign when
+// noSign disables it
+func (s signing) commitArgs(noSign bool) []string {
+	switch {
+	case noSign:
+		return []string{"--no-gpg-sign"}
+	case s.enabled:
+		return []string{"-S"}
+	default:
+		return nil
+	}
+}
+
+// signingFailureMarkers are git and signer messages reporting that a commit
+// could not be signed
+var signingFailureMarkers = []string{
+	"gpg failed to sign the data",
+	"failed to sign the data",
+	"signing failed",
+	"no secret key",
+	"user.signingkey",
+	"ssh-keygen",
+	"gpgsm",
+}
+
+// isSigningFailure reports whether git's error output is a signing failure
+func isSigningFailure(stderr string) bool {
+	lower := strings.ToLower(stderr)
+	for _, marker := range signingFailureMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// signingHint suggests how to fix signing in the given gpg.format
+func signingHint(format string) string {
+	switch format {
+	case "ssh":
+		return "check that user.signingkey is your public key or a key in ssh-agent (ssh-add -L)"
+	case "x509":
+		return "check that gpgsm has the certificate named by user.signingkey (gpgsm --list-secret-keys)"
+	default:
+		return "check that user.signingkey is listed by 'gpg --list-secret-keys' and that GPG_TTY is set (export GPG_TTY=$(tty))"
+	}
+}
+
+// explainFailure turns a failed signed commit into an error with a hint on
+// fixing signing; other failures are returned as they are
+func (s signing) explainFailure(stderr string, err error) error {
+	if !s.enabled || !isSigningFailure(stderr) {
+		return err
+	}
+	return fmt.Errorf("signing the commit with %s failed: %w; %s, or commit unsigned with --no-sign", s.format, err, signingHint(s.format))
+}
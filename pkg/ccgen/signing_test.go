@@ -0,0 +1,70 @@
+package ccgen
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSigningCommitArgs(t *testing.T) {
+	tests := []struct {
+		signing signing
+		noSign  bool
+		want    []string
+	}{
+		{signing{}, false, nil},
+		{signing{enabled: true}, false, []string{"-S"}},
+		{signing{enabled: true}, true, []string{"--no-gpg-sign"}},
+		{signing{}, true, []string{"--no-gpg-sign"}},
+	}
+	for _, tt := range tests {
+		if got := tt.signing.commitArgs(tt.noSign); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v.commitArgs(%v) = %v, want %v", tt.signing, tt.noSign, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteCommitSigning(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Chdir(dir)
+
+	failing, err := exec.LookPath("false")
+	if err != nil {
+		t.Skip("false not installed")
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "commit.gpgsign", "true"},
+		{"config", "gpg.program", failing},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "add", "a.txt").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+
+	err = New(Options{}).ExecuteCommit(context.Background(), "feat: add a")
+	if err == nil || !strings.Contains(err.Error(), "signing the commit with openpgp failed") || !strings.Contains(err.Error(), "--no-sign") {
+		t.Fatalf("ExecuteCommit() error = %v, want a signing hint", err)
+	}
+
+	if err := New(Options{NoSign: true}).ExecuteCommit(context.Background(), "feat: add a"); err != nil {
+		t.Fatalf("ExecuteCommit() with NoSign error = %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+// Package ccgen - Message templates with variables from repository metadata
+package ccgen
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// MessageTemplate rewrites generated messages. Header and Footer expand
+// {variables}: {header} (the generated header) and its {type}, {scope} and
+// {description}, {branch}, {ticket}, {monorepo_package} (the derived scope
+// all staged files share), {author} and {author_initials}, plus Variables.
+// Unknown variables are left as is.
+type MessageTemplate struct {
+	// Header replaces the generated header (empty keeps it)
+	Header string
+	// Footer lines are appended to the message; lines whose variables all
+	// expand to nothing are left out
+	Footer []string
+	// Variables are constant variables, such as a team name
+	Variables map[string]string
+}
+
+var (
+	// templateVariableRegex matches a {variable} in a template
+	templateVariableRegex = regexp.MustCompile(`\{([a-z_]+)\}`)
+	// templateHeaderRegex splits a generated header into type, scope and
+	// description
+	templateHeaderRegex = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?!?: (.*)$`)
+)
+
+// applyTemplate rewrites message with the configured template, if any
+func (g *Generator) applyTemplate(ctx context.Context, message string, files []string) string {
+	tmpl := g.options.Template
+	if tmpl == nil || (tmpl.Header == "" && len(tmpl.Footer) == 0) {
+		return message
+	}
+	vars := g.templateVariables(ctx, message, files)
+
+	if tmpl.Header != "" {
+		header, rest, hasBody := strings.Cut(message, "\n")
+		if header = strings.Join(strings.Fields(expandTemplate(tmpl.Header, vars)), " "); header != "" {
+			message = header
+			if hasBody {
+				message += "\n" + rest
+			}
+		}
+	}
+
+	var footer []string
+	for _, line := range tmpl.Footer {
+		if expanded, ok := expandTemplateLine(line, vars); ok {
+			footer = append(footer, expanded)
+		}
+	}
+	if len(footer) == 0 {
+		return message
+	}
+	return appendBodyLine(message, strings.Join(footer, "\n"))
+}
+
+// templateVariables returns the variables of a generated message. Git is
+// only asked for the branch and author when the template uses them.
+func (g *Generator) templateVariables(ctx context.Context, message string, files []string) map[string]string {
+	tmpl := g.options.Template
+	vars := make(map[string]string, len(tmpl.Variables)+9)
+	for name, value := range tmpl.Variables {
+		vars[name] = value
+	}
+
+	header, _, _ := strings.Cut(message, "\n")
+	vars["header"] = header
+	if m := templateHeaderRegex.FindStringSubmatch(header); m != nil {
+		vars["type"], vars["scope"], vars["description"] = m[1], m[2], m[3]
+	}
+	vars["ticket"] = g.currentTicket()
+	vars["monorepo_package"] = g.monorepoPackage(files)
+
+	used := tmpl.Header + "\n" + strings.Join(tmpl.Footer, "\n")
+	if strings.Contains(used, "{branch}") {
+		if branch, err := g.backend.Branch(ctx); err == nil {
+			vars["branch"] = branch
+		}
+	}
+	if strings.Contains(used, "{author") {
+		if author, err := g.backend.Author(ctx); err == nil {
+			vars["author"] = author
+			vars["author_initials"] = initials(author)
+		}
+	}
+	return vars
+}
+
+// monorepoPackage returns the derived scope all files map to, or "" when
+// they span several or none is derived
+func (g *Generator) monorepoPackage(files []string) string {
+	if g.options.Scopes == nil || len(files) == 0 {
+		return ""
+	}
+	pkg := g.options.Scopes.Scope(files[0])
+	for _, file := range files[1:] {
+		if g.options.Scopes.Scope(file) != pkg {
+			return ""
+		}
+	}
+	return pkg
+}
+
+// expandTemplate replaces the known variables of text
+func expandTemplate(text string, vars map[string]string) string {
+	expanded, _ := expandTemplateLine(text, vars)
+	return expanded
+}
+
+// expandTemplateLine replaces the known variables of line and reports
+// whether it has content: false when it uses variables and all are empty
+func expandTemplateLine(line string, vars map[string]string) (string, bool) {
+	found, filled := false, false
+	expanded := templateVariableRegex.ReplaceAllStringFunc(line, func(match string) string {
+		value, ok := vars[match[1:len(match)-1]]
+		if !ok {
+			return match
+		}
+		found = true
+		filled = filled || value != ""
+		return value
+	})
+	return expanded, !found || filled
+}
+
+// initials returns the uppercase initials of the name in "Name <email>"
+func initials(author string) string {
+	name, _, _ := strings.Cut(author, "<")
+	var sb strings.Builder
+	for _, word := range strings.Fields(name) {
+		for _, r := range word {
+			if unicode.IsLetter(r) {
+				sb.WriteRune(unicode.ToUpper(r))
+				break
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,70 @@
+package ccgen
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/config"
+)
+
+func TestGenerateAppliesTemplate(t *testing.T) {
+	backend := &fakeBackend{
+		files: []StagedFile{
+			{Path: "apps/billing/invoice.go", Status: "M", Additions: 3, Deletions: 1},
+			{Path: "apps/billing/tax.go", Status: "M", Additions: 3, Deletions: 1},
+		},
+		branch: "feature/invoices",
+		author: "Jane van der Doe <jane@example.com>",
+	}
+	g := New(Options{Backend: backend, Output: io.Discard, StagedOnly: true, NoCache: true,
+		JiraManager: fixedTicket("CGC-7"),
+		Scopes:      prefixScopes{"apps/billing/": "payments"},
+		Template: &MessageTemplate{
+			Header:    "{type}({monorepo_package}): {description} [{author_initials}]",
+			Footer:    []string{"Branch: {branch}", "Team: {team}", "Reviewer: {reviewer}", "Epic: {epic}"},
+			Variables: map[string]string{"team": "payments-squad", "epic": ""},
+		}})
+	result, err := g.Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, _, _ := strings.Cut(result.Message, "\n")
+	if !strings.HasPrefix(header, "feat(payments): CGC-7 ") || !strings.HasSuffix(header, " [JVDD]") {
+		t.Errorf("header = %q, want the template with the package and initials", header)
+	}
+	// Unknown variables stay, empty ones drop their line
+	wantFooter := "\n\nBranch: feature/invoices\nTeam: payments-squad\nReviewer: {reviewer}"
+	if !strings.HasSuffix(result.Message, wantFooter) {
+		t.Errorf("Message = %q, want it to end with the footer %q", result.Message, wantFooter)
+	}
+}
+
+func TestMonorepoPackageNeedsOnePackage(t *testing.T) {
+	g := New(Options{Scopes: prefixScopes{"apps/billing/": "payments", "apps/web/": "web"}})
+	if got := g.monorepoPackage([]string{"apps/billing/a.go", "apps/web/b.go"}); got != "" {
+		t.Errorf("monorepoPackage() over two packages = %q, want none", got)
+	}
+	if got := g.monorepoPackage([]string{"apps/web/a.go", "apps/web/b.go"}); got != "web" {
+		t.Errorf("monorepoPackage() = %q, want web", got)
+	}
+}
+
+func TestTemplateVariablesMatchConfig(t *testing.T) {
+	// Every variable the config accepts is one the generator fills
+	g := New(Options{Backend: &fakeBackend{}, Template: &MessageTemplate{Header: "{branch} {author}"}})
+	vars := g.templateVariables(context.Background(), "feat: x", nil)
+	for _, name := range config.TemplateVariables {
+		if _, ok := vars[name]; !ok {
+			t.Errorf("template variable %q is accepted by the config but not set", name)
+		}
+	}
+	for name := range vars {
+		if !slices.Contains(config.TemplateVariables, name) {
+			t.Errorf("template variable %q is set but rejected by the config", name)
+		}
+	}
+}
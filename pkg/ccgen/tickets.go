@@ -0,0 +1,186 @@
+// Package ccgen - JIRA ticket summaries and trailers for commit bodies
+package ccgen
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+)
+
+// Ticket placements for Options.TicketPlacement
+const (
+	// TicketAtStart puts the ticket before the description (the default)
+	TicketAtStart = "start"
+	// TicketAtEnd puts the ticket after the description
+	TicketAtEnd = "end"
+	// TicketInFooter adds a "Refs: <ticket>" footer instead
+	TicketInFooter = "footer"
+)
+
+// MultiTicketManager is implemented by JIRA managers that track related
+// tickets (such as sub-tasks) besides the current one
+type MultiTicketManager interface {
+	GetCurrentJiraTickets() ([]string, error)
+}
+
+// TicketLookup fetches JIRA issues; *jira.Client implements it
+type TicketLookup interface {
+	GetIssue(ctx context.Context, key string) (*jira.Issue, error)
+}
+
+// ticketSummary returns the summary of the given ticket, or "" when no
+// lookup is configured or the ticket cannot be fetched
+func (g *Generator) ticketSummary(ctx context.Context, ticket string) string {
+	if g.options.TicketLookup == nil || ticket == "" {
+		return ""
+	}
+	issue, err := g.options.TicketLookup.GetIssue(ctx, ticket)
+	if err != nil {
+		if g.options.Verbose {
+			fmt.Fprintf(g.out, "**JIRA lookup failed:** %v\n\n", err)
+		}
+		return ""
+	}
+	return strings.TrimSpace(issue.Summary)
+}
+
+// appendTicketSummary adds a "CGC-1234: Summary" line to the message body
+func appendTicketSummary(message, ticket, summary string) string {
+	if summary == "" {
+		return message
+	}
+	return appendBodyLine(message, ticket+": "+summary)
+}
+
+// appendBodyLine adds a paragraph to the end of the message (empty lines are skipped)
+func appendBodyLine(message, line string) string {
+	if line == "" {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\n" + line
+}
+
+// trailerRegex matches a git trailer line such as "Refs: CGC-1"
+var trailerRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*: \S`)
+
+// appendTrailers adds "key: value" trailers for values, joining the last
+// paragraph when it already holds trailers (such as a Refs: footer) so git
+// sees a single trailer block
+func appendTrailers(message, key string, values []string) string {
+	if len(values) == 0 {
+		return message
+	}
+	lines := make([]string, 0, len(values))
+	for _, value := range values {
+		lines = append(lines, key+": "+value)
+	}
+	block := strings.Join(lines, "\n")
+
+	message = strings.TrimRight(message, "\n")
+	if i := strings.LastIndex(message, "\n\n"); i >= 0 {
+		isTrailers := true
+		for _, line := range strings.Split(message[i+2:], "\n") {
+			if !trailerRegex.MatchString(line) {
+				isTrailers = false
+				break
+			}
+		}
+		if isTrailers {
+			return message + "\n" + block
+		}
+	}
+	return message + "\n\n" + block
+}
+
+// coAuthors returns the current co-authors, or nil when there are none
+func (g *Generator) coAuthors() []string {
+	if g.options.CoAuthors == nil {
+		return nil
+	}
+	coAuthors, err := g.options.CoAuthors.GetCoAuthors()
+	if err != nil {
+		if g.options.Verbose {
+			fmt.Fprintf(g.out, "Warning: could not read co-authors: %v\n", err)
+		}
+		return nil
+	}
+	return coAuthors
+}
+
+// currentTicket returns the current ticket, or "" when none is set
+func (g *Generator) currentTicket() string {
+	if g.options.JiraManager == nil {
+		return ""
+	}
+	ticket, err := g.options.JiraManager.GetCurrentJiraTicket()
+	if err != nil {
+		return ""
+	}
+	return ticket
+}
+
+// currentTickets returns the current ticket followed by related tickets
+// when the manager tracks them
+func (g *Generator) currentTickets() []string {
+	if multi, ok := g.options.JiraManager.(MultiTicketManager); ok {
+		if tickets, err := multi.GetCurrentJiraTickets(); err == nil {
+			return tickets
+		}
+		return nil
+	}
+	if ticket := g.currentTicket(); ticket != "" {
+		return []string{ticket}
+	}
+	return nil
+}
+
+// subjectTicket returns the current ticket when it belongs at the start of
+// the description
+func (g *Generator) subjectTicket() string {
+	switch g.options.TicketPlacement {
+	case TicketAtEnd, TicketInFooter:
+		return ""
+	}
+	return g.currentTicket()
+}
+
+// subjectLimit returns the subject length budget for a subject containing
+// ticket; with ExcludeTicketFromLength the ticket and its space are free
+func (g *Generator) subjectLimit(ticket string) int {
+	if g.options.ExcludeTicketFromLength && ticket != "" {
+		return MaxSubjectLength + utf8.RuneCountInString(ticket) + 1
+	}
+	return MaxSubjectLength
+}
+
+// placeTicket adds the primary ticket at the end of the subject or to a Refs
+// footer, depending on the configured placement. Related tickets always go
+// to the Refs footer.
+func (g *Generator) placeTicket(message string, tickets []string) string {
+	if len(tickets) == 0 {
+		return message
+	}
+	refs := tickets[1:]
+	switch g.options.TicketPlacement {
+	case TicketAtEnd:
+		subject, rest, hasBody := strings.Cut(message, "\n")
+		limit := g.subjectLimit(tickets[0]) - utf8.RuneCountInString(tickets[0]) - 1
+		if utf8.RuneCountInString(subject) > limit {
+			subject = g.intelligentTruncate(subject, limit)
+		}
+		message = subject + " " + tickets[0]
+		if hasBody {
+			message += "\n" + rest
+		}
+	case TicketInFooter:
+		refs = tickets
+	}
+	if len(refs) == 0 {
+		return message
+	}
+	return appendBodyLine(message, "Refs: "+strings.Join(refs, ", "))
+}
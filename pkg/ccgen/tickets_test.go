@@ -0,0 +1,147 @@
+package ccgen
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+)
+
+type fixedTicket string
+
+func (f fixedTicket) GetCurrentJiraTicket() (string, error) { return string(f), nil }
+
+type fakeTicketLookup map[string]*jira.Issue
+
+func (f fakeTicketLookup) GetIssue(_ context.Context, key string) (*jira.Issue, error) {
+	if issue, ok := f[key]; ok {
+		return issue, nil
+	}
+	return nil, jira.ErrIssueNotFound
+}
+
+func TestGenerate_TicketSummary(t *testing.T) {
+	lookup := fakeTicketLookup{"CGC-1": {Key: "CGC-1", Summary: "Add login page"}}
+	newBackend := func() *fakeBackend {
+		return &fakeBackend{
+			files: []StagedFile{{Path: "main.go", Status: "M", Additions: 1}},
+			diff:  "diff --git a/main.go b/main.go\n+// login\n",
+		}
+	}
+
+	var out bytes.Buffer
+	g := New(Options{Backend: newBackend(), Output: &out, StagedOnly: true, NoCache: true,
+		JiraManager: fixedTicket("CGC-1"), TicketLookup: lookup})
+	result, err := g.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.HasSuffix(result.Message, "\n\nCGC-1: Add login page") {
+		t.Errorf("expected the ticket summary in the body, got %q", result.Message)
+	}
+	if !strings.Contains(out.String(), "`CGC-1` Add login page") {
+		t.Errorf("expected the summary in the output:\n%s", out.String())
+	}
+
+	g = New(Options{Backend: newBackend(), Output: &bytes.Buffer{}, StagedOnly: true, NoCache: true,
+		JiraManager: fixedTicket("CGC-1"), TicketLookup: lookup,
+		SmartCommit: jira.SmartCommit{Time: "2h", Transition: "In Review"}})
+	result, err = g.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.HasSuffix(result.Message, "\n\nCGC-1: Add login page\n\nCGC-1 #time 2h #transition In Review") {
+		t.Errorf("expected the smart commit line last, got %q", result.Message)
+	}
+
+	g = New(Options{Backend: newBackend(), Output: &bytes.Buffer{}, StagedOnly: true, NoCache: true,
+		JiraManager: fixedTicket("CGC-2"), TicketLookup: lookup})
+	result, err = g.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if strings.Contains(result.Message, "CGC-2:") {
+		t.Errorf("unknown tickets must not add a summary, got %q", result.Message)
+	}
+}
+
+func TestAppendTicketSummary(t *testing.T) {
+	if got := appendTicketSummary("feat: x", "CGC-1", ""); got != "feat: x" {
+		t.Errorf("empty summary changed the message: %q", got)
+	}
+	if got := appendTicketSummary("feat: x\n\n- a\n", "CGC-1", "Login"); got != "feat: x\n\n- a\n\nCGC-1: Login" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestGenerateCommitMessage_TicketPlacement(t *testing.T) {
+	changes := []ChangeType{{Type: "feat", Scope: "auth", Description: "add user login"}}
+	tests := []struct {
+		placement string
+		want      string
+	}{
+		{placement: "", want: "feat(auth): CGC-1 add user login"},
+		{placement: TicketAtStart, want: "feat(auth): CGC-1 add user login"},
+		{placement: TicketAtEnd, want: "feat(auth): add user login CGC-1"},
+		{placement: TicketInFooter, want: "feat(auth): add user login\n\nRefs: CGC-1"},
+	}
+	for _, tt := range tests {
+		g := New(Options{JiraManager: fixedTicket("CGC-1"), TicketPlacement: tt.placement})
+		if got := g.GenerateCommitMessage(changes); got != tt.want {
+			t.Errorf("placement %q: got %q, want %q", tt.placement, got, tt.want)
+		}
+	}
+
+	long := []ChangeType{{Type: "feat", Description: "add a very long description that does not fit the subject"}}
+	g := New(Options{JiraManager: fixedTicket("CGC-1"), TicketPlacement: TicketAtEnd})
+	if got := g.GenerateCommitMessage(long); !strings.HasSuffix(got, "... CGC-1") || len(got) > MaxSubjectLength {
+		t.Errorf("expected a truncated subject ending with the ticket, got %q", got)
+	}
+
+	g = New(Options{JiraManager: fixedTicket("CGC-1"), TicketPlacement: TicketAtEnd, ExcludeTicketFromLength: true})
+	if got := g.GenerateCommitMessage(long); !strings.HasSuffix(got, " CGC-1") || len(got) != MaxSubjectLength+len(" CGC-1") {
+		t.Errorf("expected the ticket outside the length budget, got %q", got)
+	}
+}
+
+type fixedTickets []string
+
+func (f fixedTickets) GetCurrentJiraTicket() (string, error)    { return f[0], nil }
+func (f fixedTickets) GetCurrentJiraTickets() ([]string, error) { return f, nil }
+
+func TestGenerateCommitMessage_RelatedTickets(t *testing.T) {
+	changes := []ChangeType{{Type: "fix", Description: "handle expired tokens"}}
+	tickets := fixedTickets{"CGC-1", "CGC-2", "CGC-3"}
+
+	g := New(Options{JiraManager: tickets})
+	if got, want := g.GenerateCommitMessage(changes), "fix: CGC-1 handle expired tokens\n\nRefs: CGC-2, CGC-3"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	g = New(Options{JiraManager: tickets, TicketPlacement: TicketInFooter})
+	if got, want := g.GenerateCommitMessage(changes), "fix: handle expired tokens\n\nRefs: CGC-1, CGC-2, CGC-3"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestAppendTrailers(t *testing.T) {
+	coAuthors := []string{"Jane Doe <jane@example.com>", "Bob Smith <bob@example.com>"}
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"feat: add login", "feat: add login\n\nCo-authored-by: Jane Doe <jane@example.com>\nCo-authored-by: Bob Smith <bob@example.com>"},
+		{"feat: add login\n\n- Add form\n", "feat: add login\n\n- Add form\n\nCo-authored-by: Jane Doe <jane@example.com>\nCo-authored-by: Bob Smith <bob@example.com>"},
+		{"feat: add login\n\n- Add form\n\nRefs: CGC-1, CGC-2", "feat: add login\n\n- Add form\n\nRefs: CGC-1, CGC-2\nCo-authored-by: Jane Doe <jane@example.com>\nCo-authored-by: Bob Smith <bob@example.com>"},
+	}
+	for _, tt := range tests {
+		if got := appendTrailers(tt.message, "Co-authored-by", coAuthors); got != tt.want {
+			t.Errorf("appendTrailers(%q) =\n%s\nwant:\n%s", tt.message, got, tt.want)
+		}
+	}
+	if got := appendTrailers("feat: add login", "Co-authored-by", nil); got != "feat: add login" {
+		t.Errorf("appendTrailers() without values = %q", got)
+	}
+}
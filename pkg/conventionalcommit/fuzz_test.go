@@ -0,0 +1,86 @@
+package conventionalcommit
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// fuzzSeeds are the in-code seeds; testdata/fuzz holds inputs found while
+// fuzzing.
+var fuzzSeeds = []string{
+	"feat: add login",
+	"feat(api,web)!: drop v1\n\nBody text.\n\nBREAKING CHANGE: v1 is gone\nReviewed-by: Jane <jane@example.com>",
+	"fix(auth): handle #12 and GH-34 [CGC-56] PROJ-7",
+	"feat(: broken",
+	"feat :wrong\n body\n\n  continued",
+	"FEAT: shout\r\n\r\nRefs: SNOW-1",
+	"",
+	"\n\n\n",
+	"feat(é): ünïcödé",
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	p := DefaultParser()
+	p.ScopeDelimiters = ",/"
+	if err := p.SetTicketPatterns([]TicketPattern{{Name: "SNOW", Pattern: `SNOW-(\d+)`}}); err != nil {
+		f.Fatal(err)
+	}
+
+	f.Fuzz(func(t *testing.T, message string) {
+		commit, err := p.Parse(message)
+		if err != nil {
+			return
+		}
+		if commit.Raw != message || commit.Type == "" {
+			t.Fatalf("Parse(%q) = %+v", message, commit)
+		}
+		checkSpans(t, commit)
+
+		// The header round-trips through Header
+		again, err := p.Parse(commit.Header())
+		if err != nil {
+			t.Fatalf("Parse(Header() %q) error = %v", commit.Header(), err)
+		}
+		if again.Type != commit.Type || again.Scope != commit.Scope {
+			t.Fatalf("Parse(Header() %q) = %s(%s), want %s(%s)", commit.Header(), again.Type, again.Scope, commit.Type, commit.Scope)
+		}
+	})
+}
+
+func FuzzParseLenient(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	p := DefaultParser()
+
+	f.Fuzz(func(t *testing.T, message string) {
+		commit, issues := p.ParseLenient(message)
+		if commit == nil {
+			t.Fatalf("ParseLenient(%q) returned no commit", message)
+		}
+		if commit.Type == "" && len(issues) == 0 {
+			t.Fatalf("ParseLenient(%q) found no issue in a message without type", message)
+		}
+		checkSpans(t, commit)
+	})
+}
+
+// checkSpans checks that the commit's spans lie within the message.
+func checkSpans(t *testing.T, commit *Commit) {
+	t.Helper()
+	lines := strings.Split(commit.Raw, "\n")
+	spans := append([]Span{commit.TypeSpan(), commit.ScopeSpan(), commit.DescriptionSpan(), commit.BreakingSpan()}, commit.ScopeSpans()...)
+	for _, span := range spans {
+		if span.IsZero() {
+			continue
+		}
+		if span.Line < 1 || span.Line > len(lines) || span.Column < 1 || span.EndColumn < span.Column ||
+			span.EndColumn > utf8.RuneCountInString(lines[span.Line-1])+1 {
+			t.Fatalf("span %+v outside message %q", span, commit.Raw)
+		}
+	}
+}
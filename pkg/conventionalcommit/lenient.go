@@ -0,0 +1,132 @@
+package conventionalcommit
+
+import (
+	"regexp"
+	"strings"
+)
+
+// IssueCode classifies how a message deviates from the conventional format.
+type IssueCode string
+
+// Issue codes reported by ParseLenient.
+const (
+	IssueEmptyMessage       IssueCode = "empty_message"
+	IssueNotConventional    IssueCode = "not_conventional"
+	IssueMissingType        IssueCode = "missing_type"
+	IssueMissingColon       IssueCode = "missing_colon"
+	IssueSpaceBeforeColon   IssueCode = "space_before_colon"
+	IssueUnclosedScope      IssueCode = "unclosed_scope"
+	IssueEmptyScope         IssueCode = "empty_scope"
+	IssueEmptyDescription   IssueCode = "empty_description"
+	IssueMissingSpace       IssueCode = "missing_space"
+	IssueTypeCase           IssueCode = "type_case"
+	IssueMissingBlankLine   IssueCode = "missing_blank_line"
+	IssueTrailingWhitespace IssueCode = "trailing_whitespace"
+	IssueTooLarge           IssueCode = "too_large"
+)
+
+// ParseIssue describes one deviation found by ParseLenient.
+type ParseIssue struct {
+	Code    IssueCode
+	Message string
+	// Line is the 1-based line of the issue.
+	Line int
+}
+
+// commonTypes are the widely used commit types, used to recognise "almost
+// conventional" headers such as "feat add login".
+var commonTypes = map[string]bool{
+	"feat": true, "fix": true, "docs": true, "style": true, "refactor": true, "test": true,
+	"chore": true, "perf": true, "ci": true, "build": true, "revert": true,
+}
+
+var (
+	emptyDescriptionRegex = regexp.MustCompile(`^\w+(\([^)]*\))?!?:\s*$`)
+	spaceBeforeColonRegex = regexp.MustCompile(`^\w+(\([^)]*\))?!?\s+:`)
+	unclosedScopeRegex    = regexp.MustCompile(`^\w+\([^)]*$|^\w+\([^)]*:`)
+	missingTypeRegex      = regexp.MustCompile(`^\s*(\([^)]*\))?!?:`)
+	missingColonRegex     = regexp.MustCompile(`^(\w+)(\([^)]*\))?!?\s+\S`)
+)
+
+// ParseLenient parses a message without failing. Messages that are not
+// conventional yield a best-effort Commit (empty Type, the whole subject as
+// Description) with body, footer and tickets still parsed; the returned
+// issues describe every deviation found, including ones Parse tolerates.
+func (p *Parser) ParseLenient(message string) (*Commit, []ParseIssue) {
+	if strings.TrimSpace(message) == "" {
+		return &Commit{Raw: message}, []ParseIssue{{Code: IssueEmptyMessage, Message: "commit message is empty", Line: 1}}
+	}
+	if err := p.CheckSize(message); err != nil {
+		return &Commit{Raw: message}, []ParseIssue{{Code: IssueTooLarge, Message: err.Error(), Line: 1}}
+	}
+
+	lines := strings.Split(message, "\n")
+	header := lines[0]
+
+	var commit *Commit
+	var issues []ParseIssue
+	if matches := conventionalCommitRegex.FindStringSubmatch(header); matches != nil {
+		commit = &Commit{
+			Type:        matches[1],
+			Scope:       matches[3],
+			Scopes:      p.splitScopes(matches[3]),
+			Breaking:    matches[4] == "!",
+			Description: strings.TrimSpace(matches[5]),
+			Raw:         message,
+		}
+		issues = headerIssues(header, matches)
+	} else {
+		commit = &Commit{Description: strings.TrimSpace(header), Raw: message}
+		issues = []ParseIssue{classifyHeader(header)}
+	}
+
+	if strings.TrimRight(header, " \t") != header {
+		issues = append(issues, ParseIssue{Code: IssueTrailingWhitespace, Message: "header has trailing whitespace", Line: 1})
+	}
+	if len(lines) > 1 {
+		if lines[1] != "" {
+			issues = append(issues, ParseIssue{Code: IssueMissingBlankLine, Message: "a blank line must separate the header from the body", Line: 2})
+		}
+		p.parseBodyAndFooter(commit, lines)
+	}
+	commit.TicketRefs = p.parseTicketRefs(message)
+
+	return commit, issues
+}
+
+// headerIssues reports deviations in a header the conventional format
+// regex accepted.
+func headerIssues(header string, matches []string) []ParseIssue {
+	var issues []ParseIssue
+	if matches[1] != strings.ToLower(matches[1]) {
+		issues = append(issues, ParseIssue{Code: IssueTypeCase, Message: "type should be lowercase", Line: 1})
+	}
+	if matches[2] == "()" {
+		issues = append(issues, ParseIssue{Code: IssueEmptyScope, Message: "scope parentheses are empty", Line: 1})
+	}
+	if strings.TrimSpace(matches[5]) == "" {
+		issues = append(issues, ParseIssue{Code: IssueEmptyDescription, Message: "description is empty", Line: 1})
+	}
+	if colon := strings.Index(header, ":"); colon >= 0 && colon+1 < len(header) && header[colon+1] != ' ' {
+		issues = append(issues, ParseIssue{Code: IssueMissingSpace, Message: "a space must follow the colon", Line: 1})
+	}
+	return issues
+}
+
+// classifyHeader explains why a header did not match the conventional format.
+func classifyHeader(header string) ParseIssue {
+	switch {
+	case emptyDescriptionRegex.MatchString(header):
+		return ParseIssue{Code: IssueEmptyDescription, Message: "description is empty", Line: 1}
+	case spaceBeforeColonRegex.MatchString(header):
+		return ParseIssue{Code: IssueSpaceBeforeColon, Message: "no space is allowed before the colon", Line: 1}
+	case unclosedScopeRegex.MatchString(header):
+		return ParseIssue{Code: IssueUnclosedScope, Message: "scope is missing its closing parenthesis", Line: 1}
+	case missingTypeRegex.MatchString(header):
+		return ParseIssue{Code: IssueMissingType, Message: "type is missing before the colon", Line: 1}
+	}
+	if m := missingColonRegex.FindStringSubmatch(header); m != nil && commonTypes[strings.ToLower(m[1])] {
+		return ParseIssue{Code: IssueMissingColon, Message: "a colon must follow the type", Line: 1}
+	}
+	return ParseIssue{Code: IssueNotConventional, Message: "expected 'type(scope): description' format", Line: 1}
+}
@@ -0,0 +1,64 @@
+package conventionalcommit
+
+import (
+	"testing"
+)
+
+func TestParser_ParseLenient(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		wantType    string
+		wantDesc    string
+		wantCodes   []IssueCode
+		wantTickets int
+	}{
+		{name: "conventional", message: "feat(api): add login", wantType: "feat", wantDesc: "add login"},
+		{name: "empty", message: "  \n", wantCodes: []IssueCode{IssueEmptyMessage}},
+		{name: "plain sentence", message: "Update the readme", wantDesc: "Update the readme", wantCodes: []IssueCode{IssueNotConventional}},
+		{name: "missing colon", message: "feat add login", wantDesc: "feat add login", wantCodes: []IssueCode{IssueMissingColon}},
+		{name: "space before colon", message: "fix : handle nil", wantDesc: "fix : handle nil", wantCodes: []IssueCode{IssueSpaceBeforeColon}},
+		{name: "unclosed scope", message: "feat(api: add login", wantDesc: "feat(api: add login", wantCodes: []IssueCode{IssueUnclosedScope}},
+		{name: "missing type", message: "(api): add login", wantDesc: "(api): add login", wantCodes: []IssueCode{IssueMissingType}},
+		{name: "empty description", message: "feat:", wantDesc: "feat:", wantCodes: []IssueCode{IssueEmptyDescription}},
+		{name: "uppercase type", message: "Feat: add login", wantType: "Feat", wantDesc: "add login", wantCodes: []IssueCode{IssueTypeCase}},
+		{name: "no space after colon", message: "feat:add login", wantType: "feat", wantDesc: "add login", wantCodes: []IssueCode{IssueMissingSpace}},
+		{name: "empty scope", message: "feat(): add login", wantType: "feat", wantDesc: "add login", wantCodes: []IssueCode{IssueEmptyScope}},
+		{
+			name:        "body without blank line keeps tickets",
+			message:     "Add login\nCloses PROJ-123",
+			wantDesc:    "Add login",
+			wantCodes:   []IssueCode{IssueNotConventional, IssueMissingBlankLine},
+			wantTickets: 1,
+		},
+	}
+
+	parser := DefaultParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit, issues := parser.ParseLenient(tt.message)
+			if commit == nil {
+				t.Fatal("ParseLenient() returned no commit")
+			}
+			if commit.Type != tt.wantType || commit.Description != tt.wantDesc {
+				t.Errorf("commit = (%q, %q), want (%q, %q)", commit.Type, commit.Description, tt.wantType, tt.wantDesc)
+			}
+			if len(commit.TicketRefs) != tt.wantTickets {
+				t.Errorf("tickets = %v, want %d", commit.TicketRefs, tt.wantTickets)
+			}
+
+			codes := make([]IssueCode, 0, len(issues))
+			for _, issue := range issues {
+				codes = append(codes, issue.Code)
+			}
+			if len(codes) != len(tt.wantCodes) {
+				t.Fatalf("issues = %v, want %v", codes, tt.wantCodes)
+			}
+			for i := range codes {
+				if codes[i] != tt.wantCodes[i] {
+					t.Errorf("issues = %v, want %v", codes, tt.wantCodes)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package conventionalcommit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// DefaultMaxMessageSize is the largest message, in bytes, a parser accepts
+// unless MaxMessageSize says otherwise. Real messages, squash merges
+// included, stay far below it.
+const DefaultMaxMessageSize = 256 << 10
+
+// MaxPatternLength is the longest regular expression CompilePattern accepts.
+const MaxPatternLength = 1024
+
+var (
+	// ErrMessageTooLarge indicates the message exceeds the parser's size limit.
+	ErrMessageTooLarge = errors.New("commit message too large")
+	// ErrPatternTooLong indicates a pattern exceeds MaxPatternLength.
+	ErrPatternTooLong = errors.New("pattern too long")
+)
+
+// CheckSize returns ErrMessageTooLarge when message exceeds the parser's
+// size limit. Parse and ParseLenient check it first, so oversized input from
+// server-side hooks is rejected before any pattern runs on it.
+func (p *Parser) CheckSize(message string) error {
+	limit := p.MaxMessageSize
+	if limit <= 0 {
+		limit = DefaultMaxMessageSize
+	}
+	if len(message) > limit {
+		return fmt.Errorf("%w: %d bytes (limit %d)", ErrMessageTooLarge, len(message), limit)
+	}
+	return nil
+}
+
+// CompilePattern compiles a user-supplied regular expression, such as a
+// ticket pattern or custom rule. Go's RE2 engine matches in linear time, so
+// no input can make a pattern backtrack; the length limit bounds the size
+// of the compiled program as well.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) > MaxPatternLength {
+		return nil, fmt.Errorf("%w: %d characters (limit %d)", ErrPatternTooLong, len(pattern), MaxPatternLength)
+	}
+	return regexp.Compile(pattern)
+}
@@ -0,0 +1,58 @@
+package conventionalcommit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParser_CheckSize(t *testing.T) {
+	p := DefaultParser()
+	big := "feat: add login\n\n" + strings.Repeat("x", DefaultMaxMessageSize)
+	if _, err := p.Parse(big); !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("Parse() of %d bytes error = %v, want ErrMessageTooLarge", len(big), err)
+	}
+	if _, issues := p.ParseLenient(big); len(issues) != 1 || issues[0].Code != IssueTooLarge {
+		t.Errorf("ParseLenient() issues = %v, want one %s issue", issues, IssueTooLarge)
+	}
+
+	p.MaxMessageSize = 20
+	if _, err := p.Parse("feat: add login"); err != nil {
+		t.Errorf("Parse() under the limit error = %v", err)
+	}
+	if _, err := p.Parse("feat: add login to the app"); !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("Parse() over MaxMessageSize error = %v, want ErrMessageTooLarge", err)
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	if _, err := CompilePattern(`SNOW-\d+`); err != nil {
+		t.Errorf("CompilePattern() error = %v", err)
+	}
+	if _, err := CompilePattern(strings.Repeat("a", MaxPatternLength+1)); !errors.Is(err, ErrPatternTooLong) {
+		t.Errorf("CompilePattern() of a long pattern error = %v, want ErrPatternTooLong", err)
+	}
+	if _, err := CompilePattern(`(a+`); err == nil {
+		t.Error("CompilePattern() of an invalid pattern succeeded")
+	}
+}
+
+// TestParser_PathologicalInput guards against patterns that would backtrack
+// on crafted headers; each parse must finish on input near the size limit.
+func TestParser_PathologicalInput(t *testing.T) {
+	p := DefaultParser()
+	p.ScopeDelimiters = ","
+	n := DefaultMaxMessageSize - 64
+	inputs := []string{
+		strings.Repeat("a", n),
+		"feat(" + strings.Repeat("(", n),
+		"feat(" + strings.Repeat("a,", n/2) + "): x",
+		"feat: " + strings.Repeat("#1 GH-", n/6),
+		"feat: x\n\n" + strings.Repeat("Key: v\n ", n/9),
+		strings.Repeat("ABCD-1 [ABCD-", n/13),
+	}
+	for _, input := range inputs {
+		_, _ = p.Parse(input)
+		_, _ = p.ParseLenient(input)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -17,14 +18,29 @@ var (
 
 // Commit represents a parsed conventional commit message.
 type Commit struct {
-	Type        string
-	Scope       string
+	Type  string
+	Scope string
+	// Scopes are the individual scopes of Scope, split on the parser's
+	// ScopeDelimiters (a single scope otherwise).
+	Scopes      []string
 	Description string
 	Body        string
 	Footer      string
 	Raw         string
 	TicketRefs  []TicketRef
-	Breaking    bool
+	// Trailers are the footer's "Key: value" (or "Key #value") lines.
+	Trailers []Trailer
+	Breaking bool
+	// BreakingDescription is the value of the "BREAKING CHANGE:" (or
+	// "BREAKING-CHANGE:") footer, describing what broke.
+	BreakingDescription string
+}
+
+// Trailer is a footer line such as "Reviewed-by: Jane Doe". Continuation
+// lines (starting with whitespace) are joined to the value with newlines.
+type Trailer struct {
+	Key   string
+	Value string
 }
 
 // TicketRef represents a ticket reference (e.g., JIRA ticket).
@@ -32,6 +48,7 @@ type TicketRef struct {
 	Type string // e.g., "JIRA", "GITHUB", "LINEAR"
 	ID   string // e.g., "PROJ-123", "#456", "ABC-789"
 	Raw  string // Original reference as found in commit
+	URL  string // Link from the ticket pattern's URL template, if any
 }
 
 // Parser provides conventional commit parsing with configurable options.
@@ -40,6 +57,15 @@ type Parser struct {
 	StrictMode bool
 	// AllowEmptyScope permits commits without scope.
 	AllowEmptyScope bool
+	// ScopeDelimiters lists the characters separating multiple scopes, as
+	// in "feat(api,web)" (empty allows a single scope).
+	ScopeDelimiters string
+	// MaxMessageSize is the largest message accepted, in bytes
+	// (DefaultMaxMessageSize when zero).
+	MaxMessageSize int
+
+	// ticketPatterns are extra ticket formats set with SetTicketPatterns.
+	ticketPatterns []compiledTicketPattern
 }
 
 // DefaultParser returns a parser with default settings.
@@ -71,6 +97,9 @@ func (p *Parser) Parse(message string) (*Commit, error) {
 	if message == "" {
 		return nil, ErrEmptyMessage
 	}
+	if err := p.CheckSize(message); err != nil {
+		return nil, err
+	}
 
 	lines := strings.Split(message, "\n")
 	if len(lines) == 0 {
@@ -93,7 +122,7 @@ func (p *Parser) Parse(message string) (*Commit, error) {
 	}
 
 	// Parse ticket references from entire commit message
-	commit.TicketRefs = parseTicketRefs(message)
+	commit.TicketRefs = p.parseTicketRefs(message)
 
 	return commit, nil
 }
@@ -116,185 +145,240 @@ func (p *Parser) parseHeader(header, fullMessage string) (*Commit, error) {
 	return &Commit{
 		Type:        matches[1],
 		Scope:       matches[3],
+		Scopes:      p.splitScopes(matches[3]),
 		Breaking:    matches[4] == "!",
 		Description: matches[5],
 		Raw:         fullMessage,
 	}, nil
 }
 
-// parseBodyAndFooter parses the body and footer sections of a commit message.
+// splitScopes splits a scope on the configured delimiters, trimming spaces.
+// Empty entries are kept so validation can report them.
+func (p *Parser) splitScopes(scope string) []string {
+	if scope == "" {
+		return nil
+	}
+	if p.ScopeDelimiters == "" {
+		return []string{scope}
+	}
+
+	var scopes []string
+	for {
+		i := strings.IndexAny(scope, p.ScopeDelimiters)
+		if i < 0 {
+			return append(scopes, strings.TrimSpace(scope))
+		}
+		scopes = append(scopes, strings.TrimSpace(scope[:i]))
+		scope = scope[i+1:]
+	}
+}
+
+// trailerRegex matches a trailer line following the conventional commits
+// footer rules: a token (words joined by hyphens, or "BREAKING CHANGE")
+// followed by ": " or " #".
+var trailerRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*|BREAKING CHANGE)(?:: | #)(.*)$`)
+
+// parseBodyAndFooter parses the body and footer sections of a commit
+// message. As with git trailers, the footer is the last paragraph when all
+// of its lines are trailers or their continuation lines.
 func (p *Parser) parseBodyAndFooter(commit *Commit, lines []string) {
 	bodyStart := 1
 	// Skip empty line after header if present.
 	if bodyStart < len(lines) && lines[bodyStart] == "" {
 		bodyStart++
 	}
+	end := len(lines)
+	for end > bodyStart && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
 
-	// Find footer (starts with BREAKING CHANGE: or contains : ).
-	footerStart := p.findFooterStart(lines, bodyStart)
-
-	// Set body
-	if footerStart > bodyStart {
-		commit.Body = strings.TrimSpace(strings.Join(lines[bodyStart:footerStart], "\n"))
-	} else if footerStart == -1 && bodyStart < len(lines) {
-		commit.Body = strings.TrimSpace(strings.Join(lines[bodyStart:], "\n"))
+	footerStart := findFooterStart(lines[:end], bodyStart)
+	if footerStart == -1 {
+		commit.Body = strings.TrimSpace(strings.Join(lines[bodyStart:end], "\n"))
+		return
 	}
 
-	// Set footer and check for breaking changes
-	if footerStart != -1 {
-		commit.Footer = strings.TrimSpace(strings.Join(lines[footerStart:], "\n"))
-		if p.hasBreakingChangeInFooter(commit.Footer) {
-			commit.Breaking = true
-		}
+	commit.Body = strings.TrimSpace(strings.Join(lines[bodyStart:footerStart], "\n"))
+	commit.Footer = strings.TrimSpace(strings.Join(lines[footerStart:end], "\n"))
+	commit.Trailers = parseTrailers(lines[footerStart:end])
+	if description, ok := p.breakingChangeInFooter(commit.Trailers); ok {
+		commit.Breaking = true
+		commit.BreakingDescription = description
 	}
 }
 
-// findFooterStart finds the starting line index of the footer section.
-func (p *Parser) findFooterStart(lines []string, bodyStart int) int {
-	footerStart := -1
-	for i := len(lines) - 1; i >= bodyStart; i-- {
-		line := lines[i]
-		if p.isBreakingChangeLine(line) || isFooterLine(line) {
-			footerStart = i
-		} else if line != "" && footerStart == -1 {
-			// Non-footer line found, stop looking.
-			break
+// findFooterStart returns the index of the footer's first line, or -1 when
+// the last paragraph is not made of trailers.
+func findFooterStart(lines []string, bodyStart int) int {
+	start := len(lines)
+	for start > bodyStart && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	if start == len(lines) || !trailerRegex.MatchString(lines[start]) {
+		return -1
+	}
+	for _, line := range lines[start+1:] {
+		if !trailerRegex.MatchString(line) && !isContinuationLine(line) {
+			return -1
 		}
 	}
-	return footerStart
+	return start
 }
 
-// isBreakingChangeLine checks if a line indicates a breaking change.
-func (*Parser) isBreakingChangeLine(line string) bool {
-	return strings.HasPrefix(line, "BREAKING CHANGE:") ||
-		strings.HasPrefix(line, "BREAKING-CHANGE:")
+// parseTrailers splits footer lines into trailers.
+func parseTrailers(lines []string) []Trailer {
+	var trailers []Trailer
+	// values collects each trailer's lines, joined once at the end so long
+	// continuations stay linear.
+	var values [][]string
+	for _, line := range lines {
+		if isContinuationLine(line) && len(trailers) > 0 {
+			values[len(values)-1] = append(values[len(values)-1], strings.TrimSpace(line))
+			continue
+		}
+		if matches := trailerRegex.FindStringSubmatch(line); matches != nil {
+			trailers = append(trailers, Trailer{Key: matches[1]})
+			values = append(values, []string{strings.TrimSpace(matches[2])})
+		}
+	}
+	for i := range trailers {
+		trailers[i].Value = strings.Join(values[i], "\n")
+	}
+	return trailers
 }
 
-// hasBreakingChangeInFooter checks if the footer contains breaking change indicators.
-func (*Parser) hasBreakingChangeInFooter(footer string) bool {
-	return strings.Contains(footer, "BREAKING CHANGE:") ||
-		strings.Contains(footer, "BREAKING-CHANGE:")
+// isContinuationLine reports whether a footer line continues the previous trailer.
+func isContinuationLine(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
 }
 
-// isFooterLine checks if a line looks like a footer token.
-func isFooterLine(line string) bool {
-	// Common footer tokens.
-	footerTokens := []string{
-		"Signed-off-by:",
-		"Co-authored-by:",
-		"Fixes:",
-		"Closes:",
-		"Refs:",
-		"See-also:",
+// breakingChangeInFooter returns the description of the first breaking
+// change trailer, and whether the footer has one.
+func (*Parser) breakingChangeInFooter(trailers []Trailer) (string, bool) {
+	for _, trailer := range trailers {
+		if trailer.Key == "BREAKING CHANGE" || trailer.Key == "BREAKING-CHANGE" {
+			return trailer.Value, true
+		}
 	}
+	return "", false
+}
 
-	for _, token := range footerTokens {
-		if strings.HasPrefix(line, token) {
-			return true
+// GetTrailer returns the value of the first trailer with the key, compared
+// case-insensitively (for example GetTrailer("Reviewed-by")).
+func (c *Commit) GetTrailer(key string) (string, bool) {
+	for _, trailer := range c.Trailers {
+		if strings.EqualFold(trailer.Key, key) {
+			return trailer.Value, true
 		}
 	}
+	return "", false
+}
 
-	// Generic token format: Word-Word: or Word:.
-	matched, err := regexp.MatchString(`^[A-Z][a-z]+(-[A-Z][a-z]+)*:\s+`, line)
-	if err != nil {
-		// If regex compilation fails, return false to be safe.
-		return false
-	}
-	if matched {
-		return true
+// GetTrailers returns the values of every trailer with the key, in order.
+func (c *Commit) GetTrailers(key string) []string {
+	var values []string
+	for _, trailer := range c.Trailers {
+		if strings.EqualFold(trailer.Key, key) {
+			values = append(values, trailer.Value)
+		}
 	}
-
-	return false
+	return values
 }
 
-// parseTicketRefs extracts ticket references from a commit message.
-func parseTicketRefs(message string) []TicketRef {
+// parseTicketRefs extracts ticket references from a commit message, in the
+// order they first appear.
+func (p *Parser) parseTicketRefs(message string) []TicketRef {
 	var refs []TicketRef
 	seen := make(map[string]bool)
+	positions := make(map[string]int)
 
-	refs = parseGithubRefs(message, refs, seen)
-	refs = parseGenericRefs(message, refs, seen)
-	refs = parseJiraRefs(message, refs, seen)
+	refs, message = p.parseCustomRefs(message, refs, seen, positions)
+	refs = parseGithubRefs(message, refs, seen, positions)
+	refs = parseGenericRefs(message, refs, seen, positions)
+	refs = parseJiraRefs(message, refs, seen, positions)
 
+	sort.SliceStable(refs, func(i, j int) bool {
+		return positions[refKey(refs[i])] < positions[refKey(refs[j])]
+	})
 	return refs
 }
 
 // parseGithubRefs extracts GitHub issue references.
-func parseGithubRefs(message string, refs []TicketRef, seen map[string]bool) []TicketRef {
-	matches := githubTicketRegex.FindAllStringSubmatch(message, -1)
-	for _, match := range matches {
-		if len(match) >= 3 {
-			var id string
-			if match[1] != "" { // #123 format.
-				id = match[1]
-			} else if match[2] != "" { // GH-456 format.
-				id = match[2]
-			}
-			if id != "" {
-				ref := TicketRef{
-					Type: "GITHUB",
-					ID:   id,
-					Raw:  match[0],
-				}
-				refs = addUniqueRef(refs, ref, seen)
+func parseGithubRefs(message string, refs []TicketRef, seen map[string]bool, positions map[string]int) []TicketRef {
+	for _, match := range githubTicketRegex.FindAllStringSubmatchIndex(message, -1) {
+		var id string
+		if match[2] >= 0 { // #123 format.
+			id = message[match[2]:match[3]]
+		} else if match[4] >= 0 { // GH-456 format.
+			id = message[match[4]:match[5]]
+		}
+		if id != "" {
+			ref := TicketRef{
+				Type: "GITHUB",
+				ID:   id,
+				Raw:  message[match[0]:match[1]],
 			}
+			refs = addUniqueRef(refs, ref, seen, positions, match[0])
 		}
 	}
 	return refs
 }
 
 // parseGenericRefs extracts generic bracketed ticket references.
-func parseGenericRefs(message string, refs []TicketRef, seen map[string]bool) []TicketRef {
-	matches := genericTicketRegex.FindAllStringSubmatch(message, -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			ref := TicketRef{
-				Type: "GENERIC",
-				ID:   match[1],
-				Raw:  match[0],
-			}
-			refs = addUniqueRef(refs, ref, seen)
+func parseGenericRefs(message string, refs []TicketRef, seen map[string]bool, positions map[string]int) []TicketRef {
+	for _, match := range genericTicketRegex.FindAllStringSubmatchIndex(message, -1) {
+		ref := TicketRef{
+			Type: "GENERIC",
+			ID:   message[match[2]:match[3]],
+			Raw:  message[match[0]:match[1]],
 		}
+		refs = addUniqueRef(refs, ref, seen, positions, match[0])
 	}
 	return refs
 }
 
 // parseJiraRefs extracts JIRA ticket references.
-func parseJiraRefs(message string, refs []TicketRef, seen map[string]bool) []TicketRef {
-	matches := jiraTicketRegex.FindAllStringSubmatch(message, -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			// Check if this was already classified as generic or github.
-			if isAlreadyClassified(match[1], seen) {
-				continue
-			}
+func parseJiraRefs(message string, refs []TicketRef, seen map[string]bool, positions map[string]int) []TicketRef {
+	for _, match := range jiraTicketRegex.FindAllStringSubmatchIndex(message, -1) {
+		id := message[match[2]:match[3]]
 
-			// Skip GitHub-style references (GH-123 format).
-			if strings.HasPrefix(match[1], "GH-") {
-				continue
-			}
+		// Check if this was already classified as generic or github.
+		if isAlreadyClassified(id, seen) {
+			continue
+		}
 
-			ref := TicketRef{
-				Type: "JIRA",
-				ID:   match[1],
-				Raw:  match[0],
-			}
-			refs = addUniqueRef(refs, ref, seen)
+		// Skip GitHub-style references (GH-123 format).
+		if strings.HasPrefix(id, "GH-") {
+			continue
+		}
+
+		ref := TicketRef{
+			Type: "JIRA",
+			ID:   id,
+			Raw:  message[match[0]:match[1]],
 		}
+		refs = addUniqueRef(refs, ref, seen, positions, match[0])
 	}
 	return refs
 }
 
-// addUniqueRef adds a ticket reference if it hasn't been seen before.
-func addUniqueRef(refs []TicketRef, ref TicketRef, seen map[string]bool) []TicketRef {
-	key := ref.Type + ":" + ref.ID
+// addUniqueRef adds a ticket reference if it hasn't been seen before,
+// remembering where it first appeared.
+func addUniqueRef(refs []TicketRef, ref TicketRef, seen map[string]bool, positions map[string]int, pos int) []TicketRef {
+	key := refKey(ref)
 	if !seen[key] {
 		refs = append(refs, ref)
 		seen[key] = true
+		positions[key] = pos
 	}
 	return refs
 }
 
+// refKey identifies a ticket reference for deduplication.
+func refKey(ref TicketRef) string {
+	return ref.Type + ":" + ref.ID
+}
+
 // isAlreadyClassified checks if a ticket ID was already classified.
 func isAlreadyClassified(id string, seen map[string]bool) bool {
 	genericKey := "GENERIC:" + id
@@ -334,9 +418,9 @@ func (c *Commit) Format() string {
 
 	// Write header.
 	sb.WriteString(c.Type)
-	if c.Scope != "" {
+	if scope := c.scope(); scope != "" {
 		sb.WriteString("(")
-		sb.WriteString(c.Scope)
+		sb.WriteString(scope)
 		sb.WriteString(")")
 	}
 	if c.Breaking {
@@ -360,13 +444,22 @@ func (c *Commit) Format() string {
 	return sb.String()
 }
 
+// scope returns the header scope: Scope as written, or Scopes joined with
+// commas for commits built without one.
+func (c *Commit) scope() string {
+	if c.Scope == "" && len(c.Scopes) > 0 {
+		return strings.Join(c.Scopes, ",")
+	}
+	return c.Scope
+}
+
 // Header returns the first line of the commit message.
 func (c *Commit) Header() string {
 	var sb strings.Builder
 	sb.WriteString(c.Type)
-	if c.Scope != "" {
+	if scope := c.scope(); scope != "" {
 		sb.WriteString("(")
-		sb.WriteString(c.Scope)
+		sb.WriteString(scope)
 		sb.WriteString(")")
 	}
 	if c.Breaking {
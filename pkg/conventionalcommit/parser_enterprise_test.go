@@ -18,6 +18,7 @@ func TestParser_ParseEnterpriseFormats(t *testing.T) {
 			want: &Commit{
 				Type:        "feat",
 				Scope:       "db",
+				Scopes:      []string{"db"},
 				Description: "CGC-1425 Added new database",
 				Raw:         "feat(db): CGC-1425 Added new database",
 				TicketRefs:  []TicketRef{{Type: "JIRA", ID: "CGC-1425", Raw: "CGC-1425"}},
@@ -29,6 +30,7 @@ func TestParser_ParseEnterpriseFormats(t *testing.T) {
 			want: &Commit{
 				Type:        "fix",
 				Scope:       "api",
+				Scopes:      []string{"api"},
 				Description: "CGC-99 Fixed authentication issue",
 				Raw:         "fix(api): CGC-99 Fixed authentication issue",
 				TicketRefs:  []TicketRef{{Type: "JIRA", ID: "CGC-99", Raw: "CGC-99"}},
@@ -40,6 +42,7 @@ func TestParser_ParseEnterpriseFormats(t *testing.T) {
 			want: &Commit{
 				Type:        "docs",
 				Scope:       "web",
+				Scopes:      []string{"web"},
 				Description: "CGC-12345 Updated API documentation",
 				Raw:         "docs(web): CGC-12345 Updated API documentation",
 				TicketRefs:  []TicketRef{{Type: "JIRA", ID: "CGC-12345", Raw: "CGC-12345"}},
@@ -51,6 +54,7 @@ func TestParser_ParseEnterpriseFormats(t *testing.T) {
 			want: &Commit{
 				Type:        "feat",
 				Scope:       "core",
+				Scopes:      []string{"core"},
 				Description: "CGC-567 Implemented new validation",
 				Body:        "This adds comprehensive input validation.",
 				Raw:         "feat(core): CGC-567 Implemented new validation\n\nThis adds comprehensive input validation.",
@@ -63,6 +67,7 @@ func TestParser_ParseEnterpriseFormats(t *testing.T) {
 			want: &Commit{
 				Type:        "feat",
 				Scope:       "db",
+				Scopes:      []string{"db"},
 				Description: "CGC-890 Breaking database schema change",
 				Breaking:    true,
 				Raw:         "feat(db)!: CGC-890 Breaking database schema change",
@@ -75,11 +80,13 @@ func TestParser_ParseEnterpriseFormats(t *testing.T) {
 			want: &Commit{
 				Type:        "fix",
 				Scope:       "auth",
+				Scopes:      []string{"auth"},
 				Description: "CGC-2001 Fixed token expiration",
-				Body:        "Fixes: CGC-2001\nReviewed-by: John Doe",
-				Footer:      "",
+				Body:        "",
+				Footer:      "Fixes: CGC-2001\nReviewed-by: John Doe",
 				Raw:         "fix(auth): CGC-2001 Fixed token expiration\n\nFixes: CGC-2001\nReviewed-by: John Doe",
 				TicketRefs:  []TicketRef{{Type: "JIRA", ID: "CGC-2001", Raw: "CGC-2001"}},
+				Trailers:    []Trailer{{Key: "Fixes", Value: "CGC-2001"}, {Key: "Reviewed-by", Value: "John Doe"}},
 			},
 		},
 		{
@@ -88,6 +95,7 @@ func TestParser_ParseEnterpriseFormats(t *testing.T) {
 			want: &Commit{
 				Type:        "feat",
 				Scope:       "api",
+				Scopes:      []string{"api"},
 				Description: "CGC-100 PROJ-200 Added multi-tenant support",
 				Raw:         "feat(api): CGC-100 PROJ-200 Added multi-tenant support",
 				TicketRefs: []TicketRef{
@@ -102,11 +110,12 @@ func TestParser_ParseEnterpriseFormats(t *testing.T) {
 			want: &Commit{
 				Type:        "fix",
 				Scope:       "cli",
+				Scopes:      []string{"cli"},
 				Description: "CAVH-3334 Fixed CLI parsing (#456)",
 				Raw:         "fix(cli): CAVH-3334 Fixed CLI parsing (#456)",
 				TicketRefs: []TicketRef{
-					{Type: "GITHUB", ID: "456", Raw: "#456"},
 					{Type: "JIRA", ID: "CAVH-3334", Raw: "CAVH-3334"},
+					{Type: "GITHUB", ID: "456", Raw: "#456"},
 				},
 			},
 		},
@@ -361,9 +370,9 @@ func TestParser_EnterpriseTicketPatterns(t *testing.T) {
 			name:    "CGC with other formats",
 			message: "fix: resolve CGC-999 #123 [TASK-456]",
 			expected: []TicketRef{
+				{Type: "JIRA", ID: "CGC-999", Raw: "CGC-999"},
 				{Type: "GITHUB", ID: "123", Raw: "#123"},
 				{Type: "GENERIC", ID: "TASK-456", Raw: "[TASK-456]"},
-				{Type: "JIRA", ID: "CGC-999", Raw: "CGC-999"},
 			},
 		},
 		{
@@ -405,7 +414,7 @@ Fixes: CGC-1001, CGC-1002, CGC-1003, CGC-1004`,
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			refs := parseTicketRefs(tt.message)
+			refs := DefaultParser().parseTicketRefs(tt.message)
 
 			if len(refs) != len(tt.expected) {
 				t.Fatalf("parseTicketRefs() returned %d refs, expected %d\nGot: %+v\nExpected: %+v",
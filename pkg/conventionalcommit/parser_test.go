@@ -32,6 +32,7 @@ func TestParser_Parse(t *testing.T) {
 			want: &Commit{
 				Type:        "fix",
 				Scope:       "api",
+				Scopes:      []string{"api"},
 				Description: "resolve authentication issue",
 				Raw:         "fix(api): resolve authentication issue",
 			},
@@ -52,6 +53,7 @@ func TestParser_Parse(t *testing.T) {
 			want: &Commit{
 				Type:        "refactor",
 				Scope:       "core",
+				Scopes:      []string{"core"},
 				Breaking:    true,
 				Description: "reorganize module structure",
 				Raw:         "refactor(core)!: reorganize module structure",
@@ -77,17 +79,20 @@ func TestParser_Parse(t *testing.T) {
 				Footer:      "Fixes: #123",
 				Raw:         "fix: bug fix\n\nSome body text\n\nFixes: #123",
 				TicketRefs:  []TicketRef{{Type: "GITHUB", ID: "123", Raw: "#123"}},
+				Trailers:    []Trailer{{Key: "Fixes", Value: "#123"}},
 			},
 		},
 		{
 			name:    "breaking change in footer",
 			message: "feat: new feature\n\nBREAKING CHANGE: This breaks the API",
 			want: &Commit{
-				Type:        "feat",
-				Description: "new feature",
-				Breaking:    true,
-				Footer:      "BREAKING CHANGE: This breaks the API",
-				Raw:         "feat: new feature\n\nBREAKING CHANGE: This breaks the API",
+				Type:                "feat",
+				Description:         "new feature",
+				Breaking:            true,
+				Footer:              "BREAKING CHANGE: This breaks the API",
+				Raw:                 "feat: new feature\n\nBREAKING CHANGE: This breaks the API",
+				Trailers:            []Trailer{{Key: "BREAKING CHANGE", Value: "This breaks the API"}},
+				BreakingDescription: "This breaks the API",
 			},
 		},
 		{
@@ -109,6 +114,7 @@ func TestParser_Parse(t *testing.T) {
 				Body:        "Body",
 				Footer:      "Signed-off-by: John Doe\nCo-authored-by: Jane Doe",
 				Raw:         "feat: feature\n\nBody\n\nSigned-off-by: John Doe\nCo-authored-by: Jane Doe",
+				Trailers:    []Trailer{{Key: "Signed-off-by", Value: "John Doe"}, {Key: "Co-authored-by", Value: "Jane Doe"}},
 			},
 		},
 		{
@@ -157,6 +163,7 @@ func TestCommit_Format(t *testing.T) {
 			commit: &Commit{
 				Type:        "fix",
 				Scope:       "api",
+				Scopes:      []string{"api"},
 				Description: "fix bug",
 			},
 			want: "fix(api): fix bug",
@@ -175,6 +182,7 @@ func TestCommit_Format(t *testing.T) {
 			commit: &Commit{
 				Type:        "feat",
 				Scope:       "core",
+				Scopes:      []string{"core"},
 				Breaking:    true,
 				Description: "major update",
 				Body:        "This is the body",
@@ -212,6 +220,7 @@ func TestCommit_Header(t *testing.T) {
 			commit: &Commit{
 				Type:        "fix",
 				Scope:       "api",
+				Scopes:      []string{"api"},
 				Breaking:    true,
 				Description: "fix critical bug",
 			},
@@ -321,9 +330,9 @@ func TestParseTicketRefs(t *testing.T) {
 			name:    "mixed ticket types",
 			message: "feat: implement auth PROJ-123 #456 [ABC-789]",
 			expected: []TicketRef{
+				{Type: "JIRA", ID: "PROJ-123", Raw: "PROJ-123"},
 				{Type: "GITHUB", ID: "456", Raw: "#456"},
 				{Type: "GENERIC", ID: "ABC-789", Raw: "[ABC-789]"},
-				{Type: "JIRA", ID: "PROJ-123", Raw: "PROJ-123"},
 			},
 		},
 		{
@@ -335,8 +344,8 @@ Implements OAuth2 login flow with JWT tokens.
 Fixes PROJ-123
 Closes #456`,
 			expected: []TicketRef{
-				{Type: "GITHUB", ID: "456", Raw: "#456"},
 				{Type: "JIRA", ID: "PROJ-123", Raw: "PROJ-123"},
+				{Type: "GITHUB", ID: "456", Raw: "#456"},
 			},
 		},
 		{
@@ -344,6 +353,16 @@ Closes #456`,
 			message:  "feat: add new feature without references",
 			expected: []TicketRef{},
 		},
+		{
+			name:    "order of first appearance",
+			message: "feat: [ABC-789] tidy up #456\n\nRefs: PROJ-123, ABC-789, #12",
+			expected: []TicketRef{
+				{Type: "GENERIC", ID: "ABC-789", Raw: "[ABC-789]"},
+				{Type: "GITHUB", ID: "456", Raw: "#456"},
+				{Type: "JIRA", ID: "PROJ-123", Raw: "PROJ-123"},
+				{Type: "GITHUB", ID: "12", Raw: "#12"},
+			},
+		},
 		{
 			name:    "duplicate tickets",
 			message: "feat: implement PROJ-123 and fix PROJ-123",
@@ -355,7 +374,7 @@ Closes #456`,
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			refs := parseTicketRefs(tt.message)
+			refs := DefaultParser().parseTicketRefs(tt.message)
 
 			if len(refs) != len(tt.expected) {
 				t.Fatalf("parseTicketRefs() returned %d refs, expected %d", len(refs), len(tt.expected))
@@ -552,3 +571,175 @@ Fixes: #789`,
 		})
 	}
 }
+
+func TestParser_ParseTrailers(t *testing.T) {
+	tests := []struct {
+		name         string
+		message      string
+		wantBody     string
+		wantTrailers []Trailer
+	}{
+		{
+			name:         "lowercase token words",
+			message:      "fix: token expiry\n\nReviewed-by: Jane Doe\nCloses #12",
+			wantTrailers: []Trailer{{Key: "Reviewed-by", Value: "Jane Doe"}, {Key: "Closes", Value: "12"}},
+		},
+		{
+			name:         "continuation line",
+			message:      "feat!: drop v1\n\nBody\n\nBREAKING CHANGE: the v1 API is gone,\n  use v2 instead\nRefs: PROJ-1",
+			wantBody:     "Body",
+			wantTrailers: []Trailer{{Key: "BREAKING CHANGE", Value: "the v1 API is gone,\nuse v2 instead"}, {Key: "Refs", Value: "PROJ-1"}},
+		},
+		{
+			name:     "last paragraph with prose is body",
+			message:  "docs: notes\n\nNote: this is prose\nthat continues here",
+			wantBody: "Note: this is prose\nthat continues here",
+		},
+		{
+			name:         "trailing blank lines",
+			message:      "fix: x\n\nBody text\n\nSigned-off-by: Dev <dev@example.com>\n\n",
+			wantBody:     "Body text",
+			wantTrailers: []Trailer{{Key: "Signed-off-by", Value: "Dev <dev@example.com>"}},
+		},
+	}
+
+	parser := DefaultParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit, err := parser.Parse(tt.message)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if commit.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", commit.Body, tt.wantBody)
+			}
+			if !reflect.DeepEqual(commit.Trailers, tt.wantTrailers) {
+				t.Errorf("Trailers = %+v, want %+v", commit.Trailers, tt.wantTrailers)
+			}
+		})
+	}
+}
+
+func TestCommit_GetTrailer(t *testing.T) {
+	commit := &Commit{Trailers: []Trailer{
+		{Key: "Reviewed-by", Value: "Jane"},
+		{Key: "Co-authored-by", Value: "Ann"},
+		{Key: "reviewed-by", Value: "Bob"},
+	}}
+
+	if value, ok := commit.GetTrailer("Reviewed-By"); !ok || value != "Jane" {
+		t.Errorf("GetTrailer() = %q, %v", value, ok)
+	}
+	if _, ok := commit.GetTrailer("Signed-off-by"); ok {
+		t.Error("GetTrailer() found a missing trailer")
+	}
+	if values := commit.GetTrailers("reviewed-by"); !reflect.DeepEqual(values, []string{"Jane", "Bob"}) {
+		t.Errorf("GetTrailers() = %v", values)
+	}
+}
+
+func TestCommit_Reviews(t *testing.T) {
+	commit, err := DefaultParser().Parse("feat: add login\n\nreviewed-by: Jane Doe <jane@example.com>\nSigned-off-by: Bob <bob@example.com>\nTested-by: ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Review{
+		{Key: "Reviewed-by", Name: "Jane Doe", Email: "jane@example.com", Value: "Jane Doe <jane@example.com>"},
+		{Key: "Tested-by", Value: "ci"},
+	}
+	if reviews := commit.Reviews(); !reflect.DeepEqual(reviews, want) {
+		t.Errorf("Reviews() = %+v, want %+v", reviews, want)
+	}
+}
+
+func TestParser_SetTicketPatterns(t *testing.T) {
+	parser := DefaultParser()
+	err := parser.SetTicketPatterns([]TicketPattern{
+		{Name: "SNOW", Pattern: `\bSNOW-\d+\b`, URLTemplate: "https://acme.service-now.com/task.do?number={id}"},
+		{Name: "ADO", Pattern: `\bAB#(\d+)\b`},
+	})
+	if err != nil {
+		t.Fatalf("SetTicketPatterns() error = %v", err)
+	}
+
+	commit, err := parser.Parse("fix(api): handle timeouts SNOW-42\n\nRefs: AB#7, CGC-1")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []TicketRef{
+		{Type: "SNOW", ID: "SNOW-42", Raw: "SNOW-42", URL: "https://acme.service-now.com/task.do?number=SNOW-42"},
+		{Type: "ADO", ID: "7", Raw: "AB#7"},
+		{Type: "JIRA", ID: "CGC-1", Raw: "CGC-1"},
+	}
+	if !reflect.DeepEqual(commit.TicketRefs, want) {
+		t.Errorf("TicketRefs = %+v, want %+v", commit.TicketRefs, want)
+	}
+
+	if err := parser.SetTicketPatterns([]TicketPattern{{Name: "BAD", Pattern: "("}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestParser_MultipleScopes(t *testing.T) {
+	parser := DefaultParser()
+	parser.ScopeDelimiters = ",/"
+
+	tests := map[string][]string{
+		"feat(api,web): add login":   {"api", "web"},
+		"feat(api/web): add login":   {"api", "web"},
+		"feat(api, web,): add login": {"api", "web", ""},
+		"feat(api): add login":       {"api"},
+		"feat: add login":            nil,
+	}
+	for message, want := range tests {
+		commit, err := parser.Parse(message)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", message, err)
+		}
+		if !reflect.DeepEqual(commit.Scopes, want) {
+			t.Errorf("Parse(%q).Scopes = %q, want %q", message, commit.Scopes, want)
+		}
+	}
+
+	if got := DefaultParser().splitScopes("api,web"); !reflect.DeepEqual(got, []string{"api,web"}) {
+		t.Errorf("expected a single scope without delimiters, got %q", got)
+	}
+	commit := &Commit{Type: "feat", Scopes: []string{"api", "web"}, Description: "add login"}
+	if got := commit.Format(); got != "feat(api,web): add login" {
+		t.Errorf("Format() = %q", got)
+	}
+}
+
+func TestCommit_Spans(t *testing.T) {
+	parser := DefaultParser()
+	parser.ScopeDelimiters = ","
+	commit, err := parser.Parse("feat(api, web)!: add login\n\nVoilà PROJ-12\n\nBREAKING CHANGE: tokens expire")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		got, want Span
+	}{
+		"type":        {commit.TypeSpan(), Span{Line: 1, Column: 1, EndColumn: 5}},
+		"scope":       {commit.ScopeSpan(), Span{Line: 1, Column: 6, EndColumn: 14}},
+		"second":      {commit.ScopeSpans()[1], Span{Line: 1, Column: 11, EndColumn: 14}},
+		"breaking":    {commit.BreakingSpan(), Span{Line: 1, Column: 15, EndColumn: 16}},
+		"description": {commit.DescriptionSpan(), Span{Line: 1, Column: 18, EndColumn: 27}},
+		"ticket":      {commit.Locate("PROJ-12"), Span{Line: 3, Column: 7, EndColumn: 14}},
+		"missing":     {commit.Locate("PROJ-99"), Span{}},
+	}
+	for name, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s span = %+v, want %+v", name, tt.got, tt.want)
+		}
+	}
+
+	footer, _ := parser.Parse("feat: add login\n\nBREAKING CHANGE: tokens expire")
+	if got, want := footer.BreakingSpan(), (Span{Line: 3, Column: 1, EndColumn: 16}); got != want {
+		t.Errorf("footer BreakingSpan() = %+v, want %+v", got, want)
+	}
+	if got, want := HeaderSpan("not conventional \nbody"), (Span{Line: 1, Column: 1, EndColumn: 17}); got != want {
+		t.Errorf("HeaderSpan() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,83 @@
+package conventionalcommit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TicketPattern describes an organization-specific ticket reference format,
+// such as ServiceNow (SNOW-123) or Azure DevOps (AB#456) work items.
+type TicketPattern struct {
+	// Name is used as the TicketRef type, e.g. "SNOW".
+	Name string
+	// Pattern is a regular expression matching the reference. Its first
+	// capture group is the ticket ID; without one the whole match is used.
+	Pattern string
+	// URLTemplate links a ticket, with {id} replaced by the ticket ID,
+	// e.g. "https://acme.service-now.com/task.do?number={id}".
+	URLTemplate string
+}
+
+// compiledTicketPattern is a TicketPattern with its regex compiled.
+type compiledTicketPattern struct {
+	TicketPattern
+	re *regexp.Regexp
+}
+
+// SetTicketPatterns compiles additional ticket reference patterns. They are
+// matched before the built-in JIRA, GitHub and bracketed formats, so text
+// they claim is not reported again as another ticket type.
+func (p *Parser) SetTicketPatterns(patterns []TicketPattern) error {
+	compiled := make([]compiledTicketPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern.Name == "" {
+			return errors.New("ticket pattern name is required")
+		}
+		re, err := CompilePattern(pattern.Pattern)
+		if err != nil {
+			return fmt.Errorf("compiling ticket pattern %s: %w", pattern.Name, err)
+		}
+		compiled = append(compiled, compiledTicketPattern{TicketPattern: pattern, re: re})
+	}
+	p.ticketPatterns = compiled
+	return nil
+}
+
+// parseCustomRefs extracts references of the configured ticket patterns and
+// returns the message with the matched text blanked out (keeping offsets),
+// for the built-in patterns to scan.
+func (p *Parser) parseCustomRefs(message string, refs []TicketRef, seen map[string]bool, positions map[string]int) ([]TicketRef, string) {
+	if len(p.ticketPatterns) == 0 {
+		return refs, message
+	}
+
+	remaining := []byte(message)
+	for _, pattern := range p.ticketPatterns {
+		for _, match := range pattern.re.FindAllStringSubmatchIndex(message, -1) {
+			if match[0] == match[1] {
+				continue
+			}
+			id := message[match[0]:match[1]]
+			if len(match) > 3 && match[2] >= 0 {
+				id = message[match[2]:match[3]]
+			}
+
+			ref := TicketRef{
+				Type: pattern.Name,
+				ID:   id,
+				Raw:  message[match[0]:match[1]],
+			}
+			if pattern.URLTemplate != "" {
+				ref.URL = strings.ReplaceAll(pattern.URLTemplate, "{id}", id)
+			}
+			refs = addUniqueRef(refs, ref, seen, positions, match[0])
+
+			for i := match[0]; i < match[1]; i++ {
+				remaining[i] = ' '
+			}
+		}
+	}
+	return refs, string(remaining)
+}
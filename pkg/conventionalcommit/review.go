@@ -0,0 +1,41 @@
+package conventionalcommit
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ReviewTrailerKeys are the trailers recording code review, as written by
+// tools such as Gerrit and the Linux kernel workflow.
+var ReviewTrailerKeys = []string{"Reviewed-by", "Acked-by", "Tested-by"}
+
+// identityRegex matches a "Name <email>" trailer value.
+var identityRegex = regexp.MustCompile(`^([^<>]+?)\s*<([^<>\s]+@[^<>\s]+)>$`)
+
+// Review is a review trailer such as "Reviewed-by: Jane Doe <jane@example.com>".
+type Review struct {
+	// Key is the trailer key as written in ReviewTrailerKeys.
+	Key string
+	// Name and Email are empty when the value is not "Name <email>".
+	Name  string
+	Email string
+	Value string
+}
+
+// Reviews returns the review trailers of the commit, in order.
+func (c *Commit) Reviews() []Review {
+	var reviews []Review
+	for _, trailer := range c.Trailers {
+		for _, key := range ReviewTrailerKeys {
+			if !strings.EqualFold(trailer.Key, key) {
+				continue
+			}
+			review := Review{Key: key, Value: trailer.Value}
+			if match := identityRegex.FindStringSubmatch(strings.TrimSpace(trailer.Value)); match != nil {
+				review.Name, review.Email = match[1], match[2]
+			}
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews
+}
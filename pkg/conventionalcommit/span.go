@@ -0,0 +1,140 @@
+package conventionalcommit
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Span locates text in a commit message. Line is 1-based; Column and
+// EndColumn are 1-based character columns, with EndColumn just past the last
+// character. The zero Span means the position is unknown.
+type Span struct {
+	Line      int
+	Column    int
+	EndColumn int
+}
+
+// IsZero reports whether the span has no position.
+func (s Span) IsZero() bool {
+	return s.Line == 0
+}
+
+// spanOf returns the span of line[start:end] on a 1-based line.
+func spanOf(line string, lineNumber, start, end int) Span {
+	column := utf8.RuneCountInString(line[:start]) + 1
+	return Span{Line: lineNumber, Column: column, EndColumn: column + utf8.RuneCountInString(line[start:end])}
+}
+
+// header returns the first line of the raw message and the submatch indexes
+// of conventionalCommitRegex in it, or nil when it is not conventional.
+func (c *Commit) header() (string, []int) {
+	header, _, _ := strings.Cut(c.Raw, "\n")
+	return header, conventionalCommitRegex.FindStringSubmatchIndex(header)
+}
+
+// TypeSpan locates the type in the header.
+func (c *Commit) TypeSpan() Span {
+	header, index := c.header()
+	if index == nil {
+		return Span{}
+	}
+	return spanOf(header, 1, index[2], index[3])
+}
+
+// ScopeSpan locates the scope, between the parentheses, in the header.
+func (c *Commit) ScopeSpan() Span {
+	header, index := c.header()
+	if index == nil || index[6] < 0 {
+		return Span{}
+	}
+	return spanOf(header, 1, index[6], index[7])
+}
+
+// ScopeSpans locates each of Scopes in the header. Scopes were split in
+// order, so each is searched for after the previous one.
+func (c *Commit) ScopeSpans() []Span {
+	header, index := c.header()
+	spans := make([]Span, len(c.Scopes))
+	if index == nil || index[6] < 0 {
+		return spans
+	}
+	start := index[6]
+	for i, scope := range c.Scopes {
+		at := start
+		if found := strings.Index(header[start:index[7]], scope); scope != "" && found >= 0 {
+			at += found
+		}
+		spans[i] = spanOf(header, 1, at, at+len(scope))
+		start = at + len(scope)
+	}
+	return spans
+}
+
+// DescriptionSpan locates the description in the header, or the whole header
+// of a message that is not conventional.
+func (c *Commit) DescriptionSpan() Span {
+	header, index := c.header()
+	if index == nil {
+		if strings.TrimSpace(header) == "" {
+			return Span{}
+		}
+		return spanOf(header, 1, 0, len(strings.TrimRight(header, " \t")))
+	}
+	description := strings.TrimRight(header[index[10]:index[11]], " \t")
+	return spanOf(header, 1, index[10], index[10]+len(description))
+}
+
+// BreakingSpan locates the header's "!" or else the key of the breaking
+// change trailer.
+func (c *Commit) BreakingSpan() Span {
+	if !c.Breaking {
+		return Span{}
+	}
+	if header, index := c.header(); index != nil && index[8] >= 0 {
+		return spanOf(header, 1, index[8], index[9])
+	}
+	for i, line := range strings.Split(c.Raw, "\n") {
+		for _, key := range []string{"BREAKING CHANGE", "BREAKING-CHANGE"} {
+			if i > 0 && strings.HasPrefix(line, key+":") {
+				return spanOf(line, i+1, 0, len(key))
+			}
+		}
+	}
+	return Span{}
+}
+
+// Locate returns the span of the first occurrence of text in the message,
+// or the zero Span.
+func (c *Commit) Locate(text string) Span {
+	return locate(c.Raw, text, strings.Index)
+}
+
+// LocateLast returns the span of the last occurrence of text in the message,
+// such as a reference in the footer, or the zero Span.
+func (c *Commit) LocateLast(text string) Span {
+	return locate(c.Raw, text, strings.LastIndex)
+}
+
+func locate(message, text string, index func(s, substr string) int) Span {
+	if text == "" || strings.Contains(text, "\n") {
+		return Span{}
+	}
+	i := index(message, text)
+	if i < 0 {
+		return Span{}
+	}
+	lineStart := strings.LastIndex(message[:i], "\n") + 1
+	line, _, _ := strings.Cut(message[lineStart:], "\n")
+	return spanOf(line, strings.Count(message[:lineStart], "\n")+1, i-lineStart, i-lineStart+len(text))
+}
+
+// HeaderSpan locates the header, the first line of a message, such as one
+// that failed to parse.
+func HeaderSpan(message string) Span {
+	header, _, _ := strings.Cut(message, "\n")
+	header = strings.TrimRight(header, " \t")
+	if header == "" {
+		return Span{}
+	}
+	return spanOf(header, 1, 0, len(header))
+}
@@ -0,0 +1,204 @@
+// Package jira - Optional JIRA REST API client for ticket verification
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/secrets"
+)
+
+const (
+	// EnvURL, EnvToken and EnvEmail configure the API client
+	EnvURL   = "FCGH_JIRA_URL"
+	EnvToken = "FCGH_JIRA_TOKEN"
+	EnvEmail = "FCGH_JIRA_EMAIL"
+	// KeychainService is the credential store entry holding the API token
+	// when FCGH_JIRA_TOKEN is not set (see `fcgh auth login jira`)
+	KeychainService = secrets.JIRAService
+
+	// InProgressJQL selects the current user's issues that are being worked on
+	InProgressJQL = "assignee = currentUser() AND statusCategory = \"In Progress\" ORDER BY updated DESC"
+
+	requestTimeout = 10 * time.Second
+)
+
+var (
+	// ErrNotConfigured indicates no server URL or token is available
+	ErrNotConfigured = errors.New("JIRA API not configured")
+	// ErrIssueNotFound indicates the ticket does not exist (or is not visible)
+	ErrIssueNotFound = errors.New("JIRA issue not found")
+)
+
+// keychainLookup reads a secret from the credential store (replaced in tests)
+var keychainLookup = secrets.Lookup
+
+// Issue is the subset of a JIRA issue used for commit validation and messages
+type Issue struct {
+	Key            string
+	Summary        string
+	Status         string
+	StatusCategory string // "new", "indeterminate" or "done"
+	Project        string
+}
+
+// IsClosed reports whether the issue is in a done status category
+func (i *Issue) IsClosed() bool {
+	return i.StatusCategory == "done"
+}
+
+// Client talks to the JIRA REST API (v2, supported by Cloud and Server)
+type Client struct {
+	baseURL    string
+	token      string
+	email      string // set for JIRA Cloud basic auth; empty uses a bearer token
+	httpClient *http.Client
+}
+
+// NewClient creates an API client. With an email the token is sent as JIRA
+// Cloud basic auth, otherwise as a Server/Data Center personal access token.
+func NewClient(baseURL, token, email string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		email:      email,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// NewClientFromEnv creates a client from FCGH_JIRA_URL (falling back to
+// serverURL from the config file), FCGH_JIRA_TOKEN or the credential store entry
+// "fast-cc-jira", and FCGH_JIRA_EMAIL. It returns ErrNotConfigured when the
+// URL or token is missing.
+func NewClientFromEnv(serverURL string) (*Client, error) {
+	if env := os.Getenv(EnvURL); env != "" {
+		serverURL = env
+	}
+	return NewClientForServer(serverURL)
+}
+
+// NewClientForServer is NewClientFromEnv without the FCGH_JIRA_URL override,
+// for servers an admin config pins
+func NewClientForServer(serverURL string) (*Client, error) {
+	if serverURL == "" {
+		return nil, fmt.Errorf("%w: set jira_url or %s", ErrNotConfigured, EnvURL)
+	}
+	if _, err := url.ParseRequestURI(serverURL); err != nil {
+		return nil, fmt.Errorf("invalid JIRA URL %q: %w", serverURL, err)
+	}
+
+	token := os.Getenv(EnvToken)
+	if token == "" {
+		token = keychainLookup(KeychainService)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("%w: set %s or run 'fcgh auth login jira'", ErrNotConfigured, EnvToken)
+	}
+
+	return NewClient(serverURL, token, os.Getenv(EnvEmail)), nil
+}
+
+// GetIssue fetches a single issue by key
+func (c *Client) GetIssue(ctx context.Context, key string) (*Issue, error) {
+	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,status,project", c.baseURL, url.PathEscape(key))
+
+	var payload issuePayload
+	if err := c.get(ctx, endpoint, &payload); err != nil {
+		if errors.Is(err, ErrIssueNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrIssueNotFound, key)
+		}
+		return nil, fmt.Errorf("fetching JIRA issue %s: %w", key, err)
+	}
+	return payload.issue(), nil
+}
+
+// SearchIssues returns up to limit issues matching a JQL query
+func (c *Client) SearchIssues(ctx context.Context, jql string, limit int) ([]*Issue, error) {
+	query := url.Values{}
+	query.Set("jql", jql)
+	query.Set("fields", "summary,status,project")
+	query.Set("maxResults", strconv.Itoa(limit))
+	endpoint := fmt.Sprintf("%s/rest/api/2/search?%s", c.baseURL, query.Encode())
+
+	var payload struct {
+		Issues []issuePayload `json:"issues"`
+	}
+	if err := c.get(ctx, endpoint, &payload); err != nil {
+		return nil, fmt.Errorf("searching JIRA issues: %w", err)
+	}
+
+	issues := make([]*Issue, 0, len(payload.Issues))
+	for _, p := range payload.Issues {
+		issues = append(issues, p.issue())
+	}
+	return issues, nil
+}
+
+// issuePayload is the REST representation of an issue
+type issuePayload struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+	} `json:"fields"`
+}
+
+func (p issuePayload) issue() *Issue {
+	return &Issue{
+		Key:            p.Key,
+		Summary:        p.Fields.Summary,
+		Status:         p.Fields.Status.Name,
+		StatusCategory: p.Fields.Status.StatusCategory.Key,
+		Project:        p.Fields.Project.Key,
+	}
+}
+
+// get performs an authenticated GET request and decodes the JSON response
+func (c *Client) get(ctx context.Context, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.email != "" {
+		req.SetBasicAuth(c.email, c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrIssueNotFound
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("authentication failed (HTTP %d)", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
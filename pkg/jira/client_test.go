@@ -0,0 +1,108 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestServer serves a single CGC-1 issue and an in-progress search, and
+// 404s everything else
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/rest/api/2/search":
+			if r.URL.Query().Get("jql") != InProgressJQL || r.URL.Query().Get("maxResults") != "5" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"issues":[{"key":"CGC-7","fields":{"summary":"Fix logout",` +
+				`"status":{"name":"In Progress","statusCategory":{"key":"indeterminate"}},"project":{"key":"CGC"}}}]}`))
+		case "/rest/api/2/issue/CGC-1":
+			_, _ = w.Write([]byte(`{"key":"CGC-1","fields":{"summary":"Add login page",` +
+				`"status":{"name":"Done","statusCategory":{"key":"done"}},"project":{"key":"CGC"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_GetIssue(t *testing.T) {
+	server := newTestServer(t)
+	client := NewClient(server.URL+"/", "secret", "")
+
+	issue, err := client.GetIssue(context.Background(), "CGC-1")
+	if err != nil {
+		t.Fatalf("GetIssue() error = %v", err)
+	}
+	if issue.Summary != "Add login page" || issue.Project != "CGC" || issue.Status != "Done" || !issue.IsClosed() {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+
+	if _, err := client.GetIssue(context.Background(), "CGC-2"); !errors.Is(err, ErrIssueNotFound) {
+		t.Errorf("expected ErrIssueNotFound, got %v", err)
+	}
+
+	if _, err := NewClient(server.URL, "wrong", "").GetIssue(context.Background(), "CGC-1"); err == nil || errors.Is(err, ErrIssueNotFound) {
+		t.Errorf("expected an authentication error, got %v", err)
+	}
+}
+
+func TestClient_SearchIssues(t *testing.T) {
+	client := NewClient(newTestServer(t).URL, "secret", "")
+
+	issues, err := client.SearchIssues(context.Background(), InProgressJQL, 5)
+	if err != nil {
+		t.Fatalf("SearchIssues() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Key != "CGC-7" || issues[0].Summary != "Fix logout" || issues[0].IsClosed() {
+		t.Errorf("unexpected issues %+v", issues)
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	original := keychainLookup
+	t.Cleanup(func() { keychainLookup = original })
+	keychainLookup = func(string) string { return "" }
+
+	t.Setenv(EnvURL, "")
+	t.Setenv(EnvToken, "")
+	if _, err := NewClientFromEnv(""); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("expected ErrNotConfigured without a URL, got %v", err)
+	}
+	if _, err := NewClientFromEnv("https://jira.example.com"); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("expected ErrNotConfigured without a token, got %v", err)
+	}
+
+	keychainLookup = func(service string) string {
+		if service == KeychainService {
+			return "from-keychain"
+		}
+		return ""
+	}
+	client, err := NewClientFromEnv("https://jira.example.com")
+	if err != nil || client.token != "from-keychain" {
+		t.Errorf("expected keychain token, got %+v, %v", client, err)
+	}
+
+	t.Setenv(EnvURL, "https://override.example.com")
+	t.Setenv(EnvToken, "env-token")
+	client, err = NewClientFromEnv("https://jira.example.com")
+	if err != nil || client.baseURL != "https://override.example.com" || client.token != "env-token" {
+		t.Errorf("expected environment to win, got %+v, %v", client, err)
+	}
+
+	client, err = NewClientForServer("https://jira.example.com")
+	if err != nil || client.baseURL != "https://jira.example.com" {
+		t.Errorf("expected the pinned server to ignore %s, got %+v, %v", EnvURL, client, err)
+	}
+}
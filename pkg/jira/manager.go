@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/dirs"
 )
 
 const (
@@ -16,7 +18,9 @@ const (
 
 // Manager handles JIRA ticket reference management
 type Manager struct {
-	configDir string // Changed from repoPath to use ~/.fast-cc directory
+	configDir string // the repo's .fast-cc directory or the global config directory
+	// normalize validates tickets of another tracker (nil accepts JIRA keys)
+	normalize func(string) (string, error)
 }
 
 // NewManager creates a new JIRA ticket manager
@@ -39,8 +43,8 @@ func NewManager(repoPath string) *Manager {
 		return m
 	}
 
-	// Otherwise, get the global config directory (~/.fast-cc)
-	home, err := os.UserHomeDir()
+	// Otherwise, get the global config directory ($XDG_CONFIG_HOME/fast-cc)
+	globalConfigDir, err := dirs.Config()
 	if err != nil {
 		// Fall back to using repo path if we can't get home directory
 		return &Manager{
@@ -48,7 +52,6 @@ func NewManager(repoPath string) *Manager {
 		}
 	}
 
-	globalConfigDir := filepath.Join(home, ".fast-cc")
 	// Create the global directory if it doesn't exist
 	if err := os.MkdirAll(globalConfigDir, 0o750); err != nil {
 		// Fall back to using repo path if we can't create config directory
@@ -64,14 +67,33 @@ func NewManager(repoPath string) *Manager {
 	return m
 }
 
+// SetTicketFormat makes the manager store another tracker's tickets;
+// normalize returns the canonical reference or an error for invalid input
+func (m *Manager) SetTicketFormat(normalize func(string) (string, error)) {
+	m.normalize = normalize
+}
+
 // SetJiraTicket sets the current JIRA ticket, commenting out previous entries
 func (m *Manager) SetJiraTicket(ticketID string) error {
-	// Validate JIRA ticket format (e.g., CGC-1245)
-	if !m.isValidJiraFormat(ticketID) {
-		return fmt.Errorf("invalid JIRA ticket format: %s (expected format: XXX-####)", ticketID)
-	}
+	return m.SetJiraTickets(ticketID)
+}
 
-	ticketID = strings.ToUpper(ticketID)
+// SetJiraTickets sets the primary ticket followed by related tickets (such as
+// sub-tasks), commenting out previous entries
+func (m *Manager) SetJiraTickets(primary string, related ...string) error {
+	tickets := make([]string, 0, 1+len(related))
+	seen := make(map[string]bool)
+	for _, id := range append([]string{primary}, related...) {
+		// Validate ticket format (e.g., CGC-1245)
+		ticket, err := m.normalizeTicket(id)
+		if err != nil {
+			return err
+		}
+		if !seen[ticket] {
+			seen[ticket] = true
+			tickets = append(tickets, ticket)
+		}
+	}
 
 	// Read existing content (empty if file doesn't exist)
 	existingContent, err := m.readJiraRefFile()
@@ -86,7 +108,13 @@ func (m *Manager) SetJiraTicket(ticketID string) error {
 	var newContent strings.Builder
 	newContent.WriteString(fmt.Sprintf("# JIRA Commit Reference - Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")))
 	newContent.WriteString("# Current active ticket:\n")
-	newContent.WriteString(fmt.Sprintf("%s\n", ticketID))
+	newContent.WriteString(fmt.Sprintf("%s\n", tickets[0]))
+	if len(tickets) > 1 {
+		newContent.WriteString("# Related tickets:\n")
+		for _, ticket := range tickets[1:] {
+			newContent.WriteString(fmt.Sprintf("%s\n", ticket))
+		}
+	}
 
 	if existingContent != "" {
 		newContent.WriteString("\n# Previous tickets (commented out):\n")
@@ -95,7 +123,7 @@ func (m *Manager) SetJiraTicket(ticketID string) error {
 			line = strings.TrimSpace(line)
 			if line != "" && !strings.HasPrefix(line, "#") {
 				// Comment out previous active tickets
-				if m.isValidJiraFormat(line) {
+				if _, err := m.normalizeTicket(line); err == nil {
 					newContent.WriteString(fmt.Sprintf("# %s\n", line))
 				}
 			} else if strings.HasPrefix(line, "#") {
@@ -111,38 +139,53 @@ func (m *Manager) SetJiraTicket(ticketID string) error {
 
 // GetCurrentJiraTicket returns the current active JIRA ticket
 func (m *Manager) GetCurrentJiraTicket() (string, error) {
+	tickets, err := m.GetCurrentJiraTickets()
+	if err != nil || len(tickets) == 0 {
+		return "", err
+	}
+	return tickets[0], nil
+}
+
+// GetCurrentJiraTickets returns the primary ticket followed by any related
+// tickets, in the order they were set
+func (m *Manager) GetCurrentJiraTickets() ([]string, error) {
 	content, err := m.readJiraRefFile()
 	if err != nil {
 		// If file doesn't exist, create an empty one
 		if os.IsNotExist(err) {
 			if createErr := m.createEmptyJiraRefFile(); createErr != nil {
-				return "", fmt.Errorf("failed to create JIRA reference file: %w", createErr)
+				return nil, fmt.Errorf("failed to create JIRA reference file: %w", createErr)
 			}
-			return "", nil
+			return nil, nil
 		}
-		return "", fmt.Errorf("failed to read JIRA reference file: %w", err)
+		return nil, fmt.Errorf("failed to read JIRA reference file: %w", err)
 	}
 
-	// Find the first non-commented line that's a valid JIRA ticket
+	// Collect the non-commented lines that are valid tickets
+	var tickets []string
 	lines := strings.Split(content, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if line != "" && !strings.HasPrefix(line, "#") {
-			if m.isValidJiraFormat(line) {
-				return strings.ToUpper(line), nil
+			if ticket, err := m.normalizeTicket(line); err == nil {
+				tickets = append(tickets, ticket)
 			}
 		}
 	}
 
-	return "", nil
+	return tickets, nil
 }
 
 // ShowJiraStatus displays the current JIRA ticket status
 func (m *Manager) ShowJiraStatus() error {
-	currentTicket, err := m.GetCurrentJiraTicket()
+	tickets, err := m.GetCurrentJiraTickets()
 	if err != nil {
 		return err
 	}
+	var currentTicket string
+	if len(tickets) > 0 {
+		currentTicket = tickets[0]
+	}
 
 	fmt.Println()
 	fmt.Println("## 🎫 JIRA Ticket Status")
@@ -154,6 +197,9 @@ func (m *Manager) ShowJiraStatus() error {
 		fmt.Println("Use `cc set-jira CGC-1234` to set a JIRA ticket for commits.")
 	} else {
 		fmt.Printf("**Current ticket:** `%s`\n", currentTicket)
+		if len(tickets) > 1 {
+			fmt.Printf("**Related tickets:** `%s`\n", strings.Join(tickets[1:], "`, `"))
+		}
 		fmt.Println()
 		fmt.Printf("This ticket will be automatically included in commit messages.\n")
 		fmt.Printf("Use `cc set-jira NEW-TICKET` to change or `cc clear-jira` to remove.\n")
@@ -182,7 +228,7 @@ func (m *Manager) ClearJiraTicket() error {
 			line = strings.TrimSpace(line)
 			if line != "" && !strings.HasPrefix(line, "#") {
 				// Comment out any active tickets
-				if m.isValidJiraFormat(line) {
+				if _, err := m.normalizeTicket(line); err == nil {
 					newContent.WriteString(fmt.Sprintf("# %s\n", line))
 				}
 			} else if strings.HasPrefix(line, "#") {
@@ -275,6 +321,18 @@ func (m *Manager) migrateOldJiraFile(repoPath string) {
 	}
 }
 
+// normalizeTicket validates a ticket with the configured format, defaulting
+// to JIRA keys, and returns its canonical form
+func (m *Manager) normalizeTicket(ticketID string) (string, error) {
+	if m.normalize != nil {
+		return m.normalize(ticketID)
+	}
+	if !m.isValidJiraFormat(ticketID) {
+		return "", fmt.Errorf("invalid JIRA ticket format: %s (expected format: XXX-####)", ticketID)
+	}
+	return strings.ToUpper(ticketID), nil
+}
+
 // isValidJiraFormat validates JIRA ticket format (e.g., CGC-1245)
 func (m *Manager) isValidJiraFormat(ticketID string) bool {
 	// Pattern: 2-10 uppercase letters, hyphen, 1-5 digits
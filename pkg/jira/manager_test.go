@@ -284,3 +284,33 @@ func TestManager_FileContent(t *testing.T) {
 		}
 	}
 }
+
+func TestManager_SetJiraTickets(t *testing.T) {
+	manager := setupTestManager(t)
+
+	if err := manager.SetJiraTickets("cgc-1", "CGC-2", "cgc-1", "CGC-3"); err != nil {
+		t.Fatalf("SetJiraTickets() error = %v", err)
+	}
+	tickets, err := manager.GetCurrentJiraTickets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(tickets, ",") != "CGC-1,CGC-2,CGC-3" {
+		t.Errorf("GetCurrentJiraTickets() = %v", tickets)
+	}
+	if current, _ := manager.GetCurrentJiraTicket(); current != "CGC-1" {
+		t.Errorf("GetCurrentJiraTicket() = %q, want the primary ticket", current)
+	}
+
+	// Setting a new ticket comments out all previously active ones
+	if err := manager.SetJiraTicket("PROJ-9"); err != nil {
+		t.Fatal(err)
+	}
+	if tickets, _ := manager.GetCurrentJiraTickets(); len(tickets) != 1 || tickets[0] != "PROJ-9" {
+		t.Errorf("expected only PROJ-9 to be active, got %v", tickets)
+	}
+
+	if err := manager.SetJiraTickets("CGC-1", "not-a-ticket"); err == nil {
+		t.Error("expected an error for an invalid related ticket")
+	}
+}
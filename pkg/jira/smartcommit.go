@@ -0,0 +1,113 @@
+// Package jira - Smart commit command formatting and validation
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Smart commit verbs understood by JIRA
+const (
+	SmartTime       = "time"
+	SmartComment    = "comment"
+	SmartTransition = "transition"
+)
+
+var (
+	// jiraKeyRegex finds a JIRA issue key anywhere in a line
+	jiraKeyRegex = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-\d+\b`)
+	// smartCommandRegex matches "#verb arguments" up to the next command
+	smartCommandRegex = regexp.MustCompile(`(?:^|\s)#([A-Za-z][\w-]*)((?:\s+[^#\s]\S*)*)`)
+	// smartTimeRegex matches JIRA work durations such as "1w 2d 4h 30m"
+	smartTimeRegex = regexp.MustCompile(`^(\d+(\.\d+)?[wdhm])(\s+\d+(\.\d+)?[wdhm])*$`)
+)
+
+// SmartCommit holds the smart commit commands appended to a ticket reference
+type SmartCommit struct {
+	Time       string
+	Comment    string
+	Transition string
+}
+
+// IsEmpty reports whether no command is set
+func (s SmartCommit) IsEmpty() bool {
+	return s.Time == "" && s.Comment == "" && s.Transition == ""
+}
+
+// Format renders the commands for a ticket, e.g.
+// "CGC-1234 #time 2h #comment Ready #transition In Review", or "" when empty
+func (s SmartCommit) Format(ticket string) string {
+	if ticket == "" || s.IsEmpty() {
+		return ""
+	}
+	parts := []string{ticket}
+	for _, cmd := range []SmartCommand{{SmartTime, s.Time}, {SmartComment, s.Comment}, {SmartTransition, s.Transition}} {
+		if cmd.Args != "" {
+			parts = append(parts, "#"+cmd.Verb+" "+cmd.Args)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// SmartCommand is a single "#verb arguments" command in a commit message
+type SmartCommand struct {
+	Verb string
+	Args string
+}
+
+// ParseSmartCommands extracts smart commit commands from lines that reference
+// a JIRA ticket. Numeric references such as GitHub's "#123" are not commands.
+func ParseSmartCommands(message string) []SmartCommand {
+	var commands []SmartCommand
+	for _, line := range strings.Split(message, "\n") {
+		if !jiraKeyRegex.MatchString(line) {
+			continue
+		}
+		for _, match := range smartCommandRegex.FindAllStringSubmatch(line, -1) {
+			commands = append(commands, SmartCommand{
+				Verb: strings.ToLower(match[1]),
+				Args: strings.TrimSpace(match[2]),
+			})
+		}
+	}
+	return commands
+}
+
+// ValidateSmartCommands checks that commands use known verbs with well-formed
+// arguments. allowedTransitions, when non-empty, restricts #transition targets.
+func ValidateSmartCommands(commands []SmartCommand, allowedTransitions []string) []error {
+	var errs []error
+	for _, cmd := range commands {
+		switch cmd.Verb {
+		case SmartTime:
+			if !smartTimeRegex.MatchString(cmd.Args) {
+				errs = append(errs, fmt.Errorf("#time needs a duration such as '2h' or '1d 4h 30m', got '%s'", cmd.Args))
+			}
+		case SmartComment:
+			if cmd.Args == "" {
+				errs = append(errs, errors.New("#comment needs text"))
+			}
+		case SmartTransition:
+			if cmd.Args == "" {
+				errs = append(errs, errors.New("#transition needs a target status"))
+			} else if len(allowedTransitions) > 0 && !containsFold(allowedTransitions, cmd.Args) {
+				errs = append(errs, fmt.Errorf("#transition '%s' is not allowed (allowed: %s)",
+					cmd.Args, strings.Join(allowedTransitions, ", ")))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("unknown smart commit command #%s (use #time, #comment or #transition)", cmd.Verb))
+		}
+	}
+	return errs
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
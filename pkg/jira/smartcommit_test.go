@@ -0,0 +1,53 @@
+package jira
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSmartCommit_Format(t *testing.T) {
+	sc := SmartCommit{Time: "2h", Comment: "Ready for review", Transition: "In Review"}
+	if got, want := sc.Format("CGC-1"), "CGC-1 #time 2h #comment Ready for review #transition In Review"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+	if got := (SmartCommit{Time: "2h"}).Format(""); got != "" {
+		t.Errorf("Format() without a ticket = %q", got)
+	}
+	if got := (SmartCommit{}).Format("CGC-1"); got != "" {
+		t.Errorf("Format() without commands = %q", got)
+	}
+}
+
+func TestParseAndValidateSmartCommands(t *testing.T) {
+	message := "feat: CGC-1 add login\n\nFixes #42\n\nCGC-1 #time 1d 4h #comment Ready for review #transition In Review"
+	commands := ParseSmartCommands(message)
+	want := []SmartCommand{
+		{Verb: "time", Args: "1d 4h"},
+		{Verb: "comment", Args: "Ready for review"},
+		{Verb: "transition", Args: "In Review"},
+	}
+	if !reflect.DeepEqual(commands, want) {
+		t.Fatalf("ParseSmartCommands() = %+v, want %+v", commands, want)
+	}
+	if errs := ValidateSmartCommands(commands, []string{"in review", "Done"}); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		allowed []string
+	}{
+		{name: "bad duration", message: "CGC-1 #time soon"},
+		{name: "empty comment", message: "CGC-1 #comment"},
+		{name: "unknown verb", message: "CGC-1 #resolve"},
+		{name: "transition not allowed", message: "CGC-1 #transition Closed", allowed: []string{"In Review"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if errs := ValidateSmartCommands(ParseSmartCommands(tt.message), tt.allowed); len(errs) != 1 {
+				t.Errorf("expected one error, got %v", errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Package pair tracks the co-authors of a pairing session, whose
+// Co-authored-by trailers are added to generated commit messages
+package pair
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	PairFile = "pair-co-authors.txt"
+)
+
+// Manager handles the co-authors of a repository
+type Manager struct {
+	configDir string // the repo's .fast-cc directory or its git directory
+	repoPath  string
+}
+
+// NewManager creates a co-author manager for the repository at repoPath.
+// Co-authors are kept per repository, in its .fast-cc directory when it has
+// one and in its git directory otherwise, so they are never committed.
+func NewManager(repoPath string) *Manager {
+	// For testing, allow overriding the config directory
+	if testDir := os.Getenv("FCGH_TEST_DIR"); testDir != "" {
+		return &Manager{configDir: testDir, repoPath: repoPath}
+	}
+
+	localConfigDir := filepath.Join(repoPath, ".fast-cc")
+	if info, err := os.Stat(localConfigDir); err == nil && info.IsDir() {
+		return &Manager{configDir: localConfigDir, repoPath: repoPath}
+	}
+
+	// #nosec G204 - fixed git command
+	out, err := exec.Command("git", "-C", repoPath, "rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		// Fall back to the repo path outside a git repository
+		return &Manager{configDir: repoPath, repoPath: repoPath}
+	}
+	return &Manager{configDir: strings.TrimSpace(string(out)), repoPath: repoPath}
+}
+
+// Add adds co-authors, given as "Name <email>" or as the name or email of
+// someone in the repository's history. Co-authors already set are kept once.
+func (m *Manager) Add(coAuthors ...string) error {
+	current, err := m.GetCoAuthors()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(current))
+	for _, coAuthor := range current {
+		seen[strings.ToLower(coAuthor)] = true
+	}
+	for _, input := range coAuthors {
+		coAuthor, err := m.resolve(input)
+		if err != nil {
+			return err
+		}
+		if !seen[strings.ToLower(coAuthor)] {
+			seen[strings.ToLower(coAuthor)] = true
+			current = append(current, coAuthor)
+		}
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("# Pair Co-Authors - Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")))
+	content.WriteString("# Added to generated commits as Co-authored-by trailers:\n")
+	for _, coAuthor := range current {
+		content.WriteString(coAuthor + "\n")
+	}
+	return m.writePairFile(content.String())
+}
+
+// GetCoAuthors returns the co-authors in the order they were added
+func (m *Manager) GetCoAuthors() ([]string, error) {
+	// #nosec G304 -- the filename is constant within the config directory
+	content, err := os.ReadFile(m.pairFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read co-author file: %w", err)
+	}
+
+	var coAuthors []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if coAuthor, err := parseCoAuthor(line); err == nil {
+			coAuthors = append(coAuthors, coAuthor)
+		}
+	}
+	return coAuthors, nil
+}
+
+// Clear ends the pairing session
+func (m *Manager) Clear() error {
+	content := fmt.Sprintf("# Pair Co-Authors - Cleared: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	content += "# No co-authors set\n"
+	return m.writePairFile(content)
+}
+
+// ShowStatus displays the current co-authors
+func (m *Manager) ShowStatus() error {
+	coAuthors, err := m.GetCoAuthors()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("## 👥 Pair Status")
+	fmt.Println()
+	if len(coAuthors) == 0 {
+		fmt.Println("**Co-authors:** None set")
+		fmt.Println()
+		fmt.Println("Use `ccg pair add \"Jane Doe <jane@example.com>\"` to pair on commits.")
+		return nil
+	}
+	fmt.Println("**Co-authors:**")
+	for _, coAuthor := range coAuthors {
+		fmt.Printf("- `%s`\n", coAuthor)
+	}
+	fmt.Println()
+	fmt.Println("Generated commits will include a Co-authored-by trailer for each of them.")
+	fmt.Println("Use `ccg pair clear` to stop pairing.")
+	return nil
+}
+
+// resolve turns input into "Name <email>", looking up bare names and emails
+// among the repository's commit authors
+func (m *Manager) resolve(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if coAuthor, err := parseCoAuthor(input); err == nil {
+		return coAuthor, nil
+	}
+
+	// #nosec G204 - fixed git command
+	out, err := exec.Command("git", "-C", m.repoPath, "log", "--all", "--format=%an <%ae>").Output()
+	if err == nil {
+		var matches []string
+		seen := make(map[string]bool)
+		for _, author := range strings.Split(string(out), "\n") {
+			address, err := mail.ParseAddress(author)
+			if err != nil || seen[author] {
+				continue
+			}
+			if strings.EqualFold(address.Name, input) || strings.EqualFold(address.Address, input) {
+				seen[author] = true
+				matches = append(matches, author)
+			}
+		}
+		switch len(matches) {
+		case 1:
+			return matches[0], nil
+		case 0:
+		default:
+			return "", fmt.Errorf("%q matches several authors (%s); use \"Name <email>\"", input, strings.Join(matches, ", "))
+		}
+	}
+	return "", fmt.Errorf("no author named %q in the repository history; use \"Name <email>\"", input)
+}
+
+// parseCoAuthor validates a "Name <email>" co-author and returns it in
+// canonical form
+func parseCoAuthor(value string) (string, error) {
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Name == "" {
+		return "", fmt.Errorf("invalid co-author %q (expected format: Name <email>)", value)
+	}
+	return fmt.Sprintf("%s <%s>", address.Name, address.Address), nil
+}
+
+// pairFilePath returns the path to the co-author file
+func (m *Manager) pairFilePath() string {
+	return filepath.Join(filepath.Clean(m.configDir), PairFile)
+}
+
+// writePairFile writes content to the co-author file
+func (m *Manager) writePairFile(content string) error {
+	return os.WriteFile(m.pairFilePath(), []byte(content), 0o600)
+}
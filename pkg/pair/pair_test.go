@@ -0,0 +1,91 @@
+package pair
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// setupTestManager creates a Manager for testing with a temporary directory
+func setupTestManager(t *testing.T) *Manager {
+	tempDir := t.TempDir()
+	t.Setenv("FCGH_TEST_DIR", tempDir)
+	return NewManager(tempDir)
+}
+
+func TestManager_AddAndClear(t *testing.T) {
+	manager := setupTestManager(t)
+
+	if coAuthors, err := manager.GetCoAuthors(); err != nil || coAuthors != nil {
+		t.Fatalf("GetCoAuthors() = %v, %v before pairing", coAuthors, err)
+	}
+	if err := manager.Add("Jane Doe <jane@example.com>", `"Bob Smith" <bob@example.com>`); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Add("jane doe <JANE@example.com>", "Jane Doe <jane@example.com>"); err != nil {
+		t.Fatal(err)
+	}
+
+	coAuthors, err := manager.GetCoAuthors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Jane Doe <jane@example.com>", "Bob Smith <bob@example.com>"}
+	if !reflect.DeepEqual(coAuthors, want) {
+		t.Errorf("GetCoAuthors() = %q, want %q", coAuthors, want)
+	}
+
+	if err := manager.Add("not an address"); err == nil {
+		t.Error("Add() accepted an unknown author")
+	}
+
+	if err := manager.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if coAuthors, err := manager.GetCoAuthors(); err != nil || len(coAuthors) != 0 {
+		t.Errorf("GetCoAuthors() = %v, %v after Clear()", coAuthors, err)
+	}
+}
+
+func TestManager_AddFromHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	t.Setenv("HOME", repo)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "commit", "-q", "--allow-empty", "-m", "feat: one"},
+		{"-c", "user.name=Bob Smith", "-c", "user.email=bob@example.com", "commit", "-q", "--allow-empty", "-m", "feat: two"},
+		{"-c", "user.name=Bob Smith", "-c", "user.email=bob@work.example.com", "commit", "-q", "--allow-empty", "-m", "feat: three"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	manager := NewManager(repo)
+	if err := manager.Add("jane@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Add("Bob Smith"); err == nil || !strings.Contains(err.Error(), "several authors") {
+		t.Errorf("Add() of an ambiguous name error = %v", err)
+	}
+	if err := manager.Add("bob@work.example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	coAuthors, err := manager.GetCoAuthors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Jane Doe <jane@example.com>", "Bob Smith <bob@work.example.com>"}
+	if !reflect.DeepEqual(coAuthors, want) {
+		t.Errorf("GetCoAuthors() = %q, want %q", coAuthors, want)
+	}
+	if !strings.HasSuffix(manager.pairFilePath(), ".git/"+PairFile) {
+		t.Errorf("co-authors stored in %s, want the git directory", manager.pairFilePath())
+	}
+}
@@ -0,0 +1,124 @@
+// Package scopepin tracks the scope pinned for the generated commits of a
+// repository, which replaces the scope guessed from the staged files
+package scopepin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	ScopeFile = "pinned-scope.txt"
+)
+
+// Manager handles the pinned scope of a repository
+type Manager struct {
+	configDir string // the repo's .fast-cc directory or its git directory
+}
+
+// NewManager creates a scope manager for the repository at repoPath. The
+// scope is kept per repository, in its .fast-cc directory when it has one
+// and in its git directory otherwise, so each worktree pins its own.
+func NewManager(repoPath string) *Manager {
+	// For testing, allow overriding the config directory
+	if testDir := os.Getenv("FCGH_TEST_DIR"); testDir != "" {
+		return &Manager{configDir: testDir}
+	}
+
+	localConfigDir := filepath.Join(repoPath, ".fast-cc")
+	if info, err := os.Stat(localConfigDir); err == nil && info.IsDir() {
+		return &Manager{configDir: localConfigDir}
+	}
+
+	// #nosec G204 - fixed git command
+	out, err := exec.Command("git", "-C", repoPath, "rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		// Fall back to the repo path outside a git repository
+		return &Manager{configDir: repoPath}
+	}
+	return &Manager{configDir: strings.TrimSpace(string(out))}
+}
+
+// SetScope pins scope for subsequent generated commits
+func (m *Manager) SetScope(scope string) error {
+	scope = strings.TrimSpace(scope)
+	if err := Validate(scope); err != nil {
+		return err
+	}
+	content := fmt.Sprintf("# Pinned Scope - Updated: %s\n%s\n", time.Now().Format("2006-01-02 15:04:05"), scope)
+	return m.writeScopeFile(content)
+}
+
+// GetScope returns the pinned scope, or "" when none is pinned
+func (m *Manager) GetScope() (string, error) {
+	// #nosec G304 -- the filename is constant within the config directory
+	content, err := os.ReadFile(m.scopeFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read pinned scope file: %w", err)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") && Validate(line) == nil {
+			return line, nil
+		}
+	}
+	return "", nil
+}
+
+// Clear unpins the scope
+func (m *Manager) Clear() error {
+	content := fmt.Sprintf("# Pinned Scope - Cleared: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	content += "# No scope pinned\n"
+	return m.writeScopeFile(content)
+}
+
+// ShowStatus displays the pinned scope
+func (m *Manager) ShowStatus() error {
+	scope, err := m.GetScope()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("## 📌 Scope Status")
+	fmt.Println()
+	if scope == "" {
+		fmt.Println("**Pinned scope:** None set")
+		fmt.Println()
+		fmt.Println("Use `ccg set-scope api` to pin a scope for generated commits.")
+		return nil
+	}
+	fmt.Printf("**Pinned scope:** `%s`\n", scope)
+	fmt.Println()
+	fmt.Println("Generated commits will use this scope instead of guessing one.")
+	fmt.Println("Use `ccg clear-scope` to unpin it.")
+	return nil
+}
+
+// Validate checks that scope can appear in a commit header, as in
+// "feat(scope): ...": it is non-empty and has no whitespace, parentheses or
+// colons
+func Validate(scope string) error {
+	if scope == "" || strings.ContainsAny(scope, " \t\n():") {
+		return fmt.Errorf("invalid scope %q (expected a word such as api or auth-service)", scope)
+	}
+	return nil
+}
+
+// scopeFilePath returns the path to the pinned scope file
+func (m *Manager) scopeFilePath() string {
+	return filepath.Join(filepath.Clean(m.configDir), ScopeFile)
+}
+
+// writeScopeFile writes content to the pinned scope file
+func (m *Manager) writeScopeFile(content string) error {
+	return os.WriteFile(m.scopeFilePath(), []byte(content), 0o600)
+}
@@ -0,0 +1,75 @@
+package scopepin
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManager_SetAndClear(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("FCGH_TEST_DIR", tempDir)
+	manager := NewManager(tempDir)
+
+	if scope, err := manager.GetScope(); err != nil || scope != "" {
+		t.Fatalf("GetScope() = %q, %v before pinning", scope, err)
+	}
+	if err := manager.SetScope(" api "); err != nil {
+		t.Fatal(err)
+	}
+	if scope, err := manager.GetScope(); err != nil || scope != "api" {
+		t.Errorf("GetScope() = %q, %v, want api", scope, err)
+	}
+
+	for _, invalid := range []string{"", "two words", "api)", "a:b"} {
+		if err := manager.SetScope(invalid); err == nil {
+			t.Errorf("SetScope(%q) succeeded", invalid)
+		}
+	}
+	if scope, _ := manager.GetScope(); scope != "api" {
+		t.Errorf("GetScope() = %q after invalid SetScope()", scope)
+	}
+
+	if err := manager.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if scope, err := manager.GetScope(); err != nil || scope != "" {
+		t.Errorf("GetScope() = %q, %v after Clear()", scope, err)
+	}
+}
+
+func TestNewManager_PerWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	worktree := filepath.Join(t.TempDir(), "wt")
+	t.Setenv("HOME", repo)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "commit", "-q", "--allow-empty", "-m", "feat: one"},
+		{"worktree", "add", "-q", worktree},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	if err := NewManager(repo).SetScope("api"); err != nil {
+		t.Fatal(err)
+	}
+	if scope, err := NewManager(worktree).GetScope(); err != nil || scope != "" {
+		t.Errorf("worktree GetScope() = %q, %v, want no scope", scope, err)
+	}
+	if err := NewManager(worktree).SetScope("docs"); err != nil {
+		t.Fatal(err)
+	}
+	if scope, _ := NewManager(repo).GetScope(); scope != "api" {
+		t.Errorf("repository GetScope() = %q after pinning in the worktree", scope)
+	}
+	if out, err := exec.Command("git", "-C", repo, "status", "--porcelain").Output(); err != nil || strings.TrimSpace(string(out)) != "" {
+		t.Errorf("pinning left changes in the working tree: %q, %v", out, err)
+	}
+}
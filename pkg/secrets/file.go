@@ -0,0 +1,179 @@
+// Package secrets - AES-GCM encrypted credential file
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	credentialsFile = "credentials.enc"
+	keyFile         = "credentials.key"
+	keySize         = 32
+)
+
+// FileStore keeps secrets in an AES-256-GCM encrypted file next to a random
+// key, both readable only by the user. It keeps tokens out of YAML config
+// files and backups of them; it does not protect against someone who can
+// read the user's home directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a file store in dir
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// StoreFiles returns the names of the files a FileStore keeps in its
+// directory, for tools copying the directory that must leave them out
+func StoreFiles() []string {
+	return []string{credentialsFile, keyFile}
+}
+
+// Name describes the store location
+func (f *FileStore) Name() string {
+	return "encrypted file " + filepath.Join(f.dir, credentialsFile)
+}
+
+// Get returns the secret for a service, or ErrNotFound
+func (f *FileStore) Get(service string) (string, error) {
+	secrets, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[service]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set stores the secret for a service
+func (f *FileStore) Set(service, secret string) error {
+	secrets, err := f.load()
+	if err != nil {
+		return err
+	}
+	secrets[service] = secret
+	return f.save(secrets)
+}
+
+// Delete removes the secret for a service
+func (f *FileStore) Delete(service string) error {
+	secrets, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[service]; !ok {
+		return nil
+	}
+	delete(secrets, service)
+	return f.save(secrets)
+}
+
+// load decrypts the credentials file; a missing file holds no secrets
+func (f *FileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(filepath.Join(f.dir, credentialsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading credentials: %w", err)
+	}
+
+	key, err := os.ReadFile(filepath.Join(f.dir, keyFile))
+	if err != nil {
+		return nil, fmt.Errorf("reading credentials key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("credentials file is corrupt")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting credentials: %w", err)
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("decoding credentials: %w", err)
+	}
+	return secrets, nil
+}
+
+// save encrypts the secrets with the key file, creating the key on first use
+func (f *FileStore) save(secrets map[string]string) error {
+	if err := os.MkdirAll(f.dir, 0o700); err != nil {
+		return fmt.Errorf("creating credentials directory: %w", err)
+	}
+	key, err := f.loadOrCreateKey()
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("encoding credentials: %w", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+	data := gcm.Seal(nonce, nonce, plaintext, nil)
+
+	// Write atomically so an interrupted save never loses existing secrets
+	tmp := filepath.Join(f.dir, credentialsFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing credentials: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(f.dir, credentialsFile)); err != nil {
+		return fmt.Errorf("writing credentials: %w", err)
+	}
+	return nil
+}
+
+func (f *FileStore) loadOrCreateKey() ([]byte, error) {
+	path := filepath.Join(f.dir, keyFile)
+	key, err := os.ReadFile(path)
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading credentials key: %w", err)
+	}
+
+	key = make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("generating credentials key: %w", err)
+	}
+	if err := os.WriteFile(path, key, 0o600); err != nil {
+		return nil, fmt.Errorf("writing credentials key: %w", err)
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, errors.New("credentials key is corrupt")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,93 @@
+// Package secrets - OS keychain access through the platform's CLI tools
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"os/user"
+	"runtime"
+	"strings"
+)
+
+// keychainStore uses the macOS keychain (`security`) or the freedesktop
+// secret service (`secret-tool`)
+type keychainStore struct {
+	tool    string
+	account string
+}
+
+// newKeychainStore returns the platform keychain, or nil when its tool is
+// not installed
+func newKeychainStore() *keychainStore {
+	var tool string
+	switch runtime.GOOS {
+	case "darwin":
+		tool = "security"
+	case "linux", "freebsd", "openbsd":
+		tool = "secret-tool"
+	default:
+		return nil
+	}
+	path, err := exec.LookPath(tool)
+	if err != nil {
+		return nil
+	}
+
+	account := "fast-cc"
+	if u, err := user.Current(); err == nil {
+		account = u.Username
+	}
+	return &keychainStore{tool: path, account: account}
+}
+
+func (k *keychainStore) Name() string {
+	if runtime.GOOS == "darwin" {
+		return "macOS keychain"
+	}
+	return "secret service"
+}
+
+func (k *keychainStore) Get(service string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command(k.tool, "find-generic-password", "-s", service, "-w") // #nosec G204 - fixed tool
+	} else {
+		cmd = exec.Command(k.tool, "lookup", "service", service) // #nosec G204 - fixed tool
+	}
+	output, err := cmd.Output()
+	secret := strings.TrimSpace(string(output))
+	if err != nil || secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (k *keychainStore) Set(service, secret string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command(k.tool, "add-generic-password", "-U", "-s", service, "-a", k.account, "-w", secret) // #nosec G204 - fixed tool
+	} else {
+		cmd = exec.Command(k.tool, "store", "--label", service, "service", service) // #nosec G204 - fixed tool
+		cmd.Stdin = strings.NewReader(secret)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("storing %s in the %s: %w: %s", service, k.Name(), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (k *keychainStore) Delete(service string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command(k.tool, "delete-generic-password", "-s", service) // #nosec G204 - fixed tool
+	} else {
+		cmd = exec.Command(k.tool, "clear", "service", service) // #nosec G204 - fixed tool
+	}
+	if err := cmd.Run(); err != nil {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Package secrets stores integration credentials (such as API tokens) in the
+// OS keychain, falling back to an encrypted file
+package secrets
+
+import (
+	"errors"
+	"os"
+
+	"github.com/greenstevester/fast-cc-git-hooks/internal/dirs"
+)
+
+// Services under which integration tokens are stored
+const (
+	JIRAService   = "fast-cc-jira"
+	GitHubService = "fast-cc-github"
+)
+
+// ErrNotFound indicates no secret is stored for a service
+var ErrNotFound = errors.New("secret not found")
+
+// Store saves secrets by service name
+type Store interface {
+	// Get returns the secret for a service, or ErrNotFound
+	Get(service string) (string, error)
+	// Set stores the secret for a service, replacing any previous one
+	Set(service, secret string) error
+	// Delete removes the secret for a service; deleting a missing secret is not an error
+	Delete(service string) error
+	// Name describes where secrets are kept, for status output
+	Name() string
+}
+
+// Default returns the OS keychain when one is available, backed by an
+// encrypted file in the config directory for systems without one (or when
+// the keychain cannot be used, such as over SSH without a secret service
+// session)
+func Default() Store {
+	file := NewFileStore(defaultDir())
+	if keychain := newKeychainStore(); keychain != nil {
+		return &fallbackStore{primary: keychain, fallback: file}
+	}
+	return file
+}
+
+// Lookup returns the secret for a service from the default store, or "" when
+// none is stored or the store cannot be read
+func Lookup(service string) string {
+	secret, err := Default().Get(service)
+	if err != nil {
+		return ""
+	}
+	return secret
+}
+
+// defaultDir returns the config directory (FCGH_TEST_DIR overrides it, as
+// for the JIRA ticket file)
+func defaultDir() string {
+	if dir := os.Getenv("FCGH_TEST_DIR"); dir != "" {
+		return dir
+	}
+	dir, err := dirs.Config()
+	if err != nil {
+		return dirs.LegacyName
+	}
+	return dir
+}
+
+// fallbackStore prefers the primary store and uses the fallback when the
+// primary fails
+type fallbackStore struct {
+	primary  Store
+	fallback Store
+}
+
+func (s *fallbackStore) Get(service string) (string, error) {
+	if secret, err := s.primary.Get(service); err == nil {
+		return secret, nil
+	}
+	return s.fallback.Get(service)
+}
+
+func (s *fallbackStore) Set(service, secret string) error {
+	if err := s.primary.Set(service, secret); err == nil {
+		// Do not leave an older copy behind in the file
+		_ = s.fallback.Delete(service)
+		return nil
+	}
+	return s.fallback.Set(service, secret)
+}
+
+func (s *fallbackStore) Delete(service string) error {
+	primaryErr := s.primary.Delete(service)
+	if err := s.fallback.Delete(service); err != nil {
+		return err
+	}
+	if primaryErr != nil && !errors.Is(primaryErr, ErrNotFound) {
+		return primaryErr
+	}
+	return nil
+}
+
+func (s *fallbackStore) Name() string {
+	return s.primary.Name() + " (falling back to " + s.fallback.Name() + ")"
+}
@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+
+	if _, err := store.Get(JIRAService); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound from an empty store, got %v", err)
+	}
+	if err := store.Set(JIRAService, "jira-token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set(GitHubService, "gh-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh store reads what the first one wrote
+	if got, err := NewFileStore(dir).Get(JIRAService); err != nil || got != "jira-token" {
+		t.Errorf("Get() = %q, %v", got, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, credentialsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "jira-token") {
+		t.Error("credentials file contains the plaintext token")
+	}
+	if info, err := os.Stat(filepath.Join(dir, keyFile)); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected a 0600 key file, got %v, %v", info, err)
+	}
+
+	if err := store.Delete(JIRAService); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(JIRAService); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the deleted secret to be gone, got %v", err)
+	}
+	if got, _ := store.Get(GitHubService); got != "gh-token" {
+		t.Errorf("Delete() removed another service's secret")
+	}
+}
+
+// brokenStore fails every operation, like a keychain without a session
+type brokenStore struct{}
+
+func (brokenStore) Get(string) (string, error) { return "", ErrNotFound }
+func (brokenStore) Set(string, string) error   { return errors.New("no secret service") }
+func (brokenStore) Delete(string) error        { return ErrNotFound }
+func (brokenStore) Name() string               { return "broken" }
+
+func TestFallbackStore(t *testing.T) {
+	store := &fallbackStore{primary: brokenStore{}, fallback: NewFileStore(t.TempDir())}
+
+	if err := store.Set(JIRAService, "token"); err != nil {
+		t.Fatalf("Set() should fall back to the file, got %v", err)
+	}
+	if got, err := store.Get(JIRAService); err != nil || got != "token" {
+		t.Errorf("Get() = %q, %v", got, err)
+	}
+	if err := store.Delete(JIRAService); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if _, err := store.Get(JIRAService); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after Delete(), got %v", err)
+	}
+}
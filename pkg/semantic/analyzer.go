@@ -59,6 +59,30 @@ func (c *CCSemanticAnalyzer) AnalyzeDiff(diff string) (*SemanticChange, error) {
 	return c.selectPrimaryChange(changes), nil
 }
 
+// ComposeDiff analyzes a git diff and composes the changes found by every
+// plugin into one commit with a primary subject and body bullets
+func (c *CCSemanticAnalyzer) ComposeDiff(diff string) (*Composition, error) {
+	if !c.enabled {
+		return nil, nil
+	}
+
+	files := c.parseDiffToFileChanges(diff)
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	composition, err := c.analyzer.AnalyzeChangeset(context.Background(), files)
+	if err != nil {
+		return nil, fmt.Errorf("semantic analysis failed: %w", err)
+	}
+	return composition, nil
+}
+
+// ParseDiff converts a unified git diff into FileChange objects for analysis
+func ParseDiff(diff string) []FileChange {
+	return (&CCSemanticAnalyzer{}).parseDiffToFileChanges(diff)
+}
+
 // parseDiffToFileChanges converts a git diff string to FileChange objects
 func (c *CCSemanticAnalyzer) parseDiffToFileChanges(diff string) []FileChange {
 	var files []FileChange
@@ -111,6 +135,23 @@ func (c *CCSemanticAnalyzer) parseFileSection(section string) *FileChange {
 		changeType = "deleted"
 	}
 
+	// Renames and copies (git diff -M -C) carry both paths in extended headers
+	var oldPath string
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "rename from "):
+			oldPath = strings.TrimPrefix(line, "rename from ")
+			changeType = "renamed"
+		case strings.HasPrefix(line, "rename to "):
+			filePath = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "copy from "):
+			oldPath = strings.TrimPrefix(line, "copy from ")
+			changeType = "added"
+		case strings.HasPrefix(line, "copy to "):
+			filePath = strings.TrimPrefix(line, "copy to ")
+		}
+	}
+
 	// Extract content changes
 	var beforeContent, afterContent strings.Builder
 	diffContent := section
@@ -131,6 +172,7 @@ func (c *CCSemanticAnalyzer) parseFileSection(section string) *FileChange {
 		AfterContent:  afterContent.String(),
 		DiffContent:   diffContent,
 		ChangeType:    changeType,
+		OldPath:       oldPath,
 	}
 }
 
@@ -162,52 +204,11 @@ func (c *CCSemanticAnalyzer) detectLanguageFromPath(path string) string {
 
 // selectPrimaryChange selects the most relevant change from multiple detected changes
 func (c *CCSemanticAnalyzer) selectPrimaryChange(changes []*SemanticChange) *SemanticChange {
-	if len(changes) == 0 {
+	ranked := RankChanges(changes)
+	if len(ranked) == 0 {
 		return nil
 	}
-
-	if len(changes) == 1 {
-		return changes[0]
-	}
-
-	// Priority: breaking changes > feat > fix > perf > refactor > others
-	priority := map[string]int{
-		"feat":     1,
-		"fix":      2,
-		"perf":     3,
-		"refactor": 4,
-		"docs":     5,
-		"test":     6,
-		"build":    7,
-		"ci":       8,
-		"chore":    9,
-	}
-
-	var best *SemanticChange
-	bestScore := 999
-
-	for _, change := range changes {
-		score := priority[change.Type]
-		if score == 0 {
-			score = 10 // unknown types get lowest priority
-		}
-
-		// Breaking changes get higher priority
-		if change.BreakingChange {
-			score -= 5
-		}
-
-		// Higher confidence gets priority
-		confidenceBonus := int((1.0 - change.Confidence) * 3)
-		score += confidenceBonus
-
-		if score < bestScore {
-			bestScore = score
-			best = change
-		}
-	}
-
-	return best
+	return ranked[0]
 }
 
 // Enable enables semantic analysis
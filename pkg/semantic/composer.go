@@ -0,0 +1,170 @@
+// Package semantic - Whole-changeset composition across plugins
+package semantic
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangesetAnalyzer is implemented by plugins that can summarise all of the
+// files they claim in a changeset at once. A nil result falls back to
+// per-file analysis for that plugin's files.
+type ChangesetAnalyzer interface {
+	AnalyzeChangeset(ctx context.Context, files []FileChange, context AnalysisContext) (*SemanticChange, error)
+}
+
+// Composition is a commit-ready summary of the changes found across plugins
+type Composition struct {
+	Primary *SemanticChange   // change the commit subject is built from
+	Bullets []string          // body lines for the remaining changes
+	Changes []*SemanticChange // every input change, most relevant first
+}
+
+// typePriority orders commit types from most to least significant
+var typePriority = map[string]int{
+	"feat":     1,
+	"fix":      2,
+	"perf":     3,
+	"refactor": 4,
+	"docs":     5,
+	"test":     6,
+	"build":    7,
+	"ci":       8,
+	"chore":    9,
+}
+
+// relevance scores a change; lower scores are more relevant
+func relevance(change *SemanticChange) int {
+	score := typeRank(change)
+
+	// Breaking changes get higher priority
+	if change.BreakingChange {
+		score -= 5
+	}
+
+	// Higher confidence gets priority
+	return score + int((1.0-change.Confidence)*3)
+}
+
+// RankChanges returns the changes ordered from most to least relevant.
+// Ties are broken by type, scope and description so the order is stable.
+func RankChanges(changes []*SemanticChange) []*SemanticChange {
+	ranked := make([]*SemanticChange, 0, len(changes))
+	for _, change := range changes {
+		if change != nil {
+			ranked = append(ranked, change)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if ra, rb := relevance(a), relevance(b); ra != rb {
+			return ra < rb
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+		return a.Description < b.Description
+	})
+	return ranked
+}
+
+// Compose synthesizes a single commit from per-file or per-plugin changes.
+// The most relevant change becomes the subject; the others become body bullets
+// prefixed with their scope when it differs from the subject's. The primary
+// change covers every file and is breaking if any input is.
+func Compose(changes []*SemanticChange) *Composition {
+	ranked := RankChanges(changes)
+	if len(ranked) == 0 {
+		return nil
+	}
+
+	lead := ranked[0]
+	primary := *lead
+	primary.Metadata = make(map[string]string, len(lead.Metadata)+1)
+	for key, value := range lead.Metadata {
+		primary.Metadata[key] = value
+	}
+
+	seenFiles := make(map[string]bool)
+	var files, scopes []string
+	seenScopes := make(map[string]bool)
+	for _, change := range ranked {
+		primary.BreakingChange = primary.BreakingChange || change.BreakingChange
+		for _, file := range change.Files {
+			if !seenFiles[file] {
+				seenFiles[file] = true
+				files = append(files, file)
+			}
+		}
+		if change.Scope != "" && !seenScopes[change.Scope] {
+			seenScopes[change.Scope] = true
+			scopes = append(scopes, change.Scope)
+		}
+	}
+	primary.Files = files
+
+	composition := &Composition{Primary: &primary, Changes: ranked}
+	if len(ranked) == 1 {
+		return composition
+	}
+
+	primary.Metadata["scopes"] = strings.Join(scopes, ",")
+	primary.Reasoning = fmt.Sprintf("Composed with %d other changes", len(ranked)-1)
+	if lead.Reasoning != "" {
+		primary.Reasoning = fmt.Sprintf("%s; composed with %d other changes", lead.Reasoning, len(ranked)-1)
+	}
+	for _, change := range ranked[1:] {
+		composition.Bullets = append(composition.Bullets, bullet(change, primary.Scope))
+	}
+	return composition
+}
+
+// bullet describes a secondary change for the commit body
+func bullet(change *SemanticChange, primaryScope string) string {
+	line := change.Description
+	if change.Scope != "" && change.Scope != primaryScope {
+		line = fmt.Sprintf("%s: %s", change.Scope, line)
+	}
+	if change.BreakingChange {
+		line += " (breaking)"
+	}
+	return line
+}
+
+// Subject returns the conventional commit header for the composition
+func (c *Composition) Subject() string {
+	header := c.Primary.Type
+	if c.Primary.Scope != "" {
+		header += "(" + c.Primary.Scope + ")"
+	}
+	if c.Primary.BreakingChange {
+		header += "!"
+	}
+	return header + ": " + c.Primary.Description
+}
+
+// Body returns the bulleted commit body, or "" for a single change
+func (c *Composition) Body() string {
+	if len(c.Bullets) == 0 {
+		return ""
+	}
+	var body strings.Builder
+	for _, line := range c.Bullets {
+		body.WriteString("- " + line + "\n")
+	}
+	return strings.TrimSuffix(body.String(), "\n")
+}
+
+// Message returns the subject and body as a commit message
+func (c *Composition) Message() string {
+	if body := c.Body(); body != "" {
+		return c.Subject() + "\n\n" + body
+	}
+	return c.Subject()
+}
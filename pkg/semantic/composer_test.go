@@ -0,0 +1,88 @@
+package semantic
+
+import (
+	"context"
+	"testing"
+)
+
+// changesetPlugin summarises all of its files in one change
+type changesetPlugin struct{ fakePlugin }
+
+func (c *changesetPlugin) AnalyzeChangeset(_ context.Context, files []FileChange, _ AnalysisContext) (*SemanticChange, error) {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	return &SemanticChange{Type: "chore", Scope: "infra", Description: "update stacks", Files: paths, Confidence: 0.9}, nil
+}
+
+func TestCompose(t *testing.T) {
+	t.Run("primary and bullets", func(t *testing.T) {
+		composition := Compose([]*SemanticChange{
+			{Type: "chore", Scope: "infra", Description: "update stacks", Files: []string{"main.tf"}, Confidence: 0.9},
+			nil,
+			{Type: "feat", Scope: "db", Description: "add orders table", Files: []string{"V1.sql"}, Confidence: 0.9},
+			{Type: "ci", Scope: "ci", Description: "cache go modules", Files: []string{"ci.yml"}, Confidence: 0.8, BreakingChange: true},
+		})
+
+		if got := composition.Subject(); got != "feat(db)!: add orders table" {
+			t.Errorf("Subject() = %q", got)
+		}
+		want := "- ci: cache go modules (breaking)\n- infra: update stacks"
+		if got := composition.Body(); got != want {
+			t.Errorf("Body() = %q, want %q", got, want)
+		}
+		if len(composition.Primary.Files) != 3 || composition.Primary.Metadata["scopes"] != "db,ci,infra" {
+			t.Errorf("unexpected primary %+v", composition.Primary)
+		}
+		if len(composition.Changes) != 3 {
+			t.Errorf("expected nil changes to be dropped, got %d", len(composition.Changes))
+		}
+	})
+
+	t.Run("single change", func(t *testing.T) {
+		lead := &SemanticChange{Type: "fix", Scope: "auth", Description: "reject expired tokens", Confidence: 1}
+		composition := Compose([]*SemanticChange{lead})
+		if got := composition.Message(); got != "fix(auth): reject expired tokens" {
+			t.Errorf("Message() = %q", got)
+		}
+		composition.Primary.Metadata["x"] = "y"
+		if lead.Metadata != nil {
+			t.Error("Compose() must not modify its inputs")
+		}
+	})
+
+	t.Run("nothing to compose", func(t *testing.T) {
+		if composition := Compose(nil); composition != nil {
+			t.Errorf("Compose(nil) = %+v", composition)
+		}
+	})
+
+	t.Run("changeset analyzers summarise their files", func(t *testing.T) {
+		registry := NewPluginRegistry()
+		for _, plugin := range []SemanticPlugin{
+			&fakePlugin{name: "golang", extensions: []string{".go"}},
+			&changesetPlugin{fakePlugin{name: "terraform", extensions: []string{".tf"}}},
+		} {
+			if err := registry.Register(plugin); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		composition, err := NewSemanticAnalyzer(registry).AnalyzeChangeset(context.Background(), []FileChange{
+			{Path: "main.tf", ChangeType: "modified"},
+			{Path: "cmd/main.go", ChangeType: "modified"},
+			{Path: "vpc.tf", ChangeType: "modified"},
+			{Path: "README.md", ChangeType: "modified"},
+		})
+		if err != nil {
+			t.Fatalf("AnalyzeChangeset() error = %v", err)
+		}
+		if got := composition.Message(); got != "feat: golang\n\n- infra: update stacks" {
+			t.Errorf("Message() = %q", got)
+		}
+		if files := composition.Changes[1].Files; len(files) != 2 {
+			t.Errorf("expected both Terraform files in one change, got %v", files)
+		}
+	})
+}
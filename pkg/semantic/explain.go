@@ -0,0 +1,91 @@
+// Package semantic - Explaining how a changeset was classified
+package semantic
+
+import (
+	"context"
+	"fmt"
+)
+
+// PluginResult is one plugin's raw verdict before results are consolidated
+type PluginResult struct {
+	Plugin string          // plugin that produced the result
+	Files  []string        // files the plugin analyzed (empty for project-level results)
+	Change *SemanticChange // detected change, nil when the plugin failed
+	Err    error           // analysis error, if any
+}
+
+// Explanation records every plugin result and why the primary change won
+type Explanation struct {
+	Results     []PluginResult
+	Composition *Composition // nil when no plugin recognised the changeset
+	Decision    string       // human-readable reason the primary change was chosen
+}
+
+// Confidence returns the confidence of the primary change, or 0 when no
+// plugin could classify the changeset
+func (e *Explanation) Confidence() float64 {
+	if e.Composition == nil {
+		return 0
+	}
+	return e.Composition.Primary.Confidence
+}
+
+// Explain analyzes the files like AnalyzeChangeset and also returns the
+// individual plugin results and the reason the primary change was selected
+func (s *SemanticAnalyzer) Explain(ctx context.Context, files []FileChange) (*Explanation, error) {
+	results := s.collect(ctx, files)
+
+	var changes []*SemanticChange
+	for _, result := range results {
+		if result.Change != nil {
+			changes = append(changes, result.Change)
+		}
+	}
+
+	consolidated := s.consolidateChanges(changes)
+	return &Explanation{
+		Results:     results,
+		Composition: Compose(consolidated),
+		Decision:    decide(RankChanges(consolidated)),
+	}, nil
+}
+
+// decide explains why the first ranked change beat the runner-up
+func decide(ranked []*SemanticChange) string {
+	switch len(ranked) {
+	case 0:
+		return "no plugin recognised the changes"
+	case 1:
+		return fmt.Sprintf("%s is the only change detected", label(ranked[0]))
+	}
+
+	winner, runnerUp := ranked[0], ranked[1]
+	var reason string
+	switch {
+	case winner.BreakingChange && !runnerUp.BreakingChange:
+		reason = "breaking changes take precedence"
+	case relevance(winner) == relevance(runnerUp):
+		reason = "equal relevance, tie broken by type, scope and description"
+	case typeRank(winner) != typeRank(runnerUp):
+		reason = fmt.Sprintf("%s outranks %s", winner.Type, runnerUp.Type)
+	default:
+		reason = fmt.Sprintf("higher confidence (%.2f vs %.2f)", winner.Confidence, runnerUp.Confidence)
+	}
+	return fmt.Sprintf("%s won over %s: %s", label(winner), label(runnerUp), reason)
+}
+
+// typeRank returns the priority of a change's type (unknown types rank last)
+func typeRank(change *SemanticChange) int {
+	if rank, ok := typePriority[change.Type]; ok {
+		return rank
+	}
+	return 10
+}
+
+// label formats a change as type(scope)
+func label(change *SemanticChange) string {
+	if change.Scope == "" {
+		return change.Type
+	}
+	return fmt.Sprintf("%s(%s)", change.Type, change.Scope)
+}
@@ -0,0 +1,80 @@
+package semantic
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDecide(t *testing.T) {
+	feat := &SemanticChange{Type: "feat", Scope: "db", Confidence: 0.9}
+	tests := []struct {
+		name   string
+		ranked []*SemanticChange
+		want   string
+	}{
+		{"nothing", nil, "no plugin recognised the changes"},
+		{"single", []*SemanticChange{feat}, "feat(db) is the only change detected"},
+		{"breaking", []*SemanticChange{{Type: "chore", BreakingChange: true, Confidence: 0.9}, feat},
+			"chore won over feat(db): breaking changes take precedence"},
+		{"type", []*SemanticChange{feat, {Type: "ci", Scope: "ci", Confidence: 0.9}},
+			"feat(db) won over ci(ci): feat outranks ci"},
+		{"confidence", []*SemanticChange{feat, {Type: "feat", Scope: "api", Confidence: 0.5}},
+			"feat(db) won over feat(api): higher confidence (0.90 vs 0.50)"},
+		{"tie", []*SemanticChange{{Type: "feat", Scope: "api", Confidence: 0.9}, feat},
+			"feat(api) won over feat(db): equal relevance, tie broken by type, scope and description"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decide(tt.ranked); got != tt.want {
+				t.Errorf("decide() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	registry := NewPluginRegistry()
+	if err := registry.Register(&fakePlugin{name: "golang", extensions: []string{".go"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	explanation, err := NewSemanticAnalyzer(registry).Explain(context.Background(), []FileChange{
+		{Path: "a.go", ChangeType: "modified"},
+		{Path: "README.md", ChangeType: "modified"},
+	})
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if len(explanation.Results) != 1 || explanation.Results[0].Plugin != "golang" || explanation.Results[0].Files[0] != "a.go" {
+		t.Errorf("Results = %+v", explanation.Results)
+	}
+	if explanation.Confidence() != 0 || explanation.Decision != "feat is the only change detected" {
+		t.Errorf("unexpected explanation %+v", explanation)
+	}
+}
+
+func TestConsolidateChangesKeepsOrder(t *testing.T) {
+	changes := []*SemanticChange{
+		{Type: "fix", Scope: "web", Description: "fix form", Files: []string{"web/form.ts"}},
+		{Type: "feat", Scope: "api", Description: "add orders", Files: []string{"api/orders.go", "api/routes.go"}},
+		{Type: "fix", Scope: "web", Description: "fix menu", Files: []string{"web/menu.ts", "web/form.ts"}},
+		{Type: "docs", Description: "document api", Files: []string{"README.md"}},
+	}
+
+	for i := 0; i < 20; i++ {
+		got := NewSemanticAnalyzer(NewPluginRegistry()).consolidateChanges(changes)
+		var summary []string
+		for _, change := range got {
+			summary = append(summary, change.Type+":"+change.Scope+" "+strings.Join(change.Files, ","))
+		}
+		want := []string{
+			"fix:web web/form.ts,web/menu.ts",
+			"feat:api api/orders.go,api/routes.go",
+			"docs: README.md",
+		}
+		if strings.Join(summary, "\n") != strings.Join(want, "\n") {
+			t.Fatalf("consolidateChanges() =\n%s\nwant:\n%s", strings.Join(summary, "\n"), strings.Join(want, "\n"))
+		}
+	}
+}
@@ -0,0 +1,367 @@
+// Package external runs semantic analyzers shipped as separate executables.
+//
+// An external plugin is any executable placed in the plugin directory
+// (~/.config/fast-cc/plugins by default). It speaks a small JSON protocol over
+// stdin/stdout, selected by its first argument:
+//
+//	describe          -> {"protocol":1,"name":"...","version":"...","extensions":[".x"],
+//	                      "patterns":["dir/*"],"markers":["import foo"],"priority":0,
+//	                      "default_config":{"key":"value"}}
+//	analyze-file      <- {"file":{...},"context":{...}}  -> {"change":{...}|null,"error":""}
+//	analyze-project   <- {"context":{...}}               -> {"change":{...}|null,"error":""}
+//	validate-config   <- {"config":{...}}                -> {"error":""}
+//
+// Changes use the JSON form of semantic.SemanticChange. Plugins can be written
+// in any language and need no fast-cc-git-hooks dependency.
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// ProtocolVersion is the protocol version this package speaks
+const ProtocolVersion = 1
+
+const (
+	// describeTimeout bounds plugin discovery
+	describeTimeout = 5 * time.Second
+	// callTimeout bounds a single analysis call
+	callTimeout = 30 * time.Second
+)
+
+// description is a plugin's reply to `describe`
+type description struct {
+	Protocol      int               `json:"protocol"`
+	Name          string            `json:"name"`
+	Version       string            `json:"version"`
+	Extensions    []string          `json:"extensions"`
+	Patterns      []string          `json:"patterns"`
+	Markers       []string          `json:"markers"`
+	Priority      int               `json:"priority"`
+	DefaultConfig map[string]string `json:"default_config"`
+}
+
+// wireFile is the JSON form of semantic.FileChange
+type wireFile struct {
+	Path          string `json:"path"`
+	Language      string `json:"language,omitempty"`
+	BeforeContent string `json:"before_content,omitempty"`
+	AfterContent  string `json:"after_content,omitempty"`
+	DiffContent   string `json:"diff_content,omitempty"`
+	ChangeType    string `json:"change_type"`
+	OldPath       string `json:"old_path,omitempty"`
+}
+
+// wireContext is the JSON form of semantic.AnalysisContext
+type wireContext struct {
+	Repository  string            `json:"repository,omitempty"`
+	Branch      string            `json:"branch,omitempty"`
+	Files       []wireFile        `json:"files"`
+	ProjectType string            `json:"project_type,omitempty"`
+	Config      map[string]string `json:"config"`
+	Hotspots    map[string]int    `json:"hotspots,omitempty"` // recent commit counts of hotspot files
+}
+
+// response is a plugin's reply to an analysis or validation call
+type response struct {
+	Change *semantic.SemanticChange `json:"change"`
+	Error  string                   `json:"error"`
+}
+
+// Plugin adapts an external executable to semantic.SemanticPlugin
+type Plugin struct {
+	path string
+	args []string // arguments placed before the protocol command
+	desc description
+}
+
+// Load starts the executable at path with `describe` and returns the plugin it describes
+func Load(path string, args ...string) (*Plugin, error) {
+	p := &Plugin{path: path, args: args}
+
+	ctx, cancel := context.WithTimeout(context.Background(), describeTimeout)
+	defer cancel()
+
+	out, err := p.run(ctx, "describe", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(out, &p.desc); err != nil {
+		return nil, fmt.Errorf("%s: parsing describe output: %w", filepath.Base(path), err)
+	}
+	if p.desc.Protocol != ProtocolVersion {
+		return nil, fmt.Errorf("%s: unsupported protocol version %d (want %d)", filepath.Base(path), p.desc.Protocol, ProtocolVersion)
+	}
+	if p.desc.Name == "" {
+		return nil, fmt.Errorf("%s: describe returned no plugin name", filepath.Base(path))
+	}
+	return p, nil
+}
+
+// Discover loads every executable in dir. A missing directory yields no
+// plugins; plugins that fail to load are reported without stopping discovery.
+func Discover(dir string) ([]*Plugin, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, []error{fmt.Errorf("reading plugin directory: %w", err)}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var plugins []*Plugin
+	var errs []error
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !isExecutable(info) {
+			continue
+		}
+
+		plugin, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		plugins = append(plugins, plugin)
+	}
+	return plugins, errs
+}
+
+// Register discovers plugins in dir and adds them to the registry. Plugins
+// whose names clash with already registered ones are reported and skipped.
+func Register(registry *semantic.PluginRegistry, dir string) []error {
+	plugins, errs := Discover(dir)
+	for _, plugin := range plugins {
+		if err := registry.Register(plugin); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(plugin.path), err))
+		}
+	}
+	return errs
+}
+
+// isExecutable reports whether a directory entry can be run as a plugin
+func isExecutable(info os.FileInfo) bool {
+	if !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(info.Name())) {
+		case ".exe", ".bat", ".cmd":
+			return true
+		}
+		return false
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
+
+// Path returns the executable backing the plugin
+func (p *Plugin) Path() string {
+	return p.path
+}
+
+// Name returns the plugin name
+func (p *Plugin) Name() string {
+	return p.desc.Name
+}
+
+// Version returns the plugin version
+func (p *Plugin) Version() string {
+	return p.desc.Version
+}
+
+// SupportedExtensions returns file extensions this plugin supports
+func (p *Plugin) SupportedExtensions() []string {
+	return p.desc.Extensions
+}
+
+// SupportedFilePatterns returns file patterns this plugin supports
+func (p *Plugin) SupportedFilePatterns() []string {
+	return p.desc.Patterns
+}
+
+// Priority returns the priority the plugin asked for
+func (p *Plugin) Priority() int {
+	return p.desc.Priority
+}
+
+// CanAnalyze matches the described extensions, patterns and content markers
+// without starting the executable
+func (p *Plugin) CanAnalyze(file semantic.FileChange) bool {
+	ext := strings.ToLower(filepath.Ext(file.Path))
+	for _, supported := range p.desc.Extensions {
+		if ext == supported {
+			return true
+		}
+	}
+
+	path := filepath.ToSlash(file.Path)
+	for _, pattern := range p.desc.Patterns {
+		target := path
+		if !strings.Contains(pattern, "/") {
+			target = filepath.Base(path)
+		}
+		if matched, err := filepath.Match(pattern, target); err == nil && matched {
+			return true
+		}
+	}
+
+	for _, marker := range p.desc.Markers {
+		if strings.Contains(file.AfterContent, marker) || strings.Contains(file.BeforeContent, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// AnalyzeFile sends the file to the executable
+func (p *Plugin) AnalyzeFile(ctx context.Context, file semantic.FileChange, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	request := map[string]any{"file": toWireFile(file), "context": toWireContext(analysisCtx)}
+	return p.analyze(ctx, "analyze-file", request)
+}
+
+// AnalyzeProject sends the whole changeset to the executable
+func (p *Plugin) AnalyzeProject(ctx context.Context, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	request := map[string]any{"context": toWireContext(analysisCtx)}
+	return p.analyze(ctx, "analyze-project", request)
+}
+
+// DefaultConfig returns the configuration the plugin described
+func (p *Plugin) DefaultConfig() map[string]string {
+	config := make(map[string]string, len(p.desc.DefaultConfig))
+	for key, value := range p.desc.DefaultConfig {
+		config[key] = value
+	}
+	return config
+}
+
+// ValidateConfig asks the executable to validate its configuration
+func (p *Plugin) ValidateConfig(config map[string]string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), describeTimeout)
+	defer cancel()
+
+	var resp response
+	if err := p.call(ctx, "validate-config", map[string]any{"config": config}, &resp); err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+// analyze performs an analysis call that returns an optional change
+func (p *Plugin) analyze(ctx context.Context, command string, request any) (*semantic.SemanticChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	var resp response
+	if err := p.call(ctx, command, request, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("%s: %s", p.desc.Name, resp.Error)
+	}
+	return resp.Change, nil
+}
+
+// call runs a protocol command with a JSON request and decodes the JSON reply
+func (p *Plugin) call(ctx context.Context, command string, request, reply any) error {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("encoding %s request: %w", command, err)
+	}
+
+	out, err := p.run(ctx, command, input)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, reply); err != nil {
+		return fmt.Errorf("%s: parsing %s output: %w", p.name(), command, err)
+	}
+	return nil
+}
+
+// run executes the plugin with a protocol command, returning stdout.
+// Stderr is included in errors so plugin authors can debug failures.
+func (p *Plugin) run(ctx context.Context, command string, input []byte) ([]byte, error) {
+	args := append(append([]string{}, p.args...), command)
+	cmd := exec.CommandContext(ctx, p.path, args...) // #nosec G204 - plugins are executables the user installed
+	cmd.Stdin = bytes.NewReader(input)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s %s: %w", p.name(), command, ctx.Err())
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s %s: %w: %s", p.name(), command, err, msg)
+		}
+		return nil, fmt.Errorf("%s %s: %w", p.name(), command, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// name identifies the plugin in errors, before and after describe
+func (p *Plugin) name() string {
+	if p.desc.Name != "" {
+		return p.desc.Name
+	}
+	return filepath.Base(p.path)
+}
+
+// toWireFile converts a file change to its JSON form
+func toWireFile(file semantic.FileChange) wireFile {
+	return wireFile{
+		Path:          file.Path,
+		Language:      file.Language,
+		BeforeContent: file.BeforeContent,
+		AfterContent:  file.AfterContent,
+		DiffContent:   file.DiffContent,
+		ChangeType:    file.ChangeType,
+		OldPath:       file.OldPath,
+	}
+}
+
+// toWireContext converts an analysis context to its JSON form
+func toWireContext(analysisCtx semantic.AnalysisContext) wireContext {
+	files := make([]wireFile, 0, len(analysisCtx.Files))
+	paths := make([]string, 0, len(analysisCtx.Files))
+	for _, file := range analysisCtx.Files {
+		files = append(files, toWireFile(file))
+		paths = append(paths, file.Path)
+	}
+
+	var hotspots map[string]int
+	if analysisCtx.Hotspots != nil {
+		hotspots = analysisCtx.Hotspots.Hotspots(paths)
+	}
+	return wireContext{
+		Repository:  analysisCtx.Repository,
+		Branch:      analysisCtx.Branch,
+		Files:       files,
+		ProjectType: analysisCtx.ProjectType,
+		Config:      analysisCtx.Config,
+		Hotspots:    hotspots,
+	}
+}
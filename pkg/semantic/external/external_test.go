@@ -0,0 +1,175 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// TestHelperProcess is not a real test; it acts as an external plugin when
+// the test binary is started by helperPlugin
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("FCGH_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	command := os.Args[len(os.Args)-1]
+	input, _ := io.ReadAll(os.Stdin)
+	out := json.NewEncoder(os.Stdout)
+
+	switch command {
+	case "describe":
+		_ = out.Encode(map[string]any{
+			"protocol":       ProtocolVersion,
+			"name":           "acme",
+			"version":        "0.3.0",
+			"extensions":     []string{".acme"},
+			"markers":        []string{"@acme"},
+			"priority":       5,
+			"default_config": map[string]string{"strict": "false"},
+		})
+	case "analyze-file":
+		var request struct {
+			File    wireFile    `json:"file"`
+			Context wireContext `json:"context"`
+		}
+		_ = json.Unmarshal(input, &request)
+		if request.File.ChangeType == "deleted" {
+			_ = out.Encode(map[string]any{"error": "cannot analyze deletions"})
+			return
+		}
+		_ = out.Encode(map[string]any{"change": semantic.SemanticChange{
+			Type:        "feat",
+			Scope:       "acme",
+			Description: fmt.Sprintf("add %s (strict=%s)", request.File.Path, request.Context.Config["strict"]),
+			Files:       []string{request.File.Path},
+			Confidence:  0.9,
+		}})
+	case "analyze-project":
+		_ = out.Encode(map[string]any{"change": nil})
+	case "validate-config":
+		var request struct {
+			Config map[string]string `json:"config"`
+		}
+		_ = json.Unmarshal(input, &request)
+		if v := request.Config["strict"]; v != "true" && v != "false" {
+			_ = out.Encode(map[string]any{"error": "strict must be true or false"})
+			return
+		}
+		_ = out.Encode(map[string]any{})
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %s", command)
+		os.Exit(2)
+	}
+}
+
+// helperPlugin loads the test binary as an external plugin
+func helperPlugin(t *testing.T) *Plugin {
+	t.Helper()
+	t.Setenv("FCGH_WANT_HELPER_PROCESS", "1")
+	plugin, err := Load(os.Args[0], "-test.run=TestHelperProcess", "--")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return plugin
+}
+
+func TestExternalPlugin(t *testing.T) {
+	plugin := helperPlugin(t)
+
+	t.Run("describe", func(t *testing.T) {
+		if plugin.Name() != "acme" || plugin.Version() != "0.3.0" || plugin.Priority() != 5 {
+			t.Errorf("unexpected description: %+v", plugin.desc)
+		}
+		if !plugin.CanAnalyze(semantic.FileChange{Path: "src/x.acme"}) {
+			t.Error("expected extension match")
+		}
+		if !plugin.CanAnalyze(semantic.FileChange{Path: "src/x.txt", AfterContent: "@acme widget"}) {
+			t.Error("expected marker match")
+		}
+		if plugin.CanAnalyze(semantic.FileChange{Path: "src/x.txt"}) {
+			t.Error("expected no match")
+		}
+	})
+
+	t.Run("analyze file", func(t *testing.T) {
+		file := semantic.FileChange{Path: "src/x.acme", ChangeType: "added"}
+		change, err := plugin.AnalyzeFile(context.Background(), file, semantic.AnalysisContext{Config: plugin.DefaultConfig()})
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+		if change.Type != "feat" || change.Description != "add src/x.acme (strict=false)" {
+			t.Errorf("unexpected change: %+v", change)
+		}
+
+		file.ChangeType = "deleted"
+		if _, err := plugin.AnalyzeFile(context.Background(), file, semantic.AnalysisContext{}); err == nil {
+			t.Error("expected plugin error to be returned")
+		}
+
+		change, err = plugin.AnalyzeProject(context.Background(), semantic.AnalysisContext{})
+		if err != nil || change != nil {
+			t.Errorf("AnalyzeProject() = %+v, %v", change, err)
+		}
+	})
+
+	t.Run("validate config through the analyzer", func(t *testing.T) {
+		registry := semantic.NewPluginRegistry()
+		if err := registry.Register(plugin); err != nil {
+			t.Fatal(err)
+		}
+		analyzer := semantic.NewSemanticAnalyzer(registry)
+		if err := analyzer.Configure(map[string]map[string]string{"acme": {"strict": "true"}}); err != nil {
+			t.Errorf("Configure() error = %v", err)
+		}
+		if err := analyzer.Configure(map[string]map[string]string{"acme": {"strict": "maybe"}}); err == nil {
+			t.Error("expected validation error from plugin")
+		}
+		if registry.Priority("acme") != 5 {
+			t.Errorf("Priority(acme) = %d", registry.Priority("acme"))
+		}
+	})
+}
+
+func TestDiscover(t *testing.T) {
+	if plugins, errs := Discover(filepath.Join(t.TempDir(), "missing")); plugins != nil || errs != nil {
+		t.Errorf("expected nothing for a missing directory, got %v %v", plugins, errs)
+	}
+
+	if runtime.GOOS == "windows" {
+		t.Skip("discovery fixtures are shell scripts")
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string, mode os.FileMode) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("notes.txt", "not a plugin", 0o644)
+	write(".hidden", "#!/bin/sh\nexit 1\n", 0o755)
+	write("broken", "#!/bin/sh\necho 'not json'\n", 0o755)
+	write("old", "#!/bin/sh\necho '{\"protocol\":0,\"name\":\"old\"}'\n", 0o755)
+	write("good", "#!/bin/sh\necho '{\"protocol\":1,\"name\":\"good\",\"version\":\"1.0.0\",\"extensions\":[\".good\"]}'\n", 0o755)
+
+	registry := semantic.NewPluginRegistry()
+	errs := Register(registry, dir)
+	if len(errs) != 2 {
+		t.Errorf("expected errors for broken and old plugins, got %v", errs)
+	}
+	if plugin := registry.GetPluginForFile(semantic.FileChange{Path: "a.good"}); plugin == nil || plugin.Name() != "good" {
+		t.Errorf("expected the good plugin to be registered, got %v", plugin)
+	}
+
+	if errs := Register(registry, dir); len(errs) != 3 {
+		t.Errorf("expected a name clash on re-registration, got %v", errs)
+	}
+}
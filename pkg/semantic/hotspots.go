@@ -0,0 +1,27 @@
+// Package semantic - Recent-history hotspots shared by all plugins
+package semantic
+
+// HotspotDetector reports files that changed repeatedly in recent history.
+// Implementations read history once and answer every lookup from it.
+type HotspotDetector interface {
+	// Hotspots returns, for each of the given paths that reached the hotspot
+	// threshold, the number of recent commits that touched it
+	Hotspots(paths []string) map[string]int
+	// Window is the number of recent commits considered
+	Window() int
+}
+
+// SetHotspotDetector makes recent-history hotspots available to plugins
+// through AnalysisContext.Hotspots (nil disables hotspot detection)
+func (s *SemanticAnalyzer) SetHotspotDetector(detector HotspotDetector) {
+	s.hotspots = detector
+}
+
+// HotspotCount returns how many recent commits touched path when it is a
+// hotspot, or 0 when it is not or no history is available
+func (c AnalysisContext) HotspotCount(path string) int {
+	if c.Hotspots == nil {
+		return 0
+	}
+	return c.Hotspots.Hotspots([]string{path})[path]
+}
@@ -4,7 +4,6 @@ package semantic
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 	"strings"
 )
 
@@ -29,7 +28,8 @@ type FileChange struct {
 	BeforeContent string
 	AfterContent  string
 	DiffContent   string
-	ChangeType    string // "added", "modified", "deleted"
+	ChangeType    string // "added", "modified", "deleted", "renamed"
+	OldPath       string // previous path of renamed or copied files
 }
 
 // AnalysisContext provides context for semantic analysis
@@ -39,6 +39,7 @@ type AnalysisContext struct {
 	Files       []FileChange
 	ProjectType string            // detected project type
 	Config      map[string]string // plugin-specific config
+	Hotspots    HotspotDetector   // recent-history hotspots (nil when unavailable)
 }
 
 // SemanticPlugin defines the interface for language-specific semantic analyzers
@@ -59,87 +60,11 @@ type SemanticPlugin interface {
 	ValidateConfig(config map[string]string) error
 }
 
-// PluginRegistry manages available semantic analysis plugins
-type PluginRegistry struct {
-	plugins map[string]SemanticPlugin
-}
-
-// NewPluginRegistry creates a new plugin registry
-func NewPluginRegistry() *PluginRegistry {
-	return &PluginRegistry{
-		plugins: make(map[string]SemanticPlugin),
-	}
-}
-
-// Register registers a semantic analysis plugin
-func (r *PluginRegistry) Register(plugin SemanticPlugin) error {
-	name := plugin.Name()
-	if name == "" {
-		return fmt.Errorf("plugin name cannot be empty")
-	}
-
-	if _, exists := r.plugins[name]; exists {
-		return fmt.Errorf("plugin %s already registered", name)
-	}
-
-	r.plugins[name] = plugin
-	return nil
-}
-
-// GetPlugin returns a plugin by name
-func (r *PluginRegistry) GetPlugin(name string) (SemanticPlugin, bool) {
-	plugin, exists := r.plugins[name]
-	return plugin, exists
-}
-
-// GetPluginForFile returns the most appropriate plugin for a file
-func (r *PluginRegistry) GetPluginForFile(file FileChange) SemanticPlugin {
-	// Try extension matching first
-	ext := strings.ToLower(filepath.Ext(file.Path))
-	for _, plugin := range r.plugins {
-		for _, supportedExt := range plugin.SupportedExtensions() {
-			if ext == supportedExt {
-				return plugin
-			}
-		}
-	}
-
-	// Try pattern matching
-	for _, plugin := range r.plugins {
-		for _, pattern := range plugin.SupportedFilePatterns() {
-			matched, err := filepath.Match(pattern, file.Path)
-			if err != nil {
-				continue // Skip invalid patterns
-			}
-			if matched {
-				return plugin
-			}
-		}
-	}
-
-	// Try plugin-specific analysis
-	for _, plugin := range r.plugins {
-		if plugin.CanAnalyze(file) {
-			return plugin
-		}
-	}
-
-	return nil
-}
-
-// ListPlugins returns all registered plugins
-func (r *PluginRegistry) ListPlugins() []SemanticPlugin {
-	plugins := make([]SemanticPlugin, 0, len(r.plugins))
-	for _, plugin := range r.plugins {
-		plugins = append(plugins, plugin)
-	}
-	return plugins
-}
-
 // SemanticAnalyzer orchestrates semantic analysis using plugins
 type SemanticAnalyzer struct {
 	registry *PluginRegistry
 	config   map[string]map[string]string // plugin-name -> config
+	hotspots HotspotDetector
 }
 
 // NewSemanticAnalyzer creates a new semantic analyzer
@@ -167,42 +92,90 @@ func (s *SemanticAnalyzer) SetPluginConfig(pluginName string, config map[string]
 
 // AnalyzeChanges analyzes a set of file changes using appropriate plugins
 func (s *SemanticAnalyzer) AnalyzeChanges(ctx context.Context, files []FileChange) ([]*SemanticChange, error) {
+	var changes []*SemanticChange
+	for _, result := range s.collect(ctx, files) {
+		if result.Change != nil {
+			changes = append(changes, result.Change)
+		}
+	}
+
+	return s.consolidateChanges(changes), nil
+}
+
+// AnalyzeChangeset analyzes the files and composes the results of every
+// plugin into a single multi-scope commit. It returns nil when no plugin
+// recognised any of the files.
+func (s *SemanticAnalyzer) AnalyzeChangeset(ctx context.Context, files []FileChange) (*Composition, error) {
+	changes, err := s.AnalyzeChanges(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+	return Compose(changes), nil
+}
+
+// collect runs every plugin over the files it claims, followed by
+// project-level analysis, and returns the raw per-plugin results
+func (s *SemanticAnalyzer) collect(ctx context.Context, files []FileChange) []PluginResult {
 	context := AnalysisContext{
 		Files:       files,
 		ProjectType: s.detectProjectType(files),
+		Hotspots:    s.hotspots,
 	}
 
-	var changes []*SemanticChange
-
-	// Analyze individual files
+	// Group files by the plugin that claims them, keeping first-seen order
+	var order []SemanticPlugin
+	groups := make(map[string][]FileChange)
 	for _, file := range files {
 		plugin := s.registry.GetPluginForFile(file)
 		if plugin == nil {
 			continue // Skip files without appropriate plugins
 		}
+		if _, seen := groups[plugin.Name()]; !seen {
+			order = append(order, plugin)
+		}
+		groups[plugin.Name()] = append(groups[plugin.Name()], file)
+	}
 
+	var results []PluginResult
+	for _, plugin := range order {
 		// Get plugin config
-		pluginConfig := s.config[plugin.Name()]
-		if pluginConfig == nil {
-			pluginConfig = plugin.DefaultConfig()
-		}
-		context.Config = pluginConfig
+		context.Config = s.PluginConfig(plugin.Name())
+		results = append(results, s.analyzePluginFiles(ctx, plugin, groups[plugin.Name()], context)...)
+	}
 
-		change, err := plugin.AnalyzeFile(ctx, file, context)
-		if err != nil {
-			continue // Log error but continue with other files
-		}
+	// Try project-level analysis
+	return append(results, s.analyzeProjectLevel(ctx, context)...)
+}
 
-		if change != nil {
-			changes = append(changes, change)
+// analyzePluginFiles runs one plugin over the files it claimed, preferring a
+// whole-changeset summary when the plugin provides one
+func (s *SemanticAnalyzer) analyzePluginFiles(ctx context.Context, plugin SemanticPlugin, files []FileChange, context AnalysisContext) []PluginResult {
+	if changeset, ok := plugin.(ChangesetAnalyzer); ok {
+		change, err := changeset.AnalyzeChangeset(ctx, files, context)
+		if err == nil && change != nil {
+			return []PluginResult{{Plugin: plugin.Name(), Files: paths(files), Change: change}}
 		}
 	}
 
-	// Try project-level analysis
-	projectChanges := s.analyzeProjectLevel(ctx, context)
-	changes = append(changes, projectChanges...)
+	results := make([]PluginResult, 0, len(files))
+	for _, file := range files {
+		change, err := plugin.AnalyzeFile(ctx, file, context)
+		if err == nil && change == nil {
+			continue
+		}
+		// Errors are kept for explain mode but do not stop the other files
+		results = append(results, PluginResult{Plugin: plugin.Name(), Files: []string{file.Path}, Change: change, Err: err})
+	}
+	return results
+}
 
-	return s.consolidateChanges(changes), nil
+// paths returns the paths of the given files
+func paths(files []FileChange) []string {
+	result := make([]string, 0, len(files))
+	for _, file := range files {
+		result = append(result, file.Path)
+	}
+	return result
 }
 
 // detectProjectType attempts to detect the project type from files
@@ -225,26 +198,25 @@ func (s *SemanticAnalyzer) detectProjectType(files []FileChange) string {
 }
 
 // analyzeProjectLevel performs project-level analysis using plugins
-func (s *SemanticAnalyzer) analyzeProjectLevel(ctx context.Context, context AnalysisContext) []*SemanticChange {
+func (s *SemanticAnalyzer) analyzeProjectLevel(ctx context.Context, context AnalysisContext) []PluginResult {
 	plugins := s.registry.ListPlugins()
-	changes := make([]*SemanticChange, 0, len(plugins))
+	results := make([]PluginResult, 0, len(plugins))
 
 	for _, plugin := range plugins {
-		pluginConfig := s.config[plugin.Name()]
-		if pluginConfig == nil {
-			pluginConfig = plugin.DefaultConfig()
+		if !s.registry.IsEnabled(plugin.Name()) {
+			continue
 		}
-		context.Config = pluginConfig
+		context.Config = s.PluginConfig(plugin.Name())
 
 		change, err := plugin.AnalyzeProject(ctx, context)
-		if err != nil || change == nil {
+		if err == nil && change == nil {
 			continue
 		}
 
-		changes = append(changes, change)
+		results = append(results, PluginResult{Plugin: plugin.Name(), Change: change, Err: err})
 	}
 
-	return changes
+	return results
 }
 
 // consolidateChanges merges and prioritizes semantic changes
@@ -253,16 +225,21 @@ func (s *SemanticAnalyzer) consolidateChanges(changes []*SemanticChange) []*Sema
 		return changes
 	}
 
-	// Group by type and scope
+	// Group by type and scope, keeping first-seen order
+	var order []string
 	groups := make(map[string][]*SemanticChange)
 	for _, change := range changes {
 		key := fmt.Sprintf("%s:%s", change.Type, change.Scope)
+		if _, seen := groups[key]; !seen {
+			order = append(order, key)
+		}
 		groups[key] = append(groups[key], change)
 	}
 
 	// Consolidate groups
 	var consolidated []*SemanticChange
-	for _, group := range groups {
+	for _, key := range order {
+		group := groups[key]
 		if len(group) == 1 {
 			consolidated = append(consolidated, group[0])
 		} else {
@@ -282,19 +259,18 @@ func (s *SemanticAnalyzer) mergeChanges(changes []*SemanticChange) *SemanticChan
 
 	primary := changes[0]
 
-	// Merge files
-	allFiles := make(map[string]bool)
+	// Merge files, keeping first-seen order
+	seen := make(map[string]bool)
+	var files []string
 	for _, change := range changes {
 		for _, file := range change.Files {
-			allFiles[file] = true
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
 		}
 	}
 
-	files := make([]string, 0, len(allFiles))
-	for file := range allFiles {
-		files = append(files, file)
-	}
-
 	// Calculate average confidence
 	totalConfidence := 0.0
 	for _, change := range changes {
@@ -0,0 +1,37 @@
+// Package plugins - Registration of the plugins that ship with fast-cc-git-hooks
+package plugins
+
+import (
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic/plugins/ci"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic/plugins/golang"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic/plugins/iac"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic/plugins/kubernetes"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic/plugins/migrations"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic/plugins/protobuf"
+)
+
+// Builtin returns every plugin that ships with fast-cc-git-hooks
+func Builtin() []semantic.SemanticPlugin {
+	return []semantic.SemanticPlugin{
+		NewTerraformPlugin(),
+		golang.NewGoPlugin(),
+		kubernetes.NewKubernetesPlugin(),
+		migrations.NewMigrationPlugin(),
+		ci.NewCIPlugin(),
+		protobuf.NewProtoPlugin(),
+		iac.NewCloudFormationPlugin(),
+		iac.NewCDKPlugin(),
+		iac.NewPulumiPlugin(),
+	}
+}
+
+// NewBuiltinRegistry creates a registry with all builtin plugins registered
+func NewBuiltinRegistry() *semantic.PluginRegistry {
+	registry := semantic.NewPluginRegistry()
+	for _, plugin := range Builtin() {
+		// Builtin names are unique, so registration cannot fail
+		_ = registry.Register(plugin)
+	}
+	return registry
+}
@@ -0,0 +1,58 @@
+package plugins
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+func TestBuiltinRegistry(t *testing.T) {
+	registry := NewBuiltinRegistry()
+
+	tests := []struct {
+		file semantic.FileChange
+		want string
+	}{
+		{semantic.FileChange{Path: "main.tf", AfterContent: `resource "aws_vpc" "main" {}`}, "terraform"},
+		{semantic.FileChange{Path: "pkg/a/a.go", AfterContent: "package a\n"}, "go"},
+		{semantic.FileChange{Path: "main.go", AfterContent: "import \"github.com/pulumi/pulumi/sdk/v3/go/pulumi\"\n"}, "pulumi"},
+		{semantic.FileChange{Path: ".github/workflows/ci.yml", AfterContent: "on: push\n"}, "ci"},
+		{semantic.FileChange{Path: "deploy/app.yaml", AfterContent: "apiVersion: apps/v1\nkind: Deployment\n"}, "kubernetes"},
+		{semantic.FileChange{Path: "stack.yaml", AfterContent: "Resources:\n  Db:\n    Type: AWS::RDS::DBInstance\n"}, "cloudformation"},
+		{semantic.FileChange{Path: "api/user.proto", AfterContent: "message User {}\n"}, "protobuf"},
+		{semantic.FileChange{Path: "api/openapi.yaml", AfterContent: "openapi: 3.0.0\n"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file.Path, func(t *testing.T) {
+			got := ""
+			if plugin := registry.GetPluginForFile(tt.file); plugin != nil {
+				got = plugin.Name()
+			}
+			if got != tt.want {
+				t.Errorf("GetPluginForFile(%s) = %q, want %q", tt.file.Path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuiltinComposition(t *testing.T) {
+	analyzer := semantic.NewSemanticAnalyzer(NewBuiltinRegistry())
+	composition, err := analyzer.AnalyzeChangeset(context.Background(), []semantic.FileChange{
+		{Path: "infra/dev/main.tf", ChangeType: "modified", AfterContent: "resource \"aws_s3_bucket\" \"logs\" {}\n"},
+		{Path: "infra/dev/outputs.tf", ChangeType: "modified", AfterContent: "output \"bucket\" {}\n"},
+		{Path: "db/migrations/V3__add_orders.sql", ChangeType: "added", AfterContent: "CREATE TABLE orders (id int);\n"},
+	})
+	if err != nil {
+		t.Fatalf("AnalyzeChangeset() error = %v", err)
+	}
+
+	if got := composition.Subject(); got != "feat(db): add orders table" {
+		t.Errorf("Subject() = %q", got)
+	}
+	if len(composition.Bullets) != 1 || !strings.HasPrefix(composition.Bullets[0], "infra-development: ") {
+		t.Errorf("expected one Terraform changeset bullet, got %v", composition.Bullets)
+	}
+}
@@ -0,0 +1,344 @@
+// Package ci provides semantic analysis for CI pipeline configuration
+package ci
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// CIPlugin provides semantic analysis for GitHub Actions, GitLab CI, Jenkins and similar pipelines
+type CIPlugin struct {
+	version string
+}
+
+// NewCIPlugin creates a new CI pipeline semantic analyzer plugin
+func NewCIPlugin() *CIPlugin {
+	return &CIPlugin{
+		version: "1.0.0",
+	}
+}
+
+// Name returns the plugin name
+func (c *CIPlugin) Name() string {
+	return "ci"
+}
+
+// Version returns the plugin version
+func (c *CIPlugin) Version() string {
+	return c.version
+}
+
+// SupportedExtensions returns file extensions this plugin supports.
+// YAML is shared with other plugins, so pipelines are recognised by path.
+func (c *CIPlugin) SupportedExtensions() []string {
+	return nil
+}
+
+// SupportedFilePatterns returns file patterns this plugin supports
+func (c *CIPlugin) SupportedFilePatterns() []string {
+	return []string{
+		".github/workflows/*",
+		".gitlab-ci.yml",
+		".gitlab/ci/*",
+		"Jenkinsfile",
+		".circleci/config.yml",
+		"azure-pipelines.yml",
+		"bitbucket-pipelines.yml",
+	}
+}
+
+// CanAnalyze determines if this plugin can analyze the given file
+func (c *CIPlugin) CanAnalyze(file semantic.FileChange) bool {
+	return platform(file.Path) != ""
+}
+
+// AnalyzeFile analyzes a single pipeline file for semantic changes
+func (c *CIPlugin) AnalyzeFile(_ context.Context, file semantic.FileChange, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	name := pipelineName(file)
+	change := &semantic.SemanticChange{
+		Scope: "ci",
+		Files: []string{file.Path},
+		Metadata: map[string]string{
+			"file_type": "ci",
+			"platform":  platform(file.Path),
+		},
+	}
+
+	switch file.ChangeType {
+	case "added":
+		change.Type = "feat"
+		change.Description = fmt.Sprintf("add %s workflow", name)
+		change.Intent = "Automate a new pipeline"
+		change.Impact = "New jobs run on matching events"
+		change.Confidence = 0.9
+		change.Reasoning = "New CI pipeline file"
+	case "deleted":
+		change.Type = "ci"
+		change.Scope = ""
+		change.Description = fmt.Sprintf("remove %s workflow", name)
+		change.Intent = "Retire a pipeline"
+		change.Impact = "Jobs no longer run"
+		change.Confidence = 0.9
+		change.Reasoning = "CI pipeline file deleted"
+	case "modified", "renamed":
+		c.classifyModification(file, name, change)
+	default:
+		return nil, fmt.Errorf("unknown change type: %s", file.ChangeType)
+	}
+
+	if analysisCtx.Config["flag_security"] != "false" {
+		if note := securityNote(file); note != "" {
+			change.Impact = note
+			change.Reasoning += "; " + note
+			change.Metadata["security_note"] = note
+		}
+	}
+
+	return change, nil
+}
+
+// classifyModification picks the most specific description for an edited pipeline
+func (c *CIPlugin) classifyModification(file semantic.FileChange, name string, change *semantic.SemanticChange) {
+	added := file.AfterContent
+	removed := file.BeforeContent
+
+	if cache := addedCache(removed, added); cache != "" {
+		change.Type = "perf"
+		change.Description = fmt.Sprintf("cache %s in %s workflow", cache, name)
+		change.Intent = "Speed up pipeline runs"
+		change.Impact = "Faster builds on cache hits"
+		change.Confidence = 0.85
+		change.Reasoning = "Cache configuration added"
+		return
+	}
+
+	if runner := changedValue(removed, added, runnerKeys); runner != "" {
+		change.Type = "ci"
+		change.Scope = ""
+		change.Description = fmt.Sprintf("run %s on %s", name, runner)
+		change.Intent = "Change build environment"
+		change.Impact = "Jobs run on a different runner or image"
+		change.Confidence = 0.8
+		change.Reasoning = "Runner or image changed"
+		return
+	}
+
+	if touches(removed+added, matrixKeys) {
+		change.Type = "ci"
+		change.Scope = ""
+		change.Description = fmt.Sprintf("update %s build matrix", name)
+		change.Intent = "Change tested configurations"
+		change.Impact = "Different versions or platforms are tested"
+		change.Confidence = 0.8
+		change.Reasoning = "Matrix entries changed"
+		return
+	}
+
+	if bumps := actionBumps(removed, added); len(bumps) > 0 {
+		change.Type = "ci"
+		change.Scope = "deps"
+		change.Description = strings.Join(bumps, ", ")
+		if len(bumps) > 2 {
+			change.Description = fmt.Sprintf("bump %d actions in %s workflow", len(bumps), name)
+		}
+		change.Intent = "Keep CI dependencies current"
+		change.Impact = "Pipeline uses newer action versions"
+		change.Confidence = 0.9
+		change.Reasoning = fmt.Sprintf("%d action versions changed", len(bumps))
+		return
+	}
+
+	change.Type = "ci"
+	change.Scope = ""
+	change.Description = fmt.Sprintf("update %s workflow", name)
+	change.Intent = "Pipeline maintenance"
+	change.Impact = "Pipeline behaviour changes"
+	change.Confidence = 0.6
+	change.Reasoning = "CI configuration changed"
+}
+
+// AnalyzeProject has no project-level CI analysis; per-file results are consolidated
+func (c *CIPlugin) AnalyzeProject(_ context.Context, _ semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	return nil, nil
+}
+
+// DefaultConfig returns the default configuration for the plugin
+func (c *CIPlugin) DefaultConfig() map[string]string {
+	return map[string]string{
+		"flag_security": "true",
+	}
+}
+
+// ValidateConfig validates plugin configuration
+func (c *CIPlugin) ValidateConfig(config map[string]string) error {
+	if value, exists := config["flag_security"]; exists && value != "true" && value != "false" {
+		return fmt.Errorf("flag_security must be 'true' or 'false', got %s", value)
+	}
+	return nil
+}
+
+var (
+	runnerKeys = []string{"runs-on:", "image:", "agent", "resource_class:", "vmImage:", "tags:"}
+	matrixKeys = []string{"matrix:", "go-version:", "node-version:", "python-version:", "java-version:", "os:", "parallel:"}
+
+	usesPattern   = regexp.MustCompile(`uses:\s*["']?([\w.-]+/[\w./-]+)@([\w.-]+)`)
+	secretPattern = regexp.MustCompile(`\$\{\{\s*secrets\.(\w+)|withCredentials|credentials\(|secrets:\s*inherit|\bCI_JOB_TOKEN\b`)
+	permPattern   = regexp.MustCompile(`(?m)^\s*(permissions:|id-token:|contents:\s*write|packages:\s*write|pull-requests:\s*write|actions:\s*write)`)
+)
+
+// platform returns the CI system a path belongs to, or "" if it is not a pipeline
+func platform(path string) string {
+	slashed := filepath.ToSlash(path)
+	base := filepath.Base(slashed)
+	switch {
+	case strings.Contains(slashed, ".github/workflows/") && (strings.HasSuffix(base, ".yml") || strings.HasSuffix(base, ".yaml")):
+		return "github"
+	case base == ".gitlab-ci.yml" || strings.Contains(slashed, ".gitlab/ci/"):
+		return "gitlab"
+	case base == "Jenkinsfile" || strings.HasSuffix(base, ".jenkinsfile"):
+		return "jenkins"
+	case strings.HasSuffix(slashed, ".circleci/config.yml"):
+		return "circleci"
+	case base == "azure-pipelines.yml":
+		return "azure"
+	case base == "bitbucket-pipelines.yml":
+		return "bitbucket"
+	default:
+		return ""
+	}
+}
+
+// pipelineName returns the workflow's name: key or a name derived from the path
+func pipelineName(file semantic.FileChange) string {
+	for _, content := range []string{file.AfterContent, file.BeforeContent} {
+		for _, line := range strings.Split(content, "\n") {
+			if rest, ok := strings.CutPrefix(line, "name:"); ok {
+				if name := strings.Trim(strings.TrimSpace(rest), `"'`); name != "" {
+					return name
+				}
+			}
+		}
+	}
+
+	base := filepath.Base(file.Path)
+	switch platform(file.Path) {
+	case "github":
+		return strings.TrimSuffix(strings.TrimSuffix(base, ".yml"), ".yaml")
+	case "gitlab":
+		return "GitLab CI"
+	case "jenkins":
+		return "Jenkins"
+	default:
+		return platform(file.Path)
+	}
+}
+
+// addedCache names the cache introduced by the change, or "" if none was added
+func addedCache(before, after string) string {
+	type cacheMarker struct{ marker, name string }
+	markers := []cacheMarker{
+		{"actions/cache", "dependencies"},
+		{"cache: 'npm'", "npm packages"},
+		{"cache: npm", "npm packages"},
+		{"cache: 'pip'", "pip packages"},
+		{"cache: pip", "pip packages"},
+		{"cache: true", "toolchain downloads"},
+		{"cache-dependency-path", "dependencies"},
+		{"cache:", "job artifacts"},
+		{"save_cache", "dependencies"},
+		{"cache(", "dependencies"},
+	}
+	for _, m := range markers {
+		if strings.Count(after, m.marker) > strings.Count(before, m.marker) {
+			return m.name
+		}
+	}
+	return ""
+}
+
+// changedValue returns the new value of the first key whose value changed
+func changedValue(before, after string, keys []string) string {
+	for _, key := range keys {
+		old, updated := lineValue(before, key), lineValue(after, key)
+		if updated != "" && updated != old {
+			return updated
+		}
+	}
+	return ""
+}
+
+// lineValue returns the value after the first occurrence of key in content
+func lineValue(content, key string) string {
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(trimmed, key); ok {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(rest, ":")), `"'{ `)
+		}
+	}
+	return ""
+}
+
+// touches reports whether any key appears in content
+func touches(content string, keys []string) bool {
+	for _, key := range keys {
+		if strings.Contains(content, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// actionBumps describes `uses:` references whose version changed
+func actionBumps(before, after string) []string {
+	old := make(map[string]string)
+	for _, m := range usesPattern.FindAllStringSubmatch(before, -1) {
+		old[m[1]] = m[2]
+	}
+
+	var bumps []string
+	for _, m := range usesPattern.FindAllStringSubmatch(after, -1) {
+		if previous, ok := old[m[1]]; ok && previous != m[2] {
+			bumps = append(bumps, fmt.Sprintf("bump %s from %s to %s", m[1], previous, m[2]))
+		}
+	}
+	sort.Strings(bumps)
+	return bumps
+}
+
+// securityNote describes secret or permission changes that deserve review
+func securityNote(file semantic.FileChange) string {
+	changed := file.AfterContent + file.BeforeContent
+
+	var notes []string
+	if perms := permPattern.FindAllString(changed, -1); len(perms) > 0 {
+		notes = append(notes, "token permissions changed")
+	}
+
+	secrets := make(map[string]bool)
+	for _, m := range secretPattern.FindAllStringSubmatch(changed, -1) {
+		if m[1] != "" {
+			secrets[m[1]] = true
+		} else {
+			secrets["credentials"] = true
+		}
+	}
+	if len(secrets) > 0 {
+		names := make([]string, 0, len(secrets))
+		for name := range secrets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		notes = append(notes, "secrets referenced: "+strings.Join(names, ", "))
+	}
+
+	if len(notes) == 0 {
+		return ""
+	}
+	return "security review: " + strings.Join(notes, "; ")
+}
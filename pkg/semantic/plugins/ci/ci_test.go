@@ -0,0 +1,178 @@
+package ci
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+func TestCIPlugin(t *testing.T) {
+	plugin := NewCIPlugin()
+	ctx := semantic.AnalysisContext{Config: plugin.DefaultConfig()}
+
+	t.Run("can analyze pipelines", func(t *testing.T) {
+		tests := []struct {
+			path     string
+			expected bool
+		}{
+			{".github/workflows/release.yml", true},
+			{".github/workflows/lint.yaml", true},
+			{".gitlab-ci.yml", true},
+			{"Jenkinsfile", true},
+			{".circleci/config.yml", true},
+			{".github/dependabot.yml", false},
+			{"deploy/app.yaml", false},
+		}
+		for _, tt := range tests {
+			if got := plugin.CanAnalyze(semantic.FileChange{Path: tt.path}); got != tt.expected {
+				t.Errorf("CanAnalyze(%s) = %v, expected %v", tt.path, got, tt.expected)
+			}
+		}
+	})
+
+	tests := []struct {
+		name      string
+		file      semantic.FileChange
+		wantType  string
+		wantScope string
+		wantDesc  string
+		wantNote  bool
+	}{
+		{
+			name: "new workflow",
+			file: semantic.FileChange{
+				Path:         ".github/workflows/release.yml",
+				ChangeType:   "added",
+				AfterContent: "name: Release\non:\n  push:\n    tags: ['v*']\n",
+			},
+			wantType:  "feat",
+			wantScope: "ci",
+			wantDesc:  "add Release workflow",
+		},
+		{
+			name: "cache added",
+			file: semantic.FileChange{
+				Path:          ".github/workflows/test.yml",
+				ChangeType:    "modified",
+				BeforeContent: "",
+				AfterContent:  "      - uses: actions/cache@v4\n        with:\n          path: ~/go/pkg/mod\n",
+			},
+			wantType:  "perf",
+			wantScope: "ci",
+			wantDesc:  "cache dependencies in test workflow",
+		},
+		{
+			name: "runner change",
+			file: semantic.FileChange{
+				Path:          ".github/workflows/test.yml",
+				ChangeType:    "modified",
+				BeforeContent: "    runs-on: ubuntu-20.04\n",
+				AfterContent:  "    runs-on: ubuntu-24.04\n",
+			},
+			wantType: "ci",
+			wantDesc: "run test on ubuntu-24.04",
+		},
+		{
+			name: "matrix change",
+			file: semantic.FileChange{
+				Path:          ".github/workflows/test.yml",
+				ChangeType:    "modified",
+				BeforeContent: "        go-version: ['1.22', '1.23']\n",
+				AfterContent:  "        go-version: ['1.23', '1.24']\n",
+			},
+			wantType: "ci",
+			wantDesc: "update test build matrix",
+		},
+		{
+			name: "action bump",
+			file: semantic.FileChange{
+				Path:          ".github/workflows/test.yml",
+				ChangeType:    "modified",
+				BeforeContent: "      - uses: actions/checkout@v3\n",
+				AfterContent:  "      - uses: actions/checkout@v4\n",
+			},
+			wantType:  "ci",
+			wantScope: "deps",
+			wantDesc:  "bump actions/checkout from v3 to v4",
+		},
+		{
+			name: "permissions flagged",
+			file: semantic.FileChange{
+				Path:         ".github/workflows/release.yml",
+				ChangeType:   "modified",
+				AfterContent: "permissions:\n  contents: write\n        env:\n          TOKEN: ${{ secrets.RELEASE_TOKEN }}\n",
+			},
+			wantType: "ci",
+			wantDesc: "update release workflow",
+			wantNote: true,
+		},
+		{
+			name: "jenkins credentials flagged",
+			file: semantic.FileChange{
+				Path:         "Jenkinsfile",
+				ChangeType:   "modified",
+				AfterContent: "    withCredentials([string(credentialsId: 'npm', variable: 'NPM_TOKEN')]) {\n",
+			},
+			wantType: "ci",
+			wantDesc: "update Jenkins workflow",
+			wantNote: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := plugin.AnalyzeFile(context.Background(), tt.file, ctx)
+			if err != nil {
+				t.Fatalf("AnalyzeFile() error = %v", err)
+			}
+			if change.Type != tt.wantType {
+				t.Errorf("Type = %s, expected %s", change.Type, tt.wantType)
+			}
+			if change.Scope != tt.wantScope {
+				t.Errorf("Scope = %s, expected %s", change.Scope, tt.wantScope)
+			}
+			if change.Description != tt.wantDesc {
+				t.Errorf("Description = %q, expected %q", change.Description, tt.wantDesc)
+			}
+			if _, ok := change.Metadata["security_note"]; ok != tt.wantNote {
+				t.Errorf("security note present = %v, expected %v (%s)", ok, tt.wantNote, change.Impact)
+			}
+		})
+	}
+
+	t.Run("security note names secrets", func(t *testing.T) {
+		file := semantic.FileChange{
+			Path:         ".gitlab-ci.yml",
+			ChangeType:   "modified",
+			AfterContent: "  script: curl -H \"$TOKEN\" ${{ secrets.DEPLOY_KEY }}\n",
+		}
+		change, err := plugin.AnalyzeFile(context.Background(), file, ctx)
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+		if !strings.Contains(change.Metadata["security_note"], "DEPLOY_KEY") {
+			t.Errorf("security note = %q, expected it to name DEPLOY_KEY", change.Metadata["security_note"])
+		}
+	})
+
+	t.Run("security flagging can be disabled", func(t *testing.T) {
+		file := semantic.FileChange{Path: ".gitlab-ci.yml", ChangeType: "modified", AfterContent: "permissions:\n"}
+		change, err := plugin.AnalyzeFile(context.Background(), file, semantic.AnalysisContext{
+			Config: map[string]string{"flag_security": "false"},
+		})
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+		if _, ok := change.Metadata["security_note"]; ok {
+			t.Errorf("expected no security note, got %q", change.Metadata["security_note"])
+		}
+	})
+
+	t.Run("unknown change type", func(t *testing.T) {
+		if _, err := plugin.AnalyzeFile(context.Background(), semantic.FileChange{Path: "Jenkinsfile", ChangeType: "bogus"}, ctx); err == nil {
+			t.Error("expected error for unknown change type")
+		}
+	})
+}
@@ -0,0 +1,315 @@
+// Package golang - Declaration extraction and exported API comparison
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// Kinds of the members of struct and interface types, recorded as Type.Name
+const (
+	kindField           = "field"
+	kindInterfaceMethod = "interface method"
+)
+
+// decl is a top-level declaration found in Go source, or a member of a
+// struct or interface type declared in it
+type decl struct {
+	Name      string // Recv.Method for methods, Type.Name for members
+	Kind      string // func, method, type, const, var, or a member kind
+	Signature string
+	Exported  bool
+}
+
+// apiDiff describes exported API differences between two versions of a file
+type apiDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// String summarizes the diff for SemanticChange.Reasoning
+func (d apiDiff) String() string {
+	return fmt.Sprintf("exported API: %d added, %d removed, %d changed", len(d.Added), len(d.Removed), len(d.Changed))
+}
+
+// parseDecls extracts top-level declarations from content. Complete files are
+// parsed as a whole; diff fragments fall back to parsing each declaration line.
+func parseDecls(content string) map[string]decl {
+	decls := make(map[string]decl)
+	if strings.TrimSpace(content) == "" {
+		return decls
+	}
+
+	fset := token.NewFileSet()
+	if file, err := parser.ParseFile(fset, "", content, parser.SkipObjectResolution); err == nil {
+		collectDecls(file, decls)
+		return decls
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		fragment, ok := declFragment(line)
+		if !ok {
+			continue
+		}
+		file, err := parser.ParseFile(fset, "", "package p\n"+fragment, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		collectDecls(file, decls)
+	}
+
+	return decls
+}
+
+// declFragment turns a single declaration line into a parseable declaration
+func declFragment(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(trimmed, "func "):
+		// Drop an opening body brace and give the function an empty body
+		return strings.TrimSpace(strings.TrimSuffix(trimmed, "{")) + " {}", true
+	case strings.HasPrefix(trimmed, "type "):
+		if strings.HasSuffix(trimmed, "{") {
+			return trimmed + "}", true
+		}
+		return trimmed, true
+	default:
+		return "", false
+	}
+}
+
+// collectDecls records the top-level declarations of file
+func collectDecls(file *ast.File, decls map[string]decl) {
+	for _, d := range file.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			kind := "func"
+			exported := ast.IsExported(name)
+			signature := types.ExprString(d.Type)
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				recv := receiverName(d.Recv.List[0].Type)
+				name = recv + "." + name
+				kind = "method"
+				exported = exported && ast.IsExported(recv)
+				signature = "(" + types.ExprString(d.Recv.List[0].Type) + ") " + signature
+			}
+			decls[name] = decl{Name: name, Kind: kind, Signature: signature, Exported: exported}
+
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					decls[s.Name.Name] = decl{
+						Name:      s.Name.Name,
+						Kind:      "type",
+						Signature: typeSignature(s),
+						Exported:  ast.IsExported(s.Name.Name),
+					}
+					collectMembers(s, decls)
+				case *ast.ValueSpec:
+					kind := strings.ToLower(d.Tok.String())
+					for _, ident := range s.Names {
+						signature := ""
+						if s.Type != nil {
+							signature = types.ExprString(s.Type)
+						}
+						decls[ident.Name] = decl{Name: ident.Name, Kind: kind, Signature: signature, Exported: ast.IsExported(ident.Name)}
+					}
+				}
+			}
+		}
+	}
+}
+
+// collectMembers records the fields of a struct type and the methods of an
+// interface type, so their removal or change is compared like that of
+// top-level declarations. Embedded types are named after the type.
+func collectMembers(spec *ast.TypeSpec, decls map[string]decl) {
+	var fields *ast.FieldList
+	kind := kindField
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		fields = t.Fields
+	case *ast.InterfaceType:
+		fields, kind = t.Methods, kindInterfaceMethod
+	}
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		names := make([]string, 0, len(field.Names))
+		for _, ident := range field.Names {
+			names = append(names, ident.Name)
+		}
+		if len(names) == 0 {
+			names = append(names, receiverName(field.Type))
+		}
+		for _, name := range names {
+			member := spec.Name.Name + "." + name
+			decls[member] = decl{
+				Name:      member,
+				Kind:      kind,
+				Signature: types.ExprString(field.Type),
+				Exported:  ast.IsExported(spec.Name.Name) && ast.IsExported(name),
+			}
+		}
+	}
+}
+
+// isMember reports whether a declaration is a struct field or interface
+// method rather than a top-level declaration
+func isMember(d decl) bool {
+	return d.Kind == kindField || d.Kind == kindInterfaceMethod
+}
+
+// typeSignature describes a type declaration. Struct and interface bodies are
+// not compared because diff fragments rarely contain them in full.
+func typeSignature(spec *ast.TypeSpec) string {
+	switch spec.Type.(type) {
+	case *ast.StructType:
+		return "struct"
+	case *ast.InterfaceType:
+		return "interface"
+	default:
+		return types.ExprString(spec.Type)
+	}
+}
+
+// receiverName returns the base type name of a method receiver
+func receiverName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(e.X)
+	case *ast.IndexExpr:
+		return receiverName(e.X)
+	case *ast.IndexListExpr:
+		return receiverName(e.X)
+	case *ast.Ident:
+		return e.Name
+	default:
+		return types.ExprString(expr)
+	}
+}
+
+// compareAPI compares exported declarations before and after a change, as
+// apidiff does: removing or changing a declaration or member is
+// incompatible, and so is adding a method to an existing interface, which
+// its implementations then lack.
+func compareAPI(before, after map[string]decl) apiDiff {
+	var diff apiDiff
+
+	for name, a := range after {
+		if !a.Exported {
+			continue
+		}
+		b, existed := before[name]
+		switch {
+		case !existed && a.Kind == kindInterfaceMethod && interfaceExisted(before, name):
+			diff.Changed = append(diff.Changed, name)
+		case !existed:
+			diff.Added = append(diff.Added, name)
+		case b.Kind != a.Kind || b.Signature != a.Signature:
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+
+	for name, b := range before {
+		if _, exists := after[name]; b.Exported && !exists {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
+
+// interfaceExisted reports whether the interface of member is declared in
+// before
+func interfaceExisted(before map[string]decl, member string) bool {
+	iface, _, _ := strings.Cut(member, ".")
+	return before[iface].Signature == "interface"
+}
+
+// InternalPackage reports whether a path is inside an internal directory,
+// which other modules cannot import
+func InternalPackage(filename string) bool {
+	for _, segment := range strings.Split(path.Dir(filename), "/") {
+		if segment == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
+// PackageName returns the package name of Go source
+func PackageName(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "package" {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// publicAPI reports whether the exported identifiers of a file, as it was
+// before the change, can be used by other modules: main and internal
+// packages cannot be imported, so changing their API breaks no one
+func publicAPI(file semantic.FileChange) bool {
+	filename := file.Path
+	if file.OldPath != "" {
+		filename = file.OldPath
+	}
+	return !InternalPackage(filename) && PackageName(file.BeforeContent) != "main"
+}
+
+// exportedNames returns the sorted names of exported top-level declarations
+func exportedNames(decls map[string]decl) []string {
+	var names []string
+	for name, d := range decls {
+		if d.Exported && !isMember(d) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// testNames returns the sorted names of Test, Benchmark, Fuzz and Example functions
+func testNames(decls map[string]decl) []string {
+	var names []string
+	for name, d := range decls {
+		if d.Kind != "func" {
+			continue
+		}
+		for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// packageName returns the package clause name from content, if present
+func packageName(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "package" {
+			return fields[1]
+		}
+	}
+	return ""
+}
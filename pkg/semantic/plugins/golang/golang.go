@@ -0,0 +1,356 @@
+// Package golang provides semantic analysis for Go source files and go.mod
+package golang
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// GoPlugin provides semantic analysis for Go code
+type GoPlugin struct {
+	version string
+}
+
+// NewGoPlugin creates a new Go semantic analyzer plugin
+func NewGoPlugin() *GoPlugin {
+	return &GoPlugin{
+		version: "1.0.0",
+	}
+}
+
+// Name returns the plugin name
+func (p *GoPlugin) Name() string {
+	return "go"
+}
+
+// Version returns the plugin version
+func (p *GoPlugin) Version() string {
+	return p.version
+}
+
+// SupportedExtensions returns file extensions this plugin supports
+func (p *GoPlugin) SupportedExtensions() []string {
+	return []string{".go"}
+}
+
+// SupportedFilePatterns returns file patterns this plugin supports
+func (p *GoPlugin) SupportedFilePatterns() []string {
+	return []string{"*.go", "go.mod"}
+}
+
+// CanAnalyze determines if this plugin can analyze the given file
+func (p *GoPlugin) CanAnalyze(file semantic.FileChange) bool {
+	return strings.HasSuffix(file.Path, ".go") || filepath.Base(file.Path) == "go.mod"
+}
+
+// AnalyzeFile analyzes a single Go source file or go.mod for semantic changes
+func (p *GoPlugin) AnalyzeFile(_ context.Context, file semantic.FileChange, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	switch {
+	case filepath.Base(file.Path) == "go.mod":
+		return p.analyzeGoMod(file), nil
+	case isTestFile(file.Path):
+		return p.analyzeTestFile(file), nil
+	}
+
+	switch file.ChangeType {
+	case "added":
+		return p.analyzeNewFile(file), nil
+	case "deleted":
+		return p.analyzeDeletedFile(file), nil
+	case "modified":
+		return p.analyzeModifiedFile(file, analysisCtx), nil
+	case "renamed":
+		return p.analyzeRenamedFile(file), nil
+	default:
+		return nil, fmt.Errorf("unknown change type: %s", file.ChangeType)
+	}
+}
+
+// AnalyzeProject reports changesets that only touch Go tests as a single test change
+func (p *GoPlugin) AnalyzeProject(_ context.Context, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	var goFiles []string
+	for _, file := range analysisCtx.Files {
+		if !strings.HasSuffix(file.Path, ".go") {
+			continue
+		}
+		if !isTestFile(file.Path) {
+			return nil, nil
+		}
+		goFiles = append(goFiles, file.Path)
+	}
+
+	if len(goFiles) < 2 {
+		return nil, nil
+	}
+
+	return &semantic.SemanticChange{
+		Type:        "test",
+		Scope:       commonScope(goFiles),
+		Description: fmt.Sprintf("update tests in %d files", len(goFiles)),
+		Intent:      "Improve test coverage",
+		Impact:      "No production code changes",
+		Files:       goFiles,
+		Confidence:  0.9,
+		Reasoning:   "All changed Go files are _test.go files",
+		Metadata:    map[string]string{"language": "go"},
+	}, nil
+}
+
+// DefaultConfig returns the default configuration for the plugin
+func (p *GoPlugin) DefaultConfig() map[string]string {
+	return map[string]string{
+		"detect_breaking_changes": "true",
+		"detect_error_handling":   "true",
+	}
+}
+
+// ValidateConfig validates plugin configuration
+func (p *GoPlugin) ValidateConfig(config map[string]string) error {
+	for _, key := range []string{"detect_breaking_changes", "detect_error_handling"} {
+		if value, exists := config[key]; exists && value != "true" && value != "false" {
+			return fmt.Errorf("%s must be 'true' or 'false', got %s", key, value)
+		}
+	}
+	return nil
+}
+
+// analyzeNewFile analyzes a newly added Go file
+func (p *GoPlugin) analyzeNewFile(file semantic.FileChange) *semantic.SemanticChange {
+	decls := parseDecls(file.AfterContent)
+	exported := exportedNames(decls)
+	scope := packageScope(file)
+
+	description := fmt.Sprintf("add %s", filepath.Base(file.Path))
+	if len(exported) > 0 {
+		description = fmt.Sprintf("add %s", summarizeNames(exported))
+	}
+
+	return &semantic.SemanticChange{
+		Type:        "feat",
+		Scope:       scope,
+		Description: description,
+		Intent:      "Introduce new functionality",
+		Impact:      fmt.Sprintf("%d new exported identifiers", len(exported)),
+		Files:       []string{file.Path},
+		Confidence:  0.85,
+		Reasoning:   fmt.Sprintf("New Go file declaring %d exported identifiers", len(exported)),
+		Metadata: map[string]string{
+			"language": "go",
+			"exported": strings.Join(exported, ","),
+		},
+	}
+}
+
+// analyzeDeletedFile analyzes a deleted Go file
+func (p *GoPlugin) analyzeDeletedFile(file semantic.FileChange) *semantic.SemanticChange {
+	exported := exportedNames(parseDecls(file.BeforeContent))
+
+	return &semantic.SemanticChange{
+		Type:           "refactor",
+		Scope:          packageScope(file),
+		Description:    fmt.Sprintf("remove %s", filepath.Base(file.Path)),
+		Intent:         "Code cleanup",
+		Impact:         fmt.Sprintf("%d exported identifiers removed", len(exported)),
+		BreakingChange: len(exported) > 0 && publicAPI(file),
+		Files:          []string{file.Path},
+		Confidence:     0.85,
+		Reasoning:      fmt.Sprintf("Go file deleted with %d exported identifiers", len(exported)),
+		Metadata: map[string]string{
+			"language": "go",
+			"removed":  strings.Join(exported, ","),
+		},
+	}
+}
+
+// analyzeRenamedFile analyzes a renamed or moved Go file
+func (p *GoPlugin) analyzeRenamedFile(file semantic.FileChange) *semantic.SemanticChange {
+	verb := "rename"
+	breaking := false
+	if filepath.Dir(file.OldPath) != filepath.Dir(file.Path) {
+		verb = "move"
+		// Moving a file with exported identifiers to another package changes import paths
+		breaking = len(exportedNames(parseDecls(file.AfterContent))) > 0 && publicAPI(file)
+	}
+
+	return &semantic.SemanticChange{
+		Type:           "refactor",
+		Scope:          packageScope(file),
+		Description:    fmt.Sprintf("%s %s to %s", verb, file.OldPath, file.Path),
+		Intent:         "Code organization",
+		Impact:         "File location changed",
+		BreakingChange: breaking,
+		Files:          []string{file.OldPath, file.Path},
+		Confidence:     0.9,
+		Reasoning:      fmt.Sprintf("git detected %s as a rename of %s", file.Path, file.OldPath),
+		Metadata: map[string]string{
+			"language": "go",
+			"old_path": file.OldPath,
+		},
+	}
+}
+
+// analyzeTestFile analyzes a change to a _test.go file
+func (p *GoPlugin) analyzeTestFile(file semantic.FileChange) *semantic.SemanticChange {
+	tests := testNames(parseDecls(file.AfterContent))
+
+	description := fmt.Sprintf("update %s", filepath.Base(file.Path))
+	switch {
+	case file.ChangeType == "added":
+		description = fmt.Sprintf("add %s", filepath.Base(file.Path))
+	case len(tests) > 0:
+		description = fmt.Sprintf("add %s", summarizeNames(tests))
+	}
+
+	return &semantic.SemanticChange{
+		Type:        "test",
+		Scope:       packageScope(file),
+		Description: description,
+		Intent:      "Improve test coverage",
+		Impact:      "No production code changes",
+		Files:       []string{file.Path},
+		Confidence:  0.95,
+		Reasoning:   "Change is limited to a Go test file",
+		Metadata:    map[string]string{"language": "go"},
+	}
+}
+
+// analyzeModifiedFile compares declarations and error handling before and after
+func (p *GoPlugin) analyzeModifiedFile(file semantic.FileChange, analysisCtx semantic.AnalysisContext) *semantic.SemanticChange {
+	before := parseDecls(file.BeforeContent)
+	after := parseDecls(file.AfterContent)
+	api := compareAPI(before, after)
+	scope := packageScope(file)
+
+	metadata := map[string]string{"language": "go"}
+	if len(api.Added) > 0 {
+		metadata["added"] = strings.Join(api.Added, ",")
+	}
+	if len(api.Removed) > 0 {
+		metadata["removed"] = strings.Join(api.Removed, ",")
+	}
+	if len(api.Changed) > 0 {
+		metadata["changed"] = strings.Join(api.Changed, ",")
+	}
+
+	detectBreaking := analysisCtx.Config["detect_breaking_changes"] != "false" && publicAPI(file)
+	if detectBreaking && (len(api.Changed) > 0 || len(api.Removed) > 0) {
+		description := fmt.Sprintf("change signature of %s", summarizeNames(api.Changed))
+		if len(api.Changed) == 0 {
+			description = fmt.Sprintf("remove %s", summarizeNames(api.Removed))
+		}
+		return &semantic.SemanticChange{
+			Type:           "feat",
+			Scope:          scope,
+			Description:    description,
+			Intent:         "Evolve the public API",
+			Impact:         "Callers of the changed API must be updated",
+			BreakingChange: true,
+			Files:          []string{file.Path},
+			Confidence:     0.85,
+			Reasoning:      api.String(),
+			Metadata:       metadata,
+		}
+	}
+
+	if len(api.Added) > 0 {
+		return &semantic.SemanticChange{
+			Type:        "feat",
+			Scope:       scope,
+			Description: fmt.Sprintf("add %s", summarizeNames(api.Added)),
+			Intent:      "Extend the public API",
+			Impact:      "New exported API available to callers",
+			Files:       []string{file.Path},
+			Confidence:  0.8,
+			Reasoning:   api.String(),
+			Metadata:    metadata,
+		}
+	}
+
+	detectErrors := analysisCtx.Config["detect_error_handling"] != "false"
+	if detectErrors && errorHandlingScore(file.AfterContent) > errorHandlingScore(file.BeforeContent) {
+		return &semantic.SemanticChange{
+			Type:        "fix",
+			Scope:       scope,
+			Description: fmt.Sprintf("handle errors in %s", strings.TrimSuffix(filepath.Base(file.Path), ".go")),
+			Intent:      "Improve error handling",
+			Impact:      "Failures are detected and reported instead of ignored",
+			Files:       []string{file.Path},
+			Confidence:  0.7,
+			Reasoning:   "Added error checks or wrapped errors",
+			Metadata:    metadata,
+		}
+	}
+
+	return &semantic.SemanticChange{
+		Type:        "refactor",
+		Scope:       scope,
+		Description: fmt.Sprintf("update %s internals", strings.TrimSuffix(filepath.Base(file.Path), ".go")),
+		Intent:      "Internal implementation change",
+		Impact:      "No exported API changes",
+		Files:       []string{file.Path},
+		Confidence:  0.5,
+		Reasoning:   "No exported declarations changed",
+		Metadata:    metadata,
+	}
+}
+
+// isTestFile reports whether path is a Go test file
+func isTestFile(path string) bool {
+	return strings.HasSuffix(path, "_test.go")
+}
+
+// packageScope derives the scope from the package clause or the file's directory
+func packageScope(file semantic.FileChange) string {
+	for _, content := range []string{file.AfterContent, file.BeforeContent} {
+		if name := packageName(content); name != "" && name != "main" {
+			return strings.TrimSuffix(name, "_test")
+		}
+	}
+
+	dir := filepath.Base(filepath.Dir(file.Path))
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return dir
+}
+
+// commonScope returns the shared directory scope of files, or "" if they differ
+func commonScope(files []string) string {
+	scope := ""
+	for i, file := range files {
+		dir := filepath.Base(filepath.Dir(file))
+		if dir == "." {
+			dir = ""
+		}
+		if i == 0 {
+			scope = dir
+		} else if dir != scope {
+			return ""
+		}
+	}
+	return scope
+}
+
+// summarizeNames renders up to three names, collapsing the rest into a count
+func summarizeNames(names []string) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	if len(sorted) <= 3 {
+		return strings.Join(sorted, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(sorted[:2], ", "), len(sorted)-2)
+}
+
+// errorHandlingScore counts common Go error handling constructs in content
+func errorHandlingScore(content string) int {
+	score := 0
+	for _, pattern := range []string{"if err != nil", "errors.Is(", "errors.As(", "%w", "errors.New(", "errors.Join("} {
+		score += strings.Count(content, pattern)
+	}
+	return score
+}
@@ -0,0 +1,244 @@
+package golang
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+func TestGoPlugin(t *testing.T) {
+	plugin := NewGoPlugin()
+	ctx := semantic.AnalysisContext{Config: plugin.DefaultConfig()}
+
+	t.Run("can analyze go files", func(t *testing.T) {
+		tests := []struct {
+			path     string
+			expected bool
+		}{
+			{"pkg/ccgen/generator.go", true},
+			{"go.mod", true},
+			{"tools/go.mod", true},
+			{"go.sum", false},
+			{"main.tf", false},
+		}
+		for _, tt := range tests {
+			if got := plugin.CanAnalyze(semantic.FileChange{Path: tt.path}); got != tt.expected {
+				t.Errorf("CanAnalyze(%s) = %v, expected %v", tt.path, got, tt.expected)
+			}
+		}
+	})
+
+	tests := []struct {
+		name         string
+		file         semantic.FileChange
+		wantType     string
+		wantBreaking bool
+		wantScope    string
+		wantDesc     string
+	}{
+		{
+			name: "new file with exported API",
+			file: semantic.FileChange{
+				Path:         "pkg/jira/client.go",
+				ChangeType:   "added",
+				AfterContent: "package jira\n\ntype Client struct{}\n\nfunc NewClient() *Client { return nil }\n\nfunc helper() {}\n",
+			},
+			wantType:  "feat",
+			wantScope: "jira",
+			wantDesc:  "add Client, NewClient",
+		},
+		{
+			name: "signature change is breaking",
+			file: semantic.FileChange{
+				Path:          "pkg/jira/manager.go",
+				ChangeType:    "modified",
+				BeforeContent: "func (m *Manager) SetJiraTicket(id string) error {\n",
+				AfterContent:  "func (m *Manager) SetJiraTicket(ctx context.Context, id string) error {\n",
+			},
+			wantType:     "feat",
+			wantBreaking: true,
+			wantScope:    "jira",
+			wantDesc:     "change signature of Manager.SetJiraTicket",
+		},
+		{
+			name: "removed struct field is breaking",
+			file: semantic.FileChange{
+				Path:          "pkg/jira/client.go",
+				ChangeType:    "modified",
+				BeforeContent: "package jira\n\ntype Client struct {\n\tURL   string\n\tToken string\n}\n",
+				AfterContent:  "package jira\n\ntype Client struct {\n\tURL string\n}\n",
+			},
+			wantType:     "feat",
+			wantBreaking: true,
+			wantScope:    "jira",
+			wantDesc:     "remove Client.Token",
+		},
+		{
+			name: "removed function of an internal package is compatible",
+			file: semantic.FileChange{
+				Path:          "internal/hooks/diff.go",
+				ChangeType:    "modified",
+				BeforeContent: "package hooks\n\nfunc LineDiff() {}\n\nfunc helper() {}\n",
+				AfterContent:  "package hooks\n\nfunc helper() {}\n",
+			},
+			wantType:  "refactor",
+			wantScope: "hooks",
+			wantDesc:  "update diff internals",
+		},
+		{
+			name: "removed function of package main is compatible",
+			file: semantic.FileChange{
+				Path:          "cmd/fcgh/render.go",
+				ChangeType:    "modified",
+				BeforeContent: "package main\n\nfunc Render() {}\n",
+				AfterContent:  "package main\n",
+			},
+			wantType:  "refactor",
+			wantScope: "fcgh",
+			wantDesc:  "update render internals",
+		},
+		{
+			name: "deleted file of an internal package is compatible",
+			file: semantic.FileChange{
+				Path:          "internal/hooks/diff.go",
+				ChangeType:    "deleted",
+				BeforeContent: "package hooks\n\nfunc LineDiff() {}\n",
+			},
+			wantType:  "refactor",
+			wantScope: "hooks",
+			wantDesc:  "remove diff.go",
+		},
+		{
+			name: "added struct field is compatible",
+			file: semantic.FileChange{
+				Path:          "pkg/jira/client.go",
+				ChangeType:    "modified",
+				BeforeContent: "package jira\n\ntype Client struct {\n\tURL string\n}\n",
+				AfterContent:  "package jira\n\ntype Client struct {\n\tURL     string\n\tTimeout int\n\tretries int\n}\n",
+			},
+			wantType:  "feat",
+			wantScope: "jira",
+			wantDesc:  "add Client.Timeout",
+		},
+		{
+			name: "method added to an interface is breaking",
+			file: semantic.FileChange{
+				Path:          "pkg/jira/store.go",
+				ChangeType:    "modified",
+				BeforeContent: "package jira\n\ntype Store interface {\n\tGet(key string) string\n}\n",
+				AfterContent:  "package jira\n\ntype Store interface {\n\tGet(key string) string\n\tClose() error\n}\n",
+			},
+			wantType:     "feat",
+			wantBreaking: true,
+			wantScope:    "jira",
+			wantDesc:     "change signature of Store.Close",
+		},
+		{
+			name: "exported addition in modified file",
+			file: semantic.FileChange{
+				Path:         "internal/config/config.go",
+				ChangeType:   "modified",
+				AfterContent: "func LoadFromEnv() (*Config, error) {\n\treturn nil, nil\n",
+			},
+			wantType:  "feat",
+			wantScope: "config",
+			wantDesc:  "add LoadFromEnv",
+		},
+		{
+			name: "unexported change is not API",
+			file: semantic.FileChange{
+				Path:          "internal/config/config.go",
+				ChangeType:    "modified",
+				BeforeContent: "func merge(a, b int) int {\n",
+				AfterContent:  "func merge(a, b, c int) int {\n",
+			},
+			wantType:  "refactor",
+			wantScope: "config",
+		},
+		{
+			name: "error handling fix",
+			file: semantic.FileChange{
+				Path:          "internal/hooks/installer.go",
+				ChangeType:    "modified",
+				BeforeContent: "\t_ = os.Remove(path)\n",
+				AfterContent:  "\tif err := os.Remove(path); err != nil {\n\t\treturn fmt.Errorf(\"removing hook: %w\", err)\n",
+			},
+			wantType:  "fix",
+			wantScope: "hooks",
+			wantDesc:  "handle errors in installer",
+		},
+		{
+			name: "test only change",
+			file: semantic.FileChange{
+				Path:         "pkg/ccgen/generator_test.go",
+				ChangeType:   "modified",
+				AfterContent: "func TestGenerateStagedOnly(t *testing.T) {\n",
+			},
+			wantType:  "test",
+			wantScope: "ccgen",
+			wantDesc:  "add TestGenerateStagedOnly",
+		},
+		{
+			name: "dependency bump",
+			file: semantic.FileChange{
+				Path:          "go.mod",
+				ChangeType:    "modified",
+				BeforeContent: "\tgopkg.in/yaml.v3 v3.0.0\n",
+				AfterContent:  "\tgopkg.in/yaml.v3 v3.0.1\n",
+			},
+			wantType:  "build",
+			wantScope: "deps",
+			wantDesc:  "bump gopkg.in/yaml.v3 from v3.0.0 to v3.0.1",
+		},
+		{
+			name: "go directive change",
+			file: semantic.FileChange{
+				Path:          "go.mod",
+				ChangeType:    "modified",
+				BeforeContent: "go 1.24\n",
+				AfterContent:  "go 1.25\n",
+			},
+			wantType:  "build",
+			wantScope: "go",
+			wantDesc:  "require Go 1.25",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := plugin.AnalyzeFile(context.Background(), tt.file, ctx)
+			if err != nil {
+				t.Fatalf("AnalyzeFile() error = %v", err)
+			}
+			if change.Type != tt.wantType {
+				t.Errorf("Type = %s, expected %s", change.Type, tt.wantType)
+			}
+			if change.BreakingChange != tt.wantBreaking {
+				t.Errorf("BreakingChange = %v, expected %v", change.BreakingChange, tt.wantBreaking)
+			}
+			if change.Scope != tt.wantScope {
+				t.Errorf("Scope = %q, expected %q", change.Scope, tt.wantScope)
+			}
+			if tt.wantDesc != "" && change.Description != tt.wantDesc {
+				t.Errorf("Description = %q, expected %q", change.Description, tt.wantDesc)
+			}
+		})
+	}
+
+	t.Run("test only changeset", func(t *testing.T) {
+		analysisCtx := semantic.AnalysisContext{Files: []semantic.FileChange{
+			{Path: "pkg/a/a_test.go"},
+			{Path: "pkg/a/b_test.go"},
+			{Path: "README.md"},
+		}}
+		change, err := plugin.AnalyzeProject(context.Background(), analysisCtx)
+		if err != nil {
+			t.Fatalf("AnalyzeProject() error = %v", err)
+		}
+		if change == nil || change.Type != "test" || !strings.Contains(change.Description, "2 files") {
+			t.Errorf("expected test changeset, got %+v", change)
+		}
+	})
+}
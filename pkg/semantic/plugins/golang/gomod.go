@@ -0,0 +1,123 @@
+// Package golang - go.mod dependency change analysis
+package golang
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// moduleDeps holds the require entries and go directive of a go.mod fragment
+type moduleDeps struct {
+	goVersion string
+	requires  map[string]string
+}
+
+// parseGoMod extracts require entries and the go directive from go.mod content.
+// It accepts diff fragments, so require blocks may be missing their delimiters.
+func parseGoMod(content string) moduleDeps {
+	deps := moduleDeps{requires: make(map[string]string)}
+
+	for _, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "go":
+			if len(fields) >= 2 {
+				deps.goVersion = fields[1]
+			}
+			continue
+		case "require":
+			fields = fields[1:]
+		case "module", "toolchain", "replace", "exclude", "retract", "tool", "godebug", "(", ")":
+			continue
+		}
+
+		if len(fields) >= 2 && strings.HasPrefix(fields[1], "v") {
+			deps.requires[fields[0]] = fields[1]
+		}
+	}
+
+	return deps
+}
+
+// analyzeGoMod reports dependency and Go version changes in go.mod
+func (p *GoPlugin) analyzeGoMod(file semantic.FileChange) *semantic.SemanticChange {
+	before := parseGoMod(file.BeforeContent)
+	after := parseGoMod(file.AfterContent)
+
+	var bumped, added, removed []string
+	for module, version := range after.requires {
+		old, existed := before.requires[module]
+		switch {
+		case !existed:
+			added = append(added, module)
+		case old != version:
+			bumped = append(bumped, module)
+		}
+	}
+	for module := range before.requires {
+		if _, exists := after.requires[module]; !exists {
+			removed = append(removed, module)
+		}
+	}
+	sort.Strings(bumped)
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	var description string
+	switch {
+	case len(bumped) == 1 && len(added) == 0 && len(removed) == 0:
+		module := bumped[0]
+		description = fmt.Sprintf("bump %s from %s to %s", module, before.requires[module], after.requires[module])
+	case len(bumped) > 0:
+		description = fmt.Sprintf("bump %d dependencies", len(bumped))
+	case len(added) == 1 && len(removed) == 0:
+		description = fmt.Sprintf("add %s %s", added[0], after.requires[added[0]])
+	case len(added) > 0:
+		description = fmt.Sprintf("add %d dependencies", len(added))
+	case len(removed) == 1:
+		description = fmt.Sprintf("remove %s", removed[0])
+	case len(removed) > 0:
+		description = fmt.Sprintf("remove %d dependencies", len(removed))
+	case after.goVersion != "" && after.goVersion != before.goVersion:
+		return &semantic.SemanticChange{
+			Type:        "build",
+			Scope:       "go",
+			Description: fmt.Sprintf("require Go %s", after.goVersion),
+			Intent:      "Update the Go toolchain requirement",
+			Impact:      "Builds need a newer Go version",
+			Files:       []string{file.Path},
+			Confidence:  0.9,
+			Reasoning:   fmt.Sprintf("go directive changed from %q to %q", before.goVersion, after.goVersion),
+			Metadata:    map[string]string{"language": "go", "go_version": after.goVersion},
+		}
+	default:
+		description = "update go.mod"
+	}
+
+	return &semantic.SemanticChange{
+		Type:        "build",
+		Scope:       "deps",
+		Description: description,
+		Intent:      "Dependency maintenance",
+		Impact:      fmt.Sprintf("%d bumped, %d added, %d removed", len(bumped), len(added), len(removed)),
+		Files:       []string{file.Path},
+		Confidence:  0.95,
+		Reasoning:   "go.mod require directives changed",
+		Metadata: map[string]string{
+			"language": "go",
+			"bumped":   strings.Join(bumped, ","),
+			"added":    strings.Join(added, ","),
+			"removed":  strings.Join(removed, ","),
+		},
+	}
+}
@@ -0,0 +1,95 @@
+// Package iac - Critical and security resource classification shared by the IaC plugins
+package iac
+
+import (
+	"path"
+	"strings"
+)
+
+// resource is a cloud resource declared in a template or program, normalized
+// across tools: AWS::RDS::DBInstance, rds.DatabaseInstance and aws.rds.Instance
+// all become provider "aws", service "rds"
+type resource struct {
+	Provider string
+	Service  string
+	Kind     string
+}
+
+// key returns the catalog lookup key, e.g. "aws:rds.dbinstance"
+func (r resource) key() string {
+	return strings.ToLower(r.Provider + ":" + r.Service + "." + r.Kind)
+}
+
+// String returns the resource as written, e.g. "rds.DBInstance"
+func (r resource) String() string {
+	return r.Service + "." + r.Kind
+}
+
+// criticalResources hold data or define networks; removing them is breaking
+var criticalResources = []string{
+	"aws:rds.*", "aws:dynamodb.*", "aws:docdb.*", "aws:neptune.*", "aws:redshift.*",
+	"aws:elasticache.*", "aws:ec2.vpc", "aws:s3.bucket", "aws:eks.cluster", "aws:kinesis.stream",
+	"aws:efs.filesystem",
+	"azure:sql.*", "azure:cosmosdb.*", "azure:storage.account", "azure:network.virtualnetwork",
+	"azure:containerservice.*cluster",
+	"gcp:sql.*", "gcp:storage.bucket", "gcp:compute.network", "gcp:container.cluster", "gcp:bigquery.dataset",
+}
+
+// securityResources control identity, encryption and network access
+var securityResources = []string{
+	"aws:iam.*", "aws:kms.*", "aws:secretsmanager.*", "aws:wafv2.*", "aws:cognito.*",
+	"aws:ec2.securitygroup*", "aws:ec2.networkacl*", "aws:s3.bucketpolicy", "aws:lambda.permission",
+	"azure:authorization.*", "azure:keyvault.*", "azure:network.networksecuritygroup",
+	"gcp:iam.*", "gcp:kms.*", "gcp:secretmanager.*", "gcp:compute.firewall", "gcp:projects.iam*",
+}
+
+// serviceScopes maps services to commit scopes; unknown services use "infra"
+var serviceScopes = map[string]string{
+	"ec2.vpc": "network", "ec2.subnet": "network", "elasticloadbalancingv2": "network", "elbv2": "network",
+	"network": "network", "route53": "dns", "dns": "dns",
+	"rds": "storage", "dynamodb": "storage", "s3": "storage", "storage": "storage", "sql": "storage",
+	"efs": "storage", "docdb": "storage", "elasticache": "storage", "cosmosdb": "storage",
+	"ec2": "compute", "lambda": "compute", "ecs": "compute", "eks": "compute", "compute": "compute",
+	"container": "compute", "containerservice": "compute",
+	"cloudwatch": "monitoring", "logs": "monitoring", "monitoring": "monitoring",
+}
+
+// matchesAny reports whether a resource key matches one of the catalog patterns
+func matchesAny(r resource, patterns []string) bool {
+	key := r.key()
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isCritical reports whether a resource holds data or defines a network
+func isCritical(r resource) bool {
+	return matchesAny(r, criticalResources)
+}
+
+// isSecurity reports whether a resource controls access or encryption
+func isSecurity(r resource) bool {
+	return matchesAny(r, securityResources)
+}
+
+// scopeFor picks the commit scope for a set of resources
+func scopeFor(resources []resource) string {
+	for _, r := range resources {
+		if isSecurity(r) {
+			return "security"
+		}
+	}
+	for _, r := range resources {
+		service := strings.ToLower(r.Service)
+		if scope, ok := serviceScopes[service+"."+strings.ToLower(r.Kind)]; ok {
+			return scope
+		}
+		if scope, ok := serviceScopes[service]; ok {
+			return scope
+		}
+	}
+	return "infra"
+}
@@ -0,0 +1,60 @@
+// Package iac - AWS CDK stack plugin
+package iac
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// CDKPlugin provides semantic analysis for AWS CDK stacks written in TypeScript or Python
+type CDKPlugin struct {
+	analyzer
+}
+
+// NewCDKPlugin creates a new AWS CDK semantic analyzer plugin
+func NewCDKPlugin() *CDKPlugin {
+	return &CDKPlugin{
+		analyzer: analyzer{version: "1.0.0", tool: "CDK", extract: extractCDK},
+	}
+}
+
+// Name returns the plugin name
+func (c *CDKPlugin) Name() string {
+	return "cdk"
+}
+
+// Priority ranks this plugin above language plugins for the source files it recognises
+func (c *CDKPlugin) Priority() int {
+	return 10
+}
+
+// SupportedExtensions returns file extensions this plugin supports.
+// Stacks are ordinary source files, so they are recognised by content.
+func (c *CDKPlugin) SupportedExtensions() []string {
+	return nil
+}
+
+// SupportedFilePatterns returns file patterns this plugin supports
+func (c *CDKPlugin) SupportedFilePatterns() []string {
+	return []string{"cdk.json", "lib/*-stack.ts", "stacks/*.py"}
+}
+
+// CanAnalyze determines if this plugin can analyze the given file
+func (c *CDKPlugin) CanAnalyze(file semantic.FileChange) bool {
+	base := filepath.Base(file.Path)
+	ext := filepath.Ext(base)
+	if ext != ".ts" && ext != ".js" && ext != ".py" || strings.HasSuffix(base, ".d.ts") {
+		return false
+	}
+
+	content := file.AfterContent + file.BeforeContent
+	if strings.Contains(content, "aws-cdk-lib") || strings.Contains(content, "aws_cdk") || strings.Contains(content, "@aws-cdk/") {
+		return true
+	}
+
+	// Diff fragments rarely include the imports; fall back to stack naming
+	name := strings.ToLower(base)
+	return strings.Contains(name, "stack") && len(extractCDK(content)) > 0
+}
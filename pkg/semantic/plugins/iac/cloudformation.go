@@ -0,0 +1,50 @@
+// Package iac - CloudFormation and SAM template plugin
+package iac
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// CloudFormationPlugin provides semantic analysis for CloudFormation and SAM templates
+type CloudFormationPlugin struct {
+	analyzer
+}
+
+// NewCloudFormationPlugin creates a new CloudFormation semantic analyzer plugin
+func NewCloudFormationPlugin() *CloudFormationPlugin {
+	return &CloudFormationPlugin{
+		analyzer: analyzer{version: "1.0.0", tool: "CloudFormation", extract: extractCloudFormation},
+	}
+}
+
+// Name returns the plugin name
+func (c *CloudFormationPlugin) Name() string {
+	return "cloudformation"
+}
+
+// SupportedExtensions returns file extensions this plugin supports.
+// Templates share YAML and JSON with other tools, so they are recognised by content.
+func (c *CloudFormationPlugin) SupportedExtensions() []string {
+	return nil
+}
+
+// SupportedFilePatterns returns file patterns this plugin supports
+func (c *CloudFormationPlugin) SupportedFilePatterns() []string {
+	return []string{"*.template", "template.yaml", "template.yml", "cloudformation/*"}
+}
+
+// CanAnalyze determines if this plugin can analyze the given file
+func (c *CloudFormationPlugin) CanAnalyze(file semantic.FileChange) bool {
+	ext := strings.ToLower(filepath.Ext(file.Path))
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" && ext != ".template" {
+		return false
+	}
+
+	content := file.AfterContent + file.BeforeContent
+	return strings.Contains(content, "AWSTemplateFormatVersion") ||
+		strings.Contains(content, "AWS::Serverless") ||
+		cfnTypePattern.MatchString(content)
+}
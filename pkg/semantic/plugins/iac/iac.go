@@ -0,0 +1,286 @@
+// Package iac provides semantic analysis for CloudFormation templates, AWS CDK stacks
+// and Pulumi programs, classifying critical and security resources like the Terraform plugin
+package iac
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// analyzer holds the analysis shared by the IaC plugins; each plugin supplies
+// its display name and resource extractor
+type analyzer struct {
+	version string
+	tool    string
+	extract func(content string) []resource
+}
+
+// Version returns the plugin version
+func (a *analyzer) Version() string {
+	return a.version
+}
+
+// AnalyzeFile analyzes a single template or program for resource changes
+func (a *analyzer) AnalyzeFile(_ context.Context, file semantic.FileChange, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	switch file.ChangeType {
+	case "added":
+		return a.analyzeNewFile(file, analysisCtx), nil
+	case "deleted":
+		return a.analyzeDeletedFile(file, analysisCtx), nil
+	case "modified":
+		return a.analyzeModifiedFile(file, analysisCtx), nil
+	case "renamed":
+		return a.analyzeRenamedFile(file), nil
+	default:
+		return nil, fmt.Errorf("unknown change type: %s", file.ChangeType)
+	}
+}
+
+// AnalyzeProject has no project-level IaC analysis; per-file results are consolidated
+func (a *analyzer) AnalyzeProject(_ context.Context, _ semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	return nil, nil
+}
+
+// DefaultConfig returns the default configuration for the plugin
+func (a *analyzer) DefaultConfig() map[string]string {
+	return map[string]string{
+		"detect_breaking_changes": "true",
+		"analyze_security":        "true",
+	}
+}
+
+// ValidateConfig validates plugin configuration
+func (a *analyzer) ValidateConfig(config map[string]string) error {
+	for key, value := range config {
+		switch key {
+		case "detect_breaking_changes", "analyze_security":
+			if value != "true" && value != "false" {
+				return fmt.Errorf("%s must be 'true' or 'false', got %s", key, value)
+			}
+		default:
+			return fmt.Errorf("unknown config key: %s", key)
+		}
+	}
+	return nil
+}
+
+// analyzeNewFile classifies a new template by the resources it declares
+func (a *analyzer) analyzeNewFile(file semantic.FileChange, analysisCtx semantic.AnalysisContext) *semantic.SemanticChange {
+	resources := a.extract(file.AfterContent)
+	scope := a.scope(resources, analysisCtx)
+	change := a.newChange(file, scope, resources)
+	change.Type = "feat"
+	change.Intent = "Infrastructure provisioning"
+	change.Confidence = 0.85
+
+	critical, security := classify(resources)
+	switch {
+	case len(resources) == 0:
+		change.Description = fmt.Sprintf("add %s configuration %s", a.tool, filepath.Base(file.Path))
+		change.Impact = "New infrastructure components defined"
+		change.Confidence = 0.8
+	case len(critical) > 0:
+		change.Description = fmt.Sprintf("add critical %s infrastructure", scope)
+		change.Impact = "Critical infrastructure components added"
+	case len(security) > 0:
+		change.Description = fmt.Sprintf("add %s security configuration", scope)
+		change.Impact = "Security infrastructure components added"
+	default:
+		change.Description = describe("add", resources)
+		change.Impact = "New infrastructure components"
+	}
+	change.Reasoning = fmt.Sprintf("Added %d %s resources: %s", len(resources), a.tool, join(resources))
+	return change
+}
+
+// analyzeDeletedFile flags removed stacks that destroy critical resources
+func (a *analyzer) analyzeDeletedFile(file semantic.FileChange, analysisCtx semantic.AnalysisContext) *semantic.SemanticChange {
+	resources := a.extract(file.BeforeContent)
+	change := a.newChange(file, a.scope(resources, analysisCtx), resources)
+	critical, _ := classify(resources)
+
+	change.Type = "refactor"
+	change.BreakingChange = len(critical) > 0 && analysisCtx.Config["detect_breaking_changes"] != "false"
+	if change.BreakingChange {
+		change.Type = "feat"
+	}
+	change.Description = fmt.Sprintf("remove %s configuration %s", a.tool, filepath.Base(file.Path))
+	change.Intent = "Infrastructure cleanup or refactoring"
+	change.Impact = "Infrastructure resources will be destroyed"
+	change.Confidence = 0.9
+	change.Reasoning = fmt.Sprintf("%s file deleted with %d resources", a.tool, len(resources))
+	return change
+}
+
+// analyzeRenamedFile describes a moved template
+func (a *analyzer) analyzeRenamedFile(file semantic.FileChange) *semantic.SemanticChange {
+	verb := "rename"
+	if filepath.Dir(file.OldPath) != filepath.Dir(file.Path) {
+		verb = "move"
+	}
+
+	change := a.newChange(file, "infra", nil)
+	change.Type = "refactor"
+	change.Description = fmt.Sprintf("%s %s configuration %s to %s", verb, a.tool, file.OldPath, file.Path)
+	change.Intent = "Infrastructure code organization"
+	change.Impact = "Deployed resources are unchanged unless stack names depend on the path"
+	change.Files = []string{file.OldPath, file.Path}
+	change.Confidence = 0.9
+	change.Reasoning = fmt.Sprintf("git detected %s as a rename of %s", file.Path, file.OldPath)
+	change.Metadata["old_path"] = file.OldPath
+	return change
+}
+
+// analyzeModifiedFile compares the declared resources before and after
+func (a *analyzer) analyzeModifiedFile(file semantic.FileChange, analysisCtx semantic.AnalysisContext) *semantic.SemanticChange {
+	added, removed := compare(a.extract(file.BeforeContent), a.extract(file.AfterContent))
+	touched := append(append([]resource{}, added...), removed...)
+	if len(touched) == 0 {
+		touched = a.extract(file.AfterContent + "\n" + file.BeforeContent)
+	}
+
+	change := a.newChange(file, a.scope(touched, analysisCtx), touched)
+	change.Metadata["added_resources"] = join(added)
+	change.Metadata["removed_resources"] = join(removed)
+	change.Confidence = 0.9
+
+	removedCritical, _ := classify(removed)
+	switch {
+	case len(added) > 0 && len(removed) == 0:
+		change.Type = "feat"
+		change.Description = describe("add", added)
+		change.Intent = "Infrastructure expansion"
+		change.Impact = fmt.Sprintf("%d resources will be created", len(added))
+	case len(removed) > 0 && len(added) == 0:
+		change.Type = "refactor"
+		change.Description = describe("remove", removed)
+		change.Intent = "Infrastructure cleanup"
+		change.Impact = fmt.Sprintf("%d resources will be destroyed", len(removed))
+		if len(removedCritical) > 0 && analysisCtx.Config["detect_breaking_changes"] != "false" {
+			change.Type = "feat"
+			change.BreakingChange = true
+			change.Impact = fmt.Sprintf("Critical resources will be destroyed: %s", join(removedCritical))
+		}
+	case len(added) > 0:
+		change.Type = "refactor"
+		change.Description = fmt.Sprintf("replace %s with %s", join(removed), join(added))
+		change.Intent = "Infrastructure restructuring"
+		change.Impact = fmt.Sprintf("%d resources will be created; %d resources will be destroyed", len(added), len(removed))
+		change.BreakingChange = len(removedCritical) > 0 && analysisCtx.Config["detect_breaking_changes"] != "false"
+	case analysisCtx.Config["analyze_security"] != "false" && securityHardening.MatchString(file.AfterContent):
+		change.Type = "fix"
+		change.Description = fmt.Sprintf("improve %s security configuration", change.Scope)
+		change.Intent = "Security hardening"
+		change.Impact = "Resource access or encryption tightened"
+		change.Confidence = 0.75
+	default:
+		change.Type = "refactor"
+		change.Description = fmt.Sprintf("update %s configuration", a.tool)
+		change.Intent = "Configuration improvement"
+		change.Impact = "Infrastructure configuration updated"
+		change.Confidence = 0.6
+	}
+
+	change.Reasoning = fmt.Sprintf("Added resources: %s; Removed resources: %s", orNone(join(added)), orNone(join(removed)))
+	return change
+}
+
+// securityHardening matches properties that enable encryption or block public access
+var securityHardening = regexp.MustCompile(`(?i)encrypt|BlockPublic|PublicAccessBlock|publicReadAccess\W+false|enforceSSL|sslEnforce|RestrictPublicBuckets|minimumTlsVersion`)
+
+// newChange fills the fields common to every IaC result
+func (a *analyzer) newChange(file semantic.FileChange, scope string, resources []resource) *semantic.SemanticChange {
+	critical, security := classify(resources)
+	return &semantic.SemanticChange{
+		Scope: scope,
+		Files: []string{file.Path},
+		Metadata: map[string]string{
+			"file_type":      strings.ToLower(a.tool),
+			"resource_types": join(resources),
+			"critical":       fmt.Sprintf("%t", len(critical) > 0),
+			"security":       fmt.Sprintf("%t", len(security) > 0),
+		},
+	}
+}
+
+// scope picks the commit scope, ignoring security resources when security analysis is off
+func (a *analyzer) scope(resources []resource, analysisCtx semantic.AnalysisContext) string {
+	scope := scopeFor(resources)
+	if scope == "security" && analysisCtx.Config["analyze_security"] == "false" {
+		var others []resource
+		for _, r := range resources {
+			if !isSecurity(r) {
+				others = append(others, r)
+			}
+		}
+		return scopeFor(others)
+	}
+	return scope
+}
+
+// classify splits out critical and security resources
+func classify(resources []resource) (critical, security []resource) {
+	for _, r := range resources {
+		if isCritical(r) {
+			critical = append(critical, r)
+		}
+		if isSecurity(r) {
+			security = append(security, r)
+		}
+	}
+	return critical, security
+}
+
+// compare returns resources only present after and only present before
+func compare(before, after []resource) (added, removed []resource) {
+	beforeKeys := make(map[string]bool)
+	afterKeys := make(map[string]bool)
+	for _, r := range before {
+		beforeKeys[r.key()] = true
+	}
+	for _, r := range after {
+		afterKeys[r.key()] = true
+		if !beforeKeys[r.key()] {
+			added = append(added, r)
+		}
+	}
+	for _, r := range before {
+		if !afterKeys[r.key()] {
+			removed = append(removed, r)
+		}
+	}
+	return added, removed
+}
+
+// describe phrases a resource change like the Terraform plugin does
+func describe(verb string, resources []resource) string {
+	if len(resources) == 1 {
+		return fmt.Sprintf("%s %s resource", verb, resources[0])
+	}
+	if verb == "add" {
+		return fmt.Sprintf("add %d new resources", len(resources))
+	}
+	return fmt.Sprintf("%s %d resources", verb, len(resources))
+}
+
+// join lists resources for metadata and reasoning
+func join(resources []resource) string {
+	names := make([]string, 0, len(resources))
+	for _, r := range resources {
+		names = append(names, r.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// orNone substitutes "none" for an empty list
+func orNone(list string) string {
+	if list == "" {
+		return "none"
+	}
+	return list
+}
@@ -0,0 +1,216 @@
+package iac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+const cfnTemplate = `AWSTemplateFormatVersion: "2010-09-09"
+Resources:
+  OrdersTable:
+    Type: AWS::DynamoDB::Table
+  OrdersQueue:
+    Type: AWS::SQS::Queue
+`
+
+const cdkStack = `import * as cdk from 'aws-cdk-lib';
+import * as s3 from 'aws-cdk-lib/aws-s3';
+import * as iam from 'aws-cdk-lib/aws-iam';
+
+export class AssetsStack extends cdk.Stack {
+  constructor(scope: Construct, id: string) {
+    super(scope, id);
+    new s3.Bucket(this, 'Assets');
+    new cdk.CfnOutput(this, 'Name', { value: 'x' });
+  }
+}
+`
+
+const pulumiGo = `import (
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/iam"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+func main() {
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		_, err := iam.NewRole(ctx, "deployer", &iam.RoleArgs{})
+		return err
+	})
+}
+`
+
+func TestIaCPlugins(t *testing.T) {
+	cfn := NewCloudFormationPlugin()
+	cdk := NewCDKPlugin()
+	pulumi := NewPulumiPlugin()
+	ctx := semantic.AnalysisContext{Config: cfn.DefaultConfig()}
+
+	t.Run("extract resources", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			got     []resource
+			wantKey []string
+		}{
+			{"cloudformation", extractCloudFormation(cfnTemplate), []string{"aws:dynamodb.table", "aws:sqs.queue"}},
+			{"cloudformation json", extractCloudFormation(`{"Type": "AWS::IAM::Role"}`), []string{"aws:iam.role"}},
+			{"cdk typescript", extractCDK(cdkStack), []string{"aws:s3.bucket"}},
+			{"cdk python L1", extractCDK(`rds.CfnDBInstance(self, "Db")`), []string{"aws:rds.dbinstance"}},
+			{"pulumi typescript", extractPulumi(`const b = new aws.s3.Bucket("b");`), []string{"aws:s3.bucket"}},
+			{"pulumi python azure", extractPulumi(`azure_native.keyvault.Vault("kv")`), []string{"azure:keyvault.vault"}},
+			{"pulumi yaml", extractPulumi("    type: gcp:storage:Bucket\n"), []string{"gcp:storage.bucket"}},
+			{"pulumi go", extractPulumi(pulumiGo), []string{"aws:iam.role"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if len(tt.got) != len(tt.wantKey) {
+					t.Fatalf("got %v, expected %v", tt.got, tt.wantKey)
+				}
+				for i, r := range tt.got {
+					if r.key() != tt.wantKey[i] {
+						t.Errorf("resource %d = %s, expected %s", i, r.key(), tt.wantKey[i])
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("can analyze", func(t *testing.T) {
+		tests := []struct {
+			plugin   semantic.SemanticPlugin
+			file     semantic.FileChange
+			expected bool
+		}{
+			{cfn, semantic.FileChange{Path: "infra/orders.yaml", AfterContent: cfnTemplate}, true},
+			{cfn, semantic.FileChange{Path: "k8s/deploy.yaml", AfterContent: "kind: Deployment\n"}, false},
+			{cdk, semantic.FileChange{Path: "lib/assets-stack.ts", AfterContent: cdkStack}, true},
+			{cdk, semantic.FileChange{Path: "src/app.ts", AfterContent: "new Foo(this)"}, false},
+			{pulumi, semantic.FileChange{Path: "Pulumi.prod.yaml"}, true},
+			{pulumi, semantic.FileChange{Path: "main.go", AfterContent: pulumiGo}, true},
+			{pulumi, semantic.FileChange{Path: "main.go", AfterContent: "package main\n"}, false},
+		}
+		for _, tt := range tests {
+			if got := tt.plugin.CanAnalyze(tt.file); got != tt.expected {
+				t.Errorf("%s.CanAnalyze(%s) = %v, expected %v", tt.plugin.Name(), tt.file.Path, got, tt.expected)
+			}
+		}
+	})
+
+	tests := []struct {
+		name         string
+		plugin       semantic.SemanticPlugin
+		file         semantic.FileChange
+		wantType     string
+		wantScope    string
+		wantBreaking bool
+		wantDesc     string
+	}{
+		{
+			name:      "new template with critical resources",
+			plugin:    cfn,
+			file:      semantic.FileChange{Path: "infra/orders.yaml", ChangeType: "added", AfterContent: cfnTemplate},
+			wantType:  "feat",
+			wantScope: "storage",
+			wantDesc:  "add critical storage infrastructure",
+		},
+		{
+			name:   "removed database is breaking",
+			plugin: cfn,
+			file: semantic.FileChange{
+				Path:          "infra/orders.yaml",
+				ChangeType:    "modified",
+				BeforeContent: "    Type: AWS::DynamoDB::Table\n",
+			},
+			wantType:     "feat",
+			wantScope:    "storage",
+			wantBreaking: true,
+			wantDesc:     "remove DynamoDB.Table resource",
+		},
+		{
+			name:   "cdk security construct",
+			plugin: cdk,
+			file: semantic.FileChange{
+				Path:         "lib/assets-stack.ts",
+				ChangeType:   "modified",
+				AfterContent: "    new iam.Role(this, 'Deployer', {});\n",
+			},
+			wantType:  "feat",
+			wantScope: "security",
+			wantDesc:  "add iam.Role resource",
+		},
+		{
+			name:   "encryption hardening",
+			plugin: cdk,
+			file: semantic.FileChange{
+				Path:          "lib/assets-stack.ts",
+				ChangeType:    "modified",
+				BeforeContent: "    new s3.Bucket(this, 'Assets');\n",
+				AfterContent:  "    new s3.Bucket(this, 'Assets', { encryption: s3.BucketEncryption.S3_MANAGED });\n",
+			},
+			wantType:  "fix",
+			wantScope: "storage",
+			wantDesc:  "improve storage security configuration",
+		},
+		{
+			name:         "deleted pulumi program with critical bucket",
+			plugin:       pulumi,
+			file:         semantic.FileChange{Path: "index.ts", ChangeType: "deleted", BeforeContent: `new aws.s3.Bucket("b");`},
+			wantType:     "feat",
+			wantScope:    "storage",
+			wantBreaking: true,
+			wantDesc:     "remove Pulumi configuration index.ts",
+		},
+		{
+			name:      "pulumi stack config",
+			plugin:    pulumi,
+			file:      semantic.FileChange{Path: "Pulumi.prod.yaml", ChangeType: "modified"},
+			wantType:  "chore",
+			wantScope: "prod",
+			wantDesc:  "update prod stack configuration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := tt.plugin.AnalyzeFile(context.Background(), tt.file, ctx)
+			if err != nil {
+				t.Fatalf("AnalyzeFile() error = %v", err)
+			}
+			if change.Type != tt.wantType {
+				t.Errorf("Type = %s, expected %s", change.Type, tt.wantType)
+			}
+			if change.Scope != tt.wantScope {
+				t.Errorf("Scope = %s, expected %s", change.Scope, tt.wantScope)
+			}
+			if change.BreakingChange != tt.wantBreaking {
+				t.Errorf("BreakingChange = %v, expected %v", change.BreakingChange, tt.wantBreaking)
+			}
+			if change.Description != tt.wantDesc {
+				t.Errorf("Description = %q, expected %q", change.Description, tt.wantDesc)
+			}
+		})
+	}
+
+	t.Run("breaking detection can be disabled", func(t *testing.T) {
+		file := semantic.FileChange{Path: "t.yaml", ChangeType: "modified", BeforeContent: "Type: AWS::RDS::DBInstance\n"}
+		change, err := cfn.AnalyzeFile(context.Background(), file, semantic.AnalysisContext{
+			Config: map[string]string{"detect_breaking_changes": "false"},
+		})
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+		if change.BreakingChange || change.Type != "refactor" {
+			t.Errorf("expected non-breaking refactor, got %s breaking=%v", change.Type, change.BreakingChange)
+		}
+	})
+
+	t.Run("validate config", func(t *testing.T) {
+		if err := cfn.ValidateConfig(map[string]string{"analyze_security": "maybe"}); err == nil {
+			t.Error("expected error for invalid boolean")
+		}
+		if err := cfn.ValidateConfig(map[string]string{"unknown": "true"}); err == nil {
+			t.Error("expected error for unknown key")
+		}
+	})
+}
@@ -0,0 +1,97 @@
+// Package iac - Pulumi program plugin
+package iac
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// PulumiPlugin provides semantic analysis for Pulumi programs and Pulumi YAML
+type PulumiPlugin struct {
+	analyzer
+}
+
+// NewPulumiPlugin creates a new Pulumi semantic analyzer plugin
+func NewPulumiPlugin() *PulumiPlugin {
+	return &PulumiPlugin{
+		analyzer: analyzer{version: "1.0.0", tool: "Pulumi", extract: extractPulumi},
+	}
+}
+
+// Name returns the plugin name
+func (p *PulumiPlugin) Name() string {
+	return "pulumi"
+}
+
+// Priority ranks this plugin above language plugins for the source files it recognises
+func (p *PulumiPlugin) Priority() int {
+	return 10
+}
+
+// SupportedExtensions returns file extensions this plugin supports.
+// Programs are ordinary source files, so they are recognised by content.
+func (p *PulumiPlugin) SupportedExtensions() []string {
+	return nil
+}
+
+// SupportedFilePatterns returns file patterns this plugin supports
+func (p *PulumiPlugin) SupportedFilePatterns() []string {
+	return []string{"Pulumi.yaml", "Pulumi.*.yaml"}
+}
+
+// CanAnalyze determines if this plugin can analyze the given file
+func (p *PulumiPlugin) CanAnalyze(file semantic.FileChange) bool {
+	if isStackConfig(file.Path) || filepath.Base(file.Path) == "Pulumi.yaml" {
+		return true
+	}
+
+	switch filepath.Ext(file.Path) {
+	case ".ts", ".js", ".py", ".go", ".cs":
+	default:
+		return false
+	}
+
+	content := file.AfterContent + file.BeforeContent
+	return strings.Contains(content, "@pulumi/") ||
+		strings.Contains(content, "import pulumi") ||
+		strings.Contains(content, "github.com/pulumi/pulumi") ||
+		pulumiCallPattern.MatchString(content)
+}
+
+// AnalyzeFile treats stack configuration separately from programs
+func (p *PulumiPlugin) AnalyzeFile(ctx context.Context, file semantic.FileChange, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	if !isStackConfig(file.Path) {
+		return p.analyzer.AnalyzeFile(ctx, file, analysisCtx)
+	}
+
+	stack := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file.Path), "Pulumi."), ".yaml")
+	verb := "update"
+	switch file.ChangeType {
+	case "added":
+		verb = "add"
+	case "deleted":
+		verb = "remove"
+	}
+
+	return &semantic.SemanticChange{
+		Type:        "chore",
+		Scope:       stack,
+		Description: fmt.Sprintf("%s %s stack configuration", verb, stack),
+		Intent:      "Environment configuration",
+		Impact:      fmt.Sprintf("Changes take effect on the next %s deployment", stack),
+		Files:       []string{file.Path},
+		Confidence:  0.85,
+		Reasoning:   "Pulumi stack configuration file",
+		Metadata:    map[string]string{"file_type": "pulumi", "stack": stack},
+	}, nil
+}
+
+// isStackConfig matches Pulumi.<stack>.yaml files
+func isStackConfig(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasPrefix(base, "Pulumi.") && strings.HasSuffix(base, ".yaml") && base != "Pulumi.yaml"
+}
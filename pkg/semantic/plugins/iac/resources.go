@@ -0,0 +1,101 @@
+// Package iac - Resource extraction for CloudFormation templates, CDK stacks and Pulumi programs
+package iac
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	cfnTypePattern = regexp.MustCompile(`["']?Type["']?\s*:\s*["']?(AWS|Alexa)::(\w+)::(\w+)`)
+
+	// new s3.Bucket(this, ...) in TypeScript, s3.Bucket(self, ...) in Python
+	cdkTSPattern = regexp.MustCompile(`new\s+(\w+)\.(\w+)\s*\(\s*this\b`)
+	cdkPyPattern = regexp.MustCompile(`\b(\w+)\.([A-Z]\w*)\s*\(\s*self\b`)
+
+	// new aws.s3.Bucket("name") / aws.s3.Bucket("name") / s3.NewBucket(ctx, ...) / type: aws:s3:Bucket
+	pulumiCallPattern = regexp.MustCompile(`\b(aws|azure|azure_native|gcp)\.(\w+)\.([A-Z]\w*)\s*\(`)
+	pulumiGoPattern   = regexp.MustCompile(`\b(\w+)\.New([A-Z]\w*)\s*\(\s*ctx\b`)
+	pulumiYAMLPattern = regexp.MustCompile(`type:\s*["']?(aws|azure-native|azure|gcp):(\w+)(?:/\w+)?:(\w+)`)
+	pulumiGoImport    = regexp.MustCompile(`github\.com/pulumi/pulumi-(aws|azure-native|azure|gcp)/sdk/v\d+/go/\w+/(\w+)`)
+)
+
+// cdkCoreConstructs are framework constructs that declare no cloud resource
+var cdkCoreConstructs = map[string]bool{
+	"App": true, "Stack": true, "Stage": true, "Construct": true, "CfnOutput": true,
+	"CfnParameter": true, "Duration": true, "RemovalPolicy": true, "Tags": true,
+}
+
+// extractCloudFormation finds resource types in a YAML or JSON template
+func extractCloudFormation(content string) []resource {
+	var resources []resource
+	for _, m := range cfnTypePattern.FindAllStringSubmatch(content, -1) {
+		resources = append(resources, resource{Provider: "aws", Service: m[2], Kind: m[3]})
+	}
+	return unique(resources)
+}
+
+// extractCDK finds constructs instantiated in a CDK stack. The module alias is
+// used as the service, which matches the aws-cdk-lib/aws-<service> convention.
+func extractCDK(content string) []resource {
+	var resources []resource
+	for _, pattern := range []*regexp.Regexp{cdkTSPattern, cdkPyPattern} {
+		for _, m := range pattern.FindAllStringSubmatch(content, -1) {
+			if cdkCoreConstructs[m[2]] || m[1] == "cdk" || m[1] == "core" || m[1] == "super" {
+				continue
+			}
+			resources = append(resources, resource{Provider: "aws", Service: m[1], Kind: strings.TrimPrefix(m[2], "Cfn")})
+		}
+	}
+	return unique(resources)
+}
+
+// extractPulumi finds resources declared in a Pulumi program or Pulumi YAML
+func extractPulumi(content string) []resource {
+	var resources []resource
+	for _, m := range pulumiCallPattern.FindAllStringSubmatch(content, -1) {
+		resources = append(resources, resource{Provider: pulumiProvider(m[1]), Service: m[2], Kind: m[3]})
+	}
+	for _, m := range pulumiYAMLPattern.FindAllStringSubmatch(content, -1) {
+		resources = append(resources, resource{Provider: pulumiProvider(m[1]), Service: m[2], Kind: m[3]})
+	}
+
+	// Go programs call <pkg>.New<Kind>(ctx, ...); the provider comes from the import path
+	goProviders := make(map[string]string)
+	for _, m := range pulumiGoImport.FindAllStringSubmatch(content, -1) {
+		goProviders[m[2]] = pulumiProvider(m[1])
+	}
+	for _, m := range pulumiGoPattern.FindAllStringSubmatch(content, -1) {
+		provider, ok := goProviders[m[1]]
+		if !ok {
+			if len(goProviders) > 0 || m[1] == "pulumi" {
+				continue
+			}
+			provider = "aws" // diff fragment without the import block
+		}
+		resources = append(resources, resource{Provider: provider, Service: m[1], Kind: m[2]})
+	}
+
+	return unique(resources)
+}
+
+// pulumiProvider normalizes provider package names
+func pulumiProvider(name string) string {
+	if strings.HasPrefix(name, "azure") {
+		return "azure"
+	}
+	return name
+}
+
+// unique removes duplicate resources, keeping the first occurrence
+func unique(resources []resource) []resource {
+	seen := make(map[string]bool)
+	var result []resource
+	for _, r := range resources {
+		if !seen[r.key()] {
+			seen[r.key()] = true
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,418 @@
+// Package kubernetes provides semantic analysis for Kubernetes manifests and Helm charts
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// securityKinds are resource kinds that control access or network exposure
+var securityKinds = map[string]bool{
+	"Role":                true,
+	"ClusterRole":         true,
+	"RoleBinding":         true,
+	"ClusterRoleBinding":  true,
+	"ServiceAccount":      true,
+	"NetworkPolicy":       true,
+	"PodSecurityPolicy":   true,
+	"AuthorizationPolicy": true,
+}
+
+// KubernetesPlugin provides semantic analysis for Kubernetes YAML and Helm charts
+type KubernetesPlugin struct {
+	version string
+}
+
+// NewKubernetesPlugin creates a new Kubernetes/Helm semantic analyzer plugin
+func NewKubernetesPlugin() *KubernetesPlugin {
+	return &KubernetesPlugin{
+		version: "1.0.0",
+	}
+}
+
+// Name returns the plugin name
+func (k *KubernetesPlugin) Name() string {
+	return "kubernetes"
+}
+
+// Version returns the plugin version
+func (k *KubernetesPlugin) Version() string {
+	return k.version
+}
+
+// SupportedExtensions returns file extensions this plugin supports.
+// YAML is shared with other plugins, so manifests are recognised by CanAnalyze.
+func (k *KubernetesPlugin) SupportedExtensions() []string {
+	return nil
+}
+
+// SupportedFilePatterns returns file patterns this plugin supports
+func (k *KubernetesPlugin) SupportedFilePatterns() []string {
+	return []string{"Chart.yaml", "values.yaml", "k8s/*", "kubernetes/*", "manifests/*"}
+}
+
+// CanAnalyze determines if this plugin can analyze the given file
+func (k *KubernetesPlugin) CanAnalyze(file semantic.FileChange) bool {
+	ext := strings.ToLower(filepath.Ext(file.Path))
+	if ext != ".yaml" && ext != ".yml" && ext != ".tpl" {
+		return false
+	}
+
+	if isHelmFile(file.Path) {
+		return true
+	}
+
+	content := file.AfterContent
+	if content == "" {
+		content = file.BeforeContent
+	}
+	return strings.Contains(content, "apiVersion:") || strings.Contains(content, "kind:")
+}
+
+// AnalyzeFile analyzes a single manifest, chart or values file
+func (k *KubernetesPlugin) AnalyzeFile(_ context.Context, file semantic.FileChange, _ semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	switch base := filepath.Base(file.Path); {
+	case base == "Chart.yaml":
+		return k.analyzeChart(file), nil
+	case isValuesFile(file.Path):
+		return k.analyzeValues(file), nil
+	}
+
+	switch file.ChangeType {
+	case "added", "deleted", "modified", "renamed":
+		return k.analyzeManifest(file), nil
+	default:
+		return nil, fmt.Errorf("unknown change type: %s", file.ChangeType)
+	}
+}
+
+// AnalyzeProject reports changesets that only touch Helm values files
+func (k *KubernetesPlugin) AnalyzeProject(_ context.Context, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	if len(analysisCtx.Files) < 2 {
+		return nil, nil
+	}
+
+	var files []string
+	charts := make(map[string]bool)
+	for _, file := range analysisCtx.Files {
+		if !isValuesFile(file.Path) {
+			return nil, nil
+		}
+		files = append(files, file.Path)
+		charts[chartName(file.Path)] = true
+	}
+
+	scope := "helm"
+	if len(charts) == 1 {
+		for chart := range charts {
+			if chart != "" {
+				scope = chart
+			}
+		}
+	}
+
+	return &semantic.SemanticChange{
+		Type:        "chore",
+		Scope:       scope,
+		Description: fmt.Sprintf("update Helm values in %d files", len(files)),
+		Intent:      "Deployment configuration change",
+		Impact:      "Rendered manifests change on next release",
+		Files:       files,
+		Confidence:  0.85,
+		Reasoning:   "Only Helm values files changed",
+		Metadata:    map[string]string{"file_type": "helm-values"},
+	}, nil
+}
+
+// DefaultConfig returns the default configuration for the plugin
+func (k *KubernetesPlugin) DefaultConfig() map[string]string {
+	return map[string]string{
+		"security_scope": "security",
+	}
+}
+
+// ValidateConfig validates plugin configuration
+func (k *KubernetesPlugin) ValidateConfig(config map[string]string) error {
+	if scope, exists := config["security_scope"]; exists && strings.TrimSpace(scope) == "" {
+		return fmt.Errorf("security_scope cannot be empty")
+	}
+	return nil
+}
+
+// analyzeManifest classifies changes to a Kubernetes manifest
+func (k *KubernetesPlugin) analyzeManifest(file semantic.FileChange) *semantic.SemanticChange {
+	before := parseManifest(file.BeforeContent)
+	after := parseManifest(file.AfterContent)
+	name := manifestName(file, after, before)
+
+	change := &semantic.SemanticChange{
+		Scope:    "k8s",
+		Files:    []string{file.Path},
+		Metadata: map[string]string{"file_type": "kubernetes"},
+	}
+
+	// RBAC and network policy changes always carry the security scope
+	if kinds := securityResources(before, after); len(kinds) > 0 {
+		change.Type = "feat"
+		change.Scope = "security"
+		change.Description = fmt.Sprintf("update %s", strings.Join(kinds, ", "))
+		if file.ChangeType == "added" {
+			change.Description = fmt.Sprintf("add %s", strings.Join(kinds, ", "))
+		}
+		change.Intent = "Access control or network policy change"
+		change.Impact = "Permissions or allowed traffic change"
+		change.Confidence = 0.85
+		change.Reasoning = "RBAC or NetworkPolicy resources changed"
+		change.Metadata["security"] = "true"
+		return change
+	}
+
+	if bumps := imageBumps(before.images, after.images); len(bumps) > 0 {
+		change.Type = "build"
+		change.Scope = "deps"
+		change.Description = describeBumps(bumps)
+		change.Intent = "Deploy a new image version"
+		change.Impact = "Workloads roll out the new image"
+		change.Confidence = 0.9
+		change.Reasoning = fmt.Sprintf("%d container image tags changed", len(bumps))
+		return change
+	}
+
+	switch file.ChangeType {
+	case "added":
+		change.Type = "feat"
+		change.Description = fmt.Sprintf("add %s", describeResources(after.resources, name))
+		change.Intent = "Deploy new workloads or services"
+		change.Impact = "New cluster resources"
+		change.Confidence = 0.85
+		change.Reasoning = fmt.Sprintf("New manifest with %d resources", len(after.resources))
+		return change
+	case "deleted":
+		change.Type = "refactor"
+		change.Description = fmt.Sprintf("remove %s", describeResources(before.resources, name))
+		change.Intent = "Decommission cluster resources"
+		change.Impact = "Resources are deleted on next apply"
+		change.Confidence = 0.85
+		change.Reasoning = fmt.Sprintf("Manifest deleted with %d resources", len(before.resources))
+		return change
+	}
+
+	if added := newResources(before.resources, after.resources); len(added) > 0 {
+		change.Type = "feat"
+		change.Description = fmt.Sprintf("add %s", describeResources(added, name))
+		change.Intent = "Deploy new workloads or services"
+		change.Impact = "New cluster resources"
+		change.Confidence = 0.8
+		change.Reasoning = "New resource kinds added to an existing manifest"
+		return change
+	}
+
+	if after.resourcesChanged || before.resourcesChanged {
+		change.Type = "perf"
+		change.Description = fmt.Sprintf("tune resource requests and limits for %s", name)
+		change.Intent = "Right-size workloads"
+		change.Impact = "Scheduling and throttling behaviour changes"
+		change.Confidence = 0.75
+		change.Reasoning = "CPU or memory requests/limits changed"
+		return change
+	}
+
+	if after.replicas != "" && after.replicas != before.replicas {
+		change.Type = "chore"
+		change.Description = fmt.Sprintf("scale %s to %s replicas", name, after.replicas)
+		change.Intent = "Adjust capacity"
+		change.Impact = "Replica count changes"
+		change.Confidence = 0.85
+		change.Reasoning = fmt.Sprintf("replicas changed from %q to %q", before.replicas, after.replicas)
+		return change
+	}
+
+	change.Type = "chore"
+	change.Description = fmt.Sprintf("update %s manifest", name)
+	change.Intent = "Configuration change"
+	change.Impact = "Manifest configuration changes"
+	change.Confidence = 0.5
+	change.Reasoning = "Manifest changed without image, scale or resource changes"
+	return change
+}
+
+// analyzeChart classifies Chart.yaml changes
+func (k *KubernetesPlugin) analyzeChart(file semantic.FileChange) *semantic.SemanticChange {
+	chart := chartName(file.Path)
+	before := yamlValue(file.BeforeContent, "version")
+	after := yamlValue(file.AfterContent, "version")
+
+	change := &semantic.SemanticChange{
+		Type:        "build",
+		Scope:       "helm",
+		Description: fmt.Sprintf("update %s chart metadata", chart),
+		Intent:      "Chart maintenance",
+		Impact:      "Chart metadata changes",
+		Files:       []string{file.Path},
+		Confidence:  0.7,
+		Reasoning:   "Chart.yaml changed",
+		Metadata:    map[string]string{"file_type": "helm-chart"},
+	}
+
+	switch {
+	case file.ChangeType == "added":
+		change.Type = "feat"
+		change.Description = fmt.Sprintf("add %s Helm chart", chart)
+		change.Intent = "Package a new application"
+		change.Impact = "New chart available"
+		change.Confidence = 0.9
+	case after != "" && after != before:
+		change.Description = fmt.Sprintf("bump %s chart to %s", chart, after)
+		change.Impact = "New chart release"
+		change.Confidence = 0.9
+		change.Metadata["chart_version"] = after
+	}
+
+	return change
+}
+
+// analyzeValues classifies changes to a Helm values file
+func (k *KubernetesPlugin) analyzeValues(file semantic.FileChange) *semantic.SemanticChange {
+	chart := chartName(file.Path)
+	before := yamlValue(file.BeforeContent, "tag")
+	after := yamlValue(file.AfterContent, "tag")
+
+	if after != "" && after != before {
+		return &semantic.SemanticChange{
+			Type:        "build",
+			Scope:       "deps",
+			Description: fmt.Sprintf("bump %s image tag to %s", chart, after),
+			Intent:      "Deploy a new image version",
+			Impact:      "Workloads roll out the new image",
+			Files:       []string{file.Path},
+			Confidence:  0.85,
+			Reasoning:   fmt.Sprintf("image tag changed from %q to %q", before, after),
+			Metadata:    map[string]string{"file_type": "helm-values"},
+		}
+	}
+
+	return &semantic.SemanticChange{
+		Type:        "chore",
+		Scope:       "helm",
+		Description: fmt.Sprintf("update %s chart values", chart),
+		Intent:      "Deployment configuration change",
+		Impact:      "Rendered manifests change on next release",
+		Files:       []string{file.Path},
+		Confidence:  0.7,
+		Reasoning:   "Helm values changed",
+		Metadata:    map[string]string{"file_type": "helm-values"},
+	}
+}
+
+// isHelmFile reports whether path belongs to a Helm chart
+func isHelmFile(path string) bool {
+	base := filepath.Base(path)
+	return base == "Chart.yaml" || isValuesFile(path) || strings.Contains(filepath.ToSlash(path), "/templates/")
+}
+
+// isValuesFile matches values.yaml and environment variants like values-prod.yaml
+func isValuesFile(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasPrefix(base, "values") && (strings.HasSuffix(base, ".yaml") || strings.HasSuffix(base, ".yml"))
+}
+
+// chartName returns the directory name of the chart that owns path
+func chartName(path string) string {
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) == "templates" {
+		dir = filepath.Dir(dir)
+	}
+	if dir == "." {
+		return "chart"
+	}
+	return filepath.Base(dir)
+}
+
+// yamlValue returns the last value of a "key: value" line in content
+func yamlValue(content, key string) string {
+	value := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(trimmed, key+":"); ok {
+			value = strings.Trim(strings.TrimSpace(rest), `"'`)
+		}
+	}
+	return value
+}
+
+// describeBumps renders one or more image tag changes
+func describeBumps(bumps []imageBump) string {
+	if len(bumps) == 1 {
+		return fmt.Sprintf("bump %s image to %s", bumps[0].repository, bumps[0].tag)
+	}
+	return fmt.Sprintf("bump %d container images", len(bumps))
+}
+
+// describeResources renders resources as "Deployment api, Service api"
+func describeResources(resources []resource, fallback string) string {
+	if len(resources) == 0 {
+		return fallback + " manifest"
+	}
+	parts := make([]string, 0, len(resources))
+	for _, r := range resources {
+		if r.name != "" {
+			parts = append(parts, r.kind+" "+r.name)
+		} else {
+			parts = append(parts, r.kind)
+		}
+	}
+	if len(parts) > 3 {
+		return fmt.Sprintf("%s and %d more resources", strings.Join(parts[:2], ", "), len(parts)-2)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// securityResources returns the sorted security-relevant kinds in either version
+func securityResources(before, after manifest) []string {
+	seen := make(map[string]bool)
+	for _, m := range []manifest{before, after} {
+		for _, r := range m.resources {
+			if securityKinds[r.kind] {
+				seen[r.kind] = true
+			}
+		}
+	}
+	kinds := make([]string, 0, len(seen))
+	for kind := range seen {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// newResources returns resources present after but not before
+func newResources(before, after []resource) []resource {
+	existing := make(map[resource]bool, len(before))
+	for _, r := range before {
+		existing[r] = true
+	}
+	var added []resource
+	for _, r := range after {
+		if !existing[r] {
+			added = append(added, r)
+		}
+	}
+	return added
+}
+
+// manifestName picks a short name for the manifest being changed
+func manifestName(file semantic.FileChange, manifests ...manifest) string {
+	for _, m := range manifests {
+		for _, r := range m.resources {
+			if r.name != "" {
+				return r.name
+			}
+		}
+	}
+	return strings.TrimSuffix(filepath.Base(file.Path), filepath.Ext(file.Path))
+}
@@ -0,0 +1,153 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+func TestKubernetesPlugin(t *testing.T) {
+	plugin := NewKubernetesPlugin()
+
+	t.Run("can analyze manifests and charts", func(t *testing.T) {
+		tests := []struct {
+			file     semantic.FileChange
+			expected bool
+		}{
+			{semantic.FileChange{Path: "deploy/api.yaml", AfterContent: "apiVersion: apps/v1\nkind: Deployment\n"}, true},
+			{semantic.FileChange{Path: "charts/api/values-prod.yaml", AfterContent: "replicaCount: 2\n"}, true},
+			{semantic.FileChange{Path: "charts/api/templates/service.yaml", AfterContent: "{{ .Values.x }}\n"}, true},
+			{semantic.FileChange{Path: ".github/workflows/ci.yml", AfterContent: "on: push\n"}, false},
+			{semantic.FileChange{Path: "main.go", AfterContent: "kind: x"}, false},
+		}
+		for _, tt := range tests {
+			if got := plugin.CanAnalyze(tt.file); got != tt.expected {
+				t.Errorf("CanAnalyze(%s) = %v, expected %v", tt.file.Path, got, tt.expected)
+			}
+		}
+	})
+
+	tests := []struct {
+		name      string
+		file      semantic.FileChange
+		wantType  string
+		wantScope string
+		wantDesc  string
+	}{
+		{
+			name: "image tag bump",
+			file: semantic.FileChange{
+				Path:          "deploy/api.yaml",
+				ChangeType:    "modified",
+				BeforeContent: "        image: registry.example.com:5000/team/api:1.4.0\n",
+				AfterContent:  "        image: registry.example.com:5000/team/api:1.5.0\n",
+			},
+			wantType:  "build",
+			wantScope: "deps",
+			wantDesc:  "bump registry.example.com:5000/team/api image to 1.5.0",
+		},
+		{
+			name: "new deployment and service",
+			file: semantic.FileChange{
+				Path:       "deploy/worker.yaml",
+				ChangeType: "added",
+				AfterContent: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: worker\nspec:\n  replicas: 2\n" +
+					"---\napiVersion: v1\nkind: Service\nmetadata:\n  name: worker\n",
+			},
+			wantType:  "feat",
+			wantScope: "k8s",
+			wantDesc:  "add Deployment worker, Service worker",
+		},
+		{
+			name: "replica change",
+			file: semantic.FileChange{
+				Path:          "deploy/api.yaml",
+				ChangeType:    "modified",
+				BeforeContent: "  replicas: 2\n",
+				AfterContent:  "  replicas: 4\n",
+			},
+			wantType:  "chore",
+			wantScope: "k8s",
+			wantDesc:  "scale api to 4 replicas",
+		},
+		{
+			name: "resource limits",
+			file: semantic.FileChange{
+				Path:          "deploy/api.yaml",
+				ChangeType:    "modified",
+				BeforeContent: "              memory: 256Mi\n",
+				AfterContent:  "              memory: 512Mi\n",
+			},
+			wantType:  "perf",
+			wantScope: "k8s",
+		},
+		{
+			name: "rbac change",
+			file: semantic.FileChange{
+				Path:         "deploy/rbac.yaml",
+				ChangeType:   "added",
+				AfterContent: "apiVersion: rbac.authorization.k8s.io/v1\nkind: Role\nmetadata:\n  name: reader\n",
+			},
+			wantType:  "feat",
+			wantScope: "security",
+			wantDesc:  "add Role",
+		},
+		{
+			name: "values tag bump",
+			file: semantic.FileChange{
+				Path:          "charts/api/values.yaml",
+				ChangeType:    "modified",
+				BeforeContent: "  tag: \"1.4.0\"\n",
+				AfterContent:  "  tag: \"1.5.0\"\n",
+			},
+			wantType:  "build",
+			wantScope: "deps",
+			wantDesc:  "bump api image tag to 1.5.0",
+		},
+		{
+			name: "chart version bump",
+			file: semantic.FileChange{
+				Path:          "charts/api/Chart.yaml",
+				ChangeType:    "modified",
+				BeforeContent: "version: 0.3.0\n",
+				AfterContent:  "version: 0.4.0\n",
+			},
+			wantType:  "build",
+			wantScope: "helm",
+			wantDesc:  "bump api chart to 0.4.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := plugin.AnalyzeFile(context.Background(), tt.file, semantic.AnalysisContext{})
+			if err != nil {
+				t.Fatalf("AnalyzeFile() error = %v", err)
+			}
+			if change.Type != tt.wantType {
+				t.Errorf("Type = %s, expected %s", change.Type, tt.wantType)
+			}
+			if change.Scope != tt.wantScope {
+				t.Errorf("Scope = %s, expected %s", change.Scope, tt.wantScope)
+			}
+			if tt.wantDesc != "" && change.Description != tt.wantDesc {
+				t.Errorf("Description = %q, expected %q", change.Description, tt.wantDesc)
+			}
+		})
+	}
+
+	t.Run("values only changeset", func(t *testing.T) {
+		analysisCtx := semantic.AnalysisContext{Files: []semantic.FileChange{
+			{Path: "charts/api/values.yaml"},
+			{Path: "charts/api/values-prod.yaml"},
+		}}
+		change, err := plugin.AnalyzeProject(context.Background(), analysisCtx)
+		if err != nil {
+			t.Fatalf("AnalyzeProject() error = %v", err)
+		}
+		if change == nil || change.Scope != "api" || change.Type != "chore" {
+			t.Errorf("expected values-only chore for chart api, got %+v", change)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+// Package kubernetes - Line-based manifest parsing tolerant of diff fragments
+package kubernetes
+
+import (
+	"sort"
+	"strings"
+)
+
+// resource identifies a Kubernetes object by kind and name
+type resource struct {
+	kind string
+	name string
+}
+
+// manifest holds the facts extracted from manifest content
+type manifest struct {
+	resources        []resource
+	images           map[string]string // repository -> tag
+	replicas         string
+	resourcesChanged bool // cpu/memory requests or limits present
+}
+
+// imageBump is a container image whose tag changed
+type imageBump struct {
+	repository string
+	tag        string
+}
+
+// parseManifest scans manifest lines. Full files and diff fragments are both
+// accepted, so no YAML structure is required beyond "key: value" lines.
+func parseManifest(content string) manifest {
+	m := manifest{images: make(map[string]string)}
+
+	inMetadata := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch {
+		case strings.HasPrefix(trimmed, "kind:") && indent == 0:
+			m.resources = append(m.resources, resource{kind: strings.TrimSpace(strings.TrimPrefix(trimmed, "kind:"))})
+			inMetadata = false
+		case trimmed == "metadata:" && indent == 0:
+			inMetadata = true
+		case strings.HasPrefix(trimmed, "name:") && inMetadata && indent > 0:
+			if n := len(m.resources); n > 0 && m.resources[n-1].name == "" {
+				m.resources[n-1].name = strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "name:")), `"'`)
+			}
+			inMetadata = false
+		case strings.HasPrefix(trimmed, "image:"):
+			repository, tag := splitImage(strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "image:")), `"'`))
+			if repository != "" {
+				m.images[repository] = tag
+			}
+		case strings.HasPrefix(trimmed, "replicas:"):
+			m.replicas = strings.TrimSpace(strings.TrimPrefix(trimmed, "replicas:"))
+		case strings.HasPrefix(trimmed, "cpu:") || strings.HasPrefix(trimmed, "memory:"):
+			m.resourcesChanged = true
+		}
+	}
+
+	return m
+}
+
+// splitImage splits "registry/repo:tag" into repository and tag. Digests are
+// treated as tags so digest pins are reported as bumps too.
+func splitImage(image string) (string, string) {
+	if at := strings.Index(image, "@"); at != -1 {
+		return image[:at], image[at+1:]
+	}
+	// A colon after the last slash separates the tag; earlier colons are registry ports
+	if colon := strings.LastIndex(image, ":"); colon > strings.LastIndex(image, "/") {
+		return image[:colon], image[colon+1:]
+	}
+	return image, "latest"
+}
+
+// imageBumps returns images present in both versions with different tags
+func imageBumps(before, after map[string]string) []imageBump {
+	var bumps []imageBump
+	for repository, tag := range after {
+		if old, ok := before[repository]; ok && old != tag {
+			bumps = append(bumps, imageBump{repository: repository, tag: tag})
+		}
+	}
+	sort.Slice(bumps, func(i, j int) bool { return bumps[i].repository < bumps[j].repository })
+	return bumps
+}
@@ -0,0 +1,130 @@
+// Package migrations - DDL statement recognition for SQL and Liquibase changelogs
+package migrations
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// operations groups the schema operations found in a migration
+type operations struct {
+	destructive []string
+	tables      []string
+	columns     []string
+	indexes     []string
+}
+
+const identifier = "[`\"\\[]?([\\w.]+)[`\"\\]]?"
+
+var (
+	dropTablePattern    = regexp.MustCompile(`(?i)\bDROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?` + identifier)
+	dropColumnPattern   = regexp.MustCompile(`(?i)\bALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?` + identifier + `\s+DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?` + identifier)
+	alterColumnPattern  = regexp.MustCompile(`(?i)\bALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?` + identifier + `\s+ALTER\s+(?:COLUMN\s+)?` + identifier + `\s+(?:SET\s+DATA\s+)?TYPE\b`)
+	modifyColumnPattern = regexp.MustCompile(`(?i)\bALTER\s+TABLE\s+` + identifier + `\s+MODIFY\s+(?:COLUMN\s+)?` + identifier)
+	renameColumnPattern = regexp.MustCompile(`(?i)\bALTER\s+TABLE\s+` + identifier + `\s+RENAME\s+(?:COLUMN\s+)?` + identifier + `\s+TO\s+` + identifier)
+	renameTablePattern  = regexp.MustCompile(`(?i)\bALTER\s+TABLE\s+` + identifier + `\s+RENAME\s+TO\s+` + identifier)
+	truncatePattern     = regexp.MustCompile(`(?i)\bTRUNCATE\s+(?:TABLE\s+)?` + identifier)
+	dropSchemaPattern   = regexp.MustCompile(`(?i)\bDROP\s+(SCHEMA|DATABASE)\s+(?:IF\s+EXISTS\s+)?` + identifier)
+	createTablePattern  = regexp.MustCompile(`(?i)\bCREATE\s+(?:TEMP(?:ORARY)?\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?` + identifier)
+	addColumnPattern    = regexp.MustCompile(`(?i)\bALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?` + identifier + `\s+ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?` + identifier)
+	createIndexPattern  = regexp.MustCompile(`(?i)\bCREATE\s+(?:UNIQUE\s+)?INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?` + identifier + `\s+ON\s+(?:ONLY\s+)?` + identifier)
+
+	// Liquibase change types, matched in XML, YAML and JSON changelogs
+	liquibaseAttr    = `\s*[=:]\s*["']?([\w.]+)`
+	lbDropTable      = regexp.MustCompile(`(?is)dropTable\b[^>}]*?tableName` + liquibaseAttr)
+	lbDropColumn     = regexp.MustCompile(`(?is)dropColumn\b[^>}]*?columnName` + liquibaseAttr)
+	lbModifyDataType = regexp.MustCompile(`(?is)modifyDataType\b[^>}]*?columnName` + liquibaseAttr)
+	lbRenameColumn   = regexp.MustCompile(`(?is)renameColumn\b[^>}]*?oldColumnName` + liquibaseAttr)
+	lbCreateTable    = regexp.MustCompile(`(?is)createTable\b[^>}]*?tableName` + liquibaseAttr)
+	lbAddColumn      = regexp.MustCompile(`(?is)addColumn\b[^>}]*?tableName` + liquibaseAttr)
+	lbCreateIndex    = regexp.MustCompile(`(?is)createIndex\b[^>}]*?indexName` + liquibaseAttr)
+)
+
+// parseOperations recognises schema operations in SQL or Liquibase content
+func parseOperations(content string) operations {
+	content = stripSQLComments(content)
+	var ops operations
+
+	for _, m := range dropTablePattern.FindAllStringSubmatch(content, -1) {
+		ops.destructive = append(ops.destructive, fmt.Sprintf("drop %s table", m[1]))
+	}
+	for _, m := range dropColumnPattern.FindAllStringSubmatch(content, -1) {
+		// ALTER TABLE ... DROP CONSTRAINT/INDEX is not a column drop
+		if kw := strings.ToUpper(m[2]); kw == "CONSTRAINT" || kw == "INDEX" || kw == "DEFAULT" || kw == "NOT" {
+			continue
+		}
+		ops.destructive = append(ops.destructive, fmt.Sprintf("drop %s.%s column", m[1], m[2]))
+	}
+	for _, m := range alterColumnPattern.FindAllStringSubmatch(content, -1) {
+		ops.destructive = append(ops.destructive, fmt.Sprintf("change type of %s.%s", m[1], m[2]))
+	}
+	for _, m := range modifyColumnPattern.FindAllStringSubmatch(content, -1) {
+		ops.destructive = append(ops.destructive, fmt.Sprintf("modify %s.%s column", m[1], m[2]))
+	}
+	for _, m := range renameColumnPattern.FindAllStringSubmatch(content, -1) {
+		ops.destructive = append(ops.destructive, fmt.Sprintf("rename %s.%s to %s", m[1], m[2], m[3]))
+	}
+	for _, m := range renameTablePattern.FindAllStringSubmatch(content, -1) {
+		ops.destructive = append(ops.destructive, fmt.Sprintf("rename %s table to %s", m[1], m[2]))
+	}
+	for _, m := range truncatePattern.FindAllStringSubmatch(content, -1) {
+		ops.destructive = append(ops.destructive, fmt.Sprintf("truncate %s", m[1]))
+	}
+	for _, m := range dropSchemaPattern.FindAllStringSubmatch(content, -1) {
+		ops.destructive = append(ops.destructive, fmt.Sprintf("drop %s %s", strings.ToLower(m[1]), m[2]))
+	}
+	for _, m := range createTablePattern.FindAllStringSubmatch(content, -1) {
+		ops.tables = append(ops.tables, fmt.Sprintf("add %s table", m[1]))
+	}
+	for _, m := range addColumnPattern.FindAllStringSubmatch(content, -1) {
+		// ALTER TABLE ... ADD CONSTRAINT/INDEX is not a new column
+		if kw := strings.ToUpper(m[2]); kw == "CONSTRAINT" || kw == "INDEX" || kw == "PRIMARY" || kw == "FOREIGN" || kw == "UNIQUE" {
+			continue
+		}
+		ops.columns = append(ops.columns, fmt.Sprintf("add %s.%s column", m[1], m[2]))
+	}
+	for _, m := range createIndexPattern.FindAllStringSubmatch(content, -1) {
+		ops.indexes = append(ops.indexes, fmt.Sprintf("add %s index on %s", m[1], m[2]))
+	}
+
+	for _, m := range lbDropTable.FindAllStringSubmatch(content, -1) {
+		ops.destructive = append(ops.destructive, fmt.Sprintf("drop %s table", m[1]))
+	}
+	for _, m := range lbDropColumn.FindAllStringSubmatch(content, -1) {
+		ops.destructive = append(ops.destructive, fmt.Sprintf("drop %s column", m[1]))
+	}
+	for _, m := range lbModifyDataType.FindAllStringSubmatch(content, -1) {
+		ops.destructive = append(ops.destructive, fmt.Sprintf("change type of %s", m[1]))
+	}
+	for _, m := range lbRenameColumn.FindAllStringSubmatch(content, -1) {
+		ops.destructive = append(ops.destructive, fmt.Sprintf("rename %s column", m[1]))
+	}
+	for _, m := range lbCreateTable.FindAllStringSubmatch(content, -1) {
+		ops.tables = append(ops.tables, fmt.Sprintf("add %s table", m[1]))
+	}
+	for _, m := range lbAddColumn.FindAllStringSubmatch(content, -1) {
+		ops.columns = append(ops.columns, fmt.Sprintf("add columns to %s", m[1]))
+	}
+	for _, m := range lbCreateIndex.FindAllStringSubmatch(content, -1) {
+		ops.indexes = append(ops.indexes, fmt.Sprintf("add %s index", m[1]))
+	}
+
+	return ops
+}
+
+// stripSQLComments removes "--" line comments and /* */ block comments, keeping
+// migration tool directives out of the statement patterns
+func stripSQLComments(content string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "--"); idx != -1 {
+			line = line[:idx]
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	return blockComment.ReplaceAllString(sb.String(), " ")
+}
+
+var blockComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
@@ -0,0 +1,230 @@
+// Package migrations provides semantic analysis for SQL and database migration files
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// MigrationPlugin provides semantic analysis for SQL, Flyway, Liquibase and goose migrations
+type MigrationPlugin struct {
+	version string
+}
+
+// NewMigrationPlugin creates a new database migration semantic analyzer plugin
+func NewMigrationPlugin() *MigrationPlugin {
+	return &MigrationPlugin{
+		version: "1.0.0",
+	}
+}
+
+// Name returns the plugin name
+func (m *MigrationPlugin) Name() string {
+	return "migrations"
+}
+
+// Version returns the plugin version
+func (m *MigrationPlugin) Version() string {
+	return m.version
+}
+
+// SupportedExtensions returns file extensions this plugin supports
+func (m *MigrationPlugin) SupportedExtensions() []string {
+	return []string{".sql"}
+}
+
+// SupportedFilePatterns returns file patterns this plugin supports
+func (m *MigrationPlugin) SupportedFilePatterns() []string {
+	return []string{"migrations/*", "db/migrations/*", "db/changelog/*"}
+}
+
+// CanAnalyze determines if this plugin can analyze the given file
+func (m *MigrationPlugin) CanAnalyze(file semantic.FileChange) bool {
+	if strings.HasSuffix(strings.ToLower(file.Path), ".sql") {
+		return true
+	}
+	return isLiquibaseChangelog(file.Path)
+}
+
+// AnalyzeFile analyzes a single migration file for schema changes
+func (m *MigrationPlugin) AnalyzeFile(_ context.Context, file semantic.FileChange, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	switch file.ChangeType {
+	case "added", "modified", "renamed":
+	case "deleted":
+		return &semantic.SemanticChange{
+			Type:        "chore",
+			Scope:       "db",
+			Description: fmt.Sprintf("remove migration %s", filepath.Base(file.Path)),
+			Intent:      "Migration history cleanup",
+			Impact:      "Environments that have not applied it will no longer run it",
+			Files:       []string{file.Path},
+			Confidence:  0.7,
+			Reasoning:   "Migration file deleted",
+			Metadata:    map[string]string{"file_type": "migration"},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown change type: %s", file.ChangeType)
+	}
+
+	if isDownMigration(file.Path) {
+		return &semantic.SemanticChange{
+			Type:        "chore",
+			Scope:       "db",
+			Description: fmt.Sprintf("add rollback for %s", migrationName(file.Path)),
+			Intent:      "Make the migration reversible",
+			Impact:      "Only runs when migrating down",
+			Files:       []string{file.Path},
+			Confidence:  0.8,
+			Reasoning:   "Down migration changes are not applied on deploy",
+			Metadata:    map[string]string{"file_type": "migration"},
+		}, nil
+	}
+
+	ops := parseOperations(upSection(file.AfterContent))
+	change := &semantic.SemanticChange{
+		Scope:    "db",
+		Files:    []string{file.Path},
+		Metadata: map[string]string{"file_type": "migration", "tool": migrationTool(file)},
+	}
+
+	detectBreaking := analysisCtx.Config["detect_destructive_ddl"] != "false"
+	switch {
+	case detectBreaking && len(ops.destructive) > 0:
+		change.Type = "feat"
+		change.BreakingChange = true
+		change.Description = summarize(ops.destructive)
+		change.Intent = "Schema change that removes or rewrites existing data"
+		change.Impact = "Existing data or dependent queries may break"
+		change.Confidence = 0.9
+		change.Reasoning = fmt.Sprintf("Destructive DDL detected: %s", strings.Join(ops.destructive, "; "))
+	case len(ops.tables) > 0:
+		change.Type = "feat"
+		change.Description = summarize(ops.tables)
+		change.Intent = "Extend the data model"
+		change.Impact = "New tables available"
+		change.Confidence = 0.9
+		change.Reasoning = fmt.Sprintf("%d tables created", len(ops.tables))
+	case len(ops.columns) > 0:
+		change.Type = "feat"
+		change.Description = summarize(ops.columns)
+		change.Intent = "Extend the data model"
+		change.Impact = "New columns available"
+		change.Confidence = 0.85
+		change.Reasoning = fmt.Sprintf("%d columns added", len(ops.columns))
+	case len(ops.indexes) > 0:
+		change.Type = "perf"
+		change.Description = summarize(ops.indexes)
+		change.Intent = "Speed up queries"
+		change.Impact = "Faster reads; index build may lock the table"
+		change.Confidence = 0.85
+		change.Reasoning = fmt.Sprintf("%d indexes created", len(ops.indexes))
+	default:
+		change.Type = "chore"
+		change.Description = fmt.Sprintf("update migration %s", migrationName(file.Path))
+		change.Intent = "Data or schema maintenance"
+		change.Impact = "Migration runs on next deploy"
+		change.Confidence = 0.5
+		change.Reasoning = "No schema operations recognised"
+	}
+
+	return change, nil
+}
+
+// AnalyzeProject has no project-level migration analysis; per-file results are consolidated
+func (m *MigrationPlugin) AnalyzeProject(_ context.Context, _ semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	return nil, nil
+}
+
+// DefaultConfig returns the default configuration for the plugin
+func (m *MigrationPlugin) DefaultConfig() map[string]string {
+	return map[string]string{
+		"detect_destructive_ddl": "true",
+	}
+}
+
+// ValidateConfig validates plugin configuration
+func (m *MigrationPlugin) ValidateConfig(config map[string]string) error {
+	if value, exists := config["detect_destructive_ddl"]; exists && value != "true" && value != "false" {
+		return fmt.Errorf("detect_destructive_ddl must be 'true' or 'false', got %s", value)
+	}
+	return nil
+}
+
+var (
+	flywayPattern       = regexp.MustCompile(`^[VUR]\d*(?:[._]\d+)*__.+\.sql$`)
+	gooseDownPattern    = regexp.MustCompile(`(?im)^\s*--\s*\+goose\s+down\b`)
+	dbmateDownPattern   = regexp.MustCompile(`(?im)^\s*--\s*migrate:down\b`)
+	numberPrefixPattern = regexp.MustCompile(`^\d+[_-]`)
+)
+
+// isLiquibaseChangelog matches Liquibase changelog files in any supported format
+func isLiquibaseChangelog(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	ext := filepath.Ext(base)
+	if ext != ".xml" && ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return false
+	}
+	return strings.Contains(base, "changelog") || strings.Contains(filepath.ToSlash(path), "db/changelog/")
+}
+
+// isDownMigration matches golang-migrate style rollback files
+func isDownMigration(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	return strings.HasSuffix(base, ".down.sql") || strings.HasPrefix(base, "u") && flywayPattern.MatchString(filepath.Base(path))
+}
+
+// upSection drops the rollback part of goose and dbmate migrations so their
+// DROP statements are not mistaken for destructive changes
+func upSection(content string) string {
+	for _, pattern := range []*regexp.Regexp{gooseDownPattern, dbmateDownPattern} {
+		if loc := pattern.FindStringIndex(content); loc != nil {
+			return content[:loc[0]]
+		}
+	}
+	return content
+}
+
+// migrationTool names the migration framework a file belongs to
+func migrationTool(file semantic.FileChange) string {
+	base := filepath.Base(file.Path)
+	switch {
+	case isLiquibaseChangelog(file.Path):
+		return "liquibase"
+	case flywayPattern.MatchString(base):
+		return "flyway"
+	case strings.Contains(strings.ToLower(file.AfterContent), "+goose"):
+		return "goose"
+	case strings.Contains(file.AfterContent, "migrate:up"):
+		return "dbmate"
+	case strings.HasSuffix(base, ".up.sql"):
+		return "golang-migrate"
+	default:
+		return "sql"
+	}
+}
+
+// migrationName strips version prefixes and extensions from a migration file name
+func migrationName(path string) string {
+	name := filepath.Base(path)
+	if idx := strings.Index(name, "__"); idx != -1 {
+		name = name[idx+2:]
+	}
+	name = numberPrefixPattern.ReplaceAllString(name, "")
+	for _, suffix := range []string{".up.sql", ".down.sql", ".sql", ".xml", ".yaml", ".yml", ".json"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return name
+}
+
+// summarize joins up to two operations, collapsing the rest into a count
+func summarize(ops []string) string {
+	if len(ops) <= 2 {
+		return strings.Join(ops, " and ")
+	}
+	return fmt.Sprintf("%s and %d more schema changes", ops[0], len(ops)-1)
+}
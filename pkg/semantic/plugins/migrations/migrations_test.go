@@ -0,0 +1,157 @@
+package migrations
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+func TestMigrationPlugin(t *testing.T) {
+	plugin := NewMigrationPlugin()
+	ctx := semantic.AnalysisContext{Config: plugin.DefaultConfig()}
+
+	t.Run("can analyze migrations", func(t *testing.T) {
+		tests := []struct {
+			path     string
+			expected bool
+		}{
+			{"db/migrations/V3__add_orders.sql", true},
+			{"migrations/20240101_init.up.sql", true},
+			{"src/main/resources/db/changelog/db.changelog-master.xml", true},
+			{"config/app.yaml", false},
+		}
+		for _, tt := range tests {
+			if got := plugin.CanAnalyze(semantic.FileChange{Path: tt.path}); got != tt.expected {
+				t.Errorf("CanAnalyze(%s) = %v, expected %v", tt.path, got, tt.expected)
+			}
+		}
+	})
+
+	tests := []struct {
+		name         string
+		file         semantic.FileChange
+		wantType     string
+		wantBreaking bool
+		wantDesc     string
+	}{
+		{
+			name: "new table",
+			file: semantic.FileChange{
+				Path:         "db/migrations/V3__add_orders.sql",
+				ChangeType:   "added",
+				AfterContent: "CREATE TABLE IF NOT EXISTS orders (\n  id BIGSERIAL PRIMARY KEY\n);\n",
+			},
+			wantType: "feat",
+			wantDesc: "add orders table",
+		},
+		{
+			name: "drop column is breaking",
+			file: semantic.FileChange{
+				Path:         "migrations/0007_drop_legacy.up.sql",
+				ChangeType:   "added",
+				AfterContent: "ALTER TABLE users DROP COLUMN legacy_id;\n",
+			},
+			wantType:     "feat",
+			wantBreaking: true,
+			wantDesc:     "drop users.legacy_id column",
+		},
+		{
+			name: "column type change is breaking",
+			file: semantic.FileChange{
+				Path:         "migrations/0008.sql",
+				ChangeType:   "added",
+				AfterContent: "ALTER TABLE users ALTER COLUMN age TYPE bigint;\n",
+			},
+			wantType:     "feat",
+			wantBreaking: true,
+			wantDesc:     "change type of users.age",
+		},
+		{
+			name: "index addition",
+			file: semantic.FileChange{
+				Path:         "migrations/0009_idx.sql",
+				ChangeType:   "added",
+				AfterContent: "CREATE INDEX CONCURRENTLY idx_orders_user ON orders (user_id);\n",
+			},
+			wantType: "perf",
+			wantDesc: "add idx_orders_user index on orders",
+		},
+		{
+			name: "goose down section is ignored",
+			file: semantic.FileChange{
+				Path:       "migrations/00010_accounts.sql",
+				ChangeType: "added",
+				AfterContent: "-- +goose Up\nCREATE TABLE accounts (id int);\n" +
+					"-- +goose Down\nDROP TABLE accounts;\n",
+			},
+			wantType: "feat",
+			wantDesc: "add accounts table",
+		},
+		{
+			name: "drop constraint is not a column drop",
+			file: semantic.FileChange{
+				Path:         "migrations/0011.sql",
+				ChangeType:   "added",
+				AfterContent: "ALTER TABLE orders DROP CONSTRAINT orders_fk;\n",
+			},
+			wantType: "chore",
+		},
+		{
+			name: "golang-migrate down file",
+			file: semantic.FileChange{
+				Path:         "migrations/0007_drop_legacy.down.sql",
+				ChangeType:   "added",
+				AfterContent: "DROP TABLE users;\n",
+			},
+			wantType: "chore",
+			wantDesc: "add rollback for drop_legacy",
+		},
+		{
+			name: "liquibase drop table",
+			file: semantic.FileChange{
+				Path:         "db/changelog/changes/004.xml",
+				ChangeType:   "added",
+				AfterContent: "<changeSet id=\"4\" author=\"dev\">\n  <dropTable tableName=\"audit_log\"/>\n</changeSet>\n",
+			},
+			wantType:     "feat",
+			wantBreaking: true,
+			wantDesc:     "drop audit_log table",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := plugin.AnalyzeFile(context.Background(), tt.file, ctx)
+			if err != nil {
+				t.Fatalf("AnalyzeFile() error = %v", err)
+			}
+			if change.Type != tt.wantType {
+				t.Errorf("Type = %s, expected %s", change.Type, tt.wantType)
+			}
+			if change.Scope != "db" {
+				t.Errorf("Scope = %s, expected db", change.Scope)
+			}
+			if change.BreakingChange != tt.wantBreaking {
+				t.Errorf("BreakingChange = %v, expected %v", change.BreakingChange, tt.wantBreaking)
+			}
+			if tt.wantDesc != "" && change.Description != tt.wantDesc {
+				t.Errorf("Description = %q, expected %q", change.Description, tt.wantDesc)
+			}
+		})
+	}
+
+	t.Run("destructive detection can be disabled", func(t *testing.T) {
+		file := semantic.FileChange{Path: "m.sql", ChangeType: "added", AfterContent: "DROP TABLE x;"}
+		change, err := plugin.AnalyzeFile(context.Background(), file, semantic.AnalysisContext{
+			Config: map[string]string{"detect_destructive_ddl": "false"},
+		})
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+		if change.BreakingChange || !strings.HasPrefix(change.Description, "update migration") {
+			t.Errorf("expected non-breaking change, got %+v", change)
+		}
+	})
+}
@@ -0,0 +1,299 @@
+// Package protobuf provides semantic analysis for Protocol Buffers and gRPC schemas
+package protobuf
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+// ProtoPlugin provides semantic analysis for .proto files with breaking-change detection
+type ProtoPlugin struct {
+	version string
+}
+
+// NewProtoPlugin creates a new Protocol Buffers semantic analyzer plugin
+func NewProtoPlugin() *ProtoPlugin {
+	return &ProtoPlugin{
+		version: "1.0.0",
+	}
+}
+
+// Name returns the plugin name
+func (p *ProtoPlugin) Name() string {
+	return "protobuf"
+}
+
+// Version returns the plugin version
+func (p *ProtoPlugin) Version() string {
+	return p.version
+}
+
+// SupportedExtensions returns file extensions this plugin supports
+func (p *ProtoPlugin) SupportedExtensions() []string {
+	return []string{".proto"}
+}
+
+// SupportedFilePatterns returns file patterns this plugin supports
+func (p *ProtoPlugin) SupportedFilePatterns() []string {
+	return []string{"*.proto", "proto/*", "api/*"}
+}
+
+// CanAnalyze determines if this plugin can analyze the given file
+func (p *ProtoPlugin) CanAnalyze(file semantic.FileChange) bool {
+	return strings.HasSuffix(file.Path, ".proto")
+}
+
+// AnalyzeFile compares the schema before and after the change
+func (p *ProtoPlugin) AnalyzeFile(_ context.Context, file semantic.FileChange, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	switch file.ChangeType {
+	case "added", "deleted", "modified", "renamed":
+	default:
+		return nil, fmt.Errorf("unknown change type: %s", file.ChangeType)
+	}
+
+	before := parseSchema(file.BeforeContent)
+	after := parseSchema(file.AfterContent)
+	if file.ChangeType == "added" {
+		before = parseSchema("")
+	}
+	if file.ChangeType == "deleted" {
+		after = parseSchema("")
+	}
+
+	diff := compareSchemas(before, after)
+	services := affectedServices(before, after)
+
+	change := &semantic.SemanticChange{
+		Scope: "api",
+		Files: []string{file.Path},
+		Metadata: map[string]string{
+			"file_type": "protobuf",
+			"package":   after.Package,
+			"services":  strings.Join(services, ","),
+		},
+	}
+	if change.Metadata["package"] == "" {
+		change.Metadata["package"] = before.Package
+	}
+
+	// Whole files are described by the services they declare
+	if len(services) > 0 && len(diff.breaking)+len(diff.additions) > 2 {
+		switch file.ChangeType {
+		case "added":
+			diff.additions = []string{fmt.Sprintf("add %s API", strings.Join(services, ", "))}
+		case "deleted":
+			diff.breaking = []string{fmt.Sprintf("remove %s API", strings.Join(services, ", "))}
+		}
+	}
+
+	detectBreaking := analysisCtx.Config["detect_breaking"] != "false"
+	switch {
+	case detectBreaking && len(diff.breaking) > 0:
+		change.Type = "feat"
+		change.BreakingChange = true
+		change.Description = summarize(diff.breaking, services)
+		change.Intent = "Change the API contract"
+		change.Impact = "Existing clients or stored messages may no longer be compatible"
+		change.Confidence = 0.9
+		change.Reasoning = fmt.Sprintf("Wire-incompatible schema changes: %s", strings.Join(diff.breaking, "; "))
+	case len(diff.additions) > 0:
+		change.Type = "feat"
+		change.Description = summarize(diff.additions, services)
+		change.Intent = "Extend the API"
+		change.Impact = "New API surface available to clients"
+		change.Confidence = 0.85
+		change.Reasoning = fmt.Sprintf("%d schema additions", len(diff.additions))
+	case len(diff.breaking) > 0:
+		change.Type = "refactor"
+		change.Description = summarize(diff.breaking, services)
+		change.Intent = "Restructure the API schema"
+		change.Impact = "Schema changed; breaking-change detection is disabled"
+		change.Confidence = 0.7
+		change.Reasoning = strings.Join(diff.breaking, "; ")
+	default:
+		change.Type = "docs"
+		change.Description = fmt.Sprintf("update %s schema", schemaName(file.Path, services))
+		change.Intent = "Schema documentation or options"
+		change.Impact = "No change to the wire format"
+		change.Confidence = 0.6
+		change.Reasoning = "No declarations added, removed or renumbered"
+		if strings.Contains(file.AfterContent+file.BeforeContent, "option ") {
+			change.Type = "chore"
+		}
+	}
+
+	return change, nil
+}
+
+// AnalyzeProject has no project-level schema analysis; per-file results are consolidated
+func (p *ProtoPlugin) AnalyzeProject(_ context.Context, _ semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	return nil, nil
+}
+
+// DefaultConfig returns the default configuration for the plugin
+func (p *ProtoPlugin) DefaultConfig() map[string]string {
+	return map[string]string{
+		"detect_breaking": "true",
+	}
+}
+
+// ValidateConfig validates plugin configuration
+func (p *ProtoPlugin) ValidateConfig(config map[string]string) error {
+	if value, exists := config["detect_breaking"]; exists && value != "true" && value != "false" {
+		return fmt.Errorf("detect_breaking must be 'true' or 'false', got %s", value)
+	}
+	return nil
+}
+
+// schemaDiff lists the differences between two schemas as short descriptions
+type schemaDiff struct {
+	breaking  []string
+	additions []string
+}
+
+// compareSchemas reports removed, renumbered and retyped declarations as
+// breaking and new declarations as additions. Removing a field is allowed
+// when its number is reserved, matching buf's FIELD_NO_DELETE_UNLESS_NUMBER_RESERVED.
+func compareSchemas(before, after *schema) schemaDiff {
+	var diff schemaDiff
+
+	for _, name := range sortedKeys(before.Services) {
+		if !after.Services[name] {
+			diff.breaking = append(diff.breaking, fmt.Sprintf("remove %s service", name))
+		}
+	}
+	for _, name := range sortedKeys(before.Messages) {
+		if !after.Messages[name] {
+			diff.breaking = append(diff.breaking, fmt.Sprintf("remove %s message", name))
+		}
+	}
+
+	for _, key := range sortedKeys(before.RPCs) {
+		service, method := splitKey(key)
+		if before.Services[service] && !after.Services[service] {
+			continue // reported with the service
+		}
+		if signature, ok := after.RPCs[key]; !ok {
+			diff.breaking = append(diff.breaking, fmt.Sprintf("remove %s RPC%s", method, onService(" from ", service)))
+		} else if signature != before.RPCs[key] {
+			diff.breaking = append(diff.breaking, fmt.Sprintf("change %s RPC signature%s", method, onService(" in ", service)))
+		}
+	}
+
+	for _, key := range sortedKeys(before.Fields) {
+		old := before.Fields[key]
+		if before.Messages[old.Message] && !after.Messages[old.Message] {
+			continue // reported with the message
+		}
+		current, ok := after.Fields[key]
+		switch {
+		case !ok:
+			if renamed, found := after.fieldByNumber(old.Message, old.Number); found {
+				diff.breaking = append(diff.breaking, fmt.Sprintf("rename %s to %s", qualified(old), renamed.Name))
+			} else if !after.isReserved(old.Message, old.Number) {
+				diff.breaking = append(diff.breaking, fmt.Sprintf("remove %s field", qualified(old)))
+			}
+		case current.Number != old.Number:
+			diff.breaking = append(diff.breaking, fmt.Sprintf("renumber %s from %d to %d", qualified(old), old.Number, current.Number))
+		case current.Type != old.Type:
+			diff.breaking = append(diff.breaking, fmt.Sprintf("change %s type to %s", qualified(old), current.Type))
+		}
+	}
+
+	for _, name := range sortedKeys(after.Services) {
+		if !before.Services[name] {
+			diff.additions = append(diff.additions, fmt.Sprintf("add %s service", name))
+		}
+	}
+	for _, key := range sortedKeys(after.RPCs) {
+		service, method := splitKey(key)
+		if _, ok := before.RPCs[key]; !ok && !(after.Services[service] && !before.Services[service]) {
+			diff.additions = append(diff.additions, fmt.Sprintf("add %s RPC%s", method, onService(" to ", service)))
+		}
+	}
+	for _, name := range sortedKeys(after.Messages) {
+		if !before.Messages[name] {
+			diff.additions = append(diff.additions, fmt.Sprintf("add %s message", name))
+		}
+	}
+	for _, key := range sortedKeys(after.Fields) {
+		f := after.Fields[key]
+		if _, ok := before.Fields[key]; ok || (after.Messages[f.Message] && !before.Messages[f.Message]) {
+			continue
+		}
+		if _, renamed := before.fieldByNumber(f.Message, f.Number); renamed {
+			continue
+		}
+		diff.additions = append(diff.additions, fmt.Sprintf("add %s field", qualified(f)))
+	}
+
+	return diff
+}
+
+// affectedServices lists services declared in either version of the file
+func affectedServices(before, after *schema) []string {
+	seen := make(map[string]bool)
+	for name := range before.Services {
+		seen[name] = true
+	}
+	for name := range after.Services {
+		seen[name] = true
+	}
+	return sortedKeys(seen)
+}
+
+// summarize joins up to two changes, otherwise names the affected services
+func summarize(changes, services []string) string {
+	if len(changes) <= 2 {
+		return strings.Join(changes, " and ")
+	}
+	if len(services) > 0 {
+		return fmt.Sprintf("update %s API (%d schema changes)", strings.Join(services, ", "), len(changes))
+	}
+	return fmt.Sprintf("%s and %d more schema changes", changes[0], len(changes)-1)
+}
+
+// schemaName prefers the service names over the file name
+func schemaName(path string, services []string) string {
+	if len(services) > 0 {
+		return strings.Join(services, ", ")
+	}
+	return strings.TrimSuffix(filepath.Base(path), ".proto")
+}
+
+// qualified names a field with its message when the message is known
+func qualified(f field) string {
+	if f.Message == "" {
+		return f.Name
+	}
+	return f.Message + "." + f.Name
+}
+
+// splitKey splits "Parent.name" at the last dot
+func splitKey(key string) (string, string) {
+	idx := strings.LastIndex(key, ".")
+	return key[:idx], key[idx+1:]
+}
+
+// onService phrases an optional service name, e.g. " from UserService"
+func onService(preposition, service string) string {
+	if service == "" {
+		return ""
+	}
+	return preposition + service
+}
+
+// sortedKeys returns map keys in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,211 @@
+package protobuf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
+)
+
+const userProto = `syntax = "proto3";
+package acme.user.v1;
+
+service UserService {
+  rpc GetUser(GetUserRequest) returns (User);
+  rpc ListUsers(ListUsersRequest) returns (stream User);
+}
+
+message User {
+  string id = 1;
+  string email = 2; // primary contact
+  Role role = 3;
+  oneof contact {
+    string phone = 4;
+  }
+  message Address {
+    string city = 1;
+  }
+}
+
+enum Role {
+  ROLE_UNSPECIFIED = 0;
+  ROLE_ADMIN = 1;
+}
+`
+
+func TestProtoPlugin(t *testing.T) {
+	plugin := NewProtoPlugin()
+	ctx := semantic.AnalysisContext{Config: plugin.DefaultConfig()}
+
+	t.Run("parse schema", func(t *testing.T) {
+		s := parseSchema(userProto)
+		if s.Package != "acme.user.v1" {
+			t.Errorf("Package = %q", s.Package)
+		}
+		for _, name := range []string{"User", "User.Address", "Role"} {
+			if !s.Messages[name] {
+				t.Errorf("expected declaration %s", name)
+			}
+		}
+		if s.RPCs["UserService.ListUsers"] != "(ListUsersRequest) returns (stream User)" {
+			t.Errorf("ListUsers signature = %q", s.RPCs["UserService.ListUsers"])
+		}
+		if f := s.Fields["User.phone"]; f.Number != 4 || f.Type != "string" {
+			t.Errorf("oneof field = %+v, expected User.phone = 4", f)
+		}
+		if f := s.Fields["User.Address.city"]; f.Number != 1 {
+			t.Errorf("nested field = %+v", f)
+		}
+		if f := s.Fields["Role.ROLE_ADMIN"]; f.Number != 1 || f.Type != "" {
+			t.Errorf("enum value = %+v", f)
+		}
+	})
+
+	tests := []struct {
+		name         string
+		file         semantic.FileChange
+		wantType     string
+		wantBreaking bool
+		wantDesc     string
+	}{
+		{
+			name:     "new service file",
+			file:     semantic.FileChange{Path: "api/user/v1/user.proto", ChangeType: "added", AfterContent: userProto},
+			wantType: "feat",
+			wantDesc: "add UserService API",
+		},
+		{
+			name: "new rpc",
+			file: semantic.FileChange{
+				Path:          "api/user.proto",
+				ChangeType:    "modified",
+				BeforeContent: userProto,
+				AfterContent:  replace(userProto, "  rpc GetUser", "  rpc DeleteUser(DeleteUserRequest) returns (Empty);\n  rpc GetUser"),
+			},
+			wantType: "feat",
+			wantDesc: "add DeleteUser RPC to UserService",
+		},
+		{
+			name: "removed rpc",
+			file: semantic.FileChange{
+				Path:          "api/user.proto",
+				ChangeType:    "modified",
+				BeforeContent: userProto,
+				AfterContent:  replace(userProto, "  rpc ListUsers(ListUsersRequest) returns (stream User);\n", ""),
+			},
+			wantType:     "feat",
+			wantBreaking: true,
+			wantDesc:     "remove ListUsers RPC from UserService",
+		},
+		{
+			name: "renumbered field in diff fragment",
+			file: semantic.FileChange{
+				Path:          "api/user.proto",
+				ChangeType:    "modified",
+				BeforeContent: "  string email = 2;\n",
+				AfterContent:  "  string email = 5;\n",
+			},
+			wantType:     "feat",
+			wantBreaking: true,
+			wantDesc:     "renumber email from 2 to 5",
+		},
+		{
+			name: "removed field",
+			file: semantic.FileChange{
+				Path:          "api/user.proto",
+				ChangeType:    "modified",
+				BeforeContent: userProto,
+				AfterContent:  replace(userProto, "  Role role = 3;\n", ""),
+			},
+			wantType:     "feat",
+			wantBreaking: true,
+			wantDesc:     "remove User.role field",
+		},
+		{
+			name: "removed field with reserved number",
+			file: semantic.FileChange{
+				Path:          "api/user.proto",
+				ChangeType:    "modified",
+				BeforeContent: "  Role role = 3;\n",
+				AfterContent:  "  reserved 3;\n",
+			},
+			wantType: "docs",
+			wantDesc: "update user schema",
+		},
+		{
+			name: "new message",
+			file: semantic.FileChange{
+				Path:          "api/user.proto",
+				ChangeType:    "modified",
+				BeforeContent: "",
+				AfterContent:  "message Empty {}\n",
+			},
+			wantType: "feat",
+			wantDesc: "add Empty message",
+		},
+		{
+			name:         "deleted schema",
+			file:         semantic.FileChange{Path: "api/user.proto", ChangeType: "deleted", BeforeContent: userProto},
+			wantType:     "feat",
+			wantBreaking: true,
+			wantDesc:     "remove UserService API",
+		},
+		{
+			name: "comment only",
+			file: semantic.FileChange{
+				Path:          "api/user.proto",
+				ChangeType:    "modified",
+				BeforeContent: "// Users of the system\n",
+				AfterContent:  "// Users and service accounts\n",
+			},
+			wantType: "docs",
+			wantDesc: "update user schema",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := plugin.AnalyzeFile(context.Background(), tt.file, ctx)
+			if err != nil {
+				t.Fatalf("AnalyzeFile() error = %v", err)
+			}
+			if change.Type != tt.wantType {
+				t.Errorf("Type = %s, expected %s", change.Type, tt.wantType)
+			}
+			if change.Scope != "api" {
+				t.Errorf("Scope = %s, expected api", change.Scope)
+			}
+			if change.BreakingChange != tt.wantBreaking {
+				t.Errorf("BreakingChange = %v, expected %v (%s)", change.BreakingChange, tt.wantBreaking, change.Reasoning)
+			}
+			if change.Description != tt.wantDesc {
+				t.Errorf("Description = %q, expected %q", change.Description, tt.wantDesc)
+			}
+		})
+	}
+
+	t.Run("breaking detection can be disabled", func(t *testing.T) {
+		file := semantic.FileChange{Path: "a.proto", ChangeType: "modified", BeforeContent: "  string email = 2;\n"}
+		change, err := plugin.AnalyzeFile(context.Background(), file, semantic.AnalysisContext{
+			Config: map[string]string{"detect_breaking": "false"},
+		})
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+		if change.BreakingChange || change.Type != "refactor" {
+			t.Errorf("expected non-breaking refactor, got %s breaking=%v", change.Type, change.BreakingChange)
+		}
+	})
+
+	t.Run("unknown change type", func(t *testing.T) {
+		if _, err := plugin.AnalyzeFile(context.Background(), semantic.FileChange{Path: "a.proto", ChangeType: "bogus"}, ctx); err == nil {
+			t.Error("expected error for unknown change type")
+		}
+	})
+}
+
+// replace substitutes the first occurrence of old in s
+func replace(s, old, replacement string) string {
+	return strings.Replace(s, old, replacement, 1)
+}
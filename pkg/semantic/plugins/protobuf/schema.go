@@ -0,0 +1,171 @@
+// Package protobuf - Line-based .proto schema extraction
+package protobuf
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// field is a message field or enum value
+type field struct {
+	Message string
+	Name    string
+	Type    string // empty for enum values
+	Number  int
+}
+
+// schema is the set of declarations found in a .proto file or diff fragment.
+// Declarations whose enclosing block is not part of the content (a diff hunk
+// without its message line) are recorded with an empty parent name.
+type schema struct {
+	Package  string
+	Messages map[string]bool
+	Services map[string]bool
+	RPCs     map[string]string // "Service.Method" -> signature
+	Fields   map[string]field  // "Message.name" -> field
+	Reserved map[string]map[int]bool
+}
+
+var (
+	packagePattern  = regexp.MustCompile(`^package\s+([\w.]+)\s*;`)
+	blockPattern    = regexp.MustCompile(`^(message|enum|service|oneof)\s+(\w+)\s*\{`)
+	rpcPattern      = regexp.MustCompile(`^rpc\s+(\w+)\s*\(\s*(stream\s+)?([\w.]+)\s*\)\s*returns\s*\(\s*(stream\s+)?([\w.]+)\s*\)`)
+	fieldPattern    = regexp.MustCompile(`^(?:(?:repeated|optional|required)\s+)?(map\s*<[^>]+>|[\w.]+)\s+(\w+)\s*=\s*(\d+)`)
+	enumValPattern  = regexp.MustCompile(`^(\w+)\s*=\s*(-?\d+)`)
+	reservedPattern = regexp.MustCompile(`^reserved\s+(.+?);`)
+)
+
+// block is an open declaration on the parse stack
+type block struct {
+	kind string // message, enum, service, oneof or "" for option blocks
+	name string // fully qualified within the file, e.g. Outer.Inner
+}
+
+// parseSchema extracts declarations from proto source
+func parseSchema(content string) *schema {
+	s := &schema{
+		Messages: make(map[string]bool),
+		Services: make(map[string]bool),
+		RPCs:     make(map[string]string),
+		Fields:   make(map[string]field),
+		Reserved: make(map[string]map[int]bool),
+	}
+
+	var stack []block
+	current := func() block {
+		// oneof and option blocks belong to the enclosing declaration
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].kind != "oneof" && stack[i].kind != "" {
+				return stack[i]
+			}
+		}
+		return block{}
+	}
+
+	for _, raw := range strings.Split(content, "\n") {
+		line := raw
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		opened := strings.Count(line, "{")
+		closed := strings.Count(line, "}")
+		parent := current()
+
+		switch {
+		case packagePattern.MatchString(line):
+			s.Package = packagePattern.FindStringSubmatch(line)[1]
+		case blockPattern.MatchString(line):
+			m := blockPattern.FindStringSubmatch(line)
+			b := block{kind: m[1], name: m[2]}
+			if b.kind != "service" && parent.kind == "message" {
+				b.name = parent.name + "." + b.name
+			}
+			switch b.kind {
+			case "message", "enum":
+				s.Messages[b.name] = true
+			case "service":
+				s.Services[b.name] = true
+			}
+			stack = append(stack, b)
+			opened--
+		case rpcPattern.MatchString(line):
+			m := rpcPattern.FindStringSubmatch(line)
+			signature := "(" + m[2] + m[3] + ") returns (" + m[4] + m[5] + ")"
+			s.RPCs[parent.name+"."+m[1]] = signature
+		case reservedPattern.MatchString(line):
+			numbers := parseReserved(reservedPattern.FindStringSubmatch(line)[1])
+			if s.Reserved[parent.name] == nil {
+				s.Reserved[parent.name] = make(map[int]bool)
+			}
+			for _, n := range numbers {
+				s.Reserved[parent.name][n] = true
+			}
+		case parent.kind != "enum" && parent.kind != "service" && fieldPattern.MatchString(line) && !strings.HasPrefix(line, "option "):
+			m := fieldPattern.FindStringSubmatch(line)
+			number, _ := strconv.Atoi(m[3])
+			f := field{Message: parent.name, Name: m[2], Type: strings.Join(strings.Fields(m[1]), ""), Number: number}
+			s.Fields[f.Message+"."+f.Name] = f
+		case (parent.kind == "enum" || parent.kind == "") && enumValPattern.MatchString(line):
+			m := enumValPattern.FindStringSubmatch(line)
+			number, _ := strconv.Atoi(m[2])
+			f := field{Message: parent.name, Name: m[1], Number: number}
+			s.Fields[f.Message+"."+f.Name] = f
+		}
+
+		for ; opened > 0; opened-- {
+			stack = append(stack, block{})
+		}
+		for ; closed > 0 && len(stack) > 0; closed-- {
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	return s
+}
+
+// parseReserved returns the field numbers in a reserved statement; names are ignored
+func parseReserved(spec string) []int {
+	var numbers []int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if lo, hi, ok := strings.Cut(part, " to "); ok {
+			start, err1 := strconv.Atoi(strings.TrimSpace(lo))
+			end, err2 := strconv.Atoi(strings.TrimSpace(hi))
+			if strings.TrimSpace(hi) == "max" {
+				end, err2 = start, nil
+			}
+			if err1 == nil && err2 == nil && end-start < 10000 {
+				for n := start; n <= end; n++ {
+					numbers = append(numbers, n)
+				}
+			}
+			continue
+		}
+		if n, err := strconv.Atoi(part); err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
+
+// isReserved reports whether a field number is reserved in msg (or in an
+// unattributed reserved statement from the same diff fragment)
+func (s *schema) isReserved(msg string, number int) bool {
+	return s.Reserved[msg][number] || s.Reserved[""][number]
+}
+
+// fieldByNumber finds a field with the given number in msg
+func (s *schema) fieldByNumber(msg string, number int) (field, bool) {
+	for _, f := range s.Fields {
+		if f.Message == msg && f.Number == number {
+			return f, true
+		}
+	}
+	return field{}, false
+}
@@ -4,7 +4,6 @@ package plugins
 import (
 	"context"
 	"fmt"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -87,39 +86,29 @@ func (t *TerraformPlugin) CanAnalyze(file semantic.FileChange) bool {
 
 // AnalyzeFile analyzes a single Terraform file for semantic changes
 func (t *TerraformPlugin) AnalyzeFile(ctx context.Context, file semantic.FileChange, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	catalog, err := t.catalogFor(analysisCtx.Config)
+	if err != nil {
+		return nil, err
+	}
+
 	switch file.ChangeType {
 	case "added":
-		return t.analyzeNewFile(file, analysisCtx)
+		return t.analyzeNewFile(file, catalog)
 	case "deleted":
-		return t.analyzeDeletedFile(file, analysisCtx)
+		return t.analyzeDeletedFile(file, catalog)
 	case "modified":
-		return t.analyzeModifiedFile(file, analysisCtx)
+		return t.analyzeModifiedFile(file, catalog, analysisCtx)
+	case "renamed":
+		return t.analyzeRenamedFile(file, catalog)
 	default:
 		return nil, fmt.Errorf("unknown change type: %s", file.ChangeType)
 	}
 }
 
-// AnalyzeProject performs project-level analysis for Terraform
-func (t *TerraformPlugin) AnalyzeProject(ctx context.Context, context semantic.AnalysisContext) (*semantic.SemanticChange, error) {
-	// First, detect if this is actually a Terraform codebase
-	if !t.isTerraformCodebase(context) {
-		return nil, nil
-	}
-
-	// Look for Terraform project-level changes
-	var terraformFiles []semantic.FileChange
-	for _, file := range context.Files {
-		if t.CanAnalyze(file) {
-			terraformFiles = append(terraformFiles, file)
-		}
-	}
-
-	if len(terraformFiles) == 0 {
-		return nil, nil
-	}
-
-	// Use the sophisticated whole-changeset analysis
-	return t.AnalyzeChangeset(terraformFiles)
+// AnalyzeProject has no project-level Terraform analysis; whole-changeset
+// summaries are produced by AnalyzeChangeset
+func (t *TerraformPlugin) AnalyzeProject(_ context.Context, _ semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	return nil, nil
 }
 
 // DefaultConfig returns the default configuration for the plugin
@@ -139,6 +128,10 @@ func (t *TerraformPlugin) ValidateConfig(config map[string]string) error {
 		"analyze_security":        true,
 		"check_best_practices":    true,
 		"provider_sensitivity":    true,
+		"catalog_file":            true,
+		"critical_resources":      true,
+		"destructive_resources":   true,
+		"security_resources":      true,
 	}
 
 	for key := range config {
@@ -153,17 +146,21 @@ func (t *TerraformPlugin) ValidateConfig(config map[string]string) error {
 		}
 	}
 
+	if _, err := t.catalogFor(config); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // analyzeNewFile analyzes a newly added Terraform file
-func (t *TerraformPlugin) analyzeNewFile(file semantic.FileChange, _ semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+func (t *TerraformPlugin) analyzeNewFile(file semantic.FileChange, catalog *resourceCatalog) (*semantic.SemanticChange, error) {
 	content := file.AfterContent
 
 	// Analyze what type of resources are being added
 	resourceTypes := t.extractResourceTypes(content)
 
-	scope := t.determineScope(file.Path, content)
+	scope := t.scopeWithCatalog(file.Path, content, catalog)
 
 	if len(resourceTypes) == 0 {
 		return &semantic.SemanticChange{
@@ -180,18 +177,18 @@ func (t *TerraformPlugin) analyzeNewFile(file semantic.FileChange, _ semantic.An
 	}
 
 	// Analyze specific resource types
-	change := t.analyzeResourceTypes(resourceTypes, file, scope)
+	change := t.analyzeResourceTypes(resourceTypes, file, scope, catalog)
 	return change, nil
 }
 
 // analyzeDeletedFile analyzes a deleted Terraform file
-func (t *TerraformPlugin) analyzeDeletedFile(file semantic.FileChange, _ semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+func (t *TerraformPlugin) analyzeDeletedFile(file semantic.FileChange, catalog *resourceCatalog) (*semantic.SemanticChange, error) {
 	content := file.BeforeContent
 	resourceTypes := t.extractResourceTypes(content)
-	scope := t.determineScope(file.Path, content)
+	scope := t.scopeWithCatalog(file.Path, content, catalog)
 
 	// Check if this is a breaking change
-	breaking := t.isDeletionBreaking(resourceTypes)
+	breaking := t.isDeletionBreaking(resourceTypes, catalog)
 
 	changeType := "refactor"
 	if breaking {
@@ -215,18 +212,43 @@ func (t *TerraformPlugin) analyzeDeletedFile(file semantic.FileChange, _ semanti
 	}, nil
 }
 
+// analyzeRenamedFile analyzes a renamed or moved Terraform file
+func (t *TerraformPlugin) analyzeRenamedFile(file semantic.FileChange, catalog *resourceCatalog) (*semantic.SemanticChange, error) {
+	scope := t.scopeWithCatalog(file.Path, file.AfterContent, catalog)
+
+	verb := "rename"
+	if filepath.Dir(file.OldPath) != filepath.Dir(file.Path) {
+		verb = "move"
+	}
+
+	return &semantic.SemanticChange{
+		Type:        "refactor",
+		Scope:       scope,
+		Description: fmt.Sprintf("%s Terraform configuration %s to %s", verb, file.OldPath, file.Path),
+		Intent:      "Infrastructure code organization",
+		Impact:      "Terraform addresses are unchanged unless module paths move",
+		Files:       []string{file.OldPath, file.Path},
+		Confidence:  0.9,
+		Reasoning:   fmt.Sprintf("git detected %s as a rename of %s", file.Path, file.OldPath),
+		Metadata: map[string]string{
+			"file_type": "terraform",
+			"old_path":  file.OldPath,
+		},
+	}, nil
+}
+
 // analyzeModifiedFile analyzes a modified Terraform file
-func (t *TerraformPlugin) analyzeModifiedFile(file semantic.FileChange, _ semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+func (t *TerraformPlugin) analyzeModifiedFile(file semantic.FileChange, catalog *resourceCatalog, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
 	beforeResources := t.extractResourceTypes(file.BeforeContent)
 	afterResources := t.extractResourceTypes(file.AfterContent)
 
 	added, removed, modified := t.compareResources(beforeResources, afterResources)
 
-	scope := t.determineScope(file.Path, file.AfterContent)
+	scope := t.scopeWithCatalog(file.Path, file.AfterContent, catalog)
 
 	// Check if this file is a hotspot (modified repeatedly in recent commits)
-	hotspots := t.detectHotspotFiles([]semantic.FileChange{file})
-	isHotspot := hotspots[file.Path] > 0
+	hotspotCount := analysisCtx.HotspotCount(file.Path)
+	isHotspot := hotspotCount > 0
 
 	// Determine change type based on modifications
 	changeType := "refactor" // default
@@ -259,7 +281,7 @@ func (t *TerraformPlugin) analyzeModifiedFile(file semantic.FileChange, _ semant
 				description = fmt.Sprintf("remove %d resources", len(removed))
 			}
 			intent = "Infrastructure cleanup"
-			breaking = t.isRemovalBreaking(removed)
+			breaking = t.isRemovalBreaking(removed, catalog)
 		} else if len(modified) > 0 {
 			// Check if it's a fix or enhancement
 			if t.isSecurityImprovement(file.DiffContent) {
@@ -285,7 +307,6 @@ func (t *TerraformPlugin) analyzeModifiedFile(file semantic.FileChange, _ semant
 		}
 	} else {
 		// For hotspot files, append the hotspot count to description
-		hotspotCount := hotspots[file.Path]
 		description = fmt.Sprintf("stabilize %s configuration (modified %d times recently)", t.getFileName(file.Path), hotspotCount)
 	}
 
@@ -301,14 +322,15 @@ func (t *TerraformPlugin) analyzeModifiedFile(file semantic.FileChange, _ semant
 	// Add hotspot information to metadata
 	if isHotspot {
 		metadata["hotspot"] = "true"
-		metadata["hotspot_count"] = fmt.Sprintf("%d", hotspots[file.Path])
+		metadata["hotspot_count"] = fmt.Sprintf("%d", hotspotCount)
 		metadata["hotspot_reasoning"] = "File modified repeatedly in recent commits, indicating stabilization effort"
 	}
 
 	// Update reasoning to include hotspot information
 	reasoning := t.generateReasoning(added, removed, modified)
 	if isHotspot {
-		reasoning = fmt.Sprintf("%s; Hotspot detected: modified %d times in last 5 commits", reasoning, hotspots[file.Path])
+		reasoning = fmt.Sprintf("%s; Hotspot detected: modified %d times in last %d commits",
+			reasoning, hotspotCount, analysisCtx.Hotspots.Window())
 	}
 
 	return &semantic.SemanticChange{
@@ -346,8 +368,14 @@ func (t *TerraformPlugin) extractResourceTypes(content string) []string {
 	return resources
 }
 
-// determineScope determines the scope based on file path and content
+// determineScope determines the scope based on file path and content using the builtin catalogs
 func (t *TerraformPlugin) determineScope(filePath, content string) string {
+	return t.scopeWithCatalog(filePath, content, builtinCatalog)
+}
+
+// scopeWithCatalog determines the scope from path conventions first, then from
+// the catalog's scope rules for the resources the content references
+func (t *TerraformPlugin) scopeWithCatalog(filePath, content string, catalog *resourceCatalog) string {
 	// Path-based scoping
 	pathParts := strings.Split(strings.ToLower(filePath), "/")
 
@@ -368,46 +396,19 @@ func (t *TerraformPlugin) determineScope(filePath, content string) string {
 		}
 	}
 
-	// Content-based scoping for OCI resources
-	if strings.Contains(content, "oci_core_vcn") || strings.Contains(content, "oci_core_subnet") || strings.Contains(content, "oci_load_balancer") {
-		return "network"
-	}
-	if strings.Contains(content, "oci_identity") || strings.Contains(content, "oci_core_security") {
-		return "security"
-	}
-	if strings.Contains(content, "oci_objectstorage") || strings.Contains(content, "oci_database") || strings.Contains(content, "oci_mysql") {
-		return "storage"
-	}
-	if strings.Contains(content, "oci_core_instance") || strings.Contains(content, "oci_containerengine") {
-		return "compute"
+	// Content-based scoping from the provider catalogs
+	if scope := catalog.scopeFor(referencedTypes(content)); scope != "" {
+		return scope
 	}
 
 	return "infra"
 }
 
-// analyzeResourceTypes analyzes specific OCI resource types
-func (t *TerraformPlugin) analyzeResourceTypes(resourceTypes []string, file semantic.FileChange, scope string) *semantic.SemanticChange {
+// analyzeResourceTypes classifies added resource types using the provider catalogs
+func (t *TerraformPlugin) analyzeResourceTypes(resourceTypes []string, file semantic.FileChange, scope string, catalog *resourceCatalog) *semantic.SemanticChange {
 	// Categorize resources by impact
-	criticalResources := []string{"oci_core_vcn", "oci_database_autonomous_database", "oci_database_db_system", "oci_mysql_mysql_db_system"}
-	securityResources := []string{"oci_identity_policy", "oci_core_security_list", "oci_identity_user", "oci_identity_group"}
-
-	critical := false
-	security := false
-
-	for _, resource := range resourceTypes {
-		for _, cr := range criticalResources {
-			if resource == cr {
-				critical = true
-				break
-			}
-		}
-		for _, sr := range securityResources {
-			if resource == sr {
-				security = true
-				break
-			}
-		}
-	}
+	critical := anyMatches(resourceTypes, catalog.Critical)
+	security := anyMatches(resourceTypes, catalog.Security)
 
 	changeType := "feat"
 	description := fmt.Sprintf("add %s infrastructure", scope)
@@ -474,24 +475,12 @@ func (t *TerraformPlugin) compareResources(before, after []string) (added, remov
 	return added, removed, modified
 }
 
-func (t *TerraformPlugin) isDeletionBreaking(resourceTypes []string) bool {
-	breakingResources := []string{
-		"oci_core_vcn", "oci_database_autonomous_database", "oci_database_db_system",
-		"oci_objectstorage_bucket", "oci_containerengine_cluster", "oci_mysql_mysql_db_system",
-	}
-
-	for _, resource := range resourceTypes {
-		for _, breaking := range breakingResources {
-			if resource == breaking {
-				return true
-			}
-		}
-	}
-	return false
+func (t *TerraformPlugin) isDeletionBreaking(resourceTypes []string, catalog *resourceCatalog) bool {
+	return anyMatches(resourceTypes, catalog.Destructive)
 }
 
-func (t *TerraformPlugin) isRemovalBreaking(removed []string) bool {
-	return t.isDeletionBreaking(removed)
+func (t *TerraformPlugin) isRemovalBreaking(removed []string, catalog *resourceCatalog) bool {
+	return t.isDeletionBreaking(removed, catalog)
 }
 
 func (t *TerraformPlugin) isSecurityImprovement(diff string) bool {
@@ -702,41 +691,3 @@ func (t *TerraformPlugin) isTerraformCodebase(context semantic.AnalysisContext)
 	// If we have any Terraform files at all, run the analysis
 	return terraformFileCount > 0
 }
-
-// detectHotspotFiles checks if files have been modified repeatedly in recent commits
-func (t *TerraformPlugin) detectHotspotFiles(files []semantic.FileChange) map[string]int {
-	hotspots := make(map[string]int)
-
-	for _, file := range files {
-		// Sanitize and validate file path to prevent command injection
-		cleanPath := filepath.Clean(file.Path)
-		if strings.Contains(cleanPath, "..") || strings.Contains(cleanPath, ";") ||
-			strings.Contains(cleanPath, "|") || strings.Contains(cleanPath, "&") ||
-			strings.HasPrefix(cleanPath, "-") || len(cleanPath) == 0 {
-			continue // Skip potentially malicious or invalid paths
-		}
-
-		// Use a safe, sanitized path for the git command
-		// #nosec G204 - path is sanitized above
-		cmd := exec.Command("git", "log", "-n", "5", "--name-only", "--pretty=", "--", cleanPath)
-		output, err := cmd.Output()
-		if err != nil {
-			continue // Skip if git command fails
-		}
-
-		// Count occurrences of this file in recent commits
-		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-		count := 0
-		for _, line := range lines {
-			if strings.TrimSpace(line) == cleanPath {
-				count++
-			}
-		}
-
-		if count > 1 { // File appears in multiple recent commits
-			hotspots[file.Path] = count
-		}
-	}
-
-	return hotspots
-}
@@ -0,0 +1,214 @@
+// Package plugins - Provider resource catalogs for Terraform classification
+package plugins
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed catalogs/*.yaml
+var catalogFiles embed.FS
+
+// resourceCatalog classifies Terraform resource types for one or more providers.
+// Entries are glob patterns matched against the resource type (e.g. "aws_iam_*").
+type resourceCatalog struct {
+	Provider    string      `yaml:"provider"`
+	Critical    []string    `yaml:"critical"`    // adding them is called out as critical infrastructure
+	Destructive []string    `yaml:"destructive"` // removing them is a breaking change
+	Security    []string    `yaml:"security"`
+	Scopes      []scopeRule `yaml:"scopes"` // checked in order when the path gives no scope
+}
+
+// scopeRule maps resource type patterns to a commit scope
+type scopeRule struct {
+	Scope     string   `yaml:"scope"`
+	Resources []string `yaml:"resources"`
+}
+
+// builtinCatalog merges the embedded aws, azurerm, google and oci catalogs
+var builtinCatalog = mustLoadBuiltinCatalog()
+
+// mustLoadBuiltinCatalog parses the embedded catalogs; they ship with the
+// binary, so a parse failure is a build defect
+func mustLoadBuiltinCatalog() *resourceCatalog {
+	entries, err := catalogFiles.ReadDir("catalogs")
+	if err != nil {
+		panic(fmt.Sprintf("reading embedded resource catalogs: %v", err))
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	merged := &resourceCatalog{}
+	for _, name := range names {
+		data, err := catalogFiles.ReadFile("catalogs/" + name)
+		if err != nil {
+			panic(fmt.Sprintf("reading resource catalog %s: %v", name, err))
+		}
+		catalog, err := parseCatalog(data)
+		if err != nil {
+			panic(fmt.Sprintf("parsing resource catalog %s: %v", name, err))
+		}
+		merged.merge(catalog)
+	}
+	return merged
+}
+
+// parseCatalog decodes a YAML resource catalog
+func parseCatalog(data []byte) (*resourceCatalog, error) {
+	var catalog resourceCatalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, err
+	}
+	if err := catalog.validate(); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+// validate checks that every entry is a well-formed glob pattern
+func (c *resourceCatalog) validate() error {
+	all := append(append(append([]string{}, c.Critical...), c.Destructive...), c.Security...)
+	for _, rule := range c.Scopes {
+		all = append(all, rule.Resources...)
+	}
+	for _, pattern := range all {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid resource pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// merge appends another catalog's entries. Scope rules for a scope already
+// present are folded into the existing rule so the scope order stays stable.
+func (c *resourceCatalog) merge(other *resourceCatalog) {
+	c.Critical = append(c.Critical, other.Critical...)
+	c.Destructive = append(c.Destructive, other.Destructive...)
+	c.Security = append(c.Security, other.Security...)
+
+	for _, rule := range other.Scopes {
+		found := false
+		for i := range c.Scopes {
+			if c.Scopes[i].Scope == rule.Scope {
+				c.Scopes[i].Resources = append(c.Scopes[i].Resources, rule.Resources...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.Scopes = append(c.Scopes, scopeRule{Scope: rule.Scope, Resources: append([]string{}, rule.Resources...)})
+		}
+	}
+}
+
+// clone returns a deep copy so per-call overrides never touch the builtin catalog
+func (c *resourceCatalog) clone() *resourceCatalog {
+	copied := &resourceCatalog{Provider: c.Provider}
+	copied.merge(c)
+	return copied
+}
+
+// catalogFor returns the builtin catalog extended by the plugin config:
+// catalog_file names a YAML catalog in the embedded format, and
+// critical_resources, destructive_resources and security_resources add
+// comma-separated patterns. User scope rules take precedence over builtin ones.
+func (t *TerraformPlugin) catalogFor(config map[string]string) (*resourceCatalog, error) {
+	if config["catalog_file"] == "" && config["critical_resources"] == "" &&
+		config["destructive_resources"] == "" && config["security_resources"] == "" {
+		return builtinCatalog, nil
+	}
+
+	user := &resourceCatalog{}
+	if file := config["catalog_file"]; file != "" {
+		data, err := os.ReadFile(file) // #nosec G304 - path comes from the user's own config
+		if err != nil {
+			return nil, fmt.Errorf("reading resource catalog: %w", err)
+		}
+		if user, err = parseCatalog(data); err != nil {
+			return nil, fmt.Errorf("parsing resource catalog %s: %w", file, err)
+		}
+	}
+	user.merge(&resourceCatalog{
+		Critical:    splitPatterns(config["critical_resources"]),
+		Destructive: splitPatterns(config["destructive_resources"]),
+		Security:    splitPatterns(config["security_resources"]),
+	})
+	if err := user.validate(); err != nil {
+		return nil, err
+	}
+
+	catalog := user.clone()
+	catalog.merge(builtinCatalog)
+	return catalog, nil
+}
+
+// splitPatterns parses a comma-separated pattern list
+func splitPatterns(list string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(list, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// matchesResource reports whether a resource type matches any of the patterns
+func matchesResource(resourceType string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, resourceType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// anyMatches reports whether any of the resource types matches the patterns
+func anyMatches(resourceTypes, patterns []string) bool {
+	for _, resourceType := range resourceTypes {
+		if matchesResource(resourceType, patterns) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeFor returns the first scope whose rule matches one of the resource types
+func (c *resourceCatalog) scopeFor(resourceTypes []string) string {
+	for _, rule := range c.Scopes {
+		if anyMatches(resourceTypes, rule.Resources) {
+			return rule.Scope
+		}
+	}
+	return ""
+}
+
+var (
+	// declaredTypesPattern finds resource and data source declarations
+	declaredTypesPattern = regexp.MustCompile(`(?:resource|data)\s+"([^"]+)"`)
+	// referenceTypesPattern finds references such as oci_core_subnet.main.id,
+	// which are often all a diff fragment contains
+	referenceTypesPattern = regexp.MustCompile(`\b([a-z][a-z0-9]*_[a-z0-9_]+)\.[A-Za-z_][\w-]*\.`)
+)
+
+// referencedTypes returns the resource types declared or referenced in Terraform content
+func referencedTypes(content string) []string {
+	var types []string
+	for _, pattern := range []*regexp.Regexp{declaredTypesPattern, referenceTypesPattern} {
+		for _, m := range pattern.FindAllStringSubmatch(content, -1) {
+			types = append(types, m[1])
+		}
+	}
+	return types
+}
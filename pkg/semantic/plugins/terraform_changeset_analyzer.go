@@ -2,8 +2,10 @@
 package plugins
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
@@ -15,13 +17,21 @@ type TerraformChangesetAnalyzer struct {
 	addedFiles    []string
 	modifiedFiles []string
 	deletedFiles  []string
+	renamedFiles  []string
 	allTerraform  bool
+	hotspots      semantic.HotspotDetector
 }
 
-// AnalyzeChangeset performs sophisticated whole-changeset analysis for Terraform files
-func (t *TerraformPlugin) AnalyzeChangeset(files []semantic.FileChange) (*semantic.SemanticChange, error) {
+// AnalyzeChangeset performs sophisticated whole-changeset analysis for the Terraform
+// files in a changeset, implementing semantic.ChangesetAnalyzer
+func (t *TerraformPlugin) AnalyzeChangeset(_ context.Context, files []semantic.FileChange, analysisCtx semantic.AnalysisContext) (*semantic.SemanticChange, error) {
+	if len(files) == 0 || !t.isTerraformCodebase(analysisCtx) {
+		return nil, nil
+	}
+
 	analyzer := &TerraformChangesetAnalyzer{
-		files: files,
+		files:    files,
+		hotspots: analysisCtx.Hotspots,
 	}
 
 	// Categorize files
@@ -79,7 +89,7 @@ func (t *TerraformPlugin) AnalyzeChangeset(files []semantic.FileChange) (*semant
 	return analyzer.analyzeByFilePatterns(), nil
 }
 
-// categorizeFiles sorts files into added, modified, and deleted
+// categorizeFiles sorts files into added, modified, deleted, and renamed
 func (a *TerraformChangesetAnalyzer) categorizeFiles() {
 	for _, file := range a.files {
 		switch file.ChangeType {
@@ -89,6 +99,8 @@ func (a *TerraformChangesetAnalyzer) categorizeFiles() {
 			a.modifiedFiles = append(a.modifiedFiles, file.Path)
 		case "deleted":
 			a.deletedFiles = append(a.deletedFiles, file.Path)
+		case "renamed":
+			a.renamedFiles = append(a.renamedFiles, file.Path)
 		}
 	}
 }
@@ -327,8 +339,8 @@ func (a *TerraformChangesetAnalyzer) detectProviderUpgrade() *semantic.SemanticC
 
 // detectRefactoring detects pure refactoring (renames, moves)
 func (a *TerraformChangesetAnalyzer) detectRefactoring() *semantic.SemanticChange {
-	// Check if files are mostly renames/moves
-	renamedCount := 0
+	// Check if files are mostly renames/moves; git-detected renames count directly
+	renamedCount := len(a.renamedFiles)
 
 	for range a.files {
 		// Simple heuristic: if deleted and added files have similar names
@@ -581,14 +593,12 @@ func (a *TerraformChangesetAnalyzer) detectStateManagementChanges() *semantic.Se
 
 // detectHotspotStabilization detects changes focused on stabilizing frequently modified files
 func (a *TerraformChangesetAnalyzer) detectHotspotStabilization() *semantic.SemanticChange {
-	// Only apply to modified files (not new/deleted files)
-	if len(a.modifiedFiles) == 0 || len(a.addedFiles) > 0 || len(a.deletedFiles) > 0 {
+	// Only apply to modified files (not new/deleted files) with history available
+	if a.hotspots == nil || len(a.modifiedFiles) == 0 || len(a.addedFiles) > 0 || len(a.deletedFiles) > 0 {
 		return nil
 	}
 
-	// Use the terraform plugin's hotspot detection
-	plugin := &TerraformPlugin{}
-	hotspots := plugin.detectHotspotFiles(a.files)
+	hotspots := a.hotspots.Hotspots(a.modifiedFiles)
 
 	// Check if majority of files are hotspots
 	hotspotCount := len(hotspots)
@@ -600,6 +610,7 @@ func (a *TerraformChangesetAnalyzer) detectHotspotStabilization() *semantic.Sema
 		for filePath, count := range hotspots {
 			hotspotDetails = append(hotspotDetails, fmt.Sprintf("%s (%d times)", filepath.Base(filePath), count))
 		}
+		sort.Strings(hotspotDetails)
 
 		return &semantic.SemanticChange{
 			Type:        "fix",
@@ -2,6 +2,9 @@ package plugins
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/greenstevester/fast-cc-git-hooks/pkg/semantic"
@@ -247,6 +250,27 @@ resource "oci_database_autonomous_database" "main" {
 		}
 	})
 
+	t.Run("analyze renamed terraform file", func(t *testing.T) {
+		file := semantic.FileChange{
+			Path:       "modules/network/vcn.tf",
+			OldPath:    "network.tf",
+			ChangeType: "renamed",
+		}
+
+		change, err := plugin.AnalyzeFile(context.Background(), file, semantic.AnalysisContext{})
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+
+		if change.Type != "refactor" {
+			t.Errorf("expected type 'refactor' for rename, got %s", change.Type)
+		}
+
+		if !strings.Contains(change.Description, "move") || !strings.Contains(change.Description, "network.tf") {
+			t.Errorf("expected move description, got %q", change.Description)
+		}
+	})
+
 	t.Run("extract resource types", func(t *testing.T) {
 		content := `
 resource "oci_core_vcn" "main" {
@@ -321,6 +345,100 @@ resource "oci_core_vcn" "secondary" {
 		}
 	})
 
+	t.Run("provider resource catalogs", func(t *testing.T) {
+		scopes := []struct {
+			content  string
+			expected string
+		}{
+			{`resource "aws_db_instance" "main" {}`, "storage"},
+			{`resource "aws_iam_role" "deployer" {}`, "security"},
+			{`  subnet_id = aws_subnet.private.id`, "network"},
+			{`resource "azurerm_kubernetes_cluster" "aks" {}`, "compute"},
+			{`resource "google_dns_managed_zone" "zone" {}`, "dns"},
+		}
+		for _, tt := range scopes {
+			if got := plugin.determineScope("main.tf", tt.content); got != tt.expected {
+				t.Errorf("determineScope(%q) = %s, expected %s", tt.content, got, tt.expected)
+			}
+		}
+
+		added := semantic.FileChange{Path: "rds.tf", ChangeType: "added", AfterContent: `resource "aws_rds_cluster" "orders" {}`}
+		change, err := plugin.AnalyzeFile(context.Background(), added, semantic.AnalysisContext{})
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+		if change.Description != "add critical storage infrastructure" {
+			t.Errorf("expected critical AWS infrastructure, got %q", change.Description)
+		}
+
+		removed := semantic.FileChange{
+			Path:          "buckets.tf",
+			ChangeType:    "modified",
+			BeforeContent: `resource "google_storage_bucket" "assets" {}`,
+		}
+		change, err = plugin.AnalyzeFile(context.Background(), removed, semantic.AnalysisContext{})
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+		if !change.BreakingChange {
+			t.Errorf("expected removing a storage bucket to be breaking, got %+v", change)
+		}
+	})
+
+	t.Run("catalog overrides from config", func(t *testing.T) {
+		catalogFile := filepath.Join(t.TempDir(), "catalog.yaml")
+		data := "provider: internal\nscopes:\n  - scope: platform\n    resources: [acme_*]\n"
+		if err := os.WriteFile(catalogFile, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		config := map[string]string{
+			"catalog_file":          catalogFile,
+			"destructive_resources": "acme_cluster, acme_queue",
+		}
+		if err := plugin.ValidateConfig(config); err != nil {
+			t.Fatalf("ValidateConfig() error = %v", err)
+		}
+
+		file := semantic.FileChange{Path: "main.tf", ChangeType: "deleted", BeforeContent: `resource "acme_cluster" "main" {}`}
+		change, err := plugin.AnalyzeFile(context.Background(), file, semantic.AnalysisContext{Config: config})
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+		if change.Scope != "platform" || !change.BreakingChange {
+			t.Errorf("expected breaking platform change, got scope=%s breaking=%v", change.Scope, change.BreakingChange)
+		}
+
+		if err := plugin.ValidateConfig(map[string]string{"security_resources": "acme_[iam"}); err == nil {
+			t.Error("ValidateConfig() with a malformed pattern should return error")
+		}
+		if err := plugin.ValidateConfig(map[string]string{"catalog_file": filepath.Join(t.TempDir(), "missing.yaml")}); err == nil {
+			t.Error("ValidateConfig() with a missing catalog file should return error")
+		}
+	})
+
+	t.Run("hotspots from the shared detector", func(t *testing.T) {
+		hotspots := staticHotspots{"infra/main.tf": 4}
+		file := semantic.FileChange{Path: "infra/main.tf", ChangeType: "modified", DiffContent: "+  tags = {}\n"}
+
+		change, err := plugin.AnalyzeFile(context.Background(), file, semantic.AnalysisContext{Hotspots: hotspots})
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+		if change.Type != "fix" || change.Metadata["hotspot_count"] != "4" ||
+			!strings.Contains(change.Reasoning, "modified 4 times in last 10 commits") {
+			t.Errorf("expected hotspot stabilization, got %+v", change)
+		}
+
+		change, err = plugin.AnalyzeFile(context.Background(), file, semantic.AnalysisContext{})
+		if err != nil {
+			t.Fatalf("AnalyzeFile() error = %v", err)
+		}
+		if change.Metadata["hotspot"] != "" {
+			t.Errorf("expected no hotspot without history, got %+v", change.Metadata)
+		}
+	})
+
 	t.Run("config validation", func(t *testing.T) {
 		plugin := &TerraformPlugin{}
 
@@ -355,6 +473,20 @@ resource "oci_core_vcn" "secondary" {
 	})
 }
 
+// staticHotspots is a fixed semantic.HotspotDetector over a 10 commit window
+type staticHotspots map[string]int
+
+func (s staticHotspots) Window() int { return 10 }
+func (s staticHotspots) Hotspots(paths []string) map[string]int {
+	result := make(map[string]int)
+	for _, path := range paths {
+		if count, ok := s[path]; ok {
+			result[path] = count
+		}
+	}
+	return result
+}
+
 // Helper function to check if slice contains string
 func containsString(slice []string, item string) bool {
 	for _, s := range slice {
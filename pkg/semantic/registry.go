@@ -0,0 +1,264 @@
+// Package semantic - Plugin registration, enablement and conflict resolution
+package semantic
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Prioritizer is implemented by plugins that should win over other plugins
+// claiming the same file, e.g. a Pulumi plugin over the Go plugin for a
+// Pulumi program. Plugins without it have priority 0.
+type Prioritizer interface {
+	Priority() int
+}
+
+// Match strengths, strongest first; used to break priority ties
+const (
+	MatchExtension = "extension"
+	MatchPattern   = "pattern"
+	MatchContent   = "content"
+)
+
+var matchStrength = map[string]int{MatchExtension: 3, MatchPattern: 2, MatchContent: 1}
+
+// PluginMatch is a plugin that claims a file
+type PluginMatch struct {
+	Plugin   SemanticPlugin
+	Match    string // MatchExtension, MatchPattern or MatchContent
+	Priority int
+}
+
+// PluginRegistry manages available semantic analysis plugins
+type PluginRegistry struct {
+	plugins    map[string]SemanticPlugin
+	disabled   map[string]bool
+	priorities map[string]int // configured overrides of Prioritizer
+}
+
+// NewPluginRegistry creates a new plugin registry
+func NewPluginRegistry() *PluginRegistry {
+	return &PluginRegistry{
+		plugins:    make(map[string]SemanticPlugin),
+		disabled:   make(map[string]bool),
+		priorities: make(map[string]int),
+	}
+}
+
+// Register registers a semantic analysis plugin
+func (r *PluginRegistry) Register(plugin SemanticPlugin) error {
+	name := plugin.Name()
+	if name == "" {
+		return fmt.Errorf("plugin name cannot be empty")
+	}
+
+	if _, exists := r.plugins[name]; exists {
+		return fmt.Errorf("plugin %s already registered", name)
+	}
+
+	r.plugins[name] = plugin
+	return nil
+}
+
+// GetPlugin returns a plugin by name
+func (r *PluginRegistry) GetPlugin(name string) (SemanticPlugin, bool) {
+	plugin, exists := r.plugins[name]
+	return plugin, exists
+}
+
+// Enable re-enables a disabled plugin
+func (r *PluginRegistry) Enable(name string) error {
+	if _, exists := r.plugins[name]; !exists {
+		return fmt.Errorf("plugin %s not found", name)
+	}
+	delete(r.disabled, name)
+	return nil
+}
+
+// Disable stops a plugin from claiming files or running project analysis
+func (r *PluginRegistry) Disable(name string) error {
+	if _, exists := r.plugins[name]; !exists {
+		return fmt.Errorf("plugin %s not found", name)
+	}
+	r.disabled[name] = true
+	return nil
+}
+
+// IsEnabled reports whether a registered plugin is enabled
+func (r *PluginRegistry) IsEnabled(name string) bool {
+	_, exists := r.plugins[name]
+	return exists && !r.disabled[name]
+}
+
+// SetPriority overrides a plugin's priority for conflict resolution
+func (r *PluginRegistry) SetPriority(name string, priority int) error {
+	if _, exists := r.plugins[name]; !exists {
+		return fmt.Errorf("plugin %s not found", name)
+	}
+	r.priorities[name] = priority
+	return nil
+}
+
+// Priority returns the effective priority of a plugin
+func (r *PluginRegistry) Priority(name string) int {
+	if priority, ok := r.priorities[name]; ok {
+		return priority
+	}
+	if p, ok := r.plugins[name].(Prioritizer); ok {
+		return p.Priority()
+	}
+	return 0
+}
+
+// Candidates returns the enabled plugins that claim a file, best first.
+// A plugin claims a file by extension, by file pattern (when CanAnalyze agrees)
+// or by CanAnalyze alone. Candidates are ordered by priority, then match
+// strength, then name, so the choice never depends on registration order.
+func (r *PluginRegistry) Candidates(file FileChange) []PluginMatch {
+	ext := strings.ToLower(filepath.Ext(file.Path))
+
+	var matches []PluginMatch
+	for _, plugin := range r.ListPlugins() {
+		if !r.IsEnabled(plugin.Name()) {
+			continue
+		}
+
+		match := ""
+		for _, supportedExt := range plugin.SupportedExtensions() {
+			if ext == supportedExt {
+				match = MatchExtension
+				break
+			}
+		}
+		if match == "" && plugin.CanAnalyze(file) {
+			match = MatchContent
+			if matchesAnyPattern(file.Path, plugin.SupportedFilePatterns()) {
+				match = MatchPattern
+			}
+		}
+		if match == "" {
+			continue
+		}
+
+		matches = append(matches, PluginMatch{Plugin: plugin, Match: match, Priority: r.Priority(plugin.Name())})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Priority != matches[j].Priority {
+			return matches[i].Priority > matches[j].Priority
+		}
+		return matchStrength[matches[i].Match] > matchStrength[matches[j].Match]
+	})
+	return matches
+}
+
+// GetPluginForFile returns the most appropriate plugin for a file
+func (r *PluginRegistry) GetPluginForFile(file FileChange) SemanticPlugin {
+	if candidates := r.Candidates(file); len(candidates) > 0 {
+		return candidates[0].Plugin
+	}
+	return nil
+}
+
+// matchesAnyPattern matches patterns against the full path, and patterns
+// without a directory against the base name
+func matchesAnyPattern(path string, patterns []string) bool {
+	path = filepath.ToSlash(path)
+	base := filepath.Base(path)
+	for _, pattern := range patterns {
+		target := path
+		if !strings.Contains(pattern, "/") {
+			target = base
+		}
+		if matched, err := filepath.Match(pattern, target); err == nil && matched {
+			return true
+		}
+		// "dir/*" also covers files below dir at any depth
+		if dir, ok := strings.CutSuffix(pattern, "/*"); ok && strings.Contains("/"+path, "/"+dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ListPlugins returns all registered plugins sorted by name
+func (r *PluginRegistry) ListPlugins() []SemanticPlugin {
+	plugins := make([]SemanticPlugin, 0, len(r.plugins))
+	for _, plugin := range r.plugins {
+		plugins = append(plugins, plugin)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name() < plugins[j].Name()
+	})
+	return plugins
+}
+
+// Reserved per-plugin settings; every other key is plugin configuration
+const (
+	SettingEnabled  = "enabled"
+	SettingPriority = "priority"
+)
+
+// Configure applies per-plugin settings, as read from the plugins section of
+// the config file. "enabled" and "priority" control the registry; remaining
+// keys are layered over the plugin's DefaultConfig and validated by the plugin.
+func (s *SemanticAnalyzer) Configure(settings map[string]map[string]string) error {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		plugin, exists := s.registry.GetPlugin(name)
+		if !exists {
+			return fmt.Errorf("plugins.%s: unknown plugin", name)
+		}
+
+		config := plugin.DefaultConfig()
+		if config == nil {
+			config = make(map[string]string)
+		}
+		for key, value := range settings[name] {
+			switch key {
+			case SettingEnabled:
+				enabled, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("plugins.%s.enabled: %q is not a boolean", name, value)
+				}
+				if enabled {
+					_ = s.registry.Enable(name)
+				} else {
+					_ = s.registry.Disable(name)
+				}
+			case SettingPriority:
+				priority, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("plugins.%s.priority: %q is not an integer", name, value)
+				}
+				_ = s.registry.SetPriority(name, priority)
+			default:
+				config[key] = value
+			}
+		}
+
+		if err := s.SetPluginConfig(name, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PluginConfig returns the effective configuration of a plugin
+func (s *SemanticAnalyzer) PluginConfig(name string) map[string]string {
+	if config := s.config[name]; config != nil {
+		return config
+	}
+	if plugin, exists := s.registry.GetPlugin(name); exists {
+		return plugin.DefaultConfig()
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package semantic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakePlugin claims files by extension, pattern or content marker
+type fakePlugin struct {
+	name       string
+	extensions []string
+	patterns   []string
+	marker     string
+}
+
+func (f *fakePlugin) Name() string                    { return f.name }
+func (f *fakePlugin) Version() string                 { return "1.0.0" }
+func (f *fakePlugin) SupportedExtensions() []string   { return f.extensions }
+func (f *fakePlugin) SupportedFilePatterns() []string { return f.patterns }
+func (f *fakePlugin) CanAnalyze(file FileChange) bool {
+	return f.marker != "" && strings.Contains(file.AfterContent, f.marker)
+}
+func (f *fakePlugin) AnalyzeFile(context.Context, FileChange, AnalysisContext) (*SemanticChange, error) {
+	return &SemanticChange{Type: "feat", Description: f.name}, nil
+}
+func (f *fakePlugin) AnalyzeProject(context.Context, AnalysisContext) (*SemanticChange, error) {
+	return nil, nil
+}
+func (f *fakePlugin) DefaultConfig() map[string]string { return map[string]string{"mode": "default"} }
+func (f *fakePlugin) ValidateConfig(config map[string]string) error {
+	if mode := config["mode"]; mode != "default" && mode != "strict" {
+		return fmt.Errorf("invalid mode %q", mode)
+	}
+	return nil
+}
+
+// prioritizedPlugin adds a builtin priority to fakePlugin
+type prioritizedPlugin struct{ fakePlugin }
+
+func (p *prioritizedPlugin) Priority() int { return 10 }
+
+func newTestRegistry(t *testing.T) *PluginRegistry {
+	t.Helper()
+	registry := NewPluginRegistry()
+	for _, plugin := range []SemanticPlugin{
+		&fakePlugin{name: "golang", extensions: []string{".go"}},
+		&prioritizedPlugin{fakePlugin{name: "pulumi", marker: "pulumi.Run"}},
+		&fakePlugin{name: "kubernetes", patterns: []string{"k8s/*"}, marker: "kind:"},
+		&fakePlugin{name: "helm", marker: "kind:"},
+	} {
+		if err := registry.Register(plugin); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+	return registry
+}
+
+func TestPluginRegistry(t *testing.T) {
+	t.Run("conflict resolution", func(t *testing.T) {
+		registry := newTestRegistry(t)
+		tests := []struct {
+			name string
+			file FileChange
+			want string
+		}{
+			{"extension", FileChange{Path: "main.go", AfterContent: "package main"}, "golang"},
+			{"priority beats extension", FileChange{Path: "main.go", AfterContent: "pulumi.Run(f)"}, "pulumi"},
+			{"pattern beats content", FileChange{Path: "deploy/k8s/app.yaml", AfterContent: "kind: Deployment"}, "kubernetes"},
+			{"content ties break by name", FileChange{Path: "app.yaml", AfterContent: "kind: Deployment"}, "helm"},
+			{"no claim", FileChange{Path: "README.md"}, ""},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := ""
+				if plugin := registry.GetPluginForFile(tt.file); plugin != nil {
+					got = plugin.Name()
+				}
+				if got != tt.want {
+					t.Errorf("GetPluginForFile(%s) = %q, want %q", tt.file.Path, got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("disable and priority overrides", func(t *testing.T) {
+		registry := newTestRegistry(t)
+		file := FileChange{Path: "main.go", AfterContent: "pulumi.Run(f)"}
+
+		if err := registry.SetPriority("golang", 20); err != nil {
+			t.Fatal(err)
+		}
+		if got := registry.GetPluginForFile(file).Name(); got != "golang" {
+			t.Errorf("expected configured priority to win, got %s", got)
+		}
+
+		if err := registry.Disable("golang"); err != nil {
+			t.Fatal(err)
+		}
+		if got := registry.GetPluginForFile(file).Name(); got != "pulumi" {
+			t.Errorf("expected disabled plugin to be skipped, got %s", got)
+		}
+		if candidates := registry.Candidates(file); len(candidates) != 1 || candidates[0].Match != MatchContent {
+			t.Errorf("Candidates() = %+v", candidates)
+		}
+
+		if err := registry.Enable("golang"); err != nil || !registry.IsEnabled("golang") {
+			t.Errorf("Enable() error = %v", err)
+		}
+		if err := registry.Disable("missing"); err == nil {
+			t.Error("expected error disabling an unknown plugin")
+		}
+	})
+
+	t.Run("configure from settings", func(t *testing.T) {
+		registry := newTestRegistry(t)
+		analyzer := NewSemanticAnalyzer(registry)
+
+		err := analyzer.Configure(map[string]map[string]string{
+			"helm":   {"enabled": "false"},
+			"golang": {"priority": "30", "mode": "strict"},
+		})
+		if err != nil {
+			t.Fatalf("Configure() error = %v", err)
+		}
+		if registry.IsEnabled("helm") {
+			t.Error("expected helm to be disabled")
+		}
+		if registry.Priority("golang") != 30 {
+			t.Errorf("Priority(golang) = %d", registry.Priority("golang"))
+		}
+		if got := analyzer.PluginConfig("golang")["mode"]; got != "strict" {
+			t.Errorf("PluginConfig(golang) mode = %q", got)
+		}
+		if got := analyzer.PluginConfig("helm")["mode"]; got != "default" {
+			t.Errorf("expected defaults for helm, got mode %q", got)
+		}
+
+		for name, settings := range map[string]map[string]string{
+			"unknown":  {"enabled": "true"},
+			"enabled":  {"enabled": "sometimes"},
+			"priority": {"priority": "high"},
+			"option":   {"mode": "loose"},
+		} {
+			target := name
+			if name != "unknown" {
+				target = "golang"
+			}
+			if err := analyzer.Configure(map[string]map[string]string{target: settings}); err == nil {
+				t.Errorf("Configure() with invalid %s setting should return error", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,89 @@
+// Package ticket - JIRA, Linear and Azure Boards reference formats
+package ticket
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	jiraRefRegex    = regexp.MustCompile(`^[A-Z]{2,10}-\d{1,5}$`)
+	jiraFindRegex   = regexp.MustCompile(`\b[A-Z]{2,10}-\d{1,5}\b`)
+	linearRefRegex  = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,6}-\d+$`)
+	linearFindRegex = regexp.MustCompile(`\b[A-Z][A-Z0-9]{0,6}-\d+\b`)
+	azureRefRegex   = regexp.MustCompile(`^(?:AB#)?(\d+)$`)
+	azureFindRegex  = regexp.MustCompile(`\bAB#(\d+)\b`)
+)
+
+// jiraProvider writes JIRA keys such as CGC-1234
+type jiraProvider struct{}
+
+func (jiraProvider) Name() string    { return JIRA }
+func (jiraProvider) Example() string { return "CGC-1234" }
+
+func (jiraProvider) Normalize(ref string) (string, error) {
+	ref = strings.ToUpper(strings.TrimSpace(ref))
+	if !jiraRefRegex.MatchString(ref) {
+		return "", fmt.Errorf("invalid JIRA ticket format: %s (expected format: XXX-####)", ref)
+	}
+	return ref, nil
+}
+
+func (jiraProvider) Find(message string) []string {
+	return unique(jiraFindRegex.FindAllString(message, -1))
+}
+
+// linearProvider writes Linear issue identifiers such as ENG-123
+type linearProvider struct{}
+
+func (linearProvider) Name() string    { return Linear }
+func (linearProvider) Example() string { return "ENG-123" }
+
+func (linearProvider) Normalize(ref string) (string, error) {
+	ref = strings.ToUpper(strings.TrimSpace(ref))
+	if !linearRefRegex.MatchString(ref) {
+		return "", fmt.Errorf("invalid Linear issue format: %s (expected format: TEAM-123)", ref)
+	}
+	return ref, nil
+}
+
+func (linearProvider) Find(message string) []string {
+	return unique(linearFindRegex.FindAllString(message, -1))
+}
+
+// azureProvider writes Azure Boards work items as AB#123, which Azure
+// DevOps links from GitHub commits
+type azureProvider struct{}
+
+func (azureProvider) Name() string    { return Azure }
+func (azureProvider) Example() string { return "AB#123" }
+
+func (azureProvider) Normalize(ref string) (string, error) {
+	match := azureRefRegex.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(ref)))
+	if match == nil {
+		return "", fmt.Errorf("invalid Azure Boards work item: %s (expected format: AB#123)", ref)
+	}
+	return "AB#" + match[1], nil
+}
+
+func (azureProvider) Find(message string) []string {
+	var refs []string
+	for _, match := range azureFindRegex.FindAllStringSubmatch(message, -1) {
+		refs = append(refs, "AB#"+match[1])
+	}
+	return unique(refs)
+}
+
+// unique removes repeated references while keeping their order
+func unique(refs []string) []string {
+	seen := make(map[string]bool, len(refs))
+	out := refs[:0]
+	for _, ref := range refs {
+		if !seen[ref] {
+			seen[ref] = true
+			out = append(out, ref)
+		}
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+// Package ticket - GitHub issue references with an optional API check
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvGitHubToken holds the token used for GitHub issue checks
+	EnvGitHubToken = "GITHUB_TOKEN"
+
+	defaultGitHubAPI = "https://api.github.com"
+)
+
+var (
+	githubRefRegex  = regexp.MustCompile(`^(?:#|GH-)?(\d+)$`)
+	githubFindRegex = regexp.MustCompile(`(?:^|[^\w&])(?:#|GH-)(\d+)\b`)
+)
+
+// GitHubProvider writes GitHub issues as GH-123, which GitHub links like #123
+// but which survives in files where "#" starts a comment (such as the
+// current-ticket file and git's commit editor)
+type GitHubProvider struct {
+	repo       string
+	token      string
+	apiURL     string
+	httpClient *http.Client
+}
+
+// NewGitHubProvider creates a GitHub issue provider. Verify needs the
+// "owner/name" repository; the token is optional for public repositories.
+func NewGitHubProvider(repo, token string) *GitHubProvider {
+	return &GitHubProvider{
+		repo:       repo,
+		token:      token,
+		apiURL:     defaultGitHubAPI,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns "github"
+func (p *GitHubProvider) Name() string { return GitHub }
+
+// Example returns a sample reference
+func (p *GitHubProvider) Example() string { return "GH-123" }
+
+// Normalize accepts 123, #123 or GH-123
+func (p *GitHubProvider) Normalize(ref string) (string, error) {
+	match := githubRefRegex.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(ref)))
+	if match == nil {
+		return "", fmt.Errorf("invalid GitHub issue: %s (expected format: #123 or GH-123)", ref)
+	}
+	return "GH-" + match[1], nil
+}
+
+// Find returns the #123 and GH-123 references in a message as GH-123
+func (p *GitHubProvider) Find(message string) []string {
+	var refs []string
+	for _, match := range githubFindRegex.FindAllStringSubmatch(message, -1) {
+		refs = append(refs, "GH-"+match[1])
+	}
+	return unique(refs)
+}
+
+// Verify checks the issue exists in the configured repository
+func (p *GitHubProvider) Verify(ctx context.Context, ref string) error {
+	if p.repo == "" {
+		return fmt.Errorf("github_repo is not configured")
+	}
+	number := strings.TrimPrefix(ref, "GH-")
+	endpoint := fmt.Sprintf("%s/repos/%s/issues/%s", strings.TrimRight(p.apiURL, "/"), p.repo, number)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("checking GitHub issue %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound, http.StatusGone:
+		return fmt.Errorf("%w: %s in %s", ErrNotFound, ref, p.repo)
+	default:
+		return fmt.Errorf("checking GitHub issue %s: unexpected HTTP status %d", ref, resp.StatusCode)
+	}
+}
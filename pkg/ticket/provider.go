@@ -0,0 +1,92 @@
+// Package ticket provides issue tracker ticket providers for the
+// current-ticket workflow and commit validation
+package ticket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/jira"
+	"github.com/greenstevester/fast-cc-git-hooks/pkg/secrets"
+)
+
+// Provider names accepted by the ticket_provider setting
+const (
+	JIRA   = "jira"
+	GitHub = "github"
+	Linear = "linear"
+	Azure  = "azure"
+)
+
+// ErrNotFound indicates a ticket does not exist in the tracker
+var ErrNotFound = errors.New("ticket not found")
+
+// Provider describes how an issue tracker's tickets are written
+type Provider interface {
+	// Name returns the provider name, e.g. "github"
+	Name() string
+	// Normalize validates a ticket entered by the user and returns the form
+	// used in commit messages
+	Normalize(ref string) (string, error)
+	// Find returns the normalized ticket references in a commit message
+	Find(message string) []string
+	// Example returns a sample reference for help and error messages
+	Example() string
+}
+
+// Verifier is implemented by providers that can check a ticket exists
+type Verifier interface {
+	// Verify returns ErrNotFound for missing tickets; other errors mean the
+	// tracker could not be asked
+	Verify(ctx context.Context, ref string) error
+}
+
+// Settings holds provider-specific configuration
+type Settings struct {
+	// GitHubRepo is the "owner/name" repository for GitHub issue checks
+	GitHubRepo string
+}
+
+// Names returns the supported provider names
+func Names() []string {
+	names := []string{JIRA, GitHub, Linear, Azure}
+	sort.Strings(names)
+	return names
+}
+
+// New returns the named provider; an empty name selects JIRA
+func New(name string, settings Settings) (Provider, error) {
+	switch name {
+	case "", JIRA:
+		return jiraProvider{}, nil
+	case GitHub:
+		return NewGitHubProvider(settings.GitHubRepo, githubToken()), nil
+	case Linear:
+		return linearProvider{}, nil
+	case Azure:
+		return azureProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown ticket provider %q (supported: %v)", name, Names())
+	}
+}
+
+// githubToken returns GITHUB_TOKEN, falling back to the token stored with
+// `fcgh auth login github`
+func githubToken() string {
+	if token := os.Getenv(EnvGitHubToken); token != "" {
+		return token
+	}
+	return secrets.Lookup(secrets.GitHubService)
+}
+
+// NewManager creates a current-ticket manager that accepts the provider's tickets
+func NewManager(repoPath string, provider Provider) *jira.Manager {
+	manager := jira.NewManager(repoPath)
+	if provider.Name() != JIRA {
+		manager.SetTicketFormat(provider.Normalize)
+	}
+	return manager
+}
@@ -0,0 +1,119 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestProviders_Normalize(t *testing.T) {
+	tests := []struct {
+		provider string
+		input    string
+		want     string
+		wantErr  bool
+	}{
+		{provider: JIRA, input: "cgc-12", want: "CGC-12"},
+		{provider: JIRA, input: "#12", wantErr: true},
+		{provider: GitHub, input: "#123", want: "GH-123"},
+		{provider: GitHub, input: "123", want: "GH-123"},
+		{provider: GitHub, input: "gh-123", want: "GH-123"},
+		{provider: GitHub, input: "ENG-1", wantErr: true},
+		{provider: Linear, input: "eng-123", want: "ENG-123"},
+		{provider: Linear, input: "E2E-7", want: "E2E-7"},
+		{provider: Linear, input: "123", wantErr: true},
+		{provider: Azure, input: "ab#42", want: "AB#42"},
+		{provider: Azure, input: "42", want: "AB#42"},
+		{provider: Azure, input: "#42", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider+" "+tt.input, func(t *testing.T) {
+			provider, err := New(tt.provider, Settings{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := provider.Normalize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := New("trello", Settings{}); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
+
+func TestProviders_Find(t *testing.T) {
+	message := "feat: GH-7 add login\n\nFixes #12, refs AB#40 and ENG-3. See &#38; and #12 again."
+	tests := []struct {
+		provider string
+		want     []string
+	}{
+		{provider: GitHub, want: []string{"GH-7", "GH-12"}},
+		{provider: Azure, want: []string{"AB#40"}},
+		{provider: Linear, want: []string{"GH-7", "ENG-3"}},
+	}
+	for _, tt := range tests {
+		provider, _ := New(tt.provider, Settings{})
+		if got := provider.Find(message); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s Find() = %v, want %v", tt.provider, got, tt.want)
+		}
+	}
+}
+
+func TestGitHubProvider_Verify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/repos/acme/app/issues/7" {
+			_, _ = w.Write([]byte(`{"number":7}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	provider := NewGitHubProvider("acme/app", "token")
+	provider.apiURL = server.URL
+
+	if err := provider.Verify(context.Background(), "GH-7"); err != nil {
+		t.Errorf("Verify(GH-7) error = %v", err)
+	}
+	if err := provider.Verify(context.Background(), "GH-8"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Verify(GH-8) expected ErrNotFound, got %v", err)
+	}
+
+	provider.token = ""
+	if err := provider.Verify(context.Background(), "GH-7"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected an HTTP status error, got %v", err)
+	}
+	if err := NewGitHubProvider("", "").Verify(context.Background(), "GH-7"); err == nil {
+		t.Error("expected an error without github_repo")
+	}
+}
+
+func TestNewManager_ProviderFormat(t *testing.T) {
+	t.Setenv("FCGH_TEST_DIR", t.TempDir())
+	provider, _ := New(Azure, Settings{})
+	manager := NewManager(t.TempDir(), provider)
+
+	if err := manager.SetJiraTicket("42"); err != nil {
+		t.Fatalf("SetJiraTicket() error = %v", err)
+	}
+	if got, err := manager.GetCurrentJiraTicket(); err != nil || got != "AB#42" {
+		t.Errorf("GetCurrentJiraTicket() = %q, %v", got, err)
+	}
+	if err := manager.SetJiraTicket("CGC-1"); err == nil {
+		t.Error("expected JIRA keys to be rejected by the Azure provider")
+	}
+}